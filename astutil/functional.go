@@ -0,0 +1,108 @@
+package astutil
+
+import (
+	"go/ast"
+	"strconv"
+)
+
+// A FunctionalInterface is one of the functional interfaces of Java's standard
+// library, which is represented as a Go function with the same signature as
+// the interface's only abstract method
+type FunctionalInterface struct {
+	// The name of the method that calls the function
+	Method string
+	// The number of type arguments that the interface takes
+	TypeArgs int
+	// The Java types of the function's parameters, and of its result, which is
+	// empty if it doesn't return anything. A number is the index of one of the
+	// interface's type arguments
+	Params []string
+	Result string
+}
+
+// FunctionalInterfaces are the functional interfaces of Java's standard library
+// that are represented as Go functions, by their names
+var FunctionalInterfaces = map[string]FunctionalInterface{
+	"Runnable":          {Method: "run"},
+	"Callable":          {Method: "call", TypeArgs: 1, Result: "0"},
+	"Function":          {Method: "apply", TypeArgs: 2, Params: []string{"0"}, Result: "1"},
+	"BiFunction":        {Method: "apply", TypeArgs: 3, Params: []string{"0", "1"}, Result: "2"},
+	"UnaryOperator":     {Method: "apply", TypeArgs: 1, Params: []string{"0"}, Result: "0"},
+	"BinaryOperator":    {Method: "apply", TypeArgs: 1, Params: []string{"0", "0"}, Result: "0"},
+	"Supplier":          {Method: "get", TypeArgs: 1, Result: "0"},
+	"Consumer":          {Method: "accept", TypeArgs: 1, Params: []string{"0"}},
+	"BiConsumer":        {Method: "accept", TypeArgs: 2, Params: []string{"0", "1"}},
+	"Predicate":         {Method: "test", TypeArgs: 1, Params: []string{"0"}, Result: "boolean"},
+	"BiPredicate":       {Method: "test", TypeArgs: 2, Params: []string{"0", "1"}, Result: "boolean"},
+	"ToIntFunction":     {Method: "applyAsInt", TypeArgs: 1, Params: []string{"0"}, Result: "int"},
+	"ToLongFunction":    {Method: "applyAsLong", TypeArgs: 1, Params: []string{"0"}, Result: "long"},
+	"ToDoubleFunction":  {Method: "applyAsDouble", TypeArgs: 1, Params: []string{"0"}, Result: "double"},
+	"IntFunction":       {Method: "apply", TypeArgs: 1, Params: []string{"int"}, Result: "0"},
+	"IntPredicate":      {Method: "test", Params: []string{"int"}, Result: "boolean"},
+	"IntUnaryOperator":  {Method: "applyAsInt", Params: []string{"int"}, Result: "int"},
+	"IntBinaryOperator": {Method: "applyAsInt", Params: []string{"int", "int"}, Result: "int"},
+	"IntConsumer":       {Method: "accept", Params: []string{"int"}},
+	"IntSupplier":       {Method: "getAsInt", Result: "int"},
+	"LongSupplier":      {Method: "getAsLong", Result: "long"},
+	"DoubleSupplier":    {Method: "getAsDouble", Result: "double"},
+	"BooleanSupplier":   {Method: "getAsBoolean", Result: "boolean"},
+}
+
+// The Go types of the primitives that functional interfaces take and return
+var functionalPrimitives = map[string]string{
+	"int":     "int32",
+	"long":    "int64",
+	"double":  "float64",
+	"boolean": "bool",
+}
+
+// Signature returns the Java types of the parameters and the result of the
+// function that a functional interface is, with the given type arguments
+//
+// Ex: the signature of `Function<String, Integer>` is `(String) Integer`
+func (fi FunctionalInterface) Signature(javaTypeArgs []string) ([]string, string) {
+	resolve := func(javaType string) string {
+		if ind, err := strconv.Atoi(javaType); err == nil {
+			return javaTypeArgs[ind]
+		}
+		return javaType
+	}
+	params := make([]string, len(fi.Params))
+	for ind, param := range fi.Params {
+		params[ind] = resolve(param)
+	}
+	if fi.Result == "" {
+		return params, ""
+	}
+	return params, resolve(fi.Result)
+}
+
+// FunctionalType returns the Go function that a functional interface from
+// Java's standard library is represented as, from the Java names of its type
+// arguments and their Go types, or nil if it isn't one. The wrapper classes
+// that it takes or returns are the primitives that they box, the same as the
+// keys of a map
+//
+// Ex: `Function<String, Integer>` becomes `func(string) int32`, and
+// `Runnable` becomes `func()`
+func FunctionalType(name string, javaTypeArgs []string, typeArgs []ast.Expr) ast.Expr {
+	functional, ok := FunctionalInterfaces[name]
+	if !ok || functional.TypeArgs != len(typeArgs) || len(javaTypeArgs) != len(typeArgs) {
+		return nil
+	}
+	goType := func(javaType string) ast.Expr {
+		if ind, err := strconv.Atoi(javaType); err == nil {
+			return ElementType(javaTypeArgs[ind], typeArgs[ind], true)
+		}
+		return &ast.Ident{Name: functionalPrimitives[javaType]}
+	}
+
+	signature := &ast.FuncType{Params: &ast.FieldList{}}
+	for _, param := range functional.Params {
+		signature.Params.List = append(signature.Params.List, &ast.Field{Type: goType(param)})
+	}
+	if functional.Result != "" {
+		signature.Results = &ast.FieldList{List: []*ast.Field{{Type: goType(functional.Result)}}}
+	}
+	return signature
+}
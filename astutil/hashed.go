@@ -0,0 +1,92 @@
+package astutil
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// HashedClasses are the classes that declare their own `equals` and
+// `hashCode`, and every record, which a `HashMap` or a `HashSet` finds its
+// keys with. A Go map compares the pointers to the objects instead, so the
+// maps and sets of these classes are the runtime's `ObjectMap` and `ObjectSet`
+//
+// The classes are keyed by their qualified names, which are the names of their
+// packages and of the classes that they are nested in, such as
+// `com.example.Outer.Key`, so that a class of another package with the same
+// name isn't mistaken for one of them
+//
+// The classes are found before any of the types are parsed, since a class can
+// be used as a key in a file that comes before the one that declares it
+var HashedClasses = make(map[string]bool)
+
+// FindHashedClasses adds the classes in the given tree that are compared with
+// their own `equals` to HashedClasses
+func FindHashedClasses(root *sitter.Node, source []byte) {
+	var packageName string
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		if node := root.NamedChild(i); node.Type() == "package_declaration" {
+			packageName = node.NamedChild(0).Content(source)
+		}
+	}
+	findHashedClasses(root, source, packageName)
+}
+
+// findHashedClasses adds the classes in a tree to HashedClasses, qualified by
+// the name of the package or the class that the tree is in
+func findHashedClasses(root *sitter.Node, source []byte, qualifier string) {
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		node := root.NamedChild(i)
+		switch node.Type() {
+		case "class_declaration", "record_declaration":
+			name := node.ChildByFieldName("name").Content(source)
+			if qualifier != "" {
+				name = qualifier + "." + name
+			}
+			if node.Type() == "record_declaration" || declaresHashing(node.ChildByFieldName("body"), source) {
+				HashedClasses[name] = true
+			}
+			findHashedClasses(node, source, name)
+			continue
+		}
+		findHashedClasses(node, source, qualifier)
+	}
+}
+
+// declaresHashing determines if the body of a class declares both `equals`
+// and `hashCode`
+func declaresHashing(body *sitter.Node, source []byte) bool {
+	var equals, hashCode bool
+	for i := 0; body != nil && i < int(body.NamedChildCount()); i++ {
+		member := body.NamedChild(i)
+		if member.Type() != "method_declaration" {
+			continue
+		}
+		params := member.ChildByFieldName("parameters").NamedChildCount()
+		switch member.ChildByFieldName("name").Content(source) {
+		case "equals":
+			equals = equals || params == 1
+		case "hashCode":
+			hashCode = hashCode || params == 0
+		}
+	}
+	return equals && hashCode
+}
+
+// IsHashedClass determines if a Java type is one of the HashedClasses, which
+// it is if its qualified name is one of them, or if it is written with only
+// the end of one of their qualified names, such as `Key` or `Outer.Key`
+func IsHashedClass(javaType string) bool {
+	if ind := strings.Index(javaType, "<"); ind != -1 {
+		javaType = javaType[:ind]
+	}
+	if HashedClasses[javaType] {
+		return true
+	}
+	for name := range HashedClasses {
+		if strings.HasSuffix(name, "."+javaType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package astutil
+
+import (
+	"go/types"
+	"testing"
+)
+
+func TestFindHashedClasses(t *testing.T) {
+	source := `
+package app.keys;
+class Outer {
+    static class Key {
+        public boolean equals(Object other) { return true; }
+        public int hashCode() { return 0; }
+    }
+    static class OnlyEquals {
+        public boolean equals(Object other) { return true; }
+    }
+    record Pair(int a, int b) {}
+    Map<Key, String> byKey;
+    Set<Pair> pairs;
+    Map<OnlyEquals, String> byIdentity;
+}
+`
+	root := parseJavaType(t, source)
+	FindHashedClasses(root, []byte(source))
+	defer clear(HashedClasses)
+
+	if !HashedClasses["app.keys.Outer.Key"] || !HashedClasses["app.keys.Outer.Pair"] {
+		t.Errorf("Expected the classes to be keyed by their qualified names, got %v", HashedClasses)
+	}
+	for name, expected := range map[string]bool{
+		"Key":                true,
+		"Outer.Key":          true,
+		"app.keys.Outer.Key": true,
+		"Pair":               true,
+		"OnlyEquals":         false,
+		"Outer":              false,
+		"Pair<T>":            true,
+		// A class of the same name in another package isn't hashed
+		"app.other.Key": false,
+		"Other.Key":     false,
+	} {
+		if IsHashedClass(name) != expected {
+			t.Errorf("Expected %s to be hashed: %v", name, expected)
+		}
+	}
+
+	body := root.NamedChild(1).ChildByFieldName("body")
+	for ind, expected := range []string{
+		"*stdjava.ObjectMap[*Key, string]",
+		"*stdjava.ObjectSet[*Pair]",
+		"map[*OnlyEquals]string",
+	} {
+		typeNode := body.NamedChild(ind + 3).ChildByFieldName("type")
+		if result := types.ExprString(ParseType(typeNode, []byte(source))); result != expected {
+			t.Errorf("Expected %s, got %s", expected, result)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package astutil
 import (
 	"fmt"
 	"go/ast"
+	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
 )
@@ -64,8 +65,14 @@ func ParseTypeWithTypeParams(node *sitter.Node, source []byte, typeParams []stri
 		}
 		baseName := baseNode.Content(source)
 
+		// The type arguments of a class object only exist at compile time
+		if library := LibraryType(baseName); library != nil {
+			return library
+		}
+
 		// Find the type_arguments node
 		var typeArgs []ast.Expr
+		var javaTypeArgs []string
 		for i := 0; i < int(node.NamedChildCount()); i++ {
 			child := node.NamedChild(i)
 			if child.Type() == "type_arguments" {
@@ -73,11 +80,16 @@ func ParseTypeWithTypeParams(node *sitter.Node, source []byte, typeParams []stri
 				for j := 0; j < int(child.NamedChildCount()); j++ {
 					argNode := child.NamedChild(j)
 					typeArgs = append(typeArgs, ParseTypeWithTypeParams(argNode, source, typeParams))
+					javaTypeArgs = append(javaTypeArgs, argNode.Content(source))
 				}
 				break
 			}
 		}
 
+		if library := LibraryGenericType(baseName, javaTypeArgs, typeArgs); library != nil {
+			return library
+		}
+
 		// If we have type arguments, create an IndexExpr or IndexListExpr
 		// The pointer wraps the entire indexed expression: *List[T], not (*List)[T]
 		if len(typeArgs) > 0 {
@@ -141,6 +153,10 @@ func ParseTypeWithTypeParams(node *sitter.Node, source []byte, typeParams []stri
 			return &ast.Ident{Name: "string"}
 		}
 
+		if library := LibraryType(typeName); library != nil {
+			return library
+		}
+
 		// If this is a type parameter, don't wrap it in a pointer
 		if isTypeParam(typeName) {
 			return &ast.Ident{Name: typeName}
@@ -149,14 +165,226 @@ func ParseTypeWithTypeParams(node *sitter.Node, source []byte, typeParams []stri
 		return &ast.StarExpr{
 			X: &ast.Ident{Name: typeName},
 		}
+	case "wildcard":
+		// Go has no wildcards, so a wildcard that is bounded from above is its
+		// bound, and any other one is `any`
+		// Ex: `? extends Number` becomes `*Number`, and `? super T` becomes `any`
+		if WildcardBound(node) != nil {
+			return ParseTypeWithTypeParams(WildcardBound(node), source, typeParams)
+		}
+		return &ast.Ident{Name: "any"}
 	case "scoped_type_identifier":
 		// This contains a reference to the type of a nested class
 		// Ex: LinkedList.Node
+		if library := LibraryType(node.Content(source)); library != nil {
+			return library
+		}
 		return &ast.StarExpr{X: &ast.Ident{Name: node.Content(source)}}
 	}
 	panic("Unknown type to convert: " + node.Type())
 }
 
+// WildcardBound returns the type that a wildcard extends, or nil if it is
+// unbounded, or is bounded from below with `super`
+func WildcardBound(node *sitter.Node) *sitter.Node {
+	for i := 0; i < int(node.ChildCount())-1; i++ {
+		if node.Child(i).Type() == "extends" {
+			return node.Child(i + 1)
+		}
+	}
+	return nil
+}
+
+// AtomicType returns the type from `sync/atomic` that holds a value of the
+// given Go type, for the fields that are declared `volatile`, or nil if the
+// package has no type for it
+//
+// Ex: `int32` becomes `atomic.Int32`, and `*Node` becomes `atomic.Pointer[Node]`
+func AtomicType(goType ast.Expr) ast.Expr {
+	atomicType := func(name string) *ast.SelectorExpr {
+		return &ast.SelectorExpr{X: &ast.Ident{Name: "atomic"}, Sel: &ast.Ident{Name: name}}
+	}
+	switch goType := goType.(type) {
+	case *ast.Ident:
+		switch goType.Name {
+		case "int32":
+			return atomicType("Int32")
+		case "int64":
+			return atomicType("Int64")
+		case "bool":
+			return atomicType("Bool")
+		}
+	case *ast.StarExpr:
+		return &ast.IndexExpr{X: atomicType("Pointer"), Index: goType.X}
+	}
+	return nil
+}
+
+// LibraryType returns the Go type that a type from Java's standard library is
+// represented as, or nil if it has no representation of its own
+//
+// A `Class` is the runtime type of a value in Go, and the types that have to
+// behave the same as Java are implemented in the runtime package
+func LibraryType(name string) ast.Expr {
+	switch name {
+	case "Class":
+		return &ast.SelectorExpr{X: &ast.Ident{Name: "reflect"}, Sel: &ast.Ident{Name: "Type"}}
+	case "Locale":
+		return &ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: "Locale"}}
+	case "NumberFormat", "Properties", "ResourceBundle":
+		return &ast.StarExpr{X: &ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: name}}}
+	case "MessageDigest":
+		return &ast.SelectorExpr{X: &ast.Ident{Name: "hash"}, Sel: &ast.Ident{Name: "Hash"}}
+	case "Checksum", "CRC32", "CRC32C", "Adler32":
+		return &ast.SelectorExpr{X: &ast.Ident{Name: "hash"}, Sel: &ast.Ident{Name: "Hash32"}}
+	case "Base64.Encoder", "Base64.Decoder":
+		return &ast.StarExpr{X: &ast.SelectorExpr{X: &ast.Ident{Name: "base64"}, Sel: &ast.Ident{Name: "Encoding"}}}
+	case "HttpClient", "HttpRequest", "HttpResponse", "HttpURLConnection":
+		return &ast.StarExpr{X: &ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: name}}}
+	case "HttpsURLConnection":
+		return &ast.StarExpr{X: &ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: "HttpURLConnection"}}}
+	case "HttpRequest.Builder":
+		return &ast.StarExpr{X: &ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: "HttpRequestBuilder"}}}
+	case "URL", "URI", "UUID":
+		return &ast.Ident{Name: "string"}
+	case "ServerSocket", "Socket", "BufferedReader", "PrintWriter":
+		return &ast.StarExpr{X: &ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: name}}}
+	// A box is a pointer to the primitive that it boxes, the same as the boxes
+	// of the runtime
+	case "Integer", "Long", "Short", "Byte", "Character", "Boolean", "Float", "Double":
+		return &ast.StarExpr{X: &ast.Ident{Name: BoxedPrimitives[name]}}
+	case "StringBuilder", "StringBuffer":
+		return &ast.StarExpr{X: &ast.SelectorExpr{X: &ast.Ident{Name: "strings"}, Sel: &ast.Ident{Name: "Builder"}}}
+	case "InputStream", "InputStreamReader":
+		return &ast.SelectorExpr{X: &ast.Ident{Name: "io"}, Sel: &ast.Ident{Name: "Reader"}}
+	case "OutputStream", "OutputStreamWriter":
+		return &ast.SelectorExpr{X: &ast.Ident{Name: "io"}, Sel: &ast.Ident{Name: "Writer"}}
+	}
+	return FunctionalType(name, nil, nil)
+}
+
+// The wrapper classes of Java's primitives, and the Go types of the primitives
+// that they box
+var BoxedPrimitives = map[string]string{
+	"Integer":   "int32",
+	"Long":      "int64",
+	"Short":     "int16",
+	"Byte":      "byte",
+	"Character": "rune",
+	"Boolean":   "bool",
+	"Float":     "float32",
+	"Double":    "float64",
+}
+
+// BoxedElements keeps the values of the collections from Java's standard
+// library that are wrapper classes as pointers to their boxes, the same as
+// every other box in the translated code, instead of as the primitives that
+// they box
+var BoxedElements bool
+
+// NativeCollections makes the lists from Java's standard library Go slices of
+// their elements, the same as its maps are Go maps, instead of the classes of
+// the same names. A slice that is appended to has to be assigned back, so a
+// list that is passed to a method can't be added to by the method
+var NativeCollections bool
+
+// ElementType returns the Go type of the keys or the values of one of the
+// collections from Java's standard library, from their Java type and the Go
+// type that it is on its own
+//
+// Keys are compared with each other, so a key that is a wrapper class is always
+// the primitive that it boxes. A value is one as well, unless the values are
+// kept boxed, where it is a pointer to the primitive, the same as the boxes of
+// the runtime. Any other class is a pointer, the same as everywhere else
+//
+// Ex: the values of `Map<String, Integer>` are `int32`, or `*int32` boxed
+func ElementType(javaType string, goType ast.Expr, key bool) ast.Expr {
+	if ind := strings.LastIndex(javaType, "."); ind != -1 {
+		javaType = javaType[ind+1:]
+	}
+	primitive, boxed := BoxedPrimitives[javaType]
+	switch {
+	case !boxed:
+		return goType
+	case key || !BoxedElements:
+		return &ast.Ident{Name: primitive}
+	}
+	return &ast.StarExpr{X: &ast.Ident{Name: primitive}}
+}
+
+// LibraryGenericType returns the Go type that a generic type from Java's
+// standard library is represented as, from the Java names of its type
+// arguments and their Go types, or nil if it has no representation of its own
+//
+// A `Map` is a Go map, a `Set` is the runtime's set, which is a map as well,
+// unless their keys are one of the HashedClasses, and the sorted collections
+// are implemented in the runtime package. Their keys and values are the types
+// of their elements, as given by ElementType. An `Iterator` and an `Iterable`
+// are the runtime's interfaces of the same names. A functional interface is a Go function, as given by FunctionalType,
+// and with NativeCollections, a `List` is a slice of its elements
+//
+// Ex: `TreeMap<Integer, String>` becomes `*stdjava.TreeMap[int32, string]`,
+// `Map<String, Integer>` becomes `map[string]int32`, and `Set<Long>` becomes
+// `stdjava.HashSet[int64]`
+func LibraryGenericType(name string, javaTypeArgs []string, typeArgs []ast.Expr) ast.Expr {
+	if len(typeArgs) != len(javaTypeArgs) {
+		return nil
+	}
+	key := func(ind int) ast.Expr {
+		return ElementType(javaTypeArgs[ind], typeArgs[ind], true)
+	}
+	value := func(ind int) ast.Expr {
+		return ElementType(javaTypeArgs[ind], typeArgs[ind], false)
+	}
+	runtimeType := func(name string, typeArgs ...ast.Expr) ast.Expr {
+		runtimeName := &ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: name}}
+		if len(typeArgs) == 1 {
+			return &ast.StarExpr{X: &ast.IndexExpr{X: runtimeName, Index: typeArgs[0]}}
+		}
+		return &ast.StarExpr{X: &ast.IndexListExpr{X: runtimeName, Indices: typeArgs}}
+	}
+
+	switch name {
+	case "List", "ArrayList":
+		if NativeCollections && len(typeArgs) == 1 {
+			return &ast.ArrayType{Elt: value(0)}
+		}
+	case "Map", "HashMap":
+		if len(typeArgs) == 2 && IsHashedClass(javaTypeArgs[0]) {
+			return runtimeType("ObjectMap", key(0), value(1))
+		}
+		if len(typeArgs) == 2 {
+			return &ast.MapType{Key: key(0), Value: value(1)}
+		}
+	case "Set", "HashSet":
+		if len(typeArgs) == 1 && IsHashedClass(javaTypeArgs[0]) {
+			return runtimeType("ObjectSet", key(0))
+		}
+		if len(typeArgs) == 1 {
+			return &ast.IndexExpr{X: &ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: "HashSet"}}, Index: key(0)}
+		}
+	case "Map.Entry":
+		if len(typeArgs) == 2 {
+			return runtimeType("Entry", key(0), value(1))
+		}
+	case "TreeMap", "SortedMap", "NavigableMap":
+		if len(typeArgs) == 2 {
+			return runtimeType("TreeMap", key(0), value(1))
+		}
+	case "TreeSet", "SortedSet", "NavigableSet":
+		if len(typeArgs) == 1 {
+			return runtimeType("TreeSet", key(0))
+		}
+	case "Iterator", "Iterable":
+		// These are interfaces, which the classes that implement them satisfy,
+		// so their elements are the same types as the methods of the classes
+		if len(typeArgs) == 1 {
+			return &ast.IndexExpr{X: &ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: name}}, Index: typeArgs[0]}
+		}
+	}
+	return FunctionalType(name, javaTypeArgs, typeArgs)
+}
+
 // ExtractTypeArguments extracts type argument strings from a generic_type node.
 // Returns empty slice if node is not a generic type or has no type arguments.
 func ExtractTypeArguments(node *sitter.Node, source []byte) []string {
@@ -180,3 +408,29 @@ func ExtractTypeArguments(node *sitter.Node, source []byte) []string {
 	}
 	return typeArgs
 }
+
+// ParseTypeArguments parses the type arguments of a generic_type node into the
+// Go types that they are substituted as. A wrapper class is the primitive that
+// it boxes, the same as the elements of a collection, since a type parameter
+// doesn't need a box to hold one. Returns nil if the node has no type arguments
+//
+// Ex: `Stream<Map<K, V>>` has the arguments `map[K]V`, and `Pair<Integer,
+// String>` has the arguments `int32` and `string`
+func ParseTypeArguments(node *sitter.Node, source []byte, typeParams []string) []ast.Expr {
+	if node.Type() != "generic_type" {
+		return nil
+	}
+
+	var typeArgs []ast.Expr
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child.Type() == "type_arguments" {
+			for j := 0; j < int(child.NamedChildCount()); j++ {
+				argNode := child.NamedChild(j)
+				typeArgs = append(typeArgs, ElementType(argNode.Content(source), ParseTypeWithTypeParams(argNode, source, typeParams), false))
+			}
+			break
+		}
+	}
+	return typeArgs
+}
@@ -3,6 +3,7 @@ package astutil
 import (
 	"context"
 	"go/ast"
+	"go/types"
 	"testing"
 
 	sitter "github.com/smacker/go-tree-sitter"
@@ -158,10 +159,10 @@ func TestParseTypeWithTypeParams_GenericType(t *testing.T) {
 			wantArgIsStar: false,
 		},
 		{
-			name:          "generic type Map<K, V> with K,V in typeParams",
-			source:        "class C { Map<K, V> field; }",
+			name:          "generic type Pair<K, V> with K,V in typeParams",
+			source:        "class C { Pair<K, V> field; }",
 			typeParams:    []string{"K", "V"},
-			wantBaseName:  "Map",
+			wantBaseName:  "Pair",
 			wantArgCount:  2,
 			wantFirstArg:  "K",
 			wantArgIsStar: false,
@@ -479,3 +480,101 @@ func TestExtractTypeArguments_NonGenericType(t *testing.T) {
 		t.Errorf("Expected nil for non-generic type, got %v", result)
 	}
 }
+
+func TestParseTypeArguments(t *testing.T) {
+	for source, expected := range map[string][]string{
+		"class C { Stream<String> field; }":             {"string"},
+		"class C { Stream<Map<K, V>> field; }":          {"map[K]V"},
+		"class C { Pair<Integer, int[]> field; }":       {"int32", "[]int32"},
+		"class C { Pair<java.lang.Long, Node> field; }": {"int64", "*Node"},
+		"class C { Pair<?, ? extends Node> field; }":    {"any", "*Node"},
+		"class C { Stream<? super Integer> field; }":    {"any"},
+	} {
+		typeNode := findNode(parseJavaType(t, source), "generic_type")
+		result := ParseTypeArguments(typeNode, []byte(source), []string{"K", "V"})
+		if len(result) != len(expected) {
+			t.Errorf("Expected %q to have the arguments %v, got %d of them", source, expected, len(result))
+			continue
+		}
+		for i, arg := range result {
+			if types.ExprString(arg) != expected[i] {
+				t.Errorf("Expected the argument %d of %q to be %s, got %s", i, source, expected[i], types.ExprString(arg))
+			}
+		}
+	}
+}
+
+func TestParseTypeWithTypeParams_LibraryGenericType(t *testing.T) {
+	for source, expected := range map[string]string{
+		"class C { TreeMap<Integer, String> field; }":       "*stdjava.TreeMap[int32, string]",
+		"class C { NavigableSet<String> field; }":           "*stdjava.TreeSet[string]",
+		"class C { SortedMap<K, List<V>> field; }":          "*stdjava.TreeMap[K, *List[V]]",
+		"class C { Map<Integer, String> field; }":           "map[int32]string",
+		"class C { Map<K, V> field; }":                      "map[K]V",
+		"class C { HashMap<String, List<Long>> field; }":    "map[string]*List[*int64]",
+		"class C { Set<Long> field; }":                      "stdjava.HashSet[int64]",
+		"class C { Map.Entry<String, Integer> field; }":     "*stdjava.Entry[string, int32]",
+		"class C { LinkedHashMap<String, Integer> field; }": "*LinkedHashMap[string, *int32]",
+	} {
+		typeNode := findNode(parseJavaType(t, source), "generic_type")
+		if result := types.ExprString(ParseTypeWithTypeParams(typeNode, []byte(source), []string{"K", "V"})); result != expected {
+			t.Errorf("Expected %q to become %s, got %s", source, expected, result)
+		}
+	}
+}
+
+func TestAtomicType(t *testing.T) {
+	for source, expected := range map[string]string{
+		"class C { int field; }":     "atomic.Int32",
+		"class C { boolean field; }": "atomic.Bool",
+		"class C { Node field; }":    "atomic.Pointer[Node]",
+		"class C { double field; }":  "<nil>",
+		"class C { String field; }":  "<nil>",
+	} {
+		root := parseJavaType(t, source)
+		typeNode := root.NamedChild(0).ChildByFieldName("body").NamedChild(0).ChildByFieldName("type")
+		result := "<nil>"
+		if atomicType := AtomicType(ParseType(typeNode, []byte(source))); atomicType != nil {
+			result = types.ExprString(atomicType)
+		}
+		if result != expected {
+			t.Errorf("Expected %q to become %s, got %s", source, expected, result)
+		}
+	}
+}
+
+func TestBoxedElements(t *testing.T) {
+	BoxedElements = true
+	defer func() { BoxedElements = false }()
+
+	for source, expected := range map[string]string{
+		"class C { Map<Integer, Integer> field; }":      "map[int32]*int32",
+		"class C { TreeMap<String, Long> field; }":      "*stdjava.TreeMap[string, *int64]",
+		"class C { Set<Integer> field; }":               "stdjava.HashSet[int32]",
+		"class C { Map<String, List<Integer>> field; }": "map[string]*List[*int32]",
+	} {
+		root := parseJavaType(t, source)
+		typeNode := root.NamedChild(0).ChildByFieldName("body").NamedChild(0).ChildByFieldName("type")
+		if result := types.ExprString(ParseType(typeNode, []byte(source))); result != expected {
+			t.Errorf("Expected %q to become %s, got %s", source, expected, result)
+		}
+	}
+}
+
+func TestFunctionalType(t *testing.T) {
+	for source, expected := range map[string]string{
+		"class C { Function<String, Integer> field; }":       "func(string) int32",
+		"class C { BiFunction<Long, Long, List<T>> field; }": "func(int64, int64) *List[*T]",
+		"class C { Predicate<String> field; }":               "func(string) bool",
+		"class C { Consumer<Boolean> field; }":               "func(bool)",
+		"class C { Runnable field; }":                        "func()",
+		"class C { IntBinaryOperator field; }":               "func(int32, int32) int32",
+		"class C { Supplier field; }":                        "*Supplier",
+	} {
+		root := parseJavaType(t, source)
+		typeNode := root.NamedChild(0).ChildByFieldName("body").NamedChild(0).ChildByFieldName("type")
+		if result := types.ExprString(ParseType(typeNode, []byte(source))); result != expected {
+			t.Errorf("Expected %q to become %s, got %s", source, expected, result)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Package backend exports the declarations of the translated files, from
+// their representation in the ir package, in place of the Go code that is
+// generated for them by default, such as JSON for the tools that analyze the
+// code, or the output of a text template
+package backend
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/NickyBoy89/java2go/ir"
+)
+
+// A Backend writes the declarations of each file in its own format
+type Backend interface {
+	// Emit writes the representation of a file
+	Emit(w io.Writer, file *ir.File) error
+	// Extension is the extension of the files that the backend writes, when
+	// the output is written to files instead of stdout
+	Extension() string
+}
+
+// Backends are the backends that are built in, by their names. Go's own
+// backend isn't one of them, since the Go code is generated from the whole
+// tree of each file, instead of from its declarations
+var Backends = map[string]Backend{
+	"json":   JSON{},
+	"pseudo": Pseudo,
+}
+
+// JSON writes the representation of each file as an indented JSON object
+type JSON struct{}
+
+func (JSON) Emit(w io.Writer, file *ir.File) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(file)
+}
+
+func (JSON) Extension() string {
+	return ".json"
+}
+
+// Template writes the representation of each file by executing a text
+// template with it
+type Template struct {
+	*template.Template
+}
+
+// The functions that the templates can call, in addition to the builtin ones
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	// The parameters of a method, as a list of their names and their types
+	"params": func(params []*ir.Member) string {
+		listed := make([]string, len(params))
+		for ind, param := range params {
+			listed[ind] = param.OriginalName + ": " + param.Type
+		}
+		return strings.Join(listed, ", ")
+	},
+	"indent": func(depth int, text string) string {
+		return strings.ReplaceAll(text, "\n", "\n"+strings.Repeat("    ", depth))
+	},
+}
+
+// NewTemplate parses a text template into a backend
+//
+// Besides the functions above, a template can `include` another of its
+// templates, which returns its output, so that it can be indented
+func NewTemplate(name, text string) (*Template, error) {
+	parsed := template.New(name).Funcs(templateFuncs)
+	include := func(name string, data any) (string, error) {
+		var output strings.Builder
+		err := parsed.ExecuteTemplate(&output, name, data)
+		return output.String(), err
+	}
+	if _, err := parsed.Funcs(template.FuncMap{"include": include}).Parse(text); err != nil {
+		return nil, err
+	}
+	return &Template{parsed}, nil
+}
+
+// mustTemplate parses a template that is built in, which is known to be valid
+func mustTemplate(name, text string) *Template {
+	parsed, err := NewTemplate(name, text)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func (t *Template) Emit(w io.Writer, file *ir.File) error {
+	return t.Execute(w, file)
+}
+
+func (t *Template) Extension() string {
+	return ".txt"
+}
+
+// Pseudo writes each file as pseudo-code, which lists its declarations with
+// their Go names and types
+var Pseudo = mustTemplate("pseudo", pseudoTemplate)
+
+const pseudoTemplate = `{{define "class" -}}
+{{.Kind}} {{.Name}}{{with .TypeParameters}}[{{join . ", "}}]{{end}}{{with .Superclass}} extends {{.}}{{end}}{{with .Interfaces}} implements {{join . ", "}}{{end}}
+{{- range .Constants}}
+    constant {{.}}
+{{- end}}
+{{- range .Components}}
+    component {{.Name}}: {{.Type}}
+{{- end}}
+{{- range .Fields}}
+    {{if .Static}}static {{end}}field {{.Name}}: {{.Type}}
+{{- end}}
+{{- range .Methods}}
+    {{if .Constructor}}constructor {{.Name}}({{params .Parameters}}){{else}}{{if .Static}}static {{end}}method {{.Name}}({{params .Parameters}}){{with .Type}}: {{.}}{{end}}{{end}}{{with .Throws}} throws {{join . ", "}}{{end}}
+{{- end}}
+{{- range .Classes}}
+    {{indent 1 (include "class" .)}}
+{{- end}}
+{{- end -}}
+// {{.Name}}
+{{with .Package}}package {{.}}
+{{end -}}
+{{template "class" .Class}}
+`
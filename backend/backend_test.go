@@ -0,0 +1,87 @@
+package backend
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/NickyBoy89/java2go/ir"
+)
+
+// shapes is the representation of a file with a nested class
+var shapes = &ir.File{
+	Name:    "Shapes.java",
+	Package: "backend.shapes",
+	Class: &ir.Class{
+		Kind:         ir.ClassKind,
+		Name:         "Shapes",
+		OriginalName: "Shapes",
+		Interfaces:   []string{"Comparable"},
+		Fields:       []*ir.Member{{Name: "count", OriginalName: "count", Type: "int32", Static: true}},
+		Methods: []*ir.Member{
+			{Name: "NewShapes", OriginalName: "Shapes", Constructor: true},
+			{Name: "Area", OriginalName: "area", Type: "float64", Parameters: []*ir.Member{{OriginalName: "scale", Type: "int32"}}},
+			{Name: "Clear", OriginalName: "clear", Throws: []string{"IOException"}},
+		},
+		Classes: []*ir.Class{{
+			Kind:      ir.EnumKind,
+			Name:      "ShapesKind",
+			Constants: []string{"CIRCLE"},
+		}},
+	},
+}
+
+func TestJSON(t *testing.T) {
+	var output strings.Builder
+	if err := Backends["json"].Emit(&output, shapes); err != nil {
+		t.Fatalf("Failed to write the JSON: %v", err)
+	}
+	var decoded ir.File
+	if err := json.Unmarshal([]byte(output.String()), &decoded); err != nil {
+		t.Fatalf("Failed to read the JSON back: %v\n%s", err, output.String())
+	}
+	if decoded.Class.Methods[1].Parameters[0].Type != "int32" || decoded.Class.Classes[0].Kind != ir.EnumKind {
+		t.Errorf("Expected the JSON to have the whole representation, got:\n%s", output.String())
+	}
+	if strings.Contains(output.String(), "superclass") {
+		t.Errorf("Expected the empty fields to be left out, got:\n%s", output.String())
+	}
+}
+
+func TestPseudo(t *testing.T) {
+	var output strings.Builder
+	if err := Backends["pseudo"].Emit(&output, shapes); err != nil {
+		t.Fatalf("Failed to write the pseudo-code: %v", err)
+	}
+	expected := `// Shapes.java
+package backend.shapes
+class Shapes implements Comparable
+    static field count: int32
+    constructor NewShapes()
+    method Area(scale: int32): float64
+    method Clear() throws IOException
+    enum ShapesKind
+        constant CIRCLE
+`
+	if output.String() != expected {
+		t.Errorf("Expected the pseudo-code:\n%s\ngot:\n%s", expected, output.String())
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	custom, err := NewTemplate("custom", `{{define "names"}}{{.Name}}{{range .Classes}} {{include "names" .}}{{end}}{{end}}{{include "names" .Class | printf "%q"}}`)
+	if err != nil {
+		t.Fatalf("Failed to parse the template: %v", err)
+	}
+	var output strings.Builder
+	if err := custom.Emit(&output, shapes); err != nil {
+		t.Fatalf("Failed to execute the template: %v", err)
+	}
+	if output.String() != `"Shapes ShapesKind"` || custom.Extension() != ".txt" {
+		t.Errorf("Expected the names of the classes, got %s", output.String())
+	}
+
+	if _, err := NewTemplate("invalid", "{{.Name"); err == nil {
+		t.Error("Expected an invalid template to be an error")
+	}
+}
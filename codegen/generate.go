@@ -1,4 +1,7 @@
-package main
+// Package codegen generates the parts of Go's AST that the translated code is
+// made of, such as structs, interfaces, and the allocation of arrays, without
+// depending on the Java code that they are generated from
+package codegen
 
 import (
 	"fmt"
@@ -6,6 +9,8 @@ import (
 	"go/token"
 	"strconv"
 	"unicode"
+
+	"github.com/NickyBoy89/java2go/symbol"
 )
 
 var tokens = map[string]token.Token{
@@ -176,99 +181,140 @@ func GenTypeInterface(name string, types []string) ast.Decl {
 }
 
 func GenInterface(name string, methods *ast.FieldList) ast.Decl {
-	return &ast.GenDecl{
-		Tok: token.TYPE,
-		Specs: []ast.Spec{
-			&ast.TypeSpec{
-				Name: &ast.Ident{Name: name},
-				Type: &ast.InterfaceType{
-					Methods: methods,
-				},
-			},
-		},
-	}
+	return GenInterfaceWithTypeParams(name, methods, nil)
 }
 
-func GenMultiDimArray(arrayType string, dimensions []ast.Expr) ast.Expr {
-	if len(dimensions) == 1 {
-		return &ast.CallExpr{
-			Fun:  &ast.Ident{Name: "make"},
-			Args: append([]ast.Expr{&ast.Ident{Name: arrayType}}, dimensions...),
-		}
-	}
+// GenInterfaceWithTypeParams generates an interface with optional type
+// parameters, which are constrained by "any", the same as the ones of a struct
+func GenInterfaceWithTypeParams(name string, methods *ast.FieldList, typeParams []string) ast.Decl {
+	decl := GenStructWithTypeParams(name, nil, typeParams).(*ast.GenDecl)
+	decl.Specs[0].(*ast.TypeSpec).Type = &ast.InterfaceType{Methods: methods}
+	return decl
+}
 
-	// arr := make([][][]int, 2)
-	base := &ast.AssignStmt{
-		Tok: token.DEFINE,
-		Lhs: []ast.Expr{&ast.Ident{Name: "arr"}},
-		Rhs: []ast.Expr{
-			makeExpression(genArrayType(arrayType, len(dimensions)), dimensions[0]),
-		},
+// GenMultiDimArray generates the allocation of an array of the given element
+// type, with the given number of dimensions, where the lengths of the
+// outermost dimensions are given. Any dimensions without a length are left as
+// nil slices, like they are `null` in Java
+//
+// Ex: `new int[2][n][]` allocates a `[][][]int32`, where each of the two inner
+// arrays has `n` nil slices
+func GenMultiDimArray(elementType ast.Expr, depth int, dimensions []ast.Expr) ast.Expr {
+	// Only the outermost array needs to be allocated
+	if len(dimensions) == 1 {
+		return makeExpression(GenArrayType(elementType, depth), dimensions[0])
 	}
 
-	indexes := []string{"ind"}
-
-	var body, currentDimension *ast.RangeStmt
-
-	for offset := range dimensions[1:] {
-		nextDim := &ast.RangeStmt{
-			Key: &ast.Ident{Name: indexes[len(indexes)-1]},
-			Tok: token.DEFINE,
-			X:   multiArrayAccess("arr", indexes[:len(indexes)-1]),
-			Body: &ast.BlockStmt{
-				List: []ast.Stmt{
-					&ast.AssignStmt{
-						Tok: token.ASSIGN,
-						Lhs: []ast.Expr{multiArrayAccess("arr", indexes)},
-						Rhs: []ast.Expr{makeExpression(genArrayType(arrayType, len(dimensions)-(offset+1)), dimensions[offset+1])},
-					},
-				},
-			},
+	// The variables of the function can't shadow any of the types that the
+	// element type refers to
+	names := symbol.NewNameAllocator()
+	ast.Inspect(elementType, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok {
+			names.Reserve(ident.Name)
 		}
+		return true
+	})
 
-		if body == nil {
-			body = nextDim
-			currentDimension = body
-		} else {
-			currentDimension.Body.List = append(currentDimension.Body.List, nextDim)
-			currentDimension = currentDimension.Body.List[len(currentDimension.Body.List)-1].(*ast.RangeStmt)
+	// Java evaluates every length once, before allocating any of the arrays, so
+	// the lengths that aren't constants are passed into the function that
+	// allocates the arrays
+	params := &ast.FieldList{}
+	var args []ast.Expr
+	lengths := make([]ast.Expr, len(dimensions))
+	for index, dimension := range dimensions {
+		if isNumericLiteral(dimension) {
+			lengths[index] = dimension
+			continue
 		}
-
-		indexes = append(indexes, indexes[len(indexes)-1]+strconv.Itoa(offset))
+		name := &ast.Ident{Name: names.Allocate("len" + strconv.Itoa(index))}
+		params.List = append(params.List, &ast.Field{Names: []*ast.Ident{name}, Type: &ast.Ident{Name: "int32"}})
+		args = append(args, dimension)
+		lengths[index] = name
 	}
 
+	// arr := make([][][]int32, 2)
+	array := &ast.Ident{Name: names.Allocate("arr")}
+	body := []ast.Stmt{&ast.AssignStmt{
+		Tok: token.DEFINE,
+		Lhs: []ast.Expr{array},
+		Rhs: []ast.Expr{makeExpression(GenArrayType(elementType, depth), lengths[0])},
+	}}
+	body = append(body, allocateElements(array, elementType, depth-1, lengths[1:], 0, names)...)
+	body = append(body, &ast.ReturnStmt{Results: []ast.Expr{array}})
+
 	return &ast.CallExpr{
 		Fun: &ast.FuncLit{
 			Type: &ast.FuncType{
+				Params: params,
 				Results: &ast.FieldList{
 					List: []*ast.Field{
 						&ast.Field{
-							Type: genArrayType(arrayType, len(dimensions)),
+							Type: GenArrayType(elementType, depth),
 						},
 					},
 				},
 			},
-			Body: &ast.BlockStmt{
-				List: []ast.Stmt{
-					base,
-					body,
-					&ast.ReturnStmt{Results: []ast.Expr{&ast.Ident{Name: "arr"}}},
-				},
-			},
+			Body: &ast.BlockStmt{List: body},
 		},
+		Args: args,
 	}
 }
 
-func multiArrayAccess(arrName string, dims []string) ast.Expr {
-	var arr ast.Expr = &ast.Ident{Name: arrName}
-	for _, dim := range dims {
-		arr = &ast.IndexExpr{X: arr, Index: &ast.Ident{Name: dim}}
+// isNumericLiteral determines if an expression is a number, which integer
+// literals are generated as either a literal or an identifier
+func isNumericLiteral(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		return expr.Kind == token.INT
+	case *ast.Ident:
+		return unicode.IsDigit(rune(expr.Name[0]))
+	}
+	return false
+}
+
+// allocateElements generates the loops that allocate every element of an
+// array, and then the elements of those, for each of the given lengths, with
+// indexes that are named by the given allocator
+//
+// Ex: `for i := range arr { arr[i] = make([]int32, 3) }`
+func allocateElements(array ast.Expr, elementType ast.Expr, depth int, lengths []ast.Expr, level int, names *symbol.NameAllocator) []ast.Stmt {
+	if len(lengths) == 0 {
+		return nil
+	}
+
+	index := &ast.Ident{Name: names.Allocate(loopIndexName(level))}
+	element := &ast.IndexExpr{X: array, Index: index}
+
+	body := []ast.Stmt{&ast.AssignStmt{
+		Tok: token.ASSIGN,
+		Lhs: []ast.Expr{element},
+		Rhs: []ast.Expr{makeExpression(GenArrayType(elementType, depth), lengths[0])},
+	}}
+	body = append(body, allocateElements(element, elementType, depth-1, lengths[1:], level+1, names)...)
+
+	return []ast.Stmt{&ast.RangeStmt{
+		Key:  index,
+		Tok:  token.DEFINE,
+		X:    array,
+		Body: &ast.BlockStmt{List: body},
+	}}
+}
+
+// loopIndexName returns the name of the index of a loop, nested the given
+// number of loops deep
+//
+// Ex: `i`, `j`, and `k` for the first three levels
+func loopIndexName(level int) string {
+	if level < 'r'-'i' {
+		return string(rune('i' + level))
 	}
-	return arr
+	return "i" + strconv.Itoa(level)
 }
 
-func genArrayType(arrayType string, depth int) ast.Expr {
-	var arrayDims ast.Expr = &ast.Ident{Name: arrayType}
+// GenArrayType returns the type of an array of the given element type, with
+// the given number of dimensions
+func GenArrayType(elementType ast.Expr, depth int) ast.Expr {
+	arrayDims := elementType
 	for i := 0; i < depth; i++ {
 		arrayDims = &ast.ArrayType{Elt: arrayDims}
 	}
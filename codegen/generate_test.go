@@ -1,4 +1,4 @@
-package main
+package codegen
 
 import (
 	"bytes"
@@ -9,6 +9,12 @@ import (
 	"testing"
 )
 
+// normalizeSpaces collapses all whitespace, so that printed code can be compared
+// regardless of its formatting
+func normalizeSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
 func TestGenStructWithTypeParams_NoTypeParams(t *testing.T) {
 	fields := &ast.FieldList{
 		List: []*ast.Field{
@@ -374,6 +380,97 @@ func TestShortName(t *testing.T) {
 	}
 }
 
+func TestGenMultiDimArray(t *testing.T) {
+	int32Type := &ast.Ident{Name: "int32"}
+	tests := []struct {
+		name        string
+		elementType ast.Expr
+		depth       int
+		dimensions  []ast.Expr
+		want        string
+	}{
+		{"OneDimension", int32Type, 1, []ast.Expr{&ast.Ident{Name: "10"}}, "make([]int32, 10)"},
+		{"UnallocatedDimension", int32Type, 2, []ast.Expr{&ast.Ident{Name: "3"}}, "make([][]int32, 3)"},
+		{"ReferenceElements", &ast.StarExpr{X: &ast.Ident{Name: "Foo"}}, 1, []ast.Expr{&ast.Ident{Name: "10"}}, "make([]*Foo, 10)"},
+		{
+			"GenericElements",
+			&ast.StarExpr{X: &ast.IndexExpr{X: &ast.Ident{Name: "Box"}, Index: &ast.Ident{Name: "T"}}},
+			1, []ast.Expr{&ast.Ident{Name: "n"}},
+			"make([]*Box[T], n)",
+		},
+		{
+			"ThreeDimensions", int32Type, 3,
+			[]ast.Expr{&ast.Ident{Name: "2"}, &ast.Ident{Name: "3"}, &ast.Ident{Name: "4"}},
+			`func() [][][]int32 {
+				arr := make([][][]int32, 2)
+				for i := range arr {
+					arr[i] = make([][]int32, 3)
+					for j := range arr[i] {
+						arr[i][j] = make([]int32, 4)
+					}
+				}
+				return arr
+			}()`,
+		},
+		{
+			"FourDimensionsWithExpressions", int32Type, 4,
+			[]ast.Expr{
+				&ast.Ident{Name: "n"},
+				&ast.BinaryExpr{X: &ast.Ident{Name: "n"}, Op: token.ADD, Y: &ast.Ident{Name: "1"}},
+				&ast.Ident{Name: "0"},
+				&ast.Ident{Name: "i"},
+			},
+			`func(len0 int32, len1 int32, len3 int32) [][][][]int32 {
+				arr := make([][][][]int32, len0)
+				for i := range arr {
+					arr[i] = make([][][]int32, len1)
+					for j := range arr[i] {
+						arr[i][j] = make([][]int32, 0)
+						for k := range arr[i][j] {
+							arr[i][j][k] = make([]int32, len3)
+						}
+					}
+				}
+				return arr
+			}(n, n+1, i)`,
+		},
+		{
+			"ElementTypeNamedLikeVariables", &ast.StarExpr{X: &ast.IndexExpr{X: &ast.Ident{Name: "arr"}, Index: &ast.Ident{Name: "i"}}}, 2,
+			[]ast.Expr{&ast.Ident{Name: "2"}, &ast.Ident{Name: "3"}},
+			`func() [][]*arr[i] {
+				arr2 := make([][]*arr[i], 2)
+				for i2 := range arr2 {
+					arr2[i2] = make([]*arr[i], 3)
+				}
+				return arr2
+			}()`,
+		},
+		{
+			"PartiallyAllocated", int32Type, 3,
+			[]ast.Expr{&ast.Ident{Name: "2"}, &ast.Ident{Name: "5"}},
+			`func() [][][]int32 {
+				arr := make([][][]int32, 2)
+				for i := range arr {
+					arr[i] = make([][]int32, 5)
+				}
+				return arr
+			}()`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := printer.Fprint(&buf, token.NewFileSet(), GenMultiDimArray(tt.elementType, tt.depth, tt.dimensions)); err != nil {
+				t.Fatalf("Failed to print the array: %v", err)
+			}
+			if got, want := normalizeSpaces(buf.String()), normalizeSpaces(tt.want); got != want {
+				t.Errorf("GenMultiDimArray() = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
 func TestStrToToken(t *testing.T) {
 	tests := []struct {
 		input string
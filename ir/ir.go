@@ -0,0 +1,137 @@
+// Package ir is a representation of the declarations of a Java file, once its
+// symbols are resolved, which the backends export instead of the Go code. The
+// Go code isn't generated from it, but from the tree-sitter AST of the whole
+// file, so it only has the declarations, and not the bodies of the methods.
+// The names and types of the declarations are both the Java ones and the Go
+// ones that they are translated into, so the tools that only need the front
+// half of the pipeline, such as analyses, don't have to walk the tree-sitter
+// AST themselves
+package ir
+
+import "github.com/NickyBoy89/java2go/symbol"
+
+// Kind is the kind of declaration that a Class is
+type Kind string
+
+const (
+	ClassKind     Kind = "class"
+	InterfaceKind Kind = "interface"
+	EnumKind      Kind = "enum"
+	RecordKind    Kind = "record"
+)
+
+// File is a Java file, and the class that it declares
+type File struct {
+	// The name of the file that the declarations are from
+	Name string `json:"name"`
+	// The Java package of the file
+	Package string `json:"package"`
+	// The classes that the file imports, keyed by their names, and the
+	// packages that they are imported from
+	Imports map[string]string `json:"imports,omitempty"`
+	// The top-level class of the file, and the classes nested in it
+	Class *Class `json:"class"`
+}
+
+// Class is a class, an interface, an enum, or a record
+type Class struct {
+	Kind Kind `json:"kind"`
+	// The Go name of the class, which nested classes are prefixed with the
+	// names of the classes that they are nested in
+	Name string `json:"name"`
+	// The Java name of the class
+	OriginalName   string   `json:"originalName"`
+	TypeParameters []string `json:"typeParameters,omitempty"`
+	// The Java name of the class that the class extends, if it extends one
+	Superclass string `json:"superclass,omitempty"`
+	// The Java names of the interfaces that the class implements
+	Interfaces []string `json:"interfaces,omitempty"`
+	// The components of a record, in order
+	Components []*Member `json:"components,omitempty"`
+	// The Java names of the constants of an enum, in order
+	Constants []string  `json:"constants,omitempty"`
+	Fields    []*Member `json:"fields,omitempty"`
+	// The methods and the constructors of the class
+	Methods []*Member `json:"methods,omitempty"`
+	// The classes that are nested in the class
+	Classes []*Class `json:"classes,omitempty"`
+}
+
+// Member is a field, a method, or a parameter of a method
+type Member struct {
+	// The Go name of the member
+	Name string `json:"name"`
+	// The Java name of the member
+	OriginalName string `json:"originalName"`
+	// The Go type of the member, which is the type that a method returns
+	Type string `json:"type,omitempty"`
+	// The Java type of the member, which is empty for a constructor
+	OriginalType string `json:"originalType,omitempty"`
+	Static       bool   `json:"static,omitempty"`
+	Constructor  bool   `json:"constructor,omitempty"`
+	// The parameters of a method, in order
+	Parameters []*Member `json:"parameters,omitempty"`
+	// The exceptions in the `throws` clause of a method
+	Throws []string `json:"throws,omitempty"`
+}
+
+// FromSymbols creates the representation of a file from its resolved symbols
+func FromSymbols(name string, symbols *symbol.FileScope) *File {
+	return &File{
+		Name:    name,
+		Package: symbols.Package,
+		Imports: symbols.Imports,
+		Class:   fromClassScope(symbols.BaseClass),
+	}
+}
+
+// fromClassScope creates the representation of a class, and of the classes
+// that are nested in it
+func fromClassScope(scope *symbol.ClassScope) *Class {
+	class := &Class{
+		Kind:           ClassKind,
+		Name:           scope.Class.Name,
+		OriginalName:   scope.Class.OriginalName,
+		TypeParameters: scope.TypeParameters,
+		Superclass:     scope.Superclass,
+		Interfaces:     scope.Interfaces,
+		Constants:      scope.EnumConstants,
+		Components:     fromDefinitions(scope.RecordComponents),
+		Fields:         fromDefinitions(scope.Fields),
+		Methods:        fromDefinitions(scope.Methods),
+	}
+	switch {
+	case scope.IsInterface:
+		class.Kind = InterfaceKind
+	case scope.IsEnum:
+		class.Kind = EnumKind
+	case scope.IsRecord:
+		class.Kind = RecordKind
+	}
+	for _, subclass := range scope.Subclasses {
+		class.Classes = append(class.Classes, fromClassScope(subclass))
+	}
+	return class
+}
+
+// fromDefinitions creates the members of the given definitions, or nil if
+// there are none
+func fromDefinitions(definitions []*symbol.Definition) []*Member {
+	if len(definitions) == 0 {
+		return nil
+	}
+	members := make([]*Member, len(definitions))
+	for ind, definition := range definitions {
+		members[ind] = &Member{
+			Name:         definition.Name,
+			OriginalName: definition.OriginalName,
+			Type:         definition.Type,
+			OriginalType: definition.OriginalType,
+			Static:       definition.IsStatic,
+			Constructor:  definition.Constructor,
+			Parameters:   fromDefinitions(definition.Parameters),
+			Throws:       definition.Throws,
+		}
+	}
+	return members
+}
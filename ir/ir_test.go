@@ -0,0 +1,54 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/NickyBoy89/java2go/parsing"
+)
+
+func TestFromSymbols(t *testing.T) {
+	file := parsing.SourceFile{Name: "Shapes.java", Source: []byte(`
+package ir.shapes;
+import java.util.List;
+public class Shapes<T> implements Comparable<Shapes<T>> {
+    static int count;
+    public static Shapes<String> of(List<String> names) throws java.io.IOException { return null; }
+    public boolean equals(Object other) { return false; }
+    enum Kind { CIRCLE, SQUARE }
+    record Size(int width, int height) {}
+}
+`)}
+	if err := file.ParseAST(); err != nil {
+		t.Fatalf("Failed to parse the file: %v", err)
+	}
+	representation := FromSymbols(file.Name, file.ParseSymbols())
+
+	if representation.Package != "ir.shapes" || representation.Imports["List"] != "java.util" {
+		t.Errorf("Expected the package and the imports of the file, got %+v", representation)
+	}
+	class := representation.Class
+	if class.Kind != ClassKind || class.Name != "Shapes" || len(class.TypeParameters) != 1 || class.Interfaces[0] != "Comparable" {
+		t.Errorf("Expected the generic class, got %+v", class)
+	}
+	if len(class.Fields) != 1 || !class.Fields[0].Static || class.Fields[0].Type != "int32" {
+		t.Errorf("Expected the static field, got %+v", class.Fields)
+	}
+
+	of := class.Methods[0]
+	if of.Name != "Of" || of.Type != "*Shapes[string]" || of.Parameters[0].Type != "*List[string]" || of.Throws[0] != "java.io.IOException" {
+		t.Errorf("Expected the Go names and types of the method, got %+v", of)
+	}
+	if equals := class.Methods[1]; equals.Name != "Equals" || equals.Parameters[0].Type != "any" {
+		t.Errorf("Expected `equals` to take any value, got %+v", equals.Parameters[0])
+	}
+
+	if len(class.Classes) != 2 {
+		t.Fatalf("Expected the nested classes, got %+v", class.Classes)
+	}
+	if kind := class.Classes[0]; kind.Kind != EnumKind || len(kind.Constants) != 2 || kind.Constants[1] != "SQUARE" {
+		t.Errorf("Expected the nested enum, got %+v", kind)
+	}
+	if size := class.Classes[1]; size.Kind != RecordKind || len(size.Components) != 2 || size.Components[1].OriginalName != "height" {
+		t.Errorf("Expected the nested record, got %+v", size)
+	}
+}
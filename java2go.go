@@ -4,22 +4,23 @@ import (
 	"flag"
 	"fmt"
 	"go/ast"
-	"go/printer"
 	"go/token"
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 
+	"github.com/NickyBoy89/java2go/astutil"
+	"github.com/NickyBoy89/java2go/backend"
+	"github.com/NickyBoy89/java2go/ir"
 	"github.com/NickyBoy89/java2go/parsing"
 	"github.com/NickyBoy89/java2go/symbol"
+	"github.com/NickyBoy89/java2go/translate"
 	log "github.com/sirupsen/logrus"
 )
 
-// Stores a global list of Java annotations to exclude from the generated code
-var excludedAnnotations = make(map[string]bool)
-
 // Command-line arguments
 var (
 	writeFiles              bool
@@ -27,11 +28,17 @@ var (
 	displayAST              bool
 	symbolAware             bool
 	parseFilesSynchronously bool
+	copyResources           bool
 )
 
 var (
 	outputDirectory    string
 	ignoredAnnotations string
+	stdlibMappingsFile string
+	anyReportFile      string
+	backendName        string
+	templateFile       string
+	listenerPattern    string
 )
 
 func main() {
@@ -43,13 +50,102 @@ func main() {
 Results in better code generation, but can be disabled for a more direct translation
 or to fix crashes with the symbol handling`,
 	)
+	flag.BoolVar(&translate.NullAnalysis, "null-analysis", false, `Report places where Java would throw a NullPointerException,
+but the generated code panics differently, or not at all`,
+	)
+	flag.BoolVar(&translate.StrictMode, "strict", false, `Keep Java's exact semantics wherever the idiomatic Go translation
+would behave differently, at the cost of less readable code`,
+	)
+	flag.BoolVar(&translate.NativeBindings, "native-cgo", false, "Add a skeleton of a cgo binding to the stubs that are generated for native methods")
+	flag.BoolVar(&translate.JSONTags, "json", false, "Add JSON tags to the fields of the generated structs, leaving out transient fields")
+	flag.BoolVar(&translate.TidyOutput, "tidy", false, "Remove unused variables, self-assignments, and shadowed errors from the generated code, which linters would report")
+	flag.BoolVar(&translate.SimplifyOutput, "simplify", false, "Type check the generated code, and remove the conversions and type assertions that don't change the types of their operands")
+	flag.BoolVar(&translate.ErrorReturns, "errors", false, `Return the exceptions of the methods with a throws clause as an extra error result,
+instead of panicking with them`,
+	)
+	flag.BoolVar(&astutil.BoxedElements, "boxed-elements", false, `Keep the values of maps and sorted maps that are wrapper classes as pointers to their boxes,
+instead of the primitives that they box`,
+	)
+	flag.BoolVar(&astutil.NativeCollections, "native-collections", false, `Translate Lists and ArrayLists into Go slices, which are appended to and assigned back,
+instead of into the classes of the same names`,
+	)
+	flag.BoolVar(&translate.ParallelStreams, "parallel-streams", false, "Run the stages of parallel streams in goroutines, instead of lowering them into sequential loops")
+	flag.BoolVar(&translate.ChunkedOutput, "chunked", false, `Write the declarations of each file out as they are generated, through a temporary file,
+instead of keeping the whole file in memory until it is printed. Can't be combined with -ast`,
+	)
+	flag.BoolVar(&translate.LineDirectives, "line-directives", false, "Precede the generated declarations with //line directives that point back to the Java code that they were generated from")
+	flag.BoolVar(&translate.StrictOutput, "strict-output", false, `Check the generated code for nodes that can't be printed as valid Go, such as names that are missing,
+and report them instead of printing the file`,
+	)
+	flag.BoolVar(&translate.JavaArithmetic, "java-arithmetic", false, `Keep the semantics of Java's fixed-width integers where Go's would differ, by folding the constant expressions that Java wraps around,
+promoting the operands of byte and short operations to ints, masking every shift count, and making unsigned shifts on unsigned types inline`)
+	flag.BoolVar(&translate.MonotonicClock, "monotonic-clock", false, "Read System.nanoTime from a monotonic clock through the runtime, instead of from the wall clock with time.Now().UnixNano()")
+	flag.BoolVar(&copyResources, "copy-resources", false, "Copy the .properties files next to the sources into the output directory, when writing the files")
 	flag.StringVar(&outputDirectory, "output", ".", "Specify a directory for the generated files")
 	flag.StringVar(&ignoredAnnotations, "exclude-annotations", "", "A comma-separated list of annotations to exclude from the final code generation")
+	flag.StringVar(&translate.EnumHelperPrefix, "enum-helper-prefix", "", "A prefix for the names of the functions that are generated for enums, such as the one implementing `values()`")
+
+	flag.StringVar(&listenerPattern, "listener-pattern", `(Listener|Observer|Callback)$`, "A regular expression that matches the names of the single-method interfaces that are used as callbacks")
+	flag.StringVar(&anyReportFile, "any-report", "", "Write a report of the places where the types of the generated code fall back to `any`, ranked by the files with the most of them, to the given file")
+	flag.StringVar(&backendName, "backend", "go", "The format to write the files in, which is Go code, or one of the exports of their declarations: `json` or `pseudo`")
+	flag.StringVar(&templateFile, "template", "", "A text template to export the declarations of each file with, instead of the Go code")
+	flag.StringVar(&stdlibMappingsFile, "stdlib-mappings", "", "A file of mappings from the methods of Java's standard library to Go expressions, in addition to the built-in ones")
 
 	flag.Parse()
 
+	translate.ReportFallbacks = anyReportFile != ""
+
+	if translate.ChunkedOutput && displayAST {
+		log.Fatal("The generated AST can't be printed in the chunked mode")
+	}
+	if translate.ChunkedOutput && translate.SimplifyOutput {
+		log.Fatal("The generated code can't be type checked in the chunked mode")
+	}
+
+	// The backend that exports the declarations of the files, which is nil
+	// when the Go code is generated
+	var emitter backend.Backend
+	switch {
+	case templateFile != "":
+		text, err := os.ReadFile(templateFile)
+		if err != nil {
+			log.WithField("error", err).Fatal("Failed to read the template")
+		}
+		if emitter, err = backend.NewTemplate(filepath.Base(templateFile), string(text)); err != nil {
+			log.WithFields(log.Fields{"file": templateFile, "error": err}).Fatal("Invalid template")
+		}
+	case backendName != "go":
+		var ok bool
+		if emitter, ok = backend.Backends[backendName]; !ok {
+			log.WithField("backend", backendName).Fatal("Unknown backend")
+		}
+	}
+	if emitter != nil && !symbolAware {
+		log.Fatal("The declarations of the files can't be written without their symbols")
+	}
+
+	if listenerPattern != "" {
+		pattern, err := regexp.Compile(listenerPattern)
+		if err != nil {
+			log.WithField("error", err).Fatal("Invalid listener pattern")
+		}
+		translate.ListenerPattern = pattern
+	}
+
+	if stdlibMappingsFile != "" {
+		mappings, err := os.Open(stdlibMappingsFile)
+		if err != nil {
+			log.WithField("error", err).Fatal("Failed to open the standard library mappings")
+		}
+		err = translate.LoadStdlibMappings(mappings)
+		mappings.Close()
+		if err != nil {
+			log.WithFields(log.Fields{"file": stdlibMappingsFile, "error": err}).Fatal("Invalid standard library mappings")
+		}
+	}
+
 	for _, annotation := range strings.Split(ignoredAnnotations, ",") {
-		excludedAnnotations[annotation] = true
+		translate.ExcludedAnnotations[annotation] = true
 	}
 
 	// All the files to parse
@@ -105,6 +201,14 @@ or to fix crashes with the symbol handling`,
 	if symbolAware {
 		log.Info("Generating symbol tables...")
 
+		// The types of the maps and sets depend on how their keys are hashed,
+		// which can be declared in any of the files
+		for _, file := range files {
+			if !file.Ast.HasError() {
+				astutil.FindHashedClasses(file.Ast, file.Source)
+			}
+		}
+
 		for index, file := range files {
 			if file.Ast.HasError() {
 				log.WithFields(log.Fields{
@@ -124,7 +228,7 @@ or to fix crashes with the symbol handling`,
 
 		for _, file := range files {
 			if !file.Ast.HasError() {
-				ResolveFile(file)
+				translate.ResolveFile(file)
 			}
 		}
 	}
@@ -133,6 +237,11 @@ or to fix crashes with the symbol handling`,
 
 	log.Info("Converting files...")
 
+	// The positions of the generated nodes, in all of the Java files
+	positions := token.NewFileSet()
+	// The places where the types fall back to `any`, in each of the files
+	var fallbacks []*translate.Diagnostics
+
 	for _, file := range files {
 		if dryRun {
 			log.Infof("Not converting file \"%s\"", file.Name)
@@ -141,13 +250,25 @@ or to fix crashes with the symbol handling`,
 
 		log.Infof("Converting file \"%s\"", file.Name)
 
+		// The other backends export the declarations of the file, which are
+		// only known if its symbols could be parsed
+		if emitter != nil && file.Symbols == nil {
+			log.WithField("fileName", file.Name).Warn("The symbols of the file couldn't be parsed, skipping the export of its declarations")
+			continue
+		}
+
 		// Write to stdout by default
 		var output io.Writer = os.Stdout
 		if writeFiles {
-			// Write to a `.go` file in the same directory
+			// Write to a `.go` file in the same directory, or a file of the
+			// backend's own extension
+			extension := ".go"
+			if emitter != nil {
+				extension = emitter.Extension()
+			}
 			outputFile := fmt.Sprintf("%s/%s",
 				outputDirectory,
-				strings.TrimSuffix(file.Name, filepath.Ext(file.Name))+".go",
+				strings.TrimSuffix(file.Name, filepath.Ext(file.Name))+extension,
 			)
 
 			err := os.MkdirAll(outputDirectory, 0755)
@@ -168,22 +289,57 @@ or to fix crashes with the symbol handling`,
 			}
 		}
 
-		// The converted AST, in Go's AST representation
-		var initialContext Ctx
-		if symbolAware {
-			initialContext.currentFile = file.Symbols
-			initialContext.currentClass = file.Symbols.BaseClass
+		if emitter != nil {
+			if err := emitter.Emit(output, ir.FromSymbols(file.Name, file.Symbols)); err != nil {
+				log.WithFields(log.Fields{"file": file.Name, "error": err}).Error("Error writing the declarations of the file")
+			}
+			if writeFiles {
+				output.(*os.File).Close()
+			}
+			continue
+		}
+
+		// The converted AST, in Go's AST representation, along with anything
+		// that might behave differently in it
+		result, err := translate.File(file, positions)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err,
+			}).Panic("Error creating the file for the generated declarations")
+		}
+		if result.Fallbacks != nil {
+			fallbacks = append(fallbacks, result.Fallbacks)
+		}
+
+		result.Diagnostics.Print(os.Stderr)
+		if result.Helpers > 0 {
+			log.WithFields(log.Fields{
+				"file":    file.Name,
+				"helpers": result.Helpers,
+				"methods": result.Methods,
+			}).Info("Generated helper types for generic methods")
 		}
 
-		parsed := ParseNode(file.Ast, file.Source, initialContext).(ast.Node)
+		if result.Invalid > 0 {
+			log.WithFields(log.Fields{
+				"file":     file.Name,
+				"problems": result.Invalid,
+			}).Error("The generated code can't be printed as valid Go, not printing the file")
+			result.Discard()
+			if writeFiles {
+				output.(*os.File).Close()
+				os.Remove(output.(*os.File).Name())
+			}
+			continue
+		}
 
 		// Print the generated AST
 		if displayAST {
-			ast.Print(token.NewFileSet(), parsed)
+			ast.Print(positions, result.File)
 		}
 
 		// Output the parsed AST, into the source specified earlier
-		if err := printer.Fprint(output, token.NewFileSet(), parsed); err != nil {
+		if err := result.Print(output); err != nil {
 			log.WithFields(log.Fields{
 				"error": err,
 			}).Panic("Error printing generated code")
@@ -193,4 +349,21 @@ or to fix crashes with the symbol handling`,
 			output.(*os.File).Close()
 		}
 	}
+
+	if anyReportFile != "" {
+		report, err := os.Create(anyReportFile)
+		if err == nil {
+			err = translate.WriteFallbackReport(report, fallbacks)
+			report.Close()
+		}
+		if err != nil {
+			log.WithFields(log.Fields{"file": anyReportFile, "error": err}).Error("Error writing the report of the types that fall back to `any`")
+		}
+	}
+
+	if writeFiles && copyResources {
+		if err := translate.CopyResourceFiles(flag.Args(), outputDirectory); err != nil {
+			log.WithField("error", err).Error("Error copying resource files")
+		}
+	}
 }
@@ -21,3 +21,58 @@ func UnnamedChildrenOf(node *sitter.Node) []*sitter.Node {
 	}
 	return children
 }
+
+// ChildrenByFieldName gets every child of a given node that is stored under the
+// given field name, since `ChildByFieldName` only returns the first match
+func ChildrenByFieldName(node *sitter.Node, fieldName string) []*sitter.Node {
+	children := []*sitter.Node{}
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if node.FieldNameForChild(i) == fieldName {
+			children = append(children, node.Child(i))
+		}
+	}
+	return children
+}
+
+// HasModifier determines if a declaration is declared with the given modifier,
+// such as `static`
+func HasModifier(node *sitter.Node, modifier string) bool {
+	if node.NamedChildCount() == 0 || node.NamedChild(0).Type() != "modifiers" {
+		return false
+	}
+	for _, child := range UnnamedChildrenOf(node.NamedChild(0)) {
+		if child.Type() == modifier {
+			return true
+		}
+	}
+	return false
+}
+
+// ParameterType gets the type of a `formal_parameter` or `spread_parameter`
+//
+// A spread parameter has no fields, and may start with modifiers, such as
+// `final` or an annotation, so its type is the first child after those
+func ParameterType(param *sitter.Node) *sitter.Node {
+	if param.Type() != "spread_parameter" {
+		return param.ChildByFieldName("type")
+	}
+	for _, child := range NamedChildrenOf(param) {
+		if child.Type() != "modifiers" {
+			return child
+		}
+	}
+	return nil
+}
+
+// ParameterName gets the name of a `formal_parameter` or `spread_parameter`
+func ParameterName(param *sitter.Node) *sitter.Node {
+	if param.Type() != "spread_parameter" {
+		return param.ChildByFieldName("name")
+	}
+	for _, child := range NamedChildrenOf(param) {
+		if child.Type() == "variable_declarator" {
+			return child.ChildByFieldName("name")
+		}
+	}
+	return nil
+}
@@ -8,6 +8,10 @@ import (
 
 const JavaExt = ".java"
 
+// ResourceExt is the extension of the resource files that Java loads
+// properties and resource bundles from
+const ResourceExt = ".properties"
+
 func ReadSourcesInDir(directoryName string) ([]SourceFile, error) {
 	sources := []SourceFile{}
 
@@ -40,6 +44,22 @@ func ReadSourcesInDir(directoryName string) ([]SourceFile, error) {
 	return sources, nil
 }
 
+// ResourcesInDir returns the paths of the resource files in a directory, and
+// any directory inside of it
+func ResourcesInDir(directoryName string) ([]string, error) {
+	var resources []string
+	err := filepath.WalkDir(directoryName, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if filepath.Ext(path) == ResourceExt && !d.IsDir() {
+			resources = append(resources, path)
+		}
+		return nil
+	})
+	return resources, err
+}
+
 func ParseASTs(file SourceFile) {
 
 }
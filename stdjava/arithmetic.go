@@ -0,0 +1,35 @@
+package stdjava
+
+import (
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Java's integer division and remainder truncate towards zero, the same way as
+// Go's, so `-7 / 2` is `-3` and `-7 % 2` is `-1` in both. Go doesn't have a
+// remainder of floating-point numbers, and doesn't compile a division by a
+// constant zero, so these are implemented here for floats and doubles
+
+// FloatRemainder is an implementation of Java's `%` on floats and doubles,
+// whose result has the sign of the dividend, and is NaN if the divisor is zero,
+// or if the dividend is infinite
+func FloatRemainder[F constraints.Float](dividend, divisor F) F {
+	return F(math.Mod(float64(dividend), float64(divisor)))
+}
+
+// FloatDivide is an implementation of Java's `/` on floats and doubles, which
+// results in an infinity, or NaN, instead of an error when dividing by zero
+func FloatDivide[F constraints.Float](dividend, divisor F) F {
+	return dividend / divisor
+}
+
+// Abs is an implementation of Java's `Math.abs` on ints and longs, which
+// Go's `math.Abs` doesn't take. Just like Java, the smallest value of the type
+// stays negative, since its negation overflows
+func Abs[I constraints.Signed](value I) I {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,73 @@
+package stdjava
+
+import (
+	"math"
+	"testing"
+)
+
+// The expected values are the results of the same operations in Java
+
+func TestIntegerDivisionTruncates(t *testing.T) {
+	for _, test := range []struct {
+		dividend, divisor, quotient, remainder int32
+	}{
+		{7, 2, 3, 1},
+		{-7, 2, -3, -1},
+		{7, -2, -3, 1},
+		{-7, -2, 3, -1},
+		{math.MinInt32, -1, math.MinInt32, 0},
+	} {
+		dividend, divisor := test.dividend, test.divisor
+		if dividend/divisor != test.quotient || dividend%divisor != test.remainder {
+			t.Errorf("Expected %d / %d to be %d remainder %d, got %d remainder %d",
+				dividend, divisor, test.quotient, test.remainder, dividend/divisor, dividend%divisor)
+		}
+	}
+}
+
+func TestFloatRemainder(t *testing.T) {
+	for _, test := range []struct {
+		dividend, divisor, remainder float64
+	}{
+		{5.5, 2, 1.5},
+		{-5.5, 2, -1.5},
+		{5.5, -2, 1.5},
+		{1, math.Inf(1), 1},
+	} {
+		if result := FloatRemainder(test.dividend, test.divisor); result != test.remainder {
+			t.Errorf("Expected %v %% %v to be %v, got %v", test.dividend, test.divisor, test.remainder, result)
+		}
+	}
+	for _, operands := range [][2]float64{{1, 0}, {math.Inf(1), 2}, {math.NaN(), 2}} {
+		if result := FloatRemainder(operands[0], operands[1]); !math.IsNaN(result) {
+			t.Errorf("Expected %v %% %v to be NaN, got %v", operands[0], operands[1], result)
+		}
+	}
+	if result := FloatRemainder(float32(-7.5), 2); result != -1.5 {
+		t.Errorf("Expected the remainder of floats to be -1.5, got %v", result)
+	}
+}
+
+func TestFloatDivideByZero(t *testing.T) {
+	if result := FloatDivide(1.0, 0); !math.IsInf(result, 1) {
+		t.Errorf("Expected 1.0 / 0 to be Infinity, got %v", result)
+	}
+	if result := FloatDivide(-1.0, 0); !math.IsInf(result, -1) {
+		t.Errorf("Expected -1.0 / 0 to be -Infinity, got %v", result)
+	}
+	if result := FloatDivide(0.0, 0); !math.IsNaN(result) {
+		t.Errorf("Expected 0.0 / 0 to be NaN, got %v", result)
+	}
+}
+
+func TestAbs(t *testing.T) {
+	if result := Abs(int32(-5)); result != 5 {
+		t.Errorf("Expected the absolute value of -5 to be 5, got %d", result)
+	}
+	if result := Abs(int64(7)); result != 7 {
+		t.Errorf("Expected the absolute value of 7 to be 7, got %d", result)
+	}
+	if result := Abs(int32(math.MinInt32)); result != math.MinInt32 {
+		t.Errorf("Expected the absolute value of the smallest int to overflow, got %d", result)
+	}
+}
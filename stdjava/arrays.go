@@ -0,0 +1,23 @@
+package stdjava
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
+
+// ArrayGet is an implementation of Java's array access, which panics with an
+// `ArrayIndexOutOfBoundsException` when the index is out of bounds, instead of
+// with Go's runtime error, so that Java code that catches it still works
+func ArrayGet[T any, I constraints.Integer](array []T, index I) T {
+	return *ArrayElement(array, index)
+}
+
+// ArrayElement returns a pointer to an element of an array, for assigning to
+// it, and panics the same way as `ArrayGet` if the index is out of bounds
+func ArrayElement[T any, I constraints.Integer](array []T, index I) *T {
+	if index < 0 || uint64(index) >= uint64(len(array)) {
+		panic(NewException("ArrayIndexOutOfBoundsException", fmt.Sprintf("Index %d out of bounds for length %d", index, len(array)), nil))
+	}
+	return &array[index]
+}
@@ -0,0 +1,31 @@
+package stdjava
+
+import "testing"
+
+func TestArrayElement(t *testing.T) {
+	values := []int32{1, 2, 3}
+	*ArrayElement(values, int32(1)) += 5
+	if ArrayGet(values, 1) != 7 {
+		t.Errorf("Expected the element to be updated to 7, got %d", ArrayGet(values, 1))
+	}
+}
+
+func TestArrayIndexOutOfBounds(t *testing.T) {
+	for index, expected := range map[int32]string{
+		-1: "Index -1 out of bounds for length 3",
+		3:  "Index 3 out of bounds for length 3",
+	} {
+		func() {
+			defer func() {
+				exception, ok := recover().(*JavaException)
+				if !ok || exception.Class != "ArrayIndexOutOfBoundsException" {
+					t.Fatalf("Expected an ArrayIndexOutOfBoundsException for index %d, got %v", index, exception)
+				}
+				if exception.Message != expected {
+					t.Errorf("Expected the message %q, got %q", expected, exception.Message)
+				}
+			}()
+			ArrayGet([]int32{1, 2, 3}, index)
+		}()
+	}
+}
@@ -0,0 +1,17 @@
+package stdjava
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// DecodeBase64 is an implementation of the `decode` method of Java's Base64
+// decoders, which accept the encoded string with or without its padding, and
+// panic with an `IllegalArgumentException` if the string isn't valid
+func DecodeBase64(encoding *base64.Encoding, s string) []byte {
+	decoded, err := encoding.WithPadding(base64.NoPadding).DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		panic(NewException("IllegalArgumentException", "Illegal base64 character in "+s, nil))
+	}
+	return decoded
+}
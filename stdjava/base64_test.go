@@ -0,0 +1,25 @@
+package stdjava
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestDecodeBase64(t *testing.T) {
+	for _, encoded := range []string{"aGk=", "aGk"} {
+		if decoded := DecodeBase64(base64.StdEncoding, encoded); string(decoded) != "hi" {
+			t.Errorf("Expected %q to decode to \"hi\", got %q", encoded, decoded)
+		}
+	}
+	if decoded := DecodeBase64(base64.URLEncoding, "-_8"); string(decoded) != "\xfb\xff" {
+		t.Errorf("Expected the URL alphabet to be decoded, got %q", decoded)
+	}
+
+	defer func() {
+		exception, ok := recover().(*JavaException)
+		if !ok || exception.Class != "IllegalArgumentException" {
+			t.Errorf("Expected an IllegalArgumentException, got %v", exception)
+		}
+	}()
+	DecodeBase64(base64.StdEncoding, "a*b")
+}
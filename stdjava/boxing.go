@@ -0,0 +1,58 @@
+package stdjava
+
+import "sync"
+
+// The values that are cached by Java's wrapper classes, such as `Integer`,
+// so that boxing them always results in the same object
+const (
+	minCachedBox = -128
+	maxCachedBox = 127
+)
+
+// The boxes for every cached value, keyed by the value itself
+var boxCache sync.Map
+
+// Box is an implementation of the `valueOf` methods of Java's wrapper classes,
+// which box a primitive into an object
+//
+// Just like Java, boxing a small integer, or a boolean, always returns the same
+// pointer, but boxing any other value returns a new pointer, so comparing two
+// boxes with `==` gives the same result as comparing the objects in Java
+func Box[T comparable](value T) *T {
+	if !isCachedBox(value) {
+		return &value
+	}
+	box, _ := boxCache.LoadOrStore(any(value), &value)
+	return box.(*T)
+}
+
+func isCachedBox(value any) bool {
+	switch value := value.(type) {
+	case bool:
+		return true
+	case int8:
+		return true
+	case uint8:
+		// Every byte is cached, which is a uint8 where the Java bytes are kept
+		// unsigned
+		return true
+	case int16:
+		return value >= minCachedBox && value <= maxCachedBox
+	case int32:
+		return value >= minCachedBox && value <= maxCachedBox
+	case int64:
+		return value >= minCachedBox && value <= maxCachedBox
+	case int:
+		return value >= minCachedBox && value <= maxCachedBox
+	}
+	return false
+}
+
+// BoxedEquals compares the values inside of two boxes, where a null box is
+// only equal to another null box, instead of dereferencing it
+func BoxedEquals[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
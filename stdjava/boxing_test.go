@@ -0,0 +1,44 @@
+package stdjava
+
+import "testing"
+
+func TestBoxCachesSmallValues(t *testing.T) {
+	if Box(int32(100)) != Box(int32(100)) {
+		t.Errorf("Expected boxing a small integer twice to return the same box")
+	}
+	if Box(uint8(200)) != Box(uint8(200)) {
+		t.Errorf("Expected boxing any byte twice to return the same box")
+	}
+	if Box(true) != Box(true) {
+		t.Errorf("Expected boxing a boolean twice to return the same box")
+	}
+	// The same value of different types shouldn't share a box
+	if *Box(int64(5)) != 5 || *Box(int32(5)) != 5 {
+		t.Errorf("Expected boxes of different types to keep their own values")
+	}
+}
+
+func TestBoxDoesNotCacheLargeValues(t *testing.T) {
+	if Box(int32(1000)) == Box(int32(1000)) {
+		t.Errorf("Expected boxing a large integer twice to return different boxes")
+	}
+	if Box(1.5) == Box(1.5) {
+		t.Errorf("Expected boxing a float twice to return different boxes")
+	}
+	if *Box(int32(1000)) != 1000 {
+		t.Errorf("Expected the box to contain its value")
+	}
+}
+
+func TestBoxedEquals(t *testing.T) {
+	var none *int32
+	if !BoxedEquals(Box(int32(1000)), Box(int32(1000))) {
+		t.Errorf("Expected two boxes of the same value to be equal")
+	}
+	if BoxedEquals(none, Box(int32(1))) || BoxedEquals(Box(int32(1)), none) {
+		t.Errorf("Expected a null box to not be equal to a box")
+	}
+	if !BoxedEquals(none, none) {
+		t.Errorf("Expected two null boxes to be equal")
+	}
+}
@@ -0,0 +1,79 @@
+package stdjava
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Java's `StringBuilder` and `StringBuffer` are translated into a
+// `*strings.Builder`, which can only be appended to. The functions here
+// implement the rest of their methods by rebuilding the contents of the
+// builder, and index its contents by UTF-16 code units, the same way as the
+// functions for strings
+
+// NewStringBuilder is an implementation of `new StringBuilder(String)`, which
+// creates a builder that starts out with the given string
+func NewStringBuilder(s string) *strings.Builder {
+	builder := new(strings.Builder)
+	builder.WriteString(s)
+	return builder
+}
+
+// replaceContents replaces the contents of a builder with the given chars
+func replaceContents(b *strings.Builder, chars []rune) {
+	b.Reset()
+	b.WriteString(StringFromChars(chars))
+}
+
+// checkRange panics with the same exception as Java if the given range isn't
+// within the given number of chars
+func checkRange(start, end, length int32) {
+	if start < 0 || start > end || end > length {
+		panic(NewException("StringIndexOutOfBoundsException", fmt.Sprintf("start %d, end %d, length %d", start, end, length), nil))
+	}
+}
+
+// Insert is an implementation of StringBuilder's `insert`, which inserts a
+// string before the char at the given offset
+func Insert(b *strings.Builder, offset int32, s string) {
+	chars := ToCharArray(b.String())
+	checkRange(offset, offset, int32(len(chars)))
+	inserted := append(append(append([]rune{}, chars[:offset]...), ToCharArray(s)...), chars[offset:]...)
+	replaceContents(b, inserted)
+}
+
+// Delete is an implementation of StringBuilder's `delete`, which removes the
+// chars from the start up to the end, or up to the end of the contents if the
+// end is past it
+func Delete(b *strings.Builder, start, end int32) {
+	chars := ToCharArray(b.String())
+	end = min(end, int32(len(chars)))
+	checkRange(start, end, int32(len(chars)))
+	replaceContents(b, append(chars[:start:start], chars[end:]...))
+}
+
+// DeleteCharAt is an implementation of StringBuilder's `deleteCharAt`
+func DeleteCharAt(b *strings.Builder, index int32) {
+	chars := ToCharArray(b.String())
+	checkRange(index, index+1, int32(len(chars)))
+	replaceContents(b, append(chars[:index:index], chars[index+1:]...))
+}
+
+// SetLength is an implementation of StringBuilder's `setLength`, which
+// truncates the contents, or pads them with zero chars
+func SetLength(b *strings.Builder, length int32) {
+	chars := ToCharArray(b.String())
+	checkRange(0, length, max(length, int32(len(chars))))
+	for int32(len(chars)) < length {
+		chars = append(chars, 0)
+	}
+	replaceContents(b, chars[:length])
+}
+
+// ReverseBuilder is an implementation of StringBuilder's `reverse`, which
+// reverses the contents of the builder in place
+func ReverseBuilder(b *strings.Builder) {
+	reversed := Reverse(b.String())
+	b.Reset()
+	b.WriteString(reversed)
+}
@@ -0,0 +1,49 @@
+package stdjava
+
+import "testing"
+
+func TestBuilderInsertAndDelete(t *testing.T) {
+	b := NewStringBuilder("a😀c")
+	Insert(b, 3, "b")
+	if b.String() != "a😀bc" {
+		t.Errorf("Expected the string to be inserted after the surrogate pair, got %q", b.String())
+	}
+	Delete(b, 1, 3)
+	if b.String() != "abc" {
+		t.Errorf("Expected the surrogate pair to be deleted, got %q", b.String())
+	}
+	Delete(b, 1, 10)
+	if b.String() != "a" {
+		t.Errorf("Expected the end to be clamped to the length, got %q", b.String())
+	}
+	DeleteCharAt(b, 0)
+	if b.String() != "" {
+		t.Errorf("Expected the builder to be empty, got %q", b.String())
+	}
+}
+
+func TestBuilderInsertOutOfBounds(t *testing.T) {
+	defer func() {
+		thrown, ok := recover().(*JavaException)
+		if !ok || thrown.Class != "StringIndexOutOfBoundsException" {
+			t.Errorf("Expected a StringIndexOutOfBoundsException, got %v", thrown)
+		}
+	}()
+	Insert(NewStringBuilder("ab"), 3, "c")
+}
+
+func TestBuilderSetLengthAndReverse(t *testing.T) {
+	b := NewStringBuilder("abc")
+	SetLength(b, 2)
+	if b.String() != "ab" {
+		t.Errorf("Expected the builder to be truncated, got %q", b.String())
+	}
+	SetLength(b, 3)
+	if b.String() != "ab\x00" {
+		t.Errorf("Expected the builder to be padded with a zero char, got %q", b.String())
+	}
+	ReverseBuilder(b)
+	if b.String() != "\x00ba" {
+		t.Errorf("Expected the builder to be reversed, got %q", b.String())
+	}
+}
@@ -0,0 +1,119 @@
+package stdjava
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// The superclasses of the exceptions of Java's standard library, which decide
+// what a catch clause catches
+var exceptionSuperclasses = map[string]string{
+	"Exception":                       "Throwable",
+	"Error":                           "Throwable",
+	"RuntimeException":                "Exception",
+	"IllegalArgumentException":        "RuntimeException",
+	"NumberFormatException":           "IllegalArgumentException",
+	"IllegalStateException":           "RuntimeException",
+	"NullPointerException":            "RuntimeException",
+	"ArithmeticException":             "RuntimeException",
+	"ClassCastException":              "RuntimeException",
+	"IndexOutOfBoundsException":       "RuntimeException",
+	"ArrayIndexOutOfBoundsException":  "IndexOutOfBoundsException",
+	"StringIndexOutOfBoundsException": "IndexOutOfBoundsException",
+	"NegativeArraySizeException":      "RuntimeException",
+	"UnsupportedOperationException":   "RuntimeException",
+	"ConcurrentModificationException": "RuntimeException",
+	"NoSuchElementException":          "RuntimeException",
+	"InputMismatchException":          "NoSuchElementException",
+	"MissingResourceException":        "RuntimeException",
+	"UncheckedIOException":            "RuntimeException",
+	"DateTimeException":               "RuntimeException",
+	"IOException":                     "Exception",
+	"FileNotFoundException":           "IOException",
+	"EOFException":                    "IOException",
+	"UnsupportedEncodingException":    "IOException",
+	"InterruptedException":            "Exception",
+	"CloneNotSupportedException":      "Exception",
+	"TimeoutException":                "Exception",
+	"ExecutionException":              "Exception",
+	"ReflectiveOperationException":    "Exception",
+	"ClassNotFoundException":          "ReflectiveOperationException",
+	"GeneralSecurityException":        "Exception",
+	"NoSuchAlgorithmException":        "GeneralSecurityException",
+	"AssertionError":                  "Error",
+	"VirtualMachineError":             "Error",
+	"OutOfMemoryError":                "VirtualMachineError",
+	"StackOverflowError":              "VirtualMachineError",
+}
+
+// IsSubclass determines if an exception class is the given class, or one of
+// its subclasses
+//
+// A class that isn't part of Java's standard library is assumed to be a
+// subclass of `Exception`, or of `Error` if its name ends with "Error"
+func IsSubclass(class, superclass string) bool {
+	for class != "" {
+		if class == superclass {
+			return true
+		}
+		parent, known := exceptionSuperclasses[class]
+		if !known && class != "Throwable" {
+			parent = "Exception"
+			if strings.HasSuffix(class, "Error") {
+				parent = "Error"
+			}
+		}
+		class = parent
+	}
+	return false
+}
+
+// Thrown converts a value that was recovered from a panic into the exception
+// that Java would have thrown instead
+//
+// The panics of Go's runtime become the exceptions that Java throws for the
+// same mistakes, such as a `NullPointerException` for a nil pointer, and any
+// other value becomes a `RuntimeException`
+func Thrown(recovered any) Throwable {
+	switch thrown := recovered.(type) {
+	case Throwable:
+		return thrown
+	case runtime.Error:
+		message := thrown.Error()
+		switch {
+		case strings.Contains(message, "nil pointer dereference") || strings.Contains(message, "nil map"):
+			return NewException("NullPointerException", "", nil)
+		case strings.Contains(message, "index out of range") || strings.Contains(message, "slice bounds out of range"):
+			return NewException("ArrayIndexOutOfBoundsException", strings.TrimPrefix(message, "runtime error: "), nil)
+		case strings.Contains(message, "integer divide by zero"):
+			return NewException("ArithmeticException", "/ by zero", nil)
+		case strings.Contains(message, "interface conversion"):
+			return NewException("ClassCastException", strings.TrimPrefix(message, "interface conversion: "), nil)
+		}
+		return NewException("RuntimeException", message, nil)
+	case error:
+		return NewException("RuntimeException", thrown.Error(), nil)
+	}
+	return NewException("RuntimeException", fmt.Sprint(recovered), nil)
+}
+
+// Catch is an implementation of a catch clause, which returns the exception
+// that a recovered value was thrown as, and whether it is an instance of one of
+// the classes that the clause catches
+//
+// Exceptions with a type of their own, instead of a class name, are only
+// caught as an `Exception` or a `Throwable`
+func Catch(recovered any, classes ...string) (Throwable, bool) {
+	thrown := Thrown(recovered)
+	class := "Exception"
+	if exception, ok := thrown.(*JavaException); ok {
+		class = exception.Class
+	}
+	for _, catchable := range classes {
+		if IsSubclass(class, catchable) {
+			return thrown, true
+		}
+	}
+	return thrown, false
+}
@@ -0,0 +1,72 @@
+package stdjava
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsSubclass(t *testing.T) {
+	for _, test := range []struct {
+		class, superclass string
+		expected          bool
+	}{
+		{"NumberFormatException", "IllegalArgumentException", true},
+		{"NumberFormatException", "RuntimeException", true},
+		{"FileNotFoundException", "IOException", true},
+		{"IOException", "RuntimeException", false},
+		{"StackOverflowError", "Exception", false},
+		{"StackOverflowError", "Throwable", true},
+		{"ParseException", "Exception", true},
+		{"ParseException", "RuntimeException", false},
+	} {
+		if result := IsSubclass(test.class, test.superclass); result != test.expected {
+			t.Errorf("Expected IsSubclass(%q, %q) to be %v", test.class, test.superclass, test.expected)
+		}
+	}
+}
+
+func TestCatch(t *testing.T) {
+	thrown := NewException("NumberFormatException", "bad", nil)
+	if caught, ok := Catch(thrown, "IOException", "IllegalArgumentException"); !ok || caught != thrown {
+		t.Errorf("Expected the exception to be caught as an IllegalArgumentException, got %v", caught)
+	}
+	if _, ok := Catch(thrown, "IOException"); ok {
+		t.Errorf("Expected the exception not to be caught as an IOException")
+	}
+
+	var values []int
+	func() {
+		defer func() {
+			caught, ok := Catch(recover(), "ArrayIndexOutOfBoundsException")
+			if !ok {
+				t.Errorf("Expected indexing out of range to be caught, got %v", caught)
+			}
+		}()
+		_ = values[3]
+	}()
+
+	if caught, ok := Catch(errors.New("failed"), "RuntimeException"); !ok || caught.GetMessage() != "failed" {
+		t.Errorf("Expected an error to be caught as a RuntimeException, got %v", caught)
+	}
+}
+
+// An exception class that is generated as its own type embeds the runtime's
+// exception type
+type validationException struct {
+	JavaException
+	field string
+}
+
+func TestCatchEmbeddedException(t *testing.T) {
+	thrown := &validationException{JavaException: *NewException("ValidationException", "invalid", nil), field: "name"}
+	caught, ok := Catch(thrown, "Exception")
+	if !ok || caught != thrown {
+		t.Fatalf("Expected the exception to be caught as an Exception, got %v", caught)
+	}
+	if caught.Error() != "ValidationException: invalid" || caught.GetMessage() != "invalid" {
+		t.Errorf("Expected the embedded exception to describe it, got %q", caught.Error())
+	}
+	if _, ok := Catch(thrown, "IOException"); ok {
+		t.Errorf("Expected the exception not to be caught as an IOException")
+	}
+}
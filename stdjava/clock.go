@@ -0,0 +1,14 @@
+package stdjava
+
+import "time"
+
+// The time that NanoTime measures from, which keeps the reading of the
+// monotonic clock that it was taken with
+var clockOrigin = time.Now()
+
+// NanoTime is an implementation of `System.nanoTime`, which reads a monotonic
+// clock, in nanoseconds since an arbitrary time, so only the difference between
+// two of its values means anything
+func NanoTime() int64 {
+	return time.Since(clockOrigin).Nanoseconds()
+}
@@ -0,0 +1,14 @@
+package stdjava
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNanoTime(t *testing.T) {
+	start := NanoTime()
+	time.Sleep(time.Millisecond)
+	if elapsed := NanoTime() - start; elapsed < int64(time.Millisecond) {
+		t.Errorf("Expected at least a millisecond to elapse, got %dns", elapsed)
+	}
+}
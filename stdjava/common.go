@@ -17,7 +17,14 @@ func Ternary[T any](condition bool, result1, result2 T) T {
 // UnsignedRightShift is an implementation of Java's unsigned right shift
 // operation where a number is shifted over the number of times specified, but
 // the topmost bits are always filled in with zeroes
+//
+// A `long` is shifted over all of its 64 bits, and anything else over 32 bits,
+// the same as an `int`
 func UnsignedRightShift[V, A constraints.Integer](value V, amount A) V {
+	switch any(value).(type) {
+	case int64, uint64:
+		return V(uint64(value) >> amount)
+	}
 	return V(uint32(value) >> amount)
 }
 
@@ -0,0 +1,46 @@
+package stdjava
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"strings"
+)
+
+// The algorithms that `MessageDigestInstance` knows, by their names in Java
+var digestAlgorithms = map[string]func() hash.Hash{
+	"MD5":         md5.New,
+	"SHA":         sha1.New,
+	"SHA1":        sha1.New,
+	"SHA-1":       sha1.New,
+	"SHA-224":     sha256.New224,
+	"SHA-256":     sha256.New,
+	"SHA-384":     sha512.New384,
+	"SHA-512":     sha512.New,
+	"SHA-512/224": sha512.New512_224,
+	"SHA-512/256": sha512.New512_256,
+}
+
+// MessageDigestInstance is an implementation of `MessageDigest.getInstance`,
+// for an algorithm that isn't known until the code runs, which panics with a
+// `NoSuchAlgorithmException` if there is no such algorithm
+func MessageDigestInstance(algorithm string) hash.Hash {
+	if constructor, ok := digestAlgorithms[strings.ToUpper(algorithm)]; ok {
+		return constructor()
+	}
+	panic(NewException("NoSuchAlgorithmException", algorithm+" MessageDigest not available", nil))
+}
+
+// Digest is an implementation of the `digest` method of `MessageDigest`, which
+// finishes the hash of everything that was written to it, along with the given
+// input, and then resets it, so that it can be used again
+func Digest(h hash.Hash, input ...[]byte) []byte {
+	for _, data := range input {
+		h.Write(data)
+	}
+	sum := h.Sum(nil)
+	h.Reset()
+	return sum
+}
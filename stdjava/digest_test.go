@@ -0,0 +1,33 @@
+package stdjava
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestDigest(t *testing.T) {
+	h := sha256.New()
+	h.Write([]byte("a"))
+	if sum := hex.EncodeToString(Digest(h, []byte("bc"))); sum != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Errorf("Expected the SHA-256 of \"abc\", got %s", sum)
+	}
+	// The digest is reset after it is finished
+	if sum := hex.EncodeToString(Digest(h)); sum != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Errorf("Expected the SHA-256 of nothing, got %s", sum)
+	}
+}
+
+func TestMessageDigestInstance(t *testing.T) {
+	if size := MessageDigestInstance("sha-512").Size(); size != 64 {
+		t.Errorf("Expected SHA-512 to have 64 bytes, got %d", size)
+	}
+
+	defer func() {
+		exception, ok := recover().(*JavaException)
+		if !ok || exception.Class != "NoSuchAlgorithmException" {
+			t.Errorf("Expected a NoSuchAlgorithmException, got %v", exception)
+		}
+	}()
+	MessageDigestInstance("ROT13")
+}
@@ -0,0 +1,18 @@
+package stdjava
+
+// Must returns the value of a method that returns what it throws as an error,
+// and panics with the error instead, where it can't be returned
+func Must[T any](value T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// Check panics with the error of a void method that returns what it throws,
+// where it can't be returned
+func Check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
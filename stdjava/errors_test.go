@@ -0,0 +1,18 @@
+package stdjava
+
+import "testing"
+
+func TestMust(t *testing.T) {
+	if value := Must(int32(3), nil); value != 3 {
+		t.Errorf("Expected the value to be returned, got %d", value)
+	}
+	Check(nil)
+
+	thrown := NewException("IOException", "closed", nil)
+	defer func() {
+		if recovered := recover(); recovered != thrown {
+			t.Errorf("Expected the error to be panicked with, got %v", recovered)
+		}
+	}()
+	Must("", error(thrown))
+}
@@ -0,0 +1,155 @@
+package stdjava
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+)
+
+// Throwable is implemented by every exception that generated code can throw,
+// and is the equivalent of Java's `Throwable` class
+type Throwable interface {
+	error
+	GetMessage() string
+	GetCause() Throwable
+	PrintStackTrace()
+}
+
+// JavaException is the runtime representation of a Java exception that does
+// not have a generated type of its own, such as an `IllegalStateException`
+//
+// Exceptions are chained together through their cause, which is exposed to Go
+// through `Unwrap`, so the chain works with `errors.Is` and `errors.As`, the
+// same way as an error wrapped with `fmt.Errorf("%w")`
+type JavaException struct {
+	// The name of the exception's class, such as "IllegalStateException"
+	Class string
+	// The detail message of the exception
+	Message string
+	// The exception that caused this exception to be thrown, or nil
+	Cause Throwable
+
+	// The program counters of the goroutine's stack when the exception was created
+	stack []uintptr
+}
+
+// The maximum number of stack frames that are recorded for an exception
+const maxStackDepth = 64
+
+// NewException constructs an exception of the given class, with an optional
+// message and cause
+//
+// Just like Java, an exception constructed only from a cause takes the cause's
+// description as its message, and records the stack trace of where it was
+// created, which is almost always where it is thrown
+func NewException(class, message string, cause Throwable) *JavaException {
+	if message == "" && cause != nil {
+		message = cause.Error()
+	}
+	return &JavaException{
+		Class:   class,
+		Message: message,
+		Cause:   cause,
+		stack:   captureStack(3),
+	}
+}
+
+// captureStack records the current goroutine's stack, skipping the given
+// number of frames (including `runtime.Callers` and `captureStack` itself)
+func captureStack(skip int) []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	return pcs[:runtime.Callers(skip, pcs)]
+}
+
+// StackTrace returns the frames of the stack where the exception was created,
+// with the innermost frame first
+func (e *JavaException) StackTrace() []runtime.Frame {
+	var trace []runtime.Frame
+	if len(e.stack) == 0 {
+		return trace
+	}
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		trace = append(trace, frame)
+		if !more {
+			return trace
+		}
+	}
+}
+
+// Error describes the exception in the same format as Java's `toString`
+func (e *JavaException) Error() string {
+	if e.Message == "" {
+		return e.Class
+	}
+	return e.Class + ": " + e.Message
+}
+
+// Unwrap returns the cause of the exception
+func (e *JavaException) Unwrap() error {
+	if e.Cause == nil {
+		return nil
+	}
+	return e.Cause
+}
+
+// GetMessage is an implementation of Java's `getMessage`
+func (e *JavaException) GetMessage() string {
+	return e.Message
+}
+
+// GetLocalizedMessage is an implementation of Java's `getLocalizedMessage`
+func (e *JavaException) GetLocalizedMessage() string {
+	return e.Message
+}
+
+// GetCause is an implementation of Java's `getCause`
+func (e *JavaException) GetCause() Throwable {
+	return e.Cause
+}
+
+// String is an implementation of Java's `toString`
+func (e *JavaException) String() string {
+	return e.Error()
+}
+
+// PrintStackTrace is an implementation of Java's `printStackTrace`, which
+// writes the exception and its chain of causes to stderr
+func (e *JavaException) PrintStackTrace() {
+	PrintStackTrace(os.Stderr, e)
+}
+
+// A stackTracer is an error that recorded the stack where it was created
+type stackTracer interface {
+	StackTrace() []runtime.Frame
+}
+
+// PrintStackTrace writes an error and every error that caused it to the given
+// writer, in the same format that Java uses, including the stack frames of
+// any error that recorded them
+//
+// Ex:
+//
+//	IllegalStateException: bad state
+//		at main.(*parser).parse(parser.go:12)
+//		at main.main(main.go:5)
+//	Caused by: IOException: disk full
+//		at main.(*parser).read(parser.go:30)
+func PrintStackTrace(w io.Writer, err error) {
+	printTrace(w, "", err)
+	for cause := errors.Unwrap(err); cause != nil; cause = errors.Unwrap(cause) {
+		printTrace(w, "Caused by: ", cause)
+	}
+}
+
+func printTrace(w io.Writer, prefix string, err error) {
+	fmt.Fprintln(w, prefix+err.Error())
+	if tracer, ok := err.(stackTracer); ok {
+		for _, frame := range tracer.StackTrace() {
+			fmt.Fprintf(w, "\tat %s(%s:%d)\n", frame.Function, frame.File, frame.Line)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package stdjava
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExceptionMessage(t *testing.T) {
+	err := NewException("IllegalStateException", "bad state", nil)
+	if err.Error() != "IllegalStateException: bad state" {
+		t.Errorf("Got %q, expected %q", err.Error(), "IllegalStateException: bad state")
+	}
+	if NewException("RuntimeException", "", nil).Error() != "RuntimeException" {
+		t.Errorf("Expected an exception without a message to only contain its class")
+	}
+}
+
+func TestExceptionCauseChain(t *testing.T) {
+	root := NewException("IOException", "disk full", nil)
+	wrapped := NewException("RuntimeException", "", root)
+
+	if wrapped.GetMessage() != "IOException: disk full" {
+		t.Errorf("Expected the message to come from the cause. Got %q", wrapped.GetMessage())
+	}
+	if wrapped.GetCause() != root {
+		t.Errorf("Expected the cause to be the root exception")
+	}
+	if !errors.Is(wrapped, root) {
+		t.Errorf("Expected the wrapped exception to unwrap to its cause")
+	}
+
+	var trace bytes.Buffer
+	PrintStackTrace(&trace, NewException("IllegalStateException", "outer", wrapped))
+	var headers []string
+	for _, line := range strings.Split(strings.TrimSpace(trace.String()), "\n") {
+		if !strings.HasPrefix(line, "\tat ") {
+			headers = append(headers, line)
+		}
+	}
+	expected := []string{
+		"IllegalStateException: outer",
+		"Caused by: RuntimeException: IOException: disk full",
+		"Caused by: IOException: disk full",
+	}
+	if strings.Join(headers, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Got exceptions %q, expected %q", headers, expected)
+	}
+}
+
+func throwFromHelper() *JavaException {
+	return NewException("IllegalStateException", "from helper", nil)
+}
+
+func TestExceptionStackTrace(t *testing.T) {
+	frames := throwFromHelper().StackTrace()
+	if len(frames) < 2 {
+		t.Fatalf("Expected the stack to be recorded, got %d frames", len(frames))
+	}
+	if !strings.HasSuffix(frames[0].Function, "throwFromHelper") {
+		t.Errorf("Expected the innermost frame to be where the exception was created, got %s", frames[0].Function)
+	}
+	if !strings.HasSuffix(frames[1].Function, "TestExceptionStackTrace") {
+		t.Errorf("Expected the second frame to be the caller, got %s", frames[1].Function)
+	}
+
+	var trace bytes.Buffer
+	PrintStackTrace(&trace, throwFromHelper())
+	if !strings.Contains(trace.String(), "\tat github.com/NickyBoy89/java2go/stdjava.throwFromHelper(") {
+		t.Errorf("Expected the printed trace to contain the frames, got:\n%s", trace.String())
+	}
+}
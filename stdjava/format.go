@@ -0,0 +1,56 @@
+package stdjava
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FormatDouble is an implementation of Java's `Double.toString`, which formats
+// a double with the fewest digits that uniquely identify it, always with a
+// fractional part, and in scientific notation when it is very large or small
+//
+// Ex: `0.1` is formatted as "0.1", `100` as "100.0", and `1e10` as "1.0E10"
+func FormatDouble(value float64) string {
+	return formatFloating(value, 64)
+}
+
+// FormatFloat is an implementation of Java's `Float.toString`, which formats
+// a float the same way as a double, but only with the digits that a float has
+func FormatFloat(value float32) string {
+	return formatFloating(float64(value), 32)
+}
+
+func formatFloating(value float64, bitSize int) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "Infinity"
+	case math.IsInf(value, -1):
+		return "-Infinity"
+	case value == 0:
+		if math.Signbit(value) {
+			return "-0.0"
+		}
+		return "0.0"
+	}
+
+	// Values from 10^-3 up to 10^7 are written out in full
+	if magnitude := math.Abs(value); magnitude >= 1e-3 && magnitude < 1e7 {
+		formatted := strconv.FormatFloat(value, 'f', -1, bitSize)
+		if !strings.Contains(formatted, ".") {
+			formatted += ".0"
+		}
+		return formatted
+	}
+
+	// Otherwise, they are written in scientific notation, such as `1.5E-5`
+	formatted := strconv.FormatFloat(value, 'e', -1, bitSize)
+	mantissa, exponent, _ := strings.Cut(formatted, "e")
+	if !strings.Contains(mantissa, ".") {
+		mantissa += ".0"
+	}
+	power, _ := strconv.Atoi(exponent)
+	return mantissa + "E" + strconv.Itoa(power)
+}
@@ -0,0 +1,57 @@
+package stdjava
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormatDouble(t *testing.T) {
+	// Adding these as constants would be exact
+	tenth, fifth := 0.1, 0.2
+
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{0.1, "0.1"},
+		{100, "100.0"},
+		{-2.5, "-2.5"},
+		{tenth + fifth, "0.30000000000000004"},
+		{1e-3, "0.001"},
+		{9999999, "9999999.0"},
+		{1e7, "1.0E7"},
+		{1.5e-5, "1.5E-5"},
+		{-1.234e100, "-1.234E100"},
+		{math.MaxFloat64, "1.7976931348623157E308"},
+		{0, "0.0"},
+		{math.Copysign(0, -1), "-0.0"},
+		{math.NaN(), "NaN"},
+		{math.Inf(1), "Infinity"},
+		{math.Inf(-1), "-Infinity"},
+	}
+
+	for _, test := range tests {
+		if formatted := FormatDouble(test.value); formatted != test.expected {
+			t.Errorf("Formatted %v as %q, expected %q", test.value, formatted, test.expected)
+		}
+	}
+}
+
+func TestFormatFloat(t *testing.T) {
+	tests := []struct {
+		value    float32
+		expected string
+	}{
+		{0.1, "0.1"},
+		{1.5, "1.5"},
+		{1.0 / 3, "0.33333334"},
+		{1e10, "1.0E10"},
+		{3.4028235e38, "3.4028235E38"},
+	}
+
+	for _, test := range tests {
+		if formatted := FormatFloat(test.value); formatted != test.expected {
+			t.Errorf("Formatted %v as %q, expected %q", test.value, formatted, test.expected)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package stdjava
+
+import (
+	"maps"
+	"slices"
+)
+
+// HashSet is an implementation of `HashSet`, which is a map of its elements to
+// empty values, so that it is ranged over, and compared with `maps.Equal`, the
+// same as any other map
+type HashSet[T comparable] map[T]struct{}
+
+// NewHashSet creates a set of the given elements
+func NewHashSet[T comparable](elements ...T) HashSet[T] {
+	set := make(HashSet[T], len(elements))
+	for _, element := range elements {
+		set[element] = struct{}{}
+	}
+	return set
+}
+
+// Add adds an element to the set, and returns whether it wasn't in the set
+// already
+func (s HashSet[T]) Add(element T) bool {
+	if _, ok := s[element]; ok {
+		return false
+	}
+	s[element] = struct{}{}
+	return true
+}
+
+// Remove removes an element from the set, and returns whether it was in the set
+func (s HashSet[T]) Remove(element T) bool {
+	if _, ok := s[element]; !ok {
+		return false
+	}
+	delete(s, element)
+	return true
+}
+
+// Contains determines if an element is in the set
+func (s HashSet[T]) Contains(element T) bool {
+	_, ok := s[element]
+	return ok
+}
+
+// Size returns the number of elements in the set
+func (s HashSet[T]) Size() int32 {
+	return int32(len(s))
+}
+
+// IsEmpty determines if the set has no elements
+func (s HashSet[T]) IsEmpty() bool {
+	return len(s) == 0
+}
+
+// Clear removes every element from the set
+func (s HashSet[T]) Clear() {
+	clear(s)
+}
+
+// Elements returns the elements of the set, in no particular order
+func (s HashSet[T]) Elements() []T {
+	return slices.Collect(maps.Keys(s))
+}
+
+// Equals determines if two sets have the same elements
+func (s HashSet[T]) Equals(other HashSet[T]) bool {
+	return maps.Equal(s, other)
+}
+
+// The methods that take another collection take it as a set, and return
+// whether the set was changed, the same as Java
+
+// AddAll adds every element of another set, which is their union
+func (s HashSet[T]) AddAll(other HashSet[T]) bool {
+	changed := false
+	for element := range other {
+		changed = s.Add(element) || changed
+	}
+	return changed
+}
+
+// RemoveAll removes every element of another set, which is their difference
+func (s HashSet[T]) RemoveAll(other HashSet[T]) bool {
+	changed := false
+	for element := range other {
+		changed = s.Remove(element) || changed
+	}
+	return changed
+}
+
+// RetainAll removes every element that isn't in another set, which is their
+// intersection
+func (s HashSet[T]) RetainAll(other HashSet[T]) bool {
+	changed := false
+	for element := range s {
+		if !other.Contains(element) {
+			delete(s, element)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// ContainsAll determines if every element of another set is in the set
+func (s HashSet[T]) ContainsAll(other HashSet[T]) bool {
+	for element := range other {
+		if !s.Contains(element) {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns a new set of the elements that are in either set
+func Union[T comparable](a, b HashSet[T]) HashSet[T] {
+	union := maps.Clone(a)
+	union.AddAll(b)
+	return union
+}
+
+// Intersection returns a new set of the elements that are in both sets
+func Intersection[T comparable](a, b HashSet[T]) HashSet[T] {
+	intersection := maps.Clone(a)
+	intersection.RetainAll(b)
+	return intersection
+}
+
+// Difference returns a new set of the elements of the first set that aren't in
+// the second
+func Difference[T comparable](a, b HashSet[T]) HashSet[T] {
+	difference := maps.Clone(a)
+	difference.RemoveAll(b)
+	return difference
+}
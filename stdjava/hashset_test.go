@@ -0,0 +1,44 @@
+package stdjava
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestHashSet(t *testing.T) {
+	set := NewHashSet("a", "b")
+	if !set.Add("c") || set.Add("a") || set.Size() != 3 {
+		t.Errorf("Expected only a new element to be added, got %v", set.Elements())
+	}
+	if !set.Contains("b") || set.Contains("z") || !set.Remove("b") || set.Remove("b") {
+		t.Errorf("Expected the elements to be found and removed, got %v", set.Elements())
+	}
+	elements := set.Elements()
+	slices.Sort(elements)
+	if !slices.Equal(elements, []string{"a", "c"}) {
+		t.Errorf("Expected the remaining elements, got %v", elements)
+	}
+	set.Clear()
+	if !set.IsEmpty() {
+		t.Errorf("Expected the set to be cleared, got %v", set.Elements())
+	}
+}
+
+func TestHashSetAlgebra(t *testing.T) {
+	a, b := NewHashSet[int32](1, 2, 3), NewHashSet[int32](2, 3, 4)
+	if union := Union(a, b); !union.Equals(NewHashSet[int32](1, 2, 3, 4)) {
+		t.Errorf("Expected the union of the sets, got %v", union.Elements())
+	}
+	if intersection := Intersection(a, b); !intersection.Equals(NewHashSet[int32](2, 3)) {
+		t.Errorf("Expected the intersection of the sets, got %v", intersection.Elements())
+	}
+	if difference := Difference(a, b); !difference.Equals(NewHashSet[int32](1)) || a.Size() != 3 {
+		t.Errorf("Expected the difference of the sets, without changing them, got %v", difference.Elements())
+	}
+	if !a.ContainsAll(NewHashSet[int32](1, 3)) || a.ContainsAll(b) {
+		t.Errorf("Expected only a subset to be contained")
+	}
+	if !a.RetainAll(b) || a.RetainAll(b) || !a.Equals(NewHashSet[int32](2, 3)) {
+		t.Errorf("Expected retaining the elements to change the set once, got %v", a.Elements())
+	}
+}
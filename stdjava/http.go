@@ -0,0 +1,246 @@
+package stdjava
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HttpClient is an implementation of `java.net.http.HttpClient`, which sends
+// requests with a Go `*http.Client`
+type HttpClient struct {
+	Client *http.Client
+}
+
+// NewHttpClient is an implementation of `HttpClient.newHttpClient`
+func NewHttpClient() *HttpClient {
+	return &HttpClient{Client: &http.Client{}}
+}
+
+// Send is an implementation of `send`, which always reads the body of the
+// response as a string, and panics with an `IOException` if the request fails
+func (c *HttpClient) Send(request *HttpRequest) *HttpResponse {
+	req, err := http.NewRequest(request.Method, request.URI, bytes.NewReader(request.Body))
+	if err != nil {
+		panic(NewException("IllegalArgumentException", err.Error(), nil))
+	}
+	req.Header = request.Headers.Clone()
+
+	client := c.Client
+	if request.Timeout > 0 {
+		timed := *c.Client
+		timed.Timeout = request.Timeout
+		client = &timed
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		panic(NewException("IOException", err.Error(), nil))
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		panic(NewException("IOException", err.Error(), nil))
+	}
+	return &HttpResponse{status: resp.StatusCode, headers: resp.Header, body: string(body)}
+}
+
+// HttpRequest is an implementation of `java.net.http.HttpRequest`
+type HttpRequest struct {
+	Method  string
+	URI     string
+	Headers http.Header
+	Body    []byte
+	Timeout time.Duration
+}
+
+// HttpRequestBuilder is an implementation of `HttpRequest.Builder`, which
+// builds a GET request unless another method is set
+type HttpRequestBuilder struct {
+	request HttpRequest
+}
+
+// NewHttpRequestBuilder is an implementation of `HttpRequest.newBuilder`,
+// optionally with the URI of the request
+func NewHttpRequestBuilder(uri ...string) *HttpRequestBuilder {
+	builder := &HttpRequestBuilder{request: HttpRequest{Method: http.MethodGet, Headers: make(http.Header)}}
+	if len(uri) > 0 {
+		builder.request.URI = uri[0]
+	}
+	return builder
+}
+
+// URI sets the URI of the request
+func (b *HttpRequestBuilder) URI(uri string) *HttpRequestBuilder {
+	b.request.URI = uri
+	return b
+}
+
+// Header adds a value to a header of the request
+func (b *HttpRequestBuilder) Header(name, value string) *HttpRequestBuilder {
+	b.request.Headers.Add(name, value)
+	return b
+}
+
+// SetHeader replaces the values of a header of the request
+func (b *HttpRequestBuilder) SetHeader(name, value string) *HttpRequestBuilder {
+	b.request.Headers.Set(name, value)
+	return b
+}
+
+// Method sets the method of the request, and its body
+func (b *HttpRequestBuilder) Method(method, body string) *HttpRequestBuilder {
+	b.request.Method, b.request.Body = strings.ToUpper(method), []byte(body)
+	return b
+}
+
+// GET makes the request a GET request
+func (b *HttpRequestBuilder) GET() *HttpRequestBuilder {
+	return b.Method(http.MethodGet, "")
+}
+
+// DELETE makes the request a DELETE request
+func (b *HttpRequestBuilder) DELETE() *HttpRequestBuilder {
+	return b.Method(http.MethodDelete, "")
+}
+
+// POST makes the request a POST request with the given body
+func (b *HttpRequestBuilder) POST(body string) *HttpRequestBuilder {
+	return b.Method(http.MethodPost, body)
+}
+
+// PUT makes the request a PUT request with the given body
+func (b *HttpRequestBuilder) PUT(body string) *HttpRequestBuilder {
+	return b.Method(http.MethodPut, body)
+}
+
+// Build creates the request
+func (b *HttpRequestBuilder) Build() *HttpRequest {
+	request := b.request
+	request.Headers = b.request.Headers.Clone()
+	return &request
+}
+
+// HttpResponse is an implementation of `java.net.http.HttpResponse`, for a
+// response whose body was read as a string
+type HttpResponse struct {
+	status  int
+	headers http.Header
+	body    string
+}
+
+// StatusCode returns the status code of the response
+func (r *HttpResponse) StatusCode() int32 {
+	return int32(r.status)
+}
+
+// Body returns the body of the response
+func (r *HttpResponse) Body() string {
+	return r.body
+}
+
+// Header returns the first value of a header of the response, or an empty
+// string if it wasn't set
+func (r *HttpResponse) Header(name string) string {
+	return r.headers.Get(name)
+}
+
+// HttpURLConnection is an implementation of Java's legacy `HttpURLConnection`,
+// which sends its request the first time that its response is needed
+type HttpURLConnection struct {
+	request  HttpRequest
+	output   bytes.Buffer
+	response *HttpResponse
+}
+
+// OpenConnection is an implementation of `URL.openConnection`, for a URL that
+// uses HTTP
+func OpenConnection(url string) *HttpURLConnection {
+	return &HttpURLConnection{request: HttpRequest{Method: http.MethodGet, URI: url, Headers: make(http.Header)}}
+}
+
+// SetRequestMethod sets the method of the request
+func (c *HttpURLConnection) SetRequestMethod(method string) {
+	c.request.Method = strings.ToUpper(method)
+}
+
+// SetRequestProperty sets a header of the request
+func (c *HttpURLConnection) SetRequestProperty(name, value string) {
+	c.request.Headers.Set(name, value)
+}
+
+// AddRequestProperty adds a value to a header of the request
+func (c *HttpURLConnection) AddRequestProperty(name, value string) {
+	c.request.Headers.Add(name, value)
+}
+
+// SetDoOutput does nothing, since writing to the output stream is always
+// allowed
+func (c *HttpURLConnection) SetDoOutput(bool) {}
+
+// SetConnectTimeout sets how long the request can take, in milliseconds
+func (c *HttpURLConnection) SetConnectTimeout(millis int32) {
+	c.request.Timeout = time.Duration(millis) * time.Millisecond
+}
+
+// SetReadTimeout sets how long the request can take, in milliseconds
+func (c *HttpURLConnection) SetReadTimeout(millis int32) {
+	c.SetConnectTimeout(millis)
+}
+
+// GetOutputStream returns the writer for the body of the request
+func (c *HttpURLConnection) GetOutputStream() io.Writer {
+	return &c.output
+}
+
+// connect sends the request, if it hasn't been sent yet
+func (c *HttpURLConnection) connect() *HttpResponse {
+	if c.response == nil {
+		c.request.Body = c.output.Bytes()
+		// Like Java, writing a body turns a GET request into a POST request
+		if len(c.request.Body) > 0 && c.request.Method == http.MethodGet {
+			c.request.Method = http.MethodPost
+		}
+		c.response = NewHttpClient().Send(&c.request)
+	}
+	return c.response
+}
+
+// Connect is an implementation of `connect`, which sends the request
+func (c *HttpURLConnection) Connect() {
+	c.connect()
+}
+
+// GetResponseCode returns the status code of the response
+func (c *HttpURLConnection) GetResponseCode() int32 {
+	return c.connect().StatusCode()
+}
+
+// GetHeaderField returns the first value of a header of the response
+func (c *HttpURLConnection) GetHeaderField(name string) string {
+	return c.connect().Header(name)
+}
+
+// GetInputStream returns the body of the response, and panics with an
+// `IOException` if the response has an error status, the same way as Java
+func (c *HttpURLConnection) GetInputStream() io.Reader {
+	if response := c.connect(); response.status >= 400 {
+		panic(NewException("IOException", "Server returned HTTP response code: "+strconv.Itoa(response.status)+" for URL: "+c.request.URI, nil))
+	}
+	return strings.NewReader(c.response.body)
+}
+
+// GetErrorStream returns the body of a response that has an error status, or
+// nil otherwise
+func (c *HttpURLConnection) GetErrorStream() io.Reader {
+	if response := c.connect(); response.status >= 400 {
+		return strings.NewReader(response.body)
+	}
+	return nil
+}
+
+// Disconnect does nothing, since the body of the response has already been
+// read and closed
+func (c *HttpURLConnection) Disconnect() {}
@@ -0,0 +1,58 @@
+package stdjava
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func echoServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		w.Write([]byte(r.Header.Get("Accept") + ":" + string(body)))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHttpClient(t *testing.T) {
+	server := echoServer(t)
+
+	request := NewHttpRequestBuilder().URI(server.URL).Header("Accept", "text/plain").POST("hello").Build()
+	response := NewHttpClient().Send(request)
+	if response.StatusCode() != 200 || response.Body() != "text/plain:hello" || response.Header("X-Method") != "POST" {
+		t.Errorf("Expected the request to be echoed, got %d %q", response.StatusCode(), response.Body())
+	}
+}
+
+func TestHttpURLConnection(t *testing.T) {
+	server := echoServer(t)
+
+	connection := OpenConnection(server.URL)
+	connection.SetRequestProperty("Accept", "json")
+	connection.SetDoOutput(true)
+	connection.GetOutputStream().Write([]byte("body"))
+	if code := connection.GetResponseCode(); code != 200 {
+		t.Errorf("Expected a status of 200, got %d", code)
+	}
+	if body, _ := io.ReadAll(connection.GetInputStream()); string(body) != "json:body" || connection.GetHeaderField("X-Method") != "POST" {
+		t.Errorf("Expected the body to be posted, got %q", body)
+	}
+
+	missing := OpenConnection(server.URL + "/missing")
+	if code := missing.GetResponseCode(); code != 404 || missing.GetErrorStream() == nil {
+		t.Errorf("Expected a status of 404 with an error stream, got %d", code)
+	}
+	defer func() {
+		if exception, ok := recover().(*JavaException); !ok || exception.Class != "IOException" {
+			t.Errorf("Expected an IOException, got %v", exception)
+		}
+	}()
+	missing.GetInputStream()
+}
@@ -0,0 +1,29 @@
+package stdjava
+
+import "iter"
+
+// Iterator is Java's `Iterator`, which the classes that implement it satisfy
+// with their `HasNext` and `Next` methods
+type Iterator[T any] interface {
+	HasNext() bool
+	Next() T
+}
+
+// Iterable is Java's `Iterable`, which the classes that implement it satisfy
+// with the `All` method that is generated for them, which ranges over the
+// elements of their iterators
+type Iterable[T any] interface {
+	All() iter.Seq[T]
+}
+
+// Elements returns the sequence of the elements that an iterator has left,
+// which stops calling `Next` as soon as the loop over it stops
+func Elements[T any](it Iterator[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for it.HasNext() {
+			if !yield(it.Next()) {
+				return
+			}
+		}
+	}
+}
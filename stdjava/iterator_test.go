@@ -0,0 +1,35 @@
+package stdjava
+
+import (
+	"slices"
+	"testing"
+)
+
+type countdown struct {
+	left int32
+}
+
+func (c *countdown) HasNext() bool {
+	return c.left > 0
+}
+
+func (c *countdown) Next() int32 {
+	c.left--
+	return c.left + 1
+}
+
+func TestElements(t *testing.T) {
+	if elements := slices.Collect(Elements[int32](&countdown{left: 3})); !slices.Equal(elements, []int32{3, 2, 1}) {
+		t.Errorf("Expected the elements 3, 2, 1, got %v", elements)
+	}
+
+	it := &countdown{left: 5}
+	for element := range Elements[int32](it) {
+		if element == 4 {
+			break
+		}
+	}
+	if it.left != 3 {
+		t.Errorf("Expected the iterator to stop after the loop is broken out of, got %d elements left", it.left)
+	}
+}
@@ -0,0 +1,30 @@
+package stdjava
+
+import (
+	"reflect"
+	"slices"
+)
+
+// RemoveListener removes the first of a list of listeners that is the same as
+// the given one, the same as removing it from the list that a Java class keeps
+// of them. A listener that can't be compared, such as a function, is never the
+// same as another one, so it can't be removed
+func RemoveListener[L any](listeners []L, listener L) []L {
+	for index, registered := range listeners {
+		if sameListener(registered, listener) {
+			return slices.Delete(listeners, index, index+1)
+		}
+	}
+	return listeners
+}
+
+// sameListener determines if two listeners are the same object
+func sameListener(a, b any) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if kind := reflect.TypeOf(a); kind != reflect.TypeOf(b) || !kind.Comparable() {
+		return false
+	}
+	return a == b
+}
@@ -0,0 +1,31 @@
+package stdjava
+
+import "testing"
+
+type clickListener interface{ onClick() }
+
+type button struct{ clicks int }
+
+func (b *button) onClick() { b.clicks++ }
+
+type clickFunc func()
+
+func (f clickFunc) onClick() { f() }
+
+func TestRemoveListener(t *testing.T) {
+	first, second := &button{}, &button{}
+	function := clickFunc(func() {})
+	listeners := []clickListener{first, function, second, first}
+
+	listeners = RemoveListener(listeners, clickListener(first))
+	if len(listeners) != 3 || listeners[1] != clickListener(second) || listeners[2] != clickListener(first) {
+		t.Errorf("Expected only the first registration of the listener to be removed, got %v", listeners)
+	}
+	// A function can't be compared, so it is never removed, instead of panicking
+	if listeners = RemoveListener(listeners, clickListener(function)); len(listeners) != 3 {
+		t.Errorf("Expected a function to not be removed, got %v", listeners)
+	}
+	if listeners = RemoveListener(listeners, clickListener(&button{})); len(listeners) != 3 {
+		t.Errorf("Expected a listener that isn't registered to not be removed, got %v", listeners)
+	}
+}
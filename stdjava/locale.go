@@ -0,0 +1,152 @@
+package stdjava
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Locale is an implementation of Java's `Locale`, which is the language and
+// the region that text is converted and formatted for
+type Locale struct {
+	Language string
+	Country  string
+}
+
+// The locales that Java has constants for
+var (
+	LocaleRoot    = Locale{}
+	LocaleEnglish = Locale{Language: "en"}
+	LocaleUS      = Locale{Language: "en", Country: "US"}
+	LocaleUK      = Locale{Language: "en", Country: "GB"}
+	LocaleCanada  = Locale{Language: "en", Country: "CA"}
+	LocaleFrench  = Locale{Language: "fr"}
+	LocaleFrance  = Locale{Language: "fr", Country: "FR"}
+	LocaleGerman  = Locale{Language: "de"}
+	LocaleGermany = Locale{Language: "de", Country: "DE"}
+	LocaleItalian = Locale{Language: "it"}
+	LocaleItaly   = Locale{Language: "it", Country: "IT"}
+)
+
+// DefaultLocale is the locale that is used wherever Java would use the default
+// locale of the JVM, which depends on the machine that it runs on
+//
+// This is `LocaleUS` instead, so that the generated code behaves the same on
+// every machine, but it can be changed to match the locale that the Java code
+// was run with
+var DefaultLocale = LocaleUS
+
+// NewLocale is an implementation of the constructors of Java's `Locale`, which
+// take a language, and optionally a country. Any variant is ignored
+func NewLocale(language string, country ...string) Locale {
+	locale := Locale{Language: strings.ToLower(language)}
+	if len(country) > 0 {
+		locale.Country = strings.ToUpper(country[0])
+	}
+	return locale
+}
+
+// LocaleForLanguageTag is an implementation of Java's `Locale.forLanguageTag`,
+// for tags such as "en-US"
+func LocaleForLanguageTag(tag string) Locale {
+	language, rest, _ := strings.Cut(tag, "-")
+	country, _, _ := strings.Cut(rest, "-")
+	// Only two letters or three digits are a region, anything else is a script
+	// or a variant
+	if len(country) != 2 && len(country) != 3 {
+		country = ""
+	}
+	return NewLocale(language, country)
+}
+
+// String formats a locale the same way as Java's `Locale.toString`, such as "en_US"
+func (l Locale) String() string {
+	if l.Country == "" {
+		return l.Language
+	}
+	return l.Language + "_" + l.Country
+}
+
+// caseRules returns the rules that a locale converts the case of letters with
+func (l Locale) caseRules() unicode.SpecialCase {
+	switch l.Language {
+	case "tr", "az":
+		return unicode.TurkishCase
+	}
+	return nil
+}
+
+// ToLowerCaseIn is an implementation of Java's String `toLowerCase(Locale)`,
+// which follows the rules of the locale, such as the dotted and dotless `i`
+// of Turkish
+func ToLowerCaseIn(s string, locale Locale) string {
+	if rules := locale.caseRules(); rules != nil {
+		return strings.ToLowerSpecial(rules, s)
+	}
+	return ToLowerCase(s)
+}
+
+// ToUpperCaseIn is an implementation of Java's String `toUpperCase(Locale)`
+func ToUpperCaseIn(s string, locale Locale) string {
+	if rules := locale.caseRules(); rules != nil {
+		return strings.ToUpperSpecial(rules, s)
+	}
+	return ToUpperCase(s)
+}
+
+// NumberFormat is an implementation of the `NumberFormat` that Java's
+// `NumberFormat.getInstance` returns, which groups the digits of a number and
+// rounds it to at most three fractional digits
+type NumberFormat struct {
+	grouping string
+	decimal  string
+}
+
+// NumberInstance is an implementation of Java's `NumberFormat.getInstance` and
+// `NumberFormat.getNumberInstance`, for the separators of a few languages.
+// Every other language is formatted with the same separators as English
+func NumberInstance(locale Locale) *NumberFormat {
+	switch locale.Language {
+	case "de", "es", "it", "nl", "pt", "tr", "id", "da":
+		return &NumberFormat{grouping: ".", decimal: ","}
+	case "fr":
+		return &NumberFormat{grouping: " ", decimal: ","}
+	case "ru", "pl", "sv", "fi", "cs", "nb":
+		return &NumberFormat{grouping: " ", decimal: ","}
+	}
+	return &NumberFormat{grouping: ",", decimal: "."}
+}
+
+// Format formats a number, rounding it to even
+func (f *NumberFormat) Format(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 0):
+		if value < 0 {
+			return "-∞"
+		}
+		return "∞"
+	}
+
+	// strconv rounds exact halves to even
+	formatted := strconv.FormatFloat(math.Abs(value), 'f', 3, 64)
+	whole, fraction, _ := strings.Cut(formatted, ".")
+	fraction = strings.TrimRight(fraction, "0")
+
+	var grouped strings.Builder
+	if value < 0 && (whole != "0" || fraction != "") {
+		grouped.WriteByte('-')
+	}
+	for index, digit := range whole {
+		if index > 0 && (len(whole)-index)%3 == 0 {
+			grouped.WriteString(f.grouping)
+		}
+		grouped.WriteRune(digit)
+	}
+	if fraction != "" {
+		grouped.WriteString(f.decimal + fraction)
+	}
+	return grouped.String()
+}
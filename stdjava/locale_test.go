@@ -0,0 +1,52 @@
+package stdjava
+
+import "testing"
+
+func TestLocaleCaseConversion(t *testing.T) {
+	turkish := NewLocale("tr", "TR")
+	if upper := ToUpperCaseIn("title", turkish); upper != "TİTLE" {
+		t.Errorf("Expected the Turkish uppercase of \"title\" to be \"TİTLE\", got %q", upper)
+	}
+	if lower := ToLowerCaseIn("TITLE", turkish); lower != "tıtle" {
+		t.Errorf("Expected the Turkish lowercase of \"TITLE\" to be \"tıtle\", got %q", lower)
+	}
+	if upper := ToUpperCaseIn("title", LocaleRoot); upper != "TITLE" {
+		t.Errorf("Expected the uppercase of \"title\" to be \"TITLE\", got %q", upper)
+	}
+}
+
+func TestLocaleForLanguageTag(t *testing.T) {
+	tests := map[string]string{
+		"en-US":      "en_US",
+		"de":         "de",
+		"zh-Hant-TW": "zh",
+		"es-419":     "es_419",
+	}
+	for tag, expected := range tests {
+		if locale := LocaleForLanguageTag(tag).String(); locale != expected {
+			t.Errorf("Expected the tag %q to be the locale %q, got %q", tag, expected, locale)
+		}
+	}
+}
+
+func TestNumberFormat(t *testing.T) {
+	tests := []struct {
+		locale   Locale
+		value    float64
+		expected string
+	}{
+		{LocaleUS, 1234567.891, "1,234,567.891"},
+		{LocaleUS, 0.12345, "0.123"},
+		{LocaleUS, 0.0625, "0.062"},
+		{LocaleUS, 2.0004, "2"},
+		{LocaleUS, -1000, "-1,000"},
+		{LocaleUS, -0.0001, "0"},
+		{LocaleGermany, 1234.5, "1.234,5"},
+		{LocaleFrance, 1234.5, "1 234,5"},
+	}
+	for _, test := range tests {
+		if formatted := NumberInstance(test.locale).Format(test.value); formatted != test.expected {
+			t.Errorf("Formatted %v for %v as %q, expected %q", test.value, test.locale, formatted, test.expected)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package stdjava
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// The maps of Java are Go's maps, which are indexed, assigned, and deleted from
+// directly. These are the methods of `Map` that need more than one statement,
+// or that are used as values, such as `put` when its previous value is used
+//
+// A key that isn't in the map has the zero value of the map's values, which
+// is what these functions return in place of null
+
+// Entry is an implementation of `Map.Entry`, for the entries of a map that are
+// stored or passed around, and the entries that a sorted map returns, which are
+// a snapshot of the mapping when they were created
+type Entry[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// NewEntry is an implementation of `Map.entry`
+func NewEntry[K, V any](key K, value V) *Entry[K, V] {
+	return &Entry[K, V]{Key: key, Value: value}
+}
+
+// GetKey returns the key of the entry
+func (e *Entry[K, V]) GetKey() K {
+	return e.Key
+}
+
+// GetValue returns the value of the entry
+func (e *Entry[K, V]) GetValue() V {
+	return e.Value
+}
+
+// Put maps a key to a value, and returns the value that it was mapped to before
+func Put[K comparable, V any](m map[K]V, key K, value V) V {
+	previous := m[key]
+	m[key] = value
+	return previous
+}
+
+// Remove removes the mapping of a key, and returns the value that it had
+func Remove[K comparable, V any](m map[K]V, key K) V {
+	previous := m[key]
+	delete(m, key)
+	return previous
+}
+
+// ContainsKey determines if a key is mapped to a value
+func ContainsKey[K comparable, V any](m map[K]V, key K) bool {
+	_, ok := m[key]
+	return ok
+}
+
+// ContainsValue determines if any key is mapped to the given value
+func ContainsValue[K, V comparable](m map[K]V, value V) bool {
+	for _, v := range m {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// GetOrDefault returns the value of a key, or the default if it isn't mapped
+func GetOrDefault[K comparable, V any](m map[K]V, key K, defaultValue V) V {
+	if value, ok := m[key]; ok {
+		return value
+	}
+	return defaultValue
+}
+
+// PutIfAbsent maps a key to a value if it isn't mapped already, and returns the
+// value that it was mapped to before
+func PutIfAbsent[K comparable, V any](m map[K]V, key K, value V) V {
+	if previous, ok := m[key]; ok {
+		return previous
+	}
+	m[key] = value
+	return *new(V)
+}
+
+// Merge maps a key to a value if it isn't mapped already, and otherwise to the
+// result of combining its value with the given one, which it returns
+func Merge[K comparable, V any](m map[K]V, key K, value V, remapping func(V, V) V) V {
+	if previous, ok := m[key]; ok {
+		value = remapping(previous, value)
+	}
+	m[key] = value
+	return value
+}
+
+// PutUnique maps a key to a value, the same as `Collectors.toMap` without a
+// merge function, which panics with an `IllegalStateException` if the key is
+// mapped already
+func PutUnique[K comparable, V any](m map[K]V, key K, value V) {
+	if previous, ok := m[key]; ok {
+		panic(NewException("IllegalStateException", fmt.Sprintf("Duplicate key %v (attempted merging values %v and %v)", key, previous, value), nil))
+	}
+	m[key] = value
+}
+
+// Compute maps a key to the result of the function, which is called with the
+// key and its value, or the zero value if it isn't mapped
+func Compute[K comparable, V any](m map[K]V, key K, remapping func(K, V) V) V {
+	value := remapping(key, m[key])
+	m[key] = value
+	return value
+}
+
+// ComputeIfAbsent maps a key to the result of the function if it isn't mapped
+// already, and returns the value that it is mapped to
+func ComputeIfAbsent[K comparable, V any](m map[K]V, key K, mapping func(K) V) V {
+	if value, ok := m[key]; ok {
+		return value
+	}
+	value := mapping(key)
+	m[key] = value
+	return value
+}
+
+// ComputeIfPresent maps a key that is mapped to the result of the function,
+// which is called with the key and its value, and returns the new value
+func ComputeIfPresent[K comparable, V any](m map[K]V, key K, remapping func(K, V) V) V {
+	value, ok := m[key]
+	if !ok {
+		return value
+	}
+	value = remapping(key, value)
+	m[key] = value
+	return value
+}
+
+// Keys returns the keys of a map, for its `keySet` when it isn't ranged over
+func Keys[K comparable, V any](m map[K]V) []K {
+	return slices.Collect(maps.Keys(m))
+}
+
+// Values returns the values of a map, for its `values` when it isn't ranged
+// over
+func Values[K comparable, V any](m map[K]V) []V {
+	return slices.Collect(maps.Values(m))
+}
+
+// Entries returns the entries of a map, for its `entrySet` when it isn't ranged
+// over
+func Entries[K comparable, V any](m map[K]V) []*Entry[K, V] {
+	entries := make([]*Entry[K, V], 0, len(m))
+	for key, value := range m {
+		entries = append(entries, &Entry[K, V]{Key: key, Value: value})
+	}
+	return entries
+}
+
+// Sum is an implementation of `Integer::sum` and the other `sum` methods of the
+// wrapper classes, which are used to merge counts into a map
+func Sum[T Number](a, b T) T {
+	return a + b
+}
+
+// BoxedSum is Sum for the values of a map that are kept boxed
+func BoxedSum[T Number](a, b *T) *T {
+	return Box(*a + *b)
+}
@@ -0,0 +1,89 @@
+package stdjava
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMapMethods(t *testing.T) {
+	counts := map[string]int32{}
+	for _, word := range []string{"a", "b", "a", "c", "a"} {
+		Merge(counts, word, 1, Sum[int32])
+	}
+	if counts["a"] != 3 || counts["b"] != 1 || len(counts) != 3 {
+		t.Errorf("Expected the words to be counted, got %v", counts)
+	}
+	if previous := Put(counts, "b", 5); previous != 1 || counts["b"] != 5 {
+		t.Errorf("Expected put to return the previous value, got %d", previous)
+	}
+	if previous := PutIfAbsent(counts, "b", 7); previous != 5 || counts["b"] != 5 {
+		t.Errorf("Expected a mapped key to be kept, got %d", previous)
+	}
+	if value := GetOrDefault(counts, "z", -1); value != -1 {
+		t.Errorf("Expected the default for a key that isn't mapped, got %d", value)
+	}
+	if !ContainsKey(counts, "c") || ContainsKey(counts, "z") || !ContainsValue(counts, int32(3)) {
+		t.Errorf("Expected the keys and values to be found, got %v", counts)
+	}
+	if previous := Remove(counts, "c"); previous != 1 || ContainsKey(counts, "c") {
+		t.Errorf("Expected remove to return the removed value, got %d", previous)
+	}
+}
+
+func TestMapCompute(t *testing.T) {
+	groups := map[int32][]string{}
+	for _, word := range []string{"go", "java", "c", "rust"} {
+		group := ComputeIfAbsent(groups, int32(len(word)), func(int32) []string { return nil })
+		groups[int32(len(word))] = append(group, word)
+	}
+	if !slices.Equal(groups[4], []string{"java", "rust"}) {
+		t.Errorf("Expected the words to be grouped by length, got %v", groups)
+	}
+
+	lengths := map[string]int32{"a": 1}
+	Compute(lengths, "a", func(_ string, v int32) int32 { return v + 10 })
+	Compute(lengths, "b", func(_ string, v int32) int32 { return v + 10 })
+	if value := ComputeIfPresent(lengths, "c", func(_ string, v int32) int32 { return v * 2 }); value != 0 || ContainsKey(lengths, "c") {
+		t.Errorf("Expected a key that isn't mapped to stay unmapped, got %v", lengths)
+	}
+	if lengths["a"] != 11 || lengths["b"] != 10 {
+		t.Errorf("Expected the values to be computed, got %v", lengths)
+	}
+
+	keys := Keys(lengths)
+	slices.Sort(keys)
+	if !slices.Equal(keys, []string{"a", "b"}) || len(Values(lengths)) != 2 {
+		t.Errorf("Expected the keys and values of the map, got %v", keys)
+	}
+	for _, entry := range Entries(lengths) {
+		if lengths[entry.GetKey()] != entry.GetValue() {
+			t.Errorf("Expected the entries to match the map, got %v", entry)
+		}
+	}
+}
+
+func TestBoxedSum(t *testing.T) {
+	counts := map[string]*int32{}
+	for _, word := range []string{"a", "b", "a"} {
+		Merge(counts, word, Box[int32](1), BoxedSum[int32])
+	}
+	if *counts["a"] != 2 || *counts["b"] != 1 {
+		t.Errorf("Expected the boxed words to be counted, got %d and %d", *counts["a"], *counts["b"])
+	}
+}
+
+func TestPutUnique(t *testing.T) {
+	lengths := map[string]int32{}
+	PutUnique(lengths, "a", 1)
+	PutUnique(lengths, "bb", 2)
+	if len(lengths) != 2 || lengths["bb"] != 2 {
+		t.Errorf("Expected both keys to be mapped, got %v", lengths)
+	}
+
+	defer func() {
+		if err, ok := recover().(*JavaException); !ok || err.Class != "IllegalStateException" {
+			t.Errorf("Expected a duplicate key to panic with an IllegalStateException, got %v", err)
+		}
+	}()
+	PutUnique(lengths, "a", 3)
+}
@@ -0,0 +1,42 @@
+package stdjava
+
+import (
+	"reflect"
+	"sync"
+)
+
+// The objects of a class that has synchronized methods have a mutex of their
+// own, which is locked the same way as Java locks the monitor of an object.
+// Every other object that is synchronized on is given a mutex here, the first
+// time that it is locked
+//
+// Unlike the monitors of Java, these mutexes aren't reentrant, so a thread that
+// locks an object twice deadlocks instead
+
+// The mutexes of the objects that don't have one of their own, which are kept
+// for as long as the program runs
+var monitors sync.Map
+
+// A slice, which is an array in Java, is locked by the array that it refers to
+type arrayMonitor uintptr
+
+// A class is locked by its name, for its static synchronized methods
+type classMonitor string
+
+// Monitor returns the mutex that locks an object, which is always the same
+// mutex for the same object
+func Monitor(object any) *sync.Mutex {
+	var key any = object
+	if value := reflect.ValueOf(object); object != nil && !value.Type().Comparable() {
+		key = arrayMonitor(value.Pointer())
+	}
+	monitor, _ := monitors.LoadOrStore(key, new(sync.Mutex))
+	return monitor.(*sync.Mutex)
+}
+
+// ClassMonitor returns the mutex that locks a class, which the static
+// synchronized methods of the class, and the blocks that synchronize on the
+// class's literal, lock
+func ClassMonitor(class string) *sync.Mutex {
+	return Monitor(classMonitor(class))
+}
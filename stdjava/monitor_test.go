@@ -0,0 +1,37 @@
+package stdjava
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestMonitor(t *testing.T) {
+	type account struct{ balance int }
+	first, second := &account{}, &account{}
+	if Monitor(first) != Monitor(first) || Monitor(first) == Monitor(second) {
+		t.Errorf("Expected every object to have a mutex of its own")
+	}
+	values := []int32{1, 2}
+	if Monitor(values) != Monitor(values) || Monitor(values) == Monitor([]int32{1, 2}) {
+		t.Errorf("Expected every array to have a mutex of its own")
+	}
+	if ClassMonitor("Account") != ClassMonitor("Account") || ClassMonitor("Account") == Monitor("Account") {
+		t.Errorf("Expected a class to be locked separately from a string")
+	}
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			monitor := Monitor(first)
+			monitor.Lock()
+			defer monitor.Unlock()
+			first.balance++
+		}()
+	}
+	wg.Wait()
+	if first.balance != 50 {
+		t.Errorf("Expected every increment to be locked, got %d", first.balance)
+	}
+}
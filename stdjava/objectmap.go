@@ -0,0 +1,263 @@
+package stdjava
+
+import "slices"
+
+// Hashable is an object that declares its own `equals` and `hashCode`, which
+// the keys of a `HashMap` and the elements of a `HashSet` are found with in
+// Java, instead of by their identity
+type Hashable interface {
+	Equals(other any) bool
+	HashCode() int32
+}
+
+// ObjectMap is an implementation of `HashMap`, for keys that are objects with
+// their own `equals` and `hashCode`, which a Go map can't use, since it
+// compares the pointers to the objects instead
+//
+// The keys are kept in buckets of the same hash, and are compared with
+// `Equals`, the same as Java. A missing key is allowed, and is equal only to
+// itself. The keys are iterated over in the order that they were added
+type ObjectMap[K Hashable, V any] struct {
+	buckets map[int32][]*Entry[K, V]
+	entries []*Entry[K, V]
+}
+
+// NewObjectMap is an implementation of `new HashMap<>()`
+func NewObjectMap[K Hashable, V any]() *ObjectMap[K, V] {
+	return &ObjectMap[K, V]{buckets: make(map[int32][]*Entry[K, V])}
+}
+
+// find returns the entry of a key, or nil if the key isn't in the map
+func (m *ObjectMap[K, V]) find(key K) *Entry[K, V] {
+	for _, entry := range m.buckets[ObjectHashCode(key)] {
+		if Equals(entry.Key, key) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// Put associates a value with a key, and returns the value that was
+// associated with it before, or the zero value if there was none
+func (m *ObjectMap[K, V]) Put(key K, value V) V {
+	if entry := m.find(key); entry != nil {
+		previous := entry.Value
+		entry.Value = value
+		return previous
+	}
+	entry := &Entry[K, V]{Key: key, Value: value}
+	hash := ObjectHashCode(key)
+	m.buckets[hash] = append(m.buckets[hash], entry)
+	m.entries = append(m.entries, entry)
+	var zero V
+	return zero
+}
+
+// PutIfAbsent associates a value with a key that isn't in the map, and returns
+// the value that the key is associated with already otherwise
+func (m *ObjectMap[K, V]) PutIfAbsent(key K, value V) V {
+	if entry := m.find(key); entry != nil {
+		return entry.Value
+	}
+	return m.Put(key, value)
+}
+
+// Get returns the value that is associated with a key, or the zero value if
+// there is none
+func (m *ObjectMap[K, V]) Get(key K) V {
+	return m.GetOrDefault(key, *new(V))
+}
+
+// GetOrDefault returns the value that is associated with a key, or the given
+// value if there is none
+func (m *ObjectMap[K, V]) GetOrDefault(key K, defaultValue V) V {
+	if entry := m.find(key); entry != nil {
+		return entry.Value
+	}
+	return defaultValue
+}
+
+// ContainsKey determines if a value is associated with a key
+func (m *ObjectMap[K, V]) ContainsKey(key K) bool {
+	return m.find(key) != nil
+}
+
+// Remove removes a key, and returns the value that was associated with it, or
+// the zero value if there was none
+func (m *ObjectMap[K, V]) Remove(key K) V {
+	entry := m.find(key)
+	if entry == nil {
+		var zero V
+		return zero
+	}
+	hash := ObjectHashCode(key)
+	isEntry := func(e *Entry[K, V]) bool { return e == entry }
+	if m.buckets[hash] = slices.DeleteFunc(m.buckets[hash], isEntry); len(m.buckets[hash]) == 0 {
+		delete(m.buckets, hash)
+	}
+	m.entries = slices.DeleteFunc(m.entries, isEntry)
+	return entry.Value
+}
+
+// Size returns the number of keys in the map
+func (m *ObjectMap[K, V]) Size() int32 {
+	return int32(len(m.entries))
+}
+
+// IsEmpty determines if the map has no keys
+func (m *ObjectMap[K, V]) IsEmpty() bool {
+	return len(m.entries) == 0
+}
+
+// Clear removes every key from the map
+func (m *ObjectMap[K, V]) Clear() {
+	clear(m.buckets)
+	m.entries = nil
+}
+
+// KeySet returns the keys of the map
+func (m *ObjectMap[K, V]) KeySet() []K {
+	keys := make([]K, len(m.entries))
+	for ind, entry := range m.entries {
+		keys[ind] = entry.Key
+	}
+	return keys
+}
+
+// Values returns the values of the map, in the order of their keys
+func (m *ObjectMap[K, V]) Values() []V {
+	values := make([]V, len(m.entries))
+	for ind, entry := range m.entries {
+		values[ind] = entry.Value
+	}
+	return values
+}
+
+// EntrySet returns the entries of the map, which change the map when their
+// values are set
+func (m *ObjectMap[K, V]) EntrySet() []*Entry[K, V] {
+	return slices.Clone(m.entries)
+}
+
+// PutAll associates every key of another map with its value in that map
+func (m *ObjectMap[K, V]) PutAll(other *ObjectMap[K, V]) {
+	for _, entry := range other.entries {
+		m.Put(entry.Key, entry.Value)
+	}
+}
+
+// Clone is an implementation of `new HashMap<>(other)`, which copies the
+// entries of another map
+func (m *ObjectMap[K, V]) Clone() *ObjectMap[K, V] {
+	clone := NewObjectMap[K, V]()
+	clone.PutAll(m)
+	return clone
+}
+
+// ObjectSet is an implementation of `HashSet`, for elements that are objects
+// with their own `equals` and `hashCode`, which is the keys of an ObjectMap
+type ObjectSet[T Hashable] struct {
+	elements *ObjectMap[T, struct{}]
+}
+
+// NewObjectSet creates a set of the given elements
+func NewObjectSet[T Hashable](elements ...T) *ObjectSet[T] {
+	set := &ObjectSet[T]{elements: NewObjectMap[T, struct{}]()}
+	for _, element := range elements {
+		set.Add(element)
+	}
+	return set
+}
+
+// Add adds an element to the set, and returns whether it wasn't in the set
+// already
+func (s *ObjectSet[T]) Add(element T) bool {
+	if s.elements.ContainsKey(element) {
+		return false
+	}
+	s.elements.Put(element, struct{}{})
+	return true
+}
+
+// Remove removes an element from the set, and returns whether it was in the set
+func (s *ObjectSet[T]) Remove(element T) bool {
+	if !s.elements.ContainsKey(element) {
+		return false
+	}
+	s.elements.Remove(element)
+	return true
+}
+
+// Contains determines if an element is in the set
+func (s *ObjectSet[T]) Contains(element T) bool {
+	return s.elements.ContainsKey(element)
+}
+
+// Size returns the number of elements in the set
+func (s *ObjectSet[T]) Size() int32 {
+	return s.elements.Size()
+}
+
+// IsEmpty determines if the set has no elements
+func (s *ObjectSet[T]) IsEmpty() bool {
+	return s.elements.IsEmpty()
+}
+
+// Clear removes every element from the set
+func (s *ObjectSet[T]) Clear() {
+	s.elements.Clear()
+}
+
+// Elements returns the elements of the set, in the order that they were added,
+// which is what a loop over the set ranges over
+func (s *ObjectSet[T]) Elements() []T {
+	return s.elements.KeySet()
+}
+
+// AddAll adds every element of another set, and returns whether the set was
+// changed
+func (s *ObjectSet[T]) AddAll(other *ObjectSet[T]) bool {
+	changed := false
+	for _, element := range other.Elements() {
+		changed = s.Add(element) || changed
+	}
+	return changed
+}
+
+// RemoveAll removes every element of another set, and returns whether the set
+// was changed
+func (s *ObjectSet[T]) RemoveAll(other *ObjectSet[T]) bool {
+	changed := false
+	for _, element := range other.Elements() {
+		changed = s.Remove(element) || changed
+	}
+	return changed
+}
+
+// RetainAll removes every element that isn't in another set, and returns
+// whether the set was changed
+func (s *ObjectSet[T]) RetainAll(other *ObjectSet[T]) bool {
+	changed := false
+	for _, element := range s.Elements() {
+		if !other.Contains(element) {
+			changed = s.Remove(element) || changed
+		}
+	}
+	return changed
+}
+
+// ContainsAll determines if every element of another set is in the set
+func (s *ObjectSet[T]) ContainsAll(other *ObjectSet[T]) bool {
+	for _, element := range other.Elements() {
+		if !s.Contains(element) {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone is an implementation of `new HashSet<>(other)`, which copies the
+// elements of another set
+func (s *ObjectSet[T]) Clone() *ObjectSet[T] {
+	return NewObjectSet(s.Elements()...)
+}
@@ -0,0 +1,68 @@
+package stdjava
+
+import (
+	"slices"
+	"testing"
+)
+
+// point is an object with its own `equals` and `hashCode`, where every point
+// on the same row has the same hash
+type point struct {
+	x, y int32
+}
+
+func (p *point) Equals(other any) bool {
+	that, ok := other.(*point)
+	return ok && p.x == that.x && p.y == that.y
+}
+
+func (p *point) HashCode() int32 {
+	return p.y
+}
+
+func TestObjectMap(t *testing.T) {
+	m := NewObjectMap[*point, string]()
+	m.Put(&point{1, 2}, "a")
+	m.Put(&point{3, 2}, "b")
+	if previous := m.Put(&point{1, 2}, "c"); previous != "a" {
+		t.Errorf("Expected an equal key to replace the value, got the previous value %q", previous)
+	}
+	if m.Size() != 2 || m.Get(&point{1, 2}) != "c" || m.Get(&point{3, 2}) != "b" {
+		t.Errorf("Expected two keys with the same hash, got %v", m.Values())
+	}
+	if m.ContainsKey(&point{2, 1}) || m.GetOrDefault(&point{2, 1}, "none") != "none" {
+		t.Error("Expected a key that isn't in the map to be missing")
+	}
+	m.Put(nil, "null")
+	if m.Get(nil) != "null" {
+		t.Errorf("Expected a missing key to be allowed, got %q", m.Get(nil))
+	}
+	if removed := m.Remove(&point{1, 2}); removed != "c" || m.ContainsKey(&point{1, 2}) {
+		t.Errorf("Expected the key to be removed, got %q", removed)
+	}
+	if values := m.Values(); !slices.Equal(values, []string{"b", "null"}) {
+		t.Errorf("Expected the values in the order that they were added, got %v", values)
+	}
+	clone := m.Clone()
+	m.Clear()
+	if !m.IsEmpty() || clone.Size() != 2 {
+		t.Errorf("Expected the clone to keep its entries, got %d", clone.Size())
+	}
+}
+
+func TestObjectSet(t *testing.T) {
+	s := NewObjectSet(&point{1, 1}, &point{2, 1})
+	if s.Add(&point{1, 1}) || !s.Add(&point{1, 2}) || s.Size() != 3 {
+		t.Errorf("Expected only the elements that aren't equal to be added, got %v", s.Elements())
+	}
+	if !s.Contains(&point{2, 1}) || s.Contains(&point{2, 2}) {
+		t.Error("Expected the set to contain the equal elements")
+	}
+	other := NewObjectSet(&point{2, 1}, &point{5, 5})
+	if !s.RetainAll(other) || s.Size() != 1 || !s.ContainsAll(NewObjectSet(&point{2, 1})) {
+		t.Errorf("Expected the intersection of the sets, got %v", s.Elements())
+	}
+	if !s.AddAll(other) || s.Size() != 2 || !s.RemoveAll(other) || !s.IsEmpty() {
+		t.Errorf("Expected the union and the difference of the sets, got %v", s.Elements())
+	}
+}
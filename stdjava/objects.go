@@ -0,0 +1,132 @@
+package stdjava
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math"
+	"reflect"
+	"unicode/utf16"
+)
+
+// IsNull determines if a value is nil, including a nil pointer, map, slice,
+// function, or channel that is stored in an interface, which Go doesn't
+// consider to be equal to nil
+func IsNull(value any) bool {
+	if value == nil {
+		return true
+	}
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface, reflect.UnsafePointer:
+		return v.IsNil()
+	}
+	return false
+}
+
+// RequireNonNull is an implementation of `Objects.requireNonNull`, which
+// returns the value, and panics with a `NullPointerException` with the
+// optional message if it is nil
+func RequireNonNull[T any](value T, message ...string) T {
+	if IsNull(value) {
+		var detail string
+		if len(message) > 0 {
+			detail = message[0]
+		}
+		panic(NewException("NullPointerException", detail, nil))
+	}
+	return value
+}
+
+// ObjectToString is an implementation of `Objects.toString`, which describes
+// a value, or returns the optional default if the value is nil, and "null"
+// otherwise
+func ObjectToString(value any, nullDefault ...string) string {
+	if IsNull(value) {
+		if len(nullDefault) > 0 {
+			return nullDefault[0]
+		}
+		return "null"
+	}
+	switch v := value.(type) {
+	case fmt.Stringer:
+		return v.String()
+	case error:
+		return v.Error()
+	}
+	return fmt.Sprint(value)
+}
+
+// Equals is an implementation of `Objects.equals`, which determines if two
+// values are equal with the `Equals` method of the first value, if it has one.
+// Other objects are only equal to themselves, and anything else is compared by
+// its contents
+func Equals(value, other any) bool {
+	if IsNull(value) || IsNull(other) {
+		return IsNull(value) && IsNull(other)
+	}
+	if equaler, ok := value.(interface{ Equals(any) bool }); ok {
+		return equaler.Equals(other)
+	}
+	if reflect.TypeOf(value).Comparable() {
+		return value == other
+	}
+	return reflect.DeepEqual(value, other)
+}
+
+// The seed of the hashes of the objects that don't have a `HashCode` of their
+// own, which is the same for the whole program, so that an object always has
+// the same hash
+var identitySeed = maphash.MakeSeed()
+
+// ObjectHashCode is an implementation of `Objects.hashCode`, which is the
+// `HashCode` of a value that has one, or the same hash as Java's wrapper
+// classes and strings. A missing value is 0, and any other object is hashed by
+// its identity, the same as Java's `Object.hashCode`
+func ObjectHashCode(value any) int32 {
+	if IsNull(value) {
+		return 0
+	}
+	switch v := value.(type) {
+	case interface{ HashCode() int32 }:
+		return v.HashCode()
+	case string:
+		var hash int32
+		for _, unit := range utf16.Encode([]rune(v)) {
+			hash = 31*hash + int32(unit)
+		}
+		return hash
+	case bool:
+		if v {
+			return 1231
+		}
+		return 1237
+	case int8:
+		return int32(v)
+	case int16:
+		return int32(v)
+	case int32:
+		return v
+	case uint16:
+		return int32(v)
+	case int64:
+		return int32(v ^ int64(uint64(v)>>32))
+	case float32:
+		return int32(math.Float32bits(v))
+	case float64:
+		bits := math.Float64bits(v)
+		return int32(bits ^ bits>>32)
+	}
+	if reflect.TypeOf(value).Comparable() {
+		return int32(maphash.Comparable(identitySeed, value))
+	}
+	return int32(maphash.String(identitySeed, fmt.Sprint(value)))
+}
+
+// Hash is an implementation of `Objects.hash`, which combines the hashes of
+// the given values the same way as Java
+func Hash(values ...any) int32 {
+	var hash int32 = 1
+	for _, value := range values {
+		hash = 31*hash + ObjectHashCode(value)
+	}
+	return hash
+}
@@ -0,0 +1,113 @@
+package stdjava
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsNull(t *testing.T) {
+	var pointer *int
+	var values map[string]int
+	if !IsNull(nil) || !IsNull(pointer) || !IsNull(values) {
+		t.Errorf("Expected nil values to be null, even when they are stored in an interface")
+	}
+	if IsNull(0) || IsNull("") || IsNull(&struct{}{}) {
+		t.Errorf("Expected values that aren't nil not to be null")
+	}
+}
+
+func TestRequireNonNull(t *testing.T) {
+	value := 3
+	if RequireNonNull(&value) != &value {
+		t.Errorf("Expected the value to be returned")
+	}
+
+	defer func() {
+		caught, ok := Catch(recover(), "NullPointerException")
+		if !ok || caught.GetMessage() != "value is required" {
+			t.Errorf("Expected a nil value to throw a NullPointerException with the message, got %v", caught)
+		}
+	}()
+	var missing *int
+	RequireNonNull(missing, "value is required")
+}
+
+func TestObjectToString(t *testing.T) {
+	var missing *int
+	for _, test := range []struct {
+		value    any
+		defaults []string
+		expected string
+	}{
+		{missing, nil, "null"},
+		{nil, []string{"none"}, "none"},
+		{42, nil, "42"},
+		{errors.New("failed"), nil, "failed"},
+		{NewException("IOException", "disk full", nil), []string{"none"}, "IOException: disk full"},
+	} {
+		if result := ObjectToString(test.value, test.defaults...); result != test.expected {
+			t.Errorf("Expected ObjectToString(%v) to be %q, got %q", test.value, test.expected, result)
+		}
+	}
+}
+
+type equalsByName struct {
+	name string
+}
+
+func (ebn *equalsByName) Equals(other any) bool {
+	that, ok := other.(*equalsByName)
+	return ok && ebn.name == that.name
+}
+
+func TestEquals(t *testing.T) {
+	var missing *int
+	first, second := 1, 1
+	for _, test := range []struct {
+		value, other any
+		expected     bool
+	}{
+		{nil, missing, true},
+		{missing, 3, false},
+		{"a", "a", true},
+		{int32(3), int64(3), false},
+		{&first, &second, false},
+		{&first, &first, true},
+		{&equalsByName{"a"}, &equalsByName{"a"}, true},
+		{&equalsByName{"a"}, &equalsByName{"b"}, false},
+		{[]int{1, 2}, []int{1, 2}, true},
+	} {
+		if result := Equals(test.value, test.other); result != test.expected {
+			t.Errorf("Expected Equals(%v, %v) to be %v, got %v", test.value, test.other, test.expected, result)
+		}
+	}
+}
+
+func TestObjectHashCode(t *testing.T) {
+	var missing *equalsByName
+	for _, test := range []struct {
+		value    any
+		expected int32
+	}{
+		{nil, 0},
+		{missing, 0},
+		{"Hello", 69609650},
+		{"a much longer string than an int can hold", 1871000096},
+		{int32(-7), -7},
+		{int64(1) << 32, 1},
+		{true, 1231},
+		{1.5, 1073217536},
+		{&point{1, 2}, 2},
+	} {
+		if hash := ObjectHashCode(test.value); hash != test.expected {
+			t.Errorf("Expected the hash of %v to be %d, got %d", test.value, test.expected, hash)
+		}
+	}
+	first := &equalsByName{"a"}
+	if ObjectHashCode(first) != ObjectHashCode(first) {
+		t.Error("Expected an object to always have the same hash")
+	}
+	if hash := Hash(int32(1), "a"); hash != 31*(31+1)+97 {
+		t.Errorf("Expected the hashes to be combined, got %d", hash)
+	}
+}
@@ -0,0 +1,55 @@
+package stdjava
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Parallel runs the stages of a parallel stream for each of its elements, in
+// goroutines that are spread over the CPUs, the same way as Java splits the
+// stream over the threads of its pool. The stages of an element return the
+// function that collects it, or nil if it is filtered out, and these are called
+// one at a time, in the order of the elements, once every stage has finished,
+// so that the result is the same as the result of the sequential stream
+//
+// A panic in the stages of any element is raised again by Parallel, after the
+// rest of the goroutines have stopped, the same as Java throws the exception
+// of a stage from the operation that ends the stream
+func Parallel[T any](elements []T, stages func(T) func()) {
+	collected := make([]func(), len(elements))
+
+	var (
+		wg      sync.WaitGroup
+		next    atomic.Int64
+		failure atomic.Pointer[any]
+	)
+	for range min(runtime.GOMAXPROCS(0), len(elements)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					failure.CompareAndSwap(nil, &recovered)
+				}
+			}()
+			for failure.Load() == nil {
+				ind := next.Add(1) - 1
+				if ind >= int64(len(elements)) {
+					return
+				}
+				collected[ind] = stages(elements[ind])
+			}
+		}()
+	}
+	wg.Wait()
+
+	if recovered := failure.Load(); recovered != nil {
+		panic(*recovered)
+	}
+	for _, collect := range collected {
+		if collect != nil {
+			collect()
+		}
+	}
+}
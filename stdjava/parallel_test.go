@@ -0,0 +1,45 @@
+package stdjava
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParallel(t *testing.T) {
+	numbers := make([]int32, 1000)
+	for ind := range numbers {
+		numbers[ind] = int32(ind)
+	}
+
+	var evens []int32
+	Parallel(numbers, func(n int32) func() {
+		if n%2 != 0 {
+			return nil
+		}
+		squared := n * n
+		return func() {
+			evens = append(evens, squared)
+		}
+	})
+	if len(evens) != 500 || evens[1] != 4 || !slices.IsSorted(evens) {
+		t.Errorf("Expected the squares of the even numbers in order, got %v", evens)
+	}
+
+	Parallel([]int32(nil), func(n int32) func() {
+		t.Errorf("Expected no stages for an empty stream")
+		return nil
+	})
+
+	defer func() {
+		if recovered := recover(); recovered != "stage" {
+			t.Errorf("Expected the panic of a stage to be raised again, got %v", recovered)
+		}
+	}()
+	Parallel(numbers, func(n int32) func() {
+		if n == 500 {
+			panic("stage")
+		}
+		return nil
+	})
+	t.Errorf("Expected the stream to panic")
+}
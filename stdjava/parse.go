@@ -0,0 +1,72 @@
+package stdjava
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The functions here implement the methods of the wrapper classes that parse a
+// string, which panic with a NumberFormatException, the same as Java, when the
+// string isn't a number, or is out of the range of the parsed type
+
+// numberFormatException creates the exception that Java throws for a string
+// that can't be parsed
+func numberFormatException(s string, radix int32) *JavaException {
+	message := fmt.Sprintf("For input string: %q", s)
+	if radix != 10 {
+		message += fmt.Sprintf(" under radix %d", radix)
+	}
+	return NewException("NumberFormatException", message, nil)
+}
+
+// ParseInt is an implementation of `Integer.parseInt`, which parses a string
+// in the given radix into an int
+func ParseInt(s string, radix int32) int32 {
+	value, err := strconv.ParseInt(s, int(radix), 32)
+	if err != nil {
+		panic(numberFormatException(s, radix))
+	}
+	return int32(value)
+}
+
+// ParseLong is an implementation of `Long.parseLong`, which parses a string in
+// the given radix into a long
+func ParseLong(s string, radix int32) int64 {
+	value, err := strconv.ParseInt(s, int(radix), 64)
+	if err != nil {
+		panic(numberFormatException(s, radix))
+	}
+	return value
+}
+
+// parseFloatingPoint parses a string the way that Java does, which ignores
+// the whitespace around it, and a suffix for its type, but only accepts the
+// special values by their exact names
+func parseFloatingPoint(s string, bitSize int) float64 {
+	trimmed := strings.TrimSpace(s)
+	unsigned := strings.TrimLeft(trimmed, "+-")
+	if strings.ContainsAny(unsigned, "iInN") && unsigned != "Infinity" && unsigned != "NaN" {
+		panic(numberFormatException(s, 10))
+	}
+	if !strings.HasPrefix(strings.ToLower(unsigned), "0x") {
+		trimmed = strings.TrimRight(trimmed, "dDfF")
+	}
+	value, err := strconv.ParseFloat(trimmed, bitSize)
+	// A number that is too large is infinite in Java as well
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		panic(numberFormatException(s, 10))
+	}
+	return value
+}
+
+// ParseDouble is an implementation of `Double.parseDouble`
+func ParseDouble(s string) float64 {
+	return parseFloatingPoint(s, 64)
+}
+
+// ParseFloat is an implementation of `Float.parseFloat`
+func ParseFloat(s string) float32 {
+	return float32(parseFloatingPoint(s, 32))
+}
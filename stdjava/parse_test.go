@@ -0,0 +1,57 @@
+package stdjava
+
+import (
+	"math"
+	"testing"
+)
+
+// expectNumberFormatException fails the test unless the function panics with a
+// NumberFormatException
+func expectNumberFormatException(t *testing.T, input string, parse func()) {
+	t.Helper()
+	defer func() {
+		thrown, ok := recover().(*JavaException)
+		if !ok || thrown.Class != "NumberFormatException" {
+			t.Errorf("Expected parsing %q to throw a NumberFormatException, got %v", input, thrown)
+		}
+	}()
+	parse()
+}
+
+func TestParseInt(t *testing.T) {
+	if value := ParseInt("-42", 10); value != -42 {
+		t.Errorf("Expected -42, got %d", value)
+	}
+	if value := ParseInt("ff", 16); value != 255 {
+		t.Errorf("Expected 255, got %d", value)
+	}
+	for _, input := range []string{"abc", "3000000000", "", " 1", "1_000"} {
+		expectNumberFormatException(t, input, func() { ParseInt(input, 10) })
+	}
+}
+
+func TestParseLong(t *testing.T) {
+	if value := ParseLong("3000000000", 10); value != 3000000000 {
+		t.Errorf("Expected 3000000000, got %d", value)
+	}
+	expectNumberFormatException(t, "9223372036854775808", func() { ParseLong("9223372036854775808", 10) })
+}
+
+func TestParseDouble(t *testing.T) {
+	for input, expected := range map[string]float64{
+		" 2.5 ":     2.5,
+		"1e3d":      1000,
+		"-Infinity": math.Inf(-1),
+		"1e400":     math.Inf(1),
+	} {
+		if value := ParseDouble(input); value != expected {
+			t.Errorf("Expected %q to be %v, got %v", input, expected, value)
+		}
+	}
+	if value := ParseDouble("NaN"); !math.IsNaN(value) {
+		t.Errorf("Expected NaN, got %v", value)
+	}
+	for _, input := range []string{"abc", "inf", "nan", ""} {
+		expectNumberFormatException(t, input, func() { ParseDouble(input) })
+	}
+}
@@ -0,0 +1,218 @@
+package stdjava
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Properties is an implementation of Java's `Properties`, which are the keys
+// and values of a `.properties` file
+type Properties struct {
+	values map[string]string
+}
+
+// NewProperties creates an empty set of properties
+func NewProperties() *Properties {
+	return &Properties{values: make(map[string]string)}
+}
+
+// GetProperty is an implementation of `getProperty`, which returns the value
+// of a key, or the default value if the key isn't set
+func (p *Properties) GetProperty(key string, defaultValue ...string) string {
+	if value, ok := p.values[key]; ok {
+		return value
+	}
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return ""
+}
+
+// SetProperty is an implementation of `setProperty`, which sets the value of a key
+func (p *Properties) SetProperty(key, value string) {
+	p.values[key] = value
+}
+
+// StringPropertyNames is an implementation of `stringPropertyNames`, which
+// returns every key that is set, in order
+func (p *Properties) StringPropertyNames() []string {
+	keys := make([]string, 0, len(p.values))
+	for key := range p.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Load is an implementation of `load`, which reads the keys and values from
+// the contents of a `.properties` file
+//
+// Every line sets a key to a value, separated by a `=`, a `:`, or whitespace,
+// and lines that start with a `#` or a `!` are comments. A line that ends with
+// a backslash continues on the next line
+func (p *Properties) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	var logical strings.Builder
+	continued := false
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if !continued && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+
+		// An odd number of backslashes at the end of a line escapes the newline
+		trailing := len(line) - len(strings.TrimRight(line, `\`))
+		continued = trailing%2 == 1
+		if continued {
+			line = line[:len(line)-1]
+		}
+		logical.WriteString(line)
+		if continued {
+			continue
+		}
+
+		key, value := splitProperty(logical.String())
+		p.values[unescapeProperty(key)] = unescapeProperty(value)
+		logical.Reset()
+	}
+	if logical.Len() > 0 {
+		key, value := splitProperty(logical.String())
+		p.values[unescapeProperty(key)] = unescapeProperty(value)
+	}
+	return scanner.Err()
+}
+
+// splitProperty splits a line into its key and its value, which are still escaped
+func splitProperty(line string) (string, string) {
+	end := len(line)
+	for index := 0; index < len(line); index++ {
+		if line[index] == '\\' {
+			index++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[index]) >= 0 {
+			end = index
+			break
+		}
+	}
+	key, rest := line[:end], strings.TrimLeft(line[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	return key, rest
+}
+
+// unescapeProperty replaces the escape sequences in a key or a value, such as
+// `\n` and `\u00e9`
+func unescapeProperty(escaped string) string {
+	if !strings.Contains(escaped, `\`) {
+		return escaped
+	}
+
+	// A `\u` escape is a UTF-16 code unit, so characters outside of the BMP are
+	// written as two of them
+	var chars []rune
+	runes := []rune(escaped)
+	for index := 0; index < len(runes); index++ {
+		char := runes[index]
+		if char == '\\' && index+1 < len(runes) {
+			index++
+			switch char = runes[index]; char {
+			case 't':
+				char = '\t'
+			case 'n':
+				char = '\n'
+			case 'r':
+				char = '\r'
+			case 'f':
+				char = '\f'
+			case 'u':
+				if index+5 <= len(runes) {
+					if unit, err := strconv.ParseUint(string(runes[index+1:index+5]), 16, 16); err == nil {
+						chars = append(chars, rune(unit))
+						index += 4
+						continue
+					}
+				}
+			}
+		}
+		chars = append(chars, ToCharArray(string(char))...)
+	}
+	return StringFromChars(chars)
+}
+
+// Resources are the files that resource bundles are loaded from, like the
+// classpath of Java. By default, these are the files in the working directory
+var Resources fs.FS = os.DirFS(".")
+
+// ResourceBundle is an implementation of the `ResourceBundle` that Java loads
+// from `.properties` files
+type ResourceBundle struct {
+	properties *Properties
+	// The bundle of a less specific locale, which has the keys that this one doesn't
+	parent *ResourceBundle
+}
+
+// GetBundle is an implementation of `ResourceBundle.getBundle`, which loads
+// the properties for a locale, such as `messages_fr_FR.properties`, falling
+// back to the less specific `messages_fr.properties` and `messages.properties`
+//
+// The base name is a path in `Resources`, where a `.` separates directories,
+// and this panics with a `MissingResourceException` if there is no bundle
+func GetBundle(baseName string, locale Locale) *ResourceBundle {
+	name := strings.ReplaceAll(baseName, ".", "/")
+	candidates := []string{name}
+	if locale.Language != "" {
+		candidates = append(candidates, name+"_"+locale.Language)
+		if locale.Country != "" {
+			candidates = append(candidates, name+"_"+locale.Language+"_"+locale.Country)
+		}
+	}
+
+	var bundle *ResourceBundle
+	for _, candidate := range candidates {
+		file, err := Resources.Open(path.Clean(candidate) + ".properties")
+		if err != nil {
+			continue
+		}
+		properties := NewProperties()
+		err = properties.Load(file)
+		file.Close()
+		if err == nil {
+			bundle = &ResourceBundle{properties: properties, parent: bundle}
+		}
+	}
+	if bundle == nil {
+		panic(NewException("MissingResourceException", fmt.Sprintf("Can't find bundle for base name %s, locale %s", baseName, locale), nil))
+	}
+	return bundle
+}
+
+// GetString is an implementation of `getString`, which returns the value of a
+// key in the bundle, and panics with a `MissingResourceException` if no value
+// is set for the key
+func (b *ResourceBundle) GetString(key string) string {
+	for bundle := b; bundle != nil; bundle = bundle.parent {
+		if value, ok := bundle.properties.values[key]; ok {
+			return value
+		}
+	}
+	panic(NewException("MissingResourceException", "Can't find resource for bundle, key "+key, nil))
+}
+
+// ContainsKey is an implementation of `containsKey`
+func (b *ResourceBundle) ContainsKey(key string) bool {
+	for bundle := b; bundle != nil; bundle = bundle.parent {
+		if _, ok := bundle.properties.values[key]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package stdjava
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPropertiesLoad(t *testing.T) {
+	contents := `# A comment
+! Another comment
+greeting = Hello, World
+name:Duke
+empty
+spaced   value with spaces
+escaped\ key=tab\there
+unicode=café 😀
+escapedUnicode=caf\u00e9 \uD83D\uDE00
+multi = first, \
+        second
+trailing\\
+other=value
+`
+	properties := NewProperties()
+	if err := properties.Load(strings.NewReader(contents)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"greeting":       "Hello, World",
+		"name":           "Duke",
+		"empty":          "",
+		"spaced":         "value with spaces",
+		"escaped key":    "tab\there",
+		"unicode":        "café 😀",
+		"escapedUnicode": "café 😀",
+		"multi":          "first, second",
+		"trailing\\":     "",
+		"other":          "value",
+	}
+	for key, value := range expected {
+		if got := properties.GetProperty(key, "missing"); got != value {
+			t.Errorf("Expected %q to be %q, got %q", key, value, got)
+		}
+	}
+	if names := properties.StringPropertyNames(); len(names) != len(expected) {
+		t.Errorf("Expected %d keys, got %v", len(expected), names)
+	}
+	if got := properties.GetProperty("unknown", "default"); got != "default" {
+		t.Errorf("Expected an unknown key to have the default value, got %q", got)
+	}
+}
+
+func TestGetBundle(t *testing.T) {
+	previous := Resources
+	defer func() { Resources = previous }()
+	Resources = fstest.MapFS{
+		"i18n/messages.properties":       {Data: []byte("hello=Hello\nbye=Goodbye\n")},
+		"i18n/messages_fr.properties":    {Data: []byte("hello=Bonjour\n")},
+		"i18n/messages_fr_CA.properties": {Data: []byte("bye=Bye\n")},
+	}
+
+	bundle := GetBundle("i18n.messages", NewLocale("fr", "CA"))
+	if hello, bye := bundle.GetString("hello"), bundle.GetString("bye"); hello != "Bonjour" || bye != "Bye" {
+		t.Errorf("Expected the French Canadian messages, got %q and %q", hello, bye)
+	}
+	if hello := GetBundle("i18n.messages", LocaleGerman).GetString("hello"); hello != "Hello" {
+		t.Errorf("Expected the default messages for German, got %q", hello)
+	}
+
+	defer func() {
+		if exception, ok := recover().(*JavaException); !ok || !strings.Contains(exception.Error(), "MissingResourceException") {
+			t.Errorf("Expected a MissingResourceException for a missing bundle, got %v", exception)
+		}
+	}()
+	GetBundle("i18n.missing", LocaleUS)
+}
@@ -15,3 +15,9 @@ func TestRightShiftNegative(t *testing.T) {
 		t.Errorf("Shifted -9 >>> 2. Expected 1073741821 but got %d", UnsignedRightShift(-9, 2))
 	}
 }
+
+func TestRightShiftLong(t *testing.T) {
+	if UnsignedRightShift(int64(-9), 2) != 4611686018427387901 {
+		t.Errorf("Shifted -9L >>> 2. Expected 4611686018427387901 but got %d", UnsignedRightShift(int64(-9), 2))
+	}
+}
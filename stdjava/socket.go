@@ -0,0 +1,109 @@
+package stdjava
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// ServerSocket is an implementation of `java.net.ServerSocket`, which listens
+// for TCP connections with a Go `net.Listener`
+type ServerSocket struct {
+	Listener net.Listener
+	closed   bool
+}
+
+// NewServerSocket listens on a port of every interface, the same way as
+// `new ServerSocket(port)`, and panics with an `IOException` if it can't
+func NewServerSocket(port int32) *ServerSocket {
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(int(port)))
+	if err != nil {
+		panic(NewException("IOException", err.Error(), nil))
+	}
+	return &ServerSocket{Listener: listener}
+}
+
+// Accept waits for the next connection, and panics with an `IOException` if
+// the server socket was closed
+func (s *ServerSocket) Accept() *Socket {
+	conn, err := s.Listener.Accept()
+	if err != nil {
+		panic(NewException("IOException", err.Error(), nil))
+	}
+	return &Socket{Conn: conn}
+}
+
+// GetLocalPort returns the port that the server socket listens on
+func (s *ServerSocket) GetLocalPort() int32 {
+	return int32(s.Listener.Addr().(*net.TCPAddr).Port)
+}
+
+// Close stops listening for connections
+func (s *ServerSocket) Close() {
+	s.closed = true
+	s.Listener.Close()
+}
+
+// IsClosed determines if the server socket has been closed
+func (s *ServerSocket) IsClosed() bool {
+	return s.closed
+}
+
+// Socket is an implementation of `java.net.Socket`, for a TCP connection with
+// a Go `net.Conn`
+type Socket struct {
+	Conn   net.Conn
+	closed bool
+}
+
+// NewSocket connects to a port of a host, the same way as
+// `new Socket(host, port)`, and panics with an `IOException` if it can't
+func NewSocket(host string, port int32) *Socket {
+	conn, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+	if err != nil {
+		panic(NewException("IOException", err.Error(), nil))
+	}
+	return &Socket{Conn: conn}
+}
+
+// GetInputStream returns the stream that the connection is read from
+func (s *Socket) GetInputStream() io.Reader {
+	return s.Conn
+}
+
+// GetOutputStream returns the stream that the connection is written to
+func (s *Socket) GetOutputStream() io.Writer {
+	return s.Conn
+}
+
+// SetSoTimeout sets how long a read from the connection can block for, in
+// milliseconds, where zero never times out
+func (s *Socket) SetSoTimeout(millis int32) {
+	var deadline time.Time
+	if millis > 0 {
+		deadline = time.Now().Add(time.Duration(millis) * time.Millisecond)
+	}
+	s.Conn.SetReadDeadline(deadline)
+}
+
+// GetPort returns the remote port of the connection
+func (s *Socket) GetPort() int32 {
+	return int32(s.Conn.RemoteAddr().(*net.TCPAddr).Port)
+}
+
+// GetLocalPort returns the local port of the connection
+func (s *Socket) GetLocalPort() int32 {
+	return int32(s.Conn.LocalAddr().(*net.TCPAddr).Port)
+}
+
+// Close closes the connection
+func (s *Socket) Close() {
+	s.closed = true
+	s.Conn.Close()
+}
+
+// IsClosed determines if the socket has been closed
+func (s *Socket) IsClosed() bool {
+	return s.closed
+}
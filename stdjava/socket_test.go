@@ -0,0 +1,31 @@
+package stdjava
+
+import "testing"
+
+func TestSockets(t *testing.T) {
+	server := NewServerSocket(0)
+	defer server.Close()
+
+	go func() {
+		client := server.Accept()
+		defer client.Close()
+		in := NewBufferedReader(client.GetInputStream())
+		out := NewPrintWriter(client.GetOutputStream(), true)
+		for in.Scan() {
+			out.Println("echo " + in.Text())
+		}
+	}()
+
+	client := NewSocket("localhost", server.GetLocalPort())
+	out := NewPrintWriter(client.GetOutputStream())
+	out.Println("hello")
+	out.Flush()
+	in := NewBufferedReader(client.GetInputStream())
+	if line := in.ReadLine(); line != "echo hello" {
+		t.Errorf("Expected the line to be echoed, got %q", line)
+	}
+	client.Close()
+	if !client.IsClosed() {
+		t.Errorf("Expected the socket to be closed")
+	}
+}
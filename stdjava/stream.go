@@ -0,0 +1,130 @@
+package stdjava
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Java's byte streams are Go's `io.Reader` and `io.Writer`, and the readers and
+// writers that decode and encode characters are the same streams, since Go's
+// strings are already UTF-8
+
+// Read is an implementation of `InputStream.read(byte[])`, which returns how
+// many bytes were read, or -1 at the end of the stream, and panics with an
+// `IOException` if the read fails
+func Read(in io.Reader, buffer []byte) int32 {
+	read, err := in.Read(buffer)
+	if read == 0 && errors.Is(err, io.EOF) {
+		return -1
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		panic(NewException("IOException", err.Error(), nil))
+	}
+	return int32(read)
+}
+
+// Write is an implementation of `OutputStream.write(byte[])`, which panics
+// with an `IOException` if the write fails
+func Write(out io.Writer, data []byte) {
+	if _, err := out.Write(data); err != nil {
+		panic(NewException("IOException", err.Error(), nil))
+	}
+}
+
+// Flush is an implementation of `flush`, for a stream that may be buffered
+func Flush(out io.Writer) {
+	if flusher, ok := out.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+}
+
+// Close is an implementation of `close`, for a stream that may need to be
+// closed
+func Close(stream any) {
+	if closer, ok := stream.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+// BufferedReader is an implementation of `java.io.BufferedReader`, which reads
+// a stream line by line with a `bufio.Scanner`
+type BufferedReader struct {
+	*bufio.Scanner
+	in io.Reader
+}
+
+// NewBufferedReader creates a reader for the lines of a stream
+func NewBufferedReader(in io.Reader) *BufferedReader {
+	return &BufferedReader{Scanner: bufio.NewScanner(in), in: in}
+}
+
+// ReadLine is an implementation of `readLine`, which returns the next line of
+// the stream without its line terminator
+//
+// Java returns null at the end of the stream, which a Go string can't be, so an
+// empty string is returned instead. Use `Scan` and `Text` to tell the end of
+// the stream apart from an empty line
+func (r *BufferedReader) ReadLine() string {
+	if !r.Scan() {
+		if err := r.Err(); err != nil {
+			panic(NewException("IOException", err.Error(), nil))
+		}
+		return ""
+	}
+	return r.Text()
+}
+
+// Close closes the stream that is being read
+func (r *BufferedReader) Close() {
+	Close(r.in)
+}
+
+// PrintWriter is an implementation of `java.io.PrintWriter`, which buffers what
+// is written to a stream until it is flushed
+type PrintWriter struct {
+	out       *bufio.Writer
+	stream    io.Writer
+	autoFlush bool
+}
+
+// NewPrintWriter creates a writer for a stream, which flushes every line that
+// is printed if `autoFlush` is set, the same way as Java
+func NewPrintWriter(out io.Writer, autoFlush ...bool) *PrintWriter {
+	return &PrintWriter{out: bufio.NewWriter(out), stream: out, autoFlush: len(autoFlush) > 0 && autoFlush[0]}
+}
+
+// Print writes a value, formatted the same way as `fmt.Print`
+func (w *PrintWriter) Print(value any) {
+	fmt.Fprint(w.out, value)
+}
+
+// Println writes a value followed by a line terminator, or only the line
+// terminator if there is no value
+func (w *PrintWriter) Println(value ...any) {
+	fmt.Fprintln(w.out, value...)
+	if w.autoFlush {
+		w.Flush()
+	}
+}
+
+// Printf writes a formatted string, and flushes it if `autoFlush` is set
+func (w *PrintWriter) Printf(format string, args ...any) *PrintWriter {
+	fmt.Fprintf(w.out, format, args...)
+	if w.autoFlush {
+		w.Flush()
+	}
+	return w
+}
+
+// Flush writes what has been buffered to the stream
+func (w *PrintWriter) Flush() {
+	w.out.Flush()
+}
+
+// Close flushes the writer, and closes its stream
+func (w *PrintWriter) Close() {
+	w.Flush()
+	Close(w.stream)
+}
@@ -0,0 +1,42 @@
+package stdjava
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStreams(t *testing.T) {
+	buffer := make([]byte, 4)
+	in := strings.NewReader("abcdef")
+	if read := Read(in, buffer); read != 4 || string(buffer) != "abcd" {
+		t.Errorf("Expected four bytes to be read, got %d", read)
+	}
+	Read(in, buffer)
+	if read := Read(in, buffer); read != -1 {
+		t.Errorf("Expected -1 at the end of the stream, got %d", read)
+	}
+
+	var out bytes.Buffer
+	writer := NewPrintWriter(&out)
+	writer.Print(1)
+	writer.Println(" line")
+	if out.Len() != 0 {
+		t.Errorf("Expected the writer to be buffered, got %q", out.String())
+	}
+	writer.Close()
+	if out.String() != "1 line\n" {
+		t.Errorf("Expected the printed line, got %q", out.String())
+	}
+
+	reader := NewBufferedReader(strings.NewReader("first\nsecond"))
+	if line := reader.ReadLine(); line != "first" {
+		t.Errorf("Expected the first line, got %q", line)
+	}
+	if line := reader.ReadLine(); line != "second" {
+		t.Errorf("Expected the last line without a terminator, got %q", line)
+	}
+	if line := reader.ReadLine(); line != "" {
+		t.Errorf("Expected an empty string at the end of the stream, got %q", line)
+	}
+}
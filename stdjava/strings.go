@@ -0,0 +1,126 @@
+package stdjava
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// Java's strings are sequences of UTF-16 code units, so a character outside of
+// the Basic Multilingual Plane, such as an emoji, takes up two `char`s, while
+// Go's strings are UTF-8, and are indexed by byte, or by rune once converted
+//
+// The functions here index strings the same way that Java does, and represent
+// each `char` as a rune containing a single UTF-16 code unit, which means that
+// surrogate pairs are split into two runes
+
+// ToCharArray is an implementation of Java's String `toCharArray`, splitting a
+// string into its UTF-16 code units
+func ToCharArray(s string) []rune {
+	units := utf16.Encode([]rune(s))
+	chars := make([]rune, len(units))
+	for ind, unit := range units {
+		chars[ind] = rune(unit)
+	}
+	return chars
+}
+
+// StringFromChars is an implementation of Java's `new String(char[])`, joining
+// UTF-16 code units back into a string
+func StringFromChars(chars []rune) string {
+	units := make([]uint16, len(chars))
+	for ind, char := range chars {
+		units[ind] = uint16(char)
+	}
+	return string(utf16.Decode(units))
+}
+
+// Length is an implementation of Java's String `length`, which counts the
+// UTF-16 code units of the string
+func Length(s string) int32 {
+	var length int32
+	for _, char := range s {
+		length += int32(utf16.RuneLen(char))
+	}
+	return length
+}
+
+// CharAt is an implementation of Java's String `charAt`, which returns the
+// UTF-16 code unit at the given index
+//
+// Just like Java, this panics if the index is out of bounds
+func CharAt(s string, index int32) rune {
+	var position int32
+	for _, char := range s {
+		if utf16.RuneLen(char) == 2 {
+			first, second := utf16.EncodeRune(char)
+			if position == index {
+				return first
+			} else if position+1 == index {
+				return second
+			}
+			position += 2
+			continue
+		}
+		if position == index {
+			return char
+		}
+		position++
+	}
+	panic(NewException("StringIndexOutOfBoundsException", fmt.Sprintf("index %d, length %d", index, position), nil))
+}
+
+// Reverse is an implementation of StringBuilder's `reverse`, which reverses the
+// characters of a string, while keeping surrogate pairs in the same order
+func Reverse(s string) string {
+	chars := []rune(s)
+	for left, right := 0, len(chars)-1; left < right; left, right = left+1, right-1 {
+		chars[left], chars[right] = chars[right], chars[left]
+	}
+	return string(chars)
+}
+
+// CompareToIgnoreCase is an implementation of Java's String
+// `compareToIgnoreCase`, which compares two strings by their UTF-16 code units,
+// after converting both to the same case
+func CompareToIgnoreCase(a, b string) int32 {
+	first, second := ToCharArray(a), ToCharArray(b)
+	for ind := 0; ind < len(first) && ind < len(second); ind++ {
+		if difference := compareCharIgnoreCase(first[ind], second[ind]); difference != 0 {
+			return difference
+		}
+	}
+	return int32(len(first) - len(second))
+}
+
+func compareCharIgnoreCase(a, b rune) int32 {
+	if a == b {
+		return 0
+	}
+	a, b = unicode.ToUpper(a), unicode.ToUpper(b)
+	if a == b {
+		return 0
+	}
+	return unicode.ToLower(a) - unicode.ToLower(b)
+}
+
+// EqualsIgnoreCase is an implementation of Java's String `equalsIgnoreCase`
+func EqualsIgnoreCase(a, b string) bool {
+	return Length(a) == Length(b) && CompareToIgnoreCase(a, b) == 0
+}
+
+// ToLowerCase is an implementation of Java's String `toLowerCase`
+//
+// Java converts the case of a string using the rules of a locale, but the
+// rules are the same for almost every locale, so they are ignored
+func ToLowerCase(s string) string {
+	return strings.ToLower(s)
+}
+
+// ToUpperCase is an implementation of Java's String `toUpperCase`
+//
+// Just like `ToLowerCase`, the rules of the locale are ignored
+func ToUpperCase(s string) string {
+	return strings.ToUpper(s)
+}
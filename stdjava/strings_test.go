@@ -0,0 +1,64 @@
+package stdjava
+
+import "testing"
+
+func TestCharArraySurrogatePairs(t *testing.T) {
+	s := "a😀b"
+	chars := ToCharArray(s)
+	if len(chars) != 4 {
+		t.Fatalf("Expected the emoji to take up two chars, got %d chars", len(chars))
+	}
+	if chars[1] != 0xD83D || chars[2] != 0xDE00 {
+		t.Errorf("Expected the emoji to be split into surrogates, got %X %X", chars[1], chars[2])
+	}
+	if StringFromChars(chars) != s {
+		t.Errorf("Expected the chars to join back into %q, got %q", s, StringFromChars(chars))
+	}
+}
+
+func TestLengthAndCharAt(t *testing.T) {
+	s := "é😀!"
+	if Length(s) != 4 {
+		t.Errorf("Expected a length of 4 UTF-16 code units, got %d", Length(s))
+	}
+	expected := []rune{'é', 0xD83D, 0xDE00, '!'}
+	for ind, char := range expected {
+		if CharAt(s, int32(ind)) != char {
+			t.Errorf("Expected char %d to be %X, got %X", ind, char, CharAt(s, int32(ind)))
+		}
+	}
+}
+
+func TestCharAtOutOfBounds(t *testing.T) {
+	defer func() {
+		thrown, ok := recover().(*JavaException)
+		if !ok || thrown.Class != "StringIndexOutOfBoundsException" {
+			t.Errorf("Expected a StringIndexOutOfBoundsException, got %v", thrown)
+		}
+	}()
+	CharAt("ab", 2)
+}
+
+func TestReverse(t *testing.T) {
+	if Reverse("ab😀c") != "c😀ba" {
+		t.Errorf("Expected the surrogate pair to stay together, got %q", Reverse("ab😀c"))
+	}
+}
+
+func TestIgnoreCaseComparisons(t *testing.T) {
+	if !EqualsIgnoreCase("Hello", "hELLO") {
+		t.Errorf("Expected strings with different cases to be equal")
+	}
+	if EqualsIgnoreCase("Hello", "Hell") {
+		t.Errorf("Expected strings with different lengths to not be equal")
+	}
+	if CompareToIgnoreCase("apple", "BANANA") >= 0 {
+		t.Errorf("Expected apple to come before banana")
+	}
+	if CompareToIgnoreCase("abc", "ABCD") != -1 {
+		t.Errorf("Expected the difference in lengths, got %d", CompareToIgnoreCase("abc", "ABCD"))
+	}
+	if CompareToIgnoreCase("b", "A") != 1 {
+		t.Errorf("Expected the difference between the characters, got %d", CompareToIgnoreCase("b", "A"))
+	}
+}
@@ -0,0 +1,529 @@
+package stdjava
+
+import (
+	"cmp"
+	"slices"
+)
+
+// sortedKeys is a list of keys that is kept in the order of a comparison
+// function, which both sorted collections are built on
+//
+// The keys are kept in a slice, which makes adding and removing a key take
+// linear time, but finding one, and iterating in order, as fast as possible
+type sortedKeys[K any] struct {
+	keys    []K
+	compare func(a, b K) int
+}
+
+// search returns where a key is, or where it would be added, and whether the
+// key is there
+func (s *sortedKeys[K]) search(key K) (int, bool) {
+	return slices.BinarySearchFunc(s.keys, key, s.compare)
+}
+
+// insert adds a key at the given index
+func (s *sortedKeys[K]) insert(index int, key K) {
+	s.keys = slices.Insert(s.keys, index, key)
+}
+
+// remove removes the key at the given index
+func (s *sortedKeys[K]) remove(index int) {
+	s.keys = slices.Delete(s.keys, index, index+1)
+}
+
+// The indices of the neighbors of a key, which are -1 if there is no such key
+func (s *sortedKeys[K]) floor(key K) int {
+	index, found := s.search(key)
+	if found {
+		return index
+	}
+	return index - 1
+}
+
+func (s *sortedKeys[K]) ceiling(key K) int {
+	index, _ := s.search(key)
+	if index == len(s.keys) {
+		return -1
+	}
+	return index
+}
+
+func (s *sortedKeys[K]) lower(key K) int {
+	index, _ := s.search(key)
+	return index - 1
+}
+
+func (s *sortedKeys[K]) higher(key K) int {
+	index, found := s.search(key)
+	if found {
+		index++
+	}
+	if index == len(s.keys) {
+		return -1
+	}
+	return index
+}
+
+// between returns the range of the indices of the keys from one key to
+// another, which includes either of the keys if the given flags are set
+func (s *sortedKeys[K]) between(from K, fromInclusive bool, to K, toInclusive bool) (int, int) {
+	low, found := s.search(from)
+	if found && !fromInclusive {
+		low++
+	}
+	high, found := s.search(to)
+	if found && toInclusive {
+		high++
+	}
+	return low, max(low, high)
+}
+
+// keyAt returns a pointer to a copy of the key at the given index, or nil if
+// the index is -1, the same as Java returns null when there is no such key
+func (s *sortedKeys[K]) keyAt(index int) *K {
+	if index < 0 {
+		return nil
+	}
+	key := s.keys[index]
+	return &key
+}
+
+// noSuchElement panics with the exception that Java throws for the first or
+// the last key of an empty collection
+func noSuchElement() {
+	panic(NewException("NoSuchElementException", "", nil))
+}
+
+// Comparison returns the comparison function of a `Comparator`, which
+// compares two values by returning a negative number, zero, or a positive
+// number, the same way as Java's `compare`
+type Comparison[T any] func(a, b T) int32
+
+// ReverseOrder is an implementation of `Comparator.reverseOrder` and
+// `Collections.reverseOrder`
+func ReverseOrder[T cmp.Ordered](a, b T) int32 {
+	return int32(cmp.Compare(b, a))
+}
+
+// TreeMap is an implementation of `java.util.TreeMap`, which keeps its keys
+// sorted in their natural order, or the order of a comparator
+//
+// The methods that return a key or an entry that may not exist, such as
+// `ceilingKey` or `firstEntry`, return nil if there is no such key, the same
+// as Java returns null. The views of a part of the map, such as `headMap`, are
+// copies of that part of the map, instead of views that change along with it
+type TreeMap[K, V any] struct {
+	sortedKeys[K]
+	values []V
+}
+
+// NewTreeMap is an implementation of `new TreeMap<>()`, for keys that are
+// sorted in their natural order
+func NewTreeMap[K cmp.Ordered, V any]() *TreeMap[K, V] {
+	return &TreeMap[K, V]{sortedKeys: sortedKeys[K]{compare: cmp.Compare[K]}}
+}
+
+// NewTreeMapFunc is an implementation of `new TreeMap<>(comparator)`
+func NewTreeMapFunc[K, V any](comparator Comparison[K]) *TreeMap[K, V] {
+	return &TreeMap[K, V]{sortedKeys: sortedKeys[K]{compare: func(a, b K) int { return int(comparator(a, b)) }}}
+}
+
+// Put associates a value with a key, and returns the value that was
+// associated with it before, or the zero value if there was none
+func (m *TreeMap[K, V]) Put(key K, value V) V {
+	index, found := m.search(key)
+	if found {
+		previous := m.values[index]
+		m.values[index] = value
+		return previous
+	}
+	m.insert(index, key)
+	m.values = slices.Insert(m.values, index, value)
+	var zero V
+	return zero
+}
+
+// Get returns the value that is associated with a key, or the zero value if
+// there is none
+func (m *TreeMap[K, V]) Get(key K) V {
+	return m.GetOrDefault(key, *new(V))
+}
+
+// GetOrDefault returns the value that is associated with a key, or the given
+// value if there is none
+func (m *TreeMap[K, V]) GetOrDefault(key K, defaultValue V) V {
+	if index, found := m.search(key); found {
+		return m.values[index]
+	}
+	return defaultValue
+}
+
+// ContainsKey determines if a value is associated with a key
+func (m *TreeMap[K, V]) ContainsKey(key K) bool {
+	_, found := m.search(key)
+	return found
+}
+
+// Remove removes a key, and returns the value that was associated with it, or
+// the zero value if there was none
+func (m *TreeMap[K, V]) Remove(key K) V {
+	index, found := m.search(key)
+	if !found {
+		var zero V
+		return zero
+	}
+	value := m.values[index]
+	m.remove(index)
+	m.values = slices.Delete(m.values, index, index+1)
+	return value
+}
+
+// Size returns the number of keys in the map
+func (m *TreeMap[K, V]) Size() int32 {
+	return int32(len(m.keys))
+}
+
+// IsEmpty determines if the map has no keys
+func (m *TreeMap[K, V]) IsEmpty() bool {
+	return len(m.keys) == 0
+}
+
+// Clear removes every key from the map
+func (m *TreeMap[K, V]) Clear() {
+	m.keys, m.values = nil, nil
+}
+
+// FirstKey returns the smallest key, and panics with a
+// `NoSuchElementException` if the map is empty
+func (m *TreeMap[K, V]) FirstKey() K {
+	if len(m.keys) == 0 {
+		noSuchElement()
+	}
+	return m.keys[0]
+}
+
+// LastKey returns the largest key, and panics with a `NoSuchElementException`
+// if the map is empty
+func (m *TreeMap[K, V]) LastKey() K {
+	if len(m.keys) == 0 {
+		noSuchElement()
+	}
+	return m.keys[len(m.keys)-1]
+}
+
+// FloorKey returns the largest key that is less than or equal to the given key
+func (m *TreeMap[K, V]) FloorKey(key K) *K {
+	return m.keyAt(m.floor(key))
+}
+
+// CeilingKey returns the smallest key that is greater than or equal to the
+// given key
+func (m *TreeMap[K, V]) CeilingKey(key K) *K {
+	return m.keyAt(m.ceiling(key))
+}
+
+// LowerKey returns the largest key that is less than the given key
+func (m *TreeMap[K, V]) LowerKey(key K) *K {
+	return m.keyAt(m.lower(key))
+}
+
+// HigherKey returns the smallest key that is greater than the given key
+func (m *TreeMap[K, V]) HigherKey(key K) *K {
+	return m.keyAt(m.higher(key))
+}
+
+// entryAt returns the entry at the given index, or nil if the index is -1
+func (m *TreeMap[K, V]) entryAt(index int) *Entry[K, V] {
+	if index < 0 || index >= len(m.keys) {
+		return nil
+	}
+	return &Entry[K, V]{Key: m.keys[index], Value: m.values[index]}
+}
+
+// FirstEntry returns the entry with the smallest key
+func (m *TreeMap[K, V]) FirstEntry() *Entry[K, V] {
+	return m.entryAt(0)
+}
+
+// LastEntry returns the entry with the largest key
+func (m *TreeMap[K, V]) LastEntry() *Entry[K, V] {
+	return m.entryAt(len(m.keys) - 1)
+}
+
+// FloorEntry returns the entry with the largest key that is less than or
+// equal to the given key
+func (m *TreeMap[K, V]) FloorEntry(key K) *Entry[K, V] {
+	return m.entryAt(m.floor(key))
+}
+
+// CeilingEntry returns the entry with the smallest key that is greater than
+// or equal to the given key
+func (m *TreeMap[K, V]) CeilingEntry(key K) *Entry[K, V] {
+	return m.entryAt(m.ceiling(key))
+}
+
+// LowerEntry returns the entry with the largest key that is less than the
+// given key
+func (m *TreeMap[K, V]) LowerEntry(key K) *Entry[K, V] {
+	return m.entryAt(m.lower(key))
+}
+
+// HigherEntry returns the entry with the smallest key that is greater than
+// the given key
+func (m *TreeMap[K, V]) HigherEntry(key K) *Entry[K, V] {
+	return m.entryAt(m.higher(key))
+}
+
+// PollFirstEntry removes and returns the entry with the smallest key
+func (m *TreeMap[K, V]) PollFirstEntry() *Entry[K, V] {
+	entry := m.FirstEntry()
+	if entry != nil {
+		m.Remove(entry.Key)
+	}
+	return entry
+}
+
+// PollLastEntry removes and returns the entry with the largest key
+func (m *TreeMap[K, V]) PollLastEntry() *Entry[K, V] {
+	entry := m.LastEntry()
+	if entry != nil {
+		m.Remove(entry.Key)
+	}
+	return entry
+}
+
+// slice returns a copy of the entries of the map between two indices
+func (m *TreeMap[K, V]) slice(low, high int) *TreeMap[K, V] {
+	return &TreeMap[K, V]{
+		sortedKeys: sortedKeys[K]{keys: slices.Clone(m.keys[low:high]), compare: m.compare},
+		values:     slices.Clone(m.values[low:high]),
+	}
+}
+
+// HeadMap returns the entries whose keys are less than the given key, or
+// equal to it if inclusive is set
+func (m *TreeMap[K, V]) HeadMap(to K, inclusive ...bool) *TreeMap[K, V] {
+	high, found := m.search(to)
+	if found && len(inclusive) > 0 && inclusive[0] {
+		high++
+	}
+	return m.slice(0, high)
+}
+
+// TailMap returns the entries whose keys are greater than or equal to the
+// given key, or only greater than it if inclusive is unset
+func (m *TreeMap[K, V]) TailMap(from K, inclusive ...bool) *TreeMap[K, V] {
+	low, found := m.search(from)
+	if found && len(inclusive) > 0 && !inclusive[0] {
+		low++
+	}
+	return m.slice(low, len(m.keys))
+}
+
+// SubMap returns the entries whose keys are from one key, inclusive, to
+// another, exclusive
+func (m *TreeMap[K, V]) SubMap(from K, to K) *TreeMap[K, V] {
+	return m.slice(m.between(from, true, to, false))
+}
+
+// SubMapInclusive is an implementation of the `subMap` method that decides
+// whether each of the ends of the range is included
+func (m *TreeMap[K, V]) SubMapInclusive(from K, fromInclusive bool, to K, toInclusive bool) *TreeMap[K, V] {
+	return m.slice(m.between(from, fromInclusive, to, toInclusive))
+}
+
+// KeySet returns the keys of the map, in order
+func (m *TreeMap[K, V]) KeySet() []K {
+	return slices.Clone(m.keys)
+}
+
+// DescendingKeySet returns the keys of the map, in reverse order
+func (m *TreeMap[K, V]) DescendingKeySet() []K {
+	keys := slices.Clone(m.keys)
+	slices.Reverse(keys)
+	return keys
+}
+
+// Values returns the values of the map, in the order of their keys
+func (m *TreeMap[K, V]) Values() []V {
+	return slices.Clone(m.values)
+}
+
+// EntrySet returns the entries of the map, in order
+func (m *TreeMap[K, V]) EntrySet() []*Entry[K, V] {
+	entries := make([]*Entry[K, V], len(m.keys))
+	for ind := range m.keys {
+		entries[ind] = m.entryAt(ind)
+	}
+	return entries
+}
+
+// TreeSet is an implementation of `java.util.TreeSet`, which keeps its
+// elements sorted in their natural order, or the order of a comparator
+//
+// Just like `TreeMap`, the methods that return an element that may not exist
+// return nil if there is no such element, and the views of a part of the set
+// are copies of that part of the set
+type TreeSet[E any] struct {
+	sortedKeys[E]
+}
+
+// NewTreeSet is an implementation of `new TreeSet<>()`, for elements that are
+// sorted in their natural order
+func NewTreeSet[E cmp.Ordered]() *TreeSet[E] {
+	return &TreeSet[E]{sortedKeys[E]{compare: cmp.Compare[E]}}
+}
+
+// NewTreeSetFunc is an implementation of `new TreeSet<>(comparator)`
+func NewTreeSetFunc[E any](comparator Comparison[E]) *TreeSet[E] {
+	return &TreeSet[E]{sortedKeys[E]{compare: func(a, b E) int { return int(comparator(a, b)) }}}
+}
+
+// Add adds an element to the set, and returns whether it wasn't already in it
+func (s *TreeSet[E]) Add(element E) bool {
+	index, found := s.search(element)
+	if !found {
+		s.insert(index, element)
+	}
+	return !found
+}
+
+// Remove removes an element from the set, and returns whether it was in it
+func (s *TreeSet[E]) Remove(element E) bool {
+	index, found := s.search(element)
+	if found {
+		s.remove(index)
+	}
+	return found
+}
+
+// Contains determines if an element is in the set
+func (s *TreeSet[E]) Contains(element E) bool {
+	_, found := s.search(element)
+	return found
+}
+
+// Size returns the number of elements in the set
+func (s *TreeSet[E]) Size() int32 {
+	return int32(len(s.keys))
+}
+
+// IsEmpty determines if the set has no elements
+func (s *TreeSet[E]) IsEmpty() bool {
+	return len(s.keys) == 0
+}
+
+// Clear removes every element from the set
+func (s *TreeSet[E]) Clear() {
+	s.keys = nil
+}
+
+// First returns the smallest element, and panics with a
+// `NoSuchElementException` if the set is empty
+func (s *TreeSet[E]) First() E {
+	if len(s.keys) == 0 {
+		noSuchElement()
+	}
+	return s.keys[0]
+}
+
+// Last returns the largest element, and panics with a
+// `NoSuchElementException` if the set is empty
+func (s *TreeSet[E]) Last() E {
+	if len(s.keys) == 0 {
+		noSuchElement()
+	}
+	return s.keys[len(s.keys)-1]
+}
+
+// Floor returns the largest element that is less than or equal to the given
+// element
+func (s *TreeSet[E]) Floor(element E) *E {
+	return s.keyAt(s.floor(element))
+}
+
+// Ceiling returns the smallest element that is greater than or equal to the
+// given element
+func (s *TreeSet[E]) Ceiling(element E) *E {
+	return s.keyAt(s.ceiling(element))
+}
+
+// Lower returns the largest element that is less than the given element
+func (s *TreeSet[E]) Lower(element E) *E {
+	return s.keyAt(s.lower(element))
+}
+
+// Higher returns the smallest element that is greater than the given element
+func (s *TreeSet[E]) Higher(element E) *E {
+	return s.keyAt(s.higher(element))
+}
+
+// PollFirst removes and returns the smallest element
+func (s *TreeSet[E]) PollFirst() *E {
+	if len(s.keys) == 0 {
+		return nil
+	}
+	first := s.keyAt(0)
+	s.remove(0)
+	return first
+}
+
+// PollLast removes and returns the largest element
+func (s *TreeSet[E]) PollLast() *E {
+	if len(s.keys) == 0 {
+		return nil
+	}
+	last := s.keyAt(len(s.keys) - 1)
+	s.remove(len(s.keys) - 1)
+	return last
+}
+
+// slice returns a copy of the elements of the set between two indices
+func (s *TreeSet[E]) slice(low, high int) *TreeSet[E] {
+	return &TreeSet[E]{sortedKeys[E]{keys: slices.Clone(s.keys[low:high]), compare: s.compare}}
+}
+
+// HeadSet returns the elements that are less than the given element, or equal
+// to it if inclusive is set
+func (s *TreeSet[E]) HeadSet(to E, inclusive ...bool) *TreeSet[E] {
+	high, found := s.search(to)
+	if found && len(inclusive) > 0 && inclusive[0] {
+		high++
+	}
+	return s.slice(0, high)
+}
+
+// TailSet returns the elements that are greater than or equal to the given
+// element, or only greater than it if inclusive is unset
+func (s *TreeSet[E]) TailSet(from E, inclusive ...bool) *TreeSet[E] {
+	low, found := s.search(from)
+	if found && len(inclusive) > 0 && !inclusive[0] {
+		low++
+	}
+	return s.slice(low, len(s.keys))
+}
+
+// SubSet returns the elements from one element, inclusive, to another,
+// exclusive
+func (s *TreeSet[E]) SubSet(from E, to E) *TreeSet[E] {
+	return s.slice(s.between(from, true, to, false))
+}
+
+// SubSetInclusive is an implementation of the `subSet` method that decides
+// whether each of the ends of the range is included
+func (s *TreeSet[E]) SubSetInclusive(from E, fromInclusive bool, to E, toInclusive bool) *TreeSet[E] {
+	return s.slice(s.between(from, fromInclusive, to, toInclusive))
+}
+
+// Elements returns the elements of the set, in order, which is what a loop
+// over the set iterates over
+func (s *TreeSet[E]) Elements() []E {
+	return slices.Clone(s.keys)
+}
+
+// DescendingSet returns the elements of the set, in reverse order
+func (s *TreeSet[E]) DescendingSet() []E {
+	elements := slices.Clone(s.keys)
+	slices.Reverse(elements)
+	return elements
+}
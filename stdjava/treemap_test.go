@@ -0,0 +1,77 @@
+package stdjava
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTreeMap(t *testing.T) {
+	m := NewTreeMap[int32, string]()
+	for _, key := range []int32{30, 10, 20, 40} {
+		m.Put(key, string(rune('a'+key/10-1)))
+	}
+	if previous := m.Put(20, "B"); previous != "b" {
+		t.Errorf("Expected put to return the previous value, got %q", previous)
+	}
+	if keys := m.KeySet(); !slices.Equal(keys, []int32{10, 20, 30, 40}) {
+		t.Errorf("Expected the keys to be sorted, got %v", keys)
+	}
+	if m.FirstKey() != 10 || m.LastKey() != 40 || m.Get(20) != "B" || m.Size() != 4 {
+		t.Errorf("Expected the first and last keys, and the value of a key, got %v", m.EntrySet())
+	}
+	if key := m.CeilingKey(25); key == nil || *key != 30 {
+		t.Errorf("Expected the ceiling of 25 to be 30, got %v", key)
+	}
+	if key := m.FloorKey(30); key == nil || *key != 30 {
+		t.Errorf("Expected the floor of 30 to be 30, got %v", key)
+	}
+	if key := m.LowerKey(10); key != nil {
+		t.Errorf("Expected no key to be lower than 10, got %v", *key)
+	}
+	if key := m.HigherKey(30); key == nil || *key != 40 {
+		t.Errorf("Expected the key higher than 30 to be 40, got %v", key)
+	}
+	if head := m.HeadMap(30).KeySet(); !slices.Equal(head, []int32{10, 20}) {
+		t.Errorf("Expected the head map to exclude its key, got %v", head)
+	}
+	if tail := m.TailMap(20, false).KeySet(); !slices.Equal(tail, []int32{30, 40}) {
+		t.Errorf("Expected the exclusive tail map to exclude its key, got %v", tail)
+	}
+	if sub := m.SubMap(15, 40).Values(); !slices.Equal(sub, []string{"B", "c"}) {
+		t.Errorf("Expected the values of the sub map, got %v", sub)
+	}
+	if entry := m.PollFirstEntry(); entry.GetKey() != 10 || entry.GetValue() != "a" || m.ContainsKey(10) {
+		t.Errorf("Expected the first entry to be removed, got %v", entry)
+	}
+
+	defer func() {
+		if caught, ok := Catch(recover(), "NoSuchElementException"); !ok {
+			t.Errorf("Expected the first key of an empty map to throw a NoSuchElementException, got %v", caught)
+		}
+	}()
+	m.Clear()
+	m.FirstKey()
+}
+
+func TestTreeSetWithComparator(t *testing.T) {
+	s := NewTreeSetFunc(ReverseOrder[string])
+	for _, element := range []string{"b", "c", "a", "b"} {
+		s.Add(element)
+	}
+	if elements := s.Elements(); !slices.Equal(elements, []string{"c", "b", "a"}) {
+		t.Errorf("Expected the elements in reverse order, without duplicates, got %v", elements)
+	}
+	if s.First() != "c" || s.Last() != "a" {
+		t.Errorf("Expected the order of the comparator to decide the first and last elements")
+	}
+	// In reverse order, the ceiling of an element is the next smaller one
+	if ceiling := s.Ceiling("bb"); ceiling == nil || *ceiling != "b" {
+		t.Errorf("Expected the ceiling in the order of the comparator, got %v", ceiling)
+	}
+	if last := s.PollLast(); last == nil || *last != "a" || s.Contains("a") {
+		t.Errorf("Expected the last element to be removed, got %v", last)
+	}
+	if !s.Remove("c") || s.Remove("c") || s.Size() != 1 {
+		t.Errorf("Expected remove to report whether the element was in the set")
+	}
+}
@@ -0,0 +1,45 @@
+package stdjava
+
+import "golang.org/x/exp/constraints"
+
+// Number is any type that Java's increment and decrement operators work on
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
+// PreIncrement represents an increment that is used as a value (`++i`), which
+// increments a variable, and then evaluates to its new value
+func PreIncrement[T Number](variable *T) T {
+	*variable++
+	return *variable
+}
+
+// PreDecrement represents a decrement that is used as a value (`--i`), which
+// decrements a variable, and then evaluates to its new value
+func PreDecrement[T Number](variable *T) T {
+	*variable--
+	return *variable
+}
+
+// PostIncrement represents an increment that is used as a value (`i++`), which
+// evaluates to the value of a variable before it was incremented
+func PostIncrement[T Number](variable *T) T {
+	previous := *variable
+	*variable++
+	return previous
+}
+
+// PostDecrement represents a decrement that is used as a value (`i--`), which
+// evaluates to the value of a variable before it was decremented
+func PostDecrement[T Number](variable *T) T {
+	previous := *variable
+	*variable--
+	return previous
+}
+
+// Assign represents an assignment that is used as a value (`x = y`), which
+// evaluates to the value that was assigned
+func Assign[T any](variable *T, value T) T {
+	*variable = value
+	return value
+}
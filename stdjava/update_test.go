@@ -0,0 +1,31 @@
+package stdjava
+
+import "testing"
+
+func TestUpdates(t *testing.T) {
+	i := int32(5)
+	if result := PostIncrement(&i); result != 5 || i != 6 {
+		t.Errorf("Expected i++ to be 5 and leave i as 6, got %d and %d", result, i)
+	}
+	if result := PreIncrement(&i); result != 7 || i != 7 {
+		t.Errorf("Expected ++i to be 7 and leave i as 7, got %d and %d", result, i)
+	}
+	if result := PostDecrement(&i); result != 7 || i != 6 {
+		t.Errorf("Expected i-- to be 7 and leave i as 6, got %d and %d", result, i)
+	}
+	if result := PreDecrement(&i); result != 5 || i != 5 {
+		t.Errorf("Expected --i to be 5 and leave i as 5, got %d and %d", result, i)
+	}
+
+	f := 1.5
+	if result := PostIncrement(&f); result != 1.5 || f != 2.5 {
+		t.Errorf("Expected f++ to be 1.5 and leave f as 2.5, got %v and %v", result, f)
+	}
+}
+
+func TestAssign(t *testing.T) {
+	var x, y string
+	if result := Assign(&x, Assign(&y, "value")); result != "value" || x != "value" || y != "value" {
+		t.Errorf("Expected x = y = \"value\" to assign both, got %q, %q, and %q", result, x, y)
+	}
+}
@@ -0,0 +1,65 @@
+package stdjava
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// A UUID is represented by the string of its canonical form, such as
+// "123e4567-e89b-12d3-a456-426614174000", in lower case
+
+// formatUUID returns the canonical form of the 16 bytes of a UUID
+func formatUUID(b [16]byte) string {
+	s := hex.EncodeToString(b[:])
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// withVersion sets the version and the variant of a UUID
+func withVersion(b [16]byte, version byte) [16]byte {
+	b[6] = b[6]&0x0f | version<<4
+	b[8] = b[8]&0x3f | 0x80
+	return b
+}
+
+// RandomUUID is an implementation of `UUID.randomUUID`, which creates a
+// version 4 UUID from cryptographically secure random bytes
+func RandomUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(NewException("InternalError", err.Error(), nil))
+	}
+	return formatUUID(withVersion(b, 4))
+}
+
+// NameUUIDFromBytes is an implementation of `UUID.nameUUIDFromBytes`, which
+// creates a version 3 UUID from the MD5 hash of a name
+func NameUUIDFromBytes(name []byte) string {
+	return formatUUID(withVersion(md5.Sum(name), 3))
+}
+
+// UUIDFromString is an implementation of `UUID.fromString`, which returns the
+// canonical form of a UUID, and panics with an `IllegalArgumentException` if
+// the string isn't one
+//
+// Just like Java, each of the five parts of the UUID can leave out its
+// leading zeros
+func UUIDFromString(s string) string {
+	parts := strings.Split(s, "-")
+	widths := [5]int{8, 4, 4, 4, 12}
+	if len(parts) != len(widths) {
+		panic(NewException("IllegalArgumentException", "Invalid UUID string: "+s, nil))
+	}
+	for ind, part := range parts {
+		if part == "" || len(part) > widths[ind] {
+			panic(NewException("IllegalArgumentException", "Invalid UUID string: "+s, nil))
+		}
+		if _, err := strconv.ParseUint(part, 16, 64); err != nil {
+			panic(NewException("IllegalArgumentException", "Invalid UUID string: "+s, nil))
+		}
+		parts[ind] = strings.Repeat("0", widths[ind]-len(part)) + strings.ToLower(part)
+	}
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,44 @@
+package stdjava
+
+import (
+	"regexp"
+	"testing"
+)
+
+var canonicalUUID = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+func TestRandomUUID(t *testing.T) {
+	id := RandomUUID()
+	if !canonicalUUID.MatchString(id) {
+		t.Fatalf("Expected a canonical UUID, got %q", id)
+	}
+	if id[14] != '4' || id[19] != '8' && id[19] != '9' && id[19] != 'a' && id[19] != 'b' {
+		t.Errorf("Expected a version 4 UUID, got %q", id)
+	}
+	if other := RandomUUID(); other == id {
+		t.Errorf("Expected two random UUIDs to differ, got %q twice", id)
+	}
+}
+
+func TestNameUUIDFromBytes(t *testing.T) {
+	// The same UUID as Java's `UUID.nameUUIDFromBytes("hello".getBytes())`
+	if id := NameUUIDFromBytes([]byte("hello")); id != "5d41402a-bc4b-3a76-b971-9d911017c592" {
+		t.Errorf("Expected the UUID of the MD5 hash of the name, got %q", id)
+	}
+}
+
+func TestUUIDFromString(t *testing.T) {
+	if id := UUIDFromString("123E4567-E89B-12D3-A456-426614174000"); id != "123e4567-e89b-12d3-a456-426614174000" {
+		t.Errorf("Expected the UUID in lower case, got %q", id)
+	}
+	if id := UUIDFromString("1-2-3-4-5"); id != "00000001-0002-0003-0004-000000000005" {
+		t.Errorf("Expected the leading zeros of each part to be filled in, got %q", id)
+	}
+
+	defer func() {
+		if caught, ok := Catch(recover(), "IllegalArgumentException"); !ok {
+			t.Errorf("Expected an invalid UUID to throw an IllegalArgumentException, got %v", caught)
+		}
+	}()
+	UUIDFromString("not-a-uuid")
+}
@@ -12,10 +12,48 @@ type ClassScope struct {
 	Methods []*Definition
 	// Whether this class is an enum
 	IsEnum bool
+	// Whether this class is an interface
+	IsInterface bool
+	// Whether this class is a record
+	IsRecord bool
+	// Whether this class or interface is sealed, so that only the types that it
+	// permits can extend or implement it
+	IsSealed bool
+	// The components of a record, in the order that its canonical constructor
+	// takes them, which are also the fields of its objects
+	RecordComponents []*Definition
+	// Whether the constants of the enum carry their own state, either from the
+	// enum's fields, arguments to its constructor, or their own class bodies
+	EnumHasState bool
 	// Enum constant names (only populated if IsEnum is true)
 	EnumConstants []string
+	// The generated names of the enum constants, keyed by their original names
+	EnumConstantNames map[string]string
+	// Whether the enum's `values` method is called anywhere in the package
+	ValuesUsed bool
 	// Type parameters for generic classes (e.g., ["T", "U"] for class Foo<T, U>)
 	TypeParameters []string
+	// The type parameters that a nested class takes from the classes that it
+	// is nested in, which come before its own in TypeParameters
+	InheritedTypeParameters []string
+	// The name of the receiver of the class's methods, once it is resolved
+	ReceiverName string
+	// The Java name of the class that this class extends, without its type
+	// arguments, or empty if it doesn't extend one
+	Superclass string
+	// The Java names of the interfaces that the class implements, or that an
+	// interface extends, without their type arguments
+	Interfaces []string
+	// The name of the mutex that locks the objects of the class, for its
+	// synchronized methods and the blocks that synchronize on `this`, or empty
+	// if its objects are never locked
+	Monitor string
+	// The class that an inner class is nested in, whose objects enclose the
+	// objects of the inner class, or nil if the class is static or isn't nested
+	Enclosing *ClassScope
+	// The name of the field that holds the enclosing object of an inner class,
+	// which its constructors take, or empty if the class isn't created with one
+	Outer string
 }
 
 // IsTypeParameter checks if a given name is a type parameter of this class
@@ -28,6 +66,17 @@ func (cs *ClassScope) IsTypeParameter(name string) bool {
 	return false
 }
 
+// CanBeOverridden determines if a subclass could override the given method of
+// the class, which is not possible if either the class or the method is final
+//
+// This only decides how the generic methods are generated, where a method that
+// can't be overridden is a generic function instead of a method of a helper
+// type. Nothing else is dispatched through the subclasses of a class, so a
+// final class is otherwise generated the same as any other
+func (cs *ClassScope) CanBeOverridden(method *Definition) bool {
+	return !method.IsStatic && !method.IsFinal && !cs.Class.IsFinal
+}
+
 // FindMethod searches through the immediate class's methods find a specific method
 func (cs *ClassScope) FindMethod() Finder {
 	cm := classMethodFinder(*cs)
@@ -140,6 +189,20 @@ func (cs *ClassScope) FindClass(name string) *Definition {
 	return nil
 }
 
+// FindClassScope searches through a class file and returns the scope for the
+// found class, or nil if none was found
+func (cs *ClassScope) FindClassScope(name string) *ClassScope {
+	if cs.Class.OriginalName == name {
+		return cs
+	}
+	for _, subclass := range cs.Subclasses {
+		if class := subclass.FindClassScope(name); class != nil {
+			return class
+		}
+	}
+	return nil
+}
+
 // FindFieldByName searches for a field by its original name, and returns its definition
 // or nil if none was found
 func (cs *ClassScope) FindFieldByName(name string) *Definition {
@@ -14,10 +14,27 @@ type Definition struct {
 	TypeParameters []string
 	// Whether this definition is static (applies to methods/fields)
 	IsStatic bool
+	// Whether this definition is final, which means that a class can't be
+	// extended, or a method can't be overridden
+	IsFinal bool
+	// Whether this definition is protected, so it can be used by the subclasses
+	// of its class in other packages
+	IsProtected bool
 	// Indicates that this definition requires a helper to model method-level type parameters
 	RequiresHelper bool
 	// Name of the helper type to use (if RequiresHelper)
 	HelperName string
+	// The exceptions in the `throws` clause of a method or a constructor
+	Throws []string
+	// Whether a method is synchronized, which locks its object, or its class if
+	// the method is static, while it runs
+	IsSynchronized bool
+	// Whether a method of an interface is a default method, which has a body
+	// that the classes that implement the interface inherit
+	IsDefault bool
+	// Whether a field is volatile, and has the type from `sync/atomic` that
+	// holds its value, which it is read and written through
+	IsVolatile bool
 
 	// If the definition is a constructor
 	// This is used so that the definition handles its special naming and
@@ -27,6 +44,9 @@ type Definition struct {
 	Parameters []*Definition
 	// Children of the declaration, if the declaration is a scope
 	Children []*Definition
+	// The part of the source that a nested scope covers, which is the only
+	// place that the variables declared in it can be used
+	StartByte, EndByte uint32
 }
 
 // Rename changes the display name of a definition
@@ -53,9 +73,19 @@ func (d *Definition) OriginalParameterTypes() []string {
 	return names
 }
 
-// FindVariable searches a definition's immediate children and parameters
-// to try and find a given variable by its original name
-func (d *Definition) FindVariable(name string) *Definition {
+// FindVariable searches a definition's parameters and children to try and find
+// a given variable by its original name, as it is seen from the given position
+// in the source. The variables of a nested scope are only found from inside of
+// it, and the innermost scope that declares the variable is the one used
+func (d *Definition) FindVariable(name string, position uint32) *Definition {
+	// Nested scopes are unnamed definitions
+	for _, child := range d.Children {
+		if child.OriginalName == "" && child.StartByte <= position && position < child.EndByte {
+			if found := child.FindVariable(name, position); found != nil {
+				return found
+			}
+		}
+	}
 	for _, param := range d.Parameters {
 		if param.OriginalName == name {
 			return param
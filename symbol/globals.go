@@ -14,7 +14,7 @@ func AddSymbolsToPackage(symbols *FileScope) {
 	if _, exist := GlobalScope.Packages[symbols.Package]; !exist {
 		GlobalScope.Packages[symbols.Package] = NewPackageScope()
 	}
-	GlobalScope.Packages[symbols.Package].Files[symbols.BaseClass.Class.Name] = symbols
+	GlobalScope.Packages[symbols.Package].AddSymbolsFromFile(symbols)
 }
 
 // A GlobalSymbols represents a global view of all the packages in the parsed source
@@ -0,0 +1,75 @@
+package symbol
+
+import "strconv"
+
+// A NameAllocator generates the names of declarations that don't exist in the
+// Java source, such as method receivers and helper types, making sure that
+// they never collide with each other, or with a name that is already taken
+type NameAllocator struct {
+	taken map[string]bool
+	// The names that have been allocated for a specific declaration
+	allocated map[string]string
+}
+
+// NewNameAllocator creates an allocator with the given names already taken
+func NewNameAllocator(taken ...string) *NameAllocator {
+	allocator := &NameAllocator{taken: make(map[string]bool), allocated: make(map[string]string)}
+	allocator.Reserve(taken...)
+	return allocator
+}
+
+// Reserve marks the given names as taken
+func (na *NameAllocator) Reserve(names ...string) {
+	for _, name := range names {
+		na.taken[name] = true
+	}
+}
+
+// IsTaken determines if a name has already been taken
+func (na *NameAllocator) IsTaken(name string) bool {
+	return na.taken[name] || IsReserved(name)
+}
+
+// Allocate takes the first candidate name that is available, and if none of
+// them are, numbers the first candidate until it is unique
+//
+// Ex: Allocating "pr" when "pr" and "pr2" are taken returns "pr3"
+func (na *NameAllocator) Allocate(candidates ...string) string {
+	for _, candidate := range candidates {
+		if !na.IsTaken(candidate) {
+			na.Reserve(candidate)
+			return candidate
+		}
+	}
+	for i := 2; ; i++ {
+		if name := candidates[0] + strconv.Itoa(i); !na.IsTaken(name) {
+			na.Reserve(name)
+			return name
+		}
+	}
+}
+
+// AllocateFor allocates a name for the declaration identified by the given key,
+// returning the same name every time that it is called with the same key
+func (na *NameAllocator) AllocateFor(key string, candidates ...string) string {
+	if name, ok := na.allocated[key]; ok {
+		return name
+	}
+	name := na.Allocate(candidates...)
+	na.allocated[key] = name
+	return name
+}
+
+// Fork copies the allocator for a nested scope, such as the body of a function
+// literal, whose names can't take any name that is already taken, but which
+// doesn't take its names from the outer scope
+func (na *NameAllocator) Fork() *NameAllocator {
+	forked := NewNameAllocator()
+	for name := range na.taken {
+		forked.Reserve(name)
+	}
+	for key, name := range na.allocated {
+		forked.allocated[key] = name
+	}
+	return forked
+}
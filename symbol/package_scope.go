@@ -4,6 +4,12 @@ package symbol
 type PackageScope struct {
 	// Maps the file's name to its definitions
 	Files map[string]*FileScope
+
+	// Generates the names of the package-level declarations that are
+	// synthesized for the package, such as helper types
+	names *NameAllocator
+	// Generates the names of the receivers of every class in the package
+	receivers *NameAllocator
 }
 
 func NewPackageScope() *PackageScope {
@@ -55,6 +61,66 @@ func (ps *PackageFieldFinder) ByOriginalName(originalName string) []*Definition
 
 func (ps *PackageScope) AddSymbolsFromFile(symbols *FileScope) {
 	ps.Files[symbols.BaseClass.Class.Name] = symbols
+	if ps.names != nil {
+		ps.reserveDeclaredNames(symbols.BaseClass)
+	}
+}
+
+// Names returns the allocator for the package-level names that are generated
+// for the package, which has every name declared in the package taken
+func (ps *PackageScope) Names() *NameAllocator {
+	if ps.names == nil {
+		ps.names = NewNameAllocator()
+		for _, fileScope := range ps.Files {
+			ps.reserveDeclaredNames(fileScope.BaseClass)
+		}
+	}
+	return ps.names
+}
+
+// reserveDeclaredNames marks every package-level name that is declared by a
+// class, and its nested classes, as taken
+func (ps *PackageScope) reserveDeclaredNames(class *ClassScope) {
+	ps.names.Reserve(class.Class.Name)
+	for _, method := range class.Methods {
+		if method.IsStatic || method.Constructor {
+			ps.names.Reserve(method.Name)
+		}
+	}
+	for _, field := range class.Fields {
+		ps.names.Reserve(field.Name)
+	}
+	for _, subclass := range class.Subclasses {
+		ps.reserveDeclaredNames(subclass)
+	}
+}
+
+// Receivers returns the allocator for the receiver names of the classes in the
+// package
+//
+// Receiver names are only visible in their methods, but they are kept unique
+// across the package so that similar class names can't be confused
+func (ps *PackageScope) Receivers() *NameAllocator {
+	if ps.receivers == nil {
+		ps.receivers = NewNameAllocator()
+	}
+	return ps.receivers
+}
+
+// Classes returns every class in the package, including nested classes
+func (ps *PackageScope) Classes() []*ClassScope {
+	var classes []*ClassScope
+	var addClass func(class *ClassScope)
+	addClass = func(class *ClassScope) {
+		classes = append(classes, class)
+		for _, subclass := range class.Subclasses {
+			addClass(subclass)
+		}
+	}
+	for _, fileScope := range ps.Files {
+		addClass(fileScope.BaseClass)
+	}
+	return classes
 }
 
 // FindClass searches for a class in the given package and returns a scope for it
@@ -38,7 +38,7 @@ func ParseSymbols(root *sitter.Node, source []byte) *FileScope {
 			importPath := node.NamedChild(0).ChildByFieldName("scope").Content(source)
 
 			imports[importedItem] = importPath
-		case "class_declaration", "interface_declaration", "enum_declaration", "annotation_type_declaration":
+		case "class_declaration", "interface_declaration", "enum_declaration", "record_declaration", "annotation_type_declaration":
 			baseClass = node
 		}
 	}
@@ -74,8 +74,30 @@ func parseClassScopeWithParentTypeParams(root *sitter.Node, source []byte, paren
 		Class: &Definition{
 			OriginalName: className,
 			Name:         HandleExportStatus(public, className),
+			// Records are implicitly final
+			IsFinal: nodeutil.HasModifier(root, "final") || root.Type() == "record_declaration",
 		},
-		IsEnum: root.Type() == "enum_declaration",
+		IsEnum:      root.Type() == "enum_declaration",
+		IsInterface: root.Type() == "interface_declaration",
+		IsRecord:    root.Type() == "record_declaration",
+		IsSealed:    nodeutil.HasModifier(root, "sealed"),
+	}
+
+	// Ex: `extends Base<T>` extends `Base`
+	if superclass := root.ChildByFieldName("superclass"); superclass != nil {
+		extended := superclass.NamedChild(0)
+		if extended.Type() == "generic_type" {
+			extended = extended.NamedChild(0)
+		}
+		scope.Superclass = extended.Content(source)
+	}
+
+	// Ex: `implements Shape, Comparable<Shape>` implements `Shape` and
+	// `Comparable`, and an interface extends the interfaces in its own list
+	for _, child := range nodeutil.NamedChildrenOf(root) {
+		if child.Type() == "super_interfaces" || child.Type() == "extends_interfaces" {
+			scope.Interfaces = append(scope.Interfaces, typeListNames(child.NamedChild(0), source)...)
+		}
 	}
 
 	// Extract this class's own type parameters first (e.g., class Foo<T, U>)
@@ -94,11 +116,17 @@ func parseClassScopeWithParentTypeParams(root *sitter.Node, source []byte, paren
 			}
 		}
 		if !shadowed {
-			scope.TypeParameters = append(scope.TypeParameters, parentTP)
+			scope.InheritedTypeParameters = append(scope.InheritedTypeParameters, parentTP)
 		}
 	}
+	scope.TypeParameters = append(scope.TypeParameters, scope.InheritedTypeParameters...)
 	scope.TypeParameters = append(scope.TypeParameters, ownTypeParams...)
 
+	// The components of a record are declared before its body
+	if scope.IsRecord {
+		parseRecordComponents(scope, root.ChildByFieldName("parameters"), source)
+	}
+
 	// Parse the body of the class (or enum)
 
 	for _, node := range nodeutil.NamedChildrenOf(root.ChildByFieldName("body")) {
@@ -108,16 +136,85 @@ func parseClassScopeWithParentTypeParams(root *sitter.Node, source []byte, paren
 			// Parse enum constants
 			constName := node.ChildByFieldName("name").Content(source)
 			scope.EnumConstants = append(scope.EnumConstants, constName)
+			if node.ChildByFieldName("arguments") != nil || node.ChildByFieldName("body") != nil {
+				scope.EnumHasState = true
+			}
 		case "enum_body_declarations":
 			// Parse the methods and constructors inside the enum
 			for _, declNode := range nodeutil.NamedChildrenOf(node) {
 				parseClassMember(scope, declNode, source)
+				if declNode.Type() == "field_declaration" && !nodeutil.HasModifier(declNode, "static") {
+					scope.EnumHasState = true
+				}
 			}
 		default:
 			parseClassMember(scope, node, source)
 		}
 	}
 
+	if scope.IsRecord {
+		addRecordMembers(scope, public)
+	}
+
+	// The mutex can't take the name of any of the class's fields
+	if locksInstances(scope, root.ChildByFieldName("body"), source) {
+		names := NewNameAllocator()
+		for _, field := range scope.Fields {
+			names.Reserve(field.Name)
+		}
+		scope.Monitor = names.Allocate("mu")
+	}
+
+	return scope
+}
+
+// locksInstances determines if the objects of a class are ever locked, by one
+// of its synchronized methods, or by a block in its body that synchronizes on
+// `this`
+func locksInstances(scope *ClassScope, body *sitter.Node, source []byte) bool {
+	for _, method := range scope.Methods {
+		if method.IsSynchronized && !method.IsStatic {
+			return true
+		}
+	}
+
+	var synchronizesOnThis func(node *sitter.Node) bool
+	synchronizesOnThis = func(node *sitter.Node) bool {
+		switch node.Type() {
+		// The classes that are nested in the class have their own `this`
+		case "class_body", "enum_body", "interface_body":
+			return false
+		case "synchronized_statement":
+			if lock := node.NamedChild(0); lock.NamedChildCount() > 0 && lock.NamedChild(0).Type() == "this" {
+				return true
+			}
+		}
+		for _, child := range nodeutil.NamedChildrenOf(node) {
+			if synchronizesOnThis(child) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, member := range nodeutil.NamedChildrenOf(body) {
+		if synchronizesOnThis(member) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAnonymousClassScope generates the symbols for the body of an anonymous
+// class, which is given a name, and the type parameters that are in scope where
+// it is declared
+func ParseAnonymousClassScope(name string, body *sitter.Node, source []byte, typeParams []string) *ClassScope {
+	scope := &ClassScope{
+		Class:          &Definition{OriginalName: name, Name: name},
+		TypeParameters: typeParams,
+	}
+	for _, node := range nodeutil.NamedChildrenOf(body) {
+		parseClassMember(scope, node, source)
+	}
 	return scope
 }
 
@@ -126,12 +223,16 @@ func parseClassMember(scope *ClassScope, node *sitter.Node, source []byte) {
 	switch node.Type() {
 	case "field_declaration":
 		var public bool
+		var isStatic bool
 		// Rename the type based on the public/static rules
 		if node.NamedChild(0).Type() == "modifiers" {
 			for _, modifier := range nodeutil.UnnamedChildrenOf(node.NamedChild(0)) {
 				if modifier.Type() == "public" {
 					public = true
 				}
+				if modifier.Type() == "static" {
+					isStatic = true
+				}
 			}
 		}
 
@@ -153,16 +254,28 @@ func parseClassMember(scope *ClassScope, node *sitter.Node, source []byte) {
 
 		// The converted name and type of the field
 		fieldName := fieldNameNode.Content(source)
-		fieldType := nodeToStr(astutil.ParseTypeWithTypeParams(typeNode, source, scope.TypeParameters))
+		fieldTypeExpr := astutil.ParseTypeWithTypeParams(typeNode, source, scope.TypeParameters)
+		// A volatile field is read and written atomically
+		var volatile bool
+		if nodeutil.HasModifier(node, "volatile") {
+			if atomicType := astutil.AtomicType(fieldTypeExpr); atomicType != nil {
+				fieldTypeExpr, volatile = atomicType, true
+			}
+		}
+		fieldType := nodeToStr(fieldTypeExpr)
 
 		scope.Fields = append(scope.Fields, &Definition{
 			Name:         HandleExportStatus(public, fieldName),
 			OriginalName: fieldName,
 			Type:         fieldType,
 			OriginalType: typeNode.Content(source),
+			IsStatic:     isStatic,
+			IsProtected:  nodeutil.HasModifier(node, "protected"),
+			IsVolatile:   volatile,
 		})
 	case "method_declaration", "constructor_declaration":
-		var public bool
+		// The methods of an interface are public unless they are private
+		public := scope.IsInterface && !nodeutil.HasModifier(node, "private")
 		var isStatic bool
 		// Rename the type based on the public/static rules
 		if node.NamedChild(0).Type() == "modifiers" {
@@ -189,6 +302,18 @@ func parseClassMember(scope *ClassScope, node *sitter.Node, source []byte) {
 			Parameters:     []*Definition{},
 			TypeParameters: methodTypeParams,
 			IsStatic:       isStatic,
+			IsFinal:        nodeutil.HasModifier(node, "final"),
+			IsProtected:    nodeutil.HasModifier(node, "protected"),
+			IsSynchronized: nodeutil.HasModifier(node, "synchronized"),
+			IsDefault:      nodeutil.HasModifier(node, "default"),
+		}
+
+		for _, child := range nodeutil.NamedChildrenOf(node) {
+			if child.Type() == "throws" {
+				for _, thrown := range nodeutil.NamedChildrenOf(child) {
+					declaration.Throws = append(declaration.Throws, thrown.Content(source))
+				}
+			}
 		}
 
 		if node.Type() == "method_declaration" {
@@ -210,29 +335,24 @@ func parseClassMember(scope *ClassScope, node *sitter.Node, source []byte) {
 
 		for _, parameter := range nodeutil.NamedChildrenOf(node.ChildByFieldName("parameters")) {
 
-			var paramName string
-			var paramType *sitter.Node
-
-			// If this is a spread parameter, then it will be in the format:
-			// (type) (variable_declarator name: (name))
-			if parameter.Type() == "spread_parameter" {
-				paramName = parameter.NamedChild(1).ChildByFieldName("name").Content(source)
-				paramType = parameter.NamedChild(0)
-			} else {
-				paramName = parameter.ChildByFieldName("name").Content(source)
-				paramType = parameter.ChildByFieldName("type")
-			}
+			paramName := nodeutil.ParameterName(parameter).Content(source)
+			paramType := nodeutil.ParameterType(parameter)
 
 			declaration.Parameters = append(declaration.Parameters, &Definition{
-				Name:         paramName,
+				Name:         VariableName(paramName),
 				OriginalName: paramName,
 				Type:         nodeToStr(astutil.ParseTypeWithTypeParams(paramType, source, combinedTypeParams)),
 				OriginalType: paramType.Content(source),
 			})
 		}
+		// An `equals` that overrides Object's takes any value, so that it
+		// satisfies the runtime's `Hashable`, and is called by `Objects.equals`
+		if name == "equals" && len(declaration.Parameters) == 1 && declaration.Parameters[0].OriginalType == "Object" {
+			declaration.Parameters[0].Type = "any"
+		}
 
 		if node.ChildByFieldName("body") != nil {
-			methodScope := parseScope(node.ChildByFieldName("body"), source)
+			methodScope := parseScope(node.ChildByFieldName("body"), source, combinedTypeParams)
 			if !methodScope.IsEmpty() {
 				declaration.Children = append(declaration.Children, methodScope.Children...)
 			}
@@ -248,31 +368,75 @@ func parseClassMember(scope *ClassScope, node *sitter.Node, source []byte) {
 		}
 
 		scope.Methods = append(scope.Methods, declaration)
-	case "class_declaration", "interface_declaration", "enum_declaration":
-		other := parseClassScopeWithParentTypeParams(node, source, scope.TypeParameters)
+	case "compact_constructor_declaration":
+		scope.Methods = append(scope.Methods, compactConstructor(scope, node, source))
+	case "class_declaration", "interface_declaration", "enum_declaration", "record_declaration":
+		// Nested enums and records are implicitly static, so they can't refer to
+		// the type parameters of the class that they are nested in
+		parentTypeParams := scope.TypeParameters
+		if node.Type() == "enum_declaration" || node.Type() == "record_declaration" {
+			parentTypeParams = nil
+		}
+		other := parseClassScopeWithParentTypeParams(node, source, parentTypeParams)
 		// Any subclasses will be renamed to part of their parent class
 		other.Class.Rename(scope.Class.Name + other.Class.Name)
+		// The classes that are nested in an interface, and every nested enum and
+		// interface, are implicitly static
+		if node.Type() == "class_declaration" && !scope.IsInterface && !nodeutil.HasModifier(node, "static") {
+			other.Enclosing = scope
+		}
 		scope.Subclasses = append(scope.Subclasses, other)
 	}
 }
 
-func parseScope(root *sitter.Node, source []byte) *Definition {
-	def := &Definition{}
+func parseScope(root *sitter.Node, source []byte, typeParams []string) *Definition {
+	def := &Definition{StartByte: root.StartByte(), EndByte: root.EndByte()}
 	for _, node := range nodeutil.NamedChildrenOf(root) {
 		switch node.Type() {
 		case "local_variable_declaration":
-			/*
-				name := nodeToStr(ParseExpr(node.ChildByFieldName("declarator").ChildByFieldName("name"), source, Ctx{}))
-				def.Children = append(def.Children, &symbol.Definition{
+			for _, declarator := range nodeutil.ChildrenByFieldName(node, "declarator") {
+				name := declarator.ChildByFieldName("name").Content(source)
+				def.Children = append(def.Children, &Definition{
 					OriginalName: name,
 					OriginalType: node.ChildByFieldName("type").Content(source),
-					Type:         nodeToStr(ParseExpr(node.ChildByFieldName("type"), source, Ctx{})),
-					Name:         name,
+					Type:         nodeToStr(astutil.ParseTypeWithTypeParams(node.ChildByFieldName("type"), source, typeParams)),
+					Name:         VariableName(name),
 				})
-			*/
-		case "for_statement", "enhanced_for_statement", "while_statement", "if_statement":
-			def.Children = append(def.Children, parseScope(node, source))
+			}
+		case "block", "for_statement", "enhanced_for_statement", "while_statement", "do_statement",
+			"if_statement", "try_statement", "catch_clause", "finally_clause", "labeled_statement",
+			"switch_expression", "switch_block", "switch_block_statement_group", "synchronized_statement":
+			scope := parseScope(node, source, typeParams)
+			// The variable of an enhanced for loop is only visible in its body
+			if node.Type() == "enhanced_for_statement" {
+				name := node.ChildByFieldName("name").Content(source)
+				scope.Children = append([]*Definition{{
+					OriginalName: name,
+					OriginalType: node.ChildByFieldName("type").Content(source),
+					Type:         nodeToStr(astutil.ParseTypeWithTypeParams(node.ChildByFieldName("type"), source, typeParams)),
+					Name:         VariableName(name),
+				}}, scope.Children...)
+			}
+			def.Children = append(def.Children, scope)
 		}
 	}
 	return def
 }
+
+// hasModifier determines if a declaration is declared with the given modifier,
+// such as `static`
+// typeListNames returns the names of the types in a list of types, without
+// their type arguments or the classes that they are nested in
+func typeListNames(list *sitter.Node, source []byte) []string {
+	var names []string
+	for _, listed := range nodeutil.NamedChildrenOf(list) {
+		if listed.Type() == "generic_type" {
+			listed = listed.NamedChild(0)
+		}
+		if listed.Type() == "scoped_type_identifier" {
+			listed = listed.NamedChild(int(listed.NamedChildCount()) - 1)
+		}
+		names = append(names, listed.Content(source))
+	}
+	return names
+}
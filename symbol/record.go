@@ -0,0 +1,99 @@
+package symbol
+
+import (
+	"github.com/NickyBoy89/java2go/astutil"
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// parseRecordComponents parses the components of a record, which are private
+// fields of its objects
+func parseRecordComponents(scope *ClassScope, parameters *sitter.Node, source []byte) {
+	for _, parameter := range nodeutil.NamedChildrenOf(parameters) {
+		name := nodeutil.ParameterName(parameter).Content(source)
+		paramType := nodeutil.ParameterType(parameter)
+		component := &Definition{
+			Name:         HandleExportStatus(false, name),
+			OriginalName: name,
+			Type:         nodeToStr(astutil.ParseTypeWithTypeParams(paramType, source, scope.TypeParameters)),
+			OriginalType: paramType.Content(source),
+		}
+		scope.RecordComponents = append(scope.RecordComponents, component)
+		scope.Fields = append(scope.Fields, component)
+	}
+}
+
+// componentParameters returns the parameters of the canonical constructor of
+// a record, which are named after its components
+func componentParameters(scope *ClassScope) []*Definition {
+	params := []*Definition{}
+	for _, component := range scope.RecordComponents {
+		params = append(params, &Definition{
+			Name:         VariableName(component.OriginalName),
+			OriginalName: component.OriginalName,
+			Type:         component.Type,
+			OriginalType: component.OriginalType,
+		})
+	}
+	return params
+}
+
+// recordConstructor returns the definition of the canonical constructor of a
+// record
+func recordConstructor(scope *ClassScope, public bool) *Definition {
+	return &Definition{
+		Name:         HandleExportStatus(public, "New") + scope.Class.OriginalName,
+		OriginalName: scope.Class.OriginalName,
+		Type:         scope.Class.OriginalName,
+		Parameters:   componentParameters(scope),
+		Constructor:  true,
+	}
+}
+
+// compactConstructor parses the compact constructor of a record, which is its
+// canonical constructor, without the parameters that it takes from the
+// components of the record
+func compactConstructor(scope *ClassScope, node *sitter.Node, source []byte) *Definition {
+	declaration := recordConstructor(scope, nodeutil.HasModifier(node, "public"))
+	methodScope := parseScope(node.ChildByFieldName("body"), source, scope.TypeParameters)
+	declaration.Children = append(declaration.Children, methodScope.Children...)
+	return declaration
+}
+
+// IsCanonicalConstructor determines if a constructor of a record is its
+// canonical constructor, which takes the same types as its components
+func (cs *ClassScope) IsCanonicalConstructor(constructor *Definition) bool {
+	if !cs.IsRecord || !constructor.Constructor || len(constructor.Parameters) != len(cs.RecordComponents) {
+		return false
+	}
+	for ind, param := range constructor.Parameters {
+		if param.OriginalType != cs.RecordComponents[ind].OriginalType {
+			return false
+		}
+	}
+	return true
+}
+
+// addRecordMembers adds the members that a record declares implicitly, which
+// are its canonical constructor and the accessors of its components, unless
+// the record declares them itself
+func addRecordMembers(scope *ClassScope, public bool) {
+	if len(scope.FindMethod().By(scope.IsCanonicalConstructor)) == 0 {
+		scope.Methods = append(scope.Methods, recordConstructor(scope, public))
+	}
+	for _, component := range scope.RecordComponents {
+		declared := scope.FindMethod().By(func(d *Definition) bool {
+			return !d.Constructor && d.OriginalName == component.OriginalName && len(d.Parameters) == 0
+		})
+		if len(declared) > 0 {
+			continue
+		}
+		scope.Methods = append(scope.Methods, &Definition{
+			Name:         HandleExportStatus(true, component.OriginalName),
+			OriginalName: component.OriginalName,
+			Type:         component.Type,
+			OriginalType: component.OriginalType,
+			Parameters:   []*Definition{},
+		})
+	}
+}
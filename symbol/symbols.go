@@ -1,6 +1,8 @@
 package symbol
 
 import (
+	"strings"
+
 	sitter "github.com/smacker/go-tree-sitter"
 )
 
@@ -17,6 +19,31 @@ func IsReserved(name string) bool {
 	return false
 }
 
+// Go's predeclared identifiers that the generated code relies on, which a
+// variable can't shadow
+var predeclaredIdentifiers = []string{
+	"cap", "len", "make", "new", "panic", "recover",
+	"any", "bool", "byte", "float32", "float64", "int", "int16", "int32", "int64", "rune", "string",
+	"iota", "nil",
+}
+
+// VariableName returns the name that a parameter or local variable is
+// generated with, which has a trailing underscore if its name is a Go keyword,
+// or would shadow one of Go's predeclared identifiers
+//
+// Ex: `type` becomes `type_`, and `len` becomes `len_`
+func VariableName(name string) string {
+	if IsReserved(name) {
+		return name + "_"
+	}
+	for _, predeclared := range predeclaredIdentifiers {
+		if predeclared == name {
+			return name + "_"
+		}
+	}
+	return name
+}
+
 // TypeOfLiteral returns the corresponding type for a Java literal
 func TypeOfLiteral(node *sitter.Node, source []byte) string {
 	var originalType string
@@ -55,9 +82,14 @@ func TypeOfLiteral(node *sitter.Node, source []byte) string {
 func ResolveDefinition(definition *Definition, fileScope *FileScope) bool {
 	// Look in the class scope first
 	//if localClassDef := fileScope.FindClass().ByType(definition.Type); localClassDef != nil {
-	if localClassDef := fileScope.BaseClass.FindClass(definition.Type); localClassDef != nil {
-		// Every type in the local scope is a reference type, so prefix it with a pointer
-		definition.Type = "*" + localClassDef.Name
+	if localClass := fileScope.BaseClass.FindClassScope(definition.Type); localClass != nil {
+		// Every type in the local scope is a reference type, so prefix it with a
+		// pointer, except for interfaces, which already hold a reference
+		definition.Type = referenceType(localClass)
+		return true
+
+	} else if resolved, ok := resolveNestedClassType(definition.Type, fileScope.BaseClass); ok {
+		definition.Type = resolved
 		return true
 
 	} else if globalDef, in := fileScope.Imports[definition.Type]; in { // Look through the imports
@@ -72,6 +104,67 @@ func ResolveDefinition(definition *Definition, fileScope *FileScope) bool {
 	return false
 }
 
+// resolveNestedClassType renames a reference to a nested class of the file,
+// such as `*Inner[string]`, to the name that the class is generated with
+//
+// A nested class takes the type parameters of the classes that it is nested in
+// before its own, so those are added to the type arguments when only the
+// class's own type arguments are given
+//
+// Ex: `*Inner[string]` in `Outer<T>` becomes `*OuterInner[T, string]`
+func resolveNestedClassType(goType string, baseClass *ClassScope) (string, bool) {
+	name := strings.TrimPrefix(goType, "*")
+	var typeArgs []string
+	if open := strings.Index(name, "["); open > 0 && strings.HasSuffix(name, "]") {
+		typeArgs = splitTypeArguments(name[open+1 : len(name)-1])
+		name = name[:open]
+	}
+
+	class := baseClass.FindClassScope(name)
+	if class == nil || class == baseClass {
+		return "", false
+	}
+
+	if len(class.InheritedTypeParameters) > 0 && len(typeArgs) == len(class.TypeParameters)-len(class.InheritedTypeParameters) {
+		typeArgs = append(append([]string{}, class.InheritedTypeParameters...), typeArgs...)
+	}
+
+	resolved := referenceType(class)
+	if len(typeArgs) > 0 {
+		resolved += "[" + strings.Join(typeArgs, ", ") + "]"
+	}
+	return resolved, true
+}
+
+// referenceType returns the Go type that refers to an object of a class
+func referenceType(class *ClassScope) string {
+	if class.IsInterface {
+		return class.Class.Name
+	}
+	return "*" + class.Class.Name
+}
+
+// splitTypeArguments splits a list of Go type arguments on the commas that
+// aren't part of a nested type argument list
+func splitTypeArguments(list string) []string {
+	var args []string
+	var depth, start int
+	for i, c := range list {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(list[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(args, strings.TrimSpace(list[start:]))
+}
+
 // ResolveChildren recursively resolves a definition and all of its children
 // It returns true if all definitions were resolved correctly, and false otherwise
 func ResolveChildren(definition *Definition, fileScope *FileScope) bool {
@@ -0,0 +1,61 @@
+package com.example.bits;
+
+public class BitFlags {
+    private static final long LOW_MASK = 0xFFFFFFFFL;
+
+    private long flags;
+
+    public void set(int bit) {
+        flags |= 1L << bit;
+    }
+
+    public void clear(int bit) {
+        flags &= ~(1L << bit);
+    }
+
+    public boolean isSet(int bit) {
+        return (flags & (1L << bit)) != 0;
+    }
+
+    public int lowWord() {
+        return (int) (flags & LOW_MASK);
+    }
+
+    public int highWord() {
+        return (int) (flags >>> 32);
+    }
+
+    public static long pack(int high, int low) {
+        return ((long) high << 32) | (low & 0xFFFFFFFFL);
+    }
+
+    public static long widen(int value) {
+        return value;
+    }
+
+    public static int unsignedByte(byte value) {
+        return value & 0xFF;
+    }
+
+    public static int intMask(int bit) {
+        int mask = 1 << bit;
+        return mask | 0x80000000;
+    }
+
+    public static long total(int count, long size) {
+        long total = 0;
+        total += count;
+        count /= size;
+        return total * size + count;
+    }
+
+    public static int wrappedShift(int value) {
+        return value << 33;
+    }
+
+    public static long mix(long seed) {
+        seed ^= seed >>> 33;
+        seed *= 0xff51afd7ed558ccdL;
+        return seed ^ (seed >>> 33);
+    }
+}
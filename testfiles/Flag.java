@@ -0,0 +1,13 @@
+public class Flag {
+    interface Toggle {
+        boolean enabled();
+        public String label(int width);
+    }
+
+    enum State implements Toggle {
+        ON, OFF;
+
+        public boolean enabled() { return this == ON; }
+        public String label(int width) { return "x"; }
+    }
+}
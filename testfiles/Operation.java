@@ -0,0 +1,13 @@
+public enum Operation {
+    PLUS {
+        int apply(int a, int b) { return a + b; }
+    },
+    TIMES {
+        int apply(int a, int b) { return a * b; }
+        void describe() { System.out.println("times"); }
+    },
+    IDENTITY;
+
+    int apply(int a, int b) { return a; }
+    void describe() { System.out.println(this); }
+}
@@ -0,0 +1,19 @@
+public enum Planet {
+    MERCURY(3.303e+23, 2.4397e6),
+    EARTH(5.976e+24, 6.37814e6),
+    MOON(EARTH.mass / 81, Planet.EARTH.radius * SCALE);
+
+    private static final double SCALE = 0.27;
+    private static Planet heaviest = EARTH;
+    private final double mass;
+    private final double radius;
+
+    Planet(double mass, double radius) {
+        this.mass = mass;
+        this.radius = radius;
+    }
+
+    public double surfaceGravity() {
+        return mass / (radius * radius);
+    }
+}
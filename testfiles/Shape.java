@@ -0,0 +1,12 @@
+public enum Shape {
+    SQUARE(2) {
+        public double area() { return side * side; }
+    },
+    CIRCLE(1) {
+        public double area() { return 3.14 * side * side; }
+    };
+
+    private final int side;
+    Shape(int side) { this.side = side; }
+    public abstract double area();
+}
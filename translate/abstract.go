@@ -0,0 +1,194 @@
+package translate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// Go doesn't have abstract methods, so every abstract method of a class, along
+// with any method of its interfaces that it doesn't implement, is generated as
+// a stub that panics, the same way that Java throws an `AbstractMethodError`
+// if the method is somehow called without being implemented
+
+// stubMethodBody creates the body of a stub for a method that has no body,
+// such as an abstract method
+//
+// Ex: `panic("abstract method Shape.area is not implemented")`
+func stubMethodBody(ctx Ctx, kind, methodName string) *ast.BlockStmt {
+	return &ast.BlockStmt{List: []ast.Stmt{
+		&ast.ExprStmt{X: &ast.CallExpr{
+			Fun: &ast.Ident{Name: "panic"},
+			Args: []ast.Expr{&ast.BasicLit{
+				Kind:  token.STRING,
+				Value: strconv.Quote(fmt.Sprintf("%s method %s.%s is not implemented", kind, ctx.currentClass.Class.OriginalName, methodName)),
+			}},
+		}},
+	}}
+}
+
+// missingInterfaceStubs generates stubs for the methods of a class's
+// interfaces, and of the interfaces that they extend, that the class doesn't
+// implement. A default method is generated with its own body instead
+//
+// Only the interfaces that are declared in the same package are checked,
+// since the methods of any other interface aren't known
+func missingInterfaceStubs(node *sitter.Node, source []byte, ctx Ctx) []ast.Decl {
+	interfaces := node.ChildByFieldName("interfaces")
+	if interfaces == nil {
+		return nil
+	}
+
+	stubs := []ast.Decl{}
+	// The methods that are already generated, since a method can be declared
+	// by more than one interface, and an interface can be extended more than once
+	generated := &symbol.ClassScope{}
+	visited := map[*symbol.ClassScope]bool{}
+	var stubInterface func(class *symbol.ClassScope)
+	stubInterface = func(class *symbol.ClassScope) {
+		if class == nil || !class.IsInterface || visited[class] {
+			return
+		}
+		visited[class] = true
+		for _, method := range class.Methods {
+			if method.IsStatic || implementsMethod(ctx.currentClass, method, ctx) || declaresSameMethod(generated, method) {
+				continue
+			}
+			generated.Methods = append(generated.Methods, method)
+			if method.IsDefault {
+				// A default method that can't be found is still inherited in Java,
+				// so it isn't replaced by a stub
+				if decl := defaultMethod(node, class, method, source, ctx); decl != nil {
+					stubs = append(stubs, decl)
+				}
+				continue
+			}
+			stubs = append(stubs, interfaceMethodStub(ctx, method))
+		}
+		for _, extended := range class.Interfaces {
+			stubInterface(resolveClassScopeByName(ctx, extended))
+		}
+	}
+
+	for _, typeList := range nodeutil.NamedChildrenOf(interfaces) {
+		for _, implemented := range nodeutil.NamedChildrenOf(typeList) {
+			if implemented.Type() == "type_identifier" {
+				stubInterface(resolveClassScopeByName(ctx, implemented.Content(source)))
+			}
+		}
+	}
+	return stubs
+}
+
+// defaultMethod generates a default method of an interface on the class that
+// inherits it, as if the class declared it, or returns nil if the interface
+// isn't declared in the same file as the class
+func defaultMethod(node *sitter.Node, class *symbol.ClassScope, method *symbol.Definition, source []byte, ctx Ctx) ast.Decl {
+	root := node
+	for root.Parent() != nil {
+		root = root.Parent()
+	}
+	declaration := findDefaultMethod(root, class, method, source)
+	if declaration == nil {
+		return nil
+	}
+
+	// The method is found in the symbols of its interface, and is declared on
+	// the receiver of the class
+	inherited := *class
+	inherited.Class = ctx.currentClass.Class
+	inherited.ReceiverName = ctx.currentClass.ReceiverName
+	inherited.TypeParameters = ctx.currentClass.TypeParameters
+	methodCtx := ctx.Clone()
+	methodCtx.currentClass = &inherited
+	decls := ParseDecl(declaration, source, methodCtx)
+	if len(decls) != 1 {
+		return nil
+	}
+	return decls[0]
+}
+
+// findDefaultMethod finds the declaration of a default method, in the
+// declaration of its interface under the given node
+func findDefaultMethod(node *sitter.Node, class *symbol.ClassScope, method *symbol.Definition, source []byte) *sitter.Node {
+	if node.Type() == "interface_declaration" && node.ChildByFieldName("name").Content(source) == class.Class.OriginalName {
+		for _, member := range nodeutil.NamedChildrenOf(node.ChildByFieldName("body")) {
+			if member.Type() == "method_declaration" && member.ChildByFieldName("name").Content(source) == method.OriginalName &&
+				int(member.ChildByFieldName("parameters").NamedChildCount()) == len(method.Parameters) {
+				return member
+			}
+		}
+	}
+	for _, child := range nodeutil.NamedChildrenOf(node) {
+		if found := findDefaultMethod(child, class, method, source); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// implementsMethod determines if the class declares a method with the same
+// name and parameter types as the given method, or inherits one from the
+// classes that it extends
+func implementsMethod(class *symbol.ClassScope, method *symbol.Definition, ctx Ctx) bool {
+	for visited := map[*symbol.ClassScope]bool{}; class != nil && !visited[class]; class = resolveClassScopeByName(ctx, class.Superclass) {
+		visited[class] = true
+		if declaresSameMethod(class, method) {
+			return true
+		}
+		if class.Superclass == "" {
+			break
+		}
+	}
+	return false
+}
+
+// declaresSameMethod determines if the class itself declares a method with
+// the same name and parameter types as the given method
+func declaresSameMethod(class *symbol.ClassScope, method *symbol.Definition) bool {
+	for _, declared := range class.Methods {
+		if declared.OriginalName != method.OriginalName || len(declared.Parameters) != len(method.Parameters) {
+			continue
+		}
+		matches := true
+		for index, param := range declared.Parameters {
+			if param.OriginalType != method.Parameters[index].OriginalType {
+				matches = false
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// interfaceMethodStub generates a stub for a method of an interface
+func interfaceMethodStub(ctx Ctx, method *symbol.Definition) ast.Decl {
+	params := &ast.FieldList{}
+	for _, param := range method.Parameters {
+		params.List = append(params.List, &ast.Field{
+			Names: []*ast.Ident{{Name: param.Name}},
+			Type:  &ast.Ident{Name: param.Type},
+		})
+	}
+	var results *ast.FieldList
+	if method.Type != "" {
+		results = &ast.FieldList{List: []*ast.Field{{Type: &ast.Ident{Name: method.Type}}}}
+	}
+
+	return &ast.FuncDecl{
+		Name: &ast.Ident{Name: method.Name},
+		Recv: &ast.FieldList{List: []*ast.Field{{
+			Names: []*ast.Ident{{Name: ReceiverName(ctx)}},
+			Type:  &ast.StarExpr{X: instantiateGenericType(ctx.className, typeParamExprs(ctx.currentClass.TypeParameters))},
+		}}},
+		Type: &ast.FuncType{Params: params, Results: errorResults(results, method)},
+		Body: stubMethodBody(ctx, "abstract", method.OriginalName),
+	}
+}
@@ -0,0 +1,102 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAbstractMethodStub(t *testing.T) {
+	src := `
+package abstracts.methods;
+public abstract class Shape {
+    public abstract double area();
+    public String describe() { return "shape"; }
+}
+`
+	out := normalizeSpaces(renderGoFileFromJava(t, src))
+	want := normalizeSpaces(`func (se *Shape) Area() float64 { panic("abstract method Shape.area is not implemented") }`)
+	if !strings.Contains(out, want) {
+		t.Errorf("expected %q in output, got:\n%s", want, out)
+	}
+}
+
+func TestAbstractClassPartialInterface(t *testing.T) {
+	helpers := setupPackageHelpers(t, `
+package abstracts.partial;
+public abstract class Base implements Named {
+    public String describe() { return "base"; }
+}
+`, `
+package abstracts.partial;
+interface Named {
+    String name(int width);
+    String describe();
+}
+`)
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helpers[0]))
+	want := normalizeSpaces(`func (be *Base) Name(width int32) string { panic("abstract method Base.name is not implemented") }`)
+	if !strings.Contains(out, want) {
+		t.Errorf("expected a stub for the missing interface method, got:\n%s", out)
+	}
+	if strings.Count(out, "Describe()") != 1 {
+		t.Errorf("expected the implemented interface method to not be stubbed, got:\n%s", out)
+	}
+}
+
+func TestAbstractClassInheritedInterfaceMethod(t *testing.T) {
+	helpers := setupPackageHelpers(t, `
+package abstracts.inherited;
+public abstract class Square extends Base implements Named {
+}
+`, `
+package abstracts.inherited;
+class Base {
+    public String describe() { return "base"; }
+}
+`, `
+package abstracts.inherited;
+interface Named {
+    String name(int width);
+    String describe();
+}
+`)
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helpers[0]))
+	want := normalizeSpaces(`func (se *Square) Name(width int32) string { panic("abstract method Square.name is not implemented") }`)
+	if !strings.Contains(out, want) {
+		t.Errorf("expected a stub for the missing interface method, got:\n%s", out)
+	}
+	if strings.Contains(out, "Describe()") {
+		t.Errorf("expected the interface method that the superclass implements to not be stubbed, got:\n%s", out)
+	}
+}
+
+func TestAbstractClassExtendedAndDefaultInterfaceMethods(t *testing.T) {
+	out := normalizeSpaces(renderGoFileFromJava(t, `
+package abstracts.defaults;
+public class Shapes {
+    interface Named {
+        String name();
+        default String greet() { return "hi"; }
+    }
+    interface Shape extends Named {
+        int area();
+    }
+    static abstract class Square implements Shape {
+        public int area() { return 4; }
+    }
+}
+`))
+	for _, want := range []string{
+		`func (she *Shapessquare) Name() string { panic("abstract method Square.name is not implemented") }`,
+		`func (she *Shapessquare) Greet() string { return "hi" }`,
+	} {
+		if !strings.Contains(out, normalizeSpaces(want)) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Square.greet") {
+		t.Errorf("expected the default method to not be stubbed, got:\n%s", out)
+	}
+}
@@ -0,0 +1,571 @@
+package translate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/codegen"
+	"github.com/NickyBoy89/java2go/nodeutil"
+	"github.com/NickyBoy89/java2go/parsing"
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// anonymousClassBody returns the body of an anonymous class that is created
+// by an `object_creation_expression`, or nil if the expression only calls a
+// constructor
+func anonymousClassBody(node *sitter.Node) *sitter.Node {
+	for _, child := range nodeutil.NamedChildrenOf(node) {
+		if child.Type() == "class_body" {
+			return child
+		}
+	}
+	return nil
+}
+
+// anonymousSubclass generates the type of an anonymous class that extends
+// another class, and returns an instance of it
+//
+// The generated type embeds the class that it extends, which is created with
+// that class's own constructor, so the methods that the anonymous class doesn't
+// override are promoted from its base. Note that the base's own methods still
+// call the base's implementations, since Go has no virtual methods
+//
+// Ex: `new Greeter("hi") { ... }` becomes `&mainGreeter{Greeter: NewGreeter("hi")}`
+func anonymousSubclass(body *sitter.Node, base *symbol.ClassScope, baseType ast.Expr, created ast.Expr, source []byte, ctx Ctx) ast.Expr {
+	return anonymousClass(body, base.Class.OriginalName, base.Class.Name, baseType, created, source, ctx)
+}
+
+// anonymousImplementation generates the type of an anonymous class that
+// implements an interface, and returns an instance of it. The type has nothing
+// but the methods of the class, which satisfy the interface in Go
+//
+// Ex: `new Shape() { ... }` becomes `&mainShape{}`
+func anonymousImplementation(body *sitter.Node, interfaceName string, source []byte, ctx Ctx) ast.Expr {
+	return anonymousClass(body, interfaceName, "", nil, nil, source, ctx)
+}
+
+// anonymousClass generates the type of an anonymous class, named after the
+// class or interface that it is created from, and returns an instance of it.
+// The type embeds the class with the given name, if there is one, which is
+// created with the given value
+func anonymousClass(body *sitter.Node, baseName string, embeddedName string, embeddedType ast.Expr, created ast.Expr, source []byte, ctx Ctx) ast.Expr {
+	packageScope := symbol.GlobalScope.FindPackage(ctx.currentFile.Package)
+	if packageScope == nil {
+		return created
+	}
+	typeParams := inScopeTypeParameters(ctx)
+	baseName = stripJavaQualifier(baseName)
+
+	// Like in Java, the class is named after the class that it is declared in
+	name := packageScope.Names().AllocateFor(
+		fmt.Sprintf("%s.new %s@%d", ctx.className, baseName, body.StartByte()),
+		symbol.Lowercase(ctx.className)+symbol.Uppercase(baseName),
+	)
+
+	class := symbol.ParseAnonymousClassScope(name, body, source, typeParams)
+	ResolveClass(class, parsing.SourceFile{Symbols: ctx.currentFile})
+	classType := instantiateGenericType(name, typeParamExprs(typeParams))
+
+	// The local variables that the class uses are copied into it, since its
+	// methods are declared outside of the method that the class is created in.
+	// Java only allows this for variables that are effectively final, and the
+	// holders that are used to get around that, such as arrays, are references
+	// in Go as well, so changes through them are still seen on both sides
+	captured := map[string]capturedVariable{}
+	var capturedFields []*ast.Field
+	var capturedValues []ast.Expr
+	for _, variable := range capturedVariables(body, class, source, ctx) {
+		name := variable.Content(source)
+		typ, _ := capturedVariableType(variable, source, ctx)
+		captured[name] = capturedVariable{field: symbol.VariableName(name), typ: typ}
+		capturedFields = append(capturedFields, &ast.Field{Names: []*ast.Ident{{Name: symbol.VariableName(name)}}, Type: typ})
+		capturedValues = append(capturedValues, &ast.KeyValueExpr{
+			Key:   &ast.Ident{Name: symbol.VariableName(name)},
+			Value: ParseExpr(variable, source, ctx),
+		})
+	}
+
+	// Inside of the class, `this` is the anonymous object, and the objects that
+	// enclose it are reached through the one that created it
+	receiver := &ast.Ident{Name: class.ReceiverName}
+	instances := []enclosingInstance{{typ: classType, object: receiver, captured: captured}}
+	outer := enclosingInstances(ctx)
+	captureOuter := len(outer) > 0 && usesQualifiedThis(body)
+	if captureOuter {
+		reference := &ast.SelectorExpr{X: receiver, Sel: &ast.Ident{Name: "outer"}}
+		for _, instance := range outer {
+			instances = append(instances, enclosingInstance{
+				className: instance.className,
+				typ:       instance.typ,
+				object:    rebaseObject(instance.object, outer[0].object, reference),
+			})
+		}
+	}
+
+	classCtx := ctx.Clone()
+	classCtx.className = name
+	classCtx.currentClass = class
+	classCtx.localScope = nil
+	classCtx.localNames = nil
+	classCtx.expectedType = ""
+	classCtx.initializedObject = ""
+	classCtx.initializedType = ""
+	classCtx.instances = instances
+
+	fields, globalVariables := parseFieldDeclarations(nodeutil.NamedChildrenOf(body), source, classCtx)
+	if embeddedName != "" {
+		fields.List = append([]*ast.Field{{Type: &ast.StarExpr{X: embeddedType}}}, fields.List...)
+	}
+	fields.List = append(fields.List, capturedFields...)
+	if captureOuter {
+		fields.List = append(fields.List, &ast.Field{
+			Names: []*ast.Ident{{Name: "outer"}},
+			Type:  &ast.StarExpr{X: outer[0].typ},
+		})
+	}
+
+	if len(globalVariables.Specs) > 0 {
+		*ctx.synthesized = append(*ctx.synthesized, globalVariables)
+	}
+	*ctx.synthesized = append(*ctx.synthesized, codegen.GenStructWithTypeParams(name, fields, typeParams))
+	// The methods of the class are added to the end of the file along with it
+	classCtx.emitter = nil
+	*ctx.synthesized = append(*ctx.synthesized, ParseDecls(body, source, classCtx)...)
+
+	// The fields that are initialized in the anonymous class are set when it is
+	// created, since it has no constructor of its own
+	var elts []ast.Expr
+	if embeddedName != "" {
+		elts = append(elts, &ast.KeyValueExpr{Key: &ast.Ident{Name: embeddedName}, Value: created})
+	}
+	for _, member := range nodeutil.NamedChildrenOf(body) {
+		if member.Type() != "field_declaration" || nodeutil.HasModifier(member, "static") {
+			continue
+		}
+		for _, declarator := range nodeutil.ChildrenByFieldName(member, "declarator") {
+			value := declarator.ChildByFieldName("value")
+			if value == nil {
+				continue
+			}
+			field := class.FindFieldByName(declarator.ChildByFieldName("name").Content(source))
+			elts = append(elts, &ast.KeyValueExpr{
+				Key:   &ast.Ident{Name: field.Name},
+				Value: ParseExpr(value, source, ctx),
+			})
+		}
+	}
+	elts = append(elts, capturedValues...)
+	if captureOuter {
+		elts = append(elts, &ast.KeyValueExpr{Key: &ast.Ident{Name: "outer"}, Value: outer[0].object})
+	}
+
+	return &ast.UnaryExpr{
+		Op: token.AND,
+		X: &ast.CompositeLit{
+			Type: classType,
+			Elts: elts,
+		},
+	}
+}
+
+// anonymousMethod returns the only member of an anonymous class, if it is a
+// method that can be run as a function, without an object of its own. The
+// method can't refer to its object through `this`, or call itself
+func anonymousMethod(body *sitter.Node, source []byte) *sitter.Node {
+	var method *sitter.Node
+	for _, member := range nodeutil.NamedChildrenOf(body) {
+		switch member.Type() {
+		case "method_declaration":
+			if method != nil || nodeutil.HasModifier(member, "static") {
+				return nil
+			}
+			method = member
+		case "comment", "line_comment", "block_comment":
+		default:
+			return nil
+		}
+	}
+	if method == nil {
+		return nil
+	}
+
+	name := method.ChildByFieldName("name").Content(source)
+	var usesObject func(node *sitter.Node) bool
+	usesObject = func(node *sitter.Node) bool {
+		switch node.Type() {
+		// The objects of the classes that are declared in the method are their own
+		case "class_body":
+			return false
+		case "this":
+			// `Outer.this` refers to one of the enclosing objects
+			parent := node.Parent()
+			return parent.Type() != "field_access" || !parent.ChildByFieldName("field").Equal(node)
+		case "method_invocation":
+			if node.ChildByFieldName("object") == nil && node.ChildByFieldName("name").Content(source) == name {
+				return true
+			}
+		}
+		for _, child := range nodeutil.NamedChildrenOf(node) {
+			if usesObject(child) {
+				return true
+			}
+		}
+		return false
+	}
+	if usesObject(method.ChildByFieldName("body")) {
+		return nil
+	}
+	return method
+}
+
+// anonymousFunc translates an anonymous class with a single method into a
+// function, the same as a lambda, or returns nil if the class has anything
+// that a function can't have. The function is converted into the function type
+// of the listener interface that it implements, if there is one
+//
+// Ex: `new Runnable() { public void run() { count(); } }` becomes
+// `func() { count() }`
+func anonymousFunc(body *sitter.Node, listener *symbol.ClassScope, source []byte, ctx Ctx) ast.Expr {
+	method := anonymousMethod(body, source)
+	if method == nil || ctx.currentFile == nil {
+		return nil
+	}
+	class := symbol.ParseAnonymousClassScope(ctx.className, body, source, inScopeTypeParameters(ctx))
+	ResolveClass(class, parsing.SourceFile{Symbols: ctx.currentFile})
+	definition := class.Methods[0]
+
+	// The variables of the method come before the ones of the method that the
+	// class is created in, which the function captures
+	scope := *definition
+	if ctx.localScope != nil {
+		scope.Children = append(append([]*symbol.Definition{}, definition.Children...), &symbol.Definition{
+			Parameters: ctx.localScope.Parameters,
+			Children:   ctx.localScope.Children,
+			IsStatic:   ctx.localScope.IsStatic,
+		})
+	}
+	funcCtx := ctx.Clone()
+	funcCtx.enterFunction(&scope)
+	funcCtx.expectedType = ""
+	funcCtx.hoisted = nil
+	funcCtx.errorTarget = methodErrorTarget(funcCtx)
+
+	lambda := &ast.FuncLit{
+		Type: &ast.FuncType{
+			Params:  ParseNode(method.ChildByFieldName("parameters"), source, funcCtx).(*ast.FieldList),
+			Results: errorResults(&ast.FieldList{List: []*ast.Field{{Type: &ast.Ident{Name: definition.Type}}}}, definition),
+		},
+		Body: ParseStmt(method.ChildByFieldName("body"), source, funcCtx).(*ast.BlockStmt),
+	}
+	completeWithoutError(lambda.Body, definition)
+	if definition.Type == "" && !methodThrows(definition) {
+		lambda.Type.Results = nil
+	}
+
+	if listener != nil {
+		return &ast.CallExpr{Fun: &ast.Ident{Name: listenerFuncName(listener, ctx)}, Args: []ast.Expr{lambda}}
+	}
+	return lambda
+}
+
+// An enclosingInstance is an object that `this` can refer to
+type enclosingInstance struct {
+	// The Java name of the object's class, which qualifies `this`, or empty for
+	// an anonymous class
+	className string
+	// The Go type of the object
+	typ ast.Expr
+	// The expression that refers to the object
+	object ast.Expr
+	// The local variables that an anonymous object has captured, by their Java
+	// names
+	captured map[string]capturedVariable
+}
+
+// A capturedVariable is a local variable that is copied into a field of an
+// anonymous object
+type capturedVariable struct {
+	field string
+	typ   ast.Expr
+}
+
+// capturedVariables finds the local variables from outside of an anonymous
+// class that its body uses, returning the first use of each
+func capturedVariables(body *sitter.Node, class *symbol.ClassScope, source []byte, ctx Ctx) []*sitter.Node {
+	var variables []*sitter.Node
+	seen := map[string]bool{}
+	var search func(node *sitter.Node)
+	search = func(node *sitter.Node) {
+		if node.Type() == "identifier" && isVariableReference(node) {
+			name := node.Content(source)
+			if _, ok := capturedVariableType(node, source, ctx); ok && !seen[name] && class.FindFieldByName(name) == nil {
+				seen[name] = true
+				variables = append(variables, node)
+			}
+		}
+		for _, child := range nodeutil.NamedChildrenOf(node) {
+			search(child)
+		}
+	}
+	search(body)
+	return variables
+}
+
+// capturedVariableType returns the type of a local variable that an anonymous
+// class can capture, which may have been captured by an enclosing anonymous
+// class itself
+func capturedVariableType(node *sitter.Node, source []byte, ctx Ctx) (ast.Expr, bool) {
+	name := node.Content(source)
+	if ctx.localScope != nil {
+		if variable := ctx.localScope.FindVariable(name, node.StartByte()); variable != nil {
+			return javaTypeStringToGoTypeExpr(variable.OriginalType, inScopeTypeParameters(ctx)), true
+		}
+	}
+	if len(ctx.instances) > 0 {
+		if variable, ok := ctx.instances[0].captured[name]; ok {
+			return variable.typ, true
+		}
+	}
+	return nil, false
+}
+
+// enclosingInstances returns the objects that `this` can refer to, from the
+// innermost one outwards, which is nothing in a static context
+func enclosingInstances(ctx Ctx) []enclosingInstance {
+	if len(ctx.instances) > 0 {
+		return ctx.instances
+	}
+	if ctx.currentClass == nil || ctx.localScope == nil || ctx.localScope.IsStatic {
+		return nil
+	}
+	instances := []enclosingInstance{{
+		className: ctx.currentClass.Class.OriginalName,
+		typ:       instantiateGenericType(ctx.currentClass.Class.Name, typeParamExprs(ctx.currentClass.TypeParameters)),
+		object:    &ast.Ident{Name: ReceiverName(ctx)},
+	}}
+	// The objects that enclose an object of an inner class are reached through
+	// the fields that hold them
+	for class := ctx.currentClass; class.Outer != ""; class = class.Enclosing {
+		instances = append(instances, enclosingInstance{
+			className: class.Enclosing.Class.OriginalName,
+			typ:       outerType(class).(*ast.StarExpr).X,
+			object:    &ast.SelectorExpr{X: instances[len(instances)-1].object, Sel: &ast.Ident{Name: class.Outer}},
+		})
+	}
+	return instances
+}
+
+// qualifiedThis returns the enclosing object that `Class.this` refers to
+func qualifiedThis(className string, ctx Ctx) ast.Expr {
+	className = className[strings.LastIndex(className, ".")+1:]
+	for _, instance := range enclosingInstances(ctx) {
+		if instance.className == className {
+			return instance.object
+		}
+	}
+	return &ast.Ident{Name: ReceiverName(ctx)}
+}
+
+// usesQualifiedThis determines if the body of a class refers to one of the
+// objects that enclose it, through something such as `Outer.this`
+func usesQualifiedThis(node *sitter.Node) bool {
+	if node.Type() == "field_access" && node.ChildByFieldName("field").Type() == "this" {
+		return true
+	}
+	for _, child := range nodeutil.NamedChildrenOf(node) {
+		if usesQualifiedThis(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// rebaseObject changes the object that an expression that refers to an
+// enclosing object starts from
+//
+// Ex: `mn.outer` rebased from `mn` to `ar.outer` becomes `ar.outer.outer`
+func rebaseObject(object, from, to ast.Expr) ast.Expr {
+	if types.ExprString(object) == types.ExprString(from) {
+		return to
+	}
+	if selector, ok := object.(*ast.SelectorExpr); ok {
+		return &ast.SelectorExpr{X: rebaseObject(selector.X, from, to), Sel: selector.Sel}
+	}
+	return object
+}
+
+// isCreatedInPlace determines if an object creation expression doesn't declare
+// an anonymous class, or if its class only initializes the object, so that the
+// object is created in place of the class
+func isCreatedInPlace(node *sitter.Node) bool {
+	body := anonymousClassBody(node)
+	return body == nil || isInitializerOnly(body)
+}
+
+// isInitializerOnly determines if the body of an anonymous class consists of
+// nothing but instance initializers, which means that it only exists to
+// initialize the object that it extends
+//
+// Ex: `new HashMap<>() {{ put("a", 1); }}`
+func isInitializerOnly(body *sitter.Node) bool {
+	var initializers int
+	for _, member := range nodeutil.NamedChildrenOf(body) {
+		switch member.Type() {
+		case "block":
+			initializers++
+		case "comment", "line_comment", "block_comment":
+		default:
+			return false
+		}
+	}
+	return initializers > 0
+}
+
+// localVariableNames returns the allocator for new variables in the current
+// function, which can't take the name of any of its parameters or variables,
+// or of any other variable that was generated in it
+func localVariableNames(ctx Ctx) *symbol.NameAllocator {
+	if ctx.localNames != nil {
+		return ctx.localNames
+	}
+	return newLocalNames(ctx.localScope)
+}
+
+// enterFunction makes a definition the scope of the function that is being
+// parsed, with a new allocator for the variables that are generated in it,
+// which can't take the name of the receiver either
+func (c *Ctx) enterFunction(scope *symbol.Definition) {
+	c.localScope = scope
+	c.localNames = newLocalNames(scope)
+	c.localNames.Reserve(ReceiverName(*c))
+}
+
+// newLocalNames creates an allocator with the names of the parameters and the
+// variables of a function taken
+func newLocalNames(scope *symbol.Definition) *symbol.NameAllocator {
+	taken := symbol.NewNameAllocator()
+	if scope != nil {
+		var reserveVariables func(def *symbol.Definition)
+		reserveVariables = func(def *symbol.Definition) {
+			for _, param := range def.Parameters {
+				taken.Reserve(param.Name)
+			}
+			for _, child := range def.Children {
+				taken.Reserve(child.Name)
+				reserveVariables(child)
+			}
+		}
+		reserveVariables(scope)
+	}
+	return taken
+}
+
+// doubleBraceInitialization creates an object, and runs the initializers of
+// the anonymous class on it, in a function that is called in place
+//
+// Unqualified method calls in the initializers, as well as `this`, refer to the
+// object that is being created. The object has the given Go type, which is a
+// map, a set, or a slice for the collections that are translated into them, so
+// that the calls on one of them are translated the same as everywhere else
+//
+// Ex: `new Config() {{ set("a", 1); }}` becomes
+// `func() *Config { cg := NewConfig(); cg.set("a", 1); return cg }()`, and
+// `new HashMap<String, Integer>() {{ put("a", 1); }}` becomes
+// `func() map[string]int32 { hp := make(map[string]int32); hp["a"] = 1; return hp }()`
+func doubleBraceInitialization(body *sitter.Node, javaType string, objectType ast.Expr, created ast.Expr, source []byte, ctx Ctx) ast.Expr {
+	// The object is named after its class
+	baseType := objectType
+	if pointer, ok := baseType.(*ast.StarExpr); ok {
+		baseType = pointer.X
+	}
+	base, _ := parseJavaTypeString(javaType)
+	baseName := stripJavaQualifier(base)
+	switch generic := baseType.(type) {
+	case *ast.Ident:
+		baseName = generic.Name
+	case *ast.IndexExpr:
+		if ident, ok := generic.X.(*ast.Ident); ok {
+			baseName = ident.Name
+		}
+	case *ast.IndexListExpr:
+		if ident, ok := generic.X.(*ast.Ident); ok {
+			baseName = ident.Name
+		}
+	}
+
+	// The object can't shadow any of the variables that the initializers use
+	object := localVariableNames(ctx).Allocate(codegen.ShortName(baseName))
+
+	initCtx := ctx.Clone()
+	initCtx.initializedObject = object
+	initCtx.initializedType = javaType
+	initCtx.instances = append([]enclosingInstance{{typ: baseType, object: &ast.Ident{Name: object}}}, enclosingInstances(ctx)...)
+	initCtx.expectedType = ""
+
+	statements := []ast.Stmt{&ast.AssignStmt{
+		Lhs: []ast.Expr{&ast.Ident{Name: object}},
+		Tok: token.DEFINE,
+		Rhs: []ast.Expr{created},
+	}}
+	for _, member := range nodeutil.NamedChildrenOf(body) {
+		if member.Type() == "block" {
+			statements = append(statements, ParseStmt(member, source, initCtx).(*ast.BlockStmt).List...)
+		}
+	}
+	statements = append(statements, &ast.ReturnStmt{Results: []ast.Expr{&ast.Ident{Name: object}}})
+
+	return &ast.CallExpr{
+		Fun: &ast.FuncLit{
+			Type: &ast.FuncType{
+				Params:  &ast.FieldList{},
+				Results: &ast.FieldList{List: []*ast.Field{{Type: objectType}}},
+			},
+			Body: &ast.BlockStmt{List: statements},
+		},
+	}
+}
+
+// initializedCollectionCall translates an unqualified method call in the double
+// brace initializer of a map, a set, or a list, the same as the method is
+// translated when it is called on the collection, or returns nil if the object
+// that is being initialized isn't one of them
+func initializedCollectionCall(node *sitter.Node, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	object := &ast.Ident{Name: ctx.initializedObject}
+	if keyType, valueType, ok := mapTypeOf(ctx.initializedType); ok {
+		return mapMethodOn(node, object, args, keyType, valueType, ctx, source)
+	}
+	if element, ok := setTypeOf(ctx.initializedType); ok {
+		return setMethodOn(node, object, args, element, ctx, source)
+	}
+	if element, ok := listTypeOf(ctx.initializedType); ok {
+		return listMethodOn(node, object, args, element, ctx, source)
+	}
+	return nil
+}
+
+// initializedCollectionStmt translates an unqualified method call in the double
+// brace initializer of a map or a list that is the whole of an expression
+// statement, the same as mapStmt and listStmt, or returns nil if it isn't one
+//
+// Ex: `put("a", 1);` turns into `hp["a"] = 1`
+func initializedCollectionStmt(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	if ctx.initializedObject == "" || node.Type() != "method_invocation" || node.ChildByFieldName("object") != nil {
+		return nil
+	}
+	// A static method of the enclosing classes is called instead
+	if findEnclosingStaticMethod(ctx, node.ChildByFieldName("name").Content(source), int(node.ChildByFieldName("arguments").NamedChildCount())) != nil {
+		return nil
+	}
+	parseObject := func() ast.Expr { return &ast.Ident{Name: ctx.initializedObject} }
+	if keyType, valueType, ok := mapTypeOf(ctx.initializedType); ok {
+		return mapStmtOn(node, parseObject, keyType, valueType, source, ctx)
+	}
+	if element, ok := listTypeOf(ctx.initializedType); ok {
+		return listStmtOn(node, parseObject, element, source, ctx)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnonymousSubclass(t *testing.T) {
+	src := `
+package anonymous.subclass;
+public class Main {
+    public static class Greeter {
+        String name;
+        public Greeter(String name) { this.name = name; }
+        public String greet() { return "Hello " + this.name; }
+    }
+    public static Greeter create() {
+        return new Greeter("hi") {
+            int count = 2;
+            public String greet() { return "Hey " + this.name; }
+        };
+    }
+}
+`
+	out := normalizeSpaces(renderGoFileFromJava(t, src))
+	for _, want := range []string{
+		`return &mainGreeter{MainGreeter: NewGreeter("hi"), count: 2}`,
+		`type mainGreeter struct { *MainGreeter count int32 }`,
+		`func (mar *mainGreeter) Greet() string { return "Hey " + mar.name }`,
+	} {
+		if !strings.Contains(out, normalizeSpaces(want)) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestAnonymousSubclassOfGenericClass(t *testing.T) {
+	helpers := setupPackageHelpers(t, `
+package anonymous.generic;
+public class Holder<T> {
+    public Box<T> wrap() {
+        return new Box<T>() {
+            public T get() { return null; }
+        };
+    }
+}
+`, `
+package anonymous.generic;
+public class Box<V> {
+    V value;
+    public V get() { return this.value; }
+}
+`)
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helpers[0]))
+	for _, want := range []string{
+		`&holderBox[T]{Box: ConstructBox[T]()}`,
+		`type holderBox[T any] struct { *Box[T] }`,
+		`) Get() T {`,
+	} {
+		if !strings.Contains(out, normalizeSpaces(want)) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestDoubleBraceInitialization(t *testing.T) {
+	src := `
+package anonymous.doublebrace;
+import java.util.HashMap;
+public class Main {
+    public static void fill(int hp) {
+        HashMap<String, Integer> values = new HashMap<String, Integer>() {{
+            put("a", hp);
+            this.put("b", 2);
+        }};
+    }
+}
+`
+	out := normalizeSpaces(renderGoFileFromJava(t, src))
+	// The map is a Go map, which is initialized the same as anywhere else
+	want := normalizeSpaces(`values := func() map[string]int32 {
+		hp2 := make(map[string]int32)
+		hp2["a"] = hp
+		hp2["b"] = 2
+		return hp2
+	}()`)
+	if !strings.Contains(out, want) {
+		t.Errorf("expected %q in output, got:\n%s", want, out)
+	}
+	if strings.Contains(out, "type main") {
+		t.Errorf("expected no type to be generated for the initializer, got:\n%s", out)
+	}
+}
+
+func TestDoubleBraceInitializationOfClass(t *testing.T) {
+	src := `
+package anonymous.doublebrace;
+public class Main {
+    static class Config {
+        Config() {}
+        void set(String key, int value) {}
+    }
+    public static void fill() {
+        Config config = new Config() {{
+            set("a", 1);
+        }};
+    }
+}
+`
+	out := normalizeSpaces(renderGoFileFromJava(t, src))
+	want := normalizeSpaces(`config := func() *Mainconfig {
+		mg := newConfig()
+		mg.set("a", 1)
+		return mg
+	}()`)
+	if !strings.Contains(out, want) {
+		t.Errorf("expected %q in output, got:\n%s", want, out)
+	}
+}
+
+func TestAnonymousClassThis(t *testing.T) {
+	src := `
+package anonymous.receiver;
+public class Main {
+    int total;
+    public static class Counter {
+        int count;
+        public void tick() { }
+    }
+    public Counter make() {
+        Runnable reset = () -> { this.total = 0; };
+        return new Counter() {
+            public void tick() {
+                this.count++;
+                Main.this.total++;
+            }
+        };
+    }
+}
+`
+	out := normalizeSpaces(renderGoFileFromJava(t, src))
+	for _, want := range []string{
+		`reset := func() { mn.total = 0 }`,
+		`return &mainCounter{MainCounter: ConstructCounter(), outer: mn}`,
+		`type mainCounter struct { *MainCounter outer *Main }`,
+		`mar.count++ mar.outer.total++`,
+	} {
+		if !strings.Contains(out, normalizeSpaces(want)) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestAnonymousClassCapturesLocals(t *testing.T) {
+	src := `
+package anonymous.capture;
+public class Main {
+    public static class Counter {
+        public void tick() { }
+    }
+    public static Counter make(String label) {
+        int[] total = {0};
+        return new Counter() {
+            public void tick() {
+                total[0]++;
+                System.out.println(label);
+            }
+        };
+    }
+}
+`
+	out := normalizeSpaces(renderGoFileFromJava(t, src))
+	for _, want := range []string{
+		`return &mainCounter{MainCounter: ConstructCounter(), total: total, label: label}`,
+		`type mainCounter struct { *MainCounter total []int32 label string }`,
+		`mar.total[0]++ fmt.Println(mar.label)`,
+	} {
+		if !strings.Contains(out, normalizeSpaces(want)) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestAnonymousInterfaceImplementations(t *testing.T) {
+	src := `
+package anonymous.interfaces;
+import java.util.*;
+public class Main {
+    interface Shape {
+        double area();
+        String name();
+    }
+    public static Shape square(List<String> names, double side) {
+        Runnable log = new Runnable() {
+            public void run() {
+                System.out.println(side);
+            }
+        };
+        names.sort(new Comparator<String>() {
+            public int compare(String a, String b) {
+                return a.compareTo(b);
+            }
+        });
+        return new Shape() {
+            public double area() { return side * side; }
+            public String name() { return "square"; }
+        };
+    }
+}
+`
+	out := normalizeSpaces(renderGoFileFromJava(t, src))
+	for _, want := range []string{
+		`log := func() { fmt.Println(side) }`,
+		`names.sort(func(a string, b string) int32 {`,
+		`return &mainShape{side: side}`,
+		`type mainShape struct { side float64 }`,
+		`func (mae *mainShape) Area() float64 { return mae.side * mae.side }`,
+	} {
+		if !strings.Contains(out, normalizeSpaces(want)) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package translate
+
+import (
+	"go/ast"
+	"strconv"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// isFloatingExpr determines if an expression results in a float or a double
+func isFloatingExpr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	switch node.Type() {
+	case "decimal_floating_point_literal", "hex_floating_point_literal":
+		return true
+	case "parenthesized_expression":
+		return isFloatingExpr(node.NamedChild(0), ctx, source)
+	case "unary_expression":
+		return isFloatingExpr(node.ChildByFieldName("operand"), ctx, source)
+	case "binary_expression":
+		if isStringExpr(node, ctx, source) {
+			return false
+		}
+		switch node.ChildByFieldName("operator").Type() {
+		case "+", "-", "*", "/", "%":
+			return isFloatingExpr(node.ChildByFieldName("left"), ctx, source) || isFloatingExpr(node.ChildByFieldName("right"), ctx, source)
+		}
+		return false
+	case "cast_expression":
+		return isFloatingType(node.ChildByFieldName("type").Content(source))
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	return ok && isFloatingType(stripJavaQualifier(javaType))
+}
+
+// floatingType returns whether an expression results in a float or a double,
+// or an empty string if it results in neither, or its type isn't known
+func floatingType(node *sitter.Node, ctx Ctx, source []byte) string {
+	switch node.Type() {
+	case "decimal_floating_point_literal", "hex_floating_point_literal":
+		javaType, _ := literalJavaType(node, source)
+		return javaType
+	case "parenthesized_expression":
+		return floatingType(node.NamedChild(0), ctx, source)
+	case "unary_expression":
+		return floatingType(node.ChildByFieldName("operand"), ctx, source)
+	case "cast_expression":
+		if javaType := node.ChildByFieldName("type").Content(source); javaType == "float" || javaType == "double" {
+			return javaType
+		}
+		return ""
+	case "binary_expression":
+		if isStringExpr(node, ctx, source) {
+			return ""
+		}
+		switch node.ChildByFieldName("operator").Type() {
+		case "+", "-", "*", "/", "%":
+			left, right := floatingType(node.ChildByFieldName("left"), ctx, source), floatingType(node.ChildByFieldName("right"), ctx, source)
+			if left == "double" || right == "double" {
+				return "double"
+			}
+			if left == "float" || right == "float" {
+				return "float"
+			}
+		}
+		return ""
+	}
+	// The wrapper classes are left alone, since their values are pointers
+	javaType, _ := inferExprJavaType(node, ctx, source)
+	if javaType = stripJavaQualifier(javaType); javaType == "float" || javaType == "double" {
+		return javaType
+	}
+	return ""
+}
+
+// isUntypedFloatingConstant determines if an expression is a floating-point
+// constant without a suffix, which Go doesn't give a type to
+func isUntypedFloatingConstant(node *sitter.Node, source []byte) bool {
+	switch node.Type() {
+	case "decimal_floating_point_literal":
+		return !strings.ContainsAny(node.Content(source), "fFdD")
+	case "parenthesized_expression":
+		return isUntypedFloatingConstant(node.NamedChild(0), source)
+	case "unary_expression":
+		return isUntypedFloatingConstant(node.ChildByFieldName("operand"), source)
+	}
+	return false
+}
+
+// isFloatingType determines if a Java type is a float or a double
+func isFloatingType(javaType string) bool {
+	switch javaType {
+	case "float", "double", "Float", "Double":
+		return true
+	}
+	return false
+}
+
+// isZeroLiteral determines if an expression is a zero that is written out,
+// which Go evaluates as a constant
+func isZeroLiteral(node *sitter.Node, source []byte) bool {
+	switch node.Type() {
+	case "decimal_integer_literal", "decimal_floating_point_literal":
+		value, err := strconv.ParseFloat(strings.TrimRight(node.Content(source), "fFdDlL"), 64)
+		return err == nil && value == 0
+	case "parenthesized_expression":
+		return isZeroLiteral(node.NamedChild(0), source)
+	case "unary_expression":
+		return isZeroLiteral(node.ChildByFieldName("operand"), source)
+	}
+	return false
+}
+
+// floatingArithmetic translates a division or remainder of floating-point
+// numbers, which don't behave the same in Go, or returns nil for any other
+// operation
+//
+// Go has no `%` for floats, and won't compile a division by a constant zero,
+// which is Infinity or NaN in Java, so these go through the runtime in strict
+// mode. Divisions by anything other than a constant zero are left as they are,
+// since Go's division at runtime already matches Java's
+//
+// Ex: `x % 2.5` becomes `stdjava.FloatRemainder(x, 2.5)`
+func floatingArithmetic(operator string, left, right *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	if !StrictMode || !(isFloatingExpr(left, ctx, source) || isFloatingExpr(right, ctx, source)) {
+		return nil
+	}
+	switch {
+	case operator == "%":
+		return runtimeCall("FloatRemainder", ParseExpr(left, source, ctx), ParseExpr(right, source, ctx))
+	case operator == "/" && isZeroLiteral(right, source):
+		return runtimeCall("FloatDivide", ParseExpr(left, source, ctx), ParseExpr(right, source, ctx))
+	}
+	return nil
+}
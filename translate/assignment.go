@@ -0,0 +1,360 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/NickyBoy89/java2go/codegen"
+	"github.com/NickyBoy89/java2go/nodeutil"
+)
+
+// The kind of diagnostic reported for an assignment or an update that is used
+// as a value, after the variable that it changes is read in the same
+// expression, which Go doesn't guarantee to read first
+const evaluationOrderDiagnostic = "evaluation-order"
+
+// assignmentValue translates an assignment that is used as a value
+//
+// When nothing in the statement is evaluated before the assignment, or only
+// variables that can be copied before it, the assignment is hoisted before the
+// statement, and its value is the variable that was assigned to. Otherwise,
+// the assignment is made by the runtime, which evaluates to the value that was
+// assigned
+//
+// Ex: `if ((line = reader.readLine()) != null)` becomes
+// `if line = reader.readLine(); line != nil`, and `int z = x * (x = 2);`
+// becomes `xOld := x; x = 2; z := xOld * x`
+func assignmentValue(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	if assigned := volatileAssignmentValue(node, source, ctx); assigned != nil {
+		return assigned
+	}
+
+	left := node.ChildByFieldName("left")
+	if ctx.hoisted != nil && isReevaluable(left, source) && isHoistableAfterCopies(node, assignedName(left, source), source) {
+		// Assignments inside of the assigned value are hoisted before this one
+		ctx.hoisted.hoist(parseStmtNode(node, source, ctx))
+		return ParseExpr(left, source, ctx)
+	}
+	reportReadBefore(node, assignedName(left, source), source, ctx)
+
+	// A compound assignment, such as `x += 1`, assigns `x + 1`
+	variable := ParseExpr(left, source, ctx)
+	value := ParseExpr(node.ChildByFieldName("right"), source, ctx)
+	switch operator := node.ChildByFieldName("operator").Content(source); operator {
+	case "=":
+	case ">>>=":
+		value = unsignedShiftAssignment(left, node.ChildByFieldName("right"), variable, value, source, ctx)
+	default:
+		value = &ast.BinaryExpr{X: variable, Op: codegen.StrToToken(operator[:len(operator)-1]), Y: value}
+	}
+	return runtimeCall("Assign", &ast.UnaryExpr{Op: token.AND, X: variable}, value)
+}
+
+// isReevaluable determines if the variable that is assigned to can be
+// evaluated again after the assignment, to get the value that was assigned
+func isReevaluable(node *sitter.Node, source []byte) bool {
+	switch node.Type() {
+	case "identifier":
+		return true
+	case "field_access":
+		object := node.ChildByFieldName("object")
+		return object.Type() == "this" || object.Type() == "identifier"
+	case "array_access":
+		array, index := node.ChildByFieldName("array"), node.ChildByFieldName("index")
+		return array.Type() == "identifier" && isUnaffectedBy(index, array.Content(source), source)
+	}
+	return false
+}
+
+// assignedName returns the name of the variable that an assignment changes,
+// which is the array for an element of one
+func assignedName(node *sitter.Node, source []byte) string {
+	switch node.Type() {
+	case "field_access":
+		return node.ChildByFieldName("field").Content(source)
+	case "array_access":
+		return node.ChildByFieldName("array").Content(source)
+	}
+	return node.Content(source)
+}
+
+// isEvaluatedFirst determines if an expression is evaluated before anything in
+// the statement that it is in, except for what the expression can't change,
+// so that it can be evaluated before the statement instead
+//
+// An operand that is only evaluated depending on another operand, such as the
+// right side of `&&`, or a branch of a ternary, is never evaluated first
+func isEvaluatedFirst(node *sitter.Node, assigned string, source []byte) bool {
+	return evaluatedFirst(node, func(operand *sitter.Node) bool {
+		return isUnaffectedBy(operand, assigned, source)
+	})
+}
+
+// isHoistableAfterCopies determines if an expression that changes a variable can be
+// evaluated before the statement that it is in, once the operands that read
+// the variable before it have copied it, which only variables can
+func isHoistableAfterCopies(node *sitter.Node, assigned string, source []byte) bool {
+	return evaluatedFirst(node, func(operand *sitter.Node) bool {
+		return isSnapshottable(operand, assigned, source)
+	})
+}
+
+// evaluatedFirst determines if an expression is evaluated before anything in
+// the statement that it is in, other than the operands that are unaffected by
+// it, as determined by the given function
+func evaluatedFirst(node *sitter.Node, unaffected func(operand *sitter.Node) bool) bool {
+	for child, parent := node, node.Parent(); parent != nil; child, parent = parent, parent.Parent() {
+		switch parent.Type() {
+		case "expression_statement", "return_statement":
+			return true
+		case "lambda_expression":
+			return child.Equal(parent.ChildByFieldName("body"))
+		case "if_statement", "while_statement", "do_statement", "for_statement":
+			return child.Equal(parent.ChildByFieldName("condition"))
+		case "variable_declarator":
+			// Any earlier declaration in the same statement is evaluated first
+			return parent.PrevNamedSibling() == nil || parent.PrevNamedSibling().Type() != "variable_declarator"
+		case "parenthesized_expression", "unary_expression", "cast_expression", "instanceof_expression", "field_access":
+		case "binary_expression":
+			if !child.Equal(parent.ChildByFieldName("left")) && !unaffected(parent.ChildByFieldName("left")) {
+				return false
+			}
+			switch parent.ChildByFieldName("operator").Type() {
+			case "&&", "||":
+				if !child.Equal(parent.ChildByFieldName("left")) {
+					return false
+				}
+			}
+		case "array_access":
+			if !child.Equal(parent.ChildByFieldName("array")) && !unaffected(parent.ChildByFieldName("array")) {
+				return false
+			}
+		case "argument_list":
+			for previous := child.PrevNamedSibling(); previous != nil; previous = previous.PrevNamedSibling() {
+				if !unaffected(previous) {
+					return false
+				}
+			}
+		case "method_invocation":
+			if object := parent.ChildByFieldName("object"); object != nil && !child.Equal(object) && !unaffected(object) {
+				return false
+			}
+		case "ternary_expression":
+			if !child.Equal(parent.ChildByFieldName("condition")) {
+				return false
+			}
+		case "assignment_expression":
+			// Only a plain assignment doesn't read its variable before its value,
+			// such as the first assignment of `x = y = z`
+			left := parent.ChildByFieldName("left")
+			if parent.ChildByFieldName("operator").Type() != "=" || left.Type() != "identifier" && !unaffected(left) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// isUnaffectedBy determines if evaluating an expression has no side effects,
+// and gives the same result before and after a variable is assigned to
+func isUnaffectedBy(node *sitter.Node, assigned string, source []byte) bool {
+	switch node.Type() {
+	case "decimal_integer_literal", "hex_integer_literal", "octal_integer_literal", "binary_integer_literal",
+		"decimal_floating_point_literal", "hex_floating_point_literal", "string_literal", "character_literal",
+		"true", "false", "null_literal", "this":
+		return true
+	case "identifier":
+		return node.Content(source) != assigned
+	case "field_access":
+		return node.ChildByFieldName("field").Content(source) != assigned &&
+			isUnaffectedBy(node.ChildByFieldName("object"), assigned, source)
+	case "parenthesized_expression":
+		return isUnaffectedBy(node.NamedChild(0), assigned, source)
+	}
+	return false
+}
+
+// isSnapshottable determines if an operand only reads variables, so that the
+// variable that is assigned to can be copied before it is read, if it is read
+func isSnapshottable(node *sitter.Node, assigned string, source []byte) bool {
+	switch node.Type() {
+	case "identifier":
+		return true
+	case "parenthesized_expression":
+		return isSnapshottable(node.NamedChild(0), assigned, source)
+	case "cast_expression":
+		return isSnapshottable(node.ChildByFieldName("value"), assigned, source)
+	case "unary_expression":
+		return isSnapshottable(node.ChildByFieldName("operand"), assigned, source)
+	case "binary_expression":
+		switch node.ChildByFieldName("operator").Type() {
+		case "&&", "||":
+			return isUnaffectedBy(node, assigned, source)
+		}
+		return isSnapshottable(node.ChildByFieldName("left"), assigned, source) &&
+			isSnapshottable(node.ChildByFieldName("right"), assigned, source)
+	}
+	return isUnaffectedBy(node, assigned, source)
+}
+
+// isStatementRoot determines if a node contains everything that is evaluated
+// along with the expressions in it
+func isStatementRoot(node *sitter.Node) bool {
+	switch node.Type() {
+	case "expression_statement", "return_statement", "local_variable_declaration", "field_declaration",
+		"if_statement", "while_statement", "do_statement", "for_statement", "lambda_expression":
+		return true
+	}
+	return false
+}
+
+// changedVariable returns the name of the variable that an assignment or an
+// update changes, if it is hoisted before its statement when it is used as a
+// value, or an empty string for anything else
+func changedVariable(node *sitter.Node, source []byte) string {
+	switch node.Type() {
+	case "assignment_expression":
+		if left := node.ChildByFieldName("left"); isReevaluable(left, source) {
+			return assignedName(left, source)
+		}
+	case "update_expression":
+		if operand := node.NamedChild(0); operand.Type() == "identifier" {
+			return operand.Content(source)
+		}
+	}
+	return ""
+}
+
+// isChangedLater determines if a variable that is read is changed later on in
+// the same expression, by an assignment or update that is hoisted before the
+// statement, so that the variable has to be copied before then
+func isChangedLater(read *sitter.Node, source []byte) bool {
+	root := read
+	for root.Parent() != nil && !isStatementRoot(root) {
+		root = root.Parent()
+	}
+	name := read.Content(source)
+
+	var changed func(node *sitter.Node) bool
+	changed = func(node *sitter.Node) bool {
+		if node.StartByte() >= read.EndByte() && changedVariable(node, source) == name {
+			var readBefore bool
+			hoistable := evaluatedFirst(node, func(operand *sitter.Node) bool {
+				if !isSnapshottable(operand, name, source) {
+					return false
+				}
+				readBefore = readBefore || operand.StartByte() <= read.StartByte() && read.EndByte() <= operand.EndByte()
+				return true
+			})
+			if hoistable && readBefore {
+				return true
+			}
+		}
+		for _, child := range nodeutil.NamedChildrenOf(node) {
+			if changed(child) {
+				return true
+			}
+		}
+		return false
+	}
+	return changed(root)
+}
+
+// snapshot copies the value of a variable before the statements that are
+// hoisted after it change it, and returns the copy
+//
+// Ex: `x` becomes `xOld`, after `xOld := x`
+func (h *hoistedStatements) snapshot(name string, value ast.Expr) ast.Expr {
+	copied := &ast.Ident{Name: h.names.Allocate(name + "Old")}
+	h.hoist(&ast.AssignStmt{Lhs: []ast.Expr{copied}, Tok: token.DEFINE, Rhs: []ast.Expr{value}})
+	return copied
+}
+
+// reportReadBefore reports an assignment or update that is made by the
+// runtime, after the variable that it changes is read by an operand that is
+// evaluated before it, which Go may read after the change instead
+func reportReadBefore(node *sitter.Node, assigned string, source []byte, ctx Ctx) {
+	for _, operand := range earlierOperands(node) {
+		if readsVariable(operand, assigned, source) {
+			ctx.diagnostics.Report(node, evaluationOrderDiagnostic,
+				"`%s` changes `%s` after it is read in the same expression, which Go may read after the change instead", node.Content(source), assigned)
+			return
+		}
+	}
+}
+
+// earlierOperands returns the operands of the statement that an expression is
+// in, which are evaluated before it, other than the left sides of `&&` and
+// `||`, which are evaluated before the right side even starts
+func earlierOperands(node *sitter.Node) []*sitter.Node {
+	var earlier []*sitter.Node
+	for child, parent := node, node.Parent(); parent != nil && !isStatementRoot(child); child, parent = parent, parent.Parent() {
+		switch parent.Type() {
+		case "binary_expression":
+			switch parent.ChildByFieldName("operator").Type() {
+			case "&&", "||":
+				continue
+			}
+			if !child.Equal(parent.ChildByFieldName("left")) {
+				earlier = append(earlier, parent.ChildByFieldName("left"))
+			}
+		case "argument_list":
+			for previous := child.PrevNamedSibling(); previous != nil; previous = previous.PrevNamedSibling() {
+				earlier = append(earlier, previous)
+			}
+		case "array_access":
+			if !child.Equal(parent.ChildByFieldName("array")) {
+				earlier = append(earlier, parent.ChildByFieldName("array"))
+			}
+		case "method_invocation":
+			if object := parent.ChildByFieldName("object"); object != nil && !child.Equal(object) {
+				earlier = append(earlier, object)
+			}
+		}
+	}
+	return earlier
+}
+
+// readsVariable determines if an expression reads a variable by its name
+func readsVariable(node *sitter.Node, name string, source []byte) bool {
+	if node.Type() == "identifier" {
+		return node.Content(source) == name
+	}
+	for _, child := range nodeutil.NamedChildrenOf(node) {
+		if readsVariable(child, name, source) {
+			return true
+		}
+	}
+	return false
+}
+
+// hoistedUpdate translates an update that is used as a value, after an operand
+// that reads its variable, by hoisting the update before the statement, along
+// with copies of the variable for the operands, or returns nil for any other
+// update, which is made by the runtime instead
+//
+// Ex: `int y = x + x++;` becomes `xOld := x; xOld2 := x; x++; y := xOld + xOld2`
+func hoistedUpdate(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	name := changedVariable(node, source)
+	if name == "" {
+		return nil
+	}
+	if ctx.hoisted == nil || isEvaluatedFirst(node, name, source) || !isHoistableAfterCopies(node, name, source) {
+		reportReadBefore(node, name, source, ctx)
+		return nil
+	}
+
+	readCtx := ctx
+	readCtx.hoisted = nil
+	variable := ParseExpr(node.NamedChild(0), source, readCtx)
+	// The value of a post-update is the value from before it
+	if node.Child(0).IsNamed() {
+		variable = ctx.hoisted.snapshot(name, variable)
+	}
+	ctx.hoisted.hoist(parseStmtNode(node, source, ctx))
+	return variable
+}
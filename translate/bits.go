@@ -0,0 +1,413 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"math/big"
+	"strconv"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/NickyBoy89/java2go/codegen"
+)
+
+// Java promotes the operands of an arithmetic or bitwise operator to an `int`,
+// or to a `long` if either of them is one, while Go requires both of them to
+// already have the same type. The operands that have a narrower type are
+// converted, so that shift and mask heavy code keeps its 64-bit semantics:
+//
+//	long masked = value & 0xFFFFFFFFL;        masked := value & int64(0xFFFFFFFF)
+//	long flags = flags | (1L << bit);          flags = flags | (int64(1) << bit)
+//	long widened = count + total;             widened := int64(count) + total
+//	int low = b & 0xFF;                       low := int32(b) & 0xFF
+//
+// The count of a shift is masked by Java to the width of the shifted value,
+// which is done for the counts that are constants, and every other count in
+// strict mode, or with the JavaArithmetic option, since Go shifts everything
+// out instead
+
+// The Go types of Java's integral primitives, where a `char` is a rune, which
+// is the same type as an `int32`
+var integralGoTypes = map[string]string{
+	"byte":  "byte",
+	"short": "int16",
+	"char":  "rune",
+	"int":   "int32",
+	"long":  "int64",
+}
+
+// sameIntegralType determines if two integral Java types are the same type in Go
+func sameIntegralType(a, b string) bool {
+	underlying := func(javaType string) string {
+		if javaType == "char" {
+			return "int"
+		}
+		return javaType
+	}
+	return underlying(a) == underlying(b)
+}
+
+// widened converts a value of an integral Java type to a wider Go type. A
+// `byte` is signed in Java, but not in Go, so it is converted through an
+// `int8` first, which extends its sign the same way as Java
+//
+// Ex: `b` becomes `int32(int8(b))` for a byte
+func widened(goType, javaType string, expr ast.Expr) ast.Expr {
+	if javaType == "byte" {
+		expr = convertTo("int8", expr)
+	}
+	return convertTo(goType, expr)
+}
+
+// integerLiteral translates an integer literal in any base
+//
+// A `long` literal is converted to an `int64`, since Go's constants don't have
+// a type of their own. A hex, octal, or binary `int` literal that sets the sign
+// bit is negative in Java, so it is written as the negative number instead
+//
+// Ex: `0xFFFFFFFFL` becomes `int64(0xFFFFFFFF)`, and `0x80000000` becomes
+// `-2147483648`
+func integerLiteral(literal string) ast.Expr {
+	long := strings.HasSuffix(strings.ToLower(literal), "l")
+	literal = strings.TrimRight(literal, "lL")
+	// Go only allows single underscores between the digits
+	for strings.Contains(literal, "__") {
+		literal = strings.ReplaceAll(literal, "__", "_")
+	}
+
+	var value ast.Expr = &ast.Ident{Name: literal}
+	bits := 32
+	if long {
+		bits = 64
+	}
+	// A decimal literal can't set the sign bit, other than the one that is
+	// negated to the smallest value
+	if decimal := literal == "0" || !strings.HasPrefix(literal, "0"); !decimal {
+		if number, ok := new(big.Int).SetString(literal, 0); ok && number.BitLen() == bits {
+			number.Sub(number, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+			value = &ast.UnaryExpr{Op: token.SUB, X: &ast.BasicLit{Kind: token.INT, Value: number.Neg(number).String()}}
+		}
+	}
+	if long {
+		return convertTo("int64", value)
+	}
+	return value
+}
+
+// convertTo converts an expression to a Go type, unless it already is a
+// conversion to that type
+func convertTo(goType string, expr ast.Expr) ast.Expr {
+	if call, ok := expr.(*ast.CallExpr); ok && len(call.Args) == 1 {
+		if fun, ok := call.Fun.(*ast.Ident); ok && fun.Name == goType {
+			return expr
+		}
+	}
+	return &ast.CallExpr{Fun: &ast.Ident{Name: goType}, Args: []ast.Expr{expr}}
+}
+
+// isUntypedConstant determines if an expression is an integer constant that
+// Go doesn't give a type to, which takes on the type of the expression that it
+// is used in, instead of needing to be converted
+func isUntypedConstant(node *sitter.Node, source []byte) bool {
+	switch node.Type() {
+	case "decimal_integer_literal", "hex_integer_literal", "octal_integer_literal", "binary_integer_literal":
+		return !strings.HasSuffix(strings.ToLower(node.Content(source)), "l")
+	case "character_literal":
+		return true
+	case "parenthesized_expression":
+		return isUntypedConstant(node.NamedChild(0), source)
+	case "unary_expression":
+		return isUntypedConstant(node.ChildByFieldName("operand"), source)
+	case "binary_expression":
+		return isUntypedConstant(node.ChildByFieldName("left"), source) && isUntypedConstant(node.ChildByFieldName("right"), source)
+	}
+	return false
+}
+
+// promotedType returns the type that Java promotes an integral type to when it
+// is used in an arithmetic or bitwise operation
+func promotedType(javaType string) string {
+	switch javaType {
+	case "":
+		return ""
+	case "long":
+		return "long"
+	}
+	return "int"
+}
+
+// integralType returns the Java type of an expression that results in one of
+// the integral primitives, or an empty string if it results in anything else,
+// or its type isn't known
+func integralType(node *sitter.Node, ctx Ctx, source []byte) string {
+	switch node.Type() {
+	case "decimal_integer_literal", "hex_integer_literal", "octal_integer_literal", "binary_integer_literal", "character_literal":
+		javaType, _ := literalJavaType(node, source)
+		return javaType
+	case "parenthesized_expression":
+		return integralType(node.NamedChild(0), ctx, source)
+	case "unary_expression":
+		if node.ChildByFieldName("operator").Type() == "!" {
+			return ""
+		}
+		return promotedType(integralType(node.ChildByFieldName("operand"), ctx, source))
+	case "update_expression":
+		return integralType(node.NamedChild(0), ctx, source)
+	case "assignment_expression":
+		return integralType(node.ChildByFieldName("left"), ctx, source)
+	case "cast_expression":
+		if javaType := node.ChildByFieldName("type").Content(source); integralGoTypes[javaType] != "" {
+			return javaType
+		}
+		return ""
+	case "binary_expression":
+		left, right := node.ChildByFieldName("left"), node.ChildByFieldName("right")
+		switch node.ChildByFieldName("operator").Type() {
+		case "<<", ">>", ">>>":
+			return promotedType(integralType(left, ctx, source))
+		case "+", "-", "*", "/", "%", "&", "|", "^":
+			return binaryPromotion(integralType(left, ctx, source), integralType(right, ctx, source))
+		}
+		return ""
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	if !ok {
+		return ""
+	}
+	// The wrapper classes are left alone, since their values are pointers
+	javaType = stripJavaQualifier(javaType)
+	if integralGoTypes[javaType] == "" {
+		return ""
+	}
+	return javaType
+}
+
+// binaryPromotion returns the type that Java promotes the operands of an
+// arithmetic or bitwise operation to, or an empty string if either of them
+// isn't known to be integral
+func binaryPromotion(left, right string) string {
+	if left == "" || right == "" {
+		return ""
+	}
+	if left == "long" || right == "long" {
+		return "long"
+	}
+	return "int"
+}
+
+// promoteOperand converts an operand of an arithmetic, bitwise, or comparison
+// operation to the type that Java promotes it to, if it doesn't already have
+// that type in Go
+func promoteOperand(node *sitter.Node, expr ast.Expr, promoted string, ctx Ctx, source []byte) ast.Expr {
+	javaType := integralType(node, ctx, source)
+	if javaType == "" || sameIntegralType(javaType, promoted) || isUntypedConstant(node, source) {
+		return expr
+	}
+	return widened(integralGoTypes[promoted], javaType, expr)
+}
+
+// integralArithmetic translates an arithmetic, bitwise, comparison, or shift
+// operation on integral operands that Go would compile differently, or
+// doesn't compile at all, or returns nil for any other operation
+func integralArithmetic(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	operator := node.ChildByFieldName("operator").Type()
+	left, right := node.ChildByFieldName("left"), node.ChildByFieldName("right")
+
+	if JavaArithmetic {
+		if constant := wrappedConstant(node, source); constant != nil {
+			return constant
+		}
+		if promoted := promotedNarrowOperands(node, operator, source, ctx); promoted != nil {
+			return promoted
+		}
+	}
+
+	switch operator {
+	case "<<", ">>", ">>>":
+		return shift(operator, left, right, source, ctx)
+	case "+", "-", "*", "/", "%", "&", "|", "^", "==", "!=", "<", "<=", ">", ">=":
+	default:
+		return nil
+	}
+	leftType, rightType := integralType(left, ctx, source), integralType(right, ctx, source)
+	promoted := binaryPromotion(leftType, rightType)
+	// The operands that already have the same type don't need to be converted
+	if promoted == "" || sameIntegralType(leftType, rightType) {
+		return nil
+	}
+	return &ast.BinaryExpr{
+		X:  promoteOperand(left, ParseExpr(left, source, ctx), promoted, ctx, source),
+		Op: codegen.StrToToken(operator),
+		Y:  promoteOperand(right, ParseExpr(right, source, ctx), promoted, ctx, source),
+	}
+}
+
+// shift translates a shift, whose count Java masks to the width of the value
+// that is shifted
+//
+// A value that is an untyped constant is converted to an `int`, since Go would
+// give it the type `int` instead
+//
+// Ex: `1 << 33` becomes `1 << 1`, and `1 << bit` becomes `int32(1) << bit`
+func shift(operator string, left, right *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	shifted, count := ParseExpr(left, source, ctx), ParseExpr(right, source, ctx)
+	leftType := integralType(left, ctx, source)
+	promoted := promotedType(leftType)
+	if promoted == "" {
+		// The width of the value isn't known
+		if operator == ">>>" {
+			return runtimeCall("UnsignedRightShift", shifted, count)
+		}
+		return &ast.BinaryExpr{X: shifted, Op: codegen.StrToToken(operator), Y: count}
+	}
+
+	// The unsigned type already has the width of the promoted type
+	if operator == ">>>" && JavaArithmetic {
+		count = maskedShiftCount(right, count, promoted, source)
+		return convertTo(integralGoTypes[promoted], unsignedShift(shifted, count, promoted, leftType, left, source))
+	}
+
+	// The runtime's function would give the constant the type `int` as well
+	constant := isUntypedConstant(left, source)
+	if !sameIntegralType(leftType, promoted) || constant && (operator == ">>>" || !isUntypedConstant(right, source)) {
+		shifted = widened(integralGoTypes[promoted], leftType, shifted)
+	}
+
+	count = maskedShiftCount(right, count, promoted, source)
+	if operator == ">>>" {
+		return runtimeCall("UnsignedRightShift", shifted, count)
+	}
+	return &ast.BinaryExpr{X: shifted, Op: codegen.StrToToken(operator), Y: count}
+}
+
+// maskedShiftCount masks the count of a shift to the width of the given
+// promoted type, if it is a constant outside of it, or in strict mode, or with
+// the JavaArithmetic option, if it isn't a constant
+//
+// Ex: `33` becomes `1`, and `bit` becomes `(bit & 31)`
+func maskedShiftCount(node *sitter.Node, count ast.Expr, promoted string, source []byte) ast.Expr {
+	width := int64(32)
+	if promoted == "long" {
+		width = 64
+	}
+	if value, ok := constantValue(node, source); ok {
+		if value < 0 || value >= width {
+			return &ast.BasicLit{Kind: token.INT, Value: strconv.FormatInt(value&(width-1), 10)}
+		}
+	} else if StrictMode || JavaArithmetic {
+		return &ast.ParenExpr{X: &ast.BinaryExpr{X: count, Op: token.AND, Y: &ast.BasicLit{Kind: token.INT, Value: strconv.FormatInt(width-1, 10)}}}
+	}
+	return count
+}
+
+// constantValue returns the value of an integer literal, which may be negated
+// or in parentheses
+func constantValue(node *sitter.Node, source []byte) (int64, bool) {
+	switch node.Type() {
+	case "decimal_integer_literal", "hex_integer_literal", "octal_integer_literal", "binary_integer_literal":
+		literal := strings.ReplaceAll(strings.TrimRight(node.Content(source), "lL"), "_", "")
+		value, ok := new(big.Int).SetString(literal, 0)
+		if !ok || !value.IsInt64() {
+			return 0, false
+		}
+		return value.Int64(), true
+	case "parenthesized_expression":
+		return constantValue(node.NamedChild(0), source)
+	case "unary_expression":
+		if node.ChildByFieldName("operator").Type() != "-" {
+			return 0, false
+		}
+		value, ok := constantValue(node.ChildByFieldName("operand"), source)
+		return -value, ok
+	}
+	return 0, false
+}
+
+// integralAssignment translates an assignment of an integral value to a
+// variable of a different integral type, which Java converts implicitly, or
+// returns nil for any other assignment
+//
+// A compound assignment is the same as the operation, narrowed back to the
+// type of the variable, which only needs the operation itself to be widened
+// for a division or remainder, whose result depends on the width. The count of
+// a shift can have any type, but is masked the same as in the operation
+//
+// Ex: `total += count` becomes `total += int64(count)`, and `count /= total`
+// becomes `count = int32(int64(count) / total)`
+func integralAssignment(node *sitter.Node, variable, value ast.Expr, source []byte, ctx Ctx) ast.Stmt {
+	left, right := node.ChildByFieldName("left"), node.ChildByFieldName("right")
+	leftType, rightType := integralType(left, ctx, source), integralType(right, ctx, source)
+	operator := node.ChildByFieldName("operator").Content(source)
+	switch operator {
+	case "<<=", ">>=":
+		if leftType == "" {
+			return nil
+		}
+		return &ast.AssignStmt{
+			Lhs: []ast.Expr{variable},
+			Tok: codegen.StrToToken(operator),
+			Rhs: []ast.Expr{maskedShiftCount(right, value, promotedType(leftType), source)},
+		}
+	case ">>>=":
+		return nil
+	}
+	if leftType == "" || rightType == "" || sameIntegralType(leftType, rightType) || isUntypedConstant(right, source) {
+		return nil
+	}
+
+	switch operator {
+	case "/=", "%=":
+		if promoted := binaryPromotion(leftType, rightType); !sameIntegralType(promoted, leftType) {
+			operation := &ast.BinaryExpr{
+				X:  convertTo(integralGoTypes[promoted], variable),
+				Op: codegen.StrToToken(operator[:1]),
+				Y:  value,
+			}
+			return &ast.AssignStmt{
+				Lhs: []ast.Expr{variable},
+				Tok: token.ASSIGN,
+				Rhs: []ast.Expr{convertTo(integralGoTypes[leftType], operation)},
+			}
+		}
+	}
+	return &ast.AssignStmt{
+		Lhs: []ast.Expr{variable},
+		Tok: codegen.StrToToken(operator),
+		Rhs: []ast.Expr{convertTo(integralGoTypes[leftType], value)},
+	}
+}
+
+// isPrimitiveCast determines if a cast converts a primitive to another
+// primitive, which is a conversion in Go, instead of a type assertion
+//
+// Ex: `(int) (value >>> 32)` becomes `int32(stdjava.UnsignedRightShift(value, 32))`
+func isPrimitiveCast(castType, value *sitter.Node, ctx Ctx, source []byte) bool {
+	switch castType.Type() {
+	case "integral_type", "floating_point_type":
+		return integralType(value, ctx, source) != "" || isFloatingExpr(value, ctx, source)
+	}
+	return false
+}
+
+// widenedValue converts a value that is stored into a `long` variable, or
+// returned from a method that returns a `long`, from the narrower integral
+// type that it has, which Java widens implicitly, after boxing or unboxing it
+// for the type that it is stored as
+//
+// Ex: `long total = count;` becomes `total := int64(count)`
+func widenedValue(node *sitter.Node, expr ast.Expr, javaType string, ctx Ctx, source []byte) ast.Expr {
+	expr = autoboxed(node, expr, javaType, ctx, source)
+	if stripJavaQualifier(javaType) != "long" || isUntypedConstant(node, source) {
+		return expr
+	}
+	valueType := integralType(node, ctx, source)
+	// An unboxed value has the type that its wrapper class boxes
+	if javaType, ok := inferExprJavaType(node, ctx, source); ok && valueType == "" && isBoxValue(node, ctx, source) {
+		valueType = unboxedJavaType(javaType)
+	}
+	if valueType != "" && valueType != "long" {
+		return convertTo("int64", expr)
+	}
+	return expr
+}
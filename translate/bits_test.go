@@ -0,0 +1,80 @@
+package translate
+
+import (
+	"bytes"
+	"go/printer"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+// This tests the shift and mask idioms of code that mixes ints and longs
+func TestBitFlags(t *testing.T) {
+	var generated bytes.Buffer
+	err := printer.Fprint(&generated, token.NewFileSet(), ParseAst("../testfiles/BitFlags.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := normalizeSpaces(generated.String())
+
+	for _, expected := range []string{
+		"flags |= int64(1) << bit",
+		"return int32(flags & LOW_MASK)",
+		"return int32(stdjava.UnsignedRightShift(flags, 32))",
+		"return (int64(high) << 32) | (int64(low) & int64(0xFFFFFFFF))",
+		"return int64(value)",
+		"return int32(int8(value)) & 0xFF",
+		"mask := int32(1) << bit",
+		"return mask | -2147483648",
+		"total := int64(0)",
+		"total += int64(count)",
+		"count = int32(int64(count) / size)",
+		"return total*size + int64(count)",
+		"return value << 1",
+		"seed *= int64(-49064778989728563)",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestIntegerLiterals(t *testing.T) {
+	for literal, expected := range map[string]string{
+		"42":                  "42",
+		"42L":                 "int64(42)",
+		"1__000_000":          "1_000_000",
+		"0x7FFFFFFF":          "0x7FFFFFFF",
+		"0xFFFFFFFF":          "-1",
+		"0xFFFFFFFFL":         "int64(0xFFFFFFFF)",
+		"0x8000000000000000L": "int64(-9223372036854775808)",
+		"0b1010":              "0b1010",
+		"017":                 "017",
+		"037777777777":        "-1",
+	} {
+		var generated bytes.Buffer
+		if err := printer.Fprint(&generated, token.NewFileSet(), integerLiteral(literal)); err != nil {
+			t.Fatal(err)
+		}
+		if generated.String() != expected {
+			t.Errorf("Expected %s to become %s, got %s", literal, expected, generated.String())
+		}
+	}
+}
+
+func TestStrictShiftCount(t *testing.T) {
+	StrictMode = true
+	defer func() { StrictMode = false }()
+
+	out := normalizeSpaces(renderGoFileFromJava(t, `
+package app.shifts;
+public class Shifts {
+    long rotate(long value, int distance) {
+        return (value << distance) | (value >>> -distance);
+    }
+}
+`))
+	if !strings.Contains(out, "(value << (distance & 63)) | (stdjava.UnsignedRightShift(value, (-distance & 63)))") {
+		t.Errorf("Expected the counts of the shifts to be masked, got:\n%s", out)
+	}
+}
@@ -0,0 +1,322 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/NickyBoy89/java2go/astutil"
+	"github.com/NickyBoy89/java2go/codegen"
+	"github.com/NickyBoy89/java2go/nodeutil"
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported when two boxed values are compared with `==`
+const boxedIdentityDiagnostic = "boxed-identity"
+
+// Java's wrapper classes, which are automatically unboxed into primitives, and
+// the Go types of the primitives that they box
+var boxedTypes = astutil.BoxedPrimitives
+
+// isBoxedType determines if a Java type is one of the wrapper classes
+func isBoxedType(javaType string) bool {
+	_, boxed := boxedTypes[stripJavaQualifier(javaType)]
+	return boxed
+}
+
+// isBoxedExpr determines if the given expression evaluates to a wrapper class
+func isBoxedExpr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	switch node.Type() {
+	case "parenthesized_expression":
+		return isBoxedExpr(node.NamedChild(0), ctx, source)
+	case "cast_expression":
+		return isBoxedType(node.ChildByFieldName("type").Content(source))
+	case "method_invocation":
+		object := node.ChildByFieldName("object")
+		return object != nil && node.ChildByFieldName("name").Content(source) == "valueOf" &&
+			isBoxedType(object.Content(source))
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	return ok && isBoxedType(javaType)
+}
+
+// boxedComparison translates a comparison between two boxed values
+//
+// Whether two boxes are the same object depends on the values being cached by
+// Java, so in strict mode, the boxes are compared as pointers, which keeps this
+// behavior, since the runtime caches boxes in the same way. Otherwise, the
+// values inside the boxes are compared, since that is what is usually intended,
+// by the runtime, since comparing a null box doesn't throw in Java
+func boxedComparison(node *sitter.Node, op token.Token, left, right ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	if StrictMode {
+		return &ast.BinaryExpr{X: left, Op: op, Y: right}
+	}
+	ctx.diagnostics.Report(node, boxedIdentityDiagnostic,
+		"`%s` compares the identity of two boxed values in Java, which depends on the values being cached, but the generated Go compares the values themselves",
+		node.Content(source))
+	if op == token.NEQ {
+		return &ast.UnaryExpr{Op: token.NOT, X: runtimeCall("BoxedEquals", left, right)}
+	}
+	return runtimeCall("BoxedEquals", left, right)
+}
+
+// boxValue translates a wrapper class's `valueOf` method into boxing the value
+// with the runtime, so that boxes are cached the same way that they are in Java
+//
+// Ex: `Integer.valueOf(10)` turns into `stdjava.Box[int32](10)`
+func boxValue(wrapperClass string, value ast.Expr) ast.Expr {
+	return &ast.CallExpr{
+		Fun: &ast.IndexExpr{
+			X: &ast.SelectorExpr{
+				X:   &ast.Ident{Name: "stdjava"},
+				Sel: &ast.Ident{Name: "Box"},
+			},
+			Index: &ast.Ident{Name: boxedTypes[stripJavaQualifier(wrapperClass)]},
+		},
+		Args: []ast.Expr{value},
+	}
+}
+
+// The methods of the wrapper classes that return the value of a box as a
+// primitive, and the primitive that each of them returns
+var unboxingMethods = map[string]string{
+	"byteValue":    "byte",
+	"shortValue":   "short",
+	"charValue":    "char",
+	"intValue":     "int",
+	"longValue":    "long",
+	"floatValue":   "float",
+	"doubleValue":  "double",
+	"booleanValue": "boolean",
+}
+
+// boxedMethod translates a method of a wrapper class that is called on a box,
+// or returns nil if the method isn't one that returns the box's value
+func boxedMethod(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	objectNode := node.ChildByFieldName("object")
+	if objectNode == nil || len(args) != 0 {
+		return nil
+	}
+	javaType, ok := inferExprJavaType(objectNode, ctx, source)
+	if !ok || !isBoxedType(javaType) {
+		return nil
+	}
+	return unboxingMethod(javaType, node.ChildByFieldName("name").Content(source), unboxedValue(objectNode, object, ctx, source))
+}
+
+// unboxingMethod translates a method of a wrapper class that returns the value
+// of a box as a primitive, from the value that is already unboxed, which is
+// converted if the method returns a different primitive than the one that the
+// wrapper class boxes, or returns nil if the method isn't one of them
+//
+// Ex: `count.longValue()` turns into `int64(*count)`
+func unboxingMethod(wrapperClass, name string, value ast.Expr) ast.Expr {
+	primitive, ok := unboxingMethods[name]
+	if !ok {
+		return nil
+	}
+	if goType := argumentGoTypes[primitive]; goType != boxedTypes[stripJavaQualifier(wrapperClass)] {
+		return &ast.CallExpr{Fun: &ast.Ident{Name: goType}, Args: []ast.Expr{value}}
+	}
+	return value
+}
+
+// A primitive is boxed implicitly in Java wherever it is stored into a wrapper
+// class, and a box is unboxed wherever it is used as a primitive. A box is a
+// pointer to the primitive in Go, so the primitive is boxed by the runtime, and
+// the box is dereferenced, which panics for a null box, the same as Java throws
+// a NullPointerException:
+//
+//	Integer count = 5;        count := stdjava.Box[int32](5)
+//	int total = count + 1;    total := *count + 1
+//	count++;                  count = stdjava.Box[int32](*count + 1)
+//
+// The elements of the collections from Java's standard library are only boxes
+// if they are kept boxed, and neither the variables that range over them, nor
+// the parameters of lambdas, which the functional interfaces unbox, are boxes
+
+// isBoxValue determines if an expression is translated into a box, which is a
+// pointer to the primitive that it boxes
+func isBoxValue(node *sitter.Node, ctx Ctx, source []byte) bool {
+	switch node.Type() {
+	case "parenthesized_expression":
+		return isBoxValue(node.NamedChild(0), ctx, source)
+	case "identifier":
+		if isUnboxedVariable(node, ctx, source) {
+			return false
+		}
+	case "method_invocation":
+		if _, ok := mapMethodJavaType(node, ctx, source); ok {
+			return astutil.BoxedElements
+		}
+		if _, ok := listMethodJavaType(node, ctx, source); ok {
+			return astutil.BoxedElements
+		}
+		if javaType, ok := sortedMethodJavaType(node, ctx, source); ok {
+			return isSortedBox(node, javaType, source)
+		}
+		// `valueOf` boxes its argument, unless it parses a string
+		object := node.ChildByFieldName("object")
+		if object != nil && node.ChildByFieldName("name").Content(source) == "valueOf" && isBoxedType(object.Content(source)) {
+			arguments := node.ChildByFieldName("arguments")
+			return arguments.NamedChildCount() == 1 && !isStringExpr(arguments.NamedChild(0), ctx, source)
+		}
+		javaType, ok := inferExprJavaType(node, ctx, source)
+		return ok && isBoxedType(javaType)
+	}
+	return isBoxedExpr(node, ctx, source)
+}
+
+// isUnboxedVariable determines if a variable ranges over the elements of a
+// collection, or is the parameter of a lambda, whose values aren't boxes even
+// if the variable is declared as a wrapper class
+func isUnboxedVariable(node *sitter.Node, ctx Ctx, source []byte) bool {
+	name := node.Content(source)
+	for parent := node.Parent(); parent != nil; parent = parent.Parent() {
+		switch parent.Type() {
+		case "enhanced_for_statement":
+			if parent.ChildByFieldName("name").Content(source) == name {
+				return !astutil.BoxedElements && hasUnboxedElements(parent.ChildByFieldName("value"), ctx, source)
+			}
+		case "lambda_expression":
+			for _, param := range nodeutil.NamedChildrenOf(parent.ChildByFieldName("parameters")) {
+				if param.Content(source) == name || param.ChildByFieldName("name") != nil && param.ChildByFieldName("name").Content(source) == name {
+					return true
+				}
+			}
+			if parameters := parent.ChildByFieldName("parameters"); parameters.Type() == "identifier" && parameters.Content(source) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasUnboxedElements determines if the elements of a collection that is ranged
+// over are the primitives that they box, which they are for the collections
+// whose elements have the type given by `astutil.ElementType`
+func hasUnboxedElements(node *sitter.Node, ctx Ctx, source []byte) bool {
+	if _, ok := listExprType(node, ctx, source); ok {
+		return true
+	}
+	if _, ok := setExprType(node, ctx, source); ok {
+		return true
+	}
+	return sortedClass(node, ctx, source) != ""
+}
+
+// isPrimitiveValue determines if an expression is translated into a primitive,
+// which includes the result of any operator that isn't a concatenation
+func isPrimitiveValue(node *sitter.Node, ctx Ctx, source []byte) bool {
+	switch node.Type() {
+	case "parenthesized_expression":
+		return isPrimitiveValue(node.NamedChild(0), ctx, source)
+	case "binary_expression", "unary_expression", "update_expression":
+		return !isStringExpr(node, ctx, source)
+	}
+	if isBoxValue(node, ctx, source) {
+		return false
+	}
+	javaType := argumentJavaType(node, ctx, source)
+	return primitiveTypes[javaType] || isBoxedType(javaType)
+}
+
+// unboxedValue dereferences a box that is used as a primitive, or returns the
+// value unchanged if it isn't a box
+func unboxedValue(node *sitter.Node, value ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	if !isBoxValue(node, ctx, source) {
+		return value
+	}
+	// Nothing that evaluates to a box needs parentheses to be dereferenced
+	for paren, ok := value.(*ast.ParenExpr); ok; paren, ok = value.(*ast.ParenExpr) {
+		value = paren.X
+	}
+	return &ast.StarExpr{X: value}
+}
+
+// autoboxed converts a value that is stored into a variable of the given Java
+// type, by boxing a primitive that is stored into a wrapper class, or unboxing
+// a box that is stored into a primitive
+//
+// Ex: `Integer count = total;` becomes `count := stdjava.Box[int32](total)`
+func autoboxed(node *sitter.Node, value ast.Expr, javaType string, ctx Ctx, source []byte) ast.Expr {
+	switch javaType = stripJavaQualifier(javaType); {
+	case isBoxedType(javaType):
+		if isPrimitiveValue(node, ctx, source) {
+			return boxValue(javaType, value)
+		}
+	case primitiveTypes[javaType]:
+		return unboxedValue(node, value, ctx, source)
+	}
+	return value
+}
+
+// boxedArguments converts the arguments of a call to the types of the
+// parameters of the method that is called, if the method is known
+func boxedArguments(method *symbol.Definition, argumentNodes []*sitter.Node, args []ast.Expr, ctx Ctx, source []byte) {
+	if method == nil || len(method.Parameters) != len(args) || len(argumentNodes) != len(args) {
+		return
+	}
+	for ind, param := range method.Parameters {
+		args[ind] = autoboxed(argumentNodes[ind], args[ind], param.OriginalType, ctx, source)
+	}
+}
+
+// boxedAssignment translates an assignment to a box, or of a box to a
+// primitive, or returns nil if the assignment isn't one. A compound assignment
+// to a box boxes the result of the operation
+//
+// Ex: `count += 2` becomes `count = stdjava.Box[int32](*count + 2)`
+func boxedAssignment(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	left, right := node.ChildByFieldName("left"), node.ChildByFieldName("right")
+	operator := node.ChildByFieldName("operator").Content(source)
+	javaType, _ := inferExprJavaType(left, ctx, source)
+	javaType = stripJavaQualifier(javaType)
+
+	switch {
+	case isBoxValue(left, ctx, source) && operator == "=":
+		if !isPrimitiveValue(right, ctx, source) {
+			return nil
+		}
+	case isBoxValue(left, ctx, source):
+		if operator == ">>>=" {
+			return nil
+		}
+		variable := ParseExpr(left, source, ctx)
+		result := &ast.BinaryExpr{
+			X:  &ast.StarExpr{X: variable},
+			Op: codegen.StrToToken(operator[:len(operator)-1]),
+			Y:  unboxedValue(right, ParseExpr(right, source, ctx), ctx, source),
+		}
+		return &ast.AssignStmt{Lhs: []ast.Expr{variable}, Tok: token.ASSIGN, Rhs: []ast.Expr{boxValue(javaType, result)}}
+	case primitiveTypes[javaType] && isBoxValue(right, ctx, source):
+	default:
+		return nil
+	}
+	return &ast.AssignStmt{
+		Lhs: []ast.Expr{ParseExpr(left, source, ctx)},
+		Tok: codegen.StrToToken(operator),
+		Rhs: []ast.Expr{autoboxed(right, ParseExpr(right, source, ctx), javaType, ctx, source)},
+	}
+}
+
+// boxedUpdate translates an increment or a decrement of a box, which boxes
+// the updated value, or returns nil if the updated variable isn't a box
+//
+// Ex: `count++` becomes `count = stdjava.Box[int32](*count + 1)`
+func boxedUpdate(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	variableNode, operator := node.Child(0), node.Child(1).Type()
+	if !variableNode.IsNamed() {
+		variableNode, operator = node.Child(1), node.Child(0).Type()
+	}
+	if !isBoxValue(variableNode, ctx, source) {
+		return nil
+	}
+	javaType, _ := inferExprJavaType(variableNode, ctx, source)
+	variable := ParseExpr(variableNode, source, ctx)
+	result := &ast.BinaryExpr{X: &ast.StarExpr{X: variable}, Op: token.ADD, Y: &ast.BasicLit{Kind: token.INT, Value: "1"}}
+	if operator == "--" {
+		result.Op = token.SUB
+	}
+	return &ast.AssignStmt{Lhs: []ast.Expr{variable}, Tok: token.ASSIGN, Rhs: []ast.Expr{boxValue(javaType, result)}}
+}
@@ -0,0 +1,132 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+const boxedComparisonSource = `
+package boxes.identity;
+public class Counter {
+    Integer count;
+    boolean same(Integer other, int value) {
+        Integer boxed = Integer.valueOf(value);
+        return count == other || boxed != other;
+    }
+}
+`
+
+func TestBoxing_IdiomaticComparesValues(t *testing.T) {
+	helper := setupParseHelper(t, boxedComparisonSource)
+	helper.Ctx.diagnostics = NewDiagnostics("Counter.java")
+	out := renderGoFileFromJavaCtx(t, helper)
+
+	if !strings.Contains(out, "boxed := stdjava.Box[int32](value)") {
+		t.Errorf("Expected valueOf to box through the runtime, got:\n%s", out)
+	}
+	if !strings.Contains(out, "return stdjava.BoxedEquals(count, other) || !stdjava.BoxedEquals(boxed, other)") {
+		t.Errorf("Expected the boxed values to be compared, got:\n%s", out)
+	}
+
+	diagnostics := helper.Ctx.diagnostics.Items()
+	if len(diagnostics) != 2 || diagnostics[0].Kind != boxedIdentityDiagnostic {
+		t.Errorf("Expected a diagnostic for each comparison, got %v", diagnostics)
+	}
+}
+
+func TestBoxing_StrictComparesIdentity(t *testing.T) {
+	StrictMode = true
+	defer func() { StrictMode = false }()
+
+	helper := setupParseHelper(t, boxedComparisonSource)
+	helper.Ctx.diagnostics = NewDiagnostics("Counter.java")
+	out := renderGoFileFromJavaCtx(t, helper)
+
+	if !strings.Contains(out, "return count == other || boxed != other") {
+		t.Errorf("Expected the boxes to be compared as pointers, got:\n%s", out)
+	}
+	if diagnostics := helper.Ctx.diagnostics.Items(); len(diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics in strict mode, got %v", diagnostics)
+	}
+}
+
+func TestBoxing_Autoboxing(t *testing.T) {
+	out := renderGoFileFromJava(t, `
+package boxes.auto;
+import java.util.*;
+public class Tally {
+    Integer total;
+    static Integer wrap(int value) {
+        return value;
+    }
+    static void add(Integer amount, int times) {
+    }
+    int count(Set<Integer> values, Integer limit) {
+        Integer seen = 0;
+        for (Integer value : values) {
+            if (value < limit) {
+                seen++;
+            }
+        }
+        int unboxed = seen;
+        long widened = seen;
+        total = unboxed * 2;
+        total += limit;
+        add(unboxed, seen);
+        Boolean done = seen > limit;
+        while (done) {
+            done = false;
+        }
+        return seen + total;
+    }
+}
+`)
+	for _, expected := range []string{
+		"total *int32",
+		"return stdjava.Box[int32](value)",
+		"func (ty *Tally) count(values stdjava.HashSet[int32], limit *int32) int32 {",
+		"seen := stdjava.Box[int32](0)",
+		"if value < *limit {",
+		"seen = stdjava.Box[int32](*seen + 1)",
+		"unboxed := *seen",
+		"widened := int64(*seen)",
+		"total = stdjava.Box[int32](unboxed * 2)",
+		"total = stdjava.Box[int32](*total + *limit)",
+		"add(stdjava.Box[int32](unboxed), *seen)",
+		"done := stdjava.Box[bool](*seen > *limit)",
+		"for *done {",
+		"done = stdjava.Box[bool](false)",
+		"return *seen + *total",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestBoxing_SortedCollections(t *testing.T) {
+	out := renderGoFileFromJava(t, `
+package boxes.sorted;
+import java.util.*;
+public class Ranks {
+    static int rank(TreeMap<Integer, Integer> ranks, TreeSet<Integer> seen, int v) {
+        Integer first = ranks.firstKey();
+        Integer floor = ranks.floorKey(v);
+        Integer value = ranks.get(v);
+        Integer lowest = seen.first();
+        return first + floor + value + lowest + v;
+    }
+}
+`)
+	for _, expected := range []string{
+		"first := stdjava.Box[int32](ranks.FirstKey())",
+		"floor := ranks.FloorKey(v)",
+		"value := stdjava.Box[int32](ranks.Get(v))",
+		"lowest := stdjava.Box[int32](seen.First())",
+		"return *first + *floor + *value + *lowest + v",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for the methods of a StringBuilder that
+// aren't translated
+const builderDiagnostic = "string-builder"
+
+// A `StringBuilder` or a `StringBuffer` is a `*strings.Builder`, which is
+// appended to with the method for the type of the appended value, and the
+// runtime implements the methods that change the rest of its contents:
+//
+//	StringBuilder sb = new StringBuilder();    sb := new(strings.Builder)
+//	sb.append(name).append(':');               sb.WriteString(name)
+//	                                           sb.WriteRune(':')
+//	sb.insert(0, count);                       stdjava.Insert(sb, 0, fmt.Sprint(count))
+//	return sb.toString();                      return sb.String()
+//
+// The methods that return the builder, to be chained onto, are called as
+// statements, and a chain of them is split into a statement for each. A chain
+// that is used as a value is hoisted out of the statement that it is in, and
+// one on a builder that isn't in a variable, such as
+// `new StringBuilder().append(a).toString()`, puts the builder in one first
+
+// The methods of a StringBuilder that return the builder itself
+var builderChainedMethods = map[string]bool{
+	"append":       true,
+	"insert":       true,
+	"reverse":      true,
+	"delete":       true,
+	"deleteCharAt": true,
+}
+
+// isBuilderType determines if a Java type is a StringBuilder or a StringBuffer
+func isBuilderType(javaType string) bool {
+	switch stripJavaQualifier(javaType) {
+	case "StringBuilder", "StringBuffer":
+		return true
+	}
+	return false
+}
+
+// isBuilderExpr determines if an expression evaluates to a StringBuilder or a
+// StringBuffer
+func isBuilderExpr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	switch node.Type() {
+	case "parenthesized_expression":
+		return isBuilderExpr(node.NamedChild(0), ctx, source)
+	case "object_creation_expression":
+		return isBuilderType(node.ChildByFieldName("type").Content(source))
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	return ok && isBuilderType(javaType)
+}
+
+// builderMethodJavaType returns the Java type of the value that a method of a
+// StringBuilder returns
+func builderMethodJavaType(node *sitter.Node, ctx Ctx, source []byte) (string, bool) {
+	object := node.ChildByFieldName("object")
+	if object == nil || !isBuilderExpr(object, ctx, source) {
+		return "", false
+	}
+	switch name := node.ChildByFieldName("name").Content(source); {
+	case builderChainedMethods[name]:
+		return "StringBuilder", true
+	case name == "toString" || name == "substring":
+		return "String", true
+	case name == "length" || name == "indexOf" || name == "lastIndexOf":
+		return "int", true
+	case name == "charAt":
+		return "char", true
+	case name == "isEmpty":
+		return "boolean", true
+	}
+	return "", false
+}
+
+// reversedString returns the String that is reversed by
+// `new StringBuilder(s).reverse().toString()`, or nil if the expression isn't
+// that, which is translated into the runtime's `Reverse`
+func reversedString(node *sitter.Node, ctx Ctx, source []byte) *sitter.Node {
+	if node == nil || node.Type() != "method_invocation" || node.ChildByFieldName("name").Content(source) != "toString" {
+		return nil
+	}
+	reversed := node.ChildByFieldName("object")
+	if reversed == nil || reversed.Type() != "method_invocation" || reversed.ChildByFieldName("name").Content(source) != "reverse" {
+		return nil
+	}
+	builder := reversed.ChildByFieldName("object")
+	if builder == nil || builder.Type() != "object_creation_expression" || !isBuilderExpr(builder, ctx, source) {
+		return nil
+	}
+	if arguments := nodeutil.NamedChildrenOf(builder.ChildByFieldName("arguments")); len(arguments) == 1 &&
+		isStringExpr(arguments[0], ctx, source) {
+		return arguments[0]
+	}
+	return nil
+}
+
+// newBuilder translates the creation of a StringBuilder, or returns nil if the
+// created object isn't one
+//
+// Ex: `new StringBuilder()` turns into `new(strings.Builder)`, and
+// `new StringBuilder(name)` turns into `stdjava.NewStringBuilder(name)`
+func newBuilder(node *sitter.Node, className string, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	if !isBuilderType(className) || anonymousClassBody(node) != nil {
+		return nil
+	}
+	arguments := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+	// The initial capacity of the builder is ignored
+	if len(args) == 0 || isCapacity(arguments[0], ctx, source) {
+		return &ast.CallExpr{Fun: &ast.Ident{Name: "new"}, Args: []ast.Expr{
+			&ast.SelectorExpr{X: &ast.Ident{Name: "strings"}, Sel: &ast.Ident{Name: "Builder"}},
+		}}
+	}
+	return runtimeCall("NewStringBuilder", builderString(arguments[0], args[0], ctx, source))
+}
+
+// builderString converts a value that is appended to a StringBuilder into a
+// string, the same way as `String.valueOf`
+func builderString(node *sitter.Node, value ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	switch javaType := argumentJavaType(node, ctx, source); {
+	case javaType == "String":
+		return value
+	case isBuilderExpr(node, ctx, source):
+		return &ast.CallExpr{Fun: &ast.SelectorExpr{X: value, Sel: &ast.Ident{Name: "String"}}}
+	case javaType == "char" || javaType == "Character":
+		return &ast.CallExpr{Fun: &ast.Ident{Name: "string"}, Args: []ast.Expr{value}}
+	case isCharArrayExpr(node, ctx, source):
+		return charsToString(value, nil, nil)
+	}
+	if converted := concatenatedOperand(node, value, ctx, source); converted != value {
+		return converted
+	}
+	return &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: &ast.Ident{Name: "fmt"}, Sel: &ast.Ident{Name: "Sprint"}},
+		Args: []ast.Expr{value},
+	}
+}
+
+// appendValue appends a value to a builder, with the method of
+// `strings.Builder` for the value's type, or with `fmt.Fprint` for a number or
+// a boolean, which Go formats the same as Java
+func appendValue(builder ast.Expr, node *sitter.Node, value ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	method := "WriteString"
+	switch converted := builderString(node, value, ctx, source); {
+	case argumentJavaType(node, ctx, source) == "char":
+		method = "WriteRune"
+	case isFmtCall(converted, "Sprint"):
+		return &ast.CallExpr{
+			Fun:  &ast.SelectorExpr{X: &ast.Ident{Name: "fmt"}, Sel: &ast.Ident{Name: "Fprint"}},
+			Args: []ast.Expr{builder, value},
+		}
+	default:
+		value = converted
+	}
+	return &ast.CallExpr{Fun: &ast.SelectorExpr{X: builder, Sel: &ast.Ident{Name: method}}, Args: []ast.Expr{value}}
+}
+
+// isFmtCall determines if an expression is a call to the given function of the
+// fmt package
+func isFmtCall(expr ast.Expr, function string) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && selector.Sel.Name == function && isIdent(selector.X, "fmt")
+}
+
+// isIdent determines if an expression is the identifier with the given name
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+// builderMethod translates a method of a StringBuilder, or returns nil if the
+// method isn't called on one
+func builderMethod(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	objectNode := node.ChildByFieldName("object")
+	if !isBuilderExpr(objectNode, ctx, source) {
+		return nil
+	}
+	name := node.ChildByFieldName("name").Content(source)
+	arguments := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+
+	var called ast.Expr
+	switch {
+	case name == "toString" && len(args) == 0:
+		return &ast.CallExpr{Fun: &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: "String"}}}
+	// The contents are counted and indexed in UTF-16 code units, the same as the
+	// runtime's methods of the builder, which take indices in them
+	case name == "length" && len(args) == 0:
+		return runtimeStringCall("Length", &ast.CallExpr{Fun: &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: "String"}}})
+	case name == "isEmpty" && len(args) == 0:
+		return &ast.BinaryExpr{
+			X:  &ast.CallExpr{Fun: &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: "Len"}}},
+			Op: token.EQL,
+			Y:  &ast.BasicLit{Kind: token.INT, Value: "0"},
+		}
+	// Ex: `sb.charAt(i)` turns into `stdjava.CharAt(sb.String(), i)`
+	case name == "charAt" && len(args) == 1:
+		return runtimeStringCall("CharAt", &ast.CallExpr{Fun: &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: "String"}}}, args[0])
+	// Emptying the builder is the most common use of `setLength`
+	case name == "setLength" && len(args) == 1 && arguments[0].Content(source) == "0":
+		return &ast.CallExpr{Fun: &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: "Reset"}}}
+	case name == "setLength" && len(args) == 1:
+		return runtimeCall("SetLength", object, args[0])
+	case name == "append" && len(args) == 1:
+		called = appendValue(object, arguments[0], args[0], ctx, source)
+	case name == "insert" && len(args) == 2:
+		called = runtimeCall("Insert", object, args[0], builderString(arguments[1], args[1], ctx, source))
+	case name == "reverse" && len(args) == 0:
+		// Reversing a String is translated as a whole
+		if reversedString(node.Parent(), ctx, source) != nil {
+			return nil
+		}
+		called = runtimeCall("ReverseBuilder", object)
+	case name == "delete" && len(args) == 2:
+		called = runtimeCall("Delete", object, args[0], args[1])
+	case name == "deleteCharAt" && len(args) == 1:
+		called = runtimeCall("DeleteCharAt", object, args[0])
+	default:
+		ctx.diagnostics.Report(node, builderDiagnostic, "`%s` isn't translated for a `strings.Builder`", node.Content(source))
+		return nil
+	}
+
+	// A method that returns the builder is only called for what it does, unless
+	// its result is used
+	if node.Parent().Type() == "expression_statement" {
+		return called
+	}
+	if ctx.hoisted == nil || isConditionallyEvaluated(node) {
+		ctx.diagnostics.Report(node, builderDiagnostic,
+			"`%s` is used as a value, which can't be hoisted out of where it is", node.Content(source))
+		return nil
+	}
+	if !isReevaluableExpr(object) {
+		builder := &ast.Ident{Name: ctx.hoisted.names.Allocate("builder")}
+		ctx.hoisted.hoist(&ast.AssignStmt{Lhs: []ast.Expr{builder}, Tok: token.DEFINE, Rhs: []ast.Expr{object}})
+		replaceReceiver(called, object, builder)
+		object = builder
+	}
+	ctx.hoisted.hoist(&ast.ExprStmt{X: called})
+	return object
+}
+
+// isReevaluableExpr determines if a translated expression can be evaluated
+// again, which is a variable, or a field of one
+func isReevaluableExpr(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return true
+	case *ast.SelectorExpr:
+		_, ok := expr.X.(*ast.Ident)
+		return ok
+	}
+	return false
+}
+
+// replaceReceiver replaces the builder that a translated method is called on,
+// which is either the receiver of the call, or its first argument
+func replaceReceiver(called, object, builder ast.Expr) {
+	call := called.(*ast.CallExpr)
+	if selector, ok := call.Fun.(*ast.SelectorExpr); ok && selector.X == object {
+		selector.X = builder
+	}
+	for ind, arg := range call.Args {
+		if arg == object {
+			call.Args[ind] = builder
+		}
+	}
+}
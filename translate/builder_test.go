@@ -0,0 +1,79 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStringBuilders(t *testing.T) {
+	helper := setupParseHelper(t, `
+package util.builder;
+public class Joiner {
+    public static String join(String[] parts, char separator) {
+        StringBuilder sb = new StringBuilder();
+        for (int i = 0; i < parts.length; i++) {
+            if (sb.length() > 0) {
+                sb.append(separator);
+            }
+            sb.append(i).append(": ");
+        }
+        sb.insert(0, '[');
+        return sb.toString();
+    }
+    public static String pair(String key, Joiner value) {
+        return new StringBuilder(key).append(value).toString();
+    }
+    public static String describe(StringBuffer buffer) {
+        buffer.setLength(0);
+        return "contents: " + buffer + buffer.indexOf("a");
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Joiner.java")
+
+	out := renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		"sb := new(strings.Builder)",
+		"if stdjava.Length(sb.String()) > 0 {",
+		"sb.WriteRune(separator)",
+		"fmt.Fprint(sb, i)\n\t\tsb.WriteString(\": \")",
+		"stdjava.Insert(sb, 0, string('['))",
+		"return sb.String()",
+		"builder := stdjava.NewStringBuilder(key)",
+		"builder.WriteString(stdjava.ObjectToString(value))",
+		"return builder.String()",
+		"func Describe(buffer *strings.Builder) string {",
+		"buffer.Reset()",
+		`"contents: " + buffer.String()`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != builderDiagnostic || items[0].Line != 20 {
+		t.Errorf("Expected the untranslated method on line 20 to be reported, got %v", items)
+	}
+}
+
+func TestStringBuildersShareFunctionNames(t *testing.T) {
+	out := renderGoFileFromJava(t, `
+package util.builder;
+public class Pairs {
+    public static String both(String a, String b) {
+        String x = new StringBuilder(a).append(b).toString();
+        String y = new StringBuilder(b).append(a).toString();
+        return x + y;
+    }
+}
+`)
+	for _, expected := range []string{
+		"builder := stdjava.NewStringBuilder(a)\n\tbuilder.WriteString(b)\n\tx := builder.String()",
+		"builder2 := stdjava.NewStringBuilder(b)\n\tbuilder2.WriteString(a)\n\ty := builder2.String()",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// A try statement with catch clauses runs its body in a function literal that
+// recovers from any panic, the same way as a finally block that completes
+// abruptly. The catch clauses then run in the enclosing function, so that they
+// can return, break, or continue the same way as in Java:
+//
+//	{
+//		_tryCompletion, _tryResult, _tryPanic := func() (_completion int, _result T, _thrown any) {
+//			defer func() {
+//				_thrown = recover()
+//			}()
+//			// The body of the try block, with any return, break, or continue
+//			// out of it rewritten to return its completion code
+//			return
+//		}()
+//		if _tryPanic != nil {
+//			if e, ok := stdjava.Catch(_tryPanic, "IOException"); ok {
+//				// The body of the catch clause
+//			} else {
+//				panic(_tryPanic)
+//			}
+//		}
+//		if _tryCompletion == 1 {
+//			return _tryResult
+//		}
+//		// Only if the try statement can't complete normally
+//		panic("unreachable")
+//	}
+
+// catchClausesOf returns the catch clauses of a try statement
+func catchClausesOf(node *sitter.Node) []*sitter.Node {
+	var clauses []*sitter.Node
+	for _, child := range nodeutil.NamedChildrenOf(node) {
+		if child.Type() == "catch_clause" {
+			clauses = append(clauses, child)
+		}
+	}
+	return clauses
+}
+
+// tryCatch translates the body of a try statement, and the catch clauses that
+// handle what is thrown out of it, as described above
+//
+// In the errors mode, the errors of the methods that are called in the body
+// are returned out of the function literal as well
+func tryCatch(node *sitter.Node, parseBody func(Ctx) []ast.Stmt, clauses []*sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	bodyCtx := ctx
+	bodyCtx.errorTarget = tryErrorTarget(ctx)
+	body := parseBody(bodyCtx)
+
+	resultType := methodResultType(ctx)
+	rewriter := &completionRewriter{hasResult: resultType != nil, recovers: true, returnsError: returnsError(ctx)}
+	if bodyCtx.errorTarget != nil {
+		rewriter.kept = bodyCtx.errorTarget.propagations
+	}
+	rewriter.rewriteList(body, false, false, map[string]bool{})
+
+	// A returned error is assigned to the result before the deferred function
+	// runs, so it is only replaced by a panic
+	var recovered ast.Stmt = &ast.AssignStmt{
+		Lhs: []ast.Expr{&ast.Ident{Name: "_thrown"}},
+		Tok: token.ASSIGN,
+		Rhs: []ast.Expr{&ast.CallExpr{Fun: &ast.Ident{Name: "recover"}}},
+	}
+	if ErrorReturns {
+		recovered = &ast.IfStmt{
+			Init: &ast.AssignStmt{
+				Lhs: []ast.Expr{&ast.Ident{Name: "thrown"}},
+				Tok: token.DEFINE,
+				Rhs: []ast.Expr{&ast.CallExpr{Fun: &ast.Ident{Name: "recover"}}},
+			},
+			Cond: &ast.BinaryExpr{X: &ast.Ident{Name: "thrown"}, Op: token.NEQ, Y: &ast.Ident{Name: "nil"}},
+			Body: &ast.BlockStmt{List: []ast.Stmt{&ast.AssignStmt{
+				Lhs: []ast.Expr{&ast.Ident{Name: "_thrown"}},
+				Tok: token.ASSIGN,
+				Rhs: []ast.Expr{&ast.Ident{Name: "thrown"}},
+			}}},
+		}
+	}
+
+	tryBody := append([]ast.Stmt{
+		&ast.DeferStmt{
+			Call: &ast.CallExpr{
+				Fun: &ast.FuncLit{
+					Type: &ast.FuncType{Params: &ast.FieldList{}},
+					Body: &ast.BlockStmt{List: []ast.Stmt{recovered}},
+				},
+			},
+		},
+	}, body...)
+	if !isTerminating(tryBody[len(tryBody)-1]) {
+		tryBody = append(tryBody, &ast.ReturnStmt{})
+	}
+
+	// The completion of the body is only needed if it leaves the statement
+	results := &ast.FieldList{}
+	var captured []ast.Expr
+	if rewriter.returns || len(rewriter.branches) > 0 {
+		results.List = append(results.List, &ast.Field{Names: []*ast.Ident{{Name: "_completion"}}, Type: &ast.Ident{Name: "int"}})
+		captured = append(captured, &ast.Ident{Name: "_tryCompletion"})
+		if resultType != nil {
+			results.List = append(results.List, &ast.Field{Names: []*ast.Ident{{Name: "_result"}}, Type: resultType})
+			if rewriter.returns {
+				captured = append(captured, &ast.Ident{Name: "_tryResult"})
+			} else {
+				captured = append(captured, &ast.Ident{Name: "_"})
+			}
+		}
+	}
+	results.List = append(results.List, &ast.Field{Names: []*ast.Ident{{Name: "_thrown"}}, Type: &ast.Ident{Name: "any"}})
+	captured = append(captured, &ast.Ident{Name: "_tryPanic"})
+
+	lowered := &ast.BlockStmt{List: []ast.Stmt{
+		&ast.AssignStmt{
+			Lhs: captured,
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{&ast.CallExpr{
+				Fun: &ast.FuncLit{
+					Type: &ast.FuncType{Params: &ast.FieldList{}, Results: results},
+					Body: &ast.BlockStmt{List: tryBody},
+				},
+			}},
+		},
+		&ast.IfStmt{
+			Cond: &ast.BinaryExpr{X: &ast.Ident{Name: "_tryPanic"}, Op: token.NEQ, Y: &ast.Ident{Name: "nil"}},
+			Body: &ast.BlockStmt{List: []ast.Stmt{catchDispatch(clauses, source, ctx)}},
+		},
+	}}
+	lowered.List = append(lowered.List, rewriter.completionChecks()...)
+	return endUnreachable(node, lowered, source)
+}
+
+// endUnreachable ends a lowered try statement with a panic, if the statement
+// can't complete normally in Java. The completion checks that it ends with
+// aren't terminating statements in Go, which would otherwise report a missing
+// return at the end of the method
+func endUnreachable(node *sitter.Node, lowered *ast.BlockStmt, source []byte) *ast.BlockStmt {
+	if !canCompleteNormally(node, source) {
+		lowered.List = append(lowered.List, &ast.ExprStmt{X: &ast.CallExpr{
+			Fun:  &ast.Ident{Name: "panic"},
+			Args: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: strconv.Quote("unreachable")}},
+		}})
+	}
+	return lowered
+}
+
+// isTerminating determines if a Go statement is one of the terminating
+// statements that a function with results can end with, without a return after
+// it. Only returns, panics, and blocks and if statements that end with them are
+// recognized
+func isTerminating(stmt ast.Stmt) bool {
+	switch stmt := stmt.(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.ExprStmt:
+		call, ok := stmt.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		name, ok := call.Fun.(*ast.Ident)
+		return ok && name.Name == "panic"
+	case *ast.BlockStmt:
+		return len(stmt.List) > 0 && isTerminating(stmt.List[len(stmt.List)-1])
+	case *ast.IfStmt:
+		return stmt.Else != nil && isTerminating(stmt.Body) && isTerminating(stmt.Else)
+	}
+	return false
+}
+
+// canCompleteNormally determines if a Java statement can run to its end, and
+// continue with the statement after it
+//
+// This follows a simplified version of the rules in the JLS, and assumes that
+// any statement it doesn't recognize can complete normally, so that a panic is
+// never added where it could be reached
+func canCompleteNormally(node *sitter.Node, source []byte) bool {
+	switch node.Type() {
+	case "return_statement", "throw_statement", "break_statement", "continue_statement":
+		return false
+	case "block":
+		for _, child := range nodeutil.NamedChildrenOf(node) {
+			if !canCompleteNormally(child, source) {
+				return false
+			}
+		}
+		return true
+	case "if_statement":
+		alternative := node.ChildByFieldName("alternative")
+		return alternative == nil || canCompleteNormally(node.ChildByFieldName("consequence"), source) || canCompleteNormally(alternative, source)
+	case "while_statement", "for_statement", "do_statement":
+		// A loop that never ends can only be left with a break
+		condition := node.ChildByFieldName("condition")
+		for condition != nil && condition.Type() == "parenthesized_expression" {
+			condition = condition.NamedChild(0)
+		}
+		return condition != nil && condition.Type() != "true" || containsBreak(node.ChildByFieldName("body"))
+	case "synchronized_statement":
+		return canCompleteNormally(node.ChildByFieldName("body"), source)
+	case "try_statement", "try_with_resources_statement":
+		if finally := finallyClauseOf(node); finally != nil && !canCompleteNormally(finally.NamedChild(0), source) {
+			return false
+		}
+		if canCompleteNormally(node.ChildByFieldName("body"), source) {
+			return true
+		}
+		for _, clause := range catchClausesOf(node) {
+			if canCompleteNormally(clause.ChildByFieldName("body"), source) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// containsBreak determines if there is any break statement in a block of Java
+// code, outside of the lambdas and classes that are declared in it
+func containsBreak(node *sitter.Node) bool {
+	switch node.Type() {
+	case "break_statement":
+		return true
+	case "lambda_expression", "class_body":
+		return false
+	}
+	for _, child := range nodeutil.NamedChildrenOf(node) {
+		if containsBreak(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCaughtException determines if an identifier refers to the exception that
+// is caught by one of the catch clauses that it is in
+func isCaughtException(node *sitter.Node, source []byte) bool {
+	for parent := node.Parent(); parent != nil; parent = parent.Parent() {
+		if parent.Type() == "catch_clause" && parent.NamedChild(0).ChildByFieldName("name").Content(source) == node.Content(source) {
+			return true
+		}
+	}
+	return false
+}
+
+// catchDispatch runs the first catch clause that catches the recovered panic,
+// or panics again if none of them do. In the errors mode, what wasn't caught
+// is returned as an error instead, if there is somewhere to return it
+func catchDispatch(clauses []*sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	var dispatch ast.Stmt = &ast.ExprStmt{X: &ast.CallExpr{
+		Fun:  &ast.Ident{Name: "panic"},
+		Args: []ast.Expr{&ast.Ident{Name: "_tryPanic"}},
+	}}
+	if ctx.errorTarget != nil {
+		dispatch = &ast.BlockStmt{List: ctx.errorTarget.propagate(runtimeCall("Thrown", &ast.Ident{Name: "_tryPanic"}))}
+	}
+
+	// The checks never enclose each other, so they can all share a name
+	ok := localVariableNames(ctx).AllocateFor("catch ok", "ok")
+
+	// The chain of checks is built from the last clause to the first
+	for ind := len(clauses) - 1; ind >= 0; ind-- {
+		parameter := clauses[ind].NamedChild(0)
+		var types *sitter.Node
+		for _, child := range nodeutil.NamedChildrenOf(parameter) {
+			if child.Type() == "catch_type" {
+				types = child
+			}
+		}
+		body := ParseStmt(clauses[ind].ChildByFieldName("body"), source, ctx).(*ast.BlockStmt)
+
+		// An exception that is never used can't be declared in Go
+		name := parameter.ChildByFieldName("name").Content(source)
+		if variableUses(body)[name] == 0 {
+			name = "_"
+		}
+
+		// The classes of Java's standard library are caught by their names, and
+		// the exceptions that have a generated type are caught by their types
+		var classes []ast.Expr
+		for _, caught := range nodeutil.NamedChildrenOf(types) {
+			className := stripJavaQualifier(caught.Content(source))
+			if class := resolveClassScopeByName(ctx, className); class != nil {
+				dispatch = catchCheck(name, ok, &ast.TypeAssertExpr{
+					X:    &ast.Ident{Name: "_tryPanic"},
+					Type: &ast.StarExpr{X: &ast.Ident{Name: class.Class.Name}},
+				}, body, dispatch)
+				continue
+			}
+			classes = append(classes, &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(className)})
+		}
+		if len(classes) > 0 {
+			dispatch = catchCheck(name, ok, runtimeCall("Catch", append([]ast.Expr{&ast.Ident{Name: "_tryPanic"}}, classes...)...), body, dispatch)
+		}
+	}
+	return dispatch
+}
+
+// catchCheck runs the body of a catch clause if the exception is caught by
+// the given check, and otherwise runs the rest of the checks. The result of
+// the check is named after the given name
+//
+// Ex: `if e, ok := stdjava.Catch(_tryPanic, "IOException"); ok { ... } else { ... }`
+func catchCheck(name, ok string, check ast.Expr, body *ast.BlockStmt, otherwise ast.Stmt) ast.Stmt {
+	switch otherwise.(type) {
+	case *ast.IfStmt, *ast.BlockStmt:
+	default:
+		otherwise = &ast.BlockStmt{List: []ast.Stmt{otherwise}}
+	}
+	return &ast.IfStmt{
+		Init: &ast.AssignStmt{
+			Lhs: []ast.Expr{&ast.Ident{Name: name}, &ast.Ident{Name: ok}},
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{check},
+		},
+		Cond: &ast.Ident{Name: ok},
+		Body: body,
+		Else: otherwise,
+	}
+}
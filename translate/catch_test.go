@@ -0,0 +1,101 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTryCatch(t *testing.T) {
+	src := `
+package exc.caught;
+public class Caught {
+    public static int parse(String text, String[] items) {
+        try {
+            return Integer.parseInt(text);
+        } catch (NumberFormatException | IllegalStateException e) {
+            log(e.getMessage());
+        } catch (Failure failure) {
+            throw new RuntimeException("failed", failure);
+        } catch (Exception e) {
+            return -1;
+        }
+        for (String item : items) {
+            try {
+                use(item);
+            } catch (RuntimeException ignored) {
+                continue;
+            }
+        }
+        return 0;
+    }
+    public static class Failure extends Exception {}
+}
+`
+	out := renderGoFileFromJava(t, src)
+	for _, expected := range []string{
+		"_tryCompletion, _tryResult, _tryPanic := func() (_completion int, _result int32, _thrown any) {\n\t\t\tdefer func() {\n\t\t\t\t_thrown = recover()\n\t\t\t}()\n\t\t\treturn 1, stdjava.ParseInt(text, 10), nil\n\t\t}()",
+		"if e, ok := stdjava.Catch(_tryPanic, \"NumberFormatException\", \"IllegalStateException\"); ok {\n\t\t\t\tlog(e.GetMessage())",
+		"} else if failure, ok := _tryPanic.(*CaughtFailure); ok {",
+		"} else if _, ok := stdjava.Catch(_tryPanic, \"Exception\"); ok {\n\t\t\t\treturn -1\n\t\t\t} else {\n\t\t\t\tpanic(_tryPanic)\n\t\t\t}",
+		"if _tryCompletion == 1 {\n\t\t\treturn _tryResult\n\t\t}",
+		// A try block that can't leave the statement only returns what it threw
+		"_tryPanic := func() (_thrown any) {",
+		"if _, ok := stdjava.Catch(_tryPanic, \"RuntimeException\"); ok {\n\t\t\t\t\tcontinue\n\t\t\t\t}",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestTryCatchNamesCheckAfterLocals(t *testing.T) {
+	out := renderGoFileFromJava(t, `
+package exc.named;
+public class Named {
+    public static boolean check(String text) {
+        boolean ok = text.isEmpty();
+        try {
+            use(text);
+        } catch (IllegalStateException e) {
+            return ok;
+        }
+        return ok;
+    }
+}
+`)
+	expected := "if _, ok2 := stdjava.Catch(_tryPanic, \"IllegalStateException\"); ok2 {\n\t\t\t\treturn ok\n\t\t\t}"
+	if !strings.Contains(out, expected) {
+		t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+	}
+}
+
+func TestTryCatchThatCantCompleteNormally(t *testing.T) {
+	out := renderGoFileFromJava(t, `
+package exc.unreachable;
+public class Unreachable {
+    public static int parse(String text) {
+        try {
+            return Integer.parseInt(text);
+        } catch (NumberFormatException e) {
+            return -1;
+        }
+    }
+    public static int count(String text) {
+        try {
+            use(text);
+        } catch (IllegalStateException e) {
+            return 0;
+        }
+        return 1;
+    }
+}
+`)
+	// Go doesn't know that the completion checks cover every way out of the
+	// try statement, so the method would otherwise be missing a return
+	if !strings.Contains(out, "return _tryResult\n\t\t}\n\t\tpanic(\"unreachable\")\n\t}\n}") {
+		t.Errorf("Expected the try statement to end with a panic, got:\n%s", out)
+	}
+	if strings.Count(out, "panic(\"unreachable\")") != 1 {
+		t.Errorf("Expected no panic after a try statement that completes normally, got:\n%s", out)
+	}
+}
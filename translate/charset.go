@@ -0,0 +1,171 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for text that is encoded as something other
+// than UTF-8
+const charsetDiagnostic = "charset"
+
+// The methods of `Base64` that create an encoder or a decoder, and the
+// encodings of `encoding/base64` that they use
+var base64Encodings = map[string]string{
+	"getEncoder":    "StdEncoding",
+	"getDecoder":    "StdEncoding",
+	"getUrlEncoder": "URLEncoding",
+	"getUrlDecoder": "URLEncoding",
+}
+
+// charsetName returns the name of the charset that an expression refers to,
+// such as `StandardCharsets.UTF_8` or `"utf-8"`, in upper case, or an empty
+// string if it isn't known before the code runs
+func charsetName(node *sitter.Node, source []byte) string {
+	switch node.Type() {
+	case "field_access":
+		if stripJavaQualifier(node.ChildByFieldName("object").Content(source)) == "StandardCharsets" {
+			return strings.ReplaceAll(node.ChildByFieldName("field").Content(source), "_", "-")
+		}
+	case "string_literal":
+		if name, err := strconv.Unquote(node.Content(source)); err == nil {
+			return strings.ToUpper(name)
+		}
+	// Ex: `Charset.forName("UTF-8")`
+	case "method_invocation":
+		arguments := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+		if object := node.ChildByFieldName("object"); object != nil && object.Content(source) == "Charset" &&
+			node.ChildByFieldName("name").Content(source) == "forName" && len(arguments) == 1 {
+			return charsetName(arguments[0], source)
+		}
+	}
+	return ""
+}
+
+// checkCharset reports text that is encoded with a charset other than UTF-8,
+// which is what the bytes of a Go string always are
+func checkCharset(node, charset *sitter.Node, ctx Ctx, source []byte) {
+	switch name := charsetName(charset, source); name {
+	case "UTF-8", "UTF8":
+	case "":
+		ctx.diagnostics.Report(node, charsetDiagnostic,
+			"`%s` uses a charset that isn't known until the code runs, but the generated Go always uses UTF-8",
+			node.Content(source))
+	default:
+		ctx.diagnostics.Report(node, charsetDiagnostic,
+			"`%s` uses %s, but the generated Go always uses UTF-8", node.Content(source), name)
+	}
+}
+
+// isByteArrayExpr determines if the given expression evaluates to a `byte[]`
+func isByteArrayExpr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	if node.Type() == "method_invocation" {
+		switch node.ChildByFieldName("name").Content(source) {
+		case "getBytes":
+			return true
+		case "encode", "decode":
+			return isBase64Expr(node.ChildByFieldName("object"), ctx, source)
+		}
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	return ok && strings.ReplaceAll(javaType, " ", "") == "byte[]"
+}
+
+// isBase64Expr determines if the given expression evaluates to one of the
+// encoders or decoders of `Base64`
+func isBase64Expr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	if node == nil {
+		return false
+	}
+	if node.Type() == "method_invocation" && node.ChildByFieldName("object") != nil {
+		switch name := node.ChildByFieldName("name").Content(source); {
+		case name == "withoutPadding":
+			return isBase64Expr(node.ChildByFieldName("object"), ctx, source)
+		case base64Encodings[name] != "":
+			return node.ChildByFieldName("object").Content(source) == "Base64"
+		}
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	return ok && (stripJavaQualifier(javaType) == "Encoder" || stripJavaQualifier(javaType) == "Decoder")
+}
+
+// newStringFromBytes translates the creation of a String from the bytes that
+// encode it, optionally with a range of the bytes, and the charset that they
+// are encoded with
+//
+// Ex: `new String(bytes, StandardCharsets.UTF_8)` turns into `string(bytes)`
+func newStringFromBytes(node *sitter.Node, argumentNodes []*sitter.Node, arguments []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	bytes := arguments[0]
+	switch len(arguments) {
+	case 1:
+	case 2:
+		checkCharset(node, argumentNodes[1], ctx, source)
+	case 4:
+		checkCharset(node, argumentNodes[3], ctx, source)
+		fallthrough
+	case 3:
+		bytes = &ast.SliceExpr{
+			X:    bytes,
+			Low:  arguments[1],
+			High: &ast.BinaryExpr{X: arguments[1], Op: token.ADD, Y: arguments[2]},
+		}
+	default:
+		return nil
+	}
+	return &ast.CallExpr{Fun: &ast.Ident{Name: "string"}, Args: []ast.Expr{bytes}}
+}
+
+// charsetMethod translates the methods that convert between strings and their
+// bytes, and that encode and decode bytes with `Base64`, or returns nil if the
+// method isn't one of them
+//
+// Go strings are always encoded as UTF-8, so converting them with any other
+// charset is reported
+func charsetMethod(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	objectNode := node.ChildByFieldName("object")
+	argumentNodes := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+
+	switch name := node.ChildByFieldName("name").Content(source); {
+	// Ex: `text.getBytes(StandardCharsets.UTF_8)` turns into `[]byte(text)`
+	case name == "getBytes" && len(args) <= 1 && isStringExpr(objectNode, ctx, source):
+		if len(args) == 1 {
+			checkCharset(node, argumentNodes[0], ctx, source)
+		}
+		return &ast.CallExpr{Fun: &ast.ArrayType{Elt: &ast.Ident{Name: "byte"}}, Args: []ast.Expr{object}}
+	// Ex: `Base64.getEncoder()` turns into `base64.StdEncoding`
+	case objectNode.Content(source) == "Base64" && len(args) == 0:
+		if encoding, ok := base64Encodings[name]; ok {
+			return &ast.SelectorExpr{X: &ast.Ident{Name: "base64"}, Sel: &ast.Ident{Name: encoding}}
+		}
+		ctx.diagnostics.Report(node, charsetDiagnostic,
+			"`%s` has no equivalent in `encoding/base64`, and isn't translated", node.Content(source))
+	case !isBase64Expr(objectNode, ctx, source):
+		return nil
+	case name == "withoutPadding" && len(args) == 0:
+		return &ast.CallExpr{
+			Fun:  &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: "WithPadding"}},
+			Args: []ast.Expr{&ast.SelectorExpr{X: &ast.Ident{Name: "base64"}, Sel: &ast.Ident{Name: "NoPadding"}}},
+		}
+	case name == "encodeToString" && len(args) == 1:
+		return &ast.CallExpr{Fun: &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: "EncodeToString"}}, Args: args}
+	case name == "encode" && len(args) == 1:
+		return &ast.CallExpr{
+			Fun:  &ast.ArrayType{Elt: &ast.Ident{Name: "byte"}},
+			Args: []ast.Expr{&ast.CallExpr{Fun: &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: "EncodeToString"}}, Args: args}},
+		}
+	// Decoding panics with an `IllegalArgumentException`, instead of returning
+	// an error
+	case name == "decode" && len(args) == 1:
+		encoded := args[0]
+		if !isStringExpr(argumentNodes[0], ctx, source) {
+			encoded = &ast.CallExpr{Fun: &ast.Ident{Name: "string"}, Args: args}
+		}
+		return runtimeCall("DecodeBase64", object, encoded)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCharsetConversions(t *testing.T) {
+	helper := setupParseHelper(t, `
+package charset.conversions;
+import java.nio.charset.StandardCharsets;
+import java.util.Base64;
+public class Conversions {
+    public static String run(String text, byte[] data) {
+        byte[] utf = text.getBytes(StandardCharsets.UTF_8);
+        byte[] latin = text.getBytes("ISO-8859-1");
+        String back = new String(data, StandardCharsets.UTF_8) + new String(data, 1, 2, "UTF-8");
+        String encoded = Base64.getEncoder().encodeToString(utf);
+        Base64.Encoder url = Base64.getUrlEncoder().withoutPadding();
+        byte[] decoded = Base64.getDecoder().decode(url.encode(data));
+        return back + encoded;
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Conversions.java")
+
+	out := renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		"import (\n\t\"encoding/base64\"\n\t\"github.com/NickyBoy89/java2go/stdjava\"\n)",
+		"utf := []byte(text)",
+		"latin := []byte(text)",
+		"back := string(data) + string(data[1:1+2])",
+		"encoded := base64.StdEncoding.EncodeToString(utf)",
+		"url := base64.URLEncoding.WithPadding(base64.NoPadding)",
+		"decoded := stdjava.DecodeBase64(base64.StdEncoding, string([]byte(url.EncodeToString(data))))",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	// Only the charset that isn't UTF-8 is reported
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != charsetDiagnostic || items[0].Line != 8 {
+		t.Errorf("Expected the ISO-8859-1 conversion on line 8 to be reported, got %v", items)
+	}
+}
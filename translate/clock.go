@@ -0,0 +1,207 @@
+package translate
+
+import (
+	"go/ast"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// `System.currentTimeMillis()` reads the wall clock, the same as
+// `time.Now().UnixMilli()`, but `System.nanoTime()` reads a monotonic clock,
+// which `time.Now().UnixNano()` doesn't, so the difference between two of its
+// values can be off if the wall clock is changed in between. With
+// `-monotonic-clock`, it is read from the monotonic clock instead, through the
+// runtime
+//
+// A local variable that only ever holds the time that something started at is
+// a `time.Time` instead, and the time that has elapsed since then is measured
+// with `time.Since`, which uses the monotonic clock either way:
+//
+//	long start = System.nanoTime();               start := time.Now()
+//	work();                                       work()
+//	long took = System.nanoTime() - start;        took := time.Since(start).Nanoseconds()
+
+// The kind of diagnostic reported for the clocks that don't behave the same as
+// Java's
+const clockDiagnostic = "clock"
+
+// The methods of `System` that read a clock, and the methods of
+// `time.Duration` that measure a duration in the same unit as them
+var clockUnits = map[string]string{
+	"nanoTime":          "Nanoseconds",
+	"currentTimeMillis": "Milliseconds",
+}
+
+// clockMethod returns the name of the method of `System` that reads a clock
+// that a node calls, or an empty string if it doesn't call one
+func clockMethod(node *sitter.Node, source []byte) string {
+	if node == nil || node.Type() != "method_invocation" || node.ChildByFieldName("arguments").NamedChildCount() != 0 {
+		return ""
+	}
+	object := node.ChildByFieldName("object")
+	if object == nil || stripJavaQualifier(object.Content(source)) != "System" {
+		return ""
+	}
+	name := node.ChildByFieldName("name").Content(source)
+	if _, ok := clockUnits[name]; !ok {
+		return ""
+	}
+	return name
+}
+
+// timeNow is the call of `time.Now()`
+func timeNow() ast.Expr {
+	return &ast.CallExpr{Fun: &ast.SelectorExpr{X: &ast.Ident{Name: "time"}, Sel: &ast.Ident{Name: "Now"}}}
+}
+
+// readClock translates a call of one of the methods of `System` that read a
+// clock, or returns nil if the node calls something else. A call that sets a
+// variable that holds a start time is the start time itself
+func readClock(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	method := clockMethod(node, source)
+	if method == "" {
+		return nil
+	}
+	if variable := setVariable(node, source); variable != "" && startTimes(node, source)[variable] == method {
+		return timeNow()
+	}
+
+	if method == "currentTimeMillis" {
+		return &ast.CallExpr{Fun: &ast.SelectorExpr{X: timeNow(), Sel: &ast.Ident{Name: "UnixMilli"}}}
+	}
+	if MonotonicClock {
+		return runtimeCall("NanoTime")
+	}
+	ctx.diagnostics.Report(node, clockDiagnostic,
+		"`%s` reads the wall clock in the generated code, instead of a monotonic clock, so the time between its values is off if the clock is changed",
+		node.Content(source))
+	return &ast.CallExpr{Fun: &ast.SelectorExpr{X: timeNow(), Sel: &ast.Ident{Name: "UnixNano"}}}
+}
+
+// elapsedTime translates the time that has elapsed since a start time, which
+// is measured with `time.Since`, or returns nil if the node isn't a
+// subtraction of a start time from the same clock
+//
+// Ex: `System.nanoTime() - start` turns into `time.Since(start).Nanoseconds()`
+func elapsedTime(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	method := clockMethod(node.ChildByFieldName("left"), source)
+	started := node.ChildByFieldName("right")
+	if method == "" || node.Child(1).Content(source) != "-" || started.Type() != "identifier" ||
+		startTimes(node, source)[started.Content(source)] != method {
+		return nil
+	}
+	since := &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: &ast.Ident{Name: "time"}, Sel: &ast.Ident{Name: "Since"}},
+		Args: []ast.Expr{ParseExpr(started, source, ctx)},
+	}
+	return &ast.CallExpr{Fun: &ast.SelectorExpr{X: since, Sel: &ast.Ident{Name: clockUnits[method]}}}
+}
+
+// setVariable returns the name of the local variable that an expression is
+// the value of, where it is declared or assigned to, or an empty string if it
+// isn't set to a variable
+func setVariable(node *sitter.Node, source []byte) string {
+	switch parent := node.Parent(); parent.Type() {
+	case "variable_declarator":
+		return parent.ChildByFieldName("name").Content(source)
+	case "assignment_expression":
+		if left := parent.ChildByFieldName("left"); left.Type() == "identifier" && parent.ChildByFieldName("operator").Content(source) == "=" {
+			return left.Content(source)
+		}
+	}
+	return ""
+}
+
+// startTimes returns the local variables of the method that a node is in that
+// only ever hold the time that something started at, by their names, along
+// with the method of `System` that the time is read with
+//
+// Each of these variables is a `long` that is only ever set to the time, and
+// is only used to subtract it from the time of the same clock
+func startTimes(node *sitter.Node, source []byte) map[string]string {
+	var body *sitter.Node
+	for parent := node.Parent(); parent != nil && body == nil; parent = parent.Parent() {
+		switch parent.Type() {
+		case "method_declaration", "constructor_declaration", "compact_constructor_declaration":
+			body = parent.ChildByFieldName("body")
+		case "static_initializer":
+			body = parent
+		case "class_body":
+			// The initializers of fields don't have local variables
+			return nil
+		}
+	}
+	if body == nil {
+		return nil
+	}
+
+	clocks := map[string]string{}
+	// The parameters and fields aren't declared in the body, and are left alone
+	declared := map[string]bool{}
+	disqualified := map[string]bool{}
+	// Every variable is checked where it is declared, set, and used
+	use := func(name, method string) {
+		if method == "" || clocks[name] != "" && clocks[name] != method {
+			disqualified[name] = true
+		} else {
+			clocks[name] = method
+		}
+	}
+
+	var visit func(node *sitter.Node)
+	visit = func(node *sitter.Node) {
+		switch node.Type() {
+		case "class_body":
+			// The classes that are declared in the method have their own variables
+			return
+		case "local_variable_declaration":
+			declarators := nodeutil.ChildrenByFieldName(node, "declarator")
+			javaType := node.ChildByFieldName("type").Content(source)
+			for _, declarator := range declarators {
+				name := declarator.ChildByFieldName("name").Content(source)
+				declared[name] = true
+				if len(declarators) != 1 || javaType != "long" && javaType != "var" {
+					disqualified[name] = true
+				} else {
+					use(name, clockMethod(declarator.ChildByFieldName("value"), source))
+				}
+				if value := declarator.ChildByFieldName("value"); value != nil {
+					visit(value)
+				}
+			}
+			return
+		case "assignment_expression":
+			if left := node.ChildByFieldName("left"); left.Type() == "identifier" {
+				method := ""
+				if node.ChildByFieldName("operator").Content(source) == "=" {
+					method = clockMethod(node.ChildByFieldName("right"), source)
+				}
+				use(left.Content(source), method)
+				visit(node.ChildByFieldName("right"))
+				return
+			}
+		case "binary_expression":
+			if right := node.ChildByFieldName("right"); right.Type() == "identifier" && node.Child(1).Content(source) == "-" {
+				if method := clockMethod(node.ChildByFieldName("left"), source); method != "" {
+					use(right.Content(source), method)
+					return
+				}
+			}
+		case "identifier":
+			disqualified[node.Content(source)] = true
+			return
+		}
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			visit(node.NamedChild(i))
+		}
+	}
+	visit(body)
+
+	for name := range clocks {
+		if disqualified[name] || !declared[name] {
+			delete(clocks, name)
+		}
+	}
+	return clocks
+}
@@ -0,0 +1,69 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+const clockSource = `
+package app.clocks;
+public class Timer {
+    public long measure(long given) {
+        long start = System.nanoTime();
+        long begin = System.currentTimeMillis();
+        work();
+        long elapsed = System.nanoTime() - start;
+        start = System.nanoTime();
+        long stamp = System.nanoTime();
+        long sum = stamp + System.nanoTime() - given;
+        return elapsed + (System.currentTimeMillis() - begin) + (System.nanoTime() - start);
+    }
+}
+`
+
+func TestClocks(t *testing.T) {
+	helper := setupParseHelper(t, clockSource)
+	helper.Ctx.diagnostics = NewDiagnostics("Test.java")
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helper))
+	for _, expected := range []string{
+		`import "time"`,
+		// The variables that only hold a start time are a time.Time
+		"start := time.Now() begin := time.Now()",
+		"elapsed := time.Since(start).Nanoseconds() start = time.Now()",
+		// Anything else is the number that Java reads
+		"stamp := time.Now().UnixNano()",
+		"return elapsed + (time.Since(begin).Milliseconds()) + (time.Since(start).Nanoseconds())",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	// The nanoseconds that are read from the wall clock are reported
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 2 || items[0].Kind != clockDiagnostic || items[0].Line != 10 || items[1].Line != 11 {
+		t.Errorf("Expected the two reads of the wall clock to be reported, got %v", items)
+	}
+}
+
+func TestMonotonicClock(t *testing.T) {
+	MonotonicClock = true
+	defer func() { MonotonicClock = false }()
+
+	helper := setupParseHelper(t, clockSource)
+	helper.Ctx.diagnostics = NewDiagnostics("Test.java")
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helper))
+	for _, expected := range []string{
+		"stamp := stdjava.NanoTime()",
+		"elapsed := time.Since(start).Nanoseconds()",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+	if items := helper.Ctx.diagnostics.Items(); len(items) != 0 {
+		t.Errorf("Expected nothing to be reported, got %v", items)
+	}
+}
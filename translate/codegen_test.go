@@ -0,0 +1,305 @@
+package translate
+
+import (
+	"bytes"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/NickyBoy89/java2go/astutil"
+	"github.com/NickyBoy89/java2go/parsing"
+	"github.com/NickyBoy89/java2go/symbol"
+)
+
+// ParseAst parses a given source file and returns the Go AST representation.
+// It properly sets up the symbol table context required for transpilation.
+func ParseAst(fileName string) ast.Node {
+	// Read the source file
+	sourceCode, err := os.ReadFile(fileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a SourceFile and parse its AST
+	file := parsing.SourceFile{
+		Name:   fileName,
+		Source: sourceCode,
+	}
+	if err := file.ParseAST(); err != nil {
+		panic(err)
+	}
+
+	// Parse and register symbols
+	astutil.FindHashedClasses(file.Ast, file.Source)
+	symbols := file.ParseSymbols()
+	symbol.AddSymbolsToPackage(symbols)
+
+	// Resolve the file's symbols
+	ResolveFile(file)
+
+	// Create context with proper symbol information
+	ctx := Ctx{
+		currentFile:  file.Symbols,
+		currentClass: file.Symbols.BaseClass,
+	}
+
+	return ParseNode(file.Ast, file.Source, ctx).(ast.Node)
+}
+
+// This tests the increment and decrement handling on increment and decrement
+// statements, as well as expressions
+func TestIncDec(t *testing.T) {
+	var generated bytes.Buffer
+	err := printer.Fprint(&generated, token.NewFileSet(), ParseAst("../testfiles/IncrementDecrement.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(generated.String())
+}
+
+// This tests the variable assignment handling, using it as a statement as
+// well as an expression
+func TestAssignments(t *testing.T) {
+	var generated bytes.Buffer
+	err := printer.Fprint(&generated, token.NewFileSet(), ParseAst("../testfiles/VariableAssignments.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(generated.String())
+}
+
+// This tests two alternate ways of calling the new constructor
+func TestAlternateNewCall(t *testing.T) {
+	var generated bytes.Buffer
+	err := printer.Fprint(&generated, token.NewFileSet(), ParseAst("../testfiles/SelectorNewExpression.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(generated.String())
+}
+
+// This tests for various combinations of init, cond, and post parts of for loops
+func TestScrambledForLoops(t *testing.T) {
+	var generated bytes.Buffer
+	err := printer.Fprint(&generated, token.NewFileSet(), ParseAst("../testfiles/ScrambledForLoops.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(generated.String())
+}
+
+// This tests for the correct handling of generics (Implemented with go 1.18)
+func TestGenericLinkedlist(t *testing.T) {
+	var generated bytes.Buffer
+	err := printer.Fprint(&generated, token.NewFileSet(), ParseAst("../testfiles/GenericLinkedList.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(generated.String())
+}
+
+func TestConditionOrdering(t *testing.T) {
+	var generated bytes.Buffer
+	err := printer.Fprint(&generated, token.NewFileSet(), ParseAst("../testfiles/ConditionOrdering.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(generated.String())
+}
+
+// This tests enum declaration and values() method transformation
+func TestEnumDeclaration(t *testing.T) {
+	var generated bytes.Buffer
+	err := printer.Fprint(&generated, token.NewFileSet(), ParseAst("../testfiles/Compass.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := generated.String()
+	t.Log(output)
+
+	// Verify enum type is generated
+	if !bytes.Contains(generated.Bytes(), []byte("type compass int")) {
+		t.Error("Expected enum type declaration 'type compass int'")
+	}
+
+	// Verify constants are generated
+	if !bytes.Contains(generated.Bytes(), []byte("NORTH")) ||
+		!bytes.Contains(generated.Bytes(), []byte("SOUTH")) ||
+		!bytes.Contains(generated.Bytes(), []byte("EAST")) ||
+		!bytes.Contains(generated.Bytes(), []byte("WEST")) {
+		t.Error("Expected all enum constants to be generated")
+	}
+
+	// Verify values function is generated
+	if !bytes.Contains(generated.Bytes(), []byte("compassValues()")) {
+		t.Error("Expected compassValues() function to be generated")
+	}
+}
+
+// This tests enums whose constants are created with arguments that refer to
+// earlier constants and static fields
+func TestEnumConstructorArguments(t *testing.T) {
+	var generated bytes.Buffer
+	err := printer.Fprint(&generated, token.NewFileSet(), ParseAst("../testfiles/Planet.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := generated.String()
+	t.Log(output)
+
+	for _, expected := range []string{
+		"type Planet struct",
+		"EARTH\t*Planet",
+		"= 0.27",
+		"MERCURY = newPlanet(3.303e+23, 2.4397e6)",
+		"MOON = newPlanet(EARTH.mass/81, EARTH.radius*sCALE)",
+	} {
+		if !bytes.Contains(generated.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %q in the generated enum", expected)
+		}
+	}
+
+	// Static fields are initialized after all the constants are created
+	if !strings.Contains(output, "MOON = newPlanet(EARTH.mass/81, EARTH.radius*sCALE)\n\theaviest = EARTH") {
+		t.Error("Expected static fields to be initialized after the constants")
+	}
+}
+
+// This tests that enum constants are created with the overloaded constructor
+// that matches the types of their arguments
+func TestEnumOverloadedConstructors(t *testing.T) {
+	src := `
+package app.levels;
+public class Levels {
+    enum Level {
+        LOW(1), HIGH("high");
+        private final String label;
+        Level(int rank) { this.label = "rank"; }
+        Level(String label) { this.label = label; }
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	for _, expected := range []string{
+		`LOW = newLevel0(1)`,
+		`HIGH = newLevel("high")`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the generated enum, got:\n%s", expected, out)
+		}
+	}
+}
+
+// This tests enum constants that override the enum's methods with their own
+// bodies, which are stored as func values for each constant
+func TestEnumConstantBodies(t *testing.T) {
+	var generated bytes.Buffer
+	err := printer.Fprint(&generated, token.NewFileSet(), ParseAst("../testfiles/Operation.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := generated.String()
+	t.Log(output)
+
+	for _, expected := range []string{
+		"applyFunc\tfunc(on *Operation, a int32, b int32) int32",
+		"PLUS.applyFunc = func(on *Operation, a int32, b int32) int32 {\n\t\treturn a + b",
+		// Constants that don't override a method share the enum's implementation
+		"IDENTITY.applyFunc = (*Operation).applyDefault",
+		"PLUS.describeFunc = (*Operation).describeDefault",
+		"func (on *Operation) applyDefault(a int32, b int32) int32 {\n\treturn a\n",
+		"return on.applyFunc(on, a, b)",
+		"on.describeFunc(on)",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in the generated enum", expected)
+		}
+	}
+
+	// The enum's own implementation is only used through the func values, and
+	// is generated once
+	if strings.Count(output, "func (on *Operation) apply(") != 1 {
+		t.Error("Expected a single apply method that dispatches to the constant's implementation")
+	}
+	if strings.Count(output, "return a\n") != 1 {
+		t.Error("Expected the enum's implementation of apply to be generated once")
+	}
+}
+
+// This tests an abstract method that is implemented by every constant
+func TestEnumAbstractMethod(t *testing.T) {
+	var generated bytes.Buffer
+	err := printer.Fprint(&generated, token.NewFileSet(), ParseAst("../testfiles/Shape.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := generated.String()
+	t.Log(output)
+
+	for _, expected := range []string{
+		"SQUARE = newShape(2)\n\tSQUARE.areaFunc = func(se *Shape) float64",
+		"func (se *Shape) Area() float64 {\n\treturn se.areaFunc(se)",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in the generated enum", expected)
+		}
+	}
+}
+
+// This tests that an enum that implements an interface has the same method set
+// as the interface, and asserts that it does
+func TestEnumImplementsInterface(t *testing.T) {
+	var generated bytes.Buffer
+	err := printer.Fprint(&generated, token.NewFileSet(), ParseAst("../testfiles/Flag.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := generated.String()
+	t.Log(output)
+
+	for _, expected := range []string{
+		// Interface methods are public, even without the modifier
+		"Enabled() bool",
+		"var _ Flagtoggle = ON",
+		// The constants are values, so the methods can't have pointer receivers
+		"func (fle Flagstate) Enabled() bool",
+		"func (fle Flagstate) Label(width int32) string",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in the generated code", expected)
+		}
+	}
+}
+
+// This tests that an enum whose constants implement an interface's method in
+// their own bodies isn't asserted to implement it
+func TestEnumConstantsImplementInterface(t *testing.T) {
+	helper := setupParseHelper(t, `
+package app.ops;
+public class Ops {
+    interface Op {
+        int apply(int x);
+    }
+    enum Doubling implements Op {
+        TWICE {
+            public int apply(int x) {
+                return x * 2;
+            }
+        };
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Ops.java")
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helper))
+	if strings.Contains(out, "var _ Opsop") {
+		t.Errorf("Expected no assertion that the enum implements the interface, got:\n%s", out)
+	}
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != enumDiagnostic || items[0].Line != 7 {
+		t.Errorf("Expected the interface to be reported on line 7, got %v", items)
+	}
+}
@@ -0,0 +1,290 @@
+package translate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The elements of a stream that is lowered into a loop are collected by the
+// loop as well, into a map or a string that is declared before it:
+//
+//	Collectors.toList()           ->   result = append(result, e)
+//	Collectors.toMap(k, v)        ->   stdjava.PutUnique(result, k(e), v(e))
+//	Collectors.groupingBy(f)      ->   result[key] = append(result[key], e)
+//	Collectors.joining(", ")      ->   parts = append(parts, e), joined after
+//
+// The maps that are collected into take their types from the variable that the
+// stream is assigned to, or the result that it is returned as, since the types
+// of the elements of a stream aren't always known. The lists of the groups of
+// `groupingBy` and `partitioningBy` are slices
+
+// The collectors that a stream is lowered with, and the numbers of arguments
+// that they can have
+var streamCollectors = map[string][]int{
+	"toList":         {0},
+	"toSet":          {0},
+	"toMap":          {2, 3},
+	"groupingBy":     {1, 2},
+	"partitioningBy": {1, 2},
+	"joining":        {0, 1, 3},
+}
+
+// The collectors that collect the groups of `groupingBy` and `partitioningBy`,
+// and the numbers of arguments that they can have
+var downstreamCollectors = map[string][]int{
+	"toList":        {0},
+	"toSet":         {0},
+	"counting":      {0},
+	"summingInt":    {1},
+	"summingLong":   {1},
+	"summingDouble": {1},
+}
+
+// A collectedStream is what a loop collects the elements of a stream into
+type collectedStream struct {
+	// The Go type of the result
+	typ ast.Expr
+	// The statements that declare what the loop collects into
+	init []ast.Stmt
+	// The result, after the loop
+	result ast.Expr
+}
+
+// collectorCall returns the name and the arguments of a call to one of the
+// methods of `Collectors`, or an empty name if the expression isn't one
+func collectorCall(node *sitter.Node, source []byte) (string, []*sitter.Node) {
+	if node == nil || node.Type() != "method_invocation" || node.ChildByFieldName("object") == nil ||
+		stripJavaQualifier(node.ChildByFieldName("object").Content(source)) != "Collectors" {
+		return "", nil
+	}
+	return node.ChildByFieldName("name").Content(source), nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+}
+
+// checkCollector returns why a stream isn't lowered with a collector, or an
+// empty string if it is
+func checkCollector(node *sitter.Node, collectors map[string][]int, source []byte) string {
+	name, args := collectorCall(node, source)
+	if name == "" {
+		return fmt.Sprintf("`%s` isn't one of the collectors that are lowered into a loop", node.Content(source))
+	}
+	if counts, ok := collectors[name]; !ok || !slices.Contains(counts, len(args)) {
+		return fmt.Sprintf("`%s` isn't lowered into a loop", node.Content(source))
+	}
+	for ind, arg := range args {
+		// The merge function of `toMap` is a function of its own
+		if name == "toMap" && ind == 2 {
+			continue
+		}
+		if !isInlinedFunction(arg) {
+			return fmt.Sprintf("the lambdas of `%s` are only lowered into a loop if they have one parameter, and an expression as their body", name)
+		}
+	}
+	if (name == "groupingBy" || name == "partitioningBy") && len(args) == 2 {
+		return checkCollector(args[1], downstreamCollectors, source)
+	}
+	return ""
+}
+
+// collectStream collects the elements of the stream that reach the end of the
+// loop with a collector, or returns nil, and reports it, if the type that it
+// collects into isn't known
+func collectStream(node *sitter.Node, l *streamLoop, ctx Ctx) *collectedStream {
+	source := l.source
+	name, args := collectorCall(node, source)
+
+	if name == "joining" {
+		parts := &ast.Ident{Name: l.names.Allocate("parts")}
+		l.add(&ast.AssignStmt{
+			Lhs: []ast.Expr{parts},
+			Tok: token.ASSIGN,
+			Rhs: []ast.Expr{&ast.CallExpr{Fun: &ast.Ident{Name: "append"}, Args: []ast.Expr{parts, l.element}}},
+		})
+		separator := ast.Expr(&ast.BasicLit{Kind: token.STRING, Value: `""`})
+		if len(args) > 0 {
+			separator = ParseExpr(args[0], source, ctx)
+		}
+		var joined ast.Expr = &ast.CallExpr{
+			Fun:  &ast.SelectorExpr{X: &ast.Ident{Name: "strings"}, Sel: &ast.Ident{Name: "Join"}},
+			Args: []ast.Expr{parts, separator},
+		}
+		if len(args) == 3 {
+			joined = &ast.BinaryExpr{
+				X:  &ast.BinaryExpr{X: ParseExpr(args[1], source, ctx), Op: token.ADD, Y: joined},
+				Op: token.ADD,
+				Y:  ParseExpr(args[2], source, ctx),
+			}
+		}
+		return &collectedStream{
+			typ: &ast.Ident{Name: "string"},
+			init: []ast.Stmt{&ast.DeclStmt{Decl: &ast.GenDecl{Tok: token.VAR, Specs: []ast.Spec{&ast.ValueSpec{
+				Names: []*ast.Ident{parts},
+				Type:  &ast.ArrayType{Elt: &ast.Ident{Name: "string"}},
+			}}}}},
+			result: joined,
+		}
+	}
+
+	if name == "toList" || name == "toSet" {
+		return collectElements(name, node.Parent().Parent(), l, ctx)
+	}
+
+	keyType, valueType, ok := mapTypeOf(ctx.expectedType)
+	if !ok {
+		ctx.diagnostics.Report(node.Parent().Parent(), streamLoweringDiagnostic, "the type of the map that `%s` collects into isn't known", name)
+		return nil
+	}
+	result := &ast.Ident{Name: l.names.Allocate("result")}
+	collected := &collectedStream{result: result}
+
+	if name == "toMap" {
+		collected.typ = &ast.MapType{Key: elementType(keyType, true, ctx), Value: elementType(valueType, false, ctx)}
+		collected.init = []ast.Stmt{&ast.AssignStmt{
+			Lhs: []ast.Expr{result},
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{&ast.CallExpr{Fun: &ast.Ident{Name: "make"}, Args: []ast.Expr{collected.typ}}},
+		}}
+		key := l.applyToValue(args[0])
+		value := l.applyToValue(args[1])
+		if len(args) == 3 {
+			values := collected.typ.(*ast.MapType).Value
+			merge := mapFunction(args[2], ParseExpr(args[2], source, l.ctx), []ast.Expr{values, values}, values, source)
+			l.add(&ast.ExprStmt{X: runtimeCall("Merge", result, key, value, merge)})
+		} else {
+			l.add(&ast.ExprStmt{X: runtimeCall("PutUnique", result, key, value)})
+		}
+		return collected
+	}
+
+	// The elements are grouped by their keys, into the groups of the
+	// downstream collector
+	group, groupArgs := "toList", []*sitter.Node(nil)
+	if len(args) == 2 {
+		group, groupArgs = collectorCall(args[1], source)
+	}
+	groupType := collectedGroupType(group, valueType, ctx)
+	if groupType == nil {
+		ctx.diagnostics.Report(node.Parent().Parent(), streamLoweringDiagnostic, "the groups of `%s` aren't collected into a `%s`", name, valueType)
+		return nil
+	}
+	collected.typ = &ast.MapType{Key: elementType(keyType, true, ctx), Value: groupType}
+
+	// Both partitions exist, even if they are empty
+	if name == "partitioningBy" {
+		initial := &ast.CompositeLit{Type: collected.typ}
+		for _, partition := range []string{"false", "true"} {
+			initial.Elts = append(initial.Elts, &ast.KeyValueExpr{Key: &ast.Ident{Name: partition}, Value: emptyGroup(group, groupType)})
+		}
+		collected.init = []ast.Stmt{&ast.AssignStmt{Lhs: []ast.Expr{result}, Tok: token.DEFINE, Rhs: []ast.Expr{initial}}}
+	} else {
+		collected.init = []ast.Stmt{&ast.AssignStmt{
+			Lhs: []ast.Expr{result},
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{&ast.CallExpr{Fun: &ast.Ident{Name: "make"}, Args: []ast.Expr{collected.typ}}},
+		}}
+	}
+
+	key := &ast.Ident{Name: l.names.Allocate("key")}
+	l.declare(key.Name, "", l.applyToValue(args[0]))
+	entry := &ast.IndexExpr{X: result, Index: key}
+	switch group {
+	case "toList":
+		l.add(&ast.AssignStmt{
+			Lhs: []ast.Expr{entry},
+			Tok: token.ASSIGN,
+			Rhs: []ast.Expr{&ast.CallExpr{Fun: &ast.Ident{Name: "append"}, Args: []ast.Expr{entry, l.element}}},
+		})
+	case "toSet":
+		if name != "partitioningBy" {
+			l.add(&ast.IfStmt{
+				Cond: &ast.BinaryExpr{X: entry, Op: token.EQL, Y: &ast.Ident{Name: "nil"}},
+				Body: &ast.BlockStmt{List: []ast.Stmt{&ast.AssignStmt{Lhs: []ast.Expr{entry}, Tok: token.ASSIGN, Rhs: []ast.Expr{emptyGroup(group, groupType)}}}},
+			})
+		}
+		l.add(&ast.ExprStmt{X: &ast.CallExpr{Fun: &ast.SelectorExpr{X: entry, Sel: &ast.Ident{Name: "Add"}}, Args: []ast.Expr{l.element}}})
+	case "counting":
+		l.add(&ast.IncDecStmt{X: entry, Tok: token.INC})
+	default:
+		l.add(&ast.AssignStmt{Lhs: []ast.Expr{entry}, Tok: token.ADD_ASSIGN, Rhs: []ast.Expr{l.apply(groupArgs[0])}})
+	}
+	return collected
+}
+
+// collectedGroupType returns the Go type of the groups that a downstream
+// collector collects into, from their Java type, or nil if the collector
+// doesn't collect into that type
+func collectedGroupType(group string, javaType string, ctx Ctx) ast.Expr {
+	base, typeArgs := parseJavaTypeString(javaType)
+	switch group {
+	case "toList":
+		switch stripJavaQualifier(base) {
+		case "List", "ArrayList", "Collection":
+			if len(typeArgs) == 1 {
+				return &ast.ArrayType{Elt: elementType(typeArgs[0], false, ctx)}
+			}
+		}
+	case "toSet":
+		if _, ok := setTypeOf(javaType); ok {
+			return javaTypeStringToGoTypeExpr(javaType, inScopeTypeParameters(ctx))
+		}
+	default:
+		// Counts and sums are added to in place, so they can't be boxed
+		if counter, ok := elementType(javaType, false, ctx).(*ast.Ident); ok && isBoxedType(javaType) {
+			return counter
+		}
+	}
+	return nil
+}
+
+// collectElements collects the elements of a stream into a slice, for
+// `toList`, or into a set, for `toSet`, whose type is the variable's that the
+// stream is assigned to, or else the type of the elements, if it is known. The
+// stream that is collected is reported if neither of them is
+func collectElements(collector string, stream *sitter.Node, l *streamLoop, ctx Ctx) *collectedStream {
+	collectedType := collectedGroupType(collector, ctx.expectedType, ctx)
+	if collectedType == nil && l.elementType != "" {
+		if collector == "toList" {
+			collectedType = &ast.ArrayType{Elt: elementType(l.elementType, false, ctx)}
+		} else {
+			collectedType = &ast.IndexExpr{X: &ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: "HashSet"}}, Index: elementType(l.elementType, true, ctx)}
+		}
+	}
+	if collectedType == nil {
+		ctx.diagnostics.Report(stream, streamLoweringDiagnostic, "the type of the elements that `%s` collects isn't known", collector)
+		return nil
+	}
+
+	result := &ast.Ident{Name: l.names.Allocate("result")}
+	collected := &collectedStream{typ: collectedType, result: result}
+	if collector == "toList" {
+		collected.init = []ast.Stmt{&ast.DeclStmt{Decl: &ast.GenDecl{Tok: token.VAR, Specs: []ast.Spec{&ast.ValueSpec{
+			Names: []*ast.Ident{result},
+			Type:  collectedType,
+		}}}}}
+		l.add(&ast.AssignStmt{
+			Lhs: []ast.Expr{result},
+			Tok: token.ASSIGN,
+			Rhs: []ast.Expr{&ast.CallExpr{Fun: &ast.Ident{Name: "append"}, Args: []ast.Expr{result, l.element}}},
+		})
+		return collected
+	}
+	collected.init = []ast.Stmt{&ast.AssignStmt{Lhs: []ast.Expr{result}, Tok: token.DEFINE, Rhs: []ast.Expr{emptyGroup(collector, collectedType)}}}
+	l.add(&ast.ExprStmt{X: &ast.CallExpr{Fun: &ast.SelectorExpr{X: result, Sel: &ast.Ident{Name: "Add"}}, Args: []ast.Expr{l.element}}})
+	return collected
+}
+
+// emptyGroup returns the value of a group that has no elements
+func emptyGroup(group string, groupType ast.Expr) ast.Expr {
+	switch group {
+	case "toList":
+		return &ast.Ident{Name: "nil"}
+	case "toSet":
+		set := groupType.(*ast.IndexExpr)
+		return &ast.CallExpr{Fun: &ast.IndexExpr{X: &ast.SelectorExpr{X: set.X.(*ast.SelectorExpr).X, Sel: &ast.Ident{Name: "NewHashSet"}}, Index: set.Index}}
+	}
+	return &ast.BasicLit{Kind: token.INT, Value: "0"}
+}
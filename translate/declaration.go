@@ -0,0 +1,861 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/NickyBoy89/java2go/astutil"
+	"github.com/NickyBoy89/java2go/codegen"
+	"github.com/NickyBoy89/java2go/nodeutil"
+	"github.com/NickyBoy89/java2go/symbol"
+	log "github.com/sirupsen/logrus"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// ParseDecls represents any type that returns a list of top-level declarations,
+// this is any class, interface, enum, or record declaration
+func ParseDecls(node *sitter.Node, source []byte, ctx Ctx) []ast.Decl {
+	switch node.Type() {
+	case "class_declaration":
+		// TODO: Currently ignores implements and extends with the following tags:
+		//"superclass"
+		//"interfaces"
+
+		// The declarations and fields for the class
+		declarations := []ast.Decl{}
+
+		ctx.className = ctx.currentFile.FindClass(node.ChildByFieldName("name").Content(source)).Name
+		exportedProtectedMembers(node, ctx)
+
+		// First, look through the class's body for field declarations
+		fields, globalVariables := parseFieldDeclarations(nodeutil.NamedChildrenOf(node.ChildByFieldName("body")), source, ctx)
+
+		// Add the global variables
+		if len(globalVariables.Specs) > 0 {
+			declarations = append(declarations, globalVariables)
+		}
+
+		// An exception class embeds the runtime's exception type
+		if isExceptionType(ctx.currentClass, ctx) {
+			fields.List = append([]*ast.Field{embeddedException()}, fields.List...)
+		}
+
+		// The objects of the class are locked with a mutex of their own
+		if ctx.currentClass.Monitor != "" {
+			fields.List = append(fields.List, &ast.Field{
+				Names: []*ast.Ident{{Name: ctx.currentClass.Monitor}},
+				Type:  &ast.SelectorExpr{X: &ast.Ident{Name: "sync"}, Sel: &ast.Ident{Name: "Mutex"}},
+			})
+		}
+
+		// An inner class that is created from an enclosing object keeps it
+		if outer := outerField(ctx.currentClass); outer != nil {
+			fields.List = append(fields.List, outer)
+		}
+
+		// Add the struct for the class (with type parameters if present)
+		declarations = append(declarations, codegen.GenStructWithTypeParams(ctx.className, fields, ctx.currentClass.TypeParameters))
+
+		// Add all the declarations that appear in the class, after writing out
+		// the ones before them in the chunked mode
+		positionDecls(declarations, ctx.sources.declPos(node))
+		declarations = emitDecls(ctx, declarations)
+		declarations = append(declarations, ParseDecls(node.ChildByFieldName("body"), source, ctx)...)
+
+		// An abstract class can leave the methods of its interfaces for its
+		// subclasses to implement
+		if nodeutil.HasModifier(node, "abstract") {
+			stubs := missingInterfaceStubs(node, source, ctx)
+			positionDecls(stubs, ctx.sources.declPos(node))
+			declarations = append(declarations, stubs...)
+		}
+
+		markers := append(sealedMarkerDecls(node, ctx), stringerBridge(ctx)...)
+		markers = append(markers, iterableAll(node, source, ctx)...)
+		positionDecls(markers, ctx.sources.declPos(node))
+		declarations = append(declarations, markers...)
+
+		return declarations
+	case "class_body", "enum_body": // The body of the currently parsed class or enum
+		decls := []ast.Decl{}
+
+		// To switch to parsing the subclasses of a class, since we assume that
+		// all the class's subclass definitions are in-order, if we find some number
+		// of subclasses in a class, we can refer to them by index
+		var subclassIndex int
+
+		// Methods that are overridden by an enum's constants are generated along
+		// with the constants
+		var overridden map[*symbol.Definition]bool
+		if node.Type() == "enum_body" {
+			overridden = overriddenEnumMethods(node.Parent(), source, ctx.currentClass)
+		}
+
+		for _, child := range nodeutil.NamedChildrenOf(node) {
+			switch child.Type() {
+			// Skip fields, comments, and enum constants (already processed)
+			case "field_declaration", "comment", "enum_constant":
+			case "constructor_declaration", "method_declaration", "static_initializer":
+				for _, d := range ParseDecl(child, source, ctx) {
+					// If the declaration is bad, skip it
+					_, bad := d.(*ast.BadDecl)
+					if !bad {
+						decls = append(decls, emitDecls(ctx, []ast.Decl{d})...)
+					}
+				}
+			case "enum_body_declarations":
+				// Process methods and constructors inside enum body declarations
+				for _, declChild := range nodeutil.NamedChildrenOf(child) {
+					if declChild.Type() == "method_declaration" && overridden[findEnumMethod(ctx.currentClass, declChild, source)] {
+						continue
+					}
+					switch declChild.Type() {
+					case "constructor_declaration", "method_declaration", "static_initializer":
+						for _, d := range ParseDecl(declChild, source, ctx) {
+							_, bad := d.(*ast.BadDecl)
+							if !bad {
+								decls = append(decls, emitDecls(ctx, []ast.Decl{d})...)
+							}
+						}
+					}
+				}
+			// Subclasses
+			case "class_declaration", "interface_declaration", "enum_declaration", "record_declaration":
+				newCtx := ctx.Clone()
+				newCtx.currentClass = ctx.currentClass.Subclasses[subclassIndex]
+				subclassIndex++
+				decls = append(decls, emitDecls(ctx, ParseDecls(child, source, newCtx))...)
+			}
+		}
+
+		return decls
+	case "interface_body":
+		methods := &ast.FieldList{}
+		// The types that are nested in the interface, after the interface itself
+		var nested []ast.Decl
+		var subclassIndex int
+
+		for _, c := range nodeutil.NamedChildrenOf(node) {
+			switch c.Type() {
+			case "method_declaration":
+				parsedMethod := ParseNode(c, source, ctx).(*ast.Field)
+				// If the method was ignored with an annotation, it will return a blank
+				// field, so ignore that
+				if parsedMethod.Type != nil {
+					methods.List = append(methods.List, parsedMethod)
+				}
+			case "class_declaration", "interface_declaration", "enum_declaration", "record_declaration":
+				newCtx := ctx.Clone()
+				newCtx.currentClass = ctx.currentClass.Subclasses[subclassIndex]
+				subclassIndex++
+				nested = append(nested, ParseDecls(c, source, newCtx)...)
+			}
+		}
+
+		// The interfaces that are extended are embedded, with their type arguments
+		methods.List = append(embeddedInterfaces(node.Parent(), source, ctx), methods.List...)
+		// A sealed interface can only be implemented by the types that it permits
+		methods.List = append(methods.List, sealedMarkerMethods(ctx, ctx.currentClass)...)
+
+		var typeParams []string
+		if ctx.currentClass != nil {
+			typeParams = ctx.currentClass.TypeParameters
+		}
+		return append([]ast.Decl{codegen.GenInterfaceWithTypeParams(ctx.className, methods, typeParams)}, nested...)
+	case "interface_declaration":
+		ctx.className = ctx.currentFile.FindClass(node.ChildByFieldName("name").Content(source)).Name
+
+		decls := ParseDecls(node.ChildByFieldName("body"), source, ctx)
+
+		// Listeners can be implemented by a function, as well as by a type
+		if class := ctx.currentFile.BaseClass.FindClassScope(node.ChildByFieldName("name").Content(source)); listenerMethod(class) != nil {
+			decls = append(decls, listenerAdapter(class, decls[0], ctx)...)
+		}
+		positionDecls(decls, ctx.sources.declPos(node))
+
+		return decls
+	case "enum_declaration":
+		// An enum is treated as a type alias (int) and a list of constants
+		// that define the possible values the enum can have, unless its
+		// constants carry their own state
+
+		ctx.className = ctx.currentFile.FindClass(node.ChildByFieldName("name").Content(source)).Name
+		// Nested enums have their scope set by the class that contains them
+		if ctx.currentClass == nil {
+			ctx.currentClass = ctx.currentFile.BaseClass
+		}
+
+		declarations := []ast.Decl{}
+
+		enumType := ast.Expr(&ast.Ident{Name: ctx.className})
+		constExprs := []ast.Expr{}
+		for _, constName := range ctx.currentClass.EnumConstants {
+			constExprs = append(constExprs, &ast.Ident{Name: enumConstantName(ctx.currentClass, constName)})
+		}
+
+		if ctx.currentClass.EnumHasState {
+			// Constants that carry their own state are pointers to a struct instead
+			enumType = &ast.StarExpr{X: enumType}
+			declarations = append(declarations, enumStateDecls(node, source, ctx)...)
+		} else {
+			// Generate type declaration: type EnumName int
+			declarations = append(declarations, &ast.GenDecl{
+				Tok: token.TYPE,
+				Specs: []ast.Spec{
+					&ast.TypeSpec{
+						Name: &ast.Ident{Name: ctx.className},
+						Type: &ast.Ident{Name: "int"},
+					},
+				},
+			})
+
+			// Generate constants using iota
+			if len(constExprs) > 0 {
+				constSpecs := []ast.Spec{}
+				for i, constExpr := range constExprs {
+					spec := &ast.ValueSpec{
+						Names: []*ast.Ident{constExpr.(*ast.Ident)},
+						Type:  &ast.Ident{Name: ctx.className},
+					}
+					if i == 0 {
+						spec.Values = []ast.Expr{&ast.Ident{Name: "iota"}}
+					}
+					constSpecs = append(constSpecs, spec)
+				}
+				declarations = append(declarations, &ast.GenDecl{
+					Tok:   token.CONST,
+					Specs: constSpecs,
+				})
+			}
+		}
+
+		declarations = append(declarations, enumInterfaceAssertions(node, source, ctx, constExprs)...)
+
+		// Generate the values function only if `values` is called, which
+		// returns a new slice every time, since Java returns a copy of the
+		// constants: func EnumNameValues() []EnumName { return []EnumName{CONST1, CONST2, ...} }
+		if len(constExprs) > 0 && ctx.currentClass.ValuesUsed {
+			declarations = append(declarations, &ast.FuncDecl{
+				Name: &ast.Ident{Name: enumValuesName(ctx.currentClass, ctx.currentFile.Package)},
+				Type: &ast.FuncType{
+					Params: &ast.FieldList{},
+					Results: &ast.FieldList{
+						List: []*ast.Field{
+							{Type: &ast.ArrayType{Elt: enumType}},
+						},
+					},
+				},
+				Body: &ast.BlockStmt{
+					List: []ast.Stmt{
+						&ast.ReturnStmt{
+							Results: []ast.Expr{
+								&ast.CompositeLit{
+									Type: &ast.ArrayType{Elt: enumType},
+									Elts: constExprs,
+								},
+							},
+						},
+					},
+				},
+			})
+		}
+
+		// Parse the enum body declarations (methods, constructors, etc.)
+		positionDecls(declarations, ctx.sources.declPos(node))
+		declarations = emitDecls(ctx, declarations)
+		declarations = append(declarations, ParseDecls(node.ChildByFieldName("body"), source, ctx)...)
+
+		markers := append(sealedMarkerDecls(node, ctx), stringerBridge(ctx)...)
+		positionDecls(markers, ctx.sources.declPos(node))
+		declarations = append(declarations, markers...)
+
+		return declarations
+	case "record_declaration":
+		return recordDecls(node, source, ctx)
+	}
+	panic("Unknown type to parse for decls: " + node.Type())
+}
+
+// embeddedInterfaces returns the interfaces that an interface extends, as the
+// fields that embed them in its Go interface. The type arguments that an
+// interface is extended with are substituted for the type parameters of the
+// interface that it extends. Only the interfaces that are declared in the
+// package are known, and the others are left out
+//
+// Ex: `interface Names extends Stream<String>` embeds `Stream[string]`
+func embeddedInterfaces(node *sitter.Node, source []byte, ctx Ctx) []*ast.Field {
+	var extended *sitter.Node
+	for _, child := range nodeutil.NamedChildrenOf(node) {
+		if child.Type() == "extends_interfaces" {
+			extended = child.NamedChild(0)
+		}
+	}
+	if extended == nil {
+		return nil
+	}
+
+	var fields []*ast.Field
+	for _, parent := range nodeutil.NamedChildrenOf(extended) {
+		name := parent
+		if name.Type() == "generic_type" {
+			name = name.NamedChild(0)
+		}
+		if name.Type() == "scoped_type_identifier" {
+			name = name.NamedChild(int(name.NamedChildCount()) - 1)
+		}
+		iface := resolveClassScopeByName(ctx, name.Content(source))
+		if iface == nil || !iface.IsInterface {
+			continue
+		}
+		args := astutil.ParseTypeArguments(parent, source, inScopeTypeParameters(ctx))
+		// A raw type has no arguments, which Go doesn't allow
+		if len(args) != len(iface.TypeParameters) {
+			ctx.fallbacks.Report(parent, anyFallbackDiagnostic, "the raw type `%s` has no type arguments", parent.Content(source))
+			args = make([]ast.Expr, len(iface.TypeParameters))
+			for i := range args {
+				args[i] = &ast.Ident{Name: "any"}
+			}
+		}
+		fields = append(fields, &ast.Field{Type: instantiateGenericType(iface.Class.Name, args)})
+	}
+	return fields
+}
+
+func typeParamExprs(params []string) []ast.Expr {
+	if len(params) == 0 {
+		return nil
+	}
+	result := make([]ast.Expr, len(params))
+	for i, tp := range params {
+		result[i] = &ast.Ident{Name: tp}
+	}
+	return result
+}
+
+// classReceiverType returns the type of the receiver of the methods of the
+// current class
+func classReceiverType(class *symbol.ClassScope, ctx Ctx) ast.Expr {
+	receiverType := instantiateGenericType(ctx.className, typeParamExprs(class.TypeParameters))
+	// The constants of an enum without state are values
+	if !class.IsEnum || class.EnumHasState {
+		return &ast.StarExpr{X: receiverType}
+	}
+	return receiverType
+}
+
+func instantiateGenericType(name string, args []ast.Expr) ast.Expr {
+	if len(args) == 0 {
+		return &ast.Ident{Name: name}
+	}
+	if len(args) == 1 {
+		return &ast.IndexExpr{
+			X:     &ast.Ident{Name: name},
+			Index: args[0],
+		}
+	}
+	return &ast.IndexListExpr{
+		X:       &ast.Ident{Name: name},
+		Indices: args,
+	}
+}
+
+func genInstanceGenericHelperDecls(ctx Ctx, def *symbol.Definition, doc *ast.CommentGroup, params, results *ast.FieldList, body *ast.BlockStmt, receiverBaseType ast.Expr) []ast.Decl {
+	classTypeParams := ctx.currentClass.TypeParameters
+	combinedTypeParams := append([]string{}, classTypeParams...)
+	combinedTypeParams = append(combinedTypeParams, def.TypeParameters...)
+
+	helperName := def.HelperName
+
+	// A method that can't be overridden is a function that takes the receiver
+	// as its first parameter
+	if !ctx.currentClass.CanBeOverridden(def) {
+		receiverParam := &ast.Field{
+			Names: []*ast.Ident{{Name: ReceiverName(ctx)}},
+			Type:  &ast.StarExpr{X: receiverBaseType},
+		}
+		funcDecl := codegen.GenFuncDeclWithTypeParams(helperName, combinedTypeParams,
+			&ast.FieldList{List: append([]*ast.Field{receiverParam}, params.List...)}, results, body)
+		funcDecl.Doc = doc
+		return []ast.Decl{funcDecl}
+	}
+
+	helperFields := &ast.FieldList{
+		List: []*ast.Field{
+			{
+				Names: []*ast.Ident{{Name: "recv"}},
+				Type:  &ast.StarExpr{X: receiverBaseType},
+			},
+		},
+	}
+	helperStruct := codegen.GenStructWithTypeParams(helperName, helperFields, combinedTypeParams)
+
+	helperTypeArgs := typeParamExprs(combinedTypeParams)
+	helperTypeExpr := instantiateGenericType(helperName, helperTypeArgs)
+
+	receiverShortName := ReceiverName(ctx)
+	constructorName := "New" + helperName
+	constructorParams := &ast.FieldList{
+		List: []*ast.Field{
+			{
+				Names: []*ast.Ident{{Name: receiverShortName}},
+				Type:  &ast.StarExpr{X: receiverBaseType},
+			},
+		},
+	}
+	returnType := &ast.FieldList{List: []*ast.Field{{Type: &ast.StarExpr{X: helperTypeExpr}}}}
+	constructorBody := &ast.BlockStmt{
+		List: []ast.Stmt{
+			&ast.ReturnStmt{
+				Results: []ast.Expr{
+					&ast.UnaryExpr{
+						Op: token.AND,
+						X: &ast.CompositeLit{
+							Type: helperTypeExpr,
+							Elts: []ast.Expr{
+								&ast.KeyValueExpr{
+									Key:   &ast.Ident{Name: "recv"},
+									Value: &ast.Ident{Name: receiverShortName},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	constructor := codegen.GenFuncDeclWithTypeParams(constructorName, combinedTypeParams, constructorParams, returnType, constructorBody)
+
+	helperRecvName := localVariableNames(ctx).Allocate(receiverShortName + "Helper")
+	helperReceiver := &ast.FieldList{
+		List: []*ast.Field{
+			{
+				Names: []*ast.Ident{{Name: helperRecvName}},
+				Type:  &ast.StarExpr{X: helperTypeExpr},
+			},
+		},
+	}
+
+	assignOriginalReceiver := &ast.AssignStmt{
+		Lhs: []ast.Expr{&ast.Ident{Name: receiverShortName}},
+		Tok: token.DEFINE,
+		Rhs: []ast.Expr{
+			&ast.SelectorExpr{
+				X:   &ast.Ident{Name: helperRecvName},
+				Sel: &ast.Ident{Name: "recv"},
+			},
+		},
+	}
+	modifiedBody := &ast.BlockStmt{
+		List: append([]ast.Stmt{assignOriginalReceiver}, body.List...),
+	}
+
+	funcDecl := &ast.FuncDecl{
+		Doc:  doc,
+		Name: &ast.Ident{Name: def.Name},
+		Recv: helperReceiver,
+		Type: &ast.FuncType{
+			Params:  params,
+			Results: results,
+		},
+		Body: modifiedBody,
+	}
+
+	// The methods that share a helper declare it once, with the first of them
+	if !ctx.helpers.declare(helperName) {
+		return []ast.Decl{funcDecl}
+	}
+	return []ast.Decl{helperStruct, constructor, funcDecl}
+}
+
+// delegatedCreation returns the call to the constructor that a constructor
+// delegates to with `this(...)`, or nil if it doesn't delegate to one that is
+// known. An inner class passes its enclosing object along
+func delegatedCreation(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	first := node.ChildByFieldName("body").NamedChild(0)
+	if first == nil || first.Type() != "explicit_constructor_invocation" || first.ChildByFieldName("constructor").Type() != "this" {
+		return nil
+	}
+
+	arguments := first.ChildByFieldName("arguments")
+	argumentTypes := make([]string, arguments.NamedChildCount())
+	for ind, argument := range nodeutil.NamedChildrenOf(arguments) {
+		if javaType, ok := literalJavaType(argument, source); ok {
+			argumentTypes[ind] = javaType
+		} else if javaType, ok := inferExprJavaType(argument, ctx, source); ok {
+			argumentTypes[ind] = javaType
+		}
+	}
+	constructor := findMatchingConstructor(ctx.currentClass, ctx.currentClass.Class.OriginalName, argumentTypes)
+	if constructor == nil {
+		return nil
+	}
+
+	args := ParseNode(arguments, source, ctx).([]ast.Expr)
+	if ctx.currentClass.Outer != "" {
+		args = append([]ast.Expr{&ast.Ident{Name: localVariableNames(ctx).Allocate("outer")}}, args...)
+	}
+	return &ast.CallExpr{
+		Fun:  instantiateGenericType(constructor.Name, typeParamExprs(ctx.currentClass.TypeParameters)),
+		Args: args,
+	}
+}
+
+// ParseDecl parses a top-level declaration within a source file, including
+// but not limited to fields and methods
+func ParseDecl(node *sitter.Node, source []byte, ctx Ctx) []ast.Decl {
+	decls := parseDeclNode(node, source, ctx)
+	positionDecls(decls, ctx.sources.declPos(node))
+	return decls
+}
+
+// parseDeclNode parses a declaration in the body of a class, without giving
+// the declarations that it generates a position
+func parseDeclNode(node *sitter.Node, source []byte, ctx Ctx) []ast.Decl {
+	switch node.Type() {
+	case "constructor_declaration":
+		paramNode := node.ChildByFieldName("parameters")
+
+		constructorName := node.ChildByFieldName("name").Content(source)
+
+		comparison := func(d *symbol.Definition) bool {
+			// The names must match
+			if constructorName != d.OriginalName {
+				return false
+			}
+
+			// Size of parameters must match
+			if int(paramNode.NamedChildCount()) != len(d.Parameters) {
+				return false
+			}
+
+			// Go through the types and check to see if they differ
+			for index, param := range nodeutil.NamedChildrenOf(paramNode) {
+				paramType := nodeutil.ParameterType(param).Content(source)
+				if paramType != d.Parameters[index].OriginalType {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		// Search through the current class for the constructor, which is simply labeled as a method
+		ctx.enterFunction(ctx.currentClass.FindMethod().By(comparison)[0])
+		ctx.errorTarget = nil
+
+		// A constructor that delegates to another one with `this(...)` creates
+		// its object with that constructor
+		var body *ast.BlockStmt
+		delegated := delegatedCreation(node, source, ctx)
+		if delegated != nil {
+			body = &ast.BlockStmt{List: parseStmtList(nodeutil.NamedChildrenOf(node.ChildByFieldName("body"))[1:], source, ctx)}
+		} else {
+			body = ParseStmt(node.ChildByFieldName("body"), source, ctx).(*ast.BlockStmt)
+		}
+
+		// Generate the struct type for `new` call - if generic, include type params
+		var structType ast.Expr = &ast.Ident{Name: ctx.className}
+		if len(ctx.currentClass.TypeParameters) > 0 {
+			// Create ClassName[T, U, ...] for generic structs
+			typeParamExprs := make([]ast.Expr, len(ctx.currentClass.TypeParameters))
+			for i, tp := range ctx.currentClass.TypeParameters {
+				typeParamExprs[i] = &ast.Ident{Name: tp}
+			}
+			if len(typeParamExprs) == 1 {
+				structType = &ast.IndexExpr{
+					X:     &ast.Ident{Name: ctx.className},
+					Index: typeParamExprs[0],
+				}
+			} else {
+				structType = &ast.IndexListExpr{
+					X:       &ast.Ident{Name: ctx.className},
+					Indices: typeParamExprs,
+				}
+			}
+		}
+
+		// The exception that an exception class embeds is initialized even if
+		// the constructor doesn't call its superclass's constructor itself
+		if isExceptionType(ctx.currentClass, ctx) && delegated == nil && !callsSuperConstructor(node.ChildByFieldName("body")) {
+			body.List = append([]ast.Stmt{initEmbeddedException(nil, nil, ctx, source)}, body.List...)
+		}
+
+		creation := delegated
+		if creation == nil {
+			creation = &ast.CallExpr{Fun: &ast.Ident{Name: "new"}, Args: []ast.Expr{structType}}
+		}
+		body.List = append([]ast.Stmt{
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{&ast.Ident{Name: ReceiverName(ctx)}},
+				Tok: token.DEFINE,
+				Rhs: []ast.Expr{creation},
+			},
+		}, body.List...)
+
+		body.List = append(body.List, &ast.ReturnStmt{Results: []ast.Expr{&ast.Ident{Name: ReceiverName(ctx)}}})
+
+		params := ParseNode(node.ChildByFieldName("parameters"), source, ctx).(*ast.FieldList)
+		if ctx.currentClass.Outer != "" {
+			outerParameter(params, body, ctx)
+		}
+
+		// Build the return type: *ClassName or *ClassName[T, U, ...]
+		returnType := &ast.StarExpr{X: structType}
+
+		constructorTypeParams := append([]string{}, ctx.currentClass.TypeParameters...)
+		if ctx.localScope != nil && len(ctx.localScope.TypeParameters) > 0 {
+			constructorTypeParams = append(constructorTypeParams, ctx.localScope.TypeParameters...)
+		}
+
+		return []ast.Decl{codegen.GenFuncDeclWithTypeParams(
+			ctx.localScope.Name,
+			constructorTypeParams,
+			params,
+			&ast.FieldList{List: []*ast.Field{{Type: returnType}}},
+			body,
+		)}
+	case "method_declaration":
+		var static bool
+		var abstract bool
+		var native bool
+
+		// Store the annotations as comments on the method
+		comments := []*ast.Comment{}
+
+		if node.NamedChild(0).Type() == "modifiers" {
+			for _, modifier := range nodeutil.UnnamedChildrenOf(node.NamedChild(0)) {
+				switch modifier.Type() {
+				case "static":
+					static = true
+				case "abstract":
+					abstract = true
+				case "native":
+					native = true
+				case "marker_annotation", "annotation":
+					comments = append(comments, &ast.Comment{Text: "//" + modifier.Content(source)})
+					// If the annotation was on the list of ignored annotations, don't
+					// parse the method
+					if _, in := ExcludedAnnotations[modifier.Content(source)]; in {
+						return []ast.Decl{&ast.BadDecl{}}
+					}
+				}
+			}
+		}
+
+		var receiver *ast.FieldList
+		var receiverBaseType ast.Expr
+
+		// If a function is non-static, it has a method receiver
+		if !static {
+			receiverBaseType = instantiateGenericType(ctx.className, typeParamExprs(ctx.currentClass.TypeParameters))
+			receiverType := ast.Expr(&ast.StarExpr{X: receiverBaseType})
+			// The constants of an enum without state are values, so that they can
+			// satisfy the enum's interfaces
+			if ctx.currentClass.IsEnum && !ctx.currentClass.EnumHasState {
+				receiverType = receiverBaseType
+			}
+			receiver = &ast.FieldList{
+				List: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: ReceiverName(ctx)}},
+						Type:  receiverType,
+					},
+				},
+			}
+		}
+
+		methodName := ParseExpr(node.ChildByFieldName("name"), source, ctx).(*ast.Ident)
+		methodParameters := node.ChildByFieldName("parameters")
+
+		comparison := func(d *symbol.Definition) bool {
+			if d.OriginalName != methodName.Name {
+				return false
+			}
+			if len(d.Parameters) != int(methodParameters.NamedChildCount()) {
+				return false
+			}
+			for index, param := range nodeutil.NamedChildrenOf(methodParameters) {
+				paramType := nodeutil.ParameterType(param).Content(source)
+				if d.Parameters[index].OriginalType != paramType {
+					return false
+				}
+			}
+			return true
+		}
+
+		methodDefinition := ctx.currentClass.FindMethod().By(comparison)
+
+		if len(methodDefinition) == 0 {
+			log.WithFields(log.Fields{
+				"methodName": methodName.Name,
+			}).Panic("No matching definition found for method")
+		}
+
+		ctx.enterFunction(methodDefinition[0])
+		ctx.errorTarget = methodErrorTarget(ctx)
+
+		var body *ast.BlockStmt
+		switch {
+		case abstract:
+			// Abstract methods are implemented by a stub, so that the class still
+			// has every method, for when it is embedded by its subclasses
+			body = stubMethodBody(ctx, "abstract", methodName.Name)
+		case native:
+			body = stubMethodBody(ctx, "native", methodName.Name)
+			comments = append(comments, nativeMethodComments(node, ctx, source)...)
+		default:
+			body = ParseStmt(node.ChildByFieldName("body"), source, ctx).(*ast.BlockStmt)
+			completeWithoutError(body, ctx.localScope)
+			if ctx.localScope.IsSynchronized {
+				synchronizedMethod(node, body, source, ctx)
+			}
+		}
+		params := ParseNode(methodParameters, source, ctx).(*ast.FieldList)
+
+		if methodName.Name == "main" && len(ctx.localScope.Parameters) == 1 {
+			params = nil
+			body.List = append([]ast.Stmt{
+				&ast.AssignStmt{
+					Lhs: []ast.Expr{&ast.Ident{Name: ctx.localScope.Parameters[0].Name}},
+					Tok: token.DEFINE,
+					Rhs: []ast.Expr{
+						&ast.SelectorExpr{
+							X:   &ast.Ident{Name: "os"},
+							Sel: &ast.Ident{Name: "Args"},
+						},
+					},
+				},
+			}, body.List...)
+		}
+
+		var docGroup *ast.CommentGroup
+		if len(comments) > 0 {
+			docGroup = &ast.CommentGroup{List: comments}
+		}
+
+		results := errorResults(&ast.FieldList{
+			List: []*ast.Field{
+				{Type: &ast.Ident{Name: ctx.localScope.Type}},
+			},
+		}, ctx.localScope)
+
+		if ctx.localScope.RequiresHelper {
+			if receiverBaseType == nil {
+				log.WithFields(log.Fields{
+					"class":  ctx.className,
+					"method": ctx.localScope.Name,
+				}).Error("Receiver type missing for helper generation")
+				return []ast.Decl{&ast.BadDecl{}}
+			}
+			return genInstanceGenericHelperDecls(ctx, ctx.localScope, docGroup, params, results, body, receiverBaseType)
+		}
+
+		funcDecl := &ast.FuncDecl{
+			Doc:  docGroup,
+			Name: &ast.Ident{Name: ctx.localScope.Name},
+			Recv: receiver,
+			Type: &ast.FuncType{
+				Params:  params,
+				Results: results,
+			},
+			Body: body,
+		}
+		if static {
+			if len(ctx.localScope.TypeParameters) > 0 {
+				typeParamFields := make([]*ast.Field, len(ctx.localScope.TypeParameters))
+				for i, tp := range ctx.localScope.TypeParameters {
+					typeParamFields[i] = &ast.Field{
+						Names: []*ast.Ident{{Name: tp}},
+						Type:  &ast.Ident{Name: "any"},
+					}
+				}
+				funcDecl.Type.TypeParams = &ast.FieldList{List: typeParamFields}
+			}
+		} else if len(ctx.localScope.TypeParameters) > 0 {
+			log.WithFields(log.Fields{
+				"class":  ctx.className,
+				"method": ctx.localScope.Name,
+			}).Warn("Instance methods with type parameters are not supported in Go; type parameters ignored")
+		}
+		return []ast.Decl{funcDecl}
+	case "static_initializer":
+
+		ctx.enterFunction(&symbol.Definition{})
+		ctx.errorTarget = nil
+
+		// A block of `static`, which is run before the main function
+		return []ast.Decl{&ast.FuncDecl{
+			Name: &ast.Ident{Name: "init"},
+			Type: &ast.FuncType{
+				Params: &ast.FieldList{List: []*ast.Field{}},
+			},
+			Body: ParseStmt(node.NamedChild(0), source, ctx).(*ast.BlockStmt),
+		}}
+	}
+
+	panic("Unknown node type for declaration: " + node.Type())
+}
+
+// parseFieldDeclarations separates the fields that are declared in the members
+// of a class into the fields of its struct, and the global variables for its
+// static fields
+func parseFieldDeclarations(members []*sitter.Node, source []byte, ctx Ctx) (*ast.FieldList, *ast.GenDecl) {
+	fields := &ast.FieldList{}
+	globalVariables := &ast.GenDecl{Tok: token.VAR}
+
+	for _, child := range members {
+		if child.Type() == "field_declaration" {
+
+			var staticField, transientField, volatileModifier bool
+
+			comments := []*ast.Comment{}
+
+			// Handle any modifiers that the field might have
+			if child.NamedChild(0).Type() == "modifiers" {
+				for _, modifier := range nodeutil.UnnamedChildrenOf(child.NamedChild(0)) {
+					switch modifier.Type() {
+					case "static":
+						staticField = true
+					case "transient":
+						transientField = true
+					case "volatile":
+						volatileModifier = true
+					case "marker_annotation", "annotation":
+						modContent := modifier.Content(source)
+						comments = append(comments, &ast.Comment{Text: "//" + modContent})
+						if ExcludedAnnotations[modContent] {
+							// Skip this field if there is an ignored annotation
+							continue
+						}
+					}
+				}
+			}
+
+			// TODO: If a field is initialized to a value, that value is discarded
+
+			field := &ast.Field{}
+			if len(comments) > 0 {
+				field.Doc = &ast.CommentGroup{List: comments}
+			}
+
+			fieldName := child.ChildByFieldName("declarator").ChildByFieldName("name").Content(source)
+
+			fieldDef := ctx.currentClass.FindField().ByOriginalName(fieldName)[0]
+
+			field.Names, field.Type = []*ast.Ident{{Name: fieldDef.Name}}, &ast.Ident{Name: fieldDef.Type}
+			field.Tag = fieldTag(fieldDef, transientField)
+
+			if volatileModifier && !fieldDef.IsVolatile {
+				ctx.diagnostics.Report(child, volatileDiagnostic, "the volatile field `%s` isn't atomic, since `sync/atomic` has no type for `%s`", fieldName, fieldDef.OriginalType)
+			}
+
+			if staticField {
+				globalVariables.Specs = append(globalVariables.Specs, &ast.ValueSpec{Names: field.Names, Type: field.Type})
+			} else {
+				fields.List = append(fields.List, field)
+			}
+		}
+	}
+
+	return fields, globalVariables
+}
@@ -0,0 +1,101 @@
+package translate
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"sort"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// A Diagnostic is a note about a place in the Java source where the generated
+// code might not behave the same as the original code
+type Diagnostic struct {
+	// The file that the diagnostic is about
+	File string
+	// The position of the node that the diagnostic is about, starting from 1
+	Line, Column int
+	// The same position, as the position of the nodes that are generated from
+	// the node, or `token.NoPos` if the generated nodes don't have positions
+	Pos token.Pos
+	// A short name for the kind of problem, such as "null-dereference"
+	Kind    string
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s", d.File, d.Line, d.Column, d.Kind, d.Message)
+}
+
+// Diagnostics collects all the diagnostics that are reported while converting
+// a single file
+//
+// A nil `*Diagnostics` discards everything that is reported to it, so that
+// code reporting diagnostics doesn't need to check if they are being collected
+type Diagnostics struct {
+	file  string
+	items []Diagnostic
+	// The positions of the generated nodes, which the diagnostics refer to
+	sources *sourceMap
+}
+
+// NewDiagnostics creates an empty collection of diagnostics for the given file
+func NewDiagnostics(file string) *Diagnostics {
+	return &Diagnostics{file: file}
+}
+
+// Report records a diagnostic at the position of the given node
+func (d *Diagnostics) Report(node *sitter.Node, kind, format string, args ...interface{}) {
+	if d == nil {
+		return
+	}
+	point := node.StartPoint()
+	d.items = append(d.items, Diagnostic{
+		File:    d.file,
+		Line:    int(point.Row) + 1,
+		Column:  int(point.Column) + 1,
+		Pos:     d.sources.pos(node),
+		Kind:    kind,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// ReportAt records a diagnostic at the position of a generated node, which is
+// the position of the Java node that it was generated from
+func (d *Diagnostics) ReportAt(pos token.Pos, kind, format string, args ...interface{}) {
+	if d == nil {
+		return
+	}
+	position := d.sources.Position(pos)
+	d.items = append(d.items, Diagnostic{
+		File:    d.file,
+		Line:    position.Line,
+		Column:  position.Column,
+		Pos:     pos,
+		Kind:    kind,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Items returns all the diagnostics that have been reported, in the order that
+// they appear in the file
+func (d *Diagnostics) Items() []Diagnostic {
+	if d == nil {
+		return nil
+	}
+	sort.SliceStable(d.items, func(i, j int) bool {
+		if d.items[i].Line != d.items[j].Line {
+			return d.items[i].Line < d.items[j].Line
+		}
+		return d.items[i].Column < d.items[j].Column
+	})
+	return d.items
+}
+
+// Print writes every diagnostic to the given writer, one per line
+func (d *Diagnostics) Print(w io.Writer) {
+	for _, diagnostic := range d.Items() {
+		fmt.Fprintln(w, diagnostic)
+	}
+}
@@ -0,0 +1,129 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for a digest that isn't known to Go
+const digestDiagnostic = "digest"
+
+// The algorithms of `MessageDigest`, and the packages and functions that
+// create their `hash.Hash` in Go
+var digestAlgorithms = map[string]struct{ pkg, function string }{
+	"MD5":         {"md5", "New"},
+	"SHA":         {"sha1", "New"},
+	"SHA1":        {"sha1", "New"},
+	"SHA-1":       {"sha1", "New"},
+	"SHA-224":     {"sha256", "New224"},
+	"SHA-256":     {"sha256", "New"},
+	"SHA-384":     {"sha512", "New384"},
+	"SHA-512":     {"sha512", "New"},
+	"SHA-512/224": {"sha512", "New512_224"},
+	"SHA-512/256": {"sha512", "New512_256"},
+}
+
+// newChecksum creates one of the checksums of `java.util.zip`, which are a
+// `hash.Hash32` in Go, or returns nil if the class isn't one of them
+//
+// Ex: `new CRC32()` turns into `crc32.NewIEEE()`
+func newChecksum(className string) ast.Expr {
+	switch className {
+	case "CRC32":
+		return &ast.CallExpr{Fun: &ast.SelectorExpr{X: &ast.Ident{Name: "crc32"}, Sel: &ast.Ident{Name: "NewIEEE"}}}
+	case "CRC32C":
+		table := &ast.CallExpr{
+			Fun:  &ast.SelectorExpr{X: &ast.Ident{Name: "crc32"}, Sel: &ast.Ident{Name: "MakeTable"}},
+			Args: []ast.Expr{&ast.SelectorExpr{X: &ast.Ident{Name: "crc32"}, Sel: &ast.Ident{Name: "Castagnoli"}}},
+		}
+		return &ast.CallExpr{Fun: &ast.SelectorExpr{X: &ast.Ident{Name: "crc32"}, Sel: &ast.Ident{Name: "New"}}, Args: []ast.Expr{table}}
+	case "Adler32":
+		return &ast.CallExpr{Fun: &ast.SelectorExpr{X: &ast.Ident{Name: "adler32"}, Sel: &ast.Ident{Name: "New"}}}
+	}
+	return nil
+}
+
+// isDigestExpr determines if the given expression evaluates to a
+// `MessageDigest`
+func isDigestExpr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	if node.Type() == "method_invocation" && node.ChildByFieldName("object") != nil &&
+		node.ChildByFieldName("object").Content(source) == "MessageDigest" && node.ChildByFieldName("name").Content(source) == "getInstance" {
+		return true
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	return ok && stripJavaQualifier(javaType) == "MessageDigest"
+}
+
+// isChecksumExpr determines if the given expression evaluates to one of the
+// checksums of `java.util.zip`
+func isChecksumExpr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	if !ok {
+		return false
+	}
+	return stripJavaQualifier(javaType) == "Checksum" || newChecksum(stripJavaQualifier(javaType)) != nil
+}
+
+// digestMethod translates the methods of `MessageDigest`, and of checksums
+// such as `CRC32`, onto the `hash.Hash` that they are in Go, or returns nil if
+// the method isn't one of them
+//
+// Ex: `MessageDigest.getInstance("SHA-256")` turns into `sha256.New()`
+func digestMethod(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	objectNode := node.ChildByFieldName("object")
+	argumentNodes := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+	name := node.ChildByFieldName("name").Content(source)
+
+	if objectNode.Content(source) == "MessageDigest" && name == "getInstance" && len(args) == 1 {
+		if argumentNodes[0].Type() != "string_literal" {
+			return runtimeCall("MessageDigestInstance", args[0])
+		}
+		algorithm, _ := strconv.Unquote(argumentNodes[0].Content(source))
+		if constructor, ok := digestAlgorithms[strings.ToUpper(algorithm)]; ok {
+			return &ast.CallExpr{Fun: &ast.SelectorExpr{X: &ast.Ident{Name: constructor.pkg}, Sel: &ast.Ident{Name: constructor.function}}}
+		}
+		ctx.diagnostics.Report(node, digestDiagnostic, "`%s` has no equivalent in Go's standard library", node.Content(source))
+		return runtimeCall("MessageDigestInstance", args[0])
+	}
+
+	digest := isDigestExpr(objectNode, ctx, source)
+	if !digest && !isChecksumExpr(objectNode, ctx, source) {
+		return nil
+	}
+	method := func(name string, args ...ast.Expr) ast.Expr {
+		return &ast.CallExpr{Fun: &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: name}}, Args: args}
+	}
+
+	switch {
+	// Ex: `crc.update(data, 0, n)` turns into `crc.Write(data[0 : 0+n])`
+	case name == "update" && len(args) == 3:
+		return method("Write", &ast.SliceExpr{
+			X:    args[0],
+			Low:  args[1],
+			High: &ast.BinaryExpr{X: args[1], Op: token.ADD, Y: args[2]},
+		})
+	case name == "update" && len(args) == 1:
+		if isByteArrayExpr(argumentNodes[0], ctx, source) {
+			return method("Write", args[0])
+		}
+		// A single byte, which is an int for a checksum
+		return method("Write", &ast.CompositeLit{
+			Type: &ast.ArrayType{Elt: &ast.Ident{Name: "byte"}},
+			Elts: []ast.Expr{&ast.CallExpr{Fun: &ast.Ident{Name: "byte"}, Args: args}},
+		})
+	case name == "reset" && len(args) == 0:
+		return method("Reset")
+	case name == "digest" && digest && len(args) <= 1:
+		return runtimeCall("Digest", append([]ast.Expr{object}, args...)...)
+	case name == "getDigestLength" && digest && len(args) == 0:
+		return &ast.CallExpr{Fun: &ast.Ident{Name: "int32"}, Args: []ast.Expr{method("Size")}}
+	case name == "getValue" && !digest && len(args) == 0:
+		return &ast.CallExpr{Fun: &ast.Ident{Name: "int64"}, Args: []ast.Expr{method("Sum32")}}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDigestsAndChecksums(t *testing.T) {
+	helper := setupParseHelper(t, `
+package digest.sums;
+import java.security.MessageDigest;
+import java.util.zip.CRC32;
+public class Sums {
+    public static byte[] sha(byte[] data, String algorithm) throws Exception {
+        MessageDigest hash = MessageDigest.getInstance("SHA-256");
+        hash.update(data);
+        MessageDigest other = MessageDigest.getInstance(algorithm);
+        MessageDigest.getInstance("WHIRLPOOL");
+        return MessageDigest.getInstance("MD5").digest(hash.digest());
+    }
+    public static long crc(byte[] data, int b) {
+        CRC32 crc = new CRC32();
+        crc.update(data, 1, 2);
+        crc.update(b);
+        return crc.getValue();
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Sums.java")
+
+	out := renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		"import (\n\t\"crypto/md5\"\n\t\"crypto/sha256\"\n\t\"hash/crc32\"\n\t\"github.com/NickyBoy89/java2go/stdjava\"\n)",
+		"hash := sha256.New()",
+		"hash.Write(data)",
+		"other := stdjava.MessageDigestInstance(algorithm)",
+		"return stdjava.Digest(md5.New(), stdjava.Digest(hash))",
+		"crc := crc32.NewIEEE()",
+		"crc.Write(data[1 : 1+2])",
+		"crc.Write([]byte{byte(b)})",
+		"return int64(crc.Sum32())",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != digestDiagnostic || items[0].Line != 10 {
+		t.Errorf("Expected the unknown algorithm on line 10 to be reported, got %v", items)
+	}
+}
@@ -0,0 +1,42 @@
+package translate
+
+import (
+	"go/ast"
+
+	"github.com/NickyBoy89/java2go/astutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The keys and the values of the collections from Java's standard library,
+// such as maps and sets, are represented the same way wherever they appear, in
+// the types of the collections, in what is put into them, and in what is read
+// out of them, as decided by astutil.ElementType. A key, or a value that isn't
+// kept boxed, is the primitive of a wrapper class, so a box that is put into
+// the collection is unboxed, and a value that is kept boxed is boxed when a
+// primitive is put into it:
+//
+//	Integer count = 1;
+//	counts.put(word, count);   ->   counts[word] = *count
+//	counts.put(word, 1);       ->   counts[word] = stdjava.Box[int32](1), boxed
+
+// elementType returns the Go type of the keys or the values of a collection,
+// from their Java type
+func elementType(javaType string, key bool, ctx Ctx) ast.Expr {
+	return astutil.ElementType(javaType, javaTypeStringToGoTypeExpr(javaType, inScopeTypeParameters(ctx)), key)
+}
+
+// elementValue converts a value that is put into a collection, or that a key is
+// looked up by, into the type of the collection's keys or values
+func elementValue(node *sitter.Node, value ast.Expr, javaType string, key bool, ctx Ctx, source []byte) ast.Expr {
+	if !isBoxedType(javaType) || unwrapParentheses(node).Type() == "null_literal" {
+		return value
+	}
+	_, primitive := elementType(javaType, key, ctx).(*ast.Ident)
+	switch boxed := isBoxedExpr(node, ctx, source); {
+	case primitive && boxed:
+		return &ast.StarExpr{X: value}
+	case !primitive && !boxed:
+		return boxValue(javaType, value)
+	}
+	return value
+}
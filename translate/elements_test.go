@@ -0,0 +1,62 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NickyBoy89/java2go/astutil"
+)
+
+const elementsSource = `
+package util.elements;
+import java.util.*;
+public class Tally {
+    public static void count(Integer boxed, int primitive) {
+        Map<String, Integer> counts = new HashMap<>();
+        counts.put("boxed", boxed);
+        counts.put("primitive", primitive);
+        counts.merge("total", 1, Integer::sum);
+        TreeMap<Integer, Long> sorted = new TreeMap<>();
+        sorted.put(boxed, 2L);
+        Set<Integer> seen = new HashSet<>();
+        seen.add(boxed);
+    }
+}
+`
+
+func TestElementsUnboxed(t *testing.T) {
+	out := renderGoFileFromJava(t, elementsSource)
+	for _, expected := range []string{
+		`counts := make(map[string]int32)`,
+		`counts["boxed"] = *boxed`,
+		`counts["primitive"] = primitive`,
+		`stdjava.Merge(counts, "total", 1, stdjava.Sum[int32])`,
+		`sorted := stdjava.NewTreeMap[int32, int64]()`,
+		`sorted.Put(*boxed, int64(2))`,
+		`seen.Add(*boxed)`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestElementsBoxed(t *testing.T) {
+	astutil.BoxedElements = true
+	defer func() { astutil.BoxedElements = false }()
+
+	out := renderGoFileFromJava(t, elementsSource)
+	for _, expected := range []string{
+		`counts := make(map[string]*int32)`,
+		`counts["boxed"] = boxed`,
+		`counts["primitive"] = stdjava.Box[int32](primitive)`,
+		`stdjava.Merge(counts, "total", stdjava.Box[int32](1), stdjava.BoxedSum[int32])`,
+		`sorted := stdjava.NewTreeMap[int32, *int64]()`,
+		`sorted.Put(*boxed, stdjava.Box[int64](int64(2)))`,
+		`seen.Add(*boxed)`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package translate
+
+import (
+	"bufio"
+	"bytes"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"io"
+	"os"
+)
+
+// In the chunked mode, the declarations of a file are written to a temporary
+// file as soon as they are generated, instead of being kept in memory until the
+// whole file has been converted. The imports of the file are only known once
+// all of its declarations are, so the package clause and the imports are
+// written out last, ahead of the declarations that were written before them
+//
+// The output is the same as the output of the whole file, with the
+// declarations separated the same way that Go's printer separates them. Since
+// the whole file is never kept, the mode can't be combined with `-ast`, which
+// prints the whole tree of the file
+
+// A declEmitter writes out the declarations of a file as they are generated
+type declEmitter struct {
+	// The temporary file that holds the declarations that have been written
+	body *os.File
+	out  *bufio.Writer
+	// The packages that the written declarations refer to
+	used map[string]bool
+	// The kind of the last declaration that was written
+	last token.Token
+	// Whether the declarations are tidied before they are written
+	tidy bool
+	// Where the declarations that can't be printed as valid Go are reported,
+	// if they are checked, and the number of problems that were reported
+	strict  *Diagnostics
+	invalid int
+	// The first error that writing the declarations ran into
+	err error
+}
+
+// newDeclEmitter creates an emitter for the declarations of a file. With
+// `strict`, the declarations are checked before they are written, and their
+// problems are reported to it
+func newDeclEmitter(tidy bool, strict *Diagnostics) (*declEmitter, error) {
+	body, err := os.CreateTemp("", "java2go-*.go")
+	if err != nil {
+		return nil, err
+	}
+	return &declEmitter{body: body, out: bufio.NewWriter(body), used: make(map[string]bool), tidy: tidy, strict: strict}, nil
+}
+
+// declToken returns the kind of a declaration, which the printer puts a blank
+// line between, when it changes
+func declToken(decl ast.Decl) token.Token {
+	if decl, ok := decl.(*ast.GenDecl); ok {
+		return decl.Tok
+	}
+	return token.FUNC
+}
+
+// hasDoc determines if a declaration has a doc comment
+func hasDoc(decl ast.Decl) bool {
+	switch decl := decl.(type) {
+	case *ast.GenDecl:
+		return decl.Doc != nil
+	case *ast.FuncDecl:
+		return decl.Doc != nil
+	}
+	return false
+}
+
+// emit writes out declarations, after the ones that have been written already
+func (de *declEmitter) emit(decls ...ast.Decl) {
+	for _, decl := range decls {
+		if de.err != nil {
+			return
+		}
+		if function, ok := decl.(*ast.FuncDecl); ok && de.tidy && function.Body != nil {
+			tidyFunction(function)
+		}
+		if de.strict != nil {
+			de.invalid += validateOutput(decl, de.strict)
+		}
+		clearPositions(decl)
+		for path := range usedPackages(decl) {
+			de.used[path] = true
+		}
+
+		// The package clause is followed by a single newline, and the printer
+		// doesn't put a blank line before a declaration with a doc comment
+		separator := "\n"
+		switch {
+		case hasDoc(decl):
+			if de.last == token.ILLEGAL {
+				separator = ""
+			}
+		case de.last != token.ILLEGAL && declToken(decl) != de.last:
+			separator = "\n\n"
+		}
+		de.last = declToken(decl)
+		if _, de.err = de.out.WriteString(separator); de.err != nil {
+			return
+		}
+		de.err = printDecl(de.out, decl)
+	}
+}
+
+// printDecl prints a declaration the same way that it is printed in a file.
+// The printer only places the doc comments of a declaration properly in a file,
+// so the declaration is printed in a file of its own, without the package
+// clause and the newlines after it
+func printDecl(output io.Writer, decl ast.Decl) error {
+	var printed bytes.Buffer
+	if err := printer.Fprint(&printed, token.NewFileSet(), &ast.File{Name: &ast.Ident{Name: "_"}, Decls: []ast.Decl{decl}}); err != nil {
+		return err
+	}
+	body := bytes.TrimPrefix(bytes.TrimPrefix(printed.Bytes(), []byte("package _\n")), []byte("\n"))
+	_, err := output.Write(bytes.TrimSuffix(body, []byte("\n")))
+	return err
+}
+
+// finish writes the file to the output, with its package clause and imports
+// ahead of the declarations that were written, and removes the temporary file
+func (de *declEmitter) finish(output io.Writer, packageName string) error {
+	defer os.Remove(de.body.Name())
+	defer de.body.Close()
+	if de.err != nil {
+		return de.err
+	}
+	if err := de.out.Flush(); err != nil {
+		return err
+	}
+
+	header := &ast.File{Name: &ast.Ident{Name: packageName}}
+	if imports := importDecl(de.used); imports != nil {
+		header.Decls = []ast.Decl{imports}
+	}
+	if err := printer.Fprint(output, token.NewFileSet(), header); err != nil {
+		return err
+	}
+	if _, err := de.body.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.Copy(output, de.body); err != nil {
+		return err
+	}
+	// The file ends with a newline, after the last declaration
+	if de.last != token.ILLEGAL {
+		_, err := io.WriteString(output, "\n")
+		return err
+	}
+	return nil
+}
+
+// discard removes the temporary file, without writing the file to the output
+func (de *declEmitter) discard() {
+	de.body.Close()
+	os.Remove(de.body.Name())
+}
+
+// emitDecls writes out declarations right away in the chunked mode, and
+// returns the declarations that are left to be added to the file
+func emitDecls(ctx Ctx, decls []ast.Decl) []ast.Decl {
+	if ctx.emitter == nil {
+		return decls
+	}
+	if LineDirectives {
+		addLineDirectives(decls, ctx.sources)
+	}
+	ctx.emitter.emit(decls...)
+	return nil
+}
@@ -0,0 +1,71 @@
+package translate
+
+import (
+	"bytes"
+	"go/ast"
+	"strings"
+	"testing"
+)
+
+func TestChunkedOutput(t *testing.T) {
+	helper := setupParseHelper(t, `
+package emit.chunked;
+public class Shop {
+    private java.util.Map<String, Integer> stock = new java.util.HashMap<>();
+
+    /** Adds to the stock */
+    public void add(String name) {
+        stock.merge(name, 1, Integer::sum);
+    }
+
+    @Deprecated
+    public void clear() {
+        stock.clear();
+    }
+
+    public Runnable report() {
+        return new Runnable() {
+            public void run() {
+                System.out.println(stock.size());
+            }
+        };
+    }
+
+    public enum Size { SMALL, LARGE }
+
+    static class Counter {
+        int count;
+
+        void increment() {
+            synchronized (this) {
+                count++;
+            }
+        }
+    }
+}
+`)
+	whole := renderGoFileFromJavaCtx(t, helper)
+
+	emitter, err := newDeclEmitter(false, nil)
+	if err != nil {
+		t.Fatalf("Failed to create the emitter: %v", err)
+	}
+	helper.Ctx.emitter = emitter
+	file := ParseNode(helper.File.Ast, helper.File.Source, helper.Ctx).(*ast.File)
+	if len(file.Decls) != 0 {
+		t.Errorf("Expected every declaration to be written out, got %d left in the file", len(file.Decls))
+	}
+	var chunked bytes.Buffer
+	if err := emitter.finish(&chunked, file.Name.Name); err != nil {
+		t.Fatalf("Failed to finish the file: %v", err)
+	}
+
+	if chunked.String() != whole {
+		t.Errorf("Expected the chunked output to match the whole file:\n%s\ngot:\n%s", whole, chunked.String())
+	}
+	for _, expected := range []string{"import", "//@Deprecated"} {
+		if !strings.Contains(whole, expected) {
+			t.Errorf("Expected %q in the file, got:\n%s", expected, whole)
+		}
+	}
+}
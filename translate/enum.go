@@ -0,0 +1,471 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/NickyBoy89/java2go/codegen"
+	"github.com/NickyBoy89/java2go/nodeutil"
+	"github.com/NickyBoy89/java2go/symbol"
+	log "github.com/sirupsen/logrus"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for the interfaces that an enum's type
+// doesn't implement in Go
+const enumDiagnostic = "enum"
+
+// Enums whose constants don't carry any state are generated as an integer
+// type, with a constant for each of the enum's constants
+//
+// Otherwise, the enum is generated as a struct, and each constant is a pointer
+// to that struct, which is created by the enum's constructor. Java creates the
+// constants in the order that they are declared, before any of the enum's
+// static fields are initialized, so the constants are created in an `init`
+// function, which keeps this order, no matter which constants or fields are
+// referenced by the arguments to the constructor
+//
+// Constants can also override the enum's methods with their own bodies. Each
+// overridden method is stored as a func value in the struct, which is set for
+// every constant when it is created, and the enum's method calls it
+
+// enumMembers returns the fields, methods, and constructors declared in an enum
+func enumMembers(node *sitter.Node) []*sitter.Node {
+	for _, child := range nodeutil.NamedChildrenOf(node.ChildByFieldName("body")) {
+		if child.Type() == "enum_body_declarations" {
+			return nodeutil.NamedChildrenOf(child)
+		}
+	}
+	return nil
+}
+
+// enumStateDecls generates the declarations for an enum whose constants carry
+// their own state
+//
+// Ex:
+//
+//	enum Planet {
+//		EARTH(5.976e+24);
+//		private final double mass;
+//		Planet(double mass) { this.mass = mass; }
+//	}
+//
+// turns into a `Planet` struct with a `mass` field, a variable `EARTH` of type
+// `*Planet`, and an `init` function that assigns `newPlanet(5.976e+24)` to it
+func enumStateDecls(node *sitter.Node, source []byte, ctx Ctx) []ast.Decl {
+	members := enumMembers(node)
+	fields, globalVariables := parseFieldDeclarations(members, source, ctx)
+	enumType := &ast.StarExpr{X: &ast.Ident{Name: ctx.className}}
+
+	overrides := enumOverrides(node, source, ctx)
+	for _, override := range overrides {
+		fields.List = append(fields.List, &ast.Field{
+			Names: []*ast.Ident{{Name: override.field}},
+			Type:  override.funcType,
+		})
+	}
+
+	declarations := []ast.Decl{codegen.GenStruct(ctx.className, fields)}
+
+	constants := &ast.GenDecl{Tok: token.VAR}
+	initializer := &ast.BlockStmt{}
+
+	initCtx := ctx.Clone()
+	initCtx.enterFunction(&symbol.Definition{})
+
+	for _, child := range nodeutil.NamedChildrenOf(node.ChildByFieldName("body")) {
+		if child.Type() != "enum_constant" {
+			continue
+		}
+		name := &ast.Ident{Name: enumConstantName(ctx.currentClass, child.ChildByFieldName("name").Content(source))}
+		constants.Specs = append(constants.Specs, &ast.ValueSpec{Names: []*ast.Ident{name}, Type: enumType})
+
+		initializer.List = append(initializer.List, &ast.AssignStmt{
+			Lhs: []ast.Expr{&ast.Ident{Name: name.Name}},
+			Tok: token.ASSIGN,
+			Rhs: []ast.Expr{enumConstructorCall(child.ChildByFieldName("arguments"), source, initCtx)},
+		})
+
+		// Set the implementation of every overridden method for the constant
+		constantMethods := map[*symbol.Definition]*sitter.Node{}
+		if body := child.ChildByFieldName("body"); body != nil {
+			for _, member := range nodeutil.NamedChildrenOf(body) {
+				if member.Type() != "method_declaration" {
+					continue
+				}
+				if definition := findEnumMethod(ctx.currentClass, member, source); definition != nil {
+					constantMethods[definition] = member
+				} else {
+					log.WithField("method", member.ChildByFieldName("name").Content(source)).
+						Warn("Methods declared only in the body of an enum constant are not supported")
+				}
+			}
+		}
+		for _, override := range overrides {
+			var implementation ast.Expr
+			if method, ok := constantMethods[override.definition]; ok {
+				implementation = override.literal(method, source, ctx)
+			} else if override.fallback != nil {
+				// Ex: `(*Op).applyDefault`
+				implementation = &ast.SelectorExpr{
+					X:   &ast.ParenExpr{X: enumType},
+					Sel: &ast.Ident{Name: override.fallbackName},
+				}
+			} else {
+				// Abstract methods are always overridden by every constant
+				continue
+			}
+			initializer.List = append(initializer.List, &ast.AssignStmt{
+				Lhs: []ast.Expr{&ast.SelectorExpr{X: &ast.Ident{Name: name.Name}, Sel: &ast.Ident{Name: override.field}}},
+				Tok: token.ASSIGN,
+				Rhs: []ast.Expr{implementation},
+			})
+		}
+	}
+	if len(constants.Specs) > 0 {
+		declarations = append(declarations, constants)
+	}
+
+	// Static fields that are initialized to a constant value are initialized
+	// along with their declarations, since the enum's constants can refer to
+	// them, and all the others are initialized after the constants
+	for _, member := range members {
+		if member.Type() != "field_declaration" || !nodeutil.HasModifier(member, "static") {
+			continue
+		}
+		for _, declarator := range nodeutil.ChildrenByFieldName(member, "declarator") {
+			value := declarator.ChildByFieldName("value")
+			if value == nil {
+				continue
+			}
+			fieldDefs := ctx.currentClass.FindField().ByOriginalName(declarator.ChildByFieldName("name").Content(source))
+			if len(fieldDefs) == 0 {
+				continue
+			}
+			if isConstantExpression(value) {
+				for _, spec := range globalVariables.Specs {
+					if spec := spec.(*ast.ValueSpec); spec.Names[0].Name == fieldDefs[0].Name {
+						spec.Values = []ast.Expr{ParseExpr(value, source, initCtx)}
+					}
+				}
+				continue
+			}
+			initializer.List = append(initializer.List, &ast.AssignStmt{
+				Lhs: []ast.Expr{&ast.Ident{Name: fieldDefs[0].Name}},
+				Tok: token.ASSIGN,
+				Rhs: []ast.Expr{ParseExpr(value, source, initCtx)},
+			})
+		}
+	}
+	if len(globalVariables.Specs) > 0 {
+		declarations = append(declarations, globalVariables)
+	}
+
+	if len(initializer.List) > 0 {
+		declarations = append(declarations, &ast.FuncDecl{
+			Name: &ast.Ident{Name: "init"},
+			Type: &ast.FuncType{Params: &ast.FieldList{}},
+			Body: initializer,
+		})
+	}
+
+	for _, override := range overrides {
+		declarations = append(declarations, override.dispatch(ctx))
+		if override.fallback != nil {
+			fallback := ParseDecl(override.fallback, source, ctx)[0].(*ast.FuncDecl)
+			fallback.Name = &ast.Ident{Name: override.fallbackName}
+			declarations = append(declarations, fallback)
+		}
+	}
+
+	return declarations
+}
+
+// An enumOverride is a method of an enum that is overridden by at least one of
+// the enum's constants
+type enumOverride struct {
+	definition *symbol.Definition
+	// The name of the field that stores the method's implementation
+	field string
+	// The type of the field, which takes the receiver as its first parameter
+	funcType *ast.FuncType
+	params   *ast.FieldList
+	// The method declared in the enum, which is used by every constant that
+	// doesn't override it, or nil if the method is abstract
+	fallback *sitter.Node
+	// The name of the method that the fallback is generated as, which every
+	// constant that doesn't override the method shares
+	fallbackName string
+}
+
+// enumOverrides finds all the methods of an enum that its constants override
+func enumOverrides(node *sitter.Node, source []byte, ctx Ctx) []*enumOverride {
+	overridden := overriddenEnumMethods(node, source, ctx.currentClass)
+	if len(overridden) == 0 {
+		return nil
+	}
+
+	// The fields can't have the same names as any of the enum's fields or methods
+	names := symbol.NewNameAllocator()
+	for _, field := range ctx.currentClass.Fields {
+		names.Reserve(field.Name)
+	}
+	for _, method := range ctx.currentClass.Methods {
+		names.Reserve(method.Name)
+	}
+
+	var overrides []*enumOverride
+	for _, member := range enumMembers(node) {
+		if member.Type() != "method_declaration" {
+			continue
+		}
+		definition := findEnumMethod(ctx.currentClass, member, source)
+		if definition == nil || !overridden[definition] {
+			continue
+		}
+
+		methodCtx := ctx.Clone()
+		methodCtx.enterFunction(definition)
+		override := &enumOverride{
+			definition: definition,
+			field:      names.Allocate(symbol.Lowercase(definition.OriginalName) + "Func"),
+			params:     ParseNode(member.ChildByFieldName("parameters"), source, methodCtx).(*ast.FieldList),
+		}
+		if !nodeutil.HasModifier(member, "abstract") {
+			override.fallback = member
+			override.fallbackName = names.Allocate(symbol.Lowercase(definition.OriginalName) + "Default")
+		}
+
+		override.funcType = &ast.FuncType{
+			Params: &ast.FieldList{List: append([]*ast.Field{{
+				Names: []*ast.Ident{{Name: ReceiverName(ctx)}},
+				Type:  &ast.StarExpr{X: &ast.Ident{Name: ctx.className}},
+			}}, override.params.List...)},
+		}
+		if definition.Type != "" {
+			override.funcType.Results = &ast.FieldList{List: []*ast.Field{{Type: &ast.Ident{Name: definition.Type}}}}
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides
+}
+
+// dispatch generates the enum's method, which calls the implementation that is
+// stored for the constant that it is called on
+//
+// Ex: `func (op *Op) apply(a int32) int32 { return op.applyFunc(op, a) }`
+func (override *enumOverride) dispatch(ctx Ctx) ast.Decl {
+	receiver := &ast.Ident{Name: ReceiverName(ctx)}
+	args := []ast.Expr{receiver}
+	for _, param := range override.params.List {
+		for _, name := range param.Names {
+			args = append(args, &ast.Ident{Name: name.Name})
+		}
+	}
+	call := &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: receiver, Sel: &ast.Ident{Name: override.field}},
+		Args: args,
+	}
+
+	var body ast.Stmt = &ast.ExprStmt{X: call}
+	if override.funcType.Results != nil {
+		body = &ast.ReturnStmt{Results: []ast.Expr{call}}
+	}
+
+	return &ast.FuncDecl{
+		Name: &ast.Ident{Name: override.definition.Name},
+		Recv: &ast.FieldList{List: []*ast.Field{{
+			Names: []*ast.Ident{receiver},
+			Type:  &ast.StarExpr{X: &ast.Ident{Name: ctx.className}},
+		}}},
+		Type: &ast.FuncType{Params: override.params, Results: override.funcType.Results},
+		Body: &ast.BlockStmt{List: []ast.Stmt{body}},
+	}
+}
+
+// overriddenEnumMethods finds the methods of an enum that are overridden by the
+// bodies of any of its constants
+func overriddenEnumMethods(node *sitter.Node, source []byte, class *symbol.ClassScope) map[*symbol.Definition]bool {
+	overridden := map[*symbol.Definition]bool{}
+	for _, child := range nodeutil.NamedChildrenOf(node.ChildByFieldName("body")) {
+		if child.Type() != "enum_constant" || child.ChildByFieldName("body") == nil {
+			continue
+		}
+		for _, member := range nodeutil.NamedChildrenOf(child.ChildByFieldName("body")) {
+			if member.Type() != "method_declaration" {
+				continue
+			}
+			if definition := findEnumMethod(class, member, source); definition != nil {
+				overridden[definition] = true
+			}
+		}
+	}
+	return overridden
+}
+
+// findEnumMethod finds the definition of an enum's method that is declared, or
+// overridden, by the given method
+func findEnumMethod(class *symbol.ClassScope, method *sitter.Node, source []byte) *symbol.Definition {
+	name := method.ChildByFieldName("name").Content(source)
+	paramCount := int(method.ChildByFieldName("parameters").NamedChildCount())
+	for _, definition := range class.Methods {
+		if !definition.Constructor && !definition.IsStatic && definition.OriginalName == name && len(definition.Parameters) == paramCount {
+			return definition
+		}
+	}
+	return nil
+}
+
+// literal converts an implementation of the method into a function literal,
+// which takes the method's receiver as its first parameter
+func (override *enumOverride) literal(method *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	decl := ParseDecl(method, source, ctx)[0].(*ast.FuncDecl)
+	return &ast.FuncLit{
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{List: append(decl.Recv.List, decl.Type.Params.List...)},
+			Results: override.funcType.Results,
+		},
+		Body: decl.Body,
+	}
+}
+
+// enumConstructorCall creates one of the constants of an enum, using the
+// constructor that matches the types of its arguments, or that takes the same
+// number of them, when their types aren't known exactly
+func enumConstructorCall(arguments *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	var args []ast.Expr
+	var argumentTypes []string
+	if arguments != nil {
+		args = ParseNode(arguments, source, ctx).([]ast.Expr)
+		argumentTypes = make([]string, arguments.NamedChildCount())
+		for ind, argument := range nodeutil.NamedChildrenOf(arguments) {
+			argumentTypes[ind] = argumentJavaType(argument, ctx, source)
+		}
+	}
+
+	if constructor := findMatchingConstructor(ctx.currentClass, ctx.currentClass.Class.OriginalName, argumentTypes); constructor != nil {
+		return &ast.CallExpr{Fun: &ast.Ident{Name: constructor.Name}, Args: args}
+	}
+	for _, method := range ctx.currentClass.Methods {
+		if method.Constructor && len(method.Parameters) == len(args) {
+			return &ast.CallExpr{Fun: &ast.Ident{Name: method.Name}, Args: args}
+		}
+	}
+	// Without a constructor, the enum is created with its zero value
+	return &ast.CallExpr{Fun: &ast.Ident{Name: "new"}, Args: []ast.Expr{&ast.Ident{Name: ctx.className}}}
+}
+
+// isConstantExpression determines if an expression only consists of literals,
+// which makes it a compile-time constant in Java
+func isConstantExpression(node *sitter.Node) bool {
+	switch node.Type() {
+	case "decimal_integer_literal", "hex_integer_literal", "octal_integer_literal", "binary_integer_literal",
+		"decimal_floating_point_literal", "hex_floating_point_literal",
+		"string_literal", "character_literal", "true", "false":
+		return true
+	case "binary_expression", "unary_expression", "parenthesized_expression":
+		for _, child := range nodeutil.NamedChildrenOf(node) {
+			if !isConstantExpression(child) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// enumMemberReference returns the generated name of an enum constant, or one of
+// the enum's static fields, that is referred to without naming the enum, which
+// can only be done inside the enum
+func enumMemberReference(node *sitter.Node, ctx Ctx, source []byte) (string, bool) {
+	if ctx.currentClass == nil || !ctx.currentClass.IsEnum {
+		return "", false
+	}
+	// The names of fields and methods aren't references to variables
+	if parent := node.Parent(); parent != nil {
+		switch parent.Type() {
+		case "field_access":
+			if parent.ChildByFieldName("field").Equal(node) {
+				return "", false
+			}
+		case "method_invocation":
+			if parent.ChildByFieldName("name").Equal(node) {
+				return "", false
+			}
+		}
+	}
+	name := node.Content(source)
+	if ctx.localScope != nil && ctx.localScope.FindVariable(name, node.StartByte()) != nil {
+		return "", false
+	}
+	if generated, ok := ctx.currentClass.EnumConstantNames[name]; ok {
+		return generated, true
+	}
+	for _, field := range ctx.currentClass.FindField().ByOriginalName(name) {
+		if field.IsStatic {
+			return field.Name, true
+		}
+	}
+	return "", false
+}
+
+// enumInterfaceAssertions checks that the enum implements every interface that
+// it is declared to implement, if the interface is declared in the package
+//
+// Methods that the constants implement in their own bodies aren't methods of
+// the enum's type, so an interface that the enum's own methods don't cover is
+// reported instead of asserted, since the assertion wouldn't compile
+//
+// Ex: `enum State implements Toggle` generates `var _ Toggle = ON`
+func enumInterfaceAssertions(node *sitter.Node, source []byte, ctx Ctx, constants []ast.Expr) []ast.Decl {
+	interfaces := node.ChildByFieldName("interfaces")
+	if interfaces == nil || len(constants) == 0 {
+		return nil
+	}
+
+	assertions := &ast.GenDecl{Tok: token.VAR}
+	for _, typeList := range nodeutil.NamedChildrenOf(interfaces) {
+		for _, implemented := range nodeutil.NamedChildrenOf(typeList) {
+			if implemented.Type() != "type_identifier" {
+				continue
+			}
+			class := resolveClassScopeByName(ctx, implemented.Content(source))
+			if class == nil || !class.IsInterface {
+				continue
+			}
+			if missing := unimplementedMethod(ctx.currentClass, class, ctx); missing != nil {
+				ctx.diagnostics.Report(implemented, enumDiagnostic, "`%s` doesn't declare `%s` of `%s` itself, so its type doesn't implement the interface in Go",
+					ctx.currentClass.Class.OriginalName, missing.OriginalName, class.Class.OriginalName)
+				continue
+			}
+			assertions.Specs = append(assertions.Specs, &ast.ValueSpec{
+				Names:  []*ast.Ident{{Name: "_"}},
+				Type:   &ast.Ident{Name: class.Class.Name},
+				Values: []ast.Expr{constants[0]},
+			})
+		}
+	}
+	if len(assertions.Specs) == 0 {
+		return nil
+	}
+	return []ast.Decl{assertions}
+}
+
+// unimplementedMethod returns a method of an interface, or of the interfaces
+// that it extends, that a class doesn't declare itself, or nil if the class
+// declares all of them
+func unimplementedMethod(class, implemented *symbol.ClassScope, ctx Ctx) *symbol.Definition {
+	for _, method := range implemented.Methods {
+		if !method.IsStatic && !implementsMethod(class, method, ctx) {
+			return method
+		}
+	}
+	for _, extended := range implemented.Interfaces {
+		if parent := resolveClassScopeByName(ctx, extended); parent != nil && parent != implemented {
+			if missing := unimplementedMethod(class, parent, ctx); missing != nil {
+				return missing
+			}
+		}
+	}
+	return nil
+}
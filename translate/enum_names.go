@@ -0,0 +1,96 @@
+package translate
+
+import (
+	"github.com/NickyBoy89/java2go/nodeutil"
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// resolveEnumConstants names the constants of an enum
+//
+// Enum constants are generated as package-level constants, so a constant that
+// has the same name as a constant in another enum, or any other declaration in
+// the package, is qualified with the name of its enum
+//
+// Ex: `RED` in both `Color` and `Light` turns into `ColorRED` and `LightRED`
+func resolveEnumConstants(class *symbol.ClassScope, packageScope *symbol.PackageScope) {
+	if !class.IsEnum || class.EnumConstantNames != nil {
+		return
+	}
+	class.EnumConstantNames = make(map[string]string)
+	for _, constant := range class.EnumConstants {
+		candidates := []string{constant, class.Class.Name + constant}
+		if enumConstantIsShared(class, constant, packageScope) {
+			candidates = candidates[1:]
+		}
+		class.EnumConstantNames[constant] = packageScope.Names().AllocateFor(class.Class.Name+"."+constant, candidates...)
+	}
+}
+
+// enumConstantIsShared determines if another enum in the package declares a
+// constant with the same name
+func enumConstantIsShared(class *symbol.ClassScope, constant string, packageScope *symbol.PackageScope) bool {
+	for _, other := range packageScope.Classes() {
+		if other == class || !other.IsEnum {
+			continue
+		}
+		for _, otherConstant := range other.EnumConstants {
+			if otherConstant == constant {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// enumConstantName returns the generated name of an enum constant
+func enumConstantName(class *symbol.ClassScope, constant string) string {
+	if name, ok := class.EnumConstantNames[constant]; ok {
+		return name
+	}
+	return constant
+}
+
+// enumValuesName returns the name of the function that implements an enum's
+// `values()` method, which is the same at its declaration and every call
+func enumValuesName(class *symbol.ClassScope, packageName string) string {
+	name := EnumHelperPrefix + class.Class.Name + "Values"
+	if packageScope := symbol.GlobalScope.FindPackage(packageName); packageScope != nil {
+		return packageScope.Names().AllocateFor(class.Class.Name+".values()", name)
+	}
+	return name
+}
+
+// markEnumValuesUsage finds every call to an enum's `values()` method, so that
+// the function that implements it is only generated if it is used
+func markEnumValuesUsage(node *sitter.Node, source []byte, packageScope *symbol.PackageScope, enclosingEnum *symbol.ClassScope) {
+	switch node.Type() {
+	case "enum_declaration":
+		enclosingEnum = findPackageClass(packageScope, node.ChildByFieldName("name").Content(source))
+	case "class_declaration", "interface_declaration", "record_declaration":
+		enclosingEnum = nil
+	case "method_invocation":
+		if node.ChildByFieldName("name").Content(source) == "values" && node.ChildByFieldName("arguments").NamedChildCount() == 0 {
+			target := enclosingEnum
+			if object := node.ChildByFieldName("object"); object != nil {
+				target = findPackageClass(packageScope, object.Content(source))
+			}
+			if target != nil && target.IsEnum {
+				target.ValuesUsed = true
+			}
+		}
+	}
+	for _, child := range nodeutil.NamedChildrenOf(node) {
+		markEnumValuesUsage(child, source, packageScope, enclosingEnum)
+	}
+}
+
+// findPackageClass finds a class in the package by its name in the Java source
+func findPackageClass(packageScope *symbol.PackageScope, name string) *symbol.ClassScope {
+	for _, class := range packageScope.Classes() {
+		if class.Class.OriginalName == name {
+			return class
+		}
+	}
+	return nil
+}
@@ -0,0 +1,393 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// In the errors mode, a method with a `throws` clause returns what it throws
+// as an extra `error` result, instead of panicking with it:
+//
+//	int read(String name) throws IOException
+//
+// becomes
+//
+//	func Read(name string) (int32, error)
+//
+// A `throw` in the method returns the exception, along with the zero value of
+// the method's result, and the callers of the method check the error that it
+// returns where they call it, returning it in turn:
+//
+//	line, err := read(name)
+//	if err != nil {
+//		return 0, err
+//	}
+//
+// Inside of a try statement, the error is returned out of the function literal
+// that the try block is lowered into instead, and the catch clauses check it
+// the same way as a panic. The runtime, and the exceptions of Go's runtime,
+// still panic in this mode, so a lowered try block recovers from them as well
+//
+// Where nothing can be returned, such as in a constructor, `main`, a lambda, or
+// a method that doesn't declare what it throws, the error is a panic again. The
+// bodies of try-with-resources statements, and of try statements whose finally
+// block completes abruptly, are run in function literals of their own, so they
+// panic as well. So does a call that is evaluated after anything other than
+// variables in its statement, since checking its error first would change the
+// order of what the statement does
+
+// An errorTarget is where the errors of the methods that are called are
+// returned to
+type errorTarget struct {
+	// The values that are returned before the error, which is the zero value of
+	// the method's result, if it has one
+	results []ast.Expr
+	// Whether the error is returned out of a lowered try block, which assigns
+	// it to the block's named result, and returns the rest of them as they are
+	tryBlock bool
+	// The returns out of a lowered try block that return an error, which are
+	// left as they are by its completion rewriter
+	propagations map[*ast.ReturnStmt]bool
+	// The names of the variables that hold the values of the calls, which are
+	// unique across the method
+	names *symbol.NameAllocator
+}
+
+// methodThrows determines if a method returns what it throws as an error
+//
+// Constructors still panic, and so does `main`, which can't return anything
+// in Go
+func methodThrows(def *symbol.Definition) bool {
+	return ErrorReturns && def != nil && len(def.Throws) > 0 && !def.Constructor &&
+		!(def.IsStatic && def.OriginalName == "main")
+}
+
+// returnsError determines if the returns in the current method return an error
+// along with their value
+func returnsError(ctx Ctx) bool {
+	return ctx.errorTarget != nil && methodThrows(ctx.localScope)
+}
+
+// methodErrorTarget returns where the errors in the body of a method are
+// returned to, or nil if the method doesn't return them
+func methodErrorTarget(ctx Ctx) *errorTarget {
+	if !methodThrows(ctx.localScope) {
+		return nil
+	}
+	target := &errorTarget{names: localVariableNames(ctx)}
+	if ctx.localScope.Type != "" {
+		target.results = []ast.Expr{zeroValue(ctx.localScope.Type)}
+	}
+	return target
+}
+
+// tryErrorTarget returns where the errors in the body of a try block are
+// returned to, in the errors mode
+func tryErrorTarget(ctx Ctx) *errorTarget {
+	if !ErrorReturns {
+		return nil
+	}
+	target := &errorTarget{tryBlock: true, propagations: make(map[*ast.ReturnStmt]bool), names: localVariableNames(ctx)}
+	if ctx.errorTarget != nil {
+		target.names = ctx.errorTarget.names
+	}
+	return target
+}
+
+// errorResults adds the `error` result to the results of a method that
+// returns what it throws
+func errorResults(results *ast.FieldList, def *symbol.Definition) *ast.FieldList {
+	if !methodThrows(def) {
+		return results
+	}
+	errorResult := &ast.Field{Type: &ast.Ident{Name: "error"}}
+	if def.Type == "" {
+		return &ast.FieldList{List: []*ast.Field{errorResult}}
+	}
+	return &ast.FieldList{List: append(results.List, errorResult)}
+}
+
+// completeWithoutError returns nil at the end of a void method that returns
+// what it throws, if it doesn't end with a return already
+func completeWithoutError(body *ast.BlockStmt, def *symbol.Definition) {
+	if !methodThrows(def) || def.Type != "" {
+		return
+	}
+	if len(body.List) > 0 {
+		if _, returns := body.List[len(body.List)-1].(*ast.ReturnStmt); returns {
+			return
+		}
+	}
+	body.List = append(body.List, &ast.ReturnStmt{Results: []ast.Expr{&ast.Ident{Name: "nil"}}})
+}
+
+// zeroValue returns the zero value of a Go type
+func zeroValue(goType string) ast.Expr {
+	switch goType {
+	case "bool":
+		return &ast.Ident{Name: "false"}
+	case "string":
+		return &ast.BasicLit{Kind: token.STRING, Value: `""`}
+	case "byte", "rune", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return &ast.BasicLit{Kind: token.INT, Value: "0"}
+	case "any", "error":
+		return &ast.Ident{Name: "nil"}
+	}
+	for _, prefix := range []string{"*", "[]", "map[", "func(", "chan ", "interface{"} {
+		if strings.HasPrefix(goType, prefix) {
+			return &ast.Ident{Name: "nil"}
+		}
+	}
+	// Ex: `*new(T)`, for a type parameter
+	return &ast.StarExpr{X: &ast.CallExpr{Fun: &ast.Ident{Name: "new"}, Args: []ast.Expr{&ast.Ident{Name: goType}}}}
+}
+
+// propagate returns an error to where it is returned to, or panics with it if
+// there is nowhere to return it
+func (t *errorTarget) propagate(err ast.Expr) []ast.Stmt {
+	if t == nil {
+		return []ast.Stmt{&ast.ExprStmt{X: &ast.CallExpr{Fun: &ast.Ident{Name: "panic"}, Args: []ast.Expr{err}}}}
+	}
+	if t.tryBlock {
+		propagation := &ast.ReturnStmt{}
+		t.propagations[propagation] = true
+		return []ast.Stmt{
+			&ast.AssignStmt{Lhs: []ast.Expr{&ast.Ident{Name: "_thrown"}}, Tok: token.ASSIGN, Rhs: []ast.Expr{err}},
+			propagation,
+		}
+	}
+	return []ast.Stmt{&ast.ReturnStmt{Results: append(append([]ast.Expr{}, t.results...), err)}}
+}
+
+// checkError propagates an error if there was one
+//
+// Ex: `if err != nil { return 0, err }`
+func (t *errorTarget) checkError(err *ast.Ident) *ast.IfStmt {
+	return &ast.IfStmt{
+		Cond: &ast.BinaryExpr{X: err, Op: token.NEQ, Y: &ast.Ident{Name: "nil"}},
+		Body: &ast.BlockStmt{List: t.propagate(err)},
+	}
+}
+
+// throwingMethod returns the definition of the method that a method invocation
+// calls, if the method returns what it throws, or nil otherwise
+func throwingMethod(node *sitter.Node, ctx Ctx, source []byte) *symbol.Definition {
+	if !ErrorReturns {
+		return nil
+	}
+	for _, def := range calledMethods(node, ctx, source) {
+		if methodThrows(def) {
+			return def
+		}
+	}
+	return nil
+}
+
+// checkedCall translates a call to a method that returns what it throws, in
+// the middle of an expression, by hoisting the call before the statement that
+// it is in, and checking its error there. The call is replaced by its value
+//
+// The operands that are evaluated before the call are evaluated after it in
+// Go, so the variables that they read are copied before it, and a call after
+// anything else, or that is only evaluated conditionally, or that can't be
+// hoisted out of where it is, panics with its error instead
+//
+// Ex: `total += read(name)` becomes
+// `readResult, err := read(name); if err != nil { ... }; total += readResult`
+func checkedCall(node *sitter.Node, method *symbol.Definition, source []byte, ctx Ctx) ast.Expr {
+	call := parseExprNode(node, source, ctx)
+	// A void method is only called for what it does, such as in the update of
+	// a for loop
+	if method.Type == "" {
+		return runtimeCall("Check", call)
+	}
+	if ctx.errorTarget == nil || ctx.hoisted == nil || !isHoistedCall(node, source) {
+		return runtimeCall("Must", call)
+	}
+
+	value := &ast.Ident{Name: ctx.errorTarget.names.Allocate(method.OriginalName + "Result")}
+	err := &ast.Ident{Name: ctx.errorTarget.names.AllocateFor("err", "err")}
+	ctx.hoisted.hoist(&ast.AssignStmt{Lhs: []ast.Expr{value, err}, Tok: token.DEFINE, Rhs: []ast.Expr{call}})
+	ctx.hoisted.hoist(ctx.errorTarget.checkError(err))
+	return value
+}
+
+// isHoistedCall determines if a call can be hoisted before the statement that
+// it is in, which is when it is always evaluated, and everything that is
+// evaluated before it only reads variables
+func isHoistedCall(node *sitter.Node, source []byte) bool {
+	if isConditionallyEvaluated(node) {
+		return false
+	}
+	for _, operand := range earlierOperands(node) {
+		if !readsOnlyVariables(operand) {
+			return false
+		}
+	}
+	return true
+}
+
+// readsOnlyVariables determines if an operand has no side effects, other than
+// reading variables, which can be copied before a call that is hoisted
+func readsOnlyVariables(node *sitter.Node) bool {
+	switch node.Type() {
+	case "decimal_integer_literal", "hex_integer_literal", "octal_integer_literal", "binary_integer_literal",
+		"decimal_floating_point_literal", "hex_floating_point_literal", "string_literal", "character_literal",
+		"true", "false", "null_literal", "this", "identifier":
+		return true
+	case "parenthesized_expression":
+		return readsOnlyVariables(node.NamedChild(0))
+	case "cast_expression":
+		return readsOnlyVariables(node.ChildByFieldName("value"))
+	case "unary_expression":
+		return readsOnlyVariables(node.ChildByFieldName("operand"))
+	case "binary_expression":
+		return readsOnlyVariables(node.ChildByFieldName("left")) && readsOnlyVariables(node.ChildByFieldName("right"))
+	}
+	return false
+}
+
+// isReadBeforeCheckedCall determines if a variable is read before a call that
+// is hoisted before the statement for its error, which could change the
+// variable, unless it is a local one
+//
+// Ex: `count + read(name)` becomes `countOld := this.count`, before the call
+func isReadBeforeCheckedCall(read *sitter.Node, source []byte, ctx Ctx) bool {
+	if !ErrorReturns || ctx.errorTarget == nil || ctx.localScope != nil && ctx.localScope.FindVariable(read.Content(source), read.StartByte()) != nil {
+		return false
+	}
+	root := read
+	for root.Parent() != nil && !isStatementRoot(root) {
+		root = root.Parent()
+	}
+
+	var hoistedAfter func(node *sitter.Node) bool
+	hoistedAfter = func(node *sitter.Node) bool {
+		if node.StartByte() >= read.EndByte() && node.Type() == "method_invocation" {
+			if method := throwingMethod(node, ctx, source); method != nil && method.Type != "" && isHoistedCall(node, source) {
+				for _, operand := range earlierOperands(node) {
+					if operand.StartByte() <= read.StartByte() && read.EndByte() <= operand.EndByte() {
+						return true
+					}
+				}
+			}
+		}
+		for _, child := range nodeutil.NamedChildrenOf(node) {
+			if hoistedAfter(child) {
+				return true
+			}
+		}
+		return false
+	}
+	return hoistedAfter(root)
+}
+
+// isConditionallyEvaluated determines if an expression is only evaluated
+// depending on the rest of the statement that it is in, such as the right
+// side of a `&&`, or a branch of a ternary expression
+func isConditionallyEvaluated(node *sitter.Node) bool {
+	for child, parent := node, node.Parent(); parent != nil; child, parent = parent, parent.Parent() {
+		switch parent.Type() {
+		case "ternary_expression":
+			if parent.ChildByFieldName("condition") != child {
+				return true
+			}
+		case "binary_expression":
+			switch parent.ChildByFieldName("operator").Type() {
+			case "&&", "||":
+				if parent.ChildByFieldName("right") == child {
+					return true
+				}
+			}
+		case "lambda_expression", "switch_expression", "block", "expression_statement", "local_variable_declaration":
+			return false
+		}
+		if strings.HasSuffix(parent.Type(), "_statement") {
+			return false
+		}
+	}
+	return false
+}
+
+// checkedCallStmt translates a call to a method that returns what it throws,
+// which is the whole of an expression statement, or returns nil if the
+// expression isn't one
+//
+// Ex: `write(name);` becomes `if err := write(name); err != nil { ... }`
+func checkedCallStmt(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	method := throwingMethod(node, ctx, source)
+	if method == nil {
+		return nil
+	}
+	err := &ast.Ident{Name: "err"}
+	assigned := []ast.Expr{err}
+	if method.Type != "" {
+		assigned = []ast.Expr{&ast.Ident{Name: "_"}, err}
+	}
+	check := ctx.errorTarget.checkError(err)
+	check.Init = &ast.AssignStmt{Lhs: assigned, Tok: token.DEFINE, Rhs: []ast.Expr{parseExprNode(node, source, ctx)}}
+	return check
+}
+
+// checkedDeclaration translates the declaration of a single variable, whose
+// value is a call to a method that returns what it throws, or returns nil if
+// the declaration isn't one
+//
+// Ex: `String line = read(name);` becomes
+// `line, err := read(name); if err != nil { ... }`
+func checkedDeclaration(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	// The declaration is split into two statements, so it has to be in a block
+	switch node.Parent().Type() {
+	case "block", "constructor_body", "switch_block_statement_group":
+	default:
+		return nil
+	}
+	declarators := nodeutil.ChildrenByFieldName(node, "declarator")
+	if len(declarators) != 1 || declarators[0].ChildByFieldName("value") == nil {
+		return nil
+	}
+	value := declarators[0].ChildByFieldName("value")
+	method := throwingMethod(value, ctx, source)
+	if method == nil || method.Type == "" {
+		return nil
+	}
+
+	err := &ast.Ident{Name: localVariableNames(ctx).AllocateFor("err", "err")}
+	call := parseExprNode(value, source, ctx)
+	declaration := []ast.Stmt{
+		&ast.AssignStmt{
+			Lhs: []ast.Expr{ParseExpr(declarators[0].ChildByFieldName("name"), source, ctx), err},
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{call},
+		},
+		ctx.errorTarget.checkError(err),
+	}
+	// Anything that is hoisted out of the arguments of the call is declared in
+	// the same block as the variable
+	if ctx.hoisted != nil {
+		declaration = append(ctx.hoisted.list, declaration...)
+		ctx.hoisted.list = nil
+	}
+	return &ast.BlockStmt{List: declaration}
+}
+
+// checkedReturn translates the return of a call to a method that returns what
+// it throws, from a method with the same result, which returns the results of
+// the call as they are, or returns nil if the return isn't one
+//
+// Ex: `return read(name);` stays `return read(name)`
+func checkedReturn(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	if ctx.errorTarget == nil || ctx.errorTarget.tryBlock || !methodThrows(ctx.localScope) {
+		return nil
+	}
+	value := unwrapParentheses(node)
+	if method := throwingMethod(value, ctx, source); method == nil || method.Type != ctx.localScope.Type {
+		return nil
+	}
+	return &ast.ReturnStmt{Results: []ast.Expr{parseExprNode(value, source, ctx)}}
+}
@@ -0,0 +1,77 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestErrorReturns(t *testing.T) {
+	ErrorReturns = true
+	defer func() { ErrorReturns = false }()
+
+	out := renderGoFileFromJava(t, `
+package errs.mode;
+import java.io.IOException;
+public class Files {
+    static String read(String name) throws IOException {
+        if (name.isEmpty()) {
+            throw new IOException("empty name");
+        }
+        return name;
+    }
+    static void write(String name) throws IOException {
+        read(name);
+    }
+    static int total(String a, String b) throws IOException {
+        String first = read(a);
+        int size = first.length() + read(b).length();
+        write(b);
+        return size;
+    }
+    static int log(String message) { return 0; }
+    static int logged(String a) throws IOException {
+        int t = log("first") + read(a).length();
+        return t;
+    }
+    int count;
+    int counted(String a) throws IOException {
+        return count + read(a).length();
+    }
+    static String delegate(String a) throws IOException {
+        return read(a);
+    }
+    static int safe(String a) {
+        try {
+            write(read(a));
+        } catch (IOException e) {
+            return -1;
+        }
+        Runnable r = () -> {
+            String v = read(a);
+        };
+        return 0;
+    }
+}
+`)
+	for _, expected := range []string{
+		"func read(name string) (string, error) {",
+		`return "", stdjava.NewException("IOException", "empty name", nil)`,
+		"return name, nil",
+		"func write(name string) error {\n\tif _, err := read(name); err != nil {\n\t\treturn err\n\t}\n\treturn nil\n}",
+		"first, err := read(a)\n\tif err != nil {\n\t\treturn 0, err\n\t}",
+		"size := first.length() + stdjava.Must(read(b)).length()",
+		`t := log("first") + stdjava.Must(read(a)).length()`,
+		"countOld := count\n\t\treadResult, err := read(a)\n\t\tif err != nil {\n\t\t\treturn 0, err\n\t\t}\n\t\treturn countOld + readResult.length(), nil",
+		"if err := write(b); err != nil {\n\t\treturn 0, err\n\t}",
+		"return read(a)\n}",
+		"if thrown := recover(); thrown != nil {\n\t\t\t\t\t_thrown = thrown\n\t\t\t\t}",
+		"readResult, err := read(a)\n\t\t\tif err != nil {\n\t\t\t\t_thrown = err\n\t\t\t\treturn\n\t\t\t}",
+		"if err := write(readResult); err != nil {\n\t\t\t\t_thrown = err\n\t\t\t\treturn\n\t\t\t}",
+		`if _, ok := stdjava.Catch(_tryPanic, "IOException"); ok {`,
+		"v, err := read(a)\n\t\tif err != nil {\n\t\t\tpanic(err)\n\t\t}",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
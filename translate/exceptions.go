@@ -0,0 +1,149 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The methods of Java's `Throwable`, and their names on the runtime exception type
+var throwableMethods = map[string]string{
+	"getMessage":          "GetMessage",
+	"getLocalizedMessage": "GetLocalizedMessage",
+	"getCause":            "GetCause",
+	"printStackTrace":     "PrintStackTrace",
+	"toString":            "Error",
+}
+
+// isExceptionClass determines if a Java class name refers to an exception,
+// going by Java's naming conventions for `Throwable` and its subclasses
+func isExceptionClass(name string) bool {
+	base, _ := parseJavaTypeString(name)
+	base = stripJavaQualifier(base)
+	return base == "Throwable" || strings.HasSuffix(base, "Exception") || strings.HasSuffix(base, "Error")
+}
+
+// isExceptionExpr determines if the given expression evaluates to an exception
+func isExceptionExpr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	// The cause of an exception is another exception
+	if node.Type() == "method_invocation" && node.ChildByFieldName("object") != nil &&
+		node.ChildByFieldName("name").Content(source) == "getCause" {
+		return true
+	}
+	if node.Type() == "identifier" && isCaughtException(node, source) {
+		return true
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	return ok && isExceptionClass(javaType)
+}
+
+// newRuntimeException translates the creation of an exception class that has
+// no generated type of its own into the runtime's exception type, keeping the
+// message and the cause of the exception
+//
+// Ex: `new IllegalStateException("message", cause)` turns into
+// `stdjava.NewException("IllegalStateException", "message", cause)`
+func newRuntimeException(className string, argumentNodes []*sitter.Node, arguments []ast.Expr, argumentTypes []string, ctx Ctx, source []byte) ast.Expr {
+	message, cause := exceptionArguments(argumentNodes, arguments, argumentTypes, ctx, source)
+	return &ast.CallExpr{
+		Fun: &ast.SelectorExpr{
+			X:   &ast.Ident{Name: "stdjava"},
+			Sel: &ast.Ident{Name: "NewException"},
+		},
+		Args: []ast.Expr{
+			&ast.BasicLit{Kind: token.STRING, Value: `"` + stripJavaQualifier(className) + `"`},
+			message,
+			cause,
+		},
+	}
+}
+
+// exceptionArguments returns the message and the cause that an exception is
+// constructed with, from the arguments of its constructor
+func exceptionArguments(argumentNodes []*sitter.Node, arguments []ast.Expr, argumentTypes []string, ctx Ctx, source []byte) (ast.Expr, ast.Expr) {
+	var message ast.Expr = &ast.BasicLit{Kind: token.STRING, Value: `""`}
+	var cause ast.Expr = &ast.Ident{Name: "nil"}
+
+	switch {
+	case len(arguments) == 1:
+		// An exception can be constructed either from a message, or from its cause
+		if isExceptionClass(argumentTypes[0]) || isExceptionExpr(argumentNodes[0], ctx, source) ||
+			argumentNodes[0].Type() == "object_creation_expression" && isExceptionClass(argumentNodes[0].ChildByFieldName("type").Content(source)) {
+			cause = arguments[0]
+		} else {
+			message = arguments[0]
+		}
+	case len(arguments) >= 2:
+		// Any other arguments, such as `enableSuppression`, have no equivalent
+		message, cause = arguments[0], arguments[1]
+	}
+	return message, cause
+}
+
+// A class that extends one of Java's exceptions is generated as a struct that
+// embeds the runtime's exception type, which keeps its message and its cause,
+// so that the class implements `error` and the rest of `stdjava.Throwable`:
+//
+//	type ValidationException struct {
+//		stdjava.JavaException
+//		field string
+//	}
+//
+// The `super` call of each of its constructors initializes the embedded
+// exception, with the name of the class and the message and the cause that
+// are passed to it
+
+// isExceptionType determines if a class extends one of the exceptions of
+// Java's standard library, either directly or through other exception classes
+func isExceptionType(class *symbol.ClassScope, ctx Ctx) bool {
+	for visited := map[*symbol.ClassScope]bool{}; class != nil && !visited[class] && class.Superclass != ""; {
+		visited[class] = true
+		superclass := resolveClassScopeByName(ctx, class.Superclass)
+		if superclass == nil {
+			return isExceptionClass(class.Superclass)
+		}
+		class = superclass
+	}
+	return false
+}
+
+// embeddedException is the field of an exception class that embeds the
+// runtime's exception type
+func embeddedException() *ast.Field {
+	return &ast.Field{Type: &ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: "JavaException"}}}
+}
+
+// newEmbeddedException creates the runtime exception that an exception class
+// embeds, from the arguments of a call to its superclass's constructor
+//
+// Ex: `super(message)` turns into
+// `*stdjava.NewException("ValidationException", message, nil)`
+func newEmbeddedException(class *symbol.ClassScope, argumentNodes []*sitter.Node, arguments []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	return &ast.StarExpr{X: newRuntimeException(class.Class.OriginalName, argumentNodes, arguments, make([]string, len(arguments)), ctx, source)}
+}
+
+// initEmbeddedException initializes the exception that is embedded in the
+// object that a constructor of an exception class creates
+//
+// Ex: `super(message, cause)` turns into
+// `va.JavaException = *stdjava.NewException("ValidationException", message, cause)`
+func initEmbeddedException(argumentNodes []*sitter.Node, arguments []ast.Expr, ctx Ctx, source []byte) ast.Stmt {
+	return &ast.AssignStmt{
+		Lhs: []ast.Expr{&ast.SelectorExpr{X: &ast.Ident{Name: ReceiverName(ctx)}, Sel: &ast.Ident{Name: "JavaException"}}},
+		Tok: token.ASSIGN,
+		Rhs: []ast.Expr{newEmbeddedException(ctx.currentClass, argumentNodes, arguments, ctx, source)},
+	}
+}
+
+// callsSuperConstructor determines if the body of a constructor starts by
+// calling the constructor of its superclass
+func callsSuperConstructor(body *sitter.Node) bool {
+	if body.NamedChildCount() == 0 {
+		return false
+	}
+	first := body.NamedChild(0)
+	return first.Type() == "explicit_constructor_invocation" && first.ChildByFieldName("constructor").Type() == "super"
+}
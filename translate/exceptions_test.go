@@ -0,0 +1,103 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExceptions_WrappedCause(t *testing.T) {
+	src := `
+package exc.wrapped;
+public class Wrapper {
+    void rethrow(String message, IllegalStateException cause) {
+        throw new RuntimeException(message, cause);
+    }
+    void causeOnly(IllegalStateException cause) {
+        throw new IllegalArgumentException(cause);
+    }
+    void messageOnly() {
+        throw new UnsupportedOperationException("not supported");
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, `panic(stdjava.NewException("RuntimeException", message, cause))`) {
+		t.Errorf("Expected the exception to wrap its cause, got:\n%s", out)
+	}
+	if !strings.Contains(out, `panic(stdjava.NewException("IllegalArgumentException", "", cause))`) {
+		t.Errorf("Expected a single exception argument to be used as the cause, got:\n%s", out)
+	}
+	if !strings.Contains(out, `panic(stdjava.NewException("UnsupportedOperationException", "not supported", nil))`) {
+		t.Errorf("Expected a single string argument to be used as the message, got:\n%s", out)
+	}
+}
+
+func TestExceptions_ThrowableMethods(t *testing.T) {
+	src := `
+package exc.methods;
+public class Methods {
+    void inspect(Exception e) {
+        String message = e.getCause().getMessage();
+        e.printStackTrace();
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, "e.GetCause().GetMessage()") {
+		t.Errorf("Expected getCause and getMessage to be translated, got:\n%s", out)
+	}
+	if !strings.Contains(out, "e.PrintStackTrace()") {
+		t.Errorf("Expected printStackTrace to be translated, got:\n%s", out)
+	}
+}
+
+func TestExceptions_GeneratedExceptionClass(t *testing.T) {
+	src := `
+package exc.generated;
+public class ValidationException extends IllegalArgumentException {
+    private String field;
+    public ValidationException(String field, String message) {
+        super(message);
+        this.field = field;
+    }
+    public ValidationException(String field) {
+        this.field = field;
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, "type ValidationException struct {\n\tstdjava.JavaException\n") {
+		t.Errorf("Expected the exception class to embed the runtime's exception type, got:\n%s", out)
+	}
+	if !strings.Contains(out, `vn.JavaException = *stdjava.NewException("ValidationException", message, nil)`) {
+		t.Errorf("Expected the super call to initialize the embedded exception, got:\n%s", out)
+	}
+	if !strings.Contains(out, `vn.JavaException = *stdjava.NewException("ValidationException", "", nil)`) {
+		t.Errorf("Expected a constructor without a super call to initialize the embedded exception, got:\n%s", out)
+	}
+}
+
+func TestExceptions_ExceptionSubclassWithoutConstructor(t *testing.T) {
+	src := `
+package exc.subclass;
+public class Parser {
+    static class ParseException extends Exception {
+        ParseException(String message, Throwable cause) {
+            super(message, cause);
+        }
+    }
+    static class EmptyInputException extends ParseException {
+    }
+    void parse(String input) {
+        throw new EmptyInputException();
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, `.JavaException = *stdjava.NewException("ParseException", message, cause)`) {
+		t.Errorf("Expected the cause to be passed to the embedded exception, got:\n%s", out)
+	}
+	if !strings.Contains(out, `{JavaException: *stdjava.NewException("EmptyInputException", "", nil)}`) {
+		t.Errorf("Expected an exception without a constructor to be created with its embedded exception, got:\n%s", out)
+	}
+}
@@ -0,0 +1,1629 @@
+package translate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/astutil"
+	"github.com/NickyBoy89/java2go/codegen"
+	"github.com/NickyBoy89/java2go/nodeutil"
+	"github.com/NickyBoy89/java2go/symbol"
+	log "github.com/sirupsen/logrus"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// extractTypeArgsFromString extracts type arguments from a string like "List<Integer>"
+// or nested generics like "Map<String, List<Integer>>".
+// Returns ["Integer"] or ["String", "List<Integer>"] respectively, or nil if no type arguments found
+// or if the input has unbalanced angle brackets.
+func extractTypeArgsFromString(typeStr string) []string {
+	start := strings.Index(typeStr, "<")
+	end := strings.LastIndex(typeStr, ">")
+	if start == -1 || end == -1 || end <= start {
+		return nil
+	}
+	argsStr := typeStr[start+1 : end]
+
+	// Split by commas, but only at the top level (not inside nested angle brackets)
+	var result []string
+	var current strings.Builder
+	depth := 0
+
+	for _, ch := range argsStr {
+		switch ch {
+		case '<':
+			depth++
+			current.WriteRune(ch)
+		case '>':
+			depth--
+			if depth < 0 {
+				log.WithField("typeStr", typeStr).Warn("Unbalanced angle brackets in type string: too many '>'")
+				return nil
+			}
+			current.WriteRune(ch)
+		case ',':
+			if depth == 0 {
+				// Top-level comma - split here
+				trimmed := strings.TrimSpace(current.String())
+				if trimmed != "" {
+					result = append(result, trimmed)
+				}
+				current.Reset()
+			} else {
+				// Comma inside nested generics - keep it
+				current.WriteRune(ch)
+			}
+		default:
+			current.WriteRune(ch)
+		}
+	}
+
+	// Validate that all angle brackets were closed
+	if depth != 0 {
+		log.WithField("typeStr", typeStr).Warn("Unbalanced angle brackets in type string: unclosed '<'")
+		return nil
+	}
+
+	// Don't forget the last argument
+	trimmed := strings.TrimSpace(current.String())
+	if trimmed != "" {
+		result = append(result, trimmed)
+	}
+
+	return result
+}
+
+// ParseExpr parses an expression type
+func ParseExpr(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	// In the errors mode, the error of a method that returns what it throws is
+	// checked where it is called
+	if method := throwingMethod(node, ctx, source); method != nil {
+		return checkedCall(node, method, source, ctx)
+	}
+	if load := volatileLoad(node, source, ctx); load != nil {
+		return load
+	}
+	return parseExprNode(node, source, ctx)
+}
+
+// parseExprNode parses an expression, without checking the error of a method
+// that is called
+func parseExprNode(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	switch node.Type() {
+	case "ERROR":
+		log.WithFields(log.Fields{
+			"parsed":    node.Content(source),
+			"className": ctx.className,
+		}).Warn("Expression parse error")
+		return &ast.BadExpr{}
+	case "comment":
+		return &ast.BadExpr{}
+	case "update_expression":
+		if updated := volatileUpdate(node, true, source, ctx); updated != nil {
+			return updated
+		}
+
+		// This can either be a pre or post expression
+		// a pre expression has the identifier second, while the post expression
+		// has the identifier first
+
+		if updated := hoistedUpdate(node, source, ctx); updated != nil {
+			return updated
+		}
+
+		// Post-update expression, e.g. `i++`
+		if node.Child(0).IsNamed() {
+			function := "PostIncrement"
+			if node.Child(1).Type() == "--" {
+				function = "PostDecrement"
+			}
+			return runtimeCall(function, &ast.UnaryExpr{Op: token.AND, X: ParseExpr(node.Child(0), source, ctx)})
+		}
+
+		// Otherwise, pre-update expression
+		function := "PreIncrement"
+		if node.Child(0).Type() == "--" {
+			function = "PreDecrement"
+		}
+		return runtimeCall(function, &ast.UnaryExpr{Op: token.AND, X: ParseExpr(node.Child(1), source, ctx)})
+	case "class_literal":
+		// Class literals refer to the class directly, such as
+		// Object.class
+		return classLiteral(node.NamedChild(0), source, ctx)
+	case "assignment_expression":
+		return assignmentValue(node, source, ctx)
+	case "super":
+		return &ast.BadExpr{}
+	case "lambda_expression":
+		// Lambdas can either be called with a list of expressions
+		// (ex: (n1, n1) -> {}), or with a single expression
+		// (ex: n1 -> {})
+
+		var lambdaBody *ast.BlockStmt
+
+		var lambdaParameters *ast.FieldList
+
+		// The lambda's body isn't run where it is declared, and can't return the
+		// errors of the enclosing method
+		ctx.hoisted = nil
+		ctx.errorTarget = nil
+
+		bodyNode := node.ChildByFieldName("body")
+
+		paramNode := node.ChildByFieldName("parameters")
+
+		switch paramNode.Type() {
+		case "inferred_parameters", "formal_parameters":
+			lambdaParameters = ParseNode(paramNode, source, ctx).(*ast.FieldList)
+		default:
+			// If we can't identify the types of the parameters, then just set their
+			// types to any
+			lambdaParameters = &ast.FieldList{
+				List: []*ast.Field{
+					&ast.Field{
+						Names: []*ast.Ident{ParseExpr(paramNode, source, ctx).(*ast.Ident)},
+						Type:  &ast.Ident{Name: "any"},
+					},
+				},
+			}
+		}
+
+		lambda := &ast.FuncLit{
+			Type: &ast.FuncType{
+				Params: lambdaParameters,
+			},
+		}
+
+		// A lambda that implements a listener takes the types of the listener's
+		// method, and is converted to the listener's function type
+		listener := lambdaListener(node, source, ctx)
+		method := listenerMethod(listener)
+		if method != nil && len(method.Parameters) == lambdaParameters.NumFields() {
+			typeParams := append(inScopeTypeParameters(ctx), listener.TypeParameters...)
+			var index int
+			for _, field := range lambdaParameters.List {
+				field.Type = javaTypeStringToGoTypeExpr(method.Parameters[index].OriginalType, typeParams)
+				index += len(field.Names)
+			}
+			if method.OriginalType != "void" {
+				lambda.Type.Results = &ast.FieldList{List: []*ast.Field{{Type: javaTypeStringToGoTypeExpr(method.OriginalType, typeParams)}}}
+			}
+		} else {
+			method = nil
+			// A lambda that is a functional interface from Java's standard
+			// library takes the types of its function
+			params, result, ok := functionalSignature(functionalTarget(node, source, ctx), ctx)
+			if ok && len(params) == lambdaParameters.NumFields() {
+				var index int
+				for _, field := range lambdaParameters.List {
+					field.Type = params[index]
+					index += len(field.Names)
+				}
+				if result != nil {
+					lambda.Type.Results = &ast.FieldList{List: []*ast.Field{{Type: result}}}
+				}
+			} else {
+				for _, field := range lambdaParameters.List {
+					if ident, ok := field.Type.(*ast.Ident); ok && (ident.Name == "any" || ident.Name == "interface{}") {
+						for _, name := range field.Names {
+							ctx.fallbacks.Report(paramNode, anyFallbackDiagnostic, "the type of the parameter `%s` of the lambda isn't known", name.Name)
+						}
+					}
+				}
+			}
+		}
+
+		switch {
+		case bodyNode.Type() == "block":
+			lambdaBody = ParseStmt(bodyNode, source, ctx).(*ast.BlockStmt)
+		case lambda.Type.Results != nil:
+			// Lambdas can be called inline without a block expression
+			lambdaBody = &ast.BlockStmt{List: []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{ParseExpr(bodyNode, source, ctx)}}}}
+		default:
+			// An expression that nothing is returned from is run as a statement,
+			// such as `() -> count++`
+			ctx.hoisted = &hoistedStatements{names: localVariableNames(ctx), spliced: true}
+			stmt := withHoisted(parseExpressionStatement(bodyNode, source, ctx), ctx.hoisted)
+			if block, ok := stmt.(*ast.BlockStmt); ok {
+				lambdaBody = block
+			} else {
+				lambdaBody = &ast.BlockStmt{List: []ast.Stmt{stmt}}
+			}
+		}
+		lambda.Body = lambdaBody
+
+		if method != nil {
+			return &ast.CallExpr{Fun: &ast.Ident{Name: listenerFuncName(listener, ctx)}, Args: []ast.Expr{lambda}}
+		}
+
+		return lambda
+	case "method_reference":
+		// This refers to manually selecting a function from a specific class and
+		// passing it in as an argument in the `func(className::methodName)` style
+
+		// A method of a class is wrapped in a function with the signature of the
+		// functional interface that it is converted to
+		if wrapped := methodReference(node, source, ctx); wrapped != nil {
+			return wrapped
+		}
+
+		// For class constructors such as `Class::new`, you only get one node
+		if node.NamedChildCount() < 2 {
+			return &ast.SelectorExpr{
+				X:   ParseExpr(node.NamedChild(0), source, ctx),
+				Sel: &ast.Ident{Name: "new"},
+			}
+		}
+
+		return &ast.SelectorExpr{
+			X:   ParseExpr(node.NamedChild(0), source, ctx),
+			Sel: ParseExpr(node.NamedChild(1), source, ctx).(*ast.Ident),
+		}
+	case "array_initializer":
+		// A literal that initilzes an array, such as `{1, 2, 3}`
+
+		// Each of the items is expected to have the type of the array's elements,
+		// which lets a diamond constructor infer its type arguments
+		// Ex: `Box<String>[] boxes = {new Box<>()}`
+		itemCtx := ctx.Clone()
+		itemCtx.expectedType = strings.TrimSuffix(strings.TrimSpace(ctx.expectedType), "[]")
+		if arrayType, ok := ctx.lastType.(*ast.ArrayType); ok {
+			itemCtx.lastType = arrayType.Elt
+		}
+
+		items := []ast.Expr{}
+		for _, c := range nodeutil.NamedChildrenOf(node) {
+			items = append(items, ParseExpr(c, source, itemCtx))
+		}
+
+		// If there wasn't a type for the array specified, then use the one that has been defined
+		if _, ok := ctx.lastType.(*ast.ArrayType); ctx.lastType != nil && ok {
+			return &ast.CompositeLit{
+				Type: ctx.lastType.(*ast.ArrayType),
+				Elts: items,
+			}
+		}
+		return &ast.CompositeLit{
+			Elts: items,
+		}
+	case "method_invocation":
+		// Methods with a selector are called as X.Sel(Args)
+		// Otherwise, they are called as Fun(Args)
+		if node.ChildByFieldName("object") != nil {
+			// A stream is lowered into a loop before anything in it is parsed
+			if lowered := lowerStream(node, source, ctx); lowered != nil {
+				return lowered
+			}
+			if read := readClock(node, source, ctx); read != nil {
+				return read
+			}
+
+			objectNode := node.ChildByFieldName("object")
+			methodName := node.ChildByFieldName("name").Content(source)
+			methodIdent := ParseExpr(node.ChildByFieldName("name"), source, ctx).(*ast.Ident)
+			if accessor := calledAccessor(node, ctx, source); accessor != nil {
+				methodIdent = &ast.Ident{Name: accessor.Name}
+			}
+
+			// Check if this is an enum values() call
+			// Transform EnumName.values() to EnumNameValues()
+			if methodName == "values" {
+				if class := resolveClassScopeByIdentifier(ctx, source, objectNode); class != nil && class.IsEnum {
+					return &ast.CallExpr{
+						Fun:  &ast.Ident{Name: enumValuesName(class, ctx.currentFile.Package)},
+						Args: []ast.Expr{},
+					}
+				}
+			}
+
+			objectExpr := ParseExpr(objectNode, source, ctx)
+			args := ParseNode(node.ChildByFieldName("arguments"), source, ctx).([]ast.Expr)
+			boxedArguments(calledMethod(node, ctx, source), nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments")), args, ctx, source)
+
+			// Boxing a primitive goes through the runtime's cache of boxes
+			if methodName == "valueOf" && len(args) == 1 && isBoxedType(objectNode.Content(source)) &&
+				!isStringExpr(node.ChildByFieldName("arguments").NamedChild(0), ctx, source) {
+				return boxValue(objectNode.Content(source), args[0])
+			}
+
+			if called := functionalCall(node, objectExpr, args, ctx, source); called != nil {
+				return called
+			}
+			if converted := localeMethod(node, objectExpr, args, ctx, source); converted != nil {
+				return converted
+			}
+			if converted := resourceMethod(node, objectExpr, args, ctx, source); converted != nil {
+				return converted
+			}
+			if converted := charsetMethod(node, objectExpr, args, ctx, source); converted != nil {
+				return converted
+			}
+			if converted := digestMethod(node, objectExpr, args, ctx, source); converted != nil {
+				return converted
+			}
+			if converted := httpMethod(node, objectExpr, args, ctx, source); converted != nil {
+				return converted
+			}
+			if converted := streamMethod(node, objectExpr, args, ctx, source); converted != nil {
+				return converted
+			}
+			if converted := objectsMethod(node, objectExpr, args, ctx, source); converted != nil {
+				return converted
+			}
+			if converted := hashedMethod(node, objectExpr, args, ctx, source); converted != nil {
+				return converted
+			}
+			if converted := mapMethod(node, objectExpr, args, ctx, source); converted != nil {
+				return converted
+			}
+			if converted := setMethod(node, objectExpr, args, ctx, source); converted != nil {
+				return converted
+			}
+			if converted := listMethod(node, objectExpr, args, ctx, source); converted != nil {
+				return converted
+			}
+			if converted := sortedMethod(node, objectExpr, args, ctx, source); converted != nil {
+				return converted
+			}
+			if converted := stringMethod(node, objectExpr, args, ctx, source); converted != nil {
+				return converted
+			}
+			if converted := builderMethod(node, objectExpr, args, ctx, source); converted != nil {
+				return converted
+			}
+			if converted := boxedMethod(node, objectExpr, args, ctx, source); converted != nil {
+				return converted
+			}
+			if converted := floatToString(node, objectExpr, args, ctx, source); converted != nil {
+				return converted
+			}
+			if converted := stdlibMethod(node, args, ctx, source); converted != nil {
+				return converted
+			}
+
+			// Strings are compared by value in Go
+			if methodName == "equals" && len(args) == 1 && isStringExpr(objectNode, ctx, source) {
+				return stringEquals(objectExpr, args[0], node.ChildByFieldName("arguments").NamedChild(0))
+			}
+
+			// If this is a static call on a class name (e.g., Utils.<T>id(...)),
+			// rewrite it to a plain function call to match how static methods are emitted.
+			if classScope := resolveClassScopeByIdentifier(ctx, source, objectNode); classScope != nil {
+				if staticDef := findInheritedStaticMethod(ctx, classScope, methodName, len(args)); staticDef != nil {
+					fun := ast.Expr(&ast.Ident{Name: staticDef.Name})
+					if typeArgs := explicitTypeArgumentExprs(node, source, inScopeTypeParameters(ctx)); len(typeArgs) > 0 {
+						fun = applyTypeArguments(fun, typeArgs)
+					} else {
+						fun = applyTypeArguments(fun, factoryOf(staticDef).typeArguments(len(args), ctx))
+					}
+					return &ast.CallExpr{Fun: fun, Args: args}
+				}
+			} else if factory, ok := libraryFactories[objectNode.Content(source)+"."+methodName]; ok && objectNode.Type() == "identifier" {
+				fun := ast.Expr(&ast.SelectorExpr{X: objectExpr, Sel: methodIdent})
+				if typeArgs := explicitTypeArgumentExprs(node, source, inScopeTypeParameters(ctx)); len(typeArgs) > 0 {
+					fun = applyTypeArguments(fun, typeArgs)
+				} else {
+					fun = applyTypeArguments(fun, factory.typeArguments(len(args), ctx))
+				}
+				return &ast.CallExpr{Fun: fun, Args: args}
+			}
+
+			if rewritten := maybeRewriteInstanceGenericMethodInvocation(objectNode, objectExpr, methodName, args, node, ctx, source); rewritten != nil {
+				return rewritten
+			}
+
+			// Methods such as `getMessage` and `getCause` are called on the runtime's
+			// exception type
+			if exceptionMethod, known := throwableMethods[methodName]; known && isExceptionExpr(objectNode, ctx, source) {
+				methodIdent = &ast.Ident{Name: exceptionMethod}
+			}
+
+			return &ast.CallExpr{
+				Fun: &ast.SelectorExpr{
+					X:   objectExpr,
+					Sel: methodIdent,
+				},
+				Args: args,
+			}
+		}
+		fun := ParseExpr(node.ChildByFieldName("name"), source, ctx)
+		// Static methods may have been renamed to avoid collisions
+		argumentCount := int(node.ChildByFieldName("arguments").NamedChildCount())
+		if ctx.currentClass != nil && ctx.currentClass.IsEnum && argumentCount == 0 &&
+			node.ChildByFieldName("name").Content(source) == "values" {
+			return &ast.CallExpr{Fun: &ast.Ident{Name: enumValuesName(ctx.currentClass, ctx.currentFile.Package)}}
+		}
+		var factoryTypeArgs []ast.Expr
+		var initialized bool
+		if staticDef := findEnclosingStaticMethod(ctx, node.ChildByFieldName("name").Content(source), argumentCount); staticDef != nil {
+			fun = &ast.Ident{Name: staticDef.Name}
+			factoryTypeArgs = factoryOf(staticDef).typeArguments(argumentCount, ctx)
+		} else if ctx.initializedObject != "" {
+			fun = &ast.SelectorExpr{X: &ast.Ident{Name: ctx.initializedObject}, Sel: fun.(*ast.Ident)}
+			initialized = true
+		} else if accessor := calledAccessor(node, ctx, source); accessor != nil && ctx.localScope != nil && !ctx.localScope.IsStatic {
+			// A record's own accessors are called on its object
+			fun = &ast.SelectorExpr{X: &ast.Ident{Name: ReceiverName(ctx)}, Sel: &ast.Ident{Name: accessor.Name}}
+		}
+		if typeArgs := explicitTypeArgumentExprs(node, source, inScopeTypeParameters(ctx)); len(typeArgs) > 0 {
+			fun = applyTypeArguments(fun, typeArgs)
+		} else {
+			fun = applyTypeArguments(fun, factoryTypeArgs)
+		}
+		args := ParseNode(node.ChildByFieldName("arguments"), source, ctx).([]ast.Expr)
+		if initialized {
+			if converted := initializedCollectionCall(node, args, ctx, source); converted != nil {
+				return converted
+			}
+		}
+		boxedArguments(calledMethod(node, ctx, source), nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments")), args, ctx, source)
+		return &ast.CallExpr{Fun: fun, Args: args}
+	case "object_creation_expression":
+		// This is called when anything is created with a constructor
+
+		objectType := node.ChildByFieldName("type")
+
+		// Get all the arguments, and look up their types
+		objectArguments := node.ChildByFieldName("arguments")
+		arguments := make([]ast.Expr, objectArguments.NamedChildCount())
+		argumentTypes := make([]string, objectArguments.NamedChildCount())
+		for ind, argument := range nodeutil.NamedChildrenOf(objectArguments) {
+			arguments[ind] = ParseExpr(argument, source, ctx)
+
+			// Look up each argument and find its type
+			if argument.Type() != "identifier" {
+				argumentTypes[ind] = symbol.TypeOfLiteral(argument, source)
+			} else {
+				if localDef := ctx.localScope.FindVariable(argument.Content(source), argument.StartByte()); localDef != nil {
+					argumentTypes[ind] = localDef.OriginalType
+					// Otherwise, a variable may exist as a global variable
+				} else if def := ctx.currentFile.FindField().ByOriginalName(argument.Content(source)); len(def) > 0 {
+					argumentTypes[ind] = def[0].OriginalType
+				}
+			}
+		}
+
+		// Extract base class name and type arguments
+		var className string
+		var typeArgs []string
+		isDiamond := false
+		if objectType.Type() == "generic_type" {
+			className = objectType.NamedChild(0).Content(source)
+			typeArgs = astutil.ExtractTypeArguments(objectType, source)
+			// Diamond operator: generic_type with no type arguments and explicit "<>" in source
+			if len(typeArgs) == 0 {
+				content := objectType.Content(source)
+				// Look for "<>" after the class name (allowing for whitespace)
+				afterClass := strings.TrimSpace(content[len(className):])
+				isDiamond = strings.HasPrefix(afterClass, "<>")
+			}
+		} else {
+			className = objectType.Content(source)
+		}
+
+		// Find the respective constructor (if we have symbol info for that class).
+		var constructor *symbol.Definition
+		var classScope *symbol.ClassScope
+		targetScope := ctx.currentClass
+		if ctx.currentFile != nil && ctx.currentFile.BaseClass != nil {
+			if classScope = resolveClassScopeByName(ctx, className); classScope != nil {
+				targetScope = classScope
+			}
+		}
+		constructor = findMatchingConstructor(targetScope, className, argumentTypes)
+
+		if constructor == nil && classScope == nil && className == "String" {
+			if created := newString(node, nodeutil.NamedChildrenOf(objectArguments), arguments, ctx, source); created != nil {
+				return created
+			}
+		}
+
+		// Exceptions from the standard library are created with the runtime's
+		// exception type
+		if constructor == nil && classScope == nil && isExceptionClass(className) {
+			return newRuntimeException(className, nodeutil.NamedChildrenOf(objectArguments), arguments, argumentTypes, ctx, source)
+		}
+		if constructor == nil && classScope == nil {
+			if created := newLocaleObject(node, className, arguments, ctx, source); created != nil {
+				return created
+			}
+			if created := newBuilder(node, className, arguments, ctx, source); created != nil {
+				return created
+			}
+			if className == "Properties" && len(arguments) == 0 {
+				return runtimeCall("NewProperties")
+			}
+			if checksum := newChecksum(className); checksum != nil && len(arguments) == 0 {
+				return checksum
+			}
+			if url := newURL(className, nodeutil.NamedChildrenOf(objectArguments), arguments, ctx, source); url != nil {
+				return url
+			}
+			if stream := newStreamObject(className, arguments); stream != nil {
+				return stream
+			}
+		}
+
+		// An exception class without a constructor of its own is created with
+		// the exception that it embeds
+		if constructor == nil && classScope != nil && len(arguments) == 0 && isExceptionType(classScope, ctx) {
+			return &ast.UnaryExpr{Op: token.AND, X: &ast.CompositeLit{
+				Type: &ast.Ident{Name: classScope.Class.Name},
+				Elts: []ast.Expr{&ast.KeyValueExpr{
+					Key:   &ast.Ident{Name: "JavaException"},
+					Value: newEmbeddedException(classScope, nil, nil, ctx, source),
+				}},
+			}}
+		}
+
+		// Helper function to add type arguments to a function expression
+		addTypeArgs := func(funExpr ast.Expr, args []string) ast.Expr {
+			if len(args) == 0 {
+				return funExpr
+			}
+			scopeTypeParams := inScopeTypeParameters(ctx)
+			typeArgExprs := make([]ast.Expr, 0, len(args))
+			for _, ta := range args {
+				typeArgExprs = append(typeArgExprs, javaTypeStringToGoTypeExpr(ta, scopeTypeParams))
+			}
+			return applyTypeArguments(funExpr, typeArgExprs)
+		}
+
+		// Determine effective type arguments:
+		// 1. If explicit type arguments provided, use them
+		// 2. If diamond operator, try to infer from expectedType
+		// 3. A nested class also takes the type parameters of the classes that
+		//    it is nested in, before its own
+		effectiveTypeArgs := typeArgs
+		if len(effectiveTypeArgs) == 0 {
+			// For diamond operator, try to infer from expectedType
+			if isDiamond && ctx.expectedType != "" {
+				effectiveTypeArgs = extractTypeArgsFromString(ctx.expectedType)
+			}
+		}
+
+		// This handles cases like `new Node(element)` or `new Inner<String>()`
+		// inside a generic class
+		if classScope != nil && len(classScope.InheritedTypeParameters) > 0 &&
+			len(effectiveTypeArgs) == len(classScope.TypeParameters)-len(classScope.InheritedTypeParameters) {
+			effectiveTypeArgs = append(append([]string{}, classScope.InheritedTypeParameters...), effectiveTypeArgs...)
+		}
+
+		if constructor == nil && classScope == nil {
+			if sorted := newSortedCollection(node, className, effectiveTypeArgs, arguments, ctx, source); sorted != nil {
+				return sorted
+			}
+			if created := newHashedCollection(node, className, effectiveTypeArgs, arguments, ctx, source); created != nil {
+				return created
+			}
+			created := newMap(node, className, effectiveTypeArgs, arguments, ctx, source)
+			if created == nil {
+				created = newSet(node, className, effectiveTypeArgs, arguments, ctx, source)
+			}
+			if created == nil {
+				created = newList(node, className, effectiveTypeArgs, arguments, ctx, source)
+			}
+			if created != nil {
+				// A collection with double brace initialization is initialized
+				// the same way that it is used everywhere else
+				if body := anonymousClassBody(node); body != nil {
+					javaType := className + "<" + strings.Join(effectiveTypeArgs, ", ") + ">"
+					return doubleBraceInitialization(body, javaType, javaTypeStringToGoTypeExpr(javaType, inScopeTypeParameters(ctx)), created, source, ctx)
+				}
+				return created
+			}
+		}
+
+		// An object of an inner class is given the object that encloses it, and
+		// an inner class without constructors is created with only that
+		if classScope != nil && classScope.Outer != "" {
+			outer := enclosingObject(node, classScope, source, ctx)
+			if constructor == nil && !hasConstructor(classScope) && len(arguments) == 0 {
+				return &ast.UnaryExpr{Op: token.AND, X: &ast.CompositeLit{
+					Type: addTypeArgs(&ast.Ident{Name: classScope.Class.Name}, effectiveTypeArgs),
+					Elts: []ast.Expr{&ast.KeyValueExpr{Key: &ast.Ident{Name: classScope.Outer}, Value: outer}},
+				}}
+			}
+			arguments = append([]ast.Expr{outer}, arguments...)
+		}
+
+		var created ast.Expr
+		if constructor != nil {
+			boxedArguments(constructor, nodeutil.NamedChildrenOf(objectArguments), arguments[len(arguments)-len(argumentTypes):], ctx, source)
+			funExpr := addTypeArgs(&ast.Ident{Name: constructor.Name}, effectiveTypeArgs)
+			created = &ast.CallExpr{
+				Fun:  funExpr,
+				Args: arguments,
+			}
+		} else {
+			// It is also possible that a constructor could be unresolved, so we handle
+			// this by calling the type of the type + "Construct" at the beginning
+			funExpr := addTypeArgs(&ast.Ident{Name: "Construct" + className}, effectiveTypeArgs)
+			created = &ast.CallExpr{
+				Fun:  funExpr,
+				Args: arguments,
+			}
+		}
+
+		if body := anonymousClassBody(node); body != nil {
+			baseName := className
+			if classScope != nil {
+				baseName = classScope.Class.Name
+			}
+			baseType := addTypeArgs(&ast.Ident{Name: baseName}, effectiveTypeArgs)
+
+			// An anonymous class that only initializes the object is created
+			// in place, such as with double brace initialization
+			if isInitializerOnly(body) {
+				return doubleBraceInitialization(body, className, &ast.StarExpr{X: baseType}, created, source, ctx)
+			}
+
+			// An anonymous class that extends a class embeds an instance of it
+			if classScope != nil && !classScope.IsInterface && !classScope.IsEnum && ctx.synthesized != nil {
+				return anonymousSubclass(body, classScope, baseType, created, source, ctx)
+			}
+
+			// An anonymous class that implements an interface with a single
+			// method is a function, the same as a lambda, unless the interface
+			// is declared in Java, and isn't a listener. Any other class that
+			// implements an interface has a type of its own. A class that isn't
+			// known is an interface, unless it is created with arguments
+			implemented := classScope != nil && classScope.IsInterface || classScope == nil && len(arguments) == 0
+			if implemented && (classScope == nil || listenerMethod(classScope) != nil) {
+				if lambda := anonymousFunc(body, classScope, source, ctx); lambda != nil {
+					return lambda
+				}
+			}
+			if implemented && ctx.synthesized != nil {
+				if instance := anonymousImplementation(body, className, source, ctx); instance != nil {
+					if classScope == nil {
+						return functionalInstance(body, className, instance, source, ctx)
+					}
+					return instance
+				}
+			}
+		}
+
+		return created
+	case "array_creation_expression":
+		dimensions := []ast.Expr{}
+		arrayType := astutil.ParseTypeWithTypeParams(node.ChildByFieldName("type"), source, inScopeTypeParameters(ctx))
+		var initializer ast.Expr
+		// The dimensions after the ones with lengths
+		// Ex: `new int[2][][]` has two
+		var unallocated int
+
+		for _, child := range nodeutil.NamedChildrenOf(node) {
+			if child.Type() == "dimensions_expr" {
+				dimensions = append(dimensions, ParseExpr(child, source, ctx))
+			} else if child.Type() == "dimensions" {
+				unallocated = strings.Count(child.Content(source), "[")
+			} else if child.Type() == "array_initializer" {
+				initCtx := ctx.Clone()
+				initCtx.lastType = codegen.GenArrayType(arrayType, unallocated)
+				// The type that the array is assigned to has the type arguments of
+				// its elements, which a generic array creation can't have
+				if initCtx.expectedType == "" {
+					initCtx.expectedType = node.ChildByFieldName("type").Content(source) + node.ChildByFieldName("dimensions").Content(source)
+				}
+				initializer = ParseExpr(child, source, initCtx)
+			}
+		}
+
+		if initializer != nil {
+			return initializer
+		}
+
+		if len(dimensions) == 0 {
+			panic("Array had zero dimensions")
+		}
+
+		return codegen.GenMultiDimArray(arrayType, len(dimensions)+unallocated, dimensions)
+	case "instanceof_expression":
+		return instanceOf(node, source, ctx)
+	case "dimensions_expr":
+		return ParseExpr(node.NamedChild(0), source, ctx)
+	case "binary_expression":
+		if elapsed := elapsedTime(node, source, ctx); elapsed != nil {
+			return elapsed
+		}
+		if converted := integralArithmetic(node, source, ctx); converted != nil {
+			return converted
+		}
+		if converted := floatingArithmetic(node.Child(1).Content(source), node.Child(0), node.Child(2), source, ctx); converted != nil {
+			return converted
+		}
+		// Numbers and objects that are concatenated onto a string are converted
+		// the same way as Java does
+		if node.Child(1).Content(source) == "+" && isStringExpr(node, ctx, source) {
+			return &ast.BinaryExpr{
+				X:  concatenatedOperand(node.Child(0), ParseExpr(node.Child(0), source, ctx), ctx, source),
+				Op: token.ADD,
+				Y:  concatenatedOperand(node.Child(2), ParseExpr(node.Child(2), source, ctx), ctx, source),
+			}
+		}
+		if operator := node.Child(1).Content(source); operator == "==" || operator == "!=" {
+			checkStringComparison(node, ctx, source)
+			if isBoxedExpr(node.Child(0), ctx, source) && isBoxedExpr(node.Child(2), ctx, source) {
+				return boxedComparison(node, codegen.StrToToken(operator), ParseExpr(node.Child(0), source, ctx), ParseExpr(node.Child(2), source, ctx), ctx, source)
+			}
+		}
+		// A box is unboxed by any other operator, and by a comparison with a
+		// primitive
+		left, right := ParseExpr(node.Child(0), source, ctx), ParseExpr(node.Child(2), source, ctx)
+		if node.Child(0).Type() != "null_literal" && node.Child(2).Type() != "null_literal" {
+			left, right = unboxedValue(node.Child(0), left, ctx, source), unboxedValue(node.Child(2), right, ctx, source)
+		}
+		return &ast.BinaryExpr{
+			X:  left,
+			Op: codegen.StrToToken(node.Child(1).Content(source)),
+			Y:  right,
+		}
+	case "unary_expression":
+		return &ast.UnaryExpr{
+			Op: codegen.StrToToken(node.Child(0).Content(source)),
+			X:  unboxedValue(node.Child(1), ParseExpr(node.Child(1), source, ctx), ctx, source),
+		}
+	case "parenthesized_expression":
+		inner := ParseExpr(node.NamedChild(0), source, ctx)
+		// An assignment that was hoisted out of the parentheses leaves only its
+		// variable, such as the `line` of `(line = reader.readLine()) != null`
+		if node.NamedChild(0).Type() == "assignment_expression" {
+			if _, isIdent := inner.(*ast.Ident); isIdent {
+				return inner
+			}
+		}
+		return &ast.ParenExpr{X: inner}
+	case "switch_expression":
+		return switchExpression(node, source, ctx)
+	case "ternary_expression":
+		return ternary(node, source, ctx)
+	case "cast_expression":
+		// Ex: `(HttpURLConnection) url.openConnection()`, which already has the
+		// runtime's type
+		if httpClassOf(node.NamedChild(0).Content(source)) == "HttpURLConnection" {
+			return ParseExpr(node.NamedChild(1), source, ctx)
+		}
+		// A cast between primitives converts the value
+		if castType := node.ChildByFieldName("type"); isPrimitiveCast(castType, node.ChildByFieldName("value"), ctx, source) {
+			value := ParseExpr(node.ChildByFieldName("value"), source, ctx)
+			if paren, ok := value.(*ast.ParenExpr); ok {
+				value = paren.X
+			}
+			return &ast.CallExpr{Fun: astutil.ParseType(castType, source), Args: []ast.Expr{value}}
+		}
+		return &ast.TypeAssertExpr{
+			X:    ParseExpr(node.NamedChild(1), source, ctx),
+			Type: astutil.ParseTypeWithTypeParams(node.NamedChild(0), source, inScopeTypeParameters(ctx)),
+		}
+	case "field_access":
+		// X.Sel
+		obj := node.ChildByFieldName("object")
+
+		// Ex: `Outer.this`
+		if node.ChildByFieldName("field").Type() == "this" {
+			return qualifiedThis(obj.Content(source), ctx)
+		}
+
+		// Ex: `Locale.US`
+		if constant := localeConstant(node, source); constant != nil {
+			return constant
+		}
+
+		if obj.Type() == "this" {
+			def := ctx.currentClass.FindField().ByOriginalName(node.ChildByFieldName("field").Content(source))
+			// The field may be inherited from a superclass
+			visited := map[*symbol.ClassScope]bool{}
+			for class := ctx.currentClass; len(def) == 0 && class != nil && !visited[class] && ctx.currentFile != nil; {
+				visited[class] = true
+				if class, _ = superclassOf(class, ctx.currentFile); class != nil {
+					def = class.FindField().ByOriginalName(node.ChildByFieldName("field").Content(source))
+				}
+			}
+			if len(def) == 0 {
+				// TODO: This field could not be found in the current class, because it exists in the superclass
+				// definition for the class
+				def = []*symbol.Definition{&symbol.Definition{
+					Name: node.ChildByFieldName("field").Content(source),
+				}}
+			}
+
+			return &ast.SelectorExpr{
+				X:   ParseExpr(node.ChildByFieldName("object"), source, ctx),
+				Sel: &ast.Ident{Name: def[0].Name},
+			}
+		}
+		// Enum constants are package-level constants
+		if class := resolveClassScopeByIdentifier(ctx, source, obj); class != nil && class.IsEnum {
+			return &ast.Ident{Name: enumConstantName(class, node.ChildByFieldName("field").Content(source))}
+		}
+		// Fields may have been renamed, so the field is looked up in the class of
+		// the object that it is accessed on
+		if field := fieldOfExpr(obj, node.ChildByFieldName("field").Content(source), ctx, source); field != nil && !field.IsStatic {
+			return &ast.SelectorExpr{
+				X:   ParseExpr(obj, source, ctx),
+				Sel: &ast.Ident{Name: field.Name},
+			}
+		}
+		return &ast.SelectorExpr{
+			X:   ParseExpr(obj, source, ctx),
+			Sel: ParseExpr(node.ChildByFieldName("field"), source, ctx).(*ast.Ident),
+		}
+	case "array_access":
+		array := ParseExpr(node.NamedChild(0), source, ctx)
+		index := ParseExpr(node.NamedChild(1), source, ctx)
+		// Indexes are checked by the runtime in strict mode, which throws the same
+		// exception as Java when they are out of bounds
+		if StrictMode {
+			if isAssignedTo(node) {
+				return &ast.StarExpr{X: runtimeCall("ArrayElement", array, index)}
+			}
+			return runtimeCall("ArrayGet", array, index)
+		}
+		return &ast.IndexExpr{X: array, Index: index}
+	case "scoped_identifier":
+		return ParseExpr(node.NamedChild(0), source, ctx)
+	case "this":
+		if instances := enclosingInstances(ctx); len(instances) > 0 {
+			return instances[0].object
+		}
+		return &ast.Ident{Name: ReceiverName(ctx)}
+	case "identifier":
+		// A variable that is changed later on in the expression, before the
+		// expression is evaluated, is copied before then
+		if ctx.hoisted != nil && (isChangedLater(node, source) || isReadBeforeCheckedCall(node, source, ctx)) {
+			readCtx := ctx
+			readCtx.hoisted = nil
+			return ctx.hoisted.snapshot(node.Content(source), parseExprNode(node, source, readCtx))
+		}
+		if member, ok := enumMemberReference(node, ctx, source); ok {
+			return &ast.Ident{Name: member}
+		}
+		// Variables may have been renamed, if their names aren't valid in Go
+		if ctx.localScope != nil && isVariableReference(node) {
+			if variable := ctx.localScope.FindVariable(node.Content(source), node.StartByte()); variable != nil {
+				return &ast.Ident{Name: variable.Name}
+			}
+		}
+		// The components of a record are fields of its object
+		if component := recordComponent(node, ctx, source); component != nil {
+			return componentOf(ReceiverName(ctx), component)
+		}
+		// Variables from outside of an anonymous class are fields of its object
+		if len(ctx.instances) > 0 && isVariableReference(node) {
+			if variable, ok := ctx.instances[0].captured[node.Content(source)]; ok {
+				return &ast.SelectorExpr{X: ctx.instances[0].object, Sel: &ast.Ident{Name: variable.field}}
+			}
+		}
+		return &ast.Ident{Name: node.Content(source)}
+	case "type_identifier": // Any reference type
+		switch node.Content(source) {
+		// Special case for strings, because in Go, these are primitive types
+		case "String":
+			return &ast.Ident{Name: "string"}
+		}
+
+		if ctx.currentFile != nil {
+			// Look for the class locally first
+			if localClass := ctx.currentFile.FindClass(node.Content(source)); localClass != nil {
+				return &ast.StarExpr{
+					X: &ast.Ident{Name: localClass.Name},
+				}
+			}
+		}
+
+		return &ast.StarExpr{
+			X: &ast.Ident{Name: node.Content(source)},
+		}
+	case "null_literal":
+		return &ast.Ident{Name: "nil"}
+	case "decimal_integer_literal", "hex_integer_literal", "octal_integer_literal", "binary_integer_literal":
+		return integerLiteral(node.Content(source))
+	case "decimal_floating_point_literal":
+		// This is something like 1.3D or 1.3F
+		literal := node.Content(source)
+		switch literal[len(literal)-1] {
+		case 'D':
+			return &ast.CallExpr{Fun: &ast.Ident{Name: "float64"}, Args: []ast.Expr{&ast.BasicLit{Kind: token.FLOAT, Value: literal[:len(literal)-1]}}}
+		case 'F':
+			return &ast.CallExpr{Fun: &ast.Ident{Name: "float32"}, Args: []ast.Expr{&ast.BasicLit{Kind: token.FLOAT, Value: literal[:len(literal)-1]}}}
+		}
+		return &ast.Ident{Name: literal}
+	case "string_literal":
+		convert := stringLiteral
+		if strings.HasPrefix(node.Content(source), `"""`) {
+			convert = textBlockLiteral
+		}
+		literal, err := convert(node.Content(source))
+		if err != nil {
+			log.WithField("error", err).Warn("Error converting string literal")
+			return &ast.Ident{Name: node.Content(source)}
+		}
+		return literal
+	case "character_literal":
+		literal, err := charLiteral(node.Content(source))
+		if err != nil {
+			log.WithField("error", err).Warn("Error converting character literal")
+			return &ast.Ident{Name: node.Content(source)}
+		}
+		return literal
+	case "true", "false":
+		return &ast.Ident{Name: node.Content(source)}
+	}
+	panic("Unhandled expression: " + node.Type())
+}
+
+func findClassScopeByName(scope *symbol.ClassScope, name string) *symbol.ClassScope {
+	if scope == nil {
+		return nil
+	}
+	if scope.Class.OriginalName == name {
+		return scope
+	}
+	for _, sub := range scope.Subclasses {
+		if found := findClassScopeByName(sub, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// isVariableReference determines if an identifier could refer to a variable,
+// instead of being the name of a method or a field
+func isVariableReference(node *sitter.Node) bool {
+	parent := node.Parent()
+	if parent == nil {
+		return true
+	}
+	switch parent.Type() {
+	case "method_invocation":
+		return !parent.ChildByFieldName("name").Equal(node)
+	case "field_access":
+		return !parent.ChildByFieldName("field").Equal(node)
+	}
+	return true
+}
+
+// isAssignedTo determines if an expression is assigned to, or updated in place
+func isAssignedTo(node *sitter.Node) bool {
+	switch parent := node.Parent(); parent.Type() {
+	case "assignment_expression":
+		return parent.ChildByFieldName("left").Equal(node)
+	case "update_expression":
+		return true
+	}
+	return false
+}
+
+// runtimeCall calls one of the runtime's functions
+func runtimeCall(function string, args ...ast.Expr) ast.Expr {
+	return &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: function}},
+		Args: args,
+	}
+}
+
+// classLiteral translates a class literal into the runtime type of the values
+// of that class, which is what a `Class` object is represented as
+//
+// Ex: `Foo.class` becomes `reflect.TypeOf((*Foo)(nil))`, and `int.class`
+// becomes `reflect.TypeOf((*int32)(nil)).Elem()`
+func classLiteral(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	// `void` has no values, so it has no type either
+	if node.Type() == "void_type" {
+		return &ast.CallExpr{Fun: astutil.LibraryType("Class"), Args: []ast.Expr{&ast.Ident{Name: "nil"}}}
+	}
+
+	// A nil pointer to a type is a value of that type, even when the type can't
+	// have a value of its own, such as an interface
+	goType := instanceofType(node, source, ctx)
+	pointer, isPointer := goType.(*ast.StarExpr)
+	if !isPointer {
+		pointer = &ast.StarExpr{X: goType}
+	}
+	typeOf := ast.Expr(&ast.CallExpr{
+		Fun: &ast.SelectorExpr{X: &ast.Ident{Name: "reflect"}, Sel: &ast.Ident{Name: "TypeOf"}},
+		Args: []ast.Expr{&ast.CallExpr{
+			Fun:  &ast.ParenExpr{X: pointer},
+			Args: []ast.Expr{&ast.Ident{Name: "nil"}},
+		}},
+	})
+	if isPointer {
+		return typeOf
+	}
+	return &ast.CallExpr{Fun: &ast.SelectorExpr{X: typeOf, Sel: &ast.Ident{Name: "Elem"}}}
+}
+
+func resolveClassScopeByIdentifier(ctx Ctx, source []byte, objectNode *sitter.Node) *symbol.ClassScope {
+	if objectNode == nil || objectNode.Type() != "identifier" {
+		return nil
+	}
+	return resolveClassScopeByName(ctx, objectNode.Content(source))
+}
+
+// resolveClassScopeByName finds a class by its name in the Java source, in
+// either the current file, or another file of the same package
+func resolveClassScopeByName(ctx Ctx, name string) *symbol.ClassScope {
+	if ctx.currentFile == nil || ctx.currentFile.BaseClass == nil {
+		return nil
+	}
+	if class := findClassScopeByName(ctx.currentFile.BaseClass, name); class != nil {
+		return class
+	}
+	// The class may be in another file of the same package
+	if packageScope := symbol.GlobalScope.FindPackage(ctx.currentFile.Package); packageScope != nil {
+		return findPackageClass(packageScope, name)
+	}
+	return nil
+}
+
+// findEnclosingStaticMethod finds a static method that is called without
+// naming its class, which can be in either the current class, or the
+// outermost class of the file
+func findEnclosingStaticMethod(ctx Ctx, methodName string, argCount int) *symbol.Definition {
+	if def := findInheritedStaticMethod(ctx, ctx.currentClass, methodName, argCount); def != nil {
+		return def
+	}
+	if ctx.currentFile != nil {
+		return findInheritedStaticMethod(ctx, ctx.currentFile.BaseClass, methodName, argCount)
+	}
+	return nil
+}
+
+// calledMethods returns the definitions of the methods of the package that a
+// method invocation could call, which are the overloads of the method with the
+// same number of parameters, or nothing if the method isn't known
+func calledMethods(node *sitter.Node, ctx Ctx, source []byte) []*symbol.Definition {
+	if node.Type() != "method_invocation" || ctx.currentClass == nil {
+		return nil
+	}
+	name := node.ChildByFieldName("name").Content(source)
+	argumentCount := int(node.ChildByFieldName("arguments").NamedChildCount())
+
+	class := ctx.currentClass
+	if object := node.ChildByFieldName("object"); object != nil {
+		if static := resolveClassScopeByIdentifier(ctx, source, object); static != nil {
+			if def := findInheritedStaticMethod(ctx, static, name, argumentCount); def != nil {
+				return []*symbol.Definition{def}
+			}
+			return nil
+		}
+		target := resolveInvocationTarget(object, ctx, source)
+		if target == nil {
+			return nil
+		}
+		class = target.classScope
+	}
+
+	// The method may be inherited from a superclass
+	var methods []*symbol.Definition
+	for visited := map[*symbol.ClassScope]bool{}; class != nil && !visited[class]; class = resolveClassScopeByName(ctx, class.Superclass) {
+		visited[class] = true
+		methods = append(methods, class.FindMethod().By(func(d *symbol.Definition) bool {
+			return d.OriginalName == name && len(d.Parameters) == argumentCount
+		})...)
+		if class.Superclass == "" {
+			break
+		}
+	}
+	return methods
+}
+
+// calledMethod returns the definition of the method that a method invocation
+// calls, or nil if it isn't known, or it is overloaded by another method with
+// the same number of parameters
+func calledMethod(node *sitter.Node, ctx Ctx, source []byte) *symbol.Definition {
+	if methods := calledMethods(node, ctx, source); len(methods) == 1 {
+		return methods[0]
+	}
+	return nil
+}
+
+// findInheritedStaticMethod finds a static method of a class, which may be
+// inherited from one of the classes that it extends. A static method hides
+// the ones with the same signature in the classes above it, so the first one
+// that is found from the class upwards is the one that is called
+//
+// Ex: `Sub.create()` calls `Base.create()` if `Sub` doesn't declare its own
+func findInheritedStaticMethod(ctx Ctx, class *symbol.ClassScope, methodName string, argCount int) *symbol.Definition {
+	for visited := map[*symbol.ClassScope]bool{}; class != nil && !visited[class]; class = resolveClassScopeByName(ctx, class.Superclass) {
+		visited[class] = true
+		if def := findStaticMethodByNameAndArgCount(class, methodName, argCount); def != nil {
+			return def
+		}
+		if class.Superclass == "" {
+			break
+		}
+	}
+	return nil
+}
+
+func typeParamNameSet(typeParams []string) map[string]struct{} {
+	if len(typeParams) == 0 {
+		return nil
+	}
+	m := make(map[string]struct{}, len(typeParams))
+	for _, tp := range typeParams {
+		m[tp] = struct{}{}
+	}
+	return m
+}
+
+func findMatchingConstructor(scope *symbol.ClassScope, className string, argumentTypes []string) *symbol.Definition {
+	if scope == nil {
+		return nil
+	}
+
+	for _, def := range scope.Methods {
+		if !def.Constructor {
+			continue
+		}
+		if def.OriginalName != className {
+			continue
+		}
+		if len(def.Parameters) != len(argumentTypes) {
+			continue
+		}
+
+		// Allow type parameter positions (class or constructor type params) to match
+		// any argument type, since the constructor can be instantiated accordingly.
+		acceptedTypeParams := append([]string{}, scope.TypeParameters...)
+		acceptedTypeParams = append(acceptedTypeParams, def.TypeParameters...)
+		tpSet := typeParamNameSet(acceptedTypeParams)
+
+		matches := true
+		for i, param := range def.Parameters {
+			argType := argumentTypes[i]
+			if argType == "" {
+				continue
+			}
+			if param.OriginalType == argType {
+				continue
+			}
+			if tpSet != nil {
+				if _, ok := tpSet[param.OriginalType]; ok {
+					continue
+				}
+			}
+			matches = false
+			break
+		}
+		if matches {
+			return def
+		}
+	}
+
+	return nil
+}
+
+func findStaticMethodByNameAndArgCount(scope *symbol.ClassScope, methodName string, argCount int) *symbol.Definition {
+	if scope == nil {
+		return nil
+	}
+	for _, def := range scope.Methods {
+		if !def.IsStatic {
+			continue
+		}
+		if def.OriginalName != methodName {
+			continue
+		}
+		if len(def.Parameters) != argCount {
+			continue
+		}
+		return def
+	}
+	return nil
+}
+
+func parseJavaTypeString(typeStr string) (string, []string) {
+	typeStr = strings.TrimSpace(typeStr)
+	if typeStr == "" {
+		return "", nil
+	}
+	base := typeStr
+	if idx := strings.Index(typeStr, "<"); idx >= 0 {
+		base = strings.TrimSpace(typeStr[:idx])
+	}
+	return base, extractTypeArgsFromString(typeStr)
+}
+
+func stripJavaQualifier(typeName string) string {
+	typeName = strings.TrimSpace(typeName)
+	if typeName == "" {
+		return ""
+	}
+	// Tree-sitter (and symbol.OriginalType) can include package qualifiers like
+	// "java.util.List<String>". The generator doesn't model Java packages as Go
+	// packages, so drop the qualifier and keep the leaf type name.
+	if idx := strings.LastIndex(typeName, "."); idx >= 0 {
+		return typeName[idx+1:]
+	}
+	return typeName
+}
+
+func inScopeTypeParameters(ctx Ctx) []string {
+	var params []string
+	if ctx.currentClass != nil {
+		params = append(params, ctx.currentClass.TypeParameters...)
+	}
+	if ctx.localScope != nil {
+		params = append(params, ctx.localScope.TypeParameters...)
+	}
+	return params
+}
+
+// javaTypeStringToGoTypeExpr converts a Java type string (as it appears in
+// symbol.OriginalType) into a Go AST expression suitable for use as a type
+// argument in an IndexExpr/IndexListExpr. It mirrors astutil.ParseTypeWithTypeParams
+// behavior for pointer-wrapping reference types, but operates on strings to support
+// type inference paths.
+func javaTypeStringToGoTypeExpr(typeStr string, typeParams []string) ast.Expr {
+	typeStr = strings.TrimSpace(typeStr)
+	if typeStr == "" {
+		return &ast.Ident{Name: "any"}
+	}
+
+	// Arrays like Foo[][].
+	arrayDims := 0
+	for strings.HasSuffix(typeStr, "[]") {
+		arrayDims++
+		typeStr = strings.TrimSpace(typeStr[:len(typeStr)-2])
+	}
+
+	// Wildcards like ?, ? extends Foo, ? super Foo.
+	if strings.HasPrefix(typeStr, "?") {
+		rest := strings.TrimSpace(strings.TrimPrefix(typeStr, "?"))
+		if rest == "" {
+			return &ast.Ident{Name: "any"}
+		}
+		if strings.HasPrefix(rest, "extends") {
+			bound := strings.TrimSpace(strings.TrimPrefix(rest, "extends"))
+			if bound == "" {
+				return &ast.Ident{Name: "any"}
+			}
+			return javaTypeStringToGoTypeExpr(bound, typeParams)
+		}
+		// ? super ... is hard to model faithfully in Go; fall back to any.
+		return &ast.Ident{Name: "any"}
+	}
+
+	// Normalize qualifiers.
+	qualified, typeArgs := parseJavaTypeString(typeStr)
+	base := stripJavaQualifier(qualified)
+
+	isTypeParam := func(name string) bool {
+		for _, tp := range typeParams {
+			if tp == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	primitive := func(name string) (ast.Expr, bool) {
+		switch name {
+		case "String":
+			return &ast.Ident{Name: "string"}, true
+		case "boolean":
+			return &ast.Ident{Name: "bool"}, true
+		case "int":
+			return &ast.Ident{Name: "int32"}, true
+		case "short":
+			return &ast.Ident{Name: "int16"}, true
+		case "long":
+			return &ast.Ident{Name: "int64"}, true
+		case "char":
+			return &ast.Ident{Name: "rune"}, true
+		case "byte":
+			return &ast.Ident{Name: "byte"}, true
+		case "float":
+			return &ast.Ident{Name: "float32"}, true
+		case "double":
+			return &ast.Ident{Name: "float64"}, true
+		}
+		return nil, false
+	}
+
+	var expr ast.Expr
+	if prim, ok := primitive(base); ok {
+		expr = prim
+	} else if library := astutil.LibraryType(base); library != nil {
+		expr = library
+	} else if isTypeParam(base) {
+		expr = &ast.Ident{Name: base}
+	} else {
+		// Reference type (including parameterized reference types) is represented as a pointer.
+		baseIdent := &ast.Ident{Name: base}
+		if len(typeArgs) > 0 {
+			argExprs := make([]ast.Expr, 0, len(typeArgs))
+			for _, arg := range typeArgs {
+				argExprs = append(argExprs, javaTypeStringToGoTypeExpr(arg, typeParams))
+			}
+			libraryName := base
+			if strings.HasSuffix(qualified, "Map.Entry") {
+				libraryName = "Map.Entry"
+			}
+			if expr = astutil.LibraryGenericType(libraryName, typeArgs, argExprs); expr == nil {
+				expr = &ast.StarExpr{X: applyTypeArguments(baseIdent, argExprs)}
+			}
+		} else {
+			expr = &ast.StarExpr{X: baseIdent}
+		}
+	}
+
+	for i := 0; i < arrayDims; i++ {
+		expr = &ast.ArrayType{Elt: expr}
+	}
+	return expr
+}
+
+func inferIdentifierJavaType(name string, position uint32, ctx Ctx) (string, bool) {
+	if ctx.localScope != nil {
+		if param := ctx.localScope.ParameterByName(name); param != nil && param.OriginalType != "" {
+			return param.OriginalType, true
+		}
+		if local := ctx.localScope.FindVariable(name, position); local != nil && local.OriginalType != "" {
+			return local.OriginalType, true
+		}
+	}
+	if ctx.currentClass != nil {
+		if field := ctx.currentClass.FindFieldByName(name); field != nil && field.OriginalType != "" {
+			return field.OriginalType, true
+		}
+	}
+	return "", false
+}
+
+func inferExprJavaType(node *sitter.Node, ctx Ctx, source []byte) (string, bool) {
+	switch node.Type() {
+	case "identifier":
+		return inferIdentifierJavaType(node.Content(source), node.StartByte(), ctx)
+	case "this":
+		// Inside of a double brace initializer, `this` is the object that is
+		// being created
+		if ctx.initializedType != "" {
+			return ctx.initializedType, true
+		}
+		if ctx.currentClass == nil {
+			return "", false
+		}
+		base := ctx.currentClass.Class.OriginalName
+		if len(ctx.currentClass.TypeParameters) == 0 {
+			return base, true
+		}
+		return fmt.Sprintf("%s<%s>", base, strings.Join(ctx.currentClass.TypeParameters, ", ")), true
+	case "object_creation_expression":
+		typeNode := node.ChildByFieldName("type")
+		if typeNode == nil {
+			return "", false
+		}
+		return typeNode.Content(source), true
+	case "parenthesized_expression":
+		return inferExprJavaType(node.NamedChild(0), ctx, source)
+	case "field_access":
+		if field := fieldOfExpr(node.ChildByFieldName("object"), node.ChildByFieldName("field").Content(source), ctx, source); field != nil && field.OriginalType != "" {
+			return field.OriginalType, true
+		}
+	case "method_invocation":
+		if javaType, ok := mapMethodJavaType(node, ctx, source); ok {
+			return javaType, true
+		}
+		if javaType, ok := listMethodJavaType(node, ctx, source); ok {
+			return javaType, true
+		}
+		if javaType, ok := sortedMethodJavaType(node, ctx, source); ok {
+			return javaType, true
+		}
+		if javaType, ok := builderMethodJavaType(node, ctx, source); ok {
+			return javaType, true
+		}
+		class := ctx.currentClass
+		if object := node.ChildByFieldName("object"); object != nil {
+			if class = resolveClassScopeByIdentifier(ctx, source, object); class == nil {
+				javaType, ok := inferExprJavaType(object, ctx, source)
+				if !ok {
+					return "", false
+				}
+				className, _ := parseJavaTypeString(javaType)
+				class = resolveClassScopeByName(ctx, className)
+			}
+		}
+		if class == nil {
+			return "", false
+		}
+		name := node.ChildByFieldName("name").Content(source)
+		argCount := int(node.ChildByFieldName("arguments").NamedChildCount())
+		for _, method := range class.Methods {
+			if method.OriginalName != name || len(method.Parameters) != argCount || method.Constructor {
+				continue
+			}
+			// The type parameters of the class are only known from the object
+			returnType, _ := parseJavaTypeString(method.OriginalType)
+			if method.OriginalType == "" || class.IsTypeParameter(returnType) {
+				return "", false
+			}
+			return method.OriginalType, true
+		}
+	}
+	return "", false
+}
+
+// fieldOfExpr finds the field that is accessed on the result of an expression,
+// from the type of the expression, or nil if the type isn't known
+//
+// Ex: the `size` of `parent().size` is found in the class that `parent`
+// returns
+func fieldOfExpr(object *sitter.Node, fieldName string, ctx Ctx, source []byte) *symbol.Definition {
+	javaType, ok := inferExprJavaType(object, ctx, source)
+	if !ok {
+		return nil
+	}
+	className, _ := parseJavaTypeString(javaType)
+	if class := resolveClassScopeByName(ctx, className); class != nil {
+		return class.FindFieldByName(fieldName)
+	}
+	return nil
+}
+
+func applyTypeArguments(fun ast.Expr, args []ast.Expr) ast.Expr {
+	if len(args) == 0 {
+		return fun
+	}
+	if len(args) == 1 {
+		return &ast.IndexExpr{X: fun, Index: args[0]}
+	}
+	return &ast.IndexListExpr{X: fun, Indices: args}
+}
+
+type invocationTargetInfo struct {
+	classScope    *symbol.ClassScope
+	classTypeArgs []ast.Expr
+}
+
+func resolveInvocationTarget(objectNode *sitter.Node, ctx Ctx, source []byte) *invocationTargetInfo {
+	if ctx.currentFile == nil || ctx.currentFile.BaseClass == nil {
+		return nil
+	}
+
+	scopeTypeParams := inScopeTypeParameters(ctx)
+
+	var className string
+	var classTypeArgs []string
+	switch objectNode.Type() {
+	case "this":
+		if ctx.currentClass == nil {
+			return nil
+		}
+		className = ctx.currentClass.Class.OriginalName
+		classTypeArgs = ctx.currentClass.TypeParameters
+	case "identifier":
+		javaType, ok := inferIdentifierJavaType(objectNode.Content(source), objectNode.StartByte(), ctx)
+		if !ok {
+			return nil
+		}
+		className, classTypeArgs = parseJavaTypeString(javaType)
+	default:
+		javaType, ok := inferExprJavaType(objectNode, ctx, source)
+		if !ok {
+			return nil
+		}
+		className, classTypeArgs = parseJavaTypeString(javaType)
+	}
+
+	classScope := findClassScopeByName(ctx.currentFile.BaseClass, className)
+	if classScope == nil {
+		return nil
+	}
+
+	classTypeArgExprs := make([]ast.Expr, 0, len(classTypeArgs))
+	for _, arg := range classTypeArgs {
+		classTypeArgExprs = append(classTypeArgExprs, javaTypeStringToGoTypeExpr(arg, scopeTypeParams))
+	}
+
+	return &invocationTargetInfo{
+		classScope:    classScope,
+		classTypeArgs: classTypeArgExprs,
+	}
+}
+
+func explicitTypeArgumentExprs(node *sitter.Node, source []byte, typeParams []string) []ast.Expr {
+	typeArgsNode := node.ChildByFieldName("type_arguments")
+	if typeArgsNode == nil {
+		return nil
+	}
+	var exprs []ast.Expr
+	for _, arg := range nodeutil.NamedChildrenOf(typeArgsNode) {
+		exprs = append(exprs, javaTypeStringToGoTypeExpr(arg.Content(source), typeParams))
+	}
+	return exprs
+}
+
+func inferMethodTypeArguments(def *symbol.Definition, invocationNode *sitter.Node, ctx Ctx, source []byte) []ast.Expr {
+	if len(def.TypeParameters) == 0 {
+		return nil
+	}
+
+	if explicit := explicitTypeArgumentExprs(invocationNode, source, inScopeTypeParameters(ctx)); len(explicit) == len(def.TypeParameters) && len(explicit) > 0 {
+		return explicit
+	}
+
+	argsNode := invocationNode.ChildByFieldName("arguments")
+	if argsNode == nil {
+		return nil
+	}
+
+	resolved := make(map[string]ast.Expr)
+	argNodes := nodeutil.NamedChildrenOf(argsNode)
+	for idx, param := range def.Parameters {
+		for _, tp := range def.TypeParameters {
+			if param.OriginalType == tp && idx < len(argNodes) {
+				if javaType, ok := inferExprJavaType(argNodes[idx], ctx, source); ok {
+					resolved[tp] = javaTypeStringToGoTypeExpr(javaType, inScopeTypeParameters(ctx))
+				}
+			}
+		}
+	}
+
+	result := make([]ast.Expr, len(def.TypeParameters))
+	for i, tp := range def.TypeParameters {
+		if expr, ok := resolved[tp]; ok {
+			result[i] = expr
+		} else {
+			ctx.fallbacks.Report(invocationNode, anyFallbackDiagnostic, "the type argument `%s` of `%s` can't be inferred", tp, def.OriginalName)
+			result[i] = &ast.Ident{Name: "any"}
+		}
+	}
+	return result
+}
+
+func maybeRewriteInstanceGenericMethodInvocation(objectNode *sitter.Node, objectExpr ast.Expr, methodName string, args []ast.Expr, invocationNode *sitter.Node, ctx Ctx, source []byte) ast.Expr {
+	target := resolveInvocationTarget(objectNode, ctx, source)
+	if target == nil {
+		return nil
+	}
+
+	methodDefs := target.classScope.FindMethod().By(func(d *symbol.Definition) bool {
+		return d.OriginalName == methodName
+	})
+	var helperDef *symbol.Definition
+	for _, def := range methodDefs {
+		if def.RequiresHelper {
+			helperDef = def
+			break
+		}
+	}
+	if helperDef == nil {
+		return nil
+	}
+
+	classTypeArgs := target.classTypeArgs
+	methodTypeArgs := inferMethodTypeArguments(helperDef, invocationNode, ctx, source)
+	helperTypeArgs := append(classTypeArgs, methodTypeArgs...)
+
+	// Methods that can't be overridden are generic functions
+	if !target.classScope.CanBeOverridden(helperDef) {
+		return &ast.CallExpr{
+			Fun:  applyTypeArguments(&ast.Ident{Name: helperDef.HelperName}, helperTypeArgs),
+			Args: append([]ast.Expr{objectExpr}, args...),
+		}
+	}
+
+	constructorIdent := &ast.Ident{Name: "New" + helperDef.HelperName}
+	helperConstructor := applyTypeArguments(constructorIdent, helperTypeArgs)
+	helperCall := &ast.CallExpr{
+		Fun:  helperConstructor,
+		Args: []ast.Expr{objectExpr},
+	}
+
+	selIdent := &ast.Ident{Name: helperDef.Name}
+
+	return &ast.CallExpr{
+		Fun: &ast.SelectorExpr{
+			X:   helperCall,
+			Sel: selIdent,
+		},
+		Args: args,
+	}
+}
@@ -0,0 +1,135 @@
+package translate
+
+import (
+	"go/ast"
+	"strings"
+	"unicode"
+
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// A staticFactory is a generic static method that creates an object, such as
+// `List.of`, whose type arguments may only be known from the type that the
+// object is assigned to, like the type arguments of a constructor with `<>`
+type staticFactory struct {
+	typeParameters []string
+	// The Java types of the parameters, where the last one may be variadic
+	parameterTypes []string
+	returnType     string
+}
+
+// libraryFactories are the static factories from the Java standard library,
+// which have no symbols of their own
+var libraryFactories = map[string]staticFactory{
+	"List.of":               {[]string{"E"}, []string{"E..."}, "List<E>"},
+	"Set.of":                {[]string{"E"}, []string{"E..."}, "Set<E>"},
+	"Map.of":                {[]string{"K", "V"}, []string{"K", "V"}, "Map<K, V>"},
+	"Stream.of":             {[]string{"T"}, []string{"T..."}, "Stream<T>"},
+	"Stream.empty":          {[]string{"T"}, nil, "Stream<T>"},
+	"Optional.of":           {[]string{"T"}, []string{"T"}, "Optional<T>"},
+	"Optional.ofNullable":   {[]string{"T"}, []string{"T"}, "Optional<T>"},
+	"Optional.empty":        {[]string{"T"}, nil, "Optional<T>"},
+	"Collections.emptyList": {[]string{"T"}, nil, "List<T>"},
+	"Collections.emptySet":  {[]string{"T"}, nil, "Set<T>"},
+	"Collections.emptyMap":  {[]string{"K", "V"}, nil, "Map<K, V>"},
+}
+
+// factoryOf describes a static method in the source as a factory
+func factoryOf(method *symbol.Definition) staticFactory {
+	factory := staticFactory{
+		typeParameters: method.TypeParameters,
+		returnType:     method.OriginalType,
+	}
+	for _, param := range method.Parameters {
+		factory.parameterTypes = append(factory.parameterTypes, param.OriginalType)
+	}
+	return factory
+}
+
+// isInferred determines if Go can infer a type parameter of the factory from
+// the arguments of a call to it
+func (factory staticFactory) isInferred(typeParam string, argCount int) bool {
+	for index, paramType := range factory.parameterTypes {
+		if index < argCount && mentionsName(paramType, typeParam) {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionsName determines if a Java type mentions a name as a whole identifier,
+// such as the `T` of `List<T>`, but not of `Table`
+func mentionsName(javaType, name string) bool {
+	words := strings.FieldsFunc(javaType, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '$'
+	})
+	for _, word := range words {
+		if word == name {
+			return true
+		}
+	}
+	return false
+}
+
+// typeArguments infers the type arguments of a call to the factory from the
+// type that its result is expected to have, if Go can't infer them from the
+// arguments of the call. Otherwise, it returns nil
+//
+// Ex: `Box<String> box = Box.empty()` calls `BoxEmpty[string]()`
+func (factory staticFactory) typeArguments(argCount int, ctx Ctx) []ast.Expr {
+	if len(factory.typeParameters) == 0 || ctx.expectedType == "" {
+		return nil
+	}
+
+	inferred := true
+	for _, typeParam := range factory.typeParameters {
+		inferred = inferred && factory.isInferred(typeParam, argCount)
+	}
+	if inferred {
+		return nil
+	}
+
+	// The type parameters are matched up with the type arguments of the expected
+	// type, through the type arguments of the factory's return type
+	_, returnArgs := parseJavaTypeString(factory.returnType)
+	_, expectedArgs := parseJavaTypeString(ctx.expectedType)
+	if len(returnArgs) != len(expectedArgs) {
+		return nil
+	}
+
+	typeArgs := make([]ast.Expr, 0, len(factory.typeParameters))
+	for _, typeParam := range factory.typeParameters {
+		index := -1
+		for i, returnArg := range returnArgs {
+			if returnArg == typeParam {
+				index = i
+			}
+		}
+		if index < 0 {
+			return nil
+		}
+		typeArgs = append(typeArgs, javaTypeStringToGoTypeExpr(expectedArgs[index], inScopeTypeParameters(ctx)))
+	}
+	return typeArgs
+}
+
+// expectedReturnType returns the Java type that a `return` statement is
+// expected to return, which is the return type of the method that it is in
+//
+// This is empty for a `return` in a lambda, which returns from the lambda
+// instead
+func expectedReturnType(node *sitter.Node, ctx Ctx) string {
+	for parent := node.Parent(); parent != nil; parent = parent.Parent() {
+		switch parent.Type() {
+		case "method_declaration":
+			if ctx.localScope != nil {
+				return ctx.localScope.OriginalType
+			}
+			return ""
+		case "lambda_expression", "class_body", "constructor_declaration":
+			return ""
+		}
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package translate
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/NickyBoy89/java2go/astutil"
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// Wherever the type of something isn't known, the generated code falls back
+// to `any`, which compiles, but loses the type that Java checked. With
+// `-any-report`, every place that falls back to it is collected, and the files
+// are reported from the one with the most of them, so that the stubs and the
+// mappings that would fix the most of them can be added first:
+//
+//	2 Parser.java
+//		Parser.java:12:20: any-fallback: the type of the parameter `token` of the lambda isn't known
+//		Parser.java:30:9: any-fallback: the wildcard `?` has no upper bound
+//
+// The places are the parameters of the lambdas whose functional interfaces
+// aren't known, the wildcards that aren't bounded from above, and the type
+// arguments of the generic methods and interfaces that can't be inferred
+
+// The kind of every place that is reported as falling back to `any`
+const anyFallbackDiagnostic = "any-fallback"
+
+// reportWildcards reports every wildcard in the tree that is translated as
+// `any`, which are the ones that aren't bounded from above
+//
+// Ex: `List<?>` and `Comparator<? super T>` both fall back to `any`
+func reportWildcards(root *sitter.Node, source []byte, fallbacks *Diagnostics) {
+	if root.Type() == "wildcard" && astutil.WildcardBound(root) == nil {
+		fallbacks.Report(root, anyFallbackDiagnostic, "the wildcard `%s` has no upper bound", root.Content(source))
+	}
+	for _, child := range nodeutil.NamedChildrenOf(root) {
+		reportWildcards(child, source, fallbacks)
+	}
+}
+
+// WriteFallbackReport writes the places that fell back to `any` in each of the
+// files, as described above, leaving out the files that have none
+func WriteFallbackReport(w io.Writer, files []*Diagnostics) error {
+	ranked := make([]*Diagnostics, 0, len(files))
+	for _, file := range files {
+		if len(file.Items()) > 0 {
+			ranked = append(ranked, file)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return len(ranked[i].items) > len(ranked[j].items)
+	})
+
+	for _, file := range ranked {
+		if _, err := fmt.Fprintf(w, "%d %s\n", len(file.items), file.file); err != nil {
+			return err
+		}
+		for _, fallback := range file.Items() {
+			if _, err := fmt.Fprintf(w, "\t%s\n", fallback); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
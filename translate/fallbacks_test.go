@@ -0,0 +1,66 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnyFallbacks(t *testing.T) {
+	helper := setupParseHelper(t, `
+package app.shapes;
+import java.util.*;
+public class Shapes {
+    public int count(List<?> shapes, Comparator<? super Shape> order, List<? extends Shape> known) {
+        register(shape -> shape);
+        return shapes.size();
+    }
+    void register(Object handler) {}
+}
+`)
+	helper.Ctx.fallbacks = NewDiagnostics("Shapes.java")
+	reportWildcards(helper.File.Ast, helper.File.Source, helper.Ctx.fallbacks)
+
+	out := renderGoFileFromJavaCtx(t, helper)
+	if !strings.Contains(out, "func (ss *Shapes) Count(shapes *List[any], order *Comparator[any], known *List[*Shape]) int32 {") {
+		t.Errorf("Expected the wildcards to be translated, got:\n%s", out)
+	}
+
+	items := helper.Ctx.fallbacks.Items()
+	if len(items) != 3 {
+		t.Fatalf("Expected the two wildcards and the lambda to be reported, got %v", items)
+	}
+	for i, expected := range []string{
+		"Shapes.java:5:27: any-fallback: the wildcard `?` has no upper bound",
+		"Shapes.java:5:49: any-fallback: the wildcard `? super Shape` has no upper bound",
+		"Shapes.java:6:18: any-fallback: the type of the parameter `shape` of the lambda isn't known",
+	} {
+		if items[i].String() != expected {
+			t.Errorf("Expected %q, got %q", expected, items[i])
+		}
+	}
+	if diagnostics := helper.Ctx.diagnostics.Items(); len(diagnostics) != 0 {
+		t.Errorf("Expected the fallbacks to not be diagnostics, got %v", diagnostics)
+	}
+}
+
+func TestFallbackReport(t *testing.T) {
+	node := setupParseHelper(t, "class A { List<?> a; }").File.Ast
+	one, two, none := NewDiagnostics("One.java"), NewDiagnostics("Two.java"), NewDiagnostics("None.java")
+	one.Report(node, anyFallbackDiagnostic, "first")
+	two.Report(node, anyFallbackDiagnostic, "second")
+	two.Report(node, anyFallbackDiagnostic, "third")
+
+	var report strings.Builder
+	if err := WriteFallbackReport(&report, []*Diagnostics{one, none, two}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `2 Two.java
+	Two.java:1:1: any-fallback: second
+	Two.java:1:1: any-fallback: third
+1 One.java
+	One.java:1:1: any-fallback: first
+`
+	if report.String() != expected {
+		t.Errorf("Expected the files to be ranked by their fallbacks, got:\n%s", report.String())
+	}
+}
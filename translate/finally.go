@@ -0,0 +1,358 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// A `finally` block that completes abruptly (with a `return`, `break`, or
+// `continue`) overrides however the `try` block completed, and even discards
+// any exception that was thrown from it
+//
+// Deferring the finally block can't reproduce this, since a deferred function
+// can't break out of a loop, so these blocks are lowered into explicit control
+// flow instead:
+//
+//	{
+//		_tryCompletion, _tryResult, _tryPanic := func() (_completion int, _result T, _thrown any) {
+//			defer func() {
+//				_thrown = recover()
+//			}()
+//			// The body of the try block, with every `return x` rewritten to
+//			// `return 1, x, nil`, and every `break` or `continue` that leaves the
+//			// try block rewritten to return its own completion code
+//			return
+//		}()
+//		// The body of the finally block
+//		if _tryPanic != nil {
+//			panic(_tryPanic)
+//		}
+//		if _tryCompletion == 1 {
+//			return _tryResult
+//		}
+//	}
+
+const (
+	// The try block completed by running to the end of its body
+	completionNormal = iota
+	// The try block completed with a return statement
+	completionReturn
+	// Every other completion code is a break or continue out of the try block
+	completionFirstBranch
+)
+
+// finallyClauseOf returns the `finally` clause of a try statement, or nil if
+// the statement doesn't have one
+func finallyClauseOf(node *sitter.Node) *sitter.Node {
+	for _, child := range nodeutil.NamedChildrenOf(node) {
+		if child.Type() == "finally_clause" {
+			return child
+		}
+	}
+	return nil
+}
+
+// completesAbruptly determines if a block of Java code contains a statement
+// that transfers control outside of the block, such as a `return`, or a `break`
+// or `continue` that targets a loop outside of the block
+func completesAbruptly(node *sitter.Node, source []byte) bool {
+	return findAbruptCompletion(node, source, false, false, map[string]bool{})
+}
+
+func findAbruptCompletion(node *sitter.Node, source []byte, inLoop, inSwitch bool, labels map[string]bool) bool {
+	switch node.Type() {
+	case "return_statement":
+		return true
+	case "break_statement", "continue_statement":
+		// Labeled statements only escape if the label is defined outside of the block
+		if node.NamedChildCount() > 0 {
+			return !labels[node.NamedChild(0).Content(source)]
+		}
+		if node.Type() == "break_statement" {
+			return !inLoop && !inSwitch
+		}
+		return !inLoop
+	// Lambdas and classes declared in the block have their own control flow
+	case "lambda_expression", "class_body":
+		return false
+	case "for_statement", "enhanced_for_statement", "while_statement", "do_statement":
+		inLoop = true
+	case "switch_block":
+		inSwitch = true
+	case "labeled_statement":
+		labels[node.NamedChild(0).Content(source)] = true
+		defer delete(labels, node.NamedChild(0).Content(source))
+	}
+
+	for _, child := range nodeutil.NamedChildrenOf(node) {
+		if findAbruptCompletion(child, source, inLoop, inSwitch, labels) {
+			return true
+		}
+	}
+	return false
+}
+
+// deferFinally translates a try statement whose finally block completes
+// normally, by deferring the finally block before the rest of the statement,
+// so that it runs however the try block and its catch clauses complete, even
+// when they panic. The rest of the statement is run the same way as the body
+// of a try-with-resources statement, so that the finally block runs when the
+// statement completes, instead of when the method returns
+//
+// A returned value is evaluated before the deferred function runs, the same
+// way that Java evaluates it before running the finally block, so the finally
+// block can't change what is returned
+//
+// Ex: `try { ... } finally { unlock(); }` becomes
+// `func() { defer func() { unlock() }(); ... }()`
+func deferFinally(node, finally *sitter.Node, parseRest func(Ctx) []ast.Stmt, source []byte, ctx Ctx) []ast.Stmt {
+	// The errors of the statement can't be returned out of a function literal,
+	// and the finally block can't return them at all
+	errorResult := returnsError(ctx)
+	finallyCtx := ctx
+	finallyCtx.errorTarget = nil
+	if !endsMethod(node) {
+		ctx.errorTarget = nil
+	}
+
+	deferred := &ast.DeferStmt{Call: &ast.CallExpr{Fun: &ast.FuncLit{
+		Type: &ast.FuncType{Params: &ast.FieldList{}},
+		Body: ParseStmt(finally.NamedChild(0), source, finallyCtx).(*ast.BlockStmt),
+	}}}
+	return runDeferred(node, append([]ast.Stmt{deferred}, parseRest(ctx)...), errorResult, ctx)
+}
+
+// lowerAbruptFinally translates a try statement whose finally block completes
+// abruptly into explicit control flow, as described above
+func lowerAbruptFinally(node, finally *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	// The errors of the try block can't be returned out of its function literal
+	bodyCtx := ctx
+	bodyCtx.errorTarget = nil
+	tryBody := ParseStmt(node.ChildByFieldName("body"), source, bodyCtx).(*ast.BlockStmt)
+	finallyBody := ParseStmt(finally.NamedChild(0), source, ctx).(*ast.BlockStmt)
+
+	resultType := methodResultType(ctx)
+	rewriter := &completionRewriter{hasResult: resultType != nil, recovers: true, returnsError: returnsError(ctx)}
+	rewriter.rewriteList(tryBody.List, false, false, map[string]bool{})
+	if len(tryBody.List) == 0 || !isTerminating(tryBody.List[len(tryBody.List)-1]) {
+		tryBody.List = append(tryBody.List, &ast.ReturnStmt{})
+	}
+
+	// Recover from any panic, so that the finally block always runs
+	tryBody.List = append([]ast.Stmt{
+		&ast.DeferStmt{
+			Call: &ast.CallExpr{
+				Fun: &ast.FuncLit{
+					Type: &ast.FuncType{Params: &ast.FieldList{}},
+					Body: &ast.BlockStmt{List: []ast.Stmt{
+						&ast.AssignStmt{
+							Lhs: []ast.Expr{&ast.Ident{Name: "_thrown"}},
+							Tok: token.ASSIGN,
+							Rhs: []ast.Expr{&ast.CallExpr{Fun: &ast.Ident{Name: "recover"}}},
+						},
+					}},
+				},
+			},
+		},
+	}, tryBody.List...)
+
+	// The completion and the result are only bound when something checks them,
+	// since Go won't compile a variable that is never read
+	checks := rewriter.completionChecks()
+	results := &ast.FieldList{List: []*ast.Field{{Names: []*ast.Ident{{Name: "_completion"}}, Type: &ast.Ident{Name: "int"}}}}
+	captured := []ast.Expr{capturedName("_tryCompletion", len(checks) > 0)}
+	if resultType != nil {
+		results.List = append(results.List, &ast.Field{Names: []*ast.Ident{{Name: "_result"}}, Type: resultType})
+		captured = append(captured, capturedName("_tryResult", rewriter.returns))
+	}
+	results.List = append(results.List, &ast.Field{Names: []*ast.Ident{{Name: "_thrown"}}, Type: &ast.Ident{Name: "any"}})
+	captured = append(captured, &ast.Ident{Name: "_tryPanic"})
+
+	lowered := &ast.BlockStmt{List: []ast.Stmt{
+		&ast.AssignStmt{
+			Lhs: captured,
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{&ast.CallExpr{
+				Fun: &ast.FuncLit{
+					Type: &ast.FuncType{Params: &ast.FieldList{}, Results: results},
+					Body: tryBody,
+				},
+			}},
+		},
+	}}
+
+	lowered.List = append(lowered.List, finallyBody.List...)
+
+	// If the finally block completed normally, then the try block completes
+	// the same way that it would have without the finally block
+	lowered.List = append(lowered.List, &ast.IfStmt{
+		Cond: &ast.BinaryExpr{X: &ast.Ident{Name: "_tryPanic"}, Op: token.NEQ, Y: &ast.Ident{Name: "nil"}},
+		Body: &ast.BlockStmt{List: []ast.Stmt{
+			&ast.ExprStmt{X: &ast.CallExpr{Fun: &ast.Ident{Name: "panic"}, Args: []ast.Expr{&ast.Ident{Name: "_tryPanic"}}}},
+		}},
+	})
+
+	lowered.List = append(lowered.List, checks...)
+	return endUnreachable(node, lowered, source)
+}
+
+// capturedName returns the name that one of the results of a lowered try block
+// is bound to, or a blank identifier if it is never read
+func capturedName(name string, read bool) *ast.Ident {
+	if !read {
+		return &ast.Ident{Name: "_"}
+	}
+	return &ast.Ident{Name: name}
+}
+
+// methodResultType returns the type of the value that the current method
+// returns, or nil for constructors and void methods, which don't return one
+func methodResultType(ctx Ctx) ast.Expr {
+	if ctx.localScope != nil && !ctx.localScope.Constructor && ctx.localScope.Type != "" {
+		return &ast.Ident{Name: ctx.localScope.Type}
+	}
+	return nil
+}
+
+// completionChecks continues the way that the lowered try block completed,
+// for each of the ways that it can complete, other than normally
+func (cr *completionRewriter) completionChecks() []ast.Stmt {
+	var checks []ast.Stmt
+	returnStmt := &ast.ReturnStmt{}
+	if cr.hasResult {
+		returnStmt.Results = []ast.Expr{&ast.Ident{Name: "_tryResult"}}
+	}
+	if cr.returnsError {
+		returnStmt.Results = append(returnStmt.Results, &ast.Ident{Name: "nil"})
+	}
+	if cr.returns {
+		checks = append(checks, completionCheck(completionReturn, returnStmt))
+	}
+	for ind, branch := range cr.branches {
+		checks = append(checks, completionCheck(completionFirstBranch+ind, branch))
+	}
+	return checks
+}
+
+// completionCheck runs the given statement if the try block completed with
+// the given completion code
+func completionCheck(completion int, stmt ast.Stmt) ast.Stmt {
+	return &ast.IfStmt{
+		Cond: &ast.BinaryExpr{
+			X:  &ast.Ident{Name: "_tryCompletion"},
+			Op: token.EQL,
+			Y:  &ast.BasicLit{Kind: token.INT, Value: strconv.Itoa(completion)},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{stmt}},
+	}
+}
+
+// A completionRewriter rewrites the statements that leave a try block into
+// returns out of the function literal that the block has been lowered into
+type completionRewriter struct {
+	// If the enclosing method returns a value
+	hasResult bool
+	// If the lowered try block recovers from any panic, and returns it
+	recovers bool
+	// If the try block contains a return statement
+	returns bool
+	// If the enclosing method returns an error along with its result, in the
+	// errors mode
+	returnsError bool
+	// The returns that already return out of the lowered try block, which are
+	// left as they are
+	kept map[*ast.ReturnStmt]bool
+	// Every distinct break and continue that leaves the try block
+	branches []*ast.BranchStmt
+}
+
+// completionReturn generates the return out of the lowered try block, for the
+// given completion code and result value
+func (cr *completionRewriter) completionReturn(completion int, result ast.Expr) *ast.ReturnStmt {
+	values := []ast.Expr{&ast.BasicLit{Kind: token.INT, Value: strconv.Itoa(completion)}}
+	if cr.hasResult {
+		values = append(values, result)
+	}
+	if cr.recovers {
+		values = append(values, &ast.Ident{Name: "nil"})
+	}
+	return &ast.ReturnStmt{Results: values}
+}
+
+// branchCompletion returns the completion code for a break or continue,
+// registering it if it has not been seen before
+func (cr *completionRewriter) branchCompletion(branch *ast.BranchStmt) int {
+	for ind, existing := range cr.branches {
+		if existing.Tok == branch.Tok && (existing.Label == nil) == (branch.Label == nil) &&
+			(existing.Label == nil || existing.Label.Name == branch.Label.Name) {
+			return completionFirstBranch + ind
+		}
+	}
+	cr.branches = append(cr.branches, branch)
+	return completionFirstBranch + len(cr.branches) - 1
+}
+
+func (cr *completionRewriter) rewriteList(stmts []ast.Stmt, inLoop, inSwitch bool, labels map[string]bool) {
+	// The label after a labeled block is jumped to from inside of the block
+	for _, stmt := range stmts {
+		if labeled, ok := stmt.(*ast.LabeledStmt); ok && !labels[labeled.Label.Name] {
+			labels[labeled.Label.Name] = true
+			defer delete(labels, labeled.Label.Name)
+		}
+	}
+	for ind, stmt := range stmts {
+		stmts[ind] = cr.rewrite(stmt, inLoop, inSwitch, labels)
+	}
+}
+
+func (cr *completionRewriter) rewrite(stmt ast.Stmt, inLoop, inSwitch bool, labels map[string]bool) ast.Stmt {
+	switch stmt := stmt.(type) {
+	case *ast.ReturnStmt:
+		if cr.kept[stmt] {
+			return stmt
+		}
+		cr.returns = true
+		var result ast.Expr = &ast.Ident{Name: "_result"}
+		if len(stmt.Results) > 0 {
+			result = stmt.Results[0]
+		}
+		return cr.completionReturn(completionReturn, result)
+	case *ast.BranchStmt:
+		escapes := false
+		switch {
+		case stmt.Label != nil:
+			escapes = !labels[stmt.Label.Name]
+		case stmt.Tok == token.BREAK:
+			escapes = !inLoop && !inSwitch
+		case stmt.Tok == token.CONTINUE:
+			escapes = !inLoop
+		}
+		if escapes {
+			return cr.completionReturn(cr.branchCompletion(stmt), &ast.Ident{Name: "_result"})
+		}
+	case *ast.BlockStmt:
+		cr.rewriteList(stmt.List, inLoop, inSwitch, labels)
+	case *ast.IfStmt:
+		cr.rewriteList(stmt.Body.List, inLoop, inSwitch, labels)
+		if stmt.Else != nil {
+			stmt.Else = cr.rewrite(stmt.Else, inLoop, inSwitch, labels)
+		}
+	case *ast.ForStmt:
+		cr.rewriteList(stmt.Body.List, true, inSwitch, labels)
+	case *ast.RangeStmt:
+		cr.rewriteList(stmt.Body.List, true, inSwitch, labels)
+	case *ast.SwitchStmt:
+		for _, clause := range stmt.Body.List {
+			cr.rewriteList(clause.(*ast.CaseClause).Body, inLoop, true, labels)
+		}
+	case *ast.LabeledStmt:
+		labels[stmt.Label.Name] = true
+		stmt.Stmt = cr.rewrite(stmt.Stmt, inLoop, inSwitch, labels)
+		delete(labels, stmt.Label.Name)
+	}
+	return stmt
+}
@@ -0,0 +1,175 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFinally_ReturnOverridesTryBlock(t *testing.T) {
+	src := `
+package fin.returns;
+public class Finally {
+    int value(int n) {
+        try {
+            return n;
+        } finally {
+            if (n > 5) {
+                return 5;
+            }
+        }
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	flat := normalizeSpaces(out)
+	if !strings.Contains(flat, "_tryCompletion, _tryResult, _tryPanic := func() (_completion int, _result int32, _thrown any) {") {
+		t.Errorf("Expected the try block to be lowered into a function literal, got:\n%s", out)
+	}
+	if !strings.Contains(flat, "return 1, n, nil") {
+		t.Errorf("Expected the return in the try block to record its result, got:\n%s", out)
+	}
+	// The finally block has to run before the recorded result is returned
+	finallyReturn := strings.Index(out, "return 5")
+	recordedReturn := strings.Index(out, "return _tryResult")
+	if finallyReturn == -1 || recordedReturn == -1 || finallyReturn > recordedReturn {
+		t.Errorf("Expected the finally block to run before the try block's return, got:\n%s", out)
+	}
+}
+
+func TestFinally_BreakAndContinueLeaveTheTryBlock(t *testing.T) {
+	src := `
+package fin.branches;
+public class Finally {
+    void loop(int n) {
+        outer:
+        for (int i = 0; i < n; i++) {
+            try {
+                if (i == 1) continue;
+                if (i == 2) break outer;
+                for (int j = 0; j < n; j++) {
+                    break;
+                }
+            } finally {
+                if (n > 5) break;
+            }
+        }
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	flat := normalizeSpaces(out)
+	if !strings.Contains(flat, "func() (_completion int, _thrown any)") {
+		t.Errorf("Expected a void method to not record a result, got:\n%s", out)
+	}
+	if !strings.Contains(flat, "return 2, nil") || !strings.Contains(flat, "return 3, nil") {
+		t.Errorf("Expected each branch out of the try block to get a completion code, got:\n%s", out)
+	}
+	if !strings.Contains(flat, "if _tryCompletion == 2 { continue }") || !strings.Contains(flat, "if _tryCompletion == 3 { break outer }") {
+		t.Errorf("Expected the branches to be replayed after the finally block, got:\n%s", out)
+	}
+	// The inner loop's break doesn't leave the try block
+	if !strings.Contains(flat, "for j := 0; j < n; j++ { break }") {
+		t.Errorf("Expected the inner break to be left alone, got:\n%s", out)
+	}
+}
+
+func TestFinally_UnreadCompletionIsNotBound(t *testing.T) {
+	src := `
+package fin.unread;
+public class Finally {
+    int count(int n) {
+        for (int i = 0; i < 10; i++) {
+            try {
+                n++;
+            } finally {
+                if (n > 3) break;
+            }
+        }
+        return n;
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	flat := normalizeSpaces(out)
+	if !strings.Contains(flat, "_, _, _tryPanic := func() (_completion int, _result int32, _thrown any) {") {
+		t.Errorf("Expected the unread completion and result to not be bound, got:\n%s", out)
+	}
+	if strings.Contains(out, "_tryCompletion") {
+		t.Errorf("Expected no completion checks, got:\n%s", out)
+	}
+}
+
+func TestFinally_NormalCompletionIsNotLowered(t *testing.T) {
+	src := `
+package fin.normal;
+public class Finally {
+    void run(int n) {
+        try {
+            n++;
+        } finally {
+            n--;
+        }
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if strings.Contains(out, "_tryCompletion") {
+		t.Errorf("Expected a finally block without abrupt completion to not be lowered, got:\n%s", out)
+	}
+}
+
+func TestFinally_DeferredAtTheEndOfTheMethod(t *testing.T) {
+	src := `
+package fin.deferred;
+public class Finally {
+    void release() {}
+    void run(int n) {
+        try {
+            n++;
+        } finally {
+            release();
+        }
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	flat := normalizeSpaces(out)
+	if !strings.Contains(flat, "func (fy *Finally) run(n int32) { defer func() { release() }() n++ }") {
+		t.Errorf("Expected the finally block to be deferred in the method itself, got:\n%s", out)
+	}
+}
+
+func TestFinally_DeferredWithCatchAndReturn(t *testing.T) {
+	src := `
+package fin.catchreturn;
+public class Finally {
+    int count;
+    void release() {}
+    int run(int n) {
+        try {
+            if (n > 3) {
+                return n;
+            }
+        } catch (IllegalStateException e) {
+            n = 0;
+        } finally {
+            release();
+        }
+        return n + 1;
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	flat := normalizeSpaces(out)
+	// The finally block runs after the catch clauses, so it is deferred in the
+	// function literal around them
+	if !strings.Contains(flat, "_tryCompletion, _tryResult := func() (_completion int, _result int32) { defer func() { release() }()") {
+		t.Errorf("Expected the finally block to be deferred around the try statement, got:\n%s", out)
+	}
+	if !strings.Contains(flat, "if _tryCompletion == 1 { return 1, _tryResult }") {
+		t.Errorf("Expected the return of the try block to leave the deferred function literal, got:\n%s", out)
+	}
+	if !strings.Contains(flat, "if _tryCompletion == 1 { return _tryResult } } return n + 1") {
+		t.Errorf("Expected the method to return the try block's result after the finally block, got:\n%s", out)
+	}
+}
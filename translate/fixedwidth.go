@@ -0,0 +1,268 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"math/big"
+	"strconv"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/NickyBoy89/java2go/codegen"
+)
+
+// With the JavaArithmetic option, the integer arithmetic keeps the semantics
+// of Java's fixed-width integers wherever Go's would differ:
+//
+//	int max = 0x7FFFFFFF + 1;              max := -2147483648
+//	int sum = a + b; // bytes             sum := int32(int8(a)) + int32(int8(b))
+//	int high = value >>> shift;            high := int32(uint32(value) >> (shift & 31))
+//	value >>>= 8; // long                  value = int64(uint64(value) >> 8)
+//
+// Go evaluates constant expressions exactly, and refuses to compile the ones
+// that overflow their type, so the constant expressions that Java would wrap
+// around are folded into their wrapped values. The operands of a `byte` or a
+// `short` operation are promoted to an `int`, like Java does, instead of
+// wrapping around at their own width, and an unsigned shift is made on the
+// unsigned type of the same width, instead of through the runtime
+
+// unsignedTypes are the unsigned Go types that the promoted integral types are
+// shifted as
+var unsignedTypes = map[string]string{
+	"int":  "uint32",
+	"long": "uint64",
+}
+
+// unsignedShift shifts a value of the given Java type to the right as an
+// unsigned number of the type that it is promoted to, which still has to be
+// converted back to a signed type
+//
+// Ex: `value >>> 8` becomes `uint32(value) >> 8`, or `uint32(int8(value)) >> 8`
+// for a byte
+func unsignedShift(value, count ast.Expr, promoted, javaType string, left *sitter.Node, source []byte) ast.Expr {
+	unsigned := unsignedTypes[promoted]
+	// A constant can't be converted to an unsigned type if it is negative, so
+	// it is written as its two's complement instead
+	if constant, ok := evaluateConstant(left, source); ok {
+		bits := uint64(constant.value)
+		if promoted == "int" {
+			bits = uint64(uint32(constant.value))
+		}
+		return &ast.BinaryExpr{X: convertTo(unsigned, &ast.BasicLit{Kind: token.INT, Value: strconv.FormatUint(bits, 10)}), Op: token.SHR, Y: count}
+	}
+	return &ast.BinaryExpr{X: widened(unsigned, javaType, value), Op: token.SHR, Y: count}
+}
+
+// unsignedShiftAssignment returns the value that an unsigned right shift
+// assignment (`>>>=`) assigns to its variable
+//
+// Ex: `value >>>= 8` assigns `stdjava.UnsignedRightShift(value, 8)`, or
+// `int32(uint32(value) >> 8)` with the JavaArithmetic option
+func unsignedShiftAssignment(left, right *sitter.Node, variable, count ast.Expr, source []byte, ctx Ctx) ast.Expr {
+	javaType := integralType(left, ctx, source)
+	if !JavaArithmetic || javaType == "" {
+		return runtimeCall("UnsignedRightShift", variable, count)
+	}
+	promoted := promotedType(javaType)
+	count = maskedShiftCount(right, count, promoted, source)
+	return convertTo(integralGoTypes[javaType], unsignedShift(variable, count, promoted, javaType, left, source))
+}
+
+// promotedNarrowOperands translates an arithmetic or bitwise operation on two
+// operands of the same type that is narrower than an `int`, which Java
+// promotes to an `int` before the operation, or returns nil if the operation
+// isn't one
+//
+// Ex: `a + b` becomes `int32(int8(a)) + int32(int8(b))` for two bytes
+func promotedNarrowOperands(node *sitter.Node, operator string, source []byte, ctx Ctx) ast.Expr {
+	switch operator {
+	case "+", "-", "*", "/", "%", "&", "|", "^":
+	default:
+		return nil
+	}
+	left, right := node.ChildByFieldName("left"), node.ChildByFieldName("right")
+	leftType := integralType(left, ctx, source)
+	if leftType != "byte" && leftType != "short" {
+		return nil
+	}
+	return &ast.BinaryExpr{
+		X:  promoteOperand(left, ParseExpr(left, source, ctx), "int", ctx, source),
+		Op: codegen.StrToToken(operator),
+		Y:  promoteOperand(right, ParseExpr(right, source, ctx), "int", ctx, source),
+	}
+}
+
+// An integerConstant is the value of an integer constant expression, both as
+// Java evaluates it, wrapping around at the width of its type, and as Go
+// evaluates it, exactly, if Go can evaluate it at all
+type integerConstant struct {
+	value int64
+	exact *big.Int
+	long  bool
+	// Whether Go evaluates any part of the expression differently, which it
+	// can't compile even if the parts that are around it evaluate the same
+	differs bool
+}
+
+// compared notes if Go evaluates a constant differently than Java, or if any
+// of the constants that it was evaluated from are
+func (c integerConstant) compared(operands ...integerConstant) integerConstant {
+	c.differs = c.exact == nil || c.exact.Cmp(big.NewInt(c.value)) != 0
+	for _, operand := range operands {
+		c.differs = c.differs || operand.differs
+	}
+	return c
+}
+
+// wrapped truncates a number to the width of an `int`, or of a `long`, as a
+// signed number
+func wrapped(number *big.Int, long bool) int64 {
+	bits := new(big.Int).And(number, new(big.Int).SetUint64(^uint64(0))).Uint64()
+	if long {
+		return int64(bits)
+	}
+	return int64(int32(uint32(bits)))
+}
+
+// evaluateConstant evaluates an integer constant expression that is made out
+// of literals, or returns false if the expression isn't one
+func evaluateConstant(node *sitter.Node, source []byte) (integerConstant, bool) {
+	switch node.Type() {
+	case "decimal_integer_literal", "hex_integer_literal", "octal_integer_literal", "binary_integer_literal":
+		literal := strings.ToLower(node.Content(source))
+		long := strings.HasSuffix(literal, "l")
+		number, ok := new(big.Int).SetString(strings.ReplaceAll(strings.TrimSuffix(literal, "l"), "_", ""), 0)
+		if !ok {
+			return integerConstant{}, false
+		}
+		// The literals that set the sign bit are already negative
+		value := wrapped(number, long)
+		return integerConstant{value: value, exact: big.NewInt(value), long: long}, true
+	case "parenthesized_expression":
+		return evaluateConstant(node.NamedChild(0), source)
+	case "unary_expression":
+		operand, ok := evaluateConstant(node.ChildByFieldName("operand"), source)
+		if !ok {
+			return integerConstant{}, false
+		}
+		switch node.ChildByFieldName("operator").Type() {
+		case "+":
+			return operand, true
+		case "-":
+			return operand.apply(-operand.value, func(exact *big.Int) *big.Int { return exact.Neg(exact) }), true
+		case "~":
+			return operand.apply(^operand.value, func(exact *big.Int) *big.Int { return exact.Not(exact) }), true
+		}
+	case "binary_expression":
+		left, ok := evaluateConstant(node.ChildByFieldName("left"), source)
+		if !ok {
+			return integerConstant{}, false
+		}
+		right, ok := evaluateConstant(node.ChildByFieldName("right"), source)
+		if !ok {
+			return integerConstant{}, false
+		}
+		return left.binary(node.ChildByFieldName("operator").Type(), right)
+	}
+	return integerConstant{}, false
+}
+
+// apply returns the result of an operation on a constant, which is wrapped
+// around for Java, and applied to the exact value for Go
+func (c integerConstant) apply(value int64, exact func(*big.Int) *big.Int) integerConstant {
+	result := integerConstant{value: wrapped(big.NewInt(value), c.long), long: c.long}
+	if c.exact != nil {
+		result.exact = exact(new(big.Int).Set(c.exact))
+	}
+	return result.compared(c)
+}
+
+// binary returns the result of a binary operation on two constants, or false
+// if it can't be evaluated, such as a division by zero
+func (c integerConstant) binary(operator string, right integerConstant) (integerConstant, bool) {
+	switch operator {
+	case "<<", ">>", ">>>":
+		width := int64(32)
+		if c.long {
+			width = 64
+		}
+		count := right.value & (width - 1)
+		var value int64
+		switch {
+		case operator == "<<":
+			value = c.value << count
+		case operator == ">>":
+			value = c.value >> count
+		case c.long:
+			value = int64(uint64(c.value) >> count)
+		default:
+			value = int64(uint32(c.value) >> count)
+		}
+		result := integerConstant{value: wrapped(big.NewInt(value), c.long), long: c.long}
+		// Go has no unsigned shift, and doesn't mask the count
+		if operator != ">>>" && c.exact != nil && right.value >= 0 && right.value < 64 {
+			if operator == "<<" {
+				result.exact = new(big.Int).Lsh(c.exact, uint(right.value))
+			} else {
+				result.exact = new(big.Int).Rsh(c.exact, uint(right.value))
+			}
+		}
+		return result.compared(c, right), true
+	}
+
+	long := c.long || right.long
+	var value int64
+	var exact func(x, y *big.Int) *big.Int
+	switch operator {
+	case "+":
+		value, exact = c.value+right.value, new(big.Int).Add
+	case "-":
+		value, exact = c.value-right.value, new(big.Int).Sub
+	case "*":
+		value, exact = c.value*right.value, new(big.Int).Mul
+	case "/", "%":
+		if right.value == 0 {
+			return integerConstant{}, false
+		}
+		// The smallest value divided by -1 overflows back to itself in both
+		if operator == "/" {
+			value, exact = c.value/right.value, new(big.Int).Quo
+		} else {
+			value, exact = c.value%right.value, new(big.Int).Rem
+		}
+	case "&":
+		value, exact = c.value&right.value, new(big.Int).And
+	case "|":
+		value, exact = c.value|right.value, new(big.Int).Or
+	case "^":
+		value, exact = c.value^right.value, new(big.Int).Xor
+	default:
+		return integerConstant{}, false
+	}
+	result := integerConstant{value: wrapped(big.NewInt(value), long), long: long}
+	if c.exact != nil && right.exact != nil {
+		result.exact = exact(c.exact, right.exact)
+	}
+	return result.compared(c, right), true
+}
+
+// wrappedConstant translates an integer constant expression that Go would
+// evaluate differently than Java into the value that Java evaluates it to, or
+// returns nil if the expression isn't one, or Go evaluates it the same way
+//
+// Ex: `0x7FFFFFFF + 1` becomes `-2147483648`, and `1L << 64` becomes `int64(1)`
+func wrappedConstant(node *sitter.Node, source []byte) ast.Expr {
+	constant, ok := evaluateConstant(node, source)
+	if !ok || !constant.differs {
+		return nil
+	}
+	var value ast.Expr = &ast.BasicLit{Kind: token.INT, Value: strconv.FormatInt(constant.value, 10)}
+	if constant.value < 0 {
+		value = &ast.UnaryExpr{Op: token.SUB, X: &ast.BasicLit{Kind: token.INT, Value: new(big.Int).Neg(big.NewInt(constant.value)).String()}}
+	}
+	if constant.long {
+		return convertTo("int64", value)
+	}
+	return value
+}
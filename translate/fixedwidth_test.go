@@ -0,0 +1,73 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJavaArithmetic(t *testing.T) {
+	JavaArithmetic = true
+	defer func() { JavaArithmetic = false }()
+
+	out := renderGoFileFromJava(t, `
+package app.wrap;
+public class Wrap {
+    static int overflow() {
+        return 0x7FFFFFFF + 1;
+    }
+    static int restored() {
+        return (0x7FFFFFFF + 1) - 1;
+    }
+    static long rotated() {
+        return 1L << 64;
+    }
+    static int allOnes() {
+        return -1 >>> 28;
+    }
+    static int sum(byte a, byte b) {
+        return a + b;
+    }
+    static int high(int value, int shift) {
+        return value >>> shift;
+    }
+    static int ones(int shift) {
+        return -1 >>> shift;
+    }
+    static long shiftIn(long value, int n) {
+        value >>>= 8;
+        value <<= n;
+        n >>= value;
+        n <<= 33;
+        return value << n;
+    }
+    static byte shiftByte(byte b) {
+        b >>>= 1;
+        return b;
+    }
+    static int highByte(byte b) {
+        return b >>> 4;
+    }
+}
+`)
+	for _, expected := range []string{
+		"return -2147483648",
+		"return 2147483647",
+		"return int64(1)",
+		"return 15",
+		"return int32(int8(a)) + int32(int8(b))",
+		"return int32(uint32(value) >> (shift & 31))",
+		"return int32(uint32(4294967295) >> (shift & 31))",
+		"value = int64(uint64(value) >> 8)",
+		"value <<= (n & 63)",
+		"n >>= (value & 31)",
+		"n <<= 1",
+		"return value << (n & 63)",
+		// A byte extends its sign before it is shifted
+		"b = byte(uint32(int8(b)) >> 1)",
+		"return int32(uint32(int8(b)) >> 4)",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
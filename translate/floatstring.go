@@ -0,0 +1,85 @@
+package translate
+
+import (
+	"go/ast"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// floatFormatter returns the runtime function that formats a floating-point
+// expression the same way as Java, depending on if it is a float or a double
+func floatFormatter(node *sitter.Node, ctx Ctx, source []byte) string {
+	switch node.Type() {
+	case "decimal_floating_point_literal", "hex_floating_point_literal":
+		if strings.HasSuffix(strings.ToLower(node.Content(source)), "f") {
+			return "FormatFloat"
+		}
+		return "FormatDouble"
+	case "parenthesized_expression":
+		return floatFormatter(node.NamedChild(0), ctx, source)
+	case "unary_expression":
+		return floatFormatter(node.ChildByFieldName("operand"), ctx, source)
+	case "binary_expression":
+		// Arithmetic on a float and a double results in a double
+		for _, operand := range []*sitter.Node{node.ChildByFieldName("left"), node.ChildByFieldName("right")} {
+			if isFloatingExpr(operand, ctx, source) && floatFormatter(operand, ctx, source) == "FormatDouble" {
+				return "FormatDouble"
+			}
+		}
+		return "FormatFloat"
+	case "cast_expression":
+		return floatTypeFormatter(node.ChildByFieldName("type").Content(source))
+	}
+	javaType, _ := inferExprJavaType(node, ctx, source)
+	return floatTypeFormatter(stripJavaQualifier(javaType))
+}
+
+// floatTypeFormatter returns the runtime function that formats values of a
+// floating-point type
+func floatTypeFormatter(javaType string) string {
+	if javaType == "float" || javaType == "Float" {
+		return "FormatFloat"
+	}
+	return "FormatDouble"
+}
+
+// formatFloating formats a floating-point value the same way as Java, in
+// strict mode, or returns the value unchanged otherwise
+func formatFloating(node *sitter.Node, value ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	if !StrictMode || !isFloatingExpr(node, ctx, source) {
+		return value
+	}
+	return runtimeCall(floatFormatter(node, ctx, source), value)
+}
+
+// floatToString translates the methods that convert a floating-point number
+// into a string, which Go formats differently than Java, or returns nil if
+// the method isn't one of them. This is only done in strict mode
+//
+// Ex: `Double.toString(d)` and `String.valueOf(d)` turn into
+// `stdjava.FormatDouble(d)`
+func floatToString(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	argumentNodes := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+	if !StrictMode || len(argumentNodes) != 1 || !isFloatingExpr(argumentNodes[0], ctx, source) {
+		return nil
+	}
+
+	name := node.ChildByFieldName("name").Content(source)
+	switch node.ChildByFieldName("object").Content(source) + "." + name {
+	case "Double.toString", "Float.toString", "String.valueOf":
+		return formatFloating(argumentNodes[0], args[0], ctx, source)
+	// Printing a number prints it the same way that it is converted to a string
+	case "System.out.print", "System.out.println", "System.err.print", "System.err.println":
+		formatted := []ast.Expr{formatFloating(argumentNodes[0], args[0], ctx, source)}
+		if printed := stdlibMethod(node, formatted, ctx, source); printed != nil {
+			return printed
+		}
+		return &ast.CallExpr{
+			Fun:  &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: name}},
+			Args: []ast.Expr{formatFloating(argumentNodes[0], args[0], ctx, source)},
+		}
+	}
+	return nil
+}
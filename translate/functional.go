@@ -0,0 +1,332 @@
+package translate
+
+import (
+	"go/ast"
+
+	"github.com/NickyBoy89/java2go/astutil"
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for the functions that are passed where an
+// object is expected, which can't be converted to it
+const functionalDiagnostic = "functional"
+
+// The functional interfaces of Java's standard library, such as `Function` and
+// `Runnable`, are Go functions, and calling their methods calls the function:
+//
+//	f.apply("b")   ->   f("b")
+//	r.run()        ->   r()
+//
+// A lambda that is passed as one of them takes the types of its function. When
+// a call passes an object that implements a functional interface where a
+// function is expected, the method that implements it is passed instead, the
+// same as for an anonymous class that can't be a function of its own, and a
+// function that is passed where a listener is expected is converted to the
+// listener's function type:
+//
+//	total(new Doubler())          ->   total(NewDoubler().Apply)
+//	later(new Runnable() { ... }) ->   later((&mainRunnable{}).Run)
+//	addClickListener(this::log)   ->   addClickListener(ButtonClickListenerFunc(bn.log))
+//
+// Every other interface has no function type, so the functions that are passed
+// as one are reported
+
+// functionalInterface returns the functional interface from Java's standard
+// library that a Java type is, and the type's type arguments, if it is one
+func functionalInterface(javaType string) (astutil.FunctionalInterface, []string, bool) {
+	base, typeArgs := parseJavaTypeString(javaType)
+	functional, ok := astutil.FunctionalInterfaces[stripJavaQualifier(base)]
+	if !ok || functional.TypeArgs != len(typeArgs) {
+		return astutil.FunctionalInterface{}, nil, false
+	}
+	return functional, typeArgs, true
+}
+
+// functionalSignature returns the Go types of the parameters and the result of
+// the function that a Java type is, if it is a functional interface from Java's
+// standard library. The result is nil if the function doesn't return anything
+func functionalSignature(javaType string, ctx Ctx) ([]ast.Expr, ast.Expr, bool) {
+	functional, typeArgs, ok := functionalInterface(javaType)
+	if !ok {
+		return nil, nil, false
+	}
+	params, result := functional.Signature(typeArgs)
+	paramTypes := make([]ast.Expr, len(params))
+	for ind, param := range params {
+		paramTypes[ind] = elementType(param, true, ctx)
+	}
+	if result == "" {
+		return paramTypes, nil, true
+	}
+	return paramTypes, elementType(result, true, ctx), true
+}
+
+// unboxedJavaType returns the primitive that a wrapper class boxes, or the
+// Java type itself if it isn't one, since the functions of functional
+// interfaces take and return the primitives
+func unboxedJavaType(javaType string) string {
+	goType, ok := boxedTypes[stripJavaQualifier(javaType)]
+	if !ok {
+		return javaType
+	}
+	for primitive, primitiveGoType := range argumentGoTypes {
+		if primitiveGoType == goType {
+			return primitive
+		}
+	}
+	return javaType
+}
+
+// methodReference translates a method reference to a class's method, or to
+// its constructor, into a function with the signature of the functional
+// interface that it is converted to, which calls the method with the
+// function's parameters, or returns nil if the interface or the method isn't
+// known. A static method is called with all of the parameters, and any other
+// method is called on the first of them
+//
+// Ex: `Function<String, Integer> f = Integer::parseInt` turns into
+// `f := func(arg string) int32 { return stdjava.ParseInt(arg, 10) }`
+func methodReference(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	target := functionalTarget(node, source, ctx)
+	functional, typeArgs, ok := functionalInterface(target)
+	if !ok {
+		return nil
+	}
+	paramTypes, resultType, _ := functionalSignature(target, ctx)
+	javaParams, javaResult := functional.Signature(typeArgs)
+
+	names := localVariableNames(ctx).Fork()
+	signature := &ast.FuncType{Params: &ast.FieldList{}}
+	params := make([]ast.Expr, len(paramTypes))
+	for ind, paramType := range paramTypes {
+		params[ind] = &ast.Ident{Name: names.Allocate("arg")}
+		signature.Params.List = append(signature.Params.List, &ast.Field{Names: []*ast.Ident{params[ind].(*ast.Ident)}, Type: paramType})
+	}
+	if resultType != nil {
+		signature.Results = &ast.FieldList{List: []*ast.Field{{Type: resultType}}}
+	}
+
+	className := node.NamedChild(0).Content(source)
+	class := resolveClassScopeByIdentifier(ctx, source, node.NamedChild(0))
+	var call ast.Expr
+	switch {
+	// A constructor, such as `Point::new`, which only has the class
+	case node.NamedChildCount() < 2:
+		if class == nil {
+			return nil
+		}
+		var fun ast.Expr = &ast.Ident{Name: "Construct" + className}
+		if constructor := findMatchingConstructor(class, className, javaParams); constructor != nil {
+			fun = &ast.Ident{Name: constructor.Name}
+		}
+		// A generic class is created with the type arguments of the result
+		if _, resultArgs := parseJavaTypeString(javaResult); len(resultArgs) > 0 {
+			args := make([]ast.Expr, len(resultArgs))
+			for ind, resultArg := range resultArgs {
+				args[ind] = javaTypeStringToGoTypeExpr(resultArg, inScopeTypeParameters(ctx))
+			}
+			fun = applyTypeArguments(fun, args)
+		}
+		call = &ast.CallExpr{Fun: fun, Args: params}
+	case class != nil:
+		name := node.NamedChild(1).Content(source)
+		if method := findInheritedStaticMethod(ctx, class, name, len(params)); method != nil {
+			call = &ast.CallExpr{Fun: &ast.Ident{Name: method.Name}, Args: params}
+			break
+		}
+		for _, method := range class.Methods {
+			if method.OriginalName == name && !method.IsStatic && !method.Constructor && len(method.Parameters) == len(params)-1 {
+				call = &ast.CallExpr{Fun: &ast.SelectorExpr{X: params[0], Sel: &ast.Ident{Name: method.Name}}, Args: params[1:]}
+			}
+		}
+	case resolveInvocationTarget(node.NamedChild(0), ctx, source) == nil:
+		// A class of Java's standard library, whose static methods have mappings
+		argTypes := make([]string, len(javaParams))
+		for ind, javaParam := range javaParams {
+			argTypes[ind] = stripJavaQualifier(unboxedJavaType(javaParam))
+		}
+		call = stdlibCall(className, node.NamedChild(1).Content(source), params, make([]*sitter.Node, len(params)), argTypes, source)
+		if call == nil && len(params) > 0 {
+			_, boxed := paramTypes[0].(*ast.StarExpr)
+			call = unboundLibraryCall(className, node.NamedChild(1).Content(source), params[0], boxed, params[1:])
+		}
+	}
+	if call == nil {
+		return nil
+	}
+
+	body := ast.Stmt(&ast.ExprStmt{X: call})
+	if resultType != nil {
+		body = &ast.ReturnStmt{Results: []ast.Expr{call}}
+	}
+	return &ast.FuncLit{Type: signature, Body: &ast.BlockStmt{List: []ast.Stmt{body}}}
+}
+
+// unboundLibraryCall translates a call of an instance method of String, or of
+// one of the wrapper classes, on the first parameter of a method reference to
+// it, the same as the call would be translated if it was written out, or
+// returns nil if the class isn't one of them. The object is dereferenced first
+// if it is a box
+//
+// Ex: `Function<String, String> upper = String::toUpperCase` turns into
+// `upper := func(arg string) string { return stdjava.ToUpperCase(arg) }`
+func unboundLibraryCall(class, name string, object ast.Expr, boxed bool, args []ast.Expr) ast.Expr {
+	switch {
+	case stripJavaQualifier(class) == "String":
+		if call := stringInstanceMethod(name, object, args); call != nil {
+			return call
+		}
+		return &ast.CallExpr{Fun: &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: name}}, Args: args}
+	case isBoxedType(class) && len(args) == 0:
+		if boxed {
+			object = &ast.StarExpr{X: object}
+		}
+		return unboxingMethod(class, name, object)
+	}
+	return nil
+}
+
+// functionalCall translates a call to the method of a functional interface
+// into a call to the function, or returns nil if the method isn't one
+//
+// Ex: `f.apply("b")` turns into `f("b")`
+func functionalCall(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	javaType, ok := inferExprJavaType(node.ChildByFieldName("object"), ctx, source)
+	if !ok {
+		return nil
+	}
+	functional, _, ok := functionalInterface(javaType)
+	if !ok || node.ChildByFieldName("name").Content(source) != functional.Method || len(args) != len(functional.Params) {
+		return nil
+	}
+	return &ast.CallExpr{Fun: object, Args: args}
+}
+
+// functionalTarget returns the Java type that a lambda or any other expression
+// is converted to, from the parameter that it is passed to, or the variable
+// that it is assigned to, or an empty string if it isn't known
+func functionalTarget(node *sitter.Node, source []byte, ctx Ctx) string {
+	parent := node.Parent()
+	if parent == nil {
+		return ""
+	}
+	switch parent.Type() {
+	case "argument_list":
+		invocation := parent.Parent()
+		if invocation.Type() != "method_invocation" {
+			return ""
+		}
+		class := ctx.currentClass
+		if object := invocation.ChildByFieldName("object"); object != nil {
+			target := resolveInvocationTarget(object, ctx, source)
+			if target == nil {
+				return ""
+			}
+			class = target.classScope
+		}
+		if class == nil {
+			return ""
+		}
+
+		var index int
+		for index < int(parent.NamedChildCount()) && !parent.NamedChild(index).Equal(node) {
+			index++
+		}
+		name := invocation.ChildByFieldName("name").Content(source)
+		for _, method := range class.Methods {
+			if method.OriginalName == name && len(method.Parameters) == int(parent.NamedChildCount()) {
+				return method.Parameters[index].OriginalType
+			}
+		}
+	case "variable_declarator", "return_statement":
+		return ctx.expectedType
+	}
+	return ""
+}
+
+// implementingMethod returns the method of a class that implements the method
+// of a functional interface, or nil if the class doesn't have one
+func implementingMethod(class *symbol.ClassScope, functional astutil.FunctionalInterface) *symbol.Definition {
+	for _, method := range class.Methods {
+		if method.OriginalName == functional.Method && !method.IsStatic && !method.Constructor && len(method.Parameters) == len(functional.Params) {
+			return method
+		}
+	}
+	return nil
+}
+
+// functionalInstance adapts an object of an anonymous class that implements a
+// functional interface from Java's standard library into the function that the
+// interface is, through the method that implements it, or returns the object
+// as it is if the interface isn't one
+//
+// Ex: `new Runnable() { int count; ... }` becomes `(&mainRunnable{}).Run`
+func functionalInstance(body *sitter.Node, javaType string, instance ast.Expr, source []byte, ctx Ctx) ast.Expr {
+	functional, ok := astutil.FunctionalInterfaces[stripJavaQualifier(javaType)]
+	if !ok {
+		return instance
+	}
+	class := symbol.ParseAnonymousClassScope(ctx.className, body, source, inScopeTypeParameters(ctx))
+	method := implementingMethod(class, functional)
+	if method == nil {
+		return instance
+	}
+	return &ast.SelectorExpr{X: &ast.ParenExpr{X: instance}, Sel: &ast.Ident{Name: method.Name}}
+}
+
+// isFunctionExpr determines if an expression evaluates to a Go function, which
+// is either a lambda, a method reference, or a value of a functional interface
+func isFunctionExpr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	node = unwrapParentheses(node)
+	switch node.Type() {
+	case "lambda_expression", "method_reference":
+		return true
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	if !ok {
+		return false
+	}
+	_, _, ok = functionalInterface(javaType)
+	return ok
+}
+
+// adaptArgument adapts an argument of a call to the parameter that it is passed
+// to, where one of them is a function, and the other is an object that
+// implements a functional interface, as described above
+func adaptArgument(node *sitter.Node, argument ast.Expr, source []byte, ctx Ctx) ast.Expr {
+	target := functionalTarget(node, source, ctx)
+	if target == "" {
+		return argument
+	}
+
+	// An object is passed as the function that its method implements
+	if functional, _, ok := functionalInterface(target); ok {
+		javaType, known := inferExprJavaType(node, ctx, source)
+		if !known {
+			return argument
+		}
+		if class := resolveClassScopeByName(ctx, javaType); class != nil {
+			if method := implementingMethod(class, functional); method != nil {
+				return &ast.SelectorExpr{X: argument, Sel: &ast.Ident{Name: method.Name}}
+			}
+		}
+		return argument
+	}
+
+	// A function is passed as an object through a function type that
+	// implements the object's interface
+	class := resolveClassScopeByName(ctx, target)
+	if class == nil || !class.IsInterface || !isFunctionExpr(node, ctx, source) {
+		return argument
+	}
+	if listenerMethod(class) != nil {
+		// A lambda is converted to the listener's function type already
+		if unwrapParentheses(node).Type() == "lambda_expression" {
+			return argument
+		}
+		return &ast.CallExpr{Fun: &ast.Ident{Name: listenerFuncName(class, ctx)}, Args: []ast.Expr{argument}}
+	}
+	ctx.diagnostics.Report(node, functionalDiagnostic, "`%s` is passed as a `%s`, which has no function type to convert it to, since it isn't a listener", node.Content(source), class.Class.OriginalName)
+	return argument
+}
@@ -0,0 +1,152 @@
+package translate
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFunctionalInterfaces(t *testing.T) {
+	ListenerPattern = regexp.MustCompile(`Listener$`)
+	defer func() { ListenerPattern = nil }()
+
+	helper := setupParseHelper(t, `
+package com.shop;
+import java.util.function.*;
+public class Cart {
+    public interface Pricer {
+        int price(String item);
+    }
+    public interface ChangeListener {
+        void changed(String item);
+    }
+    public static class Doubler implements Function<String, Integer> {
+        public Integer apply(String s) { return 2; }
+    }
+    public int total(Pricer pricer, Function<String, Integer> f, Predicate<String> p) {
+        return f.apply("b");
+    }
+    public void listen(ChangeListener l) {}
+    public void log(String s) {}
+    public void use(Pricer pricer, Consumer<String> c, Runnable r) {
+        Doubler d = new Doubler();
+        total(pricer, d, s -> s.isEmpty());
+        total(item -> 2, null, null);
+        listen(this::log);
+        listen(c);
+        BiFunction<Integer, Integer, Integer> add = (a, b) -> a + b;
+        r.run();
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Cart.java")
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helper))
+	for _, expected := range []string{
+		"func (ct *Cart) Total(pricer CartPricer, f func(string) int32, p func(string) bool) int32 { return f(\"b\") }",
+		"func (ct *Cart) Use(pricer CartPricer, c func(string), r func()) {",
+		"total(pricer, d.Apply, func(s string) bool { return s.isEmpty() })",
+		"listen(CartChangeListenerFunc(ct.log))",
+		"listen(CartChangeListenerFunc(c))",
+		"add := func(a int32, b int32) int32 { return a + b }",
+		"r()",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != functionalDiagnostic || items[0].Line != 22 {
+		t.Errorf("Expected the lambda passed as an interface on line 22 to be reported, got %v", items)
+	}
+}
+
+func TestFunctionalAnonymousClasses(t *testing.T) {
+	out := normalizeSpaces(renderGoFileFromJava(t, `
+package com.tasks;
+public class Tasks {
+    static void later(Runnable r) {
+        r.run();
+    }
+    static void use() {
+        later(new Runnable() {
+            int count = 0;
+            public void run() {
+                count++;
+            }
+        });
+    }
+}
+`))
+	// The class has a field, so it can't be a function of its own
+	if !strings.Contains(out, "later((&tasksRunnable{count: 0}).Run)") {
+		t.Errorf("Expected the anonymous class to be passed as its method, got:\n%s", out)
+	}
+}
+
+func TestMethodReferences(t *testing.T) {
+	out := normalizeSpaces(renderGoFileFromJava(t, `
+package com.refs;
+import java.util.function.*;
+public class Parser {
+    static int twice(int x) { return x * 2; }
+    String name;
+    String getName() { return name; }
+    public int parse(Function<String, Integer> parser) { return parser.apply("1"); }
+    void run() {
+        Function<String, Integer> f = Integer::parseInt;
+        Function<Integer, Integer> g = Parser::twice;
+        Function<Parser, String> n = Parser::getName;
+        Supplier<Parser> mk = Parser::new;
+        BiFunction<Double, Double, Double> p = Math::pow;
+        parse(Integer::parseInt);
+        Supplier<String> s = this::getName;
+    }
+}
+`))
+	for _, expected := range []string{
+		"f := func(arg string) int32 { return stdjava.ParseInt(arg, 10) }",
+		"g := func(arg int32) int32 { return twice(arg) }",
+		"n := func(arg *Parser) string { return arg.getName() }",
+		"mk := func() *Parser { return ConstructParser() }",
+		"p := func(arg float64, arg2 float64) float64 { return math.Pow(arg, arg2) }",
+		"parse(func(arg string) int32 { return stdjava.ParseInt(arg, 10) })",
+		// A method of an object is already a function of its own
+		"s := pr.getName",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestUnboundLibraryMethodReferences(t *testing.T) {
+	StrictMode = true
+	defer func() { StrictMode = false }()
+
+	out := normalizeSpaces(renderGoFileFromJava(t, `
+package com.refs;
+import java.util.function.*;
+public class Words {
+    void run(Integer count) {
+        Function<String, Integer> length = String::length;
+        Function<String, String> upper = String::toUpperCase;
+        Function<Integer, Integer> value = Integer::intValue;
+        Function<Integer, Long> wide = Integer::longValue;
+        long total = count.longValue();
+    }
+}
+`))
+	for _, expected := range []string{
+		"length := func(arg string) int32 { return stdjava.Length(arg) }",
+		"upper := func(arg string) string { return stdjava.ToUpperCase(arg) }",
+		"value := func(arg int32) int32 { return arg }",
+		"wide := func(arg int32) int64 { return int64(arg) }",
+		"total := int64(*count)",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
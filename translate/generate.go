@@ -0,0 +1,35 @@
+package translate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/NickyBoy89/java2go/codegen"
+	"github.com/NickyBoy89/java2go/symbol"
+)
+
+// ReceiverName returns the name of the receiver of the current class's methods,
+// which is allocated when the class is resolved, so that it doesn't collide with
+// anything else. Without symbols, this is the ShortName of the class
+func ReceiverName(ctx Ctx) string {
+	if ctx.currentClass != nil && ctx.currentClass.ReceiverName != "" && ctx.currentClass.Class.Name == ctx.className {
+		return ctx.currentClass.ReceiverName
+	}
+	return codegen.ShortName(ctx.className)
+}
+
+// fieldTag generates the tag for a field of a struct, which is only used to
+// serialize the struct to JSON, under the field's name in the Java source
+//
+// Transient fields aren't serialized by Java, so they are left out of the JSON
+func fieldTag(field *symbol.Definition, transient bool) *ast.BasicLit {
+	if !JSONTags {
+		return nil
+	}
+	name := field.OriginalName
+	if transient {
+		name = "-"
+	}
+	return &ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("`json:%q`", name)}
+}
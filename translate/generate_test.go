@@ -0,0 +1,46 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFieldTags_JSON(t *testing.T) {
+	JSONTags = true
+	defer func() { JSONTags = false }()
+
+	out := renderGoFileFromJava(t, `
+package tags.json;
+public class Person {
+    private String name;
+    public int age;
+    private transient String cache;
+    static int count;
+}
+`)
+	for _, want := range []string{
+		"name\tstring\t`json:\"name\"`",
+		"Age\tint32\t`json:\"age\"`",
+		"cache\tstring\t`json:\"-\"`",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, out)
+		}
+	}
+	// Static fields are package-level variables instead
+	if !strings.Contains(out, "var count int32") || strings.Contains(out, "json:\"count\"") {
+		t.Errorf("Expected the static field to be left out of the struct, got:\n%s", out)
+	}
+}
+
+func TestFieldTags_Disabled(t *testing.T) {
+	out := renderGoFileFromJava(t, `
+package tags.disabled;
+public class Person {
+    private transient String cache;
+}
+`)
+	if strings.Contains(out, "json:") {
+		t.Errorf("Expected no tags without the JSON option, got:\n%s", out)
+	}
+}
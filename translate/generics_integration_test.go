@@ -0,0 +1,343 @@
+package translate
+
+import (
+	"bytes"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func renderGoFileFromJava(t *testing.T, src string) string {
+	t.Helper()
+	return renderGoFileFromJavaCtx(t, setupParseHelper(t, src))
+}
+
+// renderGoFileFromJavaCtx renders an already set up file, so that its context
+// can be changed before it is parsed
+func renderGoFileFromJavaCtx(t *testing.T, helper *ParseHelper) string {
+	t.Helper()
+	node := ParseNode(helper.File.Ast, helper.File.Source, helper.Ctx)
+	file, ok := node.(*ast.File)
+	if !ok {
+		t.Fatalf("Expected *ast.File, got %T", node)
+	}
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), file); err != nil {
+		t.Fatalf("Failed to print AST: %v", err)
+	}
+	return buf.String()
+}
+
+func normalizeSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func TestGenericsIntegration_GenericClassAndNestedTypes(t *testing.T) {
+	src := `
+package gen.integration;
+public class Pair<K extends Number, V> {
+    K key;
+    V value;
+    public Pair(K k, V v) {
+        this.key = k;
+        this.value = v;
+    }
+    public K getKey() { return this.key; }
+    public V getValue() { return this.value; }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, "type Pair[K any, V any] struct") {
+		t.Errorf("Expected generic struct with 2 type params, got:\n%s", out)
+	}
+	flat := normalizeSpaces(out)
+	if !strings.Contains(flat, "key K") || !strings.Contains(flat, "value V") {
+		t.Errorf("Expected fields to use type params K/V, got:\n%s", out)
+	}
+	if !strings.Contains(out, "func NewPair[K any, V any]") {
+		t.Errorf("Expected generic constructor function with type params, got:\n%s", out)
+	}
+	if !strings.Contains(out, "func (pr *Pair[K, V]) GetKey()") {
+		t.Errorf("Expected method receiver to use instantiated type params, got:\n%s", out)
+	}
+}
+
+func TestGenericsIntegration_NestedGenericTypeExpressions(t *testing.T) {
+	src := `
+package gen.integration2;
+import java.util.List;
+import java.util.Map;
+public class Container {
+    Map<String, List<Integer>> m;
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, "m map[string]*List[*int32]") {
+		t.Errorf("Expected nested generic field type 'map[string]*List[*int32]', got:\n%s", out)
+	}
+}
+
+func TestGenericsIntegration_DiamondExplicitAndRawConstructors(t *testing.T) {
+	src := `
+package gen.integration3;
+public class Box<T> {
+    T value;
+    public Box() {}
+    public static void test() {
+        Box<String> inferred = new Box<>();
+        Box<Integer> explicit = new Box<Integer>();
+        Box raw = new Box();
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, "NewBox[string]") {
+		t.Errorf("Expected diamond operator to infer 'string' type arg, got:\n%s", out)
+	}
+	if !strings.Contains(out, "NewBox[*int32]") && !strings.Contains(out, "NewBox[Integer]") {
+		t.Errorf("Expected explicit type args on constructor call, got:\n%s", out)
+	}
+	if strings.Contains(out, "raw := NewBox[") || strings.Contains(out, "raw = NewBox[") {
+		t.Errorf("Expected raw 'new Box()' to omit type args, got:\n%s", out)
+	}
+}
+
+func TestGenericsIntegration_InstanceGenericMethodHelper_EndToEnd(t *testing.T) {
+	src := `
+package gen.integration4;
+public class Box<T> {
+    public <R> R identity(R value) { return value; }
+
+    public static Foo callFoo(Box<Foo> box, Foo value) {
+        return box.identity(value);
+    }
+
+    public static <X> X callGeneric(Box<X> box, X value) {
+        return box.identity(value);
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, "type BoxIdentityHelper") || !strings.Contains(out, "func NewBoxIdentityHelper") {
+		t.Errorf("Expected helper type + constructor for instance generic method, got:\n%s", out)
+	}
+	if !strings.Contains(out, "NewBoxIdentityHelper[*Foo, *Foo]") && !strings.Contains(out, "NewBoxIdentityHelper[*Foo,*Foo]") {
+		t.Errorf("Expected helper call for concrete Foo to use pointer type args, got:\n%s", out)
+	}
+	if !strings.Contains(out, "NewBoxIdentityHelper[X, X]") && !strings.Contains(out, "NewBoxIdentityHelper[X,X]") {
+		t.Errorf("Expected helper call for generic X to use type param args, got:\n%s", out)
+	}
+}
+
+func TestGenericsIntegration_InstanceGenericMethodHelpersAreShared(t *testing.T) {
+	src := `
+package gen.integration4b;
+public class Box<T> {
+    public <R> R identity(R value) { return value; }
+    public <U> U fold(U initial) { return initial; }
+    public <A, B> A pick(A a, B b) { return a; }
+
+    public static Foo callFoo(Box<Foo> box, Foo value) {
+        return box.fold(value);
+    }
+}
+`
+	helper := setupParseHelper(t, src)
+	helper.Ctx.helpers = newHelperRegistry()
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helper))
+	for _, expected := range []string{
+		"type BoxHelper[T any, R any] struct",
+		"func (bxHelper *BoxHelper[T, R]) Identity(value R) R",
+		"func (bxHelper *BoxHelper[T, U]) Fold(initial U) U",
+		"type BoxPickHelper[T any, A any, B any] struct",
+		"NewBoxHelper[*Foo, *Foo](box).Fold(value)",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+	if count := strings.Count(out, "func NewBoxHelper"); count != 1 {
+		t.Errorf("Expected the shared helper to be declared once, got %d declarations:\n%s", count, out)
+	}
+	if helpers, methods := helper.Ctx.helpers.Helpers(), helper.Ctx.helpers.Methods(); helpers != 2 || methods != 3 {
+		t.Errorf("Expected 2 helpers with 3 methods, got %d helpers with %d methods", helpers, methods)
+	}
+}
+
+func TestGenericsIntegration_ExplicitTypeArgumentsOnGenericFunctionCall(t *testing.T) {
+	src := `
+package gen.integration5;
+public class Utils {
+    static <T> T id(T value) { return value; }
+
+    public static void test() {
+        Foo f = null;
+        Foo g = Utils.<Foo>id(f);
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, "func id[T any]") {
+		t.Errorf("Expected generic function declaration for id, got:\n%s", out)
+	}
+	if !strings.Contains(out, "id[*Foo]") && !strings.Contains(out, "id[Foo]") {
+		t.Errorf("Expected explicit type args to be applied at call site, got:\n%s", out)
+	}
+}
+
+func TestGenericsIntegration_NestedClassTypeParameters(t *testing.T) {
+	src := `
+package gen.integration6;
+public class Outer<T> {
+    public class Inner<U> {
+        T t;
+        U u;
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, "type Outer[T any] struct") {
+		t.Errorf("Expected Outer to be generic, got:\n%s", out)
+	}
+	if !strings.Contains(out, "type OuterInner[T any, U any] struct") {
+		t.Errorf("Expected Inner to inherit parent type params and add its own, got:\n%s", out)
+	}
+}
+
+func TestGenericsIntegration_NestedEnumIgnoresParentTypeParameters(t *testing.T) {
+	src := `
+package gen.integration7;
+public class Outer<T> {
+    T value;
+    public enum Kind {
+        A, B;
+        public Kind other() { return this == A ? B : A; }
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, "type Outer[T any] struct") {
+		t.Errorf("Expected Outer to be generic, got:\n%s", out)
+	}
+	if strings.Contains(out, "OuterKind[") {
+		t.Errorf("Expected the nested enum to not inherit the parent's type params, got:\n%s", out)
+	}
+	if !strings.Contains(out, "func (od OuterKind) Other()") {
+		t.Errorf("Expected the enum's methods to have a plain receiver, got:\n%s", out)
+	}
+}
+
+func TestGenericsIntegration_NestedClassMethodsUseParentTypeParameters(t *testing.T) {
+	src := `
+package gen.integration8;
+public class Outer<T> {
+    public class Inner<U> {
+        public T get(U key, T fallback) {
+            java.util.List<T> values = null;
+            U copy = (U) key;
+            return fallback;
+        }
+    }
+    public Inner<String> make() { return new Inner<String>(); }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, "func (our *OuterInner[T, U]) Get(key U, fallback T) T") {
+		t.Errorf("Expected the inner method to use the merged type params, got:\n%s", out)
+	}
+	if !strings.Contains(out, "var values *java.util.List[T] = nil") {
+		t.Errorf("Expected the local variable to use the parent's type param, got:\n%s", out)
+	}
+	if !strings.Contains(out, "copy := key.(U)") {
+		t.Errorf("Expected the cast to use the inner type param, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Make() *OuterInner[T, string]") {
+		t.Errorf("Expected the return type to include the parent's type params, got:\n%s", out)
+	}
+	if !strings.Contains(out, "[T, string]()") {
+		t.Errorf("Expected the construction to include the parent's type params, got:\n%s", out)
+	}
+}
+
+func TestGenericsIntegration_StaticFactoryTargetTyping(t *testing.T) {
+	src := `
+package gen.integration9;
+import java.util.List;
+public class Box<T> {
+    T value;
+    public static <T> Box<T> empty() { return new Box<>(); }
+    public static <T> Box<T> of(T value) { return null; }
+    public static void main(String[] args) {
+        Box<String> empty = Box.empty();
+        Box<String> full = Box.of("x");
+        List<Integer> none = List.of();
+        List<Integer> some = List.of(1, 2);
+    }
+    static Box<Integer> make() { return empty(); }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	for _, want := range []string{
+		"return ConstructBox[T]()",
+		"empty := Empty[string]()",
+		`full := Of("x")`,
+		"none := List.of[*int32]()",
+		"some := List.of(1, 2)",
+		"return Empty[*int32]()",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenericsIntegration_ArrayInitializerDiamond(t *testing.T) {
+	src := `
+package gen.integration10;
+public class Box<T> {
+    static void fill() {
+        Box<String>[] created = new Box[]{ new Box<>(), new Box<>() };
+        Box<Integer>[][] nested = { { new Box<>() }, {} };
+        int[][] numbers = new int[][]{{1, 2}, {3}};
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	for _, want := range []string{
+		"{ConstructBox[string](), ConstructBox[string]()}",
+		"nested := [][]*Box[*int32]{[]*Box[*int32]{ConstructBox[*int32]()}, []*Box[*int32]{}}",
+		"numbers := [][]int32{[]int32{1, 2}, []int32{3}}",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenericsIntegration_ExtendedGenericInterfaces(t *testing.T) {
+	src := `
+package gen.interfaces;
+public interface StringStream extends Stream<String>, Comparable<StringStream> {
+    String first();
+
+    interface Stream<T> {
+        T next();
+    }
+    interface Pair<K, V> extends Stream<Map<K, V>> {}
+    interface IntPair extends Pair<Integer, int[]> {}
+}
+`
+	out := normalizeSpaces(renderGoFileFromJava(t, src))
+	for _, expected := range []string{
+		"type StringStream interface { StringStreamstream[string] First() string }",
+		"type StringStreamstream[T any] interface { Next() T }",
+		"type StringStreampair[K any, V any] interface { StringStreamstream[map[K]V] }",
+		"type StringStreamintPair interface { StringStreampair[int32, []int32] }",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
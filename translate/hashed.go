@@ -0,0 +1,161 @@
+package translate
+
+import (
+	"go/ast"
+
+	"github.com/NickyBoy89/java2go/astutil"
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for the parts of the maps and sets of
+// hashed objects that the runtime doesn't implement
+const hashedDiagnostic = "hashed-collection"
+
+// A `HashMap` or a `HashSet` of objects that declare their own `equals` and
+// `hashCode` finds its keys with them, which a Go map can't do, since it
+// compares the pointers to the objects. These are translated into the
+// runtime's `ObjectMap` and `ObjectSet` instead, whose methods have the same
+// names as the sorted collections, and are called the same way:
+//
+//	Map<Point, String> names = new HashMap<>();    names := stdjava.NewObjectMap[*Point, string]()
+//	names.put(new Point(1, 2), "a");               names.Put(NewPoint(1, 2), "a")
+//	for (Point p : names.keySet()) { }             for _, p := range names.KeySet() { }
+
+// The methods of the hashed collections that the runtime implements, and their
+// names in the runtime
+var hashedMethods = map[string]map[string]string{
+	"ObjectMap": {
+		"put":          "Put",
+		"putIfAbsent":  "PutIfAbsent",
+		"get":          "Get",
+		"getOrDefault": "GetOrDefault",
+		"containsKey":  "ContainsKey",
+		"remove":       "Remove",
+		"size":         "Size",
+		"isEmpty":      "IsEmpty",
+		"clear":        "Clear",
+		"keySet":       "KeySet",
+		"values":       "Values",
+		"entrySet":     "EntrySet",
+		"putAll":       "PutAll",
+	},
+	"ObjectSet": {
+		"add":         "Add",
+		"remove":      "Remove",
+		"contains":    "Contains",
+		"size":        "Size",
+		"isEmpty":     "IsEmpty",
+		"clear":       "Clear",
+		"addAll":      "AddAll",
+		"removeAll":   "RemoveAll",
+		"retainAll":   "RetainAll",
+		"containsAll": "ContainsAll",
+	},
+}
+
+// hashedClassOf returns which of the runtime's hashed collections a Java type
+// is represented as, or an empty string if it isn't one of them
+func hashedClassOf(javaType string) string {
+	return hashedClassFor(parseJavaTypeString(javaType))
+}
+
+// hashedClassFor returns which of the runtime's hashed collections a Java
+// class with the given type arguments is represented as, or an empty string
+// if it isn't one of them
+func hashedClassFor(base string, typeArgs []string) string {
+	switch stripJavaQualifier(base) {
+	case "Map", "HashMap":
+		if len(typeArgs) == 2 && astutil.IsHashedClass(typeArgs[0]) {
+			return "ObjectMap"
+		}
+	case "Set", "HashSet":
+		if len(typeArgs) == 1 && astutil.IsHashedClass(typeArgs[0]) {
+			return "ObjectSet"
+		}
+	}
+	return ""
+}
+
+// hashedClass returns which of the runtime's hashed collections an expression
+// evaluates to, or an empty string if it isn't one of them
+func hashedClass(node *sitter.Node, ctx Ctx, source []byte) string {
+	if node.Type() == "parenthesized_expression" {
+		return hashedClass(node.NamedChild(0), ctx, source)
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	if !ok {
+		return ""
+	}
+	return hashedClassOf(javaType)
+}
+
+// hashedMethod translates a method of a hashed collection, or returns nil if
+// the method isn't called on one of them
+//
+// Ex: `names.get(p)` turns into `names.Get(p)`
+func hashedMethod(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	objectNode := node.ChildByFieldName("object")
+	class := hashedClass(objectNode, ctx, source)
+	if class == "" {
+		return nil
+	}
+	name := node.ChildByFieldName("name").Content(source)
+	method, ok := hashedMethods[class][name]
+	// The methods that take another collection only take one of the same class
+	arguments := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+	if ok && (name == "putAll" || name == "addAll" || name == "removeAll" || name == "retainAll" || name == "containsAll") {
+		ok = len(arguments) == 1 && hashedClass(arguments[0], ctx, source) == class
+	}
+	if !ok {
+		ctx.diagnostics.Report(node, hashedDiagnostic, "`%s` isn't implemented by the runtime's `%s`", node.Content(source), class)
+		return nil
+	}
+
+	// The values that are passed to a map are the map's own
+	if javaType, ok := inferExprJavaType(objectNode, ctx, source); ok && class == "ObjectMap" {
+		_, typeArgs := parseJavaTypeString(javaType)
+		if (name == "put" || name == "putIfAbsent" || name == "getOrDefault") && len(args) == 2 {
+			args[1] = elementValue(arguments[1], args[1], typeArgs[1], false, ctx, source)
+		}
+	}
+	return &ast.CallExpr{Fun: &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: method}}, Args: args}
+}
+
+// newHashedCollection translates the creation of a hashed collection, with
+// the type arguments that it is created with, or returns nil if the created
+// object isn't one of them
+//
+// Ex: `new HashMap<>()` turns into `stdjava.NewObjectMap[*Point, string]()`,
+// and `new HashSet<>(points)` turns into `points.Clone()`
+func newHashedCollection(node *sitter.Node, className string, typeArgs []string, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	class := hashedClassFor(className, typeArgs)
+	if class == "" || anonymousClassBody(node) != nil ||
+		stripJavaQualifier(className) != "HashMap" && stripJavaQualifier(className) != "HashSet" {
+		return nil
+	}
+
+	typeArgExprs := make([]ast.Expr, len(typeArgs))
+	for ind, typeArg := range typeArgs {
+		typeArgExprs[ind] = elementType(typeArg, ind == 0, ctx)
+	}
+	created := &ast.CallExpr{Fun: applyTypeArguments(&ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: "New" + class}}, typeArgExprs)}
+
+	arguments := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+	switch {
+	case len(args) == 0 || isCapacity(arguments[0], ctx, source):
+		// The initial capacity of the collection is ignored
+		return created
+	case hashedClass(arguments[0], ctx, source) == class:
+		return &ast.CallExpr{Fun: &ast.SelectorExpr{X: args[0], Sel: &ast.Ident{Name: "Clone"}}}
+	case class == "ObjectSet" && listedElements(arguments[0], source) != nil:
+		var spread bool
+		created.Args, spread = collectionElements(arguments[0], args[0], typeArgs[0], ctx, source)
+		if spread {
+			created.Ellipsis = 1
+		}
+		return created
+	}
+	ctx.diagnostics.Report(node, hashedDiagnostic, "`%s` isn't implemented by the runtime's `%s`", node.Content(source), class)
+	return nil
+}
@@ -0,0 +1,72 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashedCollections(t *testing.T) {
+	helper := setupParseHelper(t, `
+package util.hashed;
+import java.util.*;
+public class Cell {
+    int row, col;
+    @Override
+    public boolean equals(Object other) {
+        if (!(other instanceof Cell)) {
+            return false;
+        }
+        Cell that = (Cell) other;
+        return row == that.row && col == that.col;
+    }
+    @Override
+    public int hashCode() {
+        return Objects.hash(row, col);
+    }
+    public static int visit(List<Cell> cells) {
+        Map<Cell, Integer> visits = new HashMap<>();
+        Set<Cell> seen = new HashSet<>();
+        for (Cell cell : cells) {
+            visits.put(cell, visits.getOrDefault(cell, 0) + 1);
+            seen.add(cell);
+        }
+        for (Cell cell : seen) {
+            visits.remove(cell);
+        }
+        Map<String, Integer> names = new HashMap<>();
+        return visits.size() + seen.size() + names.size();
+    }
+    public static Set<Cell> copy(Set<Cell> cells) {
+        cells.stream();
+        return new HashSet<>(cells);
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Cell.java")
+
+	out := renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		"func (cl *Cell) Equals(other any) bool {",
+		"func (cl *Cell) HashCode() int32 {",
+		"return stdjava.Hash(row, col)",
+		"visits := stdjava.NewObjectMap[*Cell, int32]()",
+		"seen := stdjava.NewObjectSet[*Cell]()",
+		"visits.Put(cell, visits.GetOrDefault(cell, 0)+1)",
+		"seen.Add(cell)",
+		"for _, cell := range seen.Elements() {",
+		"visits.Remove(cell)",
+		"names := make(map[string]int32)",
+		"return visits.Size() + seen.Size() + int32(len(names))",
+		"func Copy(cells *stdjava.ObjectSet[*Cell]) *stdjava.ObjectSet[*Cell] {",
+		"return cells.Clone()",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != hashedDiagnostic || items[0].Line != 32 {
+		t.Errorf("Expected the unimplemented method on line 32 to be reported, got %v", items)
+	}
+}
@@ -0,0 +1,80 @@
+package translate
+
+import (
+	"strconv"
+
+	"github.com/NickyBoy89/java2go/symbol"
+)
+
+// The generic methods of a class that can be overridden are methods of helper
+// types, since Go methods can't declare type parameters of their own. Every
+// method with the same number of type parameters shares one helper, which
+// names the type parameters of each method itself:
+//
+//	type BoxHelper[T any, R any] struct {
+//		recv *Box[T]
+//	}
+//
+//	func (bxHelper *BoxHelper[T, R]) Map(fn func(T) R) *Box[R] { ... }
+//	func (bxHelper *BoxHelper[T, U]) Fold(initial U) U { ... }
+//
+// A method that is the only one with its number of type parameters keeps a
+// helper of its own, which is named after it
+
+// helperMethods returns the methods of a class that share a helper with the
+// given method, including the method itself
+func helperMethods(class *symbol.ClassScope, method *symbol.Definition) []*symbol.Definition {
+	return class.FindMethod().By(func(d *symbol.Definition) bool {
+		return d.RequiresHelper && class.CanBeOverridden(d) && len(d.TypeParameters) == len(method.TypeParameters)
+	})
+}
+
+// allocateHelperName names the helper type of a generic method that can be
+// overridden, and reserves the name of its constructor
+func allocateHelperName(class *symbol.ClassScope, method *symbol.Definition, names *symbol.NameAllocator) string {
+	key := class.Class.Name + method.Name + "Helper"
+	name := key
+	if len(helperMethods(class, method)) > 1 {
+		key = class.Class.Name + "Helper" + strconv.Itoa(len(method.TypeParameters))
+		name = class.Class.Name + "Helper"
+	}
+	helperName := names.AllocateFor(key, name)
+	names.Reserve("New" + helperName)
+	return helperName
+}
+
+// A helperRegistry keeps track of the helper types that are declared in a
+// file, so that the methods that share a helper declare it only once
+type helperRegistry struct {
+	declared map[string]bool
+	// The number of methods that are declared on the helpers
+	methods int
+}
+
+func newHelperRegistry() *helperRegistry {
+	return &helperRegistry{declared: make(map[string]bool)}
+}
+
+// declare records a method of the given helper, and determines if the helper
+// still has to be declared, which is only the case for its first method
+func (hr *helperRegistry) declare(helperName string) bool {
+	if hr == nil {
+		return true
+	}
+	hr.methods++
+	if hr.declared[helperName] {
+		return false
+	}
+	hr.declared[helperName] = true
+	return true
+}
+
+// Helpers returns the number of helper types that are declared in the file
+func (hr *helperRegistry) Helpers() int {
+	return len(hr.declared)
+}
+
+// Methods returns the number of methods that are declared on the helpers
+func (hr *helperRegistry) Methods() int {
+	return hr.methods
+}
@@ -0,0 +1,174 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for the parts of Java's HTTP clients that
+// the runtime doesn't implement
+const httpDiagnostic = "http"
+
+// The methods of Java's HTTP classes that the runtime implements, and their
+// names in the runtime
+var httpMethods = map[string]map[string]string{
+	"HttpClient": {"send": "Send"},
+	"HttpRequest.Builder": {
+		"uri":       "URI",
+		"header":    "Header",
+		"setHeader": "SetHeader",
+		"method":    "Method",
+		"GET":       "GET",
+		"POST":      "POST",
+		"PUT":       "PUT",
+		"DELETE":    "DELETE",
+		"build":     "Build",
+	},
+	"HttpResponse": {"statusCode": "StatusCode", "body": "Body"},
+	"HttpURLConnection": {
+		"setRequestMethod":   "SetRequestMethod",
+		"setRequestProperty": "SetRequestProperty",
+		"addRequestProperty": "AddRequestProperty",
+		"setDoOutput":        "SetDoOutput",
+		"setConnectTimeout":  "SetConnectTimeout",
+		"setReadTimeout":     "SetReadTimeout",
+		"getOutputStream":    "GetOutputStream",
+		"connect":            "Connect",
+		"getResponseCode":    "GetResponseCode",
+		"getHeaderField":     "GetHeaderField",
+		"getInputStream":     "GetInputStream",
+		"getErrorStream":     "GetErrorStream",
+		"disconnect":         "Disconnect",
+	},
+}
+
+// httpClass returns which of Java's HTTP classes an expression evaluates to,
+// such as "HttpRequest.Builder", or an empty string if it isn't one of them
+func httpClass(node *sitter.Node, ctx Ctx, source []byte) string {
+	switch node.Type() {
+	case "parenthesized_expression":
+		return httpClass(node.NamedChild(0), ctx, source)
+	// Ex: `(HttpURLConnection) url.openConnection()`
+	case "cast_expression":
+		return httpClassOf(node.ChildByFieldName("type").Content(source))
+	case "method_invocation":
+		object := node.ChildByFieldName("object")
+		if object == nil {
+			break
+		}
+		switch name := node.ChildByFieldName("name").Content(source); {
+		case object.Content(source) == "HttpClient" && name == "newHttpClient":
+			return "HttpClient"
+		case object.Content(source) == "HttpRequest" && name == "newBuilder":
+			return "HttpRequest.Builder"
+		case name == "openConnection":
+			return "HttpURLConnection"
+		case httpClass(object, ctx, source) == "HttpRequest.Builder":
+			if name == "build" {
+				return "HttpRequest"
+			}
+			return "HttpRequest.Builder"
+		case httpClass(object, ctx, source) == "HttpClient" && name == "send":
+			return "HttpResponse"
+		}
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	if !ok {
+		return ""
+	}
+	return httpClassOf(javaType)
+}
+
+// httpClassOf returns which of Java's HTTP classes a Java type is, or an empty
+// string if it isn't one of them
+func httpClassOf(javaType string) string {
+	base, _ := parseJavaTypeString(javaType)
+	if base == "HttpRequest.Builder" || strings.HasSuffix(base, ".HttpRequest.Builder") {
+		return "HttpRequest.Builder"
+	}
+	switch class := stripJavaQualifier(base); class {
+	case "HttpClient", "HttpRequest", "HttpResponse", "HttpURLConnection":
+		return class
+	case "HttpsURLConnection":
+		return "HttpURLConnection"
+	}
+	return ""
+}
+
+// isURLExpr determines if the given expression evaluates to a `URL` or a
+// `URI`, which are strings in Go
+func isURLExpr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	if node.Type() == "object_creation_expression" {
+		switch node.ChildByFieldName("type").Content(source) {
+		case "URL", "URI":
+			return true
+		}
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	return ok && (stripJavaQualifier(javaType) == "URL" || stripJavaQualifier(javaType) == "URI")
+}
+
+// httpMethod translates the parts of Java's `HttpClient`, and of the legacy
+// `HttpURLConnection`, that the runtime implements, or returns nil if the
+// method isn't part of them
+//
+// Requests and responses always have a string as their body, so the handlers
+// and publishers of their bodies are left out
+//
+// Ex: `client.send(request, HttpResponse.BodyHandlers.ofString())` turns into
+// `client.Send(request)`
+func httpMethod(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	objectNode := node.ChildByFieldName("object")
+	name := node.ChildByFieldName("name").Content(source)
+
+	switch objectNode.Content(source) + "." + name {
+	case "HttpClient.newHttpClient":
+		return runtimeCall("NewHttpClient")
+	case "HttpRequest.newBuilder":
+		return runtimeCall("NewHttpRequestBuilder", args...)
+	case "URI.create":
+		if len(args) == 1 {
+			return args[0]
+		}
+	}
+
+	switch {
+	// Ex: `HttpRequest.BodyPublishers.ofString(json)` turns into `json`
+	case strings.HasSuffix(objectNode.Content(source), "BodyPublishers"):
+		switch {
+		case name == "ofString" && len(args) == 1:
+			return args[0]
+		case name == "noBody":
+			return &ast.BasicLit{Kind: token.STRING, Value: `""`}
+		}
+	case name == "openConnection" && len(args) == 0 && isURLExpr(objectNode, ctx, source):
+		return runtimeCall("OpenConnection", object)
+	}
+
+	class := httpClass(objectNode, ctx, source)
+	if class == "" {
+		return nil
+	}
+	method, ok := httpMethods[class][name]
+	if !ok {
+		ctx.diagnostics.Report(node, httpDiagnostic, "`%s` isn't implemented by the runtime's `%s`", node.Content(source), class)
+		return nil
+	}
+	// The body of a response is always read as a string
+	if method == "Send" {
+		args = args[:min(len(args), 1)]
+	}
+	return &ast.CallExpr{Fun: &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: method}}, Args: args}
+}
+
+// newURL translates the creation of a `URL` or a `URI` from a string, which is
+// the string itself in Go, or returns nil if the created object is neither
+func newURL(className string, argumentNodes []*sitter.Node, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	if (className == "URL" || className == "URI") && len(args) == 1 && isStringExpr(argumentNodes[0], ctx, source) {
+		return args[0]
+	}
+	return nil
+}
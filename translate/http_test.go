@@ -0,0 +1,57 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHttpRequests(t *testing.T) {
+	helper := setupParseHelper(t, `
+package web.client;
+import java.net.*;
+import java.net.http.*;
+public class Client {
+    public static String fetch(String target) throws Exception {
+        HttpClient client = HttpClient.newHttpClient();
+        HttpRequest request = HttpRequest.newBuilder()
+            .uri(URI.create(target))
+            .header("Accept", "application/json")
+            .POST(HttpRequest.BodyPublishers.ofString("{}"))
+            .build();
+        HttpResponse<String> response = client.send(request, HttpResponse.BodyHandlers.ofString());
+        client.sendAsync(request, HttpResponse.BodyHandlers.ofString());
+        return response.body();
+    }
+    public static int legacy(String target) throws Exception {
+        URL url = new URL(target);
+        HttpURLConnection conn = (HttpURLConnection) url.openConnection();
+        conn.setRequestMethod("GET");
+        String type = conn.getHeaderField("Content-Type");
+        return conn.getResponseCode();
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Client.java")
+
+	out := renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		"client := stdjava.NewHttpClient()",
+		`request := stdjava.NewHttpRequestBuilder().URI(target).Header("Accept", "application/json").POST("{}").Build()`,
+		"response := client.Send(request)",
+		"return response.Body()",
+		"url := target",
+		"conn := stdjava.OpenConnection(url)",
+		`conn.SetRequestMethod("GET")`,
+		`type_ := conn.GetHeaderField("Content-Type")`,
+		"return conn.GetResponseCode()",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != httpDiagnostic || items[0].Line != 14 {
+		t.Errorf("Expected the asynchronous request on line 14 to be reported, got %v", items)
+	}
+}
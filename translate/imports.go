@@ -0,0 +1,148 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// runtimeImportPath is the import path of the runtime package, which has the
+// Go implementations of the parts of Java that the generated code calls into
+const runtimeImportPath = "github.com/NickyBoy89/java2go/stdjava"
+
+// generatedImports are the packages that the generated code refers to, by the
+// name that the code refers to them with
+var generatedImports = map[string]string{
+	"stdjava": runtimeImportPath,
+	"atomic":  "sync/atomic",
+	"base64":  "encoding/base64",
+	"fmt":     "fmt",
+	"md5":     "crypto/md5",
+	"sha1":    "crypto/sha1",
+	"sha256":  "crypto/sha256",
+	"sha512":  "crypto/sha512",
+	"hash":    "hash",
+	"crc32":   "hash/crc32",
+	"adler32": "hash/adler32",
+	"io":      "io",
+	"iter":    "iter",
+	"maps":    "maps",
+	"math":    "math",
+	"os":      "os",
+	"reflect": "reflect",
+	"slices":  "slices",
+	"strconv": "strconv",
+	"strings": "strings",
+	"sync":    "sync",
+	"time":    "time",
+}
+
+// qualifiedTypePattern matches the packages that a type refers to
+var qualifiedTypePattern = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\.`)
+
+// importPackages imports every package that a generated file refers to,
+// replacing the imports that it had before, so that this can be done again
+// after the file has been changed
+func importPackages(file *ast.File) {
+	if len(file.Decls) > 0 {
+		if decl, ok := file.Decls[0].(*ast.GenDecl); ok && decl.Tok == token.IMPORT {
+			file.Decls = file.Decls[1:]
+		}
+	}
+	if imports := importDecl(usedPackages(file)); imports != nil {
+		file.Decls = append([]ast.Decl{imports}, file.Decls...)
+	}
+}
+
+// usedPackages returns the import paths of the packages that generated code
+// refers to
+func usedPackages(code ast.Node) map[string]bool {
+	// A variable with the same name as a package, such as a `hash`, refers to
+	// the variable instead
+	declared := make(map[string]bool)
+	ast.Inspect(code, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.AssignStmt:
+			if node.Tok == token.DEFINE {
+				for _, name := range node.Lhs {
+					if ident, ok := name.(*ast.Ident); ok {
+						declared[ident.Name] = true
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for _, name := range node.Names {
+				declared[name.Name] = true
+			}
+		case *ast.FuncType:
+			for _, fields := range []*ast.FieldList{node.Params, node.Results} {
+				if fields == nil {
+					continue
+				}
+				for _, field := range fields.List {
+					for _, name := range field.Names {
+						declared[name.Name] = true
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	used := make(map[string]bool)
+	ast.Inspect(code, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			if pkg, ok := node.X.(*ast.Ident); ok && !declared[pkg.Name] {
+				if path, known := generatedImports[pkg.Name]; known {
+					used[path] = true
+				}
+			}
+		// The types from the symbol tables are written out as a single name,
+		// such as `*stdjava.Locale`
+		case *ast.Ident:
+			for _, qualified := range qualifiedTypePattern.FindAllStringSubmatch(node.Name, -1) {
+				if path, known := generatedImports[qualified[1]]; known {
+					used[path] = true
+				}
+			}
+		}
+		return true
+	})
+	return used
+}
+
+// importDecl returns the declaration that imports the given packages, or nil
+// if there aren't any
+func importDecl(used map[string]bool) *ast.GenDecl {
+	if len(used) == 0 {
+		return nil
+	}
+
+	paths := make([]string, 0, len(used))
+	for path := range used {
+		paths = append(paths, path)
+	}
+	// The standard library comes first, like goimports orders them
+	sort.Slice(paths, func(i, j int) bool {
+		iStandard, jStandard := !strings.Contains(paths[i], "."), !strings.Contains(paths[j], ".")
+		if iStandard != jStandard {
+			return iStandard
+		}
+		return paths[i] < paths[j]
+	})
+
+	imports := &ast.GenDecl{Tok: token.IMPORT}
+	for _, path := range paths {
+		imports.Specs = append(imports.Specs, &ast.ImportSpec{
+			Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(path)},
+		})
+	}
+	if len(imports.Specs) > 1 {
+		imports.Lparen = 1
+	}
+	return imports
+}
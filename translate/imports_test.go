@@ -0,0 +1,45 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImportPackages(t *testing.T) {
+	src := `
+package imports.runtime;
+public class Runtime {
+    public static void main(String[] args) {
+        int x = 5;
+        int y = x++ + --x;
+        int z = (x = 3) >>> 1;
+        String s = x > 2 ? "a" : "b";
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	for _, expected := range []string{
+		"import (\n\t\"os\"\n\t\"github.com/NickyBoy89/java2go/stdjava\"\n)",
+		"y := stdjava.PostIncrement(&x) + stdjava.PreDecrement(&x)",
+		"x = 3\n\tz := stdjava.UnsignedRightShift(x, 1)",
+		"s := stdjava.Ternary(x > 2, \"a\", \"b\")",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestImportPackages_NoneUsed(t *testing.T) {
+	src := `
+package imports.none;
+public class None {
+    public int value() {
+        return 1;
+    }
+}
+`
+	if out := renderGoFileFromJava(t, src); strings.Contains(out, "import") {
+		t.Errorf("Expected no imports in the output, got:\n%s", out)
+	}
+}
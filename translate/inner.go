@@ -0,0 +1,173 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for the objects of inner classes that are
+// created without an object to enclose them
+const innerClassDiagnostic = "inner-class"
+
+// An object of an inner class that is created from an object of the class
+// that encloses it, such as `cart.new Item(name)`, is given the enclosing
+// object as the first argument of its constructor, which stores it in a field
+// of its own, so that its methods can reach the members of the enclosing
+// object through it:
+//
+//	type CartItem struct {
+//		name  string
+//		outer *Cart
+//	}
+//
+//	func NewItem(outer *Cart, name string) *CartItem {
+//		cm := new(CartItem)
+//		cm.outer = outer
+//		...
+//	}
+//
+// The same class that is created with `new Item(name)` inside of the enclosing
+// class is given the object that `this` refers to there. An inner class that
+// refers to its enclosing object itself, through `Cart.this`, keeps it the same
+// way, and `Cart.this.owner` becomes `cm.outer.owner`
+
+// creationOuter returns the object that a created object of an inner class is
+// enclosed by, or nil if the creation doesn't name one
+//
+// Ex: `cart.new Item(name)` is enclosed by `cart`
+func creationOuter(node *sitter.Node) *sitter.Node {
+	if outer := node.NamedChild(0); !outer.Equal(node.ChildByFieldName("type")) && outer.Type() != "type_arguments" {
+		return outer
+	}
+	return nil
+}
+
+// markOuterReferences finds every inner class in the package that keeps its
+// enclosing object, which is any inner class that is created from one, or that
+// refers to one of the objects that enclose it, and names the field of the
+// class that holds it. The classes that are declared in the file, from the
+// outermost one inwards, are the classes that `this` can be qualified with
+func markOuterReferences(node *sitter.Node, source []byte, packageScope *symbol.PackageScope, declared []*symbol.ClassScope) {
+	switch node.Type() {
+	case "class_declaration", "interface_declaration", "enum_declaration", "record_declaration":
+		name := node.ChildByFieldName("name").Content(source)
+		for _, class := range packageScope.Classes() {
+			if class.Class.OriginalName != name {
+				continue
+			}
+			if len(declared) == 0 && class.Enclosing == nil || len(declared) > 0 && class.Enclosing == declared[len(declared)-1] {
+				declared = append(declared, class)
+				break
+			}
+		}
+	case "object_creation_expression":
+		if creationOuter(node) == nil {
+			break
+		}
+		className := node.ChildByFieldName("type")
+		if className.Type() == "generic_type" {
+			className = className.NamedChild(0)
+		}
+		for _, class := range packageScope.Classes() {
+			if class.Enclosing != nil && class.Class.OriginalName == stripJavaQualifier(className.Content(source)) {
+				keepOuter(class)
+			}
+		}
+	case "field_access":
+		// Ex: `Cart.this` in `Item`, which is nested in `Cart`, keeps the `Cart`
+		// that encloses the `Item`, and each of the classes in between keeps
+		// the object that encloses it
+		if node.ChildByFieldName("field").Type() != "this" {
+			break
+		}
+		name := stripJavaQualifier(node.ChildByFieldName("object").Content(source))
+		for ind := len(declared) - 1; ind > 0; ind-- {
+			if declared[ind].Class.OriginalName == name {
+				break
+			}
+			if declared[ind].Enclosing == nil {
+				break
+			}
+			keepOuter(declared[ind])
+		}
+	}
+	for _, child := range nodeutil.NamedChildrenOf(node) {
+		markOuterReferences(child, source, packageScope, declared)
+	}
+}
+
+// keepOuter names the field of an inner class that holds its enclosing object,
+// if it doesn't have one already
+func keepOuter(class *symbol.ClassScope) {
+	if class.Outer != "" {
+		return
+	}
+	// The field can't take the name of any of the class's fields
+	names := symbol.NewNameAllocator(class.Monitor)
+	for _, field := range class.Fields {
+		names.Reserve(field.Name)
+	}
+	class.Outer = names.Allocate("outer")
+}
+
+// outerType returns the Go type of the field that holds the enclosing object
+// of an inner class. The inner class takes the type parameters of the class
+// that it is nested in, so these are in scope for the field
+func outerType(class *symbol.ClassScope) ast.Expr {
+	enclosing := class.Enclosing
+	return &ast.StarExpr{X: instantiateGenericType(enclosing.Class.Name, typeParamExprs(enclosing.TypeParameters))}
+}
+
+// outerField returns the field of an inner class that holds its enclosing
+// object, or nil if the class doesn't have one
+func outerField(class *symbol.ClassScope) *ast.Field {
+	if class.Outer == "" {
+		return nil
+	}
+	return &ast.Field{Names: []*ast.Ident{{Name: class.Outer}}, Type: outerType(class)}
+}
+
+// outerParameter adds the enclosing object of an inner class as the first
+// parameter of one of its constructors, and stores it in the object that the
+// constructor creates
+func outerParameter(params *ast.FieldList, body *ast.BlockStmt, ctx Ctx) {
+	param := &ast.Ident{Name: localVariableNames(ctx).Allocate("outer")}
+	params.List = append([]*ast.Field{{Names: []*ast.Ident{param}, Type: outerType(ctx.currentClass)}}, params.List...)
+	stored := &ast.AssignStmt{
+		Lhs: []ast.Expr{&ast.SelectorExpr{X: &ast.Ident{Name: ReceiverName(ctx)}, Sel: &ast.Ident{Name: ctx.currentClass.Outer}}},
+		Tok: token.ASSIGN,
+		Rhs: []ast.Expr{param},
+	}
+	// The object is stored right after it is created
+	body.List = append(body.List[:1], append([]ast.Stmt{stored}, body.List[1:]...)...)
+}
+
+// enclosingObject returns the object that encloses a created object of an
+// inner class, which is either named by the creation, or is the innermost
+// object of the class that encloses the inner class
+func enclosingObject(node *sitter.Node, class *symbol.ClassScope, source []byte, ctx Ctx) ast.Expr {
+	if outer := creationOuter(node); outer != nil {
+		return ParseExpr(outer, source, ctx)
+	}
+	for _, instance := range enclosingInstances(ctx) {
+		if instance.className == class.Enclosing.Class.OriginalName {
+			return instance.object
+		}
+	}
+	ctx.diagnostics.Report(node, innerClassDiagnostic, "`%s` has no object of `%s` to enclose it", node.Content(source), class.Enclosing.Class.OriginalName)
+	return &ast.Ident{Name: "nil"}
+}
+
+// hasConstructor determines if a class declares any constructors of its own
+func hasConstructor(class *symbol.ClassScope) bool {
+	for _, method := range class.Methods {
+		if method.Constructor {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInnerClassCreation(t *testing.T) {
+	helper := setupParseHelper(t, `
+package com.shop;
+public class Cart {
+    private String owner;
+
+    public class Item {
+        private String name;
+
+        public Item(String name) {
+            this.name = name;
+        }
+
+        public String describe() {
+            return Cart.this.owner + name;
+        }
+
+        public Item copy() {
+            return new Item(name);
+        }
+    }
+
+    public class Tag {
+        String label;
+    }
+
+    public static Item make(Cart cart, String name) {
+        Tag tag = cart.new Tag();
+        return cart.new Item(name);
+    }
+
+    public static Item orphan(String name) {
+        return new Item(name);
+    }
+
+    public Item add(String name) {
+        return new Item(name);
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Cart.java")
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helper))
+	for _, expected := range []string{
+		"type CartItem struct { name string outer *Cart }",
+		"func NewItem(outer *Cart, name string) *CartItem { cm := new(CartItem) cm.outer = outer cm.name = name return cm }",
+		"return cm.outer.owner + name",
+		"return NewItem(cm.outer, name)",
+		"type CartTag struct { label string outer *Cart }",
+		"tag := &CartTag{outer: cart}",
+		"return NewItem(cart, name)",
+		"return NewItem(nil, name)",
+		"return NewItem(ct, name)",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != innerClassDiagnostic || items[0].Line != 32 {
+		t.Errorf("Expected the inner class created without an enclosing object on line 32 to be reported, got %v", items)
+	}
+}
+
+func TestInnerClassOuterReference(t *testing.T) {
+	helper := setupParseHelper(t, `
+package com.shop;
+public class Cart {
+    private String owner;
+
+    public class Item {
+        private String name;
+
+        public Item(String name) {
+            this.name = name;
+        }
+
+        public String describe() {
+            return Cart.this.owner + name;
+        }
+
+        public class Tag {
+            public String owner() {
+                return Cart.this.owner;
+            }
+        }
+    }
+
+    public static class Total {
+        int value;
+    }
+
+    public Item add(String name) {
+        return new Item(name);
+    }
+}
+`)
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helper))
+	for _, expected := range []string{
+		"type CartItem struct { name string outer *Cart }",
+		"func NewItem(outer *Cart, name string) *CartItem { cm := new(CartItem) cm.outer = outer cm.name = name return cm }",
+		"return cm.outer.owner + name",
+		"type ItemTag struct { outer *CartItem }",
+		"return ig.outer.outer.owner",
+		"type CartTotal struct { value int32 }",
+		"return NewItem(ct, name)",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
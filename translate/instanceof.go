@@ -0,0 +1,193 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// hoistedStatements collects the statements that have to run before the
+// statement that is being parsed, for the parts of an expression that Go can
+// only write as a statement
+type hoistedStatements struct {
+	list  []ast.Stmt
+	names *symbol.NameAllocator
+
+	// Whether the statements are spliced into the enclosing block, where a
+	// variable that they declare could collide with another one
+	spliced bool
+}
+
+// hoist adds a statement before the current one
+func (h *hoistedStatements) hoist(stmt ast.Stmt) {
+	h.list = append(h.list, stmt)
+}
+
+// withHoisted runs a statement after the statements that were hoisted out of
+// it. An `if` statement takes a single one as its init statement, unless it is
+// a declaration, and otherwise, they are put in a block together, which is only
+// done for statements that don't declare anything
+func withHoisted(stmt ast.Stmt, hoisted *hoistedStatements) ast.Stmt {
+	if len(hoisted.list) == 0 {
+		return stmt
+	}
+	if ifStmt, ok := stmt.(*ast.IfStmt); ok && len(hoisted.list) == 1 && ifStmt.Init == nil && !isDeclStmt(hoisted.list[0]) {
+		ifStmt.Init = hoisted.list[0]
+		return ifStmt
+	}
+	return &ast.BlockStmt{List: append(hoisted.list, stmt)}
+}
+
+// isDeclStmt determines if a statement is a declaration, which can't be the
+// init statement of an `if`
+func isDeclStmt(stmt ast.Stmt) bool {
+	_, ok := stmt.(*ast.DeclStmt)
+	return ok
+}
+
+// instanceOf translates an `instanceof` check into a type assertion, whose
+// result is a statement in Go
+//
+// When the checked value is a variable, which can be checked ahead of time, the
+// assertion is hoisted before the statement that it is in. Otherwise, it is
+// made in a function literal that is called in place
+//
+// Ex: `if (shape instanceof Circle)` becomes
+// `if _, isCircle := shape.(*Circle); isCircle`
+func instanceOf(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	left := node.ChildByFieldName("left")
+	value := ParseExpr(left, source, ctx)
+	assertedType := instanceofType(node.ChildByFieldName("right"), source, ctx)
+
+	// A pattern variable, such as the `s` of `obj instanceof String s`, is
+	// declared by the assertion, so the assertion is hoisted with it, as long as
+	// that doesn't run it earlier than, or when it otherwise wouldn't be
+	name := node.ChildByFieldName("name")
+	binding := ast.Expr(&ast.Ident{Name: "_"})
+	if name != nil {
+		binding = &ast.Ident{Name: symbol.VariableName(name.Content(source))}
+	}
+
+	if ctx.hoisted != nil && isHoistable(left) && (name == nil && !ctx.hoisted.spliced || name != nil && isEvaluatedFirst(node, "", source)) {
+		typeName, _ := parseJavaTypeString(node.ChildByFieldName("right").Content(source))
+		typeName = strings.TrimRight(stripJavaQualifier(typeName), "[]")
+		result := &ast.Ident{Name: ctx.hoisted.names.Allocate("is" + symbol.Uppercase(typeName))}
+		ctx.hoisted.hoist(&ast.AssignStmt{
+			Lhs: []ast.Expr{binding, result},
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{&ast.TypeAssertExpr{X: value, Type: assertedType}},
+		})
+		return result
+	}
+
+	// Otherwise, the pattern variable is declared ahead of time, and only
+	// assigned to where the check is made
+	//
+	// Ex: `ready() && next() instanceof String s` becomes
+	// `var s string` and `ready() && func() bool { var ok bool; s, ok = next().(string); return ok }()`
+	check := []ast.Stmt{
+		&ast.AssignStmt{
+			Lhs: []ast.Expr{&ast.Ident{Name: "_"}, &ast.Ident{Name: "ok"}},
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{&ast.TypeAssertExpr{X: value, Type: assertedType}},
+		},
+	}
+	if name != nil && ctx.hoisted != nil {
+		ctx.hoisted.hoist(&ast.DeclStmt{Decl: &ast.GenDecl{
+			Tok:   token.VAR,
+			Specs: []ast.Spec{&ast.ValueSpec{Names: []*ast.Ident{binding.(*ast.Ident)}, Type: assertedType}},
+		}})
+		check = []ast.Stmt{
+			&ast.DeclStmt{Decl: &ast.GenDecl{
+				Tok:   token.VAR,
+				Specs: []ast.Spec{&ast.ValueSpec{Names: []*ast.Ident{{Name: "ok"}}, Type: &ast.Ident{Name: "bool"}}},
+			}},
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{binding, &ast.Ident{Name: "ok"}},
+				Tok: token.ASSIGN,
+				Rhs: []ast.Expr{&ast.TypeAssertExpr{X: value, Type: assertedType}},
+			},
+		}
+	}
+
+	return &ast.CallExpr{
+		Fun: &ast.FuncLit{
+			Type: &ast.FuncType{
+				Params:  &ast.FieldList{},
+				Results: &ast.FieldList{List: []*ast.Field{{Type: &ast.Ident{Name: "bool"}}}},
+			},
+			Body: &ast.BlockStmt{List: append(check, &ast.ReturnStmt{Results: []ast.Expr{&ast.Ident{Name: "ok"}}})},
+		},
+	}
+}
+
+// bindsAfterIf determines if an `if` statement declares a pattern variable
+// that is used after it, which is the case when the statement only continues
+// if the pattern didn't match
+//
+// Ex: `if (!(obj instanceof String s)) { return; }`
+func bindsAfterIf(node *sitter.Node) bool {
+	if node.Type() != "if_statement" || node.ChildByFieldName("alternative") != nil {
+		return false
+	}
+
+	condition := unwrapParentheses(node.ChildByFieldName("condition"))
+	if condition.Type() != "unary_expression" || condition.ChildByFieldName("operator").Type() != "!" {
+		return false
+	}
+	check := unwrapParentheses(condition.ChildByFieldName("operand"))
+	if check.Type() != "instanceof_expression" || check.ChildByFieldName("name") == nil {
+		return false
+	}
+
+	last := node.ChildByFieldName("consequence")
+	if last.Type() == "block" {
+		if last.NamedChildCount() == 0 {
+			return false
+		}
+		last = last.NamedChild(int(last.NamedChildCount()) - 1)
+	}
+	switch last.Type() {
+	case "return_statement", "throw_statement", "break_statement", "continue_statement":
+		return true
+	}
+	return false
+}
+
+// unwrapParentheses returns the expression inside of any parentheses
+func unwrapParentheses(node *sitter.Node) *sitter.Node {
+	for node.Type() == "parenthesized_expression" {
+		node = node.NamedChild(0)
+	}
+	return node
+}
+
+// isHoistable determines if the value of an expression can be checked before
+// the statement that it is in, without changing what the statement does. This
+// is true for variables, which are checked with a type assertion that can't
+// panic, even when the variable is nil
+func isHoistable(node *sitter.Node) bool {
+	switch node.Type() {
+	case "identifier", "this":
+		return true
+	case "parenthesized_expression":
+		return isHoistable(node.NamedChild(0))
+	}
+	return false
+}
+
+// instanceofType returns the type that a value is asserted to have, for the
+// type that it is checked against with `instanceof`
+func instanceofType(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	name, typeArgs := parseJavaTypeString(node.Content(source))
+	if class := resolveClassScopeByName(ctx, stripJavaQualifier(name)); class != nil && len(typeArgs) == 0 {
+		if class.IsInterface {
+			return &ast.Ident{Name: class.Class.Name}
+		}
+		return &ast.StarExpr{X: &ast.Ident{Name: class.Class.Name}}
+	}
+	return javaTypeStringToGoTypeExpr(node.Content(source), inScopeTypeParameters(ctx))
+}
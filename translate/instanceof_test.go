@@ -0,0 +1,86 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInstanceof(t *testing.T) {
+	src := `
+package instanceof.checks;
+public class Shapes {
+    public interface Shape { }
+    public static class Circle implements Shape { }
+    public static class Square implements Shape { }
+    public static boolean check(Shape shape, Shapes shapes) {
+        if (shape instanceof Circle) {
+            return true;
+        } else if (shape instanceof Square || shape instanceof Shape) {
+            return false;
+        }
+        boolean circle = shapes.get() instanceof Circle;
+        return shape != null && shape instanceof Circle;
+    }
+    public Shape get() { return null; }
+}
+`
+	out := normalizeSpaces(renderGoFileFromJava(t, src))
+	for _, want := range []string{
+		`if _, isCircle := shape.(*ShapesCircle); isCircle { return true }`,
+		`else { _, isSquare := shape.(*ShapesSquare) _, isShape := shape.(ShapesShape) if isSquare || isShape { return false } }`,
+		`circle := func() bool { _, ok := shapes.get().(*ShapesCircle) return ok }()`,
+		`{ _, isCircle := shape.(*ShapesCircle) return shape != nil && isCircle }`,
+	} {
+		if !strings.Contains(out, normalizeSpaces(want)) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestInstanceofPatternVariables(t *testing.T) {
+	src := `
+package instanceof.patterns;
+public class Patterns {
+    public static class Circle { int radius; }
+    public static int size(Object shape, Object other) {
+        if (shape instanceof Circle circle && circle.radius > 2) {
+            return circle.radius;
+        }
+        if (!(other instanceof Circle c)) {
+            return 0;
+        }
+        return c.radius;
+    }
+}
+`
+	out := normalizeSpaces(renderGoFileFromJava(t, src))
+	for _, want := range []string{
+		`if circle, isCircle := shape.(*PatternsCircle); isCircle && circle.radius > 2 { return circle.radius }`,
+		`c, isCircle := other.(*PatternsCircle) if !(isCircle) { return 0 } return c.radius`,
+	} {
+		if !strings.Contains(out, normalizeSpaces(want)) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestInstanceofPatternVariablesEvaluatedConditionally(t *testing.T) {
+	src := `
+package instanceof.conditional;
+public class Conditional {
+    public static boolean ready() { return true; }
+    public static Object next() { return null; }
+    public static int size() {
+        if (ready() && next() instanceof String s) {
+            return s.length();
+        }
+        return 0;
+    }
+}
+`
+	out := normalizeSpaces(renderGoFileFromJava(t, src))
+	want := `{ var s string if Ready() && func() bool { var ok bool s, ok = Next().(string) return ok }() { return s.length() } }`
+	if !strings.Contains(out, normalizeSpaces(want)) {
+		t.Errorf("expected %q in output, got:\n%s", want, out)
+	}
+}
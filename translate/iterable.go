@@ -0,0 +1,128 @@
+package translate
+
+import (
+	"go/ast"
+	"slices"
+
+	"github.com/NickyBoy89/java2go/astutil"
+	"github.com/NickyBoy89/java2go/nodeutil"
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// A class that implements `Iterable` has an `All` method as well, which
+// returns the elements of its iterator as a Go iterator, so that it implements
+// the runtime's `Iterable`:
+//
+//	func (bg *Bag[T]) All() iter.Seq[T] {
+//	    return stdjava.Elements[T](bg.Iterator())
+//	}
+//
+// A loop over an object that is `Iterable` ranges over the iterator that `All`
+// returns, the same as a loop over one of Go's own collections:
+//
+//	for (String word : words) {               for word := range words.All() {
+
+// iterableElementType returns the Go type of the elements of the `Iterable`
+// that a class declares that it implements, or nil if it doesn't declare it.
+// A raw `Iterable` has elements of any type
+func iterableElementType(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	interfaces := node.ChildByFieldName("interfaces")
+	if interfaces == nil {
+		return nil
+	}
+	for _, implemented := range nodeutil.NamedChildrenOf(interfaces.NamedChild(0)) {
+		name := implemented
+		if name.Type() == "generic_type" {
+			name = name.NamedChild(0)
+		}
+		if stripJavaQualifier(name.Content(source)) != "Iterable" {
+			continue
+		}
+		if args := nodeutil.NamedChildrenOf(implemented.NamedChild(int(implemented.NamedChildCount()) - 1)); implemented.Type() == "generic_type" && len(args) == 1 {
+			return astutil.ParseTypeWithTypeParams(args[0], source, inScopeTypeParameters(ctx))
+		}
+		ctx.fallbacks.Report(implemented, anyFallbackDiagnostic, "the raw type `%s` has no type arguments", implemented.Content(source))
+		return &ast.Ident{Name: "any"}
+	}
+	return nil
+}
+
+// iterableAll generates the `All` method of the current class, as described
+// above, or returns nil if the class doesn't implement `Iterable` with an
+// `iterator` method of its own. A class that already has a method named `All`
+// keeps it
+func iterableAll(node *sitter.Node, source []byte, ctx Ctx) []ast.Decl {
+	class := ctx.currentClass
+	if class == nil || class.IsInterface {
+		return nil
+	}
+	elementType := iterableElementType(node, source, ctx)
+	if elementType == nil {
+		return nil
+	}
+	var iterator *symbol.Definition
+	for _, method := range class.Methods {
+		if method.Name == "All" {
+			return nil
+		}
+		if method.OriginalName == "iterator" && len(method.Parameters) == 0 && !method.IsStatic && !method.Constructor {
+			iterator = method
+		}
+	}
+	if iterator == nil {
+		return nil
+	}
+
+	receiver := ReceiverName(ctx)
+	elements := &ast.CallExpr{
+		Fun:  &ast.IndexExpr{X: &ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: "Elements"}}, Index: elementType},
+		Args: []ast.Expr{&ast.CallExpr{Fun: &ast.SelectorExpr{X: &ast.Ident{Name: receiver}, Sel: &ast.Ident{Name: iterator.Name}}}},
+	}
+	return []ast.Decl{&ast.FuncDecl{
+		Name: &ast.Ident{Name: "All"},
+		Recv: &ast.FieldList{List: []*ast.Field{{Names: []*ast.Ident{{Name: receiver}}, Type: classReceiverType(class, ctx)}}},
+		Type: &ast.FuncType{
+			Params: &ast.FieldList{},
+			Results: &ast.FieldList{List: []*ast.Field{{
+				Type: &ast.IndexExpr{X: &ast.SelectorExpr{X: &ast.Ident{Name: "iter"}, Sel: &ast.Ident{Name: "Seq"}}, Index: elementType},
+			}}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{elements}}}},
+	}}
+}
+
+// implementsIterable determines if a class of the package implements
+// `Iterable`, through itself, the classes that it extends, or the interfaces
+// that it implements
+func implementsIterable(class *symbol.ClassScope, ctx Ctx, visited map[*symbol.ClassScope]bool) bool {
+	if class == nil || visited[class] {
+		return false
+	}
+	visited[class] = true
+	if slices.Contains(class.Interfaces, "Iterable") {
+		return true
+	}
+	for _, implemented := range class.Interfaces {
+		if implementsIterable(resolveClassScopeByName(ctx, implemented), ctx, visited) {
+			return true
+		}
+	}
+	return class.Superclass != "" && implementsIterable(resolveClassScopeByName(ctx, class.Superclass), ctx, visited)
+}
+
+// iterableRange returns the iterator that a loop over an object that is
+// `Iterable` ranges over, or nil if the object isn't known to be one
+//
+// Ex: `for (String word : words)` ranges over `words.All()`
+func iterableRange(node *sitter.Node, iterated ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	iterable := implementsIterable(exprClass(unwrapParentheses(node), ctx, source), ctx, map[*symbol.ClassScope]bool{})
+	if javaType, ok := inferExprJavaType(node, ctx, source); ok {
+		base, _ := parseJavaTypeString(javaType)
+		iterable = iterable || stripJavaQualifier(base) == "Iterable"
+	}
+	if !iterable {
+		return nil
+	}
+	return &ast.CallExpr{Fun: &ast.SelectorExpr{X: iterated, Sel: &ast.Ident{Name: "All"}}}
+}
@@ -0,0 +1,60 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIterables(t *testing.T) {
+	out := renderGoFileFromJava(t, `
+package util.bags;
+import java.util.*;
+public class Bag<T> implements Iterable<T> {
+    private List<T> items;
+    public Iterator<T> iterator() {
+        return items.iterator();
+    }
+    public int count(Bag<String> words, Iterable<Integer> numbers, int[] lengths) {
+        int n = 0;
+        for (String word : words) {
+            n++;
+        }
+        for (Integer number : numbers) {
+            n++;
+        }
+        for (T item : this) {
+            n++;
+        }
+        for (int length : lengths) {
+            n += length;
+        }
+        return n;
+    }
+}
+`)
+	for _, expected := range []string{
+		"func (bg *Bag[T]) Iterator() stdjava.Iterator[T] {",
+		"func (bg *Bag[T]) Count(words *Bag[string], numbers stdjava.Iterable[*int32], lengths []int32) int32 {",
+		"for word := range words.All() {",
+		"for number := range numbers.All() {",
+		"for item := range bg.All() {",
+		"for _, length := range lengths {",
+		"func (bg *Bag[T]) All() iter.Seq[T] {\n\treturn stdjava.Elements[T](bg.Iterator())\n}",
+		`"iter"`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestIterableWithoutIterator(t *testing.T) {
+	out := renderGoFileFromJava(t, `
+public abstract class Source<T> implements Iterable<T> {
+    public abstract int size();
+}
+`)
+	if strings.Contains(out, "All()") {
+		t.Errorf("Expected a class without an iterator to not range over one, got:\n%s", out)
+	}
+}
@@ -0,0 +1,209 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported when two Strings are compared with `==`
+const stringIdentityDiagnostic = "string-identity"
+
+// The methods of Java's `String` that return another String
+var stringResultMethods = map[string]bool{
+	"concat":      true,
+	"intern":      true,
+	"repeat":      true,
+	"replace":     true,
+	"replaceAll":  true,
+	"strip":       true,
+	"substring":   true,
+	"toLowerCase": true,
+	"toString":    true,
+	"toUpperCase": true,
+	"trim":        true,
+}
+
+// isStringExpr determines if the given expression evaluates to a Java `String`
+func isStringExpr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	switch node.Type() {
+	case "string_literal":
+		return true
+	case "parenthesized_expression":
+		return isStringExpr(node.NamedChild(0), ctx, source)
+	case "binary_expression":
+		// Concatenating anything with a String results in a String
+		return node.ChildByFieldName("operator").Type() == "+" &&
+			(isStringExpr(node.ChildByFieldName("left"), ctx, source) || isStringExpr(node.ChildByFieldName("right"), ctx, source))
+	case "method_invocation":
+		object := node.ChildByFieldName("object")
+		if object == nil {
+			break
+		}
+		name := node.ChildByFieldName("name").Content(source)
+		if name == "valueOf" && object.Content(source) == "String" {
+			return true
+		}
+		return stringResultMethods[name] && isStringExpr(object, ctx, source)
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	return ok && stripJavaQualifier(javaType) == "String"
+}
+
+// checkStringComparison reports comparisons between two Strings with `==` or
+// `!=`, which compare the identity of the Strings in Java, but compare their
+// contents in Go
+//
+// The translated comparison is left as-is, since comparing the contents is
+// almost always what was intended
+func checkStringComparison(node *sitter.Node, ctx Ctx, source []byte) {
+	left, right := node.ChildByFieldName("left"), node.ChildByFieldName("right")
+	// Checking if a String is null is always an identity comparison
+	if left.Type() == "null_literal" || right.Type() == "null_literal" {
+		return
+	}
+	if isStringExpr(left, ctx, source) && isStringExpr(right, ctx, source) {
+		ctx.diagnostics.Report(node, stringIdentityDiagnostic,
+			"`%s` compares the identity of two Strings in Java, but the generated Go compares their contents. Use `equals` if this is intended",
+			node.Content(source))
+	}
+}
+
+// stringEquals translates a call to `equals` on a String into a comparison of
+// the two strings
+//
+// Ex: `name.equals("test")` turns into `name == "test"`
+func stringEquals(object, argument ast.Expr, argumentNode *sitter.Node) ast.Expr {
+	// A String is never equal to null, but the Go string can't be compared to nil
+	if argumentNode.Type() == "null_literal" {
+		return &ast.Ident{Name: "false"}
+	}
+	return &ast.BinaryExpr{X: object, Op: token.EQL, Y: argument}
+}
+
+// Converting between a String and a `char[]` is a conversion between a string
+// and a `[]rune` in Go, which indexes the string by its code points instead of
+// by its UTF-16 code units, the same as Java does for every character in the
+// Basic Multilingual Plane
+//
+// In strict mode, the runtime's functions are used instead, which split any
+// other character into two surrogates, the same way that Java does
+
+// isCharArrayExpr determines if the given expression evaluates to a `char[]`
+func isCharArrayExpr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	if node.Type() == "method_invocation" && node.ChildByFieldName("name").Content(source) == "toCharArray" {
+		return true
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	return ok && strings.ReplaceAll(javaType, " ", "") == "char[]"
+}
+
+// charsToString converts a `char[]` into a string, optionally only using the
+// given range of the characters
+func charsToString(chars, offset, count ast.Expr) ast.Expr {
+	if offset != nil {
+		chars = &ast.SliceExpr{
+			X:    chars,
+			Low:  offset,
+			High: &ast.BinaryExpr{X: offset, Op: token.ADD, Y: count},
+		}
+	}
+	if StrictMode {
+		return runtimeStringCall("StringFromChars", chars)
+	}
+	return &ast.CallExpr{Fun: &ast.Ident{Name: "string"}, Args: []ast.Expr{chars}}
+}
+
+// newString translates the creation of a String from its constructor, or
+// returns nil if the constructor isn't supported
+func newString(node *sitter.Node, argumentNodes []*sitter.Node, arguments []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	switch {
+	case len(arguments) == 0:
+		return &ast.BasicLit{Kind: token.STRING, Value: `""`}
+	case len(arguments) == 1 && isStringExpr(argumentNodes[0], ctx, source):
+		// Strings are values in Go, so there is nothing to copy
+		return arguments[0]
+	case len(arguments) == 1 && isCharArrayExpr(argumentNodes[0], ctx, source):
+		return charsToString(arguments[0], nil, nil)
+	case len(arguments) == 3 && isCharArrayExpr(argumentNodes[0], ctx, source):
+		return charsToString(arguments[0], arguments[1], arguments[2])
+	case isByteArrayExpr(argumentNodes[0], ctx, source):
+		return newStringFromBytes(node, argumentNodes, arguments, ctx, source)
+	}
+	return nil
+}
+
+// The methods of String that are implemented by the runtime, and the number of
+// arguments that are passed to the runtime along with the string
+var runtimeStringMethods = map[string]struct {
+	function  string
+	arguments int
+}{
+	"equalsIgnoreCase":    {"EqualsIgnoreCase", 1},
+	"compareToIgnoreCase": {"CompareToIgnoreCase", 1},
+	// Converting the case for a locale is done by `localeMethod`
+	"toLowerCase": {"ToLowerCase", 0},
+	"toUpperCase": {"ToUpperCase", 0},
+}
+
+// stringMethod translates the methods of String that don't map directly onto
+// Go, or returns nil if the method isn't one of them
+func stringMethod(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	objectNode := node.ChildByFieldName("object")
+	argumentNodes := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+
+	switch name := node.ChildByFieldName("name").Content(source); {
+	// Reversing a string is done by reversing a StringBuilder created from it
+	//
+	// Ex: `new StringBuilder(word).reverse().toString()` turns into
+	// `stdjava.Reverse(word)`
+	case reversedString(node, ctx, source) != nil:
+		return runtimeStringCall("Reverse", ParseExpr(reversedString(node, ctx, source), source, ctx))
+	// Ex: `String.valueOf(chars)` turns into `string(chars)`
+	case (name == "valueOf" || name == "copyValueOf") && objectNode.Content(source) == "String":
+		switch {
+		case len(args) == 1 && isCharArrayExpr(argumentNodes[0], ctx, source):
+			return charsToString(args[0], nil, nil)
+		case len(args) == 3 && isCharArrayExpr(argumentNodes[0], ctx, source):
+			return charsToString(args[0], args[1], args[2])
+		}
+	case isStringExpr(objectNode, ctx, source):
+		return stringInstanceMethod(name, object, args)
+	}
+	return nil
+}
+
+// stringInstanceMethod translates a method of String that is called on a
+// string, which doesn't have to be written out, such as for a method
+// reference, or returns nil if the method maps directly onto Go
+func stringInstanceMethod(name string, object ast.Expr, args []ast.Expr) ast.Expr {
+	switch {
+	// Ex: `name.toCharArray()` turns into `[]rune(name)`
+	case name == "toCharArray" && len(args) == 0:
+		if StrictMode {
+			return runtimeStringCall("ToCharArray", object)
+		}
+		return &ast.CallExpr{Fun: &ast.ArrayType{Elt: &ast.Ident{Name: "rune"}}, Args: []ast.Expr{object}}
+	// Indexing a string by its UTF-16 code units is only done in strict mode
+	case name == "charAt" && len(args) == 1 && StrictMode:
+		return runtimeStringCall("CharAt", object, args[0])
+	case name == "length" && len(args) == 0 && StrictMode:
+		return runtimeStringCall("Length", object)
+	default:
+		if method, ok := runtimeStringMethods[name]; ok && len(args) >= method.arguments {
+			return runtimeStringCall(method.function, append([]ast.Expr{object}, args[:method.arguments]...)...)
+		}
+	}
+	return nil
+}
+
+// runtimeStringCall calls one of the runtime's string functions
+func runtimeStringCall(function string, args ...ast.Expr) ast.Expr {
+	return &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: function}},
+		Args: args,
+	}
+}
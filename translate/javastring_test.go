@@ -0,0 +1,190 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStrings_EqualsComparesContents(t *testing.T) {
+	src := `
+package strs.equality;
+public class Names {
+    String name;
+    boolean matches(String other) {
+        String upper = other.toUpperCase();
+        if (!name.equals(upper)) {
+            return "root".equals(other);
+        }
+        return other.equals(null);
+    }
+}
+`
+	out := normalizeSpaces(renderGoFileFromJava(t, src))
+
+	if !strings.Contains(out, "if !(name == upper) {") {
+		t.Errorf("Expected equals on a String field to be a comparison, got:\n%s", out)
+	}
+	if !strings.Contains(out, `return "root" == other`) {
+		t.Errorf("Expected equals on a String literal to be a comparison, got:\n%s", out)
+	}
+	if !strings.Contains(out, "return false") {
+		t.Errorf("Expected a String to never equal null, got:\n%s", out)
+	}
+}
+
+func TestStrings_IdentityComparisonDiagnostic(t *testing.T) {
+	src := `
+package strs.identity;
+public class Names {
+    String name;
+    boolean same(String other, int a, int b) {
+        if (other == null || a == b) {
+            return false;
+        }
+        return name == other + "";
+    }
+}
+`
+	helper := setupParseHelper(t, src)
+	helper.Ctx.diagnostics = NewDiagnostics("Names.java")
+	out := renderGoFileFromJavaCtx(t, helper)
+
+	// The comparison is kept as a comparison of the contents
+	if !strings.Contains(out, `return name == other+""`) {
+		t.Errorf("Expected the comparison to be kept, got:\n%s", out)
+	}
+
+	diagnostics := helper.Ctx.diagnostics.Items()
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected exactly one diagnostic, got %v", diagnostics)
+	}
+	if diagnostics[0].Kind != stringIdentityDiagnostic || diagnostics[0].Line != 9 || diagnostics[0].Column != 16 {
+		t.Errorf("Expected a String identity diagnostic at 9:16, got %v", diagnostics[0])
+	}
+}
+
+func TestStrings_IdentityComparisonOfShadowedVariable(t *testing.T) {
+	src := `
+package strs.shadowed;
+public class Names {
+    boolean same(String name, int count) {
+        {
+            String x = name;
+            String y = name;
+        }
+        {
+            int x = count;
+            int y = count + 1;
+            return x == y;
+        }
+    }
+}
+`
+	helper := setupParseHelper(t, src)
+	helper.Ctx.diagnostics = NewDiagnostics("Names.java")
+	renderGoFileFromJavaCtx(t, helper)
+
+	// The variables that are compared are the ints from the block that they are
+	// used in
+	if diagnostics := helper.Ctx.diagnostics.Items(); len(diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics, got %v", diagnostics)
+	}
+}
+
+const charArraySource = `
+package strs.chars;
+public class Chars {
+    String reverse(String word) {
+        char[] letters = word.toCharArray();
+        String copy = new String(letters, 1, 2);
+        return String.valueOf(letters) + new String(letters) + word.charAt(0);
+    }
+}
+`
+
+func TestStrings_CharArrayConversions(t *testing.T) {
+	out := renderGoFileFromJava(t, charArraySource)
+
+	for _, expected := range []string{
+		"letters := []rune(word)",
+		"copy := string(letters[1 : 1+2])",
+		"return string(letters) + string(letters) + word.charAt(0)",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestStrings_StrictCharArrayConversions(t *testing.T) {
+	StrictMode = true
+	defer func() { StrictMode = false }()
+
+	out := renderGoFileFromJava(t, charArraySource)
+
+	for _, expected := range []string{
+		"letters := stdjava.ToCharArray(word)",
+		"copy := stdjava.StringFromChars(letters[1 : 1+2])",
+		"return stdjava.StringFromChars(letters) + stdjava.StringFromChars(letters) + stdjava.CharAt(word, 0)",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestStrings_RuntimeMethods(t *testing.T) {
+	src := `
+package strs.runtime;
+public class Words {
+    boolean palindrome(String word) {
+        String lower = word.toLowerCase(Locale.ROOT);
+        if (lower.compareToIgnoreCase(word.toUpperCase()) != 0) {
+            return false;
+        }
+        return word.equalsIgnoreCase(new StringBuilder(word).reverse().toString());
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+
+	for _, expected := range []string{
+		"lower := stdjava.ToLowerCase(word)",
+		"if stdjava.CompareToIgnoreCase(lower, stdjava.ToUpperCase(word)) != 0 {",
+		"return stdjava.EqualsIgnoreCase(word, stdjava.Reverse(word))",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestStrings_StrictFloatFormatting(t *testing.T) {
+	StrictMode = true
+	defer func() { StrictMode = false }()
+
+	src := `
+package strings.floats;
+public class Floats {
+    public static String run(double d, float f, int i) {
+        String a = Double.toString(d);
+        String b = String.valueOf(f);
+        String c = "sum " + (d + f) + i;
+        System.out.println(f * 2);
+        return "x" + (f + 1.5f);
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	for _, expected := range []string{
+		"a := stdjava.FormatDouble(d)",
+		"b := stdjava.FormatFloat(f)",
+		`c := "sum " + stdjava.FormatDouble((d + f)) + i`,
+		"fmt.Println(stdjava.FormatFloat(f * 2))",
+		`return "x" + stdjava.FormatFloat((f + 1.5f))`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// Java's labeled loops are Go's labeled loops, and a `break` or `continue` of
+// one of them keeps its label. Go doesn't allow a label that is never used, or
+// a label that is declared twice in the same function, so a label that nothing
+// breaks out of is dropped, and a label that is declared again is numbered:
+//
+//	outer:                      outer:
+//	for (...) {                 for ... {
+//	    for (...) {                 for ... {
+//	        continue outer;             continue outer
+//	    }                           }
+//	}                           }
+//
+// Go can only break out of loops, switches, and selects, so breaking out of any
+// other labeled statement, such as a block, jumps to a label after it instead:
+//
+//	check: {                    {
+//	    if (done) {                 if done {
+//	        break check;                goto check
+//	    }                           }
+//	    ...                         ...
+//	}                           }
+//	                            check:
+
+// isBreakable determines if a statement is one that Go can break out of
+func isBreakable(node *sitter.Node) bool {
+	switch node.Type() {
+	case "for_statement", "enhanced_for_statement", "while_statement", "do_statement", "switch_statement", "switch_expression":
+		return true
+	}
+	return false
+}
+
+// labelScope returns the node that contains all the labels that share a Go
+// function with the given node
+func labelScope(node *sitter.Node) *sitter.Node {
+	for ; node.Parent() != nil; node = node.Parent() {
+		switch node.Type() {
+		case "method_declaration", "constructor_declaration", "lambda_expression", "static_initializer", "class_body":
+			return node
+		}
+	}
+	return node
+}
+
+// labelName returns the Go name of the label of a labeled statement
+func labelName(labeled *sitter.Node, source []byte) string {
+	names := symbol.NewNameAllocator()
+	var name string
+	var allocate func(node *sitter.Node) bool
+	allocate = func(node *sitter.Node) bool {
+		if node.Type() == "labeled_statement" {
+			candidate := node.NamedChild(0).Content(source)
+			if symbol.IsReserved(candidate) {
+				candidate += "Label"
+			}
+			name = names.Allocate(candidate)
+			if node.Equal(labeled) {
+				return true
+			}
+		}
+		for _, child := range nodeutil.NamedChildrenOf(node) {
+			// The labels of lambdas and classes are in functions of their own
+			switch child.Type() {
+			case "lambda_expression", "class_body":
+				continue
+			}
+			if allocate(child) {
+				return true
+			}
+		}
+		return false
+	}
+	allocate(labelScope(labeled))
+	return name
+}
+
+// labelTarget returns the labeled statement that a `break` or `continue` with
+// a label refers to
+func labelTarget(branch *sitter.Node, source []byte) *sitter.Node {
+	label := branch.NamedChild(0).Content(source)
+	for node := branch.Parent(); node != nil; node = node.Parent() {
+		if node.Type() == "labeled_statement" && node.NamedChild(0).Content(source) == label {
+			return node
+		}
+	}
+	return nil
+}
+
+// isLabelUsed determines if anything breaks out of a labeled statement, or
+// continues it
+func isLabelUsed(labeled *sitter.Node, source []byte) bool {
+	var used func(node *sitter.Node) bool
+	used = func(node *sitter.Node) bool {
+		switch node.Type() {
+		case "break_statement", "continue_statement":
+			if node.NamedChildCount() > 0 && labelTarget(node, source).Equal(labeled) {
+				return true
+			}
+		case "lambda_expression", "class_body":
+			return false
+		}
+		for _, child := range nodeutil.NamedChildrenOf(node) {
+			if used(child) {
+				return true
+			}
+		}
+		return false
+	}
+	return used(labeled.NamedChild(1))
+}
+
+// endsWithLabel determines if a labeled statement is followed by its label,
+// which is the case for the statements that Go can't break out of
+func endsWithLabel(node *sitter.Node, source []byte) bool {
+	return node.Type() == "labeled_statement" && !isBreakable(node.NamedChild(1)) && isLabelUsed(node, source)
+}
+
+// parseLabeledStatement parses a labeled statement. A statement that is
+// followed by its label is a block of the statement and the label
+func parseLabeledStatement(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	body := node.NamedChild(1)
+	stmt := TryParseStmt(body, source, ctx)
+	if stmt == nil {
+		stmt = &ast.BlockStmt{List: ParseNode(body, source, ctx).([]ast.Stmt)}
+	}
+	if !isLabelUsed(node, source) {
+		return stmt
+	}
+	label := &ast.Ident{Name: labelName(node, source)}
+	if endsWithLabel(node, source) {
+		return &ast.BlockStmt{List: []ast.Stmt{stmt, &ast.LabeledStmt{Label: label, Stmt: &ast.EmptyStmt{Implicit: true}}}}
+	}
+	// A loop that needs statements before it is the last of them
+	if block, ok := stmt.(*ast.BlockStmt); ok && len(block.List) > 0 {
+		last := len(block.List) - 1
+		block.List[last] = &ast.LabeledStmt{Label: label, Stmt: block.List[last]}
+		return block
+	}
+	return &ast.LabeledStmt{Label: label, Stmt: stmt}
+}
+
+// parseBranch parses a `break` or a `continue`, which jumps to the label after
+// the statement that it breaks out of, if Go can't break out of it
+func parseBranch(node *sitter.Node, tok token.Token, source []byte) ast.Stmt {
+	if node.NamedChildCount() == 0 {
+		return &ast.BranchStmt{Tok: tok}
+	}
+	target := labelTarget(node, source)
+	label := &ast.Ident{Name: labelName(target, source)}
+	if endsWithLabel(target, source) {
+		return &ast.BranchStmt{Tok: token.GOTO, Label: label}
+	}
+	return &ast.BranchStmt{Tok: tok, Label: label}
+}
+
+// attachLabels moves the labels that follow labeled statements onto the
+// statements after them, where there are any
+func attachLabels(stmts []ast.Stmt) []ast.Stmt {
+	var attached []ast.Stmt
+	for ind := 0; ind < len(stmts); ind++ {
+		stmt := stmts[ind]
+		if labeled, ok := stmt.(*ast.LabeledStmt); ok && ind+1 < len(stmts) {
+			if _, empty := labeled.Stmt.(*ast.EmptyStmt); empty {
+				ind++
+				labeled.Stmt = stmts[ind]
+			}
+		}
+		attached = append(attached, stmt)
+	}
+	return attached
+}
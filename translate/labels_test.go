@@ -0,0 +1,68 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLabeledStatements(t *testing.T) {
+	src := `
+package stmt.labels;
+public class Grid {
+    public static int find(int[][] cells, int target) {
+        int found = -1;
+        outer:
+        for (int[] row : cells) {
+            for (int cell : row) {
+                if (cell < 0) {
+                    continue outer;
+                }
+                if (cell == target) {
+                    found = cell;
+                    break outer;
+                }
+            }
+        }
+        unused:
+        for (int cell : cells[0]) {
+            found += cell;
+        }
+        outer:
+        while (found < 0) {
+            found++;
+            range:
+            for (int cell : cells[0]) {
+                if (cell == found) continue range;
+                break outer;
+            }
+        }
+        check:
+        {
+            if (found > 3) {
+                break check;
+            }
+            found = 0;
+        }
+        return found;
+    }
+}
+`
+	out := normalizeSpaces(renderGoFileFromJava(t, src))
+	for _, expected := range []string{
+		"outer: for _, row := range cells {",
+		"continue outer",
+		"break outer",
+		"outer2: for found < 0 {",
+		"rangeLabel: for _, cell := range cells[0] {",
+		"continue rangeLabel",
+		"break outer2",
+		"{ if found > 3 { goto check } found = 0 } check: return found",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+	if strings.Contains(out, "unused") {
+		t.Errorf("Expected the unused label to be dropped, got:\n%s", out)
+	}
+}
@@ -0,0 +1,170 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// listenerMethod returns the only method of an interface that is used as a
+// callback, such as an event listener, or nil if the interface isn't one
+//
+// A callback is an interface with a single method, whose name matches the
+// pattern for listeners. No interface is a callback without a pattern
+func listenerMethod(class *symbol.ClassScope) *symbol.Definition {
+	if class == nil || !class.IsInterface || ListenerPattern == nil || !ListenerPattern.MatchString(class.Class.OriginalName) {
+		return nil
+	}
+	var method *symbol.Definition
+	for _, declared := range class.Methods {
+		if declared.IsStatic {
+			continue
+		}
+		if method != nil {
+			return nil
+		}
+		method = declared
+	}
+	return method
+}
+
+// listenerFuncName returns the name of the function type that implements a
+// listener interface
+func listenerFuncName(class *symbol.ClassScope, ctx Ctx) string {
+	packageScope := symbol.GlobalScope.FindPackage(ctx.currentFile.Package)
+	if packageScope == nil {
+		return class.Class.Name + "Func"
+	}
+	return packageScope.Names().AllocateFor(class.Class.Name+" func", class.Class.Name+"Func")
+}
+
+// listenerAdapter generates a function type that implements a listener
+// interface by calling itself, so that a function can be registered as a
+// listener, like `http.HandlerFunc` does for `http.Handler`
+//
+// Ex: `type ClickListenerFunc func(x int32)` with
+// `func (f ClickListenerFunc) OnClick(x int32) { f(x) }`
+func listenerAdapter(class *symbol.ClassScope, iface ast.Decl, ctx Ctx) []ast.Decl {
+	methods := iface.(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.InterfaceType).Methods.List
+	// An interface that only embeds the interface that it extends has no method
+	// of its own
+	if len(methods) != 1 || len(methods[0].Names) == 0 {
+		return nil
+	}
+	method := methods[0]
+	signature := method.Type.(*ast.FuncType)
+	name := listenerFuncName(class, ctx)
+
+	taken := symbol.NewNameAllocator()
+	var args []ast.Expr
+	var variadic token.Pos
+	for _, param := range signature.Params.List {
+		for _, paramName := range param.Names {
+			taken.Reserve(paramName.Name)
+			args = append(args, &ast.Ident{Name: paramName.Name})
+		}
+		if _, ok := param.Type.(*ast.Ellipsis); ok {
+			variadic = 1
+		}
+	}
+	receiver := taken.Allocate("f")
+
+	call := &ast.CallExpr{Fun: &ast.Ident{Name: receiver}, Args: args, Ellipsis: variadic}
+	body := ast.Stmt(&ast.ExprStmt{X: call})
+	// A `void` result is an empty type
+	if results := signature.Results; results != nil && len(results.List) > 0 {
+		if void, ok := results.List[0].Type.(*ast.Ident); !ok || void.Name != "" {
+			body = &ast.ReturnStmt{Results: []ast.Expr{call}}
+		}
+	}
+
+	decls := []ast.Decl{
+		&ast.GenDecl{
+			Tok:   token.TYPE,
+			Specs: []ast.Spec{&ast.TypeSpec{Name: &ast.Ident{Name: name}, Type: signature}},
+		},
+		&ast.FuncDecl{
+			Recv: &ast.FieldList{List: []*ast.Field{{
+				Names: []*ast.Ident{{Name: receiver}},
+				Type:  &ast.Ident{Name: name},
+			}}},
+			Name: method.Names[0],
+			Type: signature,
+			Body: &ast.BlockStmt{List: []ast.Stmt{body}},
+		},
+	}
+	// The listeners of an event, which don't return anything, are registered
+	// in a list of them
+	if body, ok := body.(*ast.ExprStmt); ok && len(class.TypeParameters) == 0 {
+		decls = append(decls, listenerList(class, method, body.X.(*ast.CallExpr), taken, ctx)...)
+	}
+	return decls
+}
+
+// listenerList generates a list of the listeners of an event, which they are
+// added to and removed from, and which notifies all of them when its own
+// method is called, the same as the list that a Java class keeps of them
+//
+// Ex: `type ClickListenerList []ClickListener`, with `Add`, `Remove`, and
+// `func (l ClickListenerList) OnClick(x int32) { for _, listener := range l { listener.OnClick(x) } }`
+func listenerList(class *symbol.ClassScope, method *ast.Field, call *ast.CallExpr, taken *symbol.NameAllocator, ctx Ctx) []ast.Decl {
+	name := class.Class.Name + "List"
+	if packageScope := symbol.GlobalScope.FindPackage(ctx.currentFile.Package); packageScope != nil {
+		name = packageScope.Names().AllocateFor(class.Class.Name+" list", name)
+	}
+	list := &ast.Ident{Name: taken.Allocate("l")}
+	listener := &ast.Ident{Name: taken.Allocate("listener")}
+	listenerType := &ast.Ident{Name: class.Class.Name}
+
+	// The list is changed through a pointer to it
+	registration := func(methodName string, assigned ast.Expr) ast.Decl {
+		return &ast.FuncDecl{
+			Recv: &ast.FieldList{List: []*ast.Field{{Names: []*ast.Ident{list}, Type: &ast.StarExpr{X: &ast.Ident{Name: name}}}}},
+			Name: &ast.Ident{Name: methodName},
+			Type: &ast.FuncType{Params: &ast.FieldList{List: []*ast.Field{{Names: []*ast.Ident{listener}, Type: listenerType}}}},
+			Body: &ast.BlockStmt{List: []ast.Stmt{&ast.AssignStmt{
+				Lhs: []ast.Expr{&ast.StarExpr{X: list}},
+				Tok: token.ASSIGN,
+				Rhs: []ast.Expr{assigned},
+			}}},
+		}
+	}
+
+	return []ast.Decl{
+		&ast.GenDecl{
+			Tok:   token.TYPE,
+			Specs: []ast.Spec{&ast.TypeSpec{Name: &ast.Ident{Name: name}, Type: &ast.ArrayType{Elt: listenerType}}},
+		},
+		registration("Add", &ast.CallExpr{Fun: &ast.Ident{Name: "append"}, Args: []ast.Expr{&ast.StarExpr{X: list}, listener}}),
+		registration("Remove", runtimeCall("RemoveListener", &ast.StarExpr{X: list}, listener)),
+		&ast.FuncDecl{
+			Recv: &ast.FieldList{List: []*ast.Field{{Names: []*ast.Ident{list}, Type: &ast.Ident{Name: name}}}},
+			Name: method.Names[0],
+			Type: method.Type.(*ast.FuncType),
+			Body: &ast.BlockStmt{List: []ast.Stmt{&ast.RangeStmt{
+				Key:   &ast.Ident{Name: "_"},
+				Value: listener,
+				Tok:   token.DEFINE,
+				X:     list,
+				Body: &ast.BlockStmt{List: []ast.Stmt{&ast.ExprStmt{X: &ast.CallExpr{
+					Fun:      &ast.SelectorExpr{X: listener, Sel: method.Names[0]},
+					Args:     call.Args,
+					Ellipsis: call.Ellipsis,
+				}}}},
+			}}},
+		},
+	}
+}
+
+// lambdaListener returns the listener interface that a lambda implements, from
+// the parameter that it is passed to, or the variable that it is assigned to,
+// or nil if it doesn't implement one
+func lambdaListener(node *sitter.Node, source []byte, ctx Ctx) *symbol.ClassScope {
+	target := functionalTarget(node, source, ctx)
+	if target == "" {
+		return nil
+	}
+	return resolveClassScopeByName(ctx, target)
+}
@@ -0,0 +1,51 @@
+package translate
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestListenerFunctionTypes(t *testing.T) {
+	ListenerPattern = regexp.MustCompile(`Listener$`)
+	defer func() { ListenerPattern = nil }()
+
+	src := `
+package listener.funcs;
+public class Button {
+    public interface ClickListener {
+        void onClick(int x, int y);
+    }
+    public interface Formatter {
+        String format(int x);
+    }
+    public void addClickListener(ClickListener listener) {
+    }
+    public static void main(String[] args) {
+        Button button = new Button();
+        button.addClickListener((x, y) -> System.out.println(x));
+        ClickListener ignore = (x, y) -> {};
+        Formatter formatter = x -> "n" + x;
+    }
+}
+`
+	out := normalizeSpaces(renderGoFileFromJava(t, src))
+	for _, want := range []string{
+		`type ButtonClickListenerFunc func(x int32, y int32)`,
+		`func (f ButtonClickListenerFunc) OnClick(x int32, y int32) { f(x, y) }`,
+		`func (bn *Button) AddClickListener(listener ButtonClickListener)`,
+		`button.addClickListener(ButtonClickListenerFunc(func(x int32, y int32) { fmt.Println(x) }))`,
+		`ignore := ButtonClickListenerFunc(func(x int32, y int32) { })`,
+		`type ButtonClickListenerList []ButtonClickListener`,
+		`func (l *ButtonClickListenerList) Add(listener ButtonClickListener) { *l = append(*l, listener) }`,
+		`func (l *ButtonClickListenerList) Remove(listener ButtonClickListener) { *l = stdjava.RemoveListener(*l, listener) }`,
+		`func (l ButtonClickListenerList) OnClick(x int32, y int32) { for _, listener := range l { listener.OnClick(x, y) } }`,
+	} {
+		if !strings.Contains(out, normalizeSpaces(want)) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "ButtonFormatterFunc") || strings.Contains(out, "ButtonFormatterList") {
+		t.Errorf("expected interfaces that don't match the pattern to be left alone, got:\n%s", out)
+	}
+}
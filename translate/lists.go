@@ -0,0 +1,296 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/NickyBoy89/java2go/astutil"
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for the methods of a list that aren't
+// translated, and for the lists that are added to where the caller can't see it
+const listDiagnostic = "list"
+
+// With `-native-collections`, a `List` or an `ArrayList` is a Go slice of its
+// elements, the same as a `Map` is a Go map, and a `Set` is the runtime's
+// `HashSet`, which is a map of its elements to empty values. The methods that
+// change the length of a list append to, insert into, or delete from the slice,
+// and assign it back, so they are only translated as statements:
+//
+//	names.add(name);            names = append(names, name)
+//	names.add(0, name);         names = slices.Insert(names, 0, name)
+//	names.remove(0);            names = slices.Delete(names, 0, 0+1)
+//	names.set(0, name);         names[0] = name
+//	names.get(0)                names[0]
+//	names.contains(name)        slices.Contains(names, name)
+//
+// A slice that is appended to can be moved to a new array, which the slice
+// that it was copied from doesn't see, so a method that adds to a list that it
+// is passed doesn't add to the caller's list. These are reported
+
+// listTypeOf returns the Java type of the elements of a Java type, if it is
+// translated into a slice
+func listTypeOf(javaType string) (string, bool) {
+	if !astutil.NativeCollections {
+		return "", false
+	}
+	base, typeArgs := parseJavaTypeString(javaType)
+	switch stripJavaQualifier(base) {
+	case "List", "ArrayList":
+		if len(typeArgs) == 1 {
+			return typeArgs[0], true
+		}
+	}
+	return "", false
+}
+
+// listExprType returns the Java type of the elements of the list that an
+// expression evaluates to, if it evaluates to one that is a slice
+func listExprType(node *sitter.Node, ctx Ctx, source []byte) (string, bool) {
+	if !astutil.NativeCollections {
+		return "", false
+	}
+	switch node.Type() {
+	case "parenthesized_expression":
+		return listExprType(node.NamedChild(0), ctx, source)
+	case "object_creation_expression":
+		return listTypeOf(node.ChildByFieldName("type").Content(source))
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	if !ok {
+		return "", false
+	}
+	return listTypeOf(javaType)
+}
+
+// listMethodJavaType returns the Java type of the element that `get` returns
+// from a list that is a slice
+func listMethodJavaType(node *sitter.Node, ctx Ctx, source []byte) (string, bool) {
+	object := node.ChildByFieldName("object")
+	if object == nil || node.ChildByFieldName("name").Content(source) != "get" {
+		return "", false
+	}
+	return listExprType(object, ctx, source)
+}
+
+// sliceIndex converts the index of an element of a list to the `int` that the
+// functions of the slices package take
+func sliceIndex(node *sitter.Node, index ast.Expr, source []byte) ast.Expr {
+	if isUntypedConstant(node, source) {
+		return index
+	}
+	return convertTo("int", index)
+}
+
+// slicesCall calls one of the functions of the slices package
+func slicesCall(function string, args ...ast.Expr) *ast.CallExpr {
+	return &ast.CallExpr{Fun: &ast.SelectorExpr{X: &ast.Ident{Name: "slices"}, Sel: &ast.Ident{Name: function}}, Args: args}
+}
+
+// listMethod translates a method of a list that is a slice, and whose result
+// is used, or `List.of`, or returns nil if the method isn't one of them
+//
+// Ex: `names.size()` turns into `int32(len(names))`, and
+// `names.indexOf(name)` turns into `int32(slices.Index(names, name))`
+func listMethod(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	objectNode := node.ChildByFieldName("object")
+	name := node.ChildByFieldName("name").Content(source)
+	arguments := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+
+	// The type of the elements comes from the variable that the list is
+	// assigned to, since the elements may be untyped constants
+	if objectNode.Content(source) == "List" && name == "of" {
+		element, ok := listTypeOf(ctx.expectedType)
+		if !ok {
+			return nil
+		}
+		for ind, argument := range arguments {
+			args[ind] = elementValue(argument, args[ind], element, false, ctx, source)
+		}
+		return &ast.CompositeLit{Type: &ast.ArrayType{Elt: elementType(element, false, ctx)}, Elts: args}
+	}
+
+	element, ok := listExprType(objectNode, ctx, source)
+	if !ok {
+		return nil
+	}
+	return listMethodOn(node, object, args, element, ctx, source)
+}
+
+// listMethodOn translates a method of a list that is a slice, which is called
+// on the given object, with the given type of elements
+func listMethodOn(node *sitter.Node, object ast.Expr, args []ast.Expr, element string, ctx Ctx, source []byte) ast.Expr {
+	name := node.ChildByFieldName("name").Content(source)
+	arguments := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+	if (name == "contains" || name == "indexOf") && len(args) == 1 {
+		args[0] = elementValue(arguments[0], args[0], element, false, ctx, source)
+	}
+
+	switch {
+	case name == "get" && len(args) == 1:
+		return &ast.IndexExpr{X: object, Index: args[0]}
+	case name == "size" && len(args) == 0:
+		return &ast.CallExpr{Fun: &ast.Ident{Name: "int32"}, Args: []ast.Expr{&ast.CallExpr{Fun: &ast.Ident{Name: "len"}, Args: []ast.Expr{object}}}}
+	case name == "isEmpty" && len(args) == 0:
+		return &ast.BinaryExpr{
+			X:  &ast.CallExpr{Fun: &ast.Ident{Name: "len"}, Args: []ast.Expr{object}},
+			Op: token.EQL,
+			Y:  &ast.BasicLit{Kind: token.INT, Value: "0"},
+		}
+	case name == "contains" && len(args) == 1:
+		return slicesCall("Contains", object, args[0])
+	case name == "indexOf" && len(args) == 1:
+		return &ast.CallExpr{Fun: &ast.Ident{Name: "int32"}, Args: []ast.Expr{slicesCall("Index", object, args[0])}}
+	}
+
+	switch name {
+	case "add", "addAll", "set", "remove", "clear":
+		ctx.diagnostics.Report(node, listDiagnostic, "`%s` is only translated as a statement of its own, since the list is a Go slice", node.Content(source))
+	default:
+		ctx.diagnostics.Report(node, listDiagnostic, "`%s` isn't translated for a list, which is a Go slice", node.Content(source))
+	}
+	return nil
+}
+
+// listStmt translates a method of a list that is a slice, that is the whole of
+// an expression statement, and whose result isn't used, into a statement, or
+// returns nil if the statement isn't one
+//
+// Ex: `names.add(name);` turns into `names = append(names, name)`
+func listStmt(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	if node.Type() != "method_invocation" || node.ChildByFieldName("object") == nil {
+		return nil
+	}
+	objectNode := node.ChildByFieldName("object")
+	// The slice can only be assigned back to a variable or a field
+	if objectNode.Type() != "identifier" && objectNode.Type() != "field_access" {
+		return nil
+	}
+	element, ok := listExprType(objectNode, ctx, source)
+	if !ok {
+		return nil
+	}
+	stmt := listStmtOn(node, func() ast.Expr { return ParseExpr(objectNode, source, ctx) }, element, source, ctx)
+
+	// A list that is passed to the method is the caller's slice, which is only
+	// added to here
+	name := node.ChildByFieldName("name").Content(source)
+	if stmt != nil && objectNode.Type() == "identifier" && ctx.localScope != nil && name != "set" {
+		for _, param := range ctx.localScope.Parameters {
+			if param.OriginalName == objectNode.Content(source) {
+				ctx.diagnostics.Report(node, listDiagnostic, "`%s` changes the parameter's slice, which the caller doesn't see, since the list is a Go slice", node.Content(source))
+			}
+		}
+	}
+	return stmt
+}
+
+// listStmtOn translates a method of a list into a statement, like listStmt,
+// for a list that is given by where it is stored, and the type of its elements
+func listStmtOn(node *sitter.Node, parseObject func() ast.Expr, element string, source []byte, ctx Ctx) ast.Stmt {
+	name := node.ChildByFieldName("name").Content(source)
+	switch name {
+	case "add", "addAll", "set", "remove", "clear":
+	default:
+		return nil
+	}
+	arguments := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+	args := make([]ast.Expr, len(arguments))
+	for ind, argument := range arguments {
+		args[ind] = ParseExpr(argument, source, ctx)
+	}
+
+	// The slice is assigned back to where the list is stored
+	assign := func(value ast.Expr) ast.Stmt {
+		return &ast.AssignStmt{Lhs: []ast.Expr{parseObject()}, Tok: token.ASSIGN, Rhs: []ast.Expr{value}}
+	}
+	var stmt ast.Stmt
+	switch {
+	case name == "add" && len(args) == 1:
+		stmt = assign(&ast.CallExpr{Fun: &ast.Ident{Name: "append"}, Args: []ast.Expr{parseObject(), elementValue(arguments[0], args[0], element, false, ctx, source)}})
+	case name == "add" && len(args) == 2:
+		stmt = assign(slicesCall("Insert", parseObject(), sliceIndex(arguments[0], args[0], source), elementValue(arguments[1], args[1], element, false, ctx, source)))
+	case name == "addAll" && len(args) == 1:
+		var elements []ast.Expr
+		spread := true
+		if _, ok := listExprType(arguments[0], ctx, source); ok {
+			elements = args
+		} else {
+			elements, spread = collectionElements(arguments[0], args[0], element, ctx, source)
+		}
+		appended := &ast.CallExpr{Fun: &ast.Ident{Name: "append"}, Args: append([]ast.Expr{parseObject()}, elements...)}
+		if spread {
+			appended.Ellipsis = 1
+		}
+		stmt = assign(appended)
+	case name == "set" && len(args) == 2:
+		return &ast.AssignStmt{
+			Lhs: []ast.Expr{&ast.IndexExpr{X: parseObject(), Index: args[0]}},
+			Tok: token.ASSIGN,
+			Rhs: []ast.Expr{elementValue(arguments[1], args[1], element, false, ctx, source)},
+		}
+	case name == "remove" && len(args) == 1 && integralType(arguments[0], ctx, source) != "" && integralType(arguments[0], ctx, source) != "long":
+		// An `int` is the index of the element that is removed, and anything
+		// else is the element itself
+		index := sliceIndex(arguments[0], args[0], source)
+		stmt = assign(slicesCall("Delete", parseObject(), index,
+			&ast.BinaryExpr{X: index, Op: token.ADD, Y: &ast.BasicLit{Kind: token.INT, Value: "1"}}))
+	case name == "remove" && len(args) == 1:
+		// Only the first of the equal elements is removed
+		index := &ast.Ident{Name: localVariableNames(ctx).Allocate("ind")}
+		stmt = &ast.IfStmt{
+			Init: &ast.AssignStmt{
+				Lhs: []ast.Expr{index},
+				Tok: token.DEFINE,
+				Rhs: []ast.Expr{slicesCall("Index", parseObject(), elementValue(arguments[0], args[0], element, false, ctx, source))},
+			},
+			Cond: &ast.BinaryExpr{X: index, Op: token.NEQ, Y: &ast.UnaryExpr{Op: token.SUB, X: &ast.BasicLit{Kind: token.INT, Value: "1"}}},
+			Body: &ast.BlockStmt{List: []ast.Stmt{assign(slicesCall("Delete", parseObject(), index,
+				&ast.BinaryExpr{X: index, Op: token.ADD, Y: &ast.BasicLit{Kind: token.INT, Value: "1"}}))}},
+		}
+	case name == "clear" && len(args) == 0:
+		stmt = assign(&ast.SliceExpr{X: parseObject(), High: &ast.BasicLit{Kind: token.INT, Value: "0"}})
+	default:
+		return nil
+	}
+	return stmt
+}
+
+// newList translates the creation of a list that is a slice, with the type
+// arguments that it is created with, or returns nil if the created object
+// isn't one
+//
+// Ex: `new ArrayList<>()` turns into `[]string{}`, and `new ArrayList<>(names)`
+// turns into `slices.Clone(names)`
+func newList(node *sitter.Node, className string, typeArgs []string, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	// A list with an anonymous class is created as the class, unless the class
+	// only initializes it
+	if !astutil.NativeCollections || stripJavaQualifier(className) != "ArrayList" || !isCreatedInPlace(node) {
+		return nil
+	}
+	// The list's type has to be known, since it can't be inferred from the
+	// arguments
+	if len(typeArgs) != 1 {
+		ctx.diagnostics.Report(node, listDiagnostic, "the type arguments of `%s` aren't known", node.Content(source))
+		return nil
+	}
+	sliceType := &ast.ArrayType{Elt: elementType(typeArgs[0], false, ctx)}
+
+	arguments := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+	if len(args) == 0 {
+		return &ast.CompositeLit{Type: sliceType}
+	}
+	if isCapacity(arguments[0], ctx, source) {
+		return &ast.CallExpr{Fun: &ast.Ident{Name: "make"}, Args: []ast.Expr{sliceType, &ast.BasicLit{Kind: token.INT, Value: "0"}, args[0]}}
+	}
+	if _, ok := listExprType(arguments[0], ctx, source); ok {
+		return slicesCall("Clone", args[0])
+	}
+	elements, spread := collectionElements(arguments[0], args[0], typeArgs[0], ctx, source)
+	if spread {
+		return &ast.CallExpr{Fun: &ast.Ident{Name: "append"}, Args: append([]ast.Expr{&ast.CompositeLit{Type: sliceType}}, elements...), Ellipsis: 1}
+	}
+	return &ast.CompositeLit{Type: sliceType, Elts: elements}
+}
@@ -0,0 +1,76 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NickyBoy89/java2go/astutil"
+)
+
+const listsSource = `
+package util.lists;
+import java.util.*;
+public class Names {
+    public int count(List<Integer> xs, Integer boxed) {
+        List<Integer> copy = new ArrayList<>(xs);
+        ArrayList<String> more = new ArrayList<>(10);
+        List<String> fixed = List.of("a", "b");
+        copy.add(boxed);
+        copy.add(0, 4);
+        copy.set(1, 5);
+        copy.remove(0);
+        more.remove("a");
+        more.addAll(fixed);
+        xs.add(1);
+        int total = 0;
+        if (copy.contains(3) && !more.isEmpty()) {
+            total += copy.get(0) + copy.indexOf(4);
+        }
+        copy.clear();
+        return total + copy.size();
+    }
+}
+`
+
+func TestNativeLists(t *testing.T) {
+	astutil.NativeCollections = true
+	defer func() { astutil.NativeCollections = false }()
+
+	helper := setupParseHelper(t, listsSource)
+	helper.Ctx.diagnostics = NewDiagnostics("Names.java")
+	out := renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		`func (ns *Names) Count(xs []int32, boxed *int32) int32`,
+		`copy := slices.Clone(xs)`,
+		`more := make([]string, 0, 10)`,
+		`fixed := []string{"a", "b"}`,
+		`copy = append(copy, *boxed)`,
+		`copy = slices.Insert(copy, 0, 4)`,
+		`copy[1] = 5`,
+		`copy = slices.Delete(copy, 0, 0+1)`,
+		`if ind := slices.Index(more, "a"); ind != -1 {`,
+		`more = slices.Delete(more, ind, ind+1)`,
+		`more = append(more, fixed...)`,
+		`slices.Contains(copy, 3) && !(len(more) == 0)`,
+		`copy[0] + int32(slices.Index(copy, 4))`,
+		`copy = copy[:0]`,
+		`return total + int32(len(copy))`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	// Only the list that was passed in is reported
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != listDiagnostic || items[0].Line != 15 {
+		t.Errorf("Expected the parameter that is added to to be reported on line 15, got %v", items)
+	}
+}
+
+func TestListsWithoutNativeCollections(t *testing.T) {
+	out := renderGoFileFromJava(t, listsSource)
+	if strings.Contains(out, "slices.") || !strings.Contains(out, "xs *List[*int32]") {
+		t.Errorf("Expected the lists to be left as classes, got:\n%s", out)
+	}
+}
@@ -0,0 +1,338 @@
+package translate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Java and Go disagree on how string and character literals are escaped, so
+// literals are decoded into the UTF-16 code units that Java would see, and
+// then re-encoded as Go literals
+//
+// Some of the differences are:
+//   - Java's unicode escapes (`\u0041`) are translated before the source is
+//     even parsed, so they can stand in for any character, including quotes
+//     and backslashes, and can be written with any number of `u`s
+//   - Java supports octal escapes of one to three digits (`\7`, `\101`),
+//     while Go's octal escapes are always three digits
+//   - Java strings are UTF-16, so they can contain surrogate halves that have
+//     no valid UTF-8 encoding
+
+// translateUnicodeEscapes replaces every unicode escape in Java source code
+// with the character that it represents, as the Java compiler does before
+// parsing
+//
+// A backslash only starts a unicode escape if it is not escaped itself
+func translateUnicodeEscapes(content string) ([]uint16, error) {
+	var units []uint16
+	for ind := 0; ind < len(content); {
+		if content[ind] == '\\' && ind+1 < len(content) && content[ind+1] == 'u' {
+			// The escape can contain any number of `u`s
+			end := ind + 1
+			for end < len(content) && content[end] == 'u' {
+				end++
+			}
+			if end+4 > len(content) {
+				return nil, fmt.Errorf("unterminated unicode escape in %q", content)
+			}
+			value, err := strconv.ParseUint(content[end:end+4], 16, 16)
+			if err != nil {
+				return nil, fmt.Errorf("invalid unicode escape in %q: %w", content, err)
+			}
+			units = append(units, uint16(value))
+			ind = end + 4
+			continue
+		}
+
+		// An escaped backslash can't start a unicode escape
+		if content[ind] == '\\' && ind+1 < len(content) && content[ind+1] == '\\' {
+			units = append(units, '\\', '\\')
+			ind += 2
+			continue
+		}
+
+		char, size := utf8.DecodeRuneInString(content[ind:])
+		units = append(units, utf16.Encode([]rune{char})...)
+		ind += size
+	}
+	return units, nil
+}
+
+// The characters that can follow a backslash in a Java escape sequence
+var javaEscapes = map[uint16]uint16{
+	'b':  '\b',
+	't':  '\t',
+	'n':  '\n',
+	'f':  '\f',
+	'r':  '\r',
+	's':  ' ',
+	'"':  '"',
+	'\'': '\'',
+	'\\': '\\',
+}
+
+// decodeJavaLiteral decodes the contents of a Java string or character
+// literal, without its quotes, into UTF-16 code units
+func decodeJavaLiteral(content string) ([]uint16, error) {
+	units, err := translateUnicodeEscapes(content)
+	if err != nil {
+		return nil, err
+	}
+	return decodeEscapeSequences(units, content)
+}
+
+// decodeEscapeSequences replaces the escape sequences in the code units of a
+// literal, whose unicode escapes have already been translated, with the
+// characters that they represent
+func decodeEscapeSequences(units []uint16, content string) ([]uint16, error) {
+	var decoded []uint16
+	for ind := 0; ind < len(units); ind++ {
+		if units[ind] != '\\' {
+			decoded = append(decoded, units[ind])
+			continue
+		}
+		ind++
+		if ind >= len(units) {
+			return nil, fmt.Errorf("unterminated escape sequence in %q", content)
+		}
+
+		if escaped, ok := javaEscapes[units[ind]]; ok {
+			decoded = append(decoded, escaped)
+			continue
+		}
+		// A backslash at the end of a line of a text block joins it to the next
+		if units[ind] == '\n' {
+			continue
+		}
+
+		// Octal escapes have up to three digits, but can only go up to `\377`
+		if isOctalDigit(units[ind]) {
+			maxDigits := 2
+			if units[ind] <= '3' {
+				maxDigits = 3
+			}
+			var value uint16
+			for digits := 0; digits < maxDigits && ind < len(units) && isOctalDigit(units[ind]); digits++ {
+				value = value*8 + units[ind] - '0'
+				ind++
+			}
+			decoded = append(decoded, value)
+			ind--
+			continue
+		}
+
+		return nil, fmt.Errorf("invalid escape sequence \\%c in %q", rune(units[ind]), content)
+	}
+	return decoded, nil
+}
+
+func isOctalDigit(unit uint16) bool {
+	return unit >= '0' && unit <= '7'
+}
+
+// encodeUTF16AsUTF8 converts a Java string into the bytes of a Go string
+//
+// Surrogate halves that aren't part of a pair have no valid UTF-8 encoding, so
+// they are encoded the same way that a valid code point would be, which keeps
+// them distinct from each other
+func encodeUTF16AsUTF8(units []uint16) string {
+	var result strings.Builder
+	for ind := 0; ind < len(units); ind++ {
+		unit := rune(units[ind])
+		if utf16.IsSurrogate(unit) && ind+1 < len(units) {
+			if paired := utf16.DecodeRune(unit, rune(units[ind+1])); paired != utf8.RuneError {
+				result.WriteRune(paired)
+				ind++
+				continue
+			}
+		}
+		if utf16.IsSurrogate(unit) {
+			result.Write([]byte{
+				0xE0 | byte(unit>>12),
+				0x80 | byte(unit>>6)&0x3F,
+				0x80 | byte(unit)&0x3F,
+			})
+			continue
+		}
+		result.WriteRune(unit)
+	}
+	return result.String()
+}
+
+// stringLiteral re-encodes a Java string literal as a Go string literal
+//
+// Ex: `"\101B"` turns into `"AB"`
+func stringLiteral(content string) (ast.Expr, error) {
+	units, err := decodeJavaLiteral(strings.TrimSuffix(strings.TrimPrefix(content, `"`), `"`))
+	if err != nil {
+		return nil, err
+	}
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(encodeUTF16AsUTF8(units))}, nil
+}
+
+// charLiteral re-encodes a Java character literal as a Go rune literal
+//
+// A surrogate half isn't a valid rune literal, so it is written as a number
+func charLiteral(content string) (ast.Expr, error) {
+	units, err := decodeJavaLiteral(strings.TrimSuffix(strings.TrimPrefix(content, "'"), "'"))
+	if err != nil {
+		return nil, err
+	}
+	if len(units) != 1 {
+		return nil, fmt.Errorf("character literal %s does not contain a single character", content)
+	}
+	if utf16.IsSurrogate(rune(units[0])) {
+		return &ast.BasicLit{Kind: token.INT, Value: fmt.Sprintf("0x%X", units[0])}, nil
+	}
+	return &ast.BasicLit{Kind: token.CHAR, Value: strconv.QuoteRune(rune(units[0]))}, nil
+}
+
+// textBlockLiteral re-encodes a Java text block as a Go raw string literal,
+// with the same value as the text block has in Java
+//
+// The incidental whitespace of the text block is stripped the same way as
+// Java does, before its escape sequences are decoded. A raw string can't hold
+// a backtick, so a value with backticks is a concatenation of raw strings and
+// the backticks in between them, and a value that a raw string can't hold at
+// all, such as one with a carriage return, is a regular string literal
+//
+// Ex:
+//
+//	"""                          `<p>
+//	    <p>                          "hi"
+//	        "hi"                 </p>
+//	    </p>                     `
+//	    """
+func textBlockLiteral(content string) (ast.Expr, error) {
+	units, err := translateUnicodeEscapes(strings.TrimSuffix(strings.TrimPrefix(content, `"""`), `"""`))
+	if err != nil {
+		return nil, err
+	}
+	lines := textBlockLines(units)
+	if lines == nil {
+		return nil, fmt.Errorf("text block %s doesn't start with a line terminator", content)
+	}
+
+	// The text block starts on the line after its opening delimiter
+	lines = lines[1:]
+	indent := -1
+	for ind, line := range lines {
+		// The line of the closing delimiter counts even if it is blank
+		if isBlankLine(line) && ind != len(lines)-1 {
+			continue
+		}
+		if width := leadingWhitespace(line); indent == -1 || width < indent {
+			indent = width
+		}
+	}
+
+	var stripped []uint16
+	for ind, line := range lines {
+		if ind > 0 {
+			stripped = append(stripped, '\n')
+		}
+		if isBlankLine(line) {
+			continue
+		}
+		line = line[indent:]
+		for len(line) > 0 && isJavaWhitespace(line[len(line)-1]) {
+			line = line[:len(line)-1]
+		}
+		stripped = append(stripped, line...)
+	}
+
+	decoded, err := decodeEscapeSequences(stripped, content)
+	if err != nil {
+		return nil, err
+	}
+	return rawStringLiteral(encodeUTF16AsUTF8(decoded)), nil
+}
+
+// textBlockLines splits the code units of a text block into its lines, where
+// any line terminator ends a line, or returns nil if the opening delimiter
+// isn't followed by a line terminator
+func textBlockLines(units []uint16) [][]uint16 {
+	var lines [][]uint16
+	var line []uint16
+	for ind := 0; ind < len(units); ind++ {
+		if units[ind] != '\n' && units[ind] != '\r' {
+			line = append(line, units[ind])
+			continue
+		}
+		if units[ind] == '\r' && ind+1 < len(units) && units[ind+1] == '\n' {
+			ind++
+		}
+		lines = append(lines, line)
+		line = nil
+	}
+	if len(lines) == 0 || !isBlankLine(lines[0]) {
+		return nil
+	}
+	return append(lines, line)
+}
+
+// isJavaWhitespace determines if a code unit is whitespace to Java, which
+// doesn't count the non-breaking spaces
+func isJavaWhitespace(unit uint16) bool {
+	switch unit {
+	case 0xA0, 0x2007, 0x202F:
+		return false
+	case 0x1C, 0x1D, 0x1E, 0x1F:
+		return true
+	}
+	return unicode.IsSpace(rune(unit))
+}
+
+func isBlankLine(line []uint16) bool {
+	return leadingWhitespace(line) == len(line)
+}
+
+func leadingWhitespace(line []uint16) int {
+	width := 0
+	for width < len(line) && isJavaWhitespace(line[width]) {
+		width++
+	}
+	return width
+}
+
+// rawStringLiteral writes a string as a Go raw string literal, or as raw
+// strings that are concatenated with the backticks in between them, or as a
+// regular string literal if a raw string can't hold it
+func rawStringLiteral(value string) ast.Expr {
+	if !utf8.ValidString(value) || strings.ContainsFunc(value, func(char rune) bool {
+		return char != '\n' && char != '\t' && !unicode.IsPrint(char)
+	}) {
+		return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(value)}
+	}
+
+	var parts []ast.Expr
+	for value != "" {
+		part := value
+		if tick := strings.IndexByte(value, '`'); tick == 0 {
+			// A run of backticks is a single regular string
+			part = value[:len(value)-len(strings.TrimLeft(value, "`"))]
+			parts = append(parts, &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(part)})
+			value = value[len(part):]
+			continue
+		} else if tick > 0 {
+			part = value[:tick]
+		}
+		parts = append(parts, &ast.BasicLit{Kind: token.STRING, Value: "`" + part + "`"})
+		value = value[len(part):]
+	}
+	if len(parts) == 0 {
+		return &ast.BasicLit{Kind: token.STRING, Value: "``"}
+	}
+
+	concatenated := parts[0]
+	for _, part := range parts[1:] {
+		concatenated = &ast.BinaryExpr{X: concatenated, Op: token.ADD, Y: part}
+	}
+	return concatenated
+}
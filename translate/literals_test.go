@@ -0,0 +1,131 @@
+package translate
+
+import (
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestStringLiteral(t *testing.T) {
+	tests := []struct {
+		name     string
+		java     string
+		expected string
+	}{
+		{"plain", `"hello"`, `"hello"`},
+		{"simple escapes", `"a\tb\n\"c\"\\"`, `"a\tb\n\"c\"\\"`},
+		{"space escape", `"a\sb"`, `"a b"`},
+		{"octal escapes", `"\101\7\0\12x\400"`, `"A\a\x00\nx 0"`},
+		{"unicode escape", `"caf\u00e9"`, `"café"`},
+		{"unicode escape with extra u", `"\uuu0041"`, `"A"`},
+		{"escaped backslash before u", `"\\u0041"`, `"\\u0041"`},
+		{"unicode escaped quote", `"say \u0022hi\u0022"`, `"say \"hi\""`},
+		{"unicode escaped backslash", `"\u005cn"`, `"\n"`},
+		{"surrogate pair", `"\uD83D\uDE00"`, `"😀"`},
+		{"lone surrogate", `"\uD800"`, `"\xed\xa0\x80"`},
+		{"non printable", `"\u0001"`, `"\x01"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			literal, err := stringLiteral(test.java)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := literal.(*ast.BasicLit).Value; got != test.expected {
+				t.Errorf("Converted %s into %s, expected %s", test.java, got, test.expected)
+			}
+		})
+	}
+}
+
+func TestCharLiteral(t *testing.T) {
+	tests := []struct {
+		java     string
+		expected string
+	}{
+		{`'a'`, `'a'`},
+		{`'\''`, `'\''`},
+		{`'"'`, `'"'`},
+		{`'\\'`, `'\\'`},
+		{`'\0'`, `'\x00'`},
+		{`'é'`, `'é'`},
+		{`'\uD800'`, `0xD800`},
+	}
+
+	for _, test := range tests {
+		literal, err := charLiteral(test.java)
+		if err != nil {
+			t.Fatalf("Unexpected error converting %s: %v", test.java, err)
+		}
+		if got := literal.(*ast.BasicLit).Value; got != test.expected {
+			t.Errorf("Converted %s into %s, expected %s", test.java, got, test.expected)
+		}
+	}
+}
+
+func TestStringLiteral_InvalidEscape(t *testing.T) {
+	if _, err := stringLiteral(`"\q"`); err == nil {
+		t.Errorf("Expected an invalid escape sequence to be an error")
+	}
+	if _, err := stringLiteral(`"\u12"`); err == nil {
+		t.Errorf("Expected an unterminated unicode escape to be an error")
+	}
+}
+
+func TestStringLiteral_InGeneratedCode(t *testing.T) {
+	src := `
+package literals.escapes;
+public class Greeter {
+    String greet() {
+        char sep = '\u002c';
+        return "caf\u00e9" + sep + "\101";
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, `sep := ','`) {
+		t.Errorf("Expected the character literal to be re-encoded, got:\n%s", out)
+	}
+	if !strings.Contains(out, `return "café" + sep + "A"`) {
+		t.Errorf("Expected the string literals to be re-encoded, got:\n%s", out)
+	}
+}
+
+func TestTextBlockLiteral(t *testing.T) {
+	tests := []struct {
+		name     string
+		java     string
+		expected string
+	}{
+		{"closing delimiter on its own line", "\"\"\"\n    <p>\n        hi\n    </p>\n    \"\"\"", "`<p>\n    hi\n</p>\n`"},
+		{"closing delimiter after the text", "\"\"\"\n    one\n      two\"\"\"", "`one\n  two`"},
+		{"closing delimiter sets the indentation", "\"\"\"\n    one\n  \"\"\"", "`  one\n`"},
+		{"trailing and blank lines", "\"\"\"  \n  a   \n\n     \n  b\"\"\"", "`a\n\n\nb`"},
+		{"escapes after stripping", "\"\"\"\n  a\\s\n  \\\"q\\\"\\tb\\\n  c\"\"\"", "`a \n\"q\"\tbc`"},
+		{"carriage returns", "\"\"\"\r\n  a\r\n  b\"\"\"", "`a\nb`"},
+		{"backticks", "\"\"\"\n  run `ls` ``\"\"\"", "`run ` + \"`\" + `ls` + \"`\" + ` ` + \"``\""},
+		{"escaped carriage return", "\"\"\"\n  a\\r\"\"\"", `"a\r"`},
+		{"empty", "\"\"\"\n\"\"\"", "``"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			literal, err := textBlockLiteral(test.java)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var out strings.Builder
+			printer.Fprint(&out, token.NewFileSet(), literal)
+			if out.String() != test.expected {
+				t.Errorf("Converted %q into %q, expected %q", test.java, out.String(), test.expected)
+			}
+		})
+	}
+
+	if _, err := textBlockLiteral(`"""a"""`); err == nil {
+		t.Errorf("Expected a text block without a line terminator after its opening delimiter to be an error")
+	}
+}
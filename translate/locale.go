@@ -0,0 +1,162 @@
+package translate
+
+import (
+	"go/ast"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for anything that depends on a locale
+const localeDiagnostic = "locale"
+
+// The constants of Java's `Locale`, and their names in the runtime
+var localeConstants = map[string]string{
+	"ROOT":    "LocaleRoot",
+	"ENGLISH": "LocaleEnglish",
+	"US":      "LocaleUS",
+	"UK":      "LocaleUK",
+	"CANADA":  "LocaleCanada",
+	"FRENCH":  "LocaleFrench",
+	"FRANCE":  "LocaleFrance",
+	"GERMAN":  "LocaleGerman",
+	"GERMANY": "LocaleGermany",
+	"ITALIAN": "LocaleItalian",
+	"ITALY":   "LocaleItaly",
+}
+
+// The classes that format dates and times for a locale, which have no
+// equivalent in the runtime
+var dateFormatClasses = map[string]bool{
+	"DateFormat":        true,
+	"SimpleDateFormat":  true,
+	"DateTimeFormatter": true,
+}
+
+// runtimeLocale returns the runtime's value for the given locale, which is its
+// default locale if there is none
+func runtimeLocale(locale ...ast.Expr) ast.Expr {
+	if len(locale) > 0 {
+		return locale[0]
+	}
+	return &ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: "DefaultLocale"}}
+}
+
+// localeConstant translates one of the constants of `Locale`, such as
+// `Locale.US`, or returns nil if the field isn't one of them
+func localeConstant(node *sitter.Node, source []byte) ast.Expr {
+	if node.Type() != "field_access" || node.ChildByFieldName("object").Content(source) != "Locale" {
+		return nil
+	}
+	if name, ok := localeConstants[node.ChildByFieldName("field").Content(source)]; ok {
+		return &ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: name}}
+	}
+	return nil
+}
+
+// localeMethod translates the methods that depend on a locale, which call the
+// runtime's implementation of locales, or returns nil if the method isn't one
+// of them
+//
+// Everything that the locale changes the result of is reported, since the
+// runtime only knows about the rules of a few locales, and uses
+// `stdjava.DefaultLocale` in place of the default locale of the JVM
+func localeMethod(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	objectNode := node.ChildByFieldName("object")
+	argumentNodes := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+	name := node.ChildByFieldName("name").Content(source)
+
+	switch objectNode.Content(source) + "." + name {
+	case "Locale.getDefault":
+		return runtimeLocale()
+	case "Locale.forLanguageTag":
+		if len(args) == 1 {
+			return runtimeCall("LocaleForLanguageTag", args[0])
+		}
+	// Ex: `NumberFormat.getInstance(Locale.GERMANY)` turns into
+	// `stdjava.NumberInstance(stdjava.LocaleGermany)`
+	case "NumberFormat.getInstance", "NumberFormat.getNumberInstance":
+		if len(args) <= 1 {
+			ctx.diagnostics.Report(node, localeDiagnostic,
+				"`%s` formats numbers for a locale, which the runtime only knows the separators of for a few languages",
+				node.Content(source))
+			return runtimeCall("NumberInstance", runtimeLocale(args...))
+		}
+	case "String.format":
+		if len(argumentNodes) > 0 && isLocaleExpr(argumentNodes[0], ctx, source) {
+			ctx.diagnostics.Report(node, localeDiagnostic,
+				"`%s` formats a string for a locale, which the generated Go ignores", node.Content(source))
+		}
+		return nil
+	}
+
+	if dateFormatClasses[objectNode.Content(source)] {
+		ctx.diagnostics.Report(node, localeDiagnostic,
+			"`%s` formats dates for a locale, which isn't translated", node.Content(source))
+		return nil
+	}
+
+	switch {
+	// Ex: `name.toUpperCase(Locale.ROOT)` turns into
+	// `stdjava.ToUpperCaseIn(name, stdjava.LocaleRoot)`
+	case (name == "toUpperCase" || name == "toLowerCase") && len(args) == 1 && isStringExpr(objectNode, ctx, source):
+		// The constants of `Locale` all convert case without any special rules
+		if localeConstant(argumentNodes[0], source) != nil {
+			return nil
+		}
+		ctx.diagnostics.Report(node, localeDiagnostic,
+			"`%s` converts the case of a string for a locale, which the runtime only knows the rules of Turkish and Azerbaijani for",
+			node.Content(source))
+		function := "ToUpperCaseIn"
+		if name == "toLowerCase" {
+			function = "ToLowerCaseIn"
+		}
+		return runtimeCall(function, object, args[0])
+	// Numbers of any type are formatted as a float64
+	case name == "format" && len(args) == 1 && isNumberFormatExpr(objectNode, ctx, source):
+		value := args[0]
+		if !isFloatingExpr(argumentNodes[0], ctx, source) || floatFormatter(argumentNodes[0], ctx, source) != "FormatDouble" {
+			value = &ast.CallExpr{Fun: &ast.Ident{Name: "float64"}, Args: args}
+		}
+		return &ast.CallExpr{
+			Fun:  &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: "Format"}},
+			Args: []ast.Expr{value},
+		}
+	}
+	return nil
+}
+
+// newLocaleObject translates the creation of a `Locale`, or of an object that
+// formats dates for a locale, which is reported, or returns nil if the created
+// object is neither
+func newLocaleObject(node *sitter.Node, className string, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	switch {
+	// Ex: `new Locale("tr", "TR")` turns into `stdjava.NewLocale("tr", "TR")`
+	case className == "Locale" && len(args) > 0:
+		return runtimeCall("NewLocale", args[:min(len(args), 2)]...)
+	case dateFormatClasses[className]:
+		ctx.diagnostics.Report(node, localeDiagnostic,
+			"`%s` formats dates for a locale, which isn't translated", node.Content(source))
+	}
+	return nil
+}
+
+// isLocaleExpr determines if the given expression evaluates to a `Locale`
+func isLocaleExpr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	if node.Type() == "field_access" && node.ChildByFieldName("object").Content(source) == "Locale" {
+		return true
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	return ok && stripJavaQualifier(javaType) == "Locale"
+}
+
+// isNumberFormatExpr determines if the given expression evaluates to a
+// `NumberFormat`
+func isNumberFormatExpr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	if node.Type() == "method_invocation" && node.ChildByFieldName("object") != nil &&
+		node.ChildByFieldName("object").Content(source) == "NumberFormat" {
+		return true
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	return ok && stripJavaQualifier(javaType) == "NumberFormat"
+}
@@ -0,0 +1,48 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLocaleSensitiveCalls(t *testing.T) {
+	helper := setupParseHelper(t, `
+package locale.calls;
+import java.util.Locale;
+import java.text.NumberFormat;
+public class Calls {
+    public static String run(String s, double d, int n, Locale given) {
+        Locale turkish = new Locale("tr", "TR");
+        String cased = s.toUpperCase(turkish) + s.toLowerCase(Locale.ROOT);
+        NumberFormat format = NumberFormat.getInstance(Locale.GERMANY);
+        return cased + format.format(d) + NumberFormat.getNumberInstance().format(n);
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Calls.java")
+
+	out := renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		"func Run(s string, d float64, n int32, given stdjava.Locale) string",
+		`turkish := stdjava.NewLocale("tr", "TR")`,
+		"cased := stdjava.ToUpperCaseIn(s, turkish) + stdjava.ToLowerCase(s)",
+		"format := stdjava.NumberInstance(stdjava.LocaleGermany)",
+		"format.Format(d) + stdjava.NumberInstance(stdjava.DefaultLocale).Format(float64(n))",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	// Converting the case with one of the constants of `Locale` isn't reported
+	var lines []int
+	for _, item := range helper.Ctx.diagnostics.Items() {
+		if item.Kind != localeDiagnostic {
+			t.Errorf("Expected only locale diagnostics, got %v", item)
+		}
+		lines = append(lines, item.Line)
+	}
+	if len(lines) != 3 || lines[0] != 8 || lines[1] != 9 || lines[2] != 10 {
+		t.Errorf("Expected the locale-sensitive calls on lines 8, 9, and 10 to be reported, got %v", helper.Ctx.diagnostics.Items())
+	}
+}
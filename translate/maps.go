@@ -0,0 +1,416 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/astutil"
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for the methods of a map that aren't
+// translated
+const mapDiagnostic = "map"
+
+// A `Map` or a `HashMap` is a Go map, which is indexed, assigned to, and
+// deleted from directly, and ranged over in place of the views of its keys,
+// values, and entries:
+//
+//	for (Map.Entry<String, Integer> e : counts.entrySet()) {
+//		total += e.getValue();
+//	}
+//
+// becomes
+//
+//	for _, eValue := range counts {
+//		total += eValue
+//	}
+//
+// An entry is only created if it is used for more than its key and its value,
+// such as when it is stored or passed around, as the runtime's `Entry`. The
+// rest of the methods of a map are functions of the runtime, which take the
+// map as their first argument
+
+// The methods of a map that the runtime implements, and their names in the
+// runtime
+var mapFunctions = map[string]string{
+	"put":              "Put",
+	"remove":           "Remove",
+	"containsKey":      "ContainsKey",
+	"containsValue":    "ContainsValue",
+	"getOrDefault":     "GetOrDefault",
+	"putIfAbsent":      "PutIfAbsent",
+	"merge":            "Merge",
+	"compute":          "Compute",
+	"computeIfAbsent":  "ComputeIfAbsent",
+	"computeIfPresent": "ComputeIfPresent",
+	"keySet":           "Keys",
+	"values":           "Values",
+	"entrySet":         "Entries",
+}
+
+// rangedEntry is the entry of a map that is being ranged over, whose key and
+// value are the variables of the range statement
+type rangedEntry struct {
+	key, value         string
+	keyUsed, valueUsed bool
+}
+
+// mapTypeOf returns the Java types of the keys and the values of a Java type,
+// if it is translated into a Go map, which it isn't if its keys are hashed
+func mapTypeOf(javaType string) (string, string, bool) {
+	base, typeArgs := parseJavaTypeString(javaType)
+	switch stripJavaQualifier(base) {
+	case "Map", "HashMap":
+		if len(typeArgs) == 2 && hashedClassFor(base, typeArgs) == "" {
+			return typeArgs[0], typeArgs[1], true
+		}
+	}
+	return "", "", false
+}
+
+// mapExprType returns the Java types of the keys and the values of the map
+// that an expression evaluates to, if it evaluates to one
+func mapExprType(node *sitter.Node, ctx Ctx, source []byte) (string, string, bool) {
+	switch node.Type() {
+	case "parenthesized_expression":
+		return mapExprType(node.NamedChild(0), ctx, source)
+	case "object_creation_expression":
+		return mapTypeOf(node.ChildByFieldName("type").Content(source))
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	if !ok {
+		return "", "", false
+	}
+	return mapTypeOf(javaType)
+}
+
+// isEntryExpr determines if the given expression evaluates to an entry of a
+// map, or one of the entries of a sorted map
+func isEntryExpr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	if isSortedEntryExpr(node, ctx, source) {
+		return true
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	if !ok {
+		return false
+	}
+	base, _ := parseJavaTypeString(javaType)
+	return strings.HasSuffix(base, "Map.Entry")
+}
+
+// mapMethodJavaType returns the Java type of the value that a method of a map,
+// or of one of its entries, returns, for the methods that return one of the
+// map's keys or values
+func mapMethodJavaType(node *sitter.Node, ctx Ctx, source []byte) (string, bool) {
+	object := node.ChildByFieldName("object")
+	if object == nil {
+		return "", false
+	}
+	switch node.ChildByFieldName("name").Content(source) {
+	case "get", "getOrDefault":
+		if _, valueType, ok := mapExprType(object, ctx, source); ok {
+			return valueType, true
+		}
+	case "getKey", "getValue":
+		javaType, ok := inferExprJavaType(object, ctx, source)
+		if !ok {
+			break
+		}
+		base, typeArgs := parseJavaTypeString(javaType)
+		if !strings.HasSuffix(base, "Map.Entry") || len(typeArgs) != 2 {
+			break
+		}
+		if node.ChildByFieldName("name").Content(source) == "getKey" {
+			return typeArgs[0], true
+		}
+		return typeArgs[1], true
+	}
+	return "", false
+}
+
+// mapFunction gives a lambda that is passed to a method of a map the types of
+// the map's keys and values, which Go can't infer, and turns the `sum` methods
+// of the wrapper classes into the runtime's `Sum`
+//
+// Ex: `(a, b) -> a + b` turns into `func(a int32, b int32) int32 { return a + b }`
+func mapFunction(node *sitter.Node, parsed ast.Expr, params []ast.Expr, result ast.Expr, source []byte) ast.Expr {
+	switch node.Type() {
+	case "lambda_expression":
+		lambda, ok := parsed.(*ast.FuncLit)
+		if !ok || lambda.Type.Params.NumFields() != len(params) {
+			return parsed
+		}
+		var index int
+		for _, field := range lambda.Type.Params.List {
+			field.Type = params[index]
+			index += max(len(field.Names), 1)
+		}
+		lambda.Type.Results = &ast.FieldList{List: []*ast.Field{{Type: result}}}
+		// A lambda without a block is the value that it returns
+		if node.ChildByFieldName("body").Type() != "block" && len(lambda.Body.List) == 1 {
+			switch stmt := lambda.Body.List[0].(type) {
+			case *ast.ExprStmt:
+				lambda.Body.List[0] = &ast.ReturnStmt{Results: []ast.Expr{stmt.X}}
+			case *ast.AssignStmt:
+				// An expression that can't be a statement is assigned to `_`
+				if ident, ok := stmt.Lhs[0].(*ast.Ident); ok && ident.Name == "_" && len(stmt.Rhs) == 1 {
+					lambda.Body.List[0] = &ast.ReturnStmt{Results: stmt.Rhs}
+				}
+			}
+		}
+		return lambda
+	case "method_reference":
+		if node.NamedChildCount() == 2 && node.NamedChild(1).Content(source) == "sum" && isBoxedType(node.NamedChild(0).Content(source)) {
+			if boxed, ok := result.(*ast.StarExpr); ok {
+				return applyTypeArguments(&ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: "BoxedSum"}}, []ast.Expr{boxed.X})
+			}
+			return applyTypeArguments(&ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: "Sum"}}, []ast.Expr{result})
+		}
+	}
+	return parsed
+}
+
+// mapMethod translates a method of a map, or of one of its entries, or returns
+// nil if the method isn't called on one of them
+//
+// Ex: `counts.get(word)` turns into `counts[word]`, and
+// `counts.merge(word, 1, Integer::sum)` turns into
+// `stdjava.Merge(counts, word, 1, stdjava.Sum[int32])`
+func mapMethod(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	objectNode := node.ChildByFieldName("object")
+	name := node.ChildByFieldName("name").Content(source)
+
+	if (name == "getKey" || name == "getValue") && len(args) == 0 {
+		if entry, ok := ctx.rangedEntries[objectNode.Content(source)]; ok && objectNode.Type() == "identifier" {
+			if name == "getKey" {
+				entry.keyUsed = true
+				return &ast.Ident{Name: entry.key}
+			}
+			entry.valueUsed = true
+			return &ast.Ident{Name: entry.value}
+		}
+		if isEntryExpr(objectNode, ctx, source) {
+			return &ast.CallExpr{Fun: &ast.SelectorExpr{X: object, Sel: CapitalizeIdent(&ast.Ident{Name: name})}}
+		}
+	}
+	if objectNode.Content(source) == "Map" && name == "entry" && len(args) == 2 {
+		return runtimeCall("NewEntry", args...)
+	}
+
+	keyType, valueType, ok := mapExprType(objectNode, ctx, source)
+	if !ok {
+		return nil
+	}
+	return mapMethodOn(node, object, args, keyType, valueType, ctx, source)
+}
+
+// mapMethodOn translates a method of a map, which is called on the given
+// object, with the given types of keys and values
+func mapMethodOn(node *sitter.Node, object ast.Expr, args []ast.Expr, keyType, valueType string, ctx Ctx, source []byte) ast.Expr {
+	name := node.ChildByFieldName("name").Content(source)
+	arguments := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+	key, value := elementType(keyType, true, ctx), elementType(valueType, false, ctx)
+
+	// The keys and the values that are passed to the method are the map's own
+	switch name {
+	case "get", "containsKey", "remove", "put", "putIfAbsent", "getOrDefault", "merge", "compute", "computeIfAbsent", "computeIfPresent":
+		if len(args) > 0 {
+			args[0] = elementValue(arguments[0], args[0], keyType, true, ctx, source)
+		}
+	case "containsValue":
+		if len(args) == 1 {
+			args[0] = elementValue(arguments[0], args[0], valueType, false, ctx, source)
+		}
+	}
+	switch name {
+	case "put", "putIfAbsent", "getOrDefault", "merge":
+		if len(args) > 1 {
+			args[1] = elementValue(arguments[1], args[1], valueType, false, ctx, source)
+		}
+	}
+
+	switch {
+	case name == "get" && len(args) == 1:
+		return &ast.IndexExpr{X: object, Index: args[0]}
+	case name == "size" && len(args) == 0:
+		return &ast.CallExpr{Fun: &ast.Ident{Name: "int32"}, Args: []ast.Expr{&ast.CallExpr{Fun: &ast.Ident{Name: "len"}, Args: []ast.Expr{object}}}}
+	case name == "isEmpty" && len(args) == 0:
+		return &ast.BinaryExpr{
+			X:  &ast.CallExpr{Fun: &ast.Ident{Name: "len"}, Args: []ast.Expr{object}},
+			Op: token.EQL,
+			Y:  &ast.BasicLit{Kind: token.INT, Value: "0"},
+		}
+	case name == "merge" && len(args) == 3:
+		args[2] = mapFunction(arguments[2], args[2], []ast.Expr{value, value}, value, source)
+	case (name == "compute" || name == "computeIfPresent") && len(args) == 2:
+		args[1] = mapFunction(arguments[1], args[1], []ast.Expr{key, value}, value, source)
+	case name == "computeIfAbsent" && len(args) == 2:
+		args[1] = mapFunction(arguments[1], args[1], []ast.Expr{key}, value, source)
+	case name == "putAll" && len(args) == 1:
+		return &ast.CallExpr{Fun: &ast.SelectorExpr{X: &ast.Ident{Name: "maps"}, Sel: &ast.Ident{Name: "Copy"}}, Args: []ast.Expr{object, args[0]}}
+	case name == "clear" && len(args) == 0:
+		return &ast.CallExpr{Fun: &ast.Ident{Name: "clear"}, Args: []ast.Expr{object}}
+	}
+
+	function, ok := mapFunctions[name]
+	if !ok {
+		ctx.diagnostics.Report(node, mapDiagnostic, "`%s` isn't translated for a map, which is a Go map", node.Content(source))
+		return nil
+	}
+	return runtimeCall(function, append([]ast.Expr{object}, args...)...)
+}
+
+// mapStmt translates a method of a map that is the whole of an expression
+// statement, and whose result isn't used, into a statement, or returns nil if
+// the statement isn't one
+//
+// Ex: `counts.put(word, 1);` turns into `counts[word] = 1`, and
+// `counts.remove(word);` turns into `delete(counts, word)`
+func mapStmt(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	if node.Type() != "method_invocation" || node.ChildByFieldName("object") == nil {
+		return nil
+	}
+	keyType, valueType, ok := mapExprType(node.ChildByFieldName("object"), ctx, source)
+	if !ok {
+		return nil
+	}
+	return mapStmtOn(node, func() ast.Expr { return ParseExpr(node.ChildByFieldName("object"), source, ctx) }, keyType, valueType, source, ctx)
+}
+
+// mapStmtOn translates a method of a map into a statement, like mapStmt, for
+// a map that is given by its object, and the types of its keys and values
+func mapStmtOn(node *sitter.Node, parseObject func() ast.Expr, keyType, valueType string, source []byte, ctx Ctx) ast.Stmt {
+	name := node.ChildByFieldName("name").Content(source)
+	arguments := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+	if !(name == "put" && len(arguments) == 2 || name == "remove" && len(arguments) == 1) {
+		return nil
+	}
+
+	object := parseObject()
+	key := elementValue(arguments[0], ParseExpr(arguments[0], source, ctx), keyType, true, ctx, source)
+	if name == "remove" {
+		return &ast.ExprStmt{X: &ast.CallExpr{Fun: &ast.Ident{Name: "delete"}, Args: []ast.Expr{object, key}}}
+	}
+	return &ast.AssignStmt{
+		Lhs: []ast.Expr{&ast.IndexExpr{X: object, Index: key}},
+		Tok: token.ASSIGN,
+		Rhs: []ast.Expr{elementValue(arguments[1], ParseExpr(arguments[1], source, ctx), valueType, false, ctx, source)},
+	}
+}
+
+// mapRange translates an enhanced for statement over the keys, the values, or
+// the entries of a map into a range over the map, as described above, or
+// returns nil if the statement doesn't iterate over one of them
+func mapRange(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	total := int(node.NamedChildCount())
+	iterated := unwrapParentheses(node.NamedChild(total - 2))
+	if iterated.Type() != "method_invocation" || iterated.ChildByFieldName("object") == nil ||
+		iterated.ChildByFieldName("arguments").NamedChildCount() > 0 {
+		return nil
+	}
+	view := iterated.ChildByFieldName("name").Content(source)
+	if view != "keySet" && view != "values" && view != "entrySet" {
+		return nil
+	}
+	keyType, valueType, ok := mapExprType(iterated.ChildByFieldName("object"), ctx, source)
+	if !ok {
+		return nil
+	}
+
+	loop := &ast.RangeStmt{Tok: token.DEFINE, X: ParseExpr(iterated.ChildByFieldName("object"), source, ctx)}
+	variable := ParseExpr(node.NamedChild(total-3), source, ctx).(*ast.Ident)
+	switch view {
+	case "keySet":
+		loop.Key = variable
+	case "values":
+		loop.Key, loop.Value = &ast.Ident{Name: "_"}, variable
+	}
+	if view != "entrySet" {
+		loop.Body = parseLoopBody(node.NamedChild(total-1), source, ctx)
+		return loop
+	}
+
+	names := localVariableNames(ctx)
+	entry := &rangedEntry{key: names.Allocate(variable.Name + "Key"), value: names.Allocate(variable.Name + "Value")}
+	bodyCtx := ctx.Clone()
+	bodyCtx.rangedEntries = map[string]*rangedEntry{node.NamedChild(total - 3).Content(source): entry}
+	for name, outer := range ctx.rangedEntries {
+		if _, shadowed := bodyCtx.rangedEntries[name]; !shadowed {
+			bodyCtx.rangedEntries[name] = outer
+		}
+	}
+	loop.Body = parseLoopBody(node.NamedChild(total-1), source, bodyCtx)
+
+	// The entry is only created if it is used as more than its key and value
+	if variableUses(loop.Body)[variable.Name] > 0 {
+		entry.keyUsed, entry.valueUsed = true, true
+		entryType := astutil.LibraryGenericType("Map.Entry", []string{keyType, valueType},
+			[]ast.Expr{elementType(keyType, true, ctx), elementType(valueType, false, ctx)}).(*ast.StarExpr)
+		loop.Body.List = append([]ast.Stmt{&ast.AssignStmt{
+			Lhs: []ast.Expr{variable},
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{&ast.UnaryExpr{Op: token.AND, X: &ast.CompositeLit{
+				Type: entryType.X,
+				Elts: []ast.Expr{
+					&ast.KeyValueExpr{Key: &ast.Ident{Name: "Key"}, Value: &ast.Ident{Name: entry.key}},
+					&ast.KeyValueExpr{Key: &ast.Ident{Name: "Value"}, Value: &ast.Ident{Name: entry.value}},
+				},
+			}}},
+		}}, loop.Body.List...)
+	}
+
+	switch {
+	case entry.valueUsed:
+		loop.Key, loop.Value = &ast.Ident{Name: entry.key}, &ast.Ident{Name: entry.value}
+		if !entry.keyUsed {
+			loop.Key = &ast.Ident{Name: "_"}
+		}
+	case entry.keyUsed:
+		loop.Key = &ast.Ident{Name: entry.key}
+	default:
+		loop.Tok = token.ILLEGAL
+	}
+	return loop
+}
+
+// newMap translates the creation of a map, with the type arguments that it is
+// created with, or returns nil if the created object isn't one
+//
+// Ex: `new HashMap<>()` turns into `make(map[string]int32)`, and
+// `new HashMap<>(other)` turns into `maps.Clone(other)`
+func newMap(node *sitter.Node, className string, typeArgs []string, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	// A map with an anonymous class is created as the class, unless the class
+	// only initializes it, and a map of hashed objects isn't a Go map
+	if stripJavaQualifier(className) != "HashMap" || !isCreatedInPlace(node) || hashedClassFor(className, typeArgs) != "" {
+		return nil
+	}
+	arguments := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+	if len(arguments) == 1 {
+		if _, _, ok := mapExprType(arguments[0], ctx, source); ok {
+			return &ast.CallExpr{Fun: &ast.SelectorExpr{X: &ast.Ident{Name: "maps"}, Sel: &ast.Ident{Name: "Clone"}}, Args: args}
+		}
+	}
+	// The map's type has to be known, since it can't be inferred from the
+	// arguments
+	if len(typeArgs) != 2 {
+		ctx.diagnostics.Report(node, mapDiagnostic, "the type arguments of `%s` aren't known", node.Content(source))
+		return nil
+	}
+	made := &ast.CallExpr{
+		Fun:  &ast.Ident{Name: "make"},
+		Args: []ast.Expr{&ast.MapType{Key: elementType(typeArgs[0], true, ctx), Value: elementType(typeArgs[1], false, ctx)}},
+	}
+	switch len(args) {
+	case 0:
+		return made
+	case 1:
+		// The initial capacity of the map
+		made.Args = append(made.Args, args[0])
+		return made
+	}
+	ctx.diagnostics.Report(node, mapDiagnostic, "`%s` isn't translated for a map, which is a Go map", node.Content(source))
+	return nil
+}
@@ -0,0 +1,72 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMapIteration(t *testing.T) {
+	helper := setupParseHelper(t, `
+package util.maps;
+import java.util.*;
+public class Counter {
+    public static int total(Map<String, Integer> counts) {
+        int total = 0;
+        for (Map.Entry<String, Integer> e : counts.entrySet()) {
+            total += e.getValue();
+        }
+        for (String word : counts.keySet()) {
+            total += counts.get(word);
+        }
+        return total;
+    }
+    public static List<Map.Entry<String, Integer>> frequent(Map<String, Integer> counts) {
+        List<Map.Entry<String, Integer>> frequent = new ArrayList<>();
+        for (Map.Entry<String, Integer> e : counts.entrySet()) {
+            if (e.getValue() > 1) {
+                frequent.add(e);
+            }
+        }
+        return frequent;
+    }
+    public static Map<String, Integer> count(String[] words) {
+        Map<String, Integer> counts = new HashMap<>();
+        for (String word : words) {
+            counts.merge(word, 1, Integer::sum);
+            counts.computeIfAbsent(word, w -> 0);
+        }
+        counts.put("total", counts.size());
+        counts.remove("");
+        counts.replaceAll((k, v) -> v);
+        return counts;
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Counter.java")
+
+	out := renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		"func Total(counts map[string]int32) int32 {",
+		"for _, eValue := range counts {",
+		"total += eValue",
+		"for word := range counts {",
+		"total += counts[word]",
+		"for eKey, eValue := range counts {",
+		"e := &stdjava.Entry[string, int32]{Key: eKey, Value: eValue}",
+		"if eValue > 1 {",
+		"counts := make(map[string]int32)",
+		"stdjava.Merge(counts, word, 1, stdjava.Sum[int32])",
+		"stdjava.ComputeIfAbsent(counts, word, func(w string) int32 {",
+		"counts[\"total\"] = int32(len(counts))",
+		"delete(counts, \"\")",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != mapDiagnostic || items[0].Line != 32 {
+		t.Errorf("Expected the untranslated method on line 32 to be reported, got %v", items)
+	}
+}
@@ -0,0 +1,118 @@
+package translate
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for every native method
+const nativeMethodDiagnostic = "native-method"
+
+// Native methods are implemented outside of Java, so they are generated as
+// stubs that panic, and are reported so that they can be implemented by hand
+//
+// With the `-native-cgo` option, the stub is documented with a skeleton of a
+// cgo binding to a C implementation of the method
+
+// The C types of Java's primitives, which are used in cgo bindings
+var nativeCTypes = map[string]string{
+	"int":     "int32_t",
+	"long":    "int64_t",
+	"short":   "int16_t",
+	"byte":    "int8_t",
+	"char":    "uint16_t",
+	"float":   "float",
+	"double":  "double",
+	"boolean": "bool",
+	"void":    "void",
+}
+
+// The Go types of Java's primitives, which the results of cgo calls are
+// converted into, to match the types that the stubs return
+var nativeGoTypes = map[string]string{
+	"int":     "int32",
+	"long":    "int64",
+	"short":   "int16",
+	"byte":    "byte",
+	"char":    "rune",
+	"float":   "float32",
+	"double":  "float64",
+	"boolean": "bool",
+}
+
+// nativeMethodComments reports a native method, and documents its stub
+func nativeMethodComments(node *sitter.Node, ctx Ctx, source []byte) []*ast.Comment {
+	methodName := node.ChildByFieldName("name").Content(source)
+	ctx.diagnostics.Report(node, nativeMethodDiagnostic,
+		"`%s` is a native method, which is generated as a stub that panics until it is implemented",
+		methodName)
+
+	lines := []string{fmt.Sprintf("// TODO: %s is a native method, and must be implemented in Go", methodName)}
+	if NativeBindings {
+		lines = append(lines, "//", "// A cgo binding to a C implementation could look like:", "//")
+		for _, line := range nativeBindingSkeleton(node, ctx, source) {
+			lines = append(lines, "//\t"+line)
+		}
+	}
+
+	comments := make([]*ast.Comment, len(lines))
+	for ind, line := range lines {
+		comments[ind] = &ast.Comment{Text: line}
+	}
+	return comments
+}
+
+// nativeBindingSkeleton generates the lines of a cgo binding for a native method
+//
+// Ex: `native int compute(int x)` in the class `Native` calls the C function
+// `int32_t Native_compute(int32_t x)`
+func nativeBindingSkeleton(node *sitter.Node, ctx Ctx, source []byte) []string {
+	cName := ctx.currentClass.Class.OriginalName + "_" + node.ChildByFieldName("name").Content(source)
+	returnType := node.ChildByFieldName("type").Content(source)
+
+	var cParams, args []string
+	for _, param := range nodeutil.NamedChildrenOf(node.ChildByFieldName("parameters")) {
+		if param.Type() != "formal_parameter" {
+			continue
+		}
+		name := param.ChildByFieldName("name").Content(source)
+		cType, primitive := nativeCTypes[param.ChildByFieldName("type").Content(source)]
+		if !primitive {
+			// Objects have to be converted into something that C can use
+			cParams = append(cParams, "void* "+name)
+			args = append(args, fmt.Sprintf("unsafe.Pointer(&%s)", name))
+			continue
+		}
+		cParams = append(cParams, cType+" "+name)
+		args = append(args, fmt.Sprintf("C.%s(%s)", cType, name))
+	}
+
+	cReturnType, primitive := nativeCTypes[returnType]
+	if !primitive {
+		cReturnType = "void*"
+	}
+
+	call := fmt.Sprintf("C.%s(%s)", cName, strings.Join(args, ", "))
+	switch goType, ok := nativeGoTypes[returnType]; {
+	case returnType == "void":
+	case ok:
+		call = fmt.Sprintf("return %s(%s)", goType, call)
+	default:
+		call = "return " + call
+	}
+
+	return []string{
+		"/*",
+		"#include <stdbool.h>",
+		"#include <stdint.h>",
+		fmt.Sprintf("%s %s(%s);", cReturnType, cName, strings.Join(cParams, ", ")),
+		"*/",
+		`import "C"`,
+		"",
+		call,
+	}
+}
@@ -0,0 +1,60 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNativeMethodStub(t *testing.T) {
+	helper := setupParseHelper(t, `
+package natives.stub;
+public class Native {
+    public native int compute(int x, double y);
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Native.java")
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helper))
+	for _, want := range []string{
+		"// TODO: compute is a native method, and must be implemented in Go",
+		`func (ne *Native) Compute(x int32, y float64) int32 { panic("native method Native.compute is not implemented") }`,
+	} {
+		if !strings.Contains(out, normalizeSpaces(want)) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `import "C"`) {
+		t.Errorf("expected no cgo binding without the option, got:\n%s", out)
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != nativeMethodDiagnostic || items[0].Line != 4 {
+		t.Errorf("expected the native method to be reported, got %v", items)
+	}
+}
+
+func TestNativeMethodBindingSkeleton(t *testing.T) {
+	NativeBindings = true
+	defer func() { NativeBindings = false }()
+
+	out := renderGoFileFromJava(t, `
+package natives.binding;
+public class Native {
+    private static native void reset(String reason);
+    public native boolean check(long value);
+    public native byte scale(byte factor);
+}
+`)
+	for _, want := range []string{
+		"//\tvoid Native_reset(void* reason);",
+		"//\tC.Native_reset(unsafe.Pointer(&reason))",
+		"//\tbool Native_check(int64_t value);",
+		"//\treturn bool(C.Native_check(C.int64_t(value)))",
+		"//\tint8_t Native_scale(int8_t factor);",
+		"//\treturn byte(C.Native_scale(C.int8_t(factor)))",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package translate
+
+import (
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+var primitiveTypes = map[string]bool{
+	"int":     true,
+	"long":    true,
+	"short":   true,
+	"byte":    true,
+	"char":    true,
+	"boolean": true,
+	"float":   true,
+	"double":  true,
+}
+
+// The kind of every diagnostic reported by the null dereference analysis
+const nullDereferenceDiagnostic = "null-dereference"
+
+// CheckNullDereferences looks for places where Java would throw a
+// `NullPointerException`, but the generated Go code either panics with a
+// different message, in a different place, or doesn't panic at all
+//
+// This covers two cases:
+//   - A wrapper type, such as an `Integer`, being automatically unboxed into a
+//     primitive, which Java checks for null, but the generated code does not
+//   - A method call or field access on a variable that is assigned `null`
+//     somewhere, since Go calls methods on nil receivers without panicking
+func CheckNullDereferences(root *sitter.Node, source []byte, diagnostics *Diagnostics) {
+	checker := &nullChecker{source: source, diagnostics: diagnostics}
+	checker.findClasses(root)
+}
+
+type nullChecker struct {
+	source      []byte
+	diagnostics *Diagnostics
+
+	// The declared Java types of all the variables that are visible
+	types map[string]string
+	// The variables that are assigned `null` somewhere
+	nullable map[string]bool
+	// The return type of the method that is being checked
+	returnType string
+}
+
+// findClasses checks the members of every class in the given tree, including
+// local and anonymous classes
+func (nc *nullChecker) findClasses(node *sitter.Node) {
+	switch node.Type() {
+	case "class_body", "interface_body", "enum_body_declarations":
+		nc.checkClassBody(node)
+	}
+	for _, child := range nodeutil.NamedChildrenOf(node) {
+		nc.findClasses(child)
+	}
+}
+
+func (nc *nullChecker) checkClassBody(body *sitter.Node) {
+	fields := map[string]string{}
+	nullableFields := map[string]bool{}
+
+	for _, member := range nodeutil.NamedChildrenOf(body) {
+		if member.Type() == "field_declaration" || member.Type() == "constant_declaration" {
+			nc.declare(member, fields, nullableFields)
+		}
+	}
+	// A field can be set to null from any method in the class
+	for _, member := range nodeutil.NamedChildrenOf(body) {
+		switch member.Type() {
+		case "method_declaration", "constructor_declaration":
+			nc.collectNullAssignments(member, nullableFields)
+		}
+	}
+
+	for _, member := range nodeutil.NamedChildrenOf(body) {
+		nc.types = map[string]string{}
+		nc.nullable = map[string]bool{}
+		for name, javaType := range fields {
+			nc.types[name] = javaType
+		}
+		for name := range nullableFields {
+			nc.nullable[name] = true
+		}
+		nc.returnType = ""
+
+		switch member.Type() {
+		case "field_declaration", "constant_declaration":
+			nc.check(member)
+		case "method_declaration", "constructor_declaration":
+			if member.ChildByFieldName("type") != nil {
+				nc.returnType = member.ChildByFieldName("type").Content(nc.source)
+			}
+			for _, param := range nodeutil.NamedChildrenOf(member.ChildByFieldName("parameters")) {
+				if param.ChildByFieldName("name") != nil {
+					nc.types[param.ChildByFieldName("name").Content(nc.source)] = param.ChildByFieldName("type").Content(nc.source)
+				}
+			}
+			if body := member.ChildByFieldName("body"); body != nil {
+				// Locals shadow any fields with the same name
+				nc.collectLocals(body)
+				nc.collectNullAssignments(body, nc.nullable)
+				nc.check(body)
+			}
+		}
+	}
+}
+
+// declare records the types of the variables in a declaration, and which of
+// them are initialized to null
+func (nc *nullChecker) declare(node *sitter.Node, types map[string]string, nullable map[string]bool) {
+	javaType := node.ChildByFieldName("type").Content(nc.source)
+	for _, declarator := range nodeutil.ChildrenByFieldName(node, "declarator") {
+		name := declarator.ChildByFieldName("name").Content(nc.source)
+		types[name] = javaType
+		delete(nullable, name)
+		if value := declarator.ChildByFieldName("value"); value != nil && value.Type() == "null_literal" {
+			nullable[name] = true
+		}
+	}
+}
+
+func (nc *nullChecker) collectLocals(node *sitter.Node) {
+	switch node.Type() {
+	case "local_variable_declaration":
+		nc.declare(node, nc.types, nc.nullable)
+	case "enhanced_for_statement":
+		nc.types[node.ChildByFieldName("name").Content(nc.source)] = node.ChildByFieldName("type").Content(nc.source)
+	case "class_body", "lambda_expression":
+		return
+	}
+	for _, child := range nodeutil.NamedChildrenOf(node) {
+		nc.collectLocals(child)
+	}
+}
+
+// collectNullAssignments finds every variable that is assigned `null`
+func (nc *nullChecker) collectNullAssignments(node *sitter.Node, nullable map[string]bool) {
+	switch node.Type() {
+	case "assignment_expression":
+		if node.ChildByFieldName("right").Type() == "null_literal" {
+			left := node.ChildByFieldName("left")
+			switch {
+			case left.Type() == "identifier":
+				nullable[left.Content(nc.source)] = true
+			case left.Type() == "field_access" && left.ChildByFieldName("object").Type() == "this":
+				nullable[left.ChildByFieldName("field").Content(nc.source)] = true
+			}
+		}
+	case "class_body":
+		return
+	}
+	for _, child := range nodeutil.NamedChildrenOf(node) {
+		nc.collectNullAssignments(child, nullable)
+	}
+}
+
+// variableName returns the name of a variable that the expression refers to
+func (nc *nullChecker) variableName(node *sitter.Node) (string, bool) {
+	switch node.Type() {
+	case "identifier":
+		return node.Content(nc.source), true
+	case "field_access":
+		if node.ChildByFieldName("object").Type() == "this" {
+			return node.ChildByFieldName("field").Content(nc.source), true
+		}
+	case "parenthesized_expression":
+		return nc.variableName(node.NamedChild(0))
+	}
+	return "", false
+}
+
+// javaType returns the declared Java type of an expression, if it is known
+func (nc *nullChecker) javaType(node *sitter.Node) string {
+	switch node.Type() {
+	case "cast_expression":
+		return node.ChildByFieldName("type").Content(nc.source)
+	case "string_literal":
+		return "String"
+	case "decimal_integer_literal", "hex_integer_literal", "octal_integer_literal", "binary_integer_literal":
+		return "int"
+	case "decimal_floating_point_literal":
+		return "double"
+	case "character_literal":
+		return "char"
+	case "true", "false":
+		return "boolean"
+	}
+	if name, ok := nc.variableName(node); ok {
+		return nc.types[name]
+	}
+	return ""
+}
+
+// checkUnboxed reports the expression if it is a wrapper type that is unboxed
+func (nc *nullChecker) checkUnboxed(node *sitter.Node) {
+	if node == nil {
+		return
+	}
+	if javaType := nc.javaType(node); isBoxedType(javaType) {
+		nc.diagnostics.Report(node, nullDereferenceDiagnostic,
+			"`%s` is unboxed from %s here, which throws a NullPointerException in Java if it is null, but the generated Go does not check for null",
+			node.Content(nc.source), javaType)
+	}
+}
+
+// checkReceiver reports a method call or field access on a variable that can be null
+func (nc *nullChecker) checkReceiver(object, member *sitter.Node, isCall bool) {
+	name, ok := nc.variableName(object)
+	if !ok || !nc.nullable[name] {
+		return
+	}
+	javaType := nc.types[name]
+	switch {
+	case !isCall:
+		nc.diagnostics.Report(object, nullDereferenceDiagnostic,
+			"`%s` may be null here: Java throws a NullPointerException when accessing `%s`, but Go panics with a nil pointer dereference instead",
+			object.Content(nc.source), member.Content(nc.source))
+	case javaType == "String" || isBoxedType(javaType):
+		nc.diagnostics.Report(object, nullDereferenceDiagnostic,
+			"`%s` may be null here: Java throws a NullPointerException when calling `%s`, but a %s can't be nil in Go, so the generated code uses its zero value instead",
+			object.Content(nc.source), member.Content(nc.source), javaType)
+	default:
+		nc.diagnostics.Report(object, nullDereferenceDiagnostic,
+			"`%s` may be null here: Java throws a NullPointerException when calling `%s`, but Go calls the method with a nil receiver, and only panics once the method accesses a field",
+			object.Content(nc.source), member.Content(nc.source))
+	}
+}
+
+func (nc *nullChecker) check(node *sitter.Node) {
+	switch node.Type() {
+	// Local and anonymous classes are checked on their own
+	case "class_body":
+		return
+	case "binary_expression":
+		left, right := node.ChildByFieldName("left"), node.ChildByFieldName("right")
+		switch node.ChildByFieldName("operator").Type() {
+		case "==", "!=":
+			// Comparing two references doesn't unbox either of them, so only
+			// comparisons against a primitive do
+			if primitiveTypes[nc.javaType(right)] {
+				nc.checkUnboxed(left)
+			}
+			if primitiveTypes[nc.javaType(left)] {
+				nc.checkUnboxed(right)
+			}
+		case "+":
+			// Concatenating a string converts the other operand with `toString`
+			if nc.javaType(left) != "String" && nc.javaType(right) != "String" {
+				nc.checkUnboxed(left)
+				nc.checkUnboxed(right)
+			}
+		default:
+			nc.checkUnboxed(left)
+			nc.checkUnboxed(right)
+		}
+	case "unary_expression", "update_expression":
+		nc.checkUnboxed(node.NamedChild(0))
+	case "assignment_expression":
+		left, right := node.ChildByFieldName("left"), node.ChildByFieldName("right")
+		if node.ChildByFieldName("operator").Type() != "=" {
+			nc.checkUnboxed(left)
+			nc.checkUnboxed(right)
+		} else if primitiveTypes[nc.javaType(left)] {
+			nc.checkUnboxed(right)
+		}
+	case "local_variable_declaration", "field_declaration", "constant_declaration":
+		if primitiveTypes[node.ChildByFieldName("type").Content(nc.source)] {
+			for _, declarator := range nodeutil.ChildrenByFieldName(node, "declarator") {
+				nc.checkUnboxed(declarator.ChildByFieldName("value"))
+			}
+		}
+	case "if_statement", "while_statement", "do_statement", "for_statement", "ternary_expression":
+		if condition := node.ChildByFieldName("condition"); condition != nil {
+			if condition.Type() == "parenthesized_expression" {
+				condition = condition.NamedChild(0)
+			}
+			nc.checkUnboxed(condition)
+		}
+	case "array_access":
+		nc.checkUnboxed(node.ChildByFieldName("index"))
+	case "return_statement":
+		if primitiveTypes[nc.returnType] && node.NamedChildCount() > 0 {
+			nc.checkUnboxed(node.NamedChild(0))
+		}
+	case "method_invocation":
+		if object := node.ChildByFieldName("object"); object != nil {
+			nc.checkReceiver(object, node.ChildByFieldName("name"), true)
+		}
+	case "field_access":
+		nc.checkReceiver(node.ChildByFieldName("object"), node.ChildByFieldName("field"), false)
+	}
+
+	for _, child := range nodeutil.NamedChildrenOf(node) {
+		nc.check(child)
+	}
+}
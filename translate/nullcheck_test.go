@@ -0,0 +1,90 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func nullDiagnosticsFor(t *testing.T, src string) []string {
+	t.Helper()
+	helper := setupParseHelper(t, src)
+	diagnostics := NewDiagnostics("Test.java")
+	CheckNullDereferences(helper.File.Ast, helper.File.Source, diagnostics)
+
+	var messages []string
+	for _, diagnostic := range diagnostics.Items() {
+		if diagnostic.Kind != nullDereferenceDiagnostic {
+			t.Errorf("Unexpected diagnostic kind %q", diagnostic.Kind)
+		}
+		messages = append(messages, diagnostic.String())
+	}
+	return messages
+}
+
+func expectDiagnostic(t *testing.T, diagnostics []string, position, snippet string) {
+	t.Helper()
+	for _, diagnostic := range diagnostics {
+		if strings.HasPrefix(diagnostic, "Test.java:"+position+":") && strings.Contains(diagnostic, snippet) {
+			return
+		}
+	}
+	t.Errorf("Expected a diagnostic at %s containing %q, got:\n%s", position, snippet, strings.Join(diagnostics, "\n"))
+}
+
+func TestNullAnalysis_Unboxing(t *testing.T) {
+	diagnostics := nullDiagnosticsFor(t, `
+package nulls.unboxing;
+public class Counter {
+    Integer count;
+    int total(Integer extra, int base) {
+        int sum = extra;
+        if (extra > base) {
+            sum += count;
+        }
+        Integer same = extra;
+        if (same == extra) {
+            sum++;
+        }
+        String label = "total: " + extra;
+        return extra;
+    }
+}
+`)
+
+	expectDiagnostic(t, diagnostics, "6:19", "`extra` is unboxed from Integer")
+	expectDiagnostic(t, diagnostics, "7:13", "`extra` is unboxed from Integer")
+	expectDiagnostic(t, diagnostics, "8:20", "`count` is unboxed from Integer")
+	expectDiagnostic(t, diagnostics, "15:16", "`extra` is unboxed from Integer")
+	// Comparing references and concatenating strings don't unbox
+	if len(diagnostics) != 4 {
+		t.Errorf("Expected 4 diagnostics, got:\n%s", strings.Join(diagnostics, "\n"))
+	}
+}
+
+func TestNullAnalysis_NullableReceivers(t *testing.T) {
+	diagnostics := nullDiagnosticsFor(t, `
+package nulls.receivers;
+public class Tree {
+    Node root = null;
+    Node cached;
+    void clear() {
+        this.cached = null;
+    }
+    int size(Node other) {
+        String name = null;
+        int length = name.length();
+        other.size();
+        cached.size();
+        return root.value;
+    }
+}
+`)
+
+	expectDiagnostic(t, diagnostics, "11:22", "a String can't be nil in Go")
+	expectDiagnostic(t, diagnostics, "13:9", "Go calls the method with a nil receiver")
+	expectDiagnostic(t, diagnostics, "14:16", "nil pointer dereference")
+	// Parameters that are never assigned null aren't reported
+	if len(diagnostics) != 3 {
+		t.Errorf("Expected 3 diagnostics, got:\n%s", strings.Join(diagnostics, "\n"))
+	}
+}
@@ -0,0 +1,186 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for the methods of `Objects` and `UUID`
+// that aren't translated
+const utilityDiagnostic = "utility"
+
+// A `UUID` is the string of its canonical form in Go, the same as a `URL`, so
+// that converting it to a string, and comparing it, are the string itself
+
+// The static methods of `UUID` that the runtime implements, and their names in
+// the runtime
+var uuidFactories = map[string]string{
+	"randomUUID":        "RandomUUID",
+	"fromString":        "UUIDFromString",
+	"nameUUIDFromBytes": "NameUUIDFromBytes",
+}
+
+// isUUIDExpr determines if the given expression evaluates to a `UUID`
+func isUUIDExpr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	if node.Type() == "method_invocation" {
+		if object := node.ChildByFieldName("object"); object != nil && object.Content(source) == "UUID" {
+			return uuidFactories[node.ChildByFieldName("name").Content(source)] != ""
+		}
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	return ok && stripJavaQualifier(javaType) == "UUID"
+}
+
+// nullCheck compares a value with nil, the same way as comparing it with null
+func nullCheck(value ast.Expr, op token.Token) ast.Expr {
+	return &ast.BinaryExpr{X: value, Op: op, Y: &ast.Ident{Name: "nil"}}
+}
+
+// isNeverNil determines if an expression is translated into a Go value that
+// can't be nil, such as a string or a primitive, which can't be compared with
+// nil either
+func isNeverNil(node *sitter.Node, ctx Ctx, source []byte) bool {
+	return isStringExpr(node, ctx, source) || isUUIDExpr(node, ctx, source) || isPrimitiveValue(node, ctx, source)
+}
+
+// nullPointerCheck panics with a `NullPointerException` if a value is nil,
+// with the given message, or no message if it is nil
+//
+// Ex: `if name == nil { panic(stdjava.NewException("NullPointerException", "name", nil)) }`
+func nullPointerCheck(value ast.Expr, message ast.Expr) ast.Stmt {
+	if message == nil {
+		message = &ast.BasicLit{Kind: token.STRING, Value: `""`}
+	}
+	return &ast.IfStmt{
+		Cond: nullCheck(value, token.EQL),
+		Body: &ast.BlockStmt{List: []ast.Stmt{&ast.ExprStmt{X: &ast.CallExpr{
+			Fun: &ast.Ident{Name: "panic"},
+			Args: []ast.Expr{runtimeCall("NewException",
+				&ast.BasicLit{Kind: token.STRING, Value: `"NullPointerException"`}, message, &ast.Ident{Name: "nil"})},
+		}}}},
+	}
+}
+
+// requireNonNullCall returns the value and the message of a call to
+// `Objects.requireNonNull`, or nil if the node isn't one
+func requireNonNullCall(node *sitter.Node, source []byte, ctx Ctx) (ast.Expr, ast.Expr) {
+	if node.Type() != "method_invocation" || node.ChildByFieldName("object") == nil ||
+		stripJavaQualifier(node.ChildByFieldName("object").Content(source)) != "Objects" ||
+		node.ChildByFieldName("name").Content(source) != "requireNonNull" {
+		return nil, nil
+	}
+	arguments := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+	// The message can also be created by a supplier, which isn't translated
+	if len(arguments) == 0 || len(arguments) > 2 || len(arguments) == 2 && !isStringExpr(arguments[1], ctx, source) {
+		return nil, nil
+	}
+	value := ParseExpr(arguments[0], source, ctx)
+	var message ast.Expr
+	if len(arguments) == 2 {
+		message = ParseExpr(arguments[1], source, ctx)
+	}
+	return value, message
+}
+
+// requireNonNullStmt translates a call to `Objects.requireNonNull` that is
+// the whole of an expression statement into a check of the value, or returns
+// nil if the statement isn't one
+//
+// Ex: `Objects.requireNonNull(name, "name");` turns into
+// `if name == nil { panic(...) }`
+func requireNonNullStmt(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	value, message := requireNonNullCall(node, source, ctx)
+	if value == nil || isNeverNil(node.ChildByFieldName("arguments").NamedChild(0), ctx, source) {
+		return nil
+	}
+	return nullPointerCheck(value, message)
+}
+
+// isHoistableValue determines if an expression can be evaluated before the
+// statement that it is in, without changing what it evaluates to, which is
+// true for variables and the fields of `this`
+func isHoistableValue(node *sitter.Node, source []byte) bool {
+	switch node.Type() {
+	case "identifier":
+		return true
+	case "field_access":
+		return node.ChildByFieldName("object").Type() == "this"
+	}
+	return false
+}
+
+// objectsMethod translates the methods of `Objects`, and the methods of a
+// `UUID`, or returns nil if the method isn't one of them
+//
+// A value that is required to be non-null in the middle of a statement is
+// checked before the statement, if it is a variable, and otherwise by the
+// runtime
+//
+// Ex: `this.name = Objects.requireNonNull(name)` turns into
+// `if name == nil { panic(...) }` followed by `this.name = name`
+func objectsMethod(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	objectNode := node.ChildByFieldName("object")
+	name := node.ChildByFieldName("name").Content(source)
+
+	switch stripJavaQualifier(objectNode.Content(source)) {
+	case "Objects":
+		// A value that can't be nil in Go is never null, and isn't checked
+		neverNil := len(args) > 0 && isNeverNil(node.ChildByFieldName("arguments").NamedChild(0), ctx, source)
+		switch {
+		case name == "isNull" && len(args) == 1 && neverNil:
+			return &ast.Ident{Name: "false"}
+		case name == "nonNull" && len(args) == 1 && neverNil:
+			return &ast.Ident{Name: "true"}
+		case name == "isNull" && len(args) == 1:
+			return nullCheck(args[0], token.EQL)
+		case name == "nonNull" && len(args) == 1:
+			return nullCheck(args[0], token.NEQ)
+		case name == "toString" && len(args) <= 2:
+			return runtimeCall("ObjectToString", args...)
+		case name == "equals" && len(args) == 2:
+			return runtimeCall("Equals", args...)
+		case name == "hashCode" && len(args) == 1:
+			return runtimeCall("ObjectHashCode", args...)
+		case name == "hash":
+			return runtimeCall("Hash", args...)
+		case name == "requireNonNull":
+			value, message := requireNonNullCall(node, source, ctx)
+			if value == nil {
+				break
+			}
+			if neverNil {
+				return value
+			}
+			valueNode := node.ChildByFieldName("arguments").NamedChild(0)
+			if ctx.hoisted != nil && isHoistableValue(valueNode, source) && !isConditionallyEvaluated(node) {
+				ctx.hoisted.hoist(nullPointerCheck(value, message))
+				return value
+			}
+			if message != nil {
+				return runtimeCall("RequireNonNull", value, message)
+			}
+			return runtimeCall("RequireNonNull", value)
+		}
+		ctx.diagnostics.Report(node, utilityDiagnostic, "`%s` isn't translated", node.Content(source))
+		return nil
+	case "UUID":
+		if factory, ok := uuidFactories[name]; ok {
+			return runtimeCall(factory, args...)
+		}
+	}
+
+	if !isUUIDExpr(objectNode, ctx, source) {
+		return nil
+	}
+	switch {
+	case name == "toString" && len(args) == 0:
+		return object
+	case name == "equals" && len(args) == 1:
+		return &ast.BinaryExpr{X: object, Op: token.EQL, Y: args[0]}
+	}
+	ctx.diagnostics.Report(node, utilityDiagnostic, "`%s` isn't translated for a `UUID`, which is a string in Go", node.Content(source))
+	return nil
+}
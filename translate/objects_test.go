@@ -0,0 +1,67 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestObjectsAndUUID(t *testing.T) {
+	helper := setupParseHelper(t, `
+package util.objects;
+import java.util.Objects;
+import java.util.UUID;
+public class Account {
+    private String owner;
+    private UUID id;
+    private Object parent;
+    public Account(String owner, Object settings, Object parent) {
+        Objects.requireNonNull(settings, "settings");
+        this.owner = Objects.requireNonNull(owner);
+        this.parent = Objects.requireNonNull(parent);
+        Objects.requireNonNull(owner, "owner");
+        this.id = UUID.randomUUID();
+    }
+    public String describe(Object value, String name) {
+        if (Objects.isNull(value) || Objects.nonNull(name)) {
+            return Objects.toString(value, "none") + id.toString();
+        }
+        UUID parsed = UUID.fromString(name);
+        int hash = parsed.hashCode();
+        boolean same = Objects.equals(value, name) && Objects.hash(value, name) == Objects.hashCode(value);
+        return Objects.toString(Objects.requireNonNull(find()));
+    }
+    Object find() {
+        return null;
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Account.java")
+
+	out := renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		"id\tstring",
+		"if settings == nil {\n\t\tpanic(stdjava.NewException(\"NullPointerException\", \"settings\", nil))\n\t}",
+		// A string is never nil in Go, so it isn't checked
+		"\tat.owner = owner\n",
+		"if parent == nil {\n\t\tpanic(stdjava.NewException(\"NullPointerException\", \"\", nil))\n\t}\n\tat.parent = parent",
+		"_ = owner",
+		"if value == nil || true {",
+		"at.id = stdjava.RandomUUID()",
+		"stdjava.ObjectToString(value, \"none\") + ",
+		"parsed := stdjava.UUIDFromString(name)",
+		"stdjava.ObjectToString(stdjava.RequireNonNull(",
+		"same := stdjava.Equals(value, name) && stdjava.Hash(value, name) == stdjava.ObjectHashCode(value)",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+	if strings.Contains(out, "owner == nil") {
+		t.Errorf("Expected the string not to be compared with nil, got:\n%s", out)
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != utilityDiagnostic || items[0].Line != 21 {
+		t.Errorf("Expected the untranslated method of the UUID on line 21 to be reported, got %v", items)
+	}
+}
@@ -0,0 +1,57 @@
+package translate
+
+import "regexp"
+
+// Options that change how the Java code is translated, which are set once
+// from the command-line before any of the files are translated
+var (
+	// StrictMode keeps Java's exact semantics wherever the idiomatic Go
+	// translation would behave differently, such as comparing boxed values by
+	// identity, indexing strings by their UTF-16 code units instead of by their
+	// runes, throwing an ArrayIndexOutOfBoundsException for an index outside of
+	// an array, taking the remainder of floating-point numbers and dividing
+	// them by a constant zero, or formatting floating-point numbers as strings
+	StrictMode bool
+	// NativeBindings adds a skeleton of a cgo binding to the stubs of native methods
+	NativeBindings bool
+	// JSONTags adds JSON tags to the fields of the generated structs
+	JSONTags bool
+	// ErrorReturns returns the exceptions of the methods with a throws clause
+	// as an extra error result, instead of panicking with them
+	ErrorReturns bool
+	// ParallelStreams runs the stages of parallel streams in goroutines
+	ParallelStreams bool
+	// LineDirectives precedes the generated declarations with //line directives
+	LineDirectives bool
+	// MonotonicClock reads System.nanoTime from a monotonic clock
+	MonotonicClock bool
+	// JavaArithmetic keeps the semantics of Java's fixed-width integers, where
+	// Go's integer arithmetic would differ
+	JavaArithmetic bool
+
+	// EnumHelperPrefix is prepended to the names of the functions that are
+	// generated for enums
+	EnumHelperPrefix string
+	// ListenerPattern matches the names of the single-method interfaces that
+	// are used as callbacks, or is nil if none of them are
+	ListenerPattern *regexp.Regexp
+	// ExcludedAnnotations are the Java annotations to exclude from the generated code
+	ExcludedAnnotations = make(map[string]bool)
+)
+
+// Options that change what is done with a file, after it is translated
+var (
+	// NullAnalysis reports the places where Java would throw a
+	// NullPointerException, but the generated code behaves differently
+	NullAnalysis bool
+	// TidyOutput removes the code that linters would report
+	TidyOutput bool
+	// SimplifyOutput removes the conversions and assertions to the same type
+	SimplifyOutput bool
+	// ChunkedOutput writes the declarations out as they are generated
+	ChunkedOutput bool
+	// StrictOutput refuses to print the files that can't be printed as valid Go
+	StrictOutput bool
+	// ReportFallbacks collects the places where the types fall back to `any`
+	ReportFallbacks bool
+)
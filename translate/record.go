@@ -0,0 +1,361 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/codegen"
+	"github.com/NickyBoy89/java2go/nodeutil"
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// A record is a struct with a field for each of its components, which its
+// canonical constructor sets. The record's compact constructor runs before the
+// fields are set, and the accessors of the components, as well as `equals`,
+// `hashCode`, and `toString`, are generated unless the record declares them
+// itself:
+//
+//	record Point(int x, String label) {     type Point struct {
+//	    Point {                                 x     int32
+//	        if (x < 0) throw ...;               label string
+//	    }                                   }
+//	}
+//	                                        func NewPoint(x int32, label string) *Point {
+//	                                            pt := new(Point)
+//	                                            if x < 0 { panic(...) }
+//	                                            pt.x = x
+//	                                            pt.label = label
+//	                                            return pt
+//	                                        }
+//
+//	                                        func (pt *Point) X() int32 { return pt.x }
+//	                                        ...
+//
+// `Equals` compares each of the components, `HashCode` combines their hashes,
+// and `String` describes the record the same way as Java, such as
+// `Point[x=1, label=a]`
+
+// recordDecls translates a record into its struct, its canonical constructor,
+// the methods that it declares implicitly, and the declarations in its body
+func recordDecls(node *sitter.Node, source []byte, ctx Ctx) []ast.Decl {
+	ctx.className = ctx.currentFile.FindClass(node.ChildByFieldName("name").Content(source)).Name
+	// Nested records have their scope set by the class that contains them
+	if ctx.currentClass == nil {
+		ctx.currentClass = ctx.currentFile.BaseClass
+	}
+	body := node.ChildByFieldName("body")
+
+	declarations := []ast.Decl{}
+
+	// A record can only declare static fields in its body
+	_, globalVariables := parseFieldDeclarations(nodeutil.NamedChildrenOf(body), source, ctx)
+	if len(globalVariables.Specs) > 0 {
+		declarations = append(declarations, globalVariables)
+	}
+
+	fields := &ast.FieldList{}
+	for _, component := range ctx.currentClass.RecordComponents {
+		fields.List = append(fields.List, &ast.Field{
+			Names: []*ast.Ident{{Name: component.Name}},
+			Type:  &ast.Ident{Name: component.Type},
+		})
+	}
+	if ctx.currentClass.Monitor != "" {
+		fields.List = append(fields.List, &ast.Field{
+			Names: []*ast.Ident{{Name: ctx.currentClass.Monitor}},
+			Type:  &ast.SelectorExpr{X: &ast.Ident{Name: "sync"}, Sel: &ast.Ident{Name: "Mutex"}},
+		})
+	}
+	declarations = append(declarations, codegen.GenStructWithTypeParams(ctx.className, fields, ctx.currentClass.TypeParameters))
+
+	if !declaresCanonicalConstructor(body, ctx.currentClass, source) {
+		declarations = append(declarations, recordConstructorDecl(node, source, ctx))
+	}
+	for _, component := range ctx.currentClass.RecordComponents {
+		if !declaresMethod(body, component.OriginalName, 0, source) {
+			declarations = append(declarations, recordAccessor(component, ctx))
+		}
+	}
+	if !declaresMethod(body, "equals", 1, source) {
+		declarations = append(declarations, recordEquals(ctx))
+	}
+	if !declaresMethod(body, "hashCode", 0, source) {
+		declarations = append(declarations, recordHashCode(ctx))
+	}
+	if !declaresMethod(body, "toString", 0, source) {
+		declarations = append(declarations, recordString(ctx))
+	}
+	declarations = append(declarations, sealedMarkerDecls(node, ctx)...)
+	declarations = append(declarations, stringerBridge(ctx)...)
+
+	positionDecls(declarations, ctx.sources.declPos(node))
+	declarations = emitDecls(ctx, declarations)
+	return append(declarations, ParseDecls(body, source, ctx)...)
+}
+
+// declaresCanonicalConstructor determines if the body of a record declares its
+// canonical constructor in full, with the parameters that it takes
+func declaresCanonicalConstructor(body *sitter.Node, class *symbol.ClassScope, source []byte) bool {
+	for _, member := range nodeutil.NamedChildrenOf(body) {
+		if member.Type() != "constructor_declaration" {
+			continue
+		}
+		params := nodeutil.NamedChildrenOf(member.ChildByFieldName("parameters"))
+		if len(params) != len(class.RecordComponents) {
+			continue
+		}
+		canonical := true
+		for ind, param := range params {
+			if nodeutil.ParameterType(param).Content(source) != class.RecordComponents[ind].OriginalType {
+				canonical = false
+			}
+		}
+		if canonical {
+			return true
+		}
+	}
+	return false
+}
+
+// declaresMethod determines if the body of a record declares a method with the
+// given name and number of parameters
+func declaresMethod(body *sitter.Node, name string, params int, source []byte) bool {
+	for _, member := range nodeutil.NamedChildrenOf(body) {
+		if member.Type() == "method_declaration" && member.ChildByFieldName("name").Content(source) == name &&
+			int(member.ChildByFieldName("parameters").NamedChildCount()) == params {
+			return true
+		}
+	}
+	return false
+}
+
+// recordType returns the type of the objects of the current record
+func recordType(ctx Ctx) ast.Expr {
+	return instantiateGenericType(ctx.className, typeParamExprs(ctx.currentClass.TypeParameters))
+}
+
+// recordReceiver returns the receiver of the methods of the current record
+func recordReceiver(ctx Ctx) *ast.FieldList {
+	return &ast.FieldList{List: []*ast.Field{{
+		Names: []*ast.Ident{{Name: ReceiverName(ctx)}},
+		Type:  &ast.StarExpr{X: recordType(ctx)},
+	}}}
+}
+
+// componentOf returns the component of the object that a method of the current
+// record is called on
+func componentOf(object string, component *symbol.Definition) ast.Expr {
+	return &ast.SelectorExpr{X: &ast.Ident{Name: object}, Sel: &ast.Ident{Name: component.Name}}
+}
+
+// recordComponent returns the component of the current record that an
+// identifier in one of its methods refers to, or nil if it doesn't refer to one
+func recordComponent(node *sitter.Node, ctx Ctx, source []byte) *symbol.Definition {
+	if ctx.currentClass == nil || !ctx.currentClass.IsRecord || ctx.localScope == nil ||
+		ctx.localScope.IsStatic || ctx.localScope.Constructor || !isVariableReference(node) {
+		return nil
+	}
+	for _, component := range ctx.currentClass.RecordComponents {
+		if component.OriginalName == node.Content(source) {
+			return component
+		}
+	}
+	return nil
+}
+
+// calledAccessor returns the accessor of a record's component that a method
+// invocation calls, which is exported when the record declares it implicitly,
+// or nil if the invocation doesn't call one
+//
+// Ex: `p.x()` calls `p.X()`
+func calledAccessor(node *sitter.Node, ctx Ctx, source []byte) *symbol.Definition {
+	if node.ChildByFieldName("arguments").NamedChildCount() != 0 {
+		return nil
+	}
+	class := ctx.currentClass
+	if object := node.ChildByFieldName("object"); object != nil {
+		target := resolveInvocationTarget(object, ctx, source)
+		if target == nil {
+			return nil
+		}
+		class = target.classScope
+	}
+	if class == nil || !class.IsRecord {
+		return nil
+	}
+	name := node.ChildByFieldName("name").Content(source)
+	for _, component := range class.RecordComponents {
+		if component.OriginalName != name {
+			continue
+		}
+		if accessors := class.FindMethod().By(func(d *symbol.Definition) bool {
+			return !d.Constructor && d.OriginalName == name && len(d.Parameters) == 0
+		}); len(accessors) > 0 {
+			return accessors[0]
+		}
+	}
+	return nil
+}
+
+// recordConstructorDecl generates the canonical constructor of a record, which
+// runs the body of its compact constructor, if it has one, and then sets each
+// of its components
+func recordConstructorDecl(node *sitter.Node, source []byte, ctx Ctx) ast.Decl {
+	ctx.enterFunction(ctx.currentClass.FindMethod().By(ctx.currentClass.IsCanonicalConstructor)[0])
+	ctx.errorTarget = nil
+
+	receiver := ReceiverName(ctx)
+	body := &ast.BlockStmt{List: []ast.Stmt{&ast.AssignStmt{
+		Lhs: []ast.Expr{&ast.Ident{Name: receiver}},
+		Tok: token.DEFINE,
+		Rhs: []ast.Expr{&ast.CallExpr{Fun: &ast.Ident{Name: "new"}, Args: []ast.Expr{recordType(ctx)}}},
+	}}}
+	for _, member := range nodeutil.NamedChildrenOf(node.ChildByFieldName("body")) {
+		if member.Type() == "compact_constructor_declaration" {
+			body.List = append(body.List, ParseStmt(member.ChildByFieldName("body"), source, ctx).(*ast.BlockStmt).List...)
+		}
+	}
+	for ind, component := range ctx.currentClass.RecordComponents {
+		body.List = append(body.List, &ast.AssignStmt{
+			Lhs: []ast.Expr{componentOf(receiver, component)},
+			Tok: token.ASSIGN,
+			Rhs: []ast.Expr{&ast.Ident{Name: ctx.localScope.Parameters[ind].Name}},
+		})
+	}
+	body.List = append(body.List, &ast.ReturnStmt{Results: []ast.Expr{&ast.Ident{Name: receiver}}})
+
+	return codegen.GenFuncDeclWithTypeParams(
+		ctx.localScope.Name,
+		ctx.currentClass.TypeParameters,
+		ParseNode(node.ChildByFieldName("parameters"), source, ctx).(*ast.FieldList),
+		&ast.FieldList{List: []*ast.Field{{Type: &ast.StarExpr{X: recordType(ctx)}}}},
+		body,
+	)
+}
+
+// recordAccessor generates the method that returns a component of a record
+func recordAccessor(component *symbol.Definition, ctx Ctx) ast.Decl {
+	accessor := ctx.currentClass.FindMethod().By(func(d *symbol.Definition) bool {
+		return !d.Constructor && d.OriginalName == component.OriginalName && len(d.Parameters) == 0
+	})[0]
+	return &ast.FuncDecl{
+		Name: &ast.Ident{Name: accessor.Name},
+		Recv: recordReceiver(ctx),
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: &ast.Ident{Name: component.Type}}}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{
+			&ast.ReturnStmt{Results: []ast.Expr{componentOf(ReceiverName(ctx), component)}},
+		}},
+	}
+}
+
+// recordEquals generates the `Equals` method of a record, which determines if
+// another object is a record of the same type, with equal components
+func recordEquals(ctx Ctx) ast.Decl {
+	receiver := ReceiverName(ctx)
+	names := symbol.NewNameAllocator(receiver)
+	other := names.Allocate("other")
+	that := "_"
+	if len(ctx.currentClass.RecordComponents) > 0 {
+		that = names.Allocate("that")
+	}
+
+	var equal ast.Expr = &ast.Ident{Name: "ok"}
+	for _, component := range ctx.currentClass.RecordComponents {
+		mine, theirs := componentOf(receiver, component), componentOf(that, component)
+		// The primitives and strings are compared by value, and any other object
+		// by its own `Equals`
+		var comparison ast.Expr = runtimeCall("Equals", mine, theirs)
+		if primitiveTypes[component.OriginalType] || component.OriginalType == "String" {
+			comparison = &ast.BinaryExpr{X: mine, Op: token.EQL, Y: theirs}
+		}
+		equal = &ast.BinaryExpr{X: equal, Op: token.LAND, Y: comparison}
+	}
+
+	return &ast.FuncDecl{
+		Name: &ast.Ident{Name: "Equals"},
+		Recv: recordReceiver(ctx),
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{List: []*ast.Field{{Names: []*ast.Ident{{Name: other}}, Type: &ast.Ident{Name: "any"}}}},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: &ast.Ident{Name: "bool"}}}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{&ast.Ident{Name: that}, &ast.Ident{Name: "ok"}},
+				Tok: token.DEFINE,
+				Rhs: []ast.Expr{&ast.TypeAssertExpr{X: &ast.Ident{Name: other}, Type: &ast.StarExpr{X: recordType(ctx)}}},
+			},
+			&ast.ReturnStmt{Results: []ast.Expr{equal}},
+		}},
+	}
+}
+
+// recordHashCode generates the `HashCode` method of a record, which combines
+// the hashes of its components, so that equal records have the same hash
+func recordHashCode(ctx Ctx) ast.Decl {
+	receiver := ReceiverName(ctx)
+	var components []ast.Expr
+	for _, component := range ctx.currentClass.RecordComponents {
+		components = append(components, componentOf(receiver, component))
+	}
+	return &ast.FuncDecl{
+		Name: &ast.Ident{Name: "HashCode"},
+		Recv: recordReceiver(ctx),
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: &ast.Ident{Name: "int32"}}}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{runtimeCall("Hash", components...)}}}},
+	}
+}
+
+// recordString generates the `String` method of a record, which names the
+// record and each of its components, the same way as Java
+//
+// Ex: `Point[x=1, label=a]`
+func recordString(ctx Ctx) ast.Decl {
+	receiver := ReceiverName(ctx)
+	var format []string
+	var args []ast.Expr
+	for _, component := range ctx.currentClass.RecordComponents {
+		value := componentOf(receiver, component)
+		switch {
+		case component.OriginalType == "char":
+			format = append(format, component.OriginalName+"=%c")
+		case component.OriginalType == "float" || component.OriginalType == "double":
+			format = append(format, component.OriginalName+"=%s")
+			value = runtimeCall(floatTypeFormatter(component.OriginalType), value)
+		case primitiveTypes[component.OriginalType] || component.OriginalType == "String":
+			format = append(format, component.OriginalName+"=%v")
+		default:
+			// Objects describe themselves, and a missing one is `null`
+			format = append(format, component.OriginalName+"=%s")
+			value = runtimeCall("ObjectToString", value)
+		}
+		args = append(args, value)
+	}
+
+	description := ctx.currentClass.Class.OriginalName + "[" + strings.Join(format, ", ") + "]"
+	var result ast.Expr = &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(description)}
+	if len(args) > 0 {
+		result = &ast.CallExpr{
+			Fun:  &ast.SelectorExpr{X: &ast.Ident{Name: "fmt"}, Sel: &ast.Ident{Name: "Sprintf"}},
+			Args: append([]ast.Expr{result}, args...),
+		}
+	}
+
+	return &ast.FuncDecl{
+		Name: &ast.Ident{Name: "String"},
+		Recv: recordReceiver(ctx),
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: &ast.Ident{Name: "string"}}}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{result}}}},
+	}
+}
@@ -0,0 +1,94 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecords(t *testing.T) {
+	helper := setupParseHelper(t, `
+package app.records;
+public record Point<T>(int x, T label, double weight) {
+    static int created = 0;
+
+    public Point {
+        if (x < 0) throw new IllegalArgumentException("negative");
+        created++;
+    }
+
+    public Point(int x) {
+        this(x, null, 1.0);
+    }
+
+    public int doubled() {
+        return x * 2;
+    }
+
+    public double weight() {
+        return Math.abs(weight);
+    }
+
+    record Pair(char first, String second) {
+        @Override
+        public String toString() {
+            return first + second;
+        }
+    }
+}
+`)
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helper))
+	for _, expected := range []string{
+		"type Point[T any] struct { x int32 label T weight float64 }",
+		"func NewPoint0[T any](x int32, label T, weight float64) *Point[T] { pt := new(Point[T]) if x < 0 { panic(",
+		"created++ pt.x = x pt.label = label pt.weight = weight return pt }",
+		"func NewPoint[T any](x int32) *Point[T] { pt := NewPoint0[T](x, nil, 1.0) return pt }",
+		"func (pt *Point[T]) X() int32 { return pt.x }",
+		"func (pt *Point[T]) Label() T { return pt.label }",
+		"func (pt *Point[T]) Equals(other any) bool { that, ok := other.(*Point[T]) return ok && pt.x == that.x && stdjava.Equals(pt.label, that.label) && pt.weight == that.weight }",
+		"func (pt *Point[T]) HashCode() int32 { return stdjava.Hash(pt.x, pt.label, pt.weight) }",
+		`func (pt *Point[T]) String() string { return fmt.Sprintf("Point[x=%v, label=%s, weight=%s]", pt.x, stdjava.ObjectToString(pt.label), stdjava.FormatDouble(pt.weight)) }`,
+		"func (pt *Point[T]) Doubled() int32 { return pt.x * 2 }",
+		"type Pointpair struct { first rune second string }",
+		// A record that declares `toString` describes itself with it
+		"func (pr *Pointpair) String() string { return pr.ToString() }",
+		"func (pr *Pointpair) Equals(other any) bool { that, ok := other.(*Pointpair) return ok && pr.first == that.first && pr.second == that.second }",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	// The methods that the record declares itself aren't generated
+	for _, unexpected := range []string{
+		"func (pt *Point[T]) Weight() float64 { return pt.weight }",
+		"func (pr *Pointpair) String() string { return fmt.Sprintf(",
+	} {
+		if strings.Contains(out, unexpected) {
+			t.Errorf("Expected %q not to be generated, got:\n%s", unexpected, out)
+		}
+	}
+}
+
+func TestRecordAccessorCalls(t *testing.T) {
+	out := normalizeSpaces(renderGoFileFromJava(t, `
+package app.records;
+public class Shapes {
+    record Point(int x, int y) {
+        int sum() { return x() + y(); }
+    }
+    int area(Point corner) {
+        Point origin = new Point(0, 0);
+        return corner.x() * corner.y() - origin.x();
+    }
+}
+`))
+	for _, expected := range []string{
+		"return st.X() + st.Y()",
+		"return corner.X()*corner.Y() - origin.X()",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
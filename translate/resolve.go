@@ -0,0 +1,251 @@
+package translate
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/NickyBoy89/java2go/codegen"
+	"github.com/NickyBoy89/java2go/parsing"
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+func ResolveFile(file parsing.SourceFile) {
+	ResolveClass(file.Symbols.BaseClass, file)
+	exportProtectedMembers(file.Symbols.BaseClass, file.Symbols)
+	for _, subclass := range file.Symbols.BaseClass.Subclasses {
+		ResolveClass(subclass, file)
+		exportProtectedMembers(subclass, file.Symbols)
+	}
+
+	if packageScope := symbol.GlobalScope.FindPackage(file.Symbols.Package); packageScope != nil {
+		markEnumValuesUsage(file.Ast, file.Source, packageScope, nil)
+		markOuterReferences(file.Ast, file.Source, packageScope, nil)
+	}
+}
+
+func ResolveClass(class *symbol.ClassScope, file parsing.SourceFile) {
+	packageScope := symbol.GlobalScope.FindPackage(file.Symbols.Package)
+
+	if class.ReceiverName == "" {
+		class.ReceiverName = allocateReceiverName(class, packageScope.Receivers())
+	}
+	resolveEnumConstants(class, packageScope)
+
+	// Resolve all the fields in that respective class
+	for _, field := range class.Fields {
+
+		// Since a private global variable is able to be accessed in the package, it must be renamed
+		// to avoid conflicts with other global variables
+
+		symbol.ResolveDefinition(field, file.Symbols)
+
+		// Rename the field if its name conflits with any keyword
+		for i := 0; symbol.IsReserved(field.Name) ||
+			len(packageScope.ExcludeFile(class.Class.Name).FindStaticField().ByName(field.Name)) > 0; i++ {
+			field.Rename(field.Name + strconv.Itoa(i))
+		}
+	}
+
+	// Resolve all the methods
+	for _, method := range class.Methods {
+		// Resolve the return type, as well as the body of the method
+		symbol.ResolveChildren(method, file.Symbols)
+
+		qualifyStaticMethod(method, class, packageScope)
+
+		// Comparison compares the method against the found method
+		// This tests for a method of the same name, but with different
+		// aspects of it, so that it can be identified as a duplicate
+		comparison := func(d *symbol.Definition) bool {
+			// The names must match, but everything else must be different
+			if method.Name != d.Name {
+				return false
+			}
+
+			// Size of parameters do not match
+			if len(method.Parameters) != len(d.Parameters) {
+				return true
+			}
+
+			// Go through the types and check to see if they differ
+			for index, param := range method.Parameters {
+				if param.OriginalType != d.Parameters[index].OriginalType {
+					return true
+				}
+			}
+
+			// Both methods are equal, skip this method since it is likely
+			// the same method that we are trying to find duplicates of
+			return false
+		}
+
+		for i := 0; symbol.IsReserved(method.Name) || len(class.FindMethod().By(comparison)) > 0; i++ {
+			method.Rename(method.Name + strconv.Itoa(i))
+		}
+
+		// The helper type, and its constructor, are both package-level declarations
+		//
+		// A method that can't be overridden doesn't need to be called through
+		// its receiver, so it is generated as a generic function instead
+		if method.RequiresHelper {
+			names := packageScope.Names()
+			helperName := class.Class.Name + method.Name + "Helper"
+			if class.CanBeOverridden(method) {
+				method.HelperName = allocateHelperName(class, method, names)
+			} else {
+				method.HelperName = names.AllocateFor(helperName, class.Class.Name+symbol.Uppercase(method.Name))
+			}
+		}
+		// Resolve all the paramters of the method
+		for _, param := range method.Parameters {
+			symbol.ResolveDefinition(param, file.Symbols)
+
+			for i := 0; symbol.IsReserved(param.Name); i++ {
+				param.Rename(param.Name + strconv.Itoa(i))
+			}
+		}
+	}
+}
+
+// unqualifiedStaticName returns the name that a static method has as a
+// package-level function, before any renaming
+func unqualifiedStaticName(method *symbol.Definition) string {
+	return symbol.HandleExportStatus(unicode.IsUpper(rune(method.Name[0])), method.OriginalName)
+}
+
+// qualifyStaticMethod renames a static method to include the name of its class,
+// if a static method in another class of the same package has the same name
+//
+// Static methods are generated as package-level functions, so they would
+// otherwise collide. Every one of the colliding methods is renamed, so that the
+// name of a method doesn't depend on the order that the files are resolved in
+//
+// Ex: `parse` in the class `Foo` is renamed to `fooParse`
+func qualifyStaticMethod(method *symbol.Definition, class *symbol.ClassScope, packageScope *symbol.PackageScope) {
+	// The entry point of the program must keep its name
+	if !method.IsStatic || method.Constructor || method.OriginalName == "main" || packageScope == nil {
+		return
+	}
+
+	name := unqualifiedStaticName(method)
+	for _, other := range packageScope.Classes() {
+		if other == class {
+			continue
+		}
+		for _, otherMethod := range other.Methods {
+			if otherMethod.IsStatic && !otherMethod.Constructor && unqualifiedStaticName(otherMethod) == name {
+				method.Rename(symbol.HandleExportStatus(unicode.IsUpper(rune(name[0])), class.Class.Name+symbol.Uppercase(method.OriginalName)))
+				return
+			}
+		}
+	}
+}
+
+// allocateReceiverName picks the name of the receiver for the methods of a
+// class, which must not be the same as any of the variables in its methods,
+// since the receiver would be shadowed
+func allocateReceiverName(class *symbol.ClassScope, receivers *symbol.NameAllocator) string {
+	variables := make(map[string]bool)
+	var addVariables func(def *symbol.Definition)
+	addVariables = func(def *symbol.Definition) {
+		for _, param := range def.Parameters {
+			variables[param.Name] = true
+		}
+		for _, child := range def.Children {
+			variables[child.Name] = true
+			addVariables(child)
+		}
+	}
+	for _, method := range class.Methods {
+		addVariables(method)
+	}
+
+	// Ex: `Parser` can be `pr` or `par`
+	name := class.Class.Name
+	candidates := []string{codegen.ShortName(name)}
+	if len(name) > 2 {
+		candidates = append(candidates, strings.ToLower(name[:2])+codegen.ShortName(name)[1:])
+	}
+
+	var available []string
+	for _, candidate := range candidates {
+		if !variables[candidate] {
+			available = append(available, candidate)
+		}
+	}
+	if len(available) == 0 {
+		available = []string{codegen.ShortName(name) + "Recv"}
+	}
+	return receivers.AllocateFor(name, available...)
+}
+
+// superclassOf finds the class that a class extends, which can be imported from
+// another package, and whether it is in another package than the class
+func superclassOf(class *symbol.ClassScope, file *symbol.FileScope) (*symbol.ClassScope, bool) {
+	if class == nil || class.Superclass == "" {
+		return nil, false
+	}
+
+	// Ex: `extends other.Base` names the package directly
+	name, packageName := class.Superclass, file.Imports[class.Superclass]
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		name, packageName = name[dot+1:], name[:dot]
+	}
+
+	if packageName == "" || packageName == file.Package {
+		if local := file.BaseClass.FindClassScope(name); local != nil {
+			return local, false
+		}
+		if packageScope := symbol.GlobalScope.FindPackage(file.Package); packageScope != nil {
+			return findPackageClass(packageScope, name), false
+		}
+		return nil, false
+	}
+	if packageScope := symbol.GlobalScope.FindPackage(packageName); packageScope != nil {
+		if superclass := findPackageClass(packageScope, name); superclass != nil {
+			return superclass, true
+		}
+	}
+	return nil, false
+}
+
+// The kind of diagnostic that is reported for protected members that had to be
+// exported
+const protectedExportDiagnostic = "protected-export"
+
+// exportProtectedMembers exports the protected members of a class's superclass,
+// if it is in another package, since an unexported member can't be used from
+// another package in Go, even by a subclass
+func exportProtectedMembers(class *symbol.ClassScope, file *symbol.FileScope) {
+	superclass, foreign := superclassOf(class, file)
+	if !foreign {
+		return
+	}
+	for _, member := range append(append([]*symbol.Definition{}, superclass.Fields...), superclass.Methods...) {
+		if member.IsProtected && !member.Constructor {
+			member.Rename(symbol.Uppercase(member.Name))
+		}
+	}
+}
+
+// exportedProtectedMembers reports that the protected members of a class's
+// superclass were exported, because the class extends it from another package
+func exportedProtectedMembers(node *sitter.Node, ctx Ctx) {
+	if ctx.currentFile == nil {
+		return
+	}
+	superclass, foreign := superclassOf(ctx.currentClass, ctx.currentFile)
+	if !foreign {
+		return
+	}
+	for _, member := range append(append([]*symbol.Definition{}, superclass.Fields...), superclass.Methods...) {
+		if member.IsProtected && !member.Constructor {
+			ctx.diagnostics.Report(node.ChildByFieldName("superclass"), protectedExportDiagnostic,
+				"protected members of %s are exported, since %s extends it from another package",
+				superclass.Class.OriginalName, ctx.currentClass.Class.OriginalName)
+			return
+		}
+	}
+}
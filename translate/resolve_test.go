@@ -0,0 +1,330 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NickyBoy89/java2go/astutil"
+	"github.com/NickyBoy89/java2go/parsing"
+	"github.com/NickyBoy89/java2go/symbol"
+)
+
+// setupPackageHelpers parses several files of the same package, so that their
+// symbols are resolved against each other
+func setupPackageHelpers(t *testing.T, sources ...string) []*ParseHelper {
+	t.Helper()
+	var files []parsing.SourceFile
+	for _, source := range sources {
+		file := parsing.SourceFile{Name: "Test.java", Source: []byte(source)}
+		if err := file.ParseAST(); err != nil {
+			t.Fatalf("Failed to parse AST: %v", err)
+		}
+		astutil.FindHashedClasses(file.Ast, file.Source)
+		files = append(files, file)
+	}
+	for index := range files {
+		symbol.AddSymbolsToPackage(files[index].ParseSymbols())
+	}
+
+	var helpers []*ParseHelper
+	for _, file := range files {
+		ResolveFile(file)
+		helpers = append(helpers, &ParseHelper{
+			File: file,
+			Ctx:  Ctx{currentFile: file.Symbols, currentClass: file.Symbols.BaseClass},
+		})
+	}
+	return helpers
+}
+
+func TestResolve_StaticMethodCollisionsAcrossClasses(t *testing.T) {
+	helpers := setupPackageHelpers(t, `
+package resolve.statics;
+public class Json {
+    static int parse(String text) { return 1; }
+    public static int Format(int value) { return parse("" + value); }
+}
+`, `
+package resolve.statics;
+public class Xml {
+    static int parse(String text) { return 2; }
+    static int unique() { return Json.parse("x") + parse("y"); }
+}
+`)
+
+	jsonOut := normalizeSpaces(renderGoFileFromJavaCtx(t, helpers[0]))
+	xmlOut := normalizeSpaces(renderGoFileFromJavaCtx(t, helpers[1]))
+
+	for _, expected := range []string{
+		"func jsonParse(text string) int32",
+		`return jsonParse("" + value)`,
+	} {
+		if !strings.Contains(jsonOut, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, jsonOut)
+		}
+	}
+	for _, expected := range []string{
+		"func xmlParse(text string) int32",
+		// Methods that don't collide keep their names
+		"func unique() int32",
+		`return jsonParse("x") + xmlParse("y")`,
+	} {
+		if !strings.Contains(xmlOut, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, xmlOut)
+		}
+	}
+	// A public method doesn't collide with a private one
+	if !strings.Contains(jsonOut, "func Format(value int32) int32") {
+		t.Errorf("Expected Format to keep its name, got:\n%s", jsonOut)
+	}
+}
+
+func TestResolve_InheritedStaticMethods(t *testing.T) {
+	helpers := setupPackageHelpers(t, `
+package resolve.inherited;
+public class Base {
+    static int create() { return 1; }
+    static int hidden() { return 1; }
+}
+`, `
+package resolve.inherited;
+public class Sub extends Base {
+    static int hidden() { return 2; }
+    static int use() { return create() + hidden(); }
+    static int qualified() { return Sub.create() + Sub.hidden() + Base.hidden(); }
+}
+`)
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helpers[1]))
+	for _, expected := range []string{
+		"return create() + subHidden()",
+		"return create() + subHidden() + baseHidden()",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestResolve_ReceiverNamesAreUniqueInPackage(t *testing.T) {
+	helpers := setupPackageHelpers(t, `
+package resolve.receivers;
+public class Parser {
+    int pos;
+    int next() { return this.pos; }
+}
+`, `
+package resolve.receivers;
+public class Printer {
+    int width;
+    int size() { return this.width; }
+}
+`, `
+package resolve.receivers;
+public class Node {
+    int value;
+    int add(Node ne) { return this.value + ne.value; }
+}
+`)
+
+	parserOut := normalizeSpaces(renderGoFileFromJavaCtx(t, helpers[0]))
+	printerOut := normalizeSpaces(renderGoFileFromJavaCtx(t, helpers[1]))
+	nodeOut := normalizeSpaces(renderGoFileFromJavaCtx(t, helpers[2]))
+
+	if !strings.Contains(parserOut, "func (pr *Parser) next() int32 { return pr.pos }") {
+		t.Errorf("Expected Parser to use the short name as its receiver, got:\n%s", parserOut)
+	}
+	if !strings.Contains(printerOut, "func (prr *Printer) size() int32 { return prr.width }") {
+		t.Errorf("Expected Printer to not reuse the receiver of Parser, got:\n%s", printerOut)
+	}
+	// The receiver can't be shadowed by a parameter
+	if !strings.Contains(nodeOut, "func (noe *Node) add(ne *Node) int32 { return noe.value + ne.value }") {
+		t.Errorf("Expected Node's receiver to not collide with its parameter, got:\n%s", nodeOut)
+	}
+}
+
+func TestResolve_HelperNamesAvoidDeclaredTypes(t *testing.T) {
+	helpers := setupPackageHelpers(t, `
+package resolve.helpers;
+public class Box<T> {
+    public <R> R identity(R value) { return value; }
+}
+`, `
+package resolve.helpers;
+public class BoxIdentityHelper {
+    int count;
+}
+`)
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helpers[0]))
+	if !strings.Contains(out, "type BoxIdentityHelper2[T any, R any] struct") {
+		t.Errorf("Expected the helper type to not collide with the declared class, got:\n%s", out)
+	}
+	if !strings.Contains(out, "func NewBoxIdentityHelper2[T any, R any]") {
+		t.Errorf("Expected the helper constructor to be renamed along with the helper, got:\n%s", out)
+	}
+}
+
+func TestResolve_EnumConstantsSharedAcrossEnums(t *testing.T) {
+	helpers := setupPackageHelpers(t, `
+package resolve.enumconstants;
+public enum Color { RED, GREEN }
+`, `
+package resolve.enumconstants;
+public enum Light {
+    RED, AMBER;
+    static Light first() { return Light.RED; }
+}
+`)
+
+	colorOut := normalizeSpaces(renderGoFileFromJavaCtx(t, helpers[0]))
+	lightOut := normalizeSpaces(renderGoFileFromJavaCtx(t, helpers[1]))
+
+	for _, want := range []string{"ColorRED Color = iota", "GREEN Color"} {
+		if !strings.Contains(colorOut, normalizeSpaces(want)) {
+			t.Errorf("expected %q in Color output, got:\n%s", want, colorOut)
+		}
+	}
+	for _, want := range []string{"LightRED Light = iota", "AMBER Light", "return LightRED"} {
+		if !strings.Contains(lightOut, normalizeSpaces(want)) {
+			t.Errorf("expected %q in Light output, got:\n%s", want, lightOut)
+		}
+	}
+}
+
+func TestResolve_EnumValuesOnlyWhenUsed(t *testing.T) {
+	helpers := setupPackageHelpers(t, `
+package resolve.enumvalues;
+public enum State { ON, OFF }
+`, `
+package resolve.enumvalues;
+public enum Mode {
+    FAST, SLOW;
+    static void print() { for (Mode m : values()) { System.out.println(m); } }
+}
+`)
+
+	stateOut := renderGoFileFromJavaCtx(t, helpers[0])
+	modeOut := normalizeSpaces(renderGoFileFromJavaCtx(t, helpers[1]))
+
+	if strings.Contains(stateOut, "Values") {
+		t.Errorf("expected no values function for an enum whose values are never used, got:\n%s", stateOut)
+	}
+	for _, want := range []string{
+		"func ModeValues() []Mode { return []Mode{FAST, SLOW} }",
+		"range ModeValues()",
+	} {
+		if !strings.Contains(modeOut, normalizeSpaces(want)) {
+			t.Errorf("expected %q in Mode output, got:\n%s", want, modeOut)
+		}
+	}
+}
+
+func TestResolve_EnumHelperPrefix(t *testing.T) {
+	EnumHelperPrefix = "enum"
+	defer func() { EnumHelperPrefix = "" }()
+
+	helpers := setupPackageHelpers(t, `
+package resolve.enumprefix;
+public enum Suit {
+    HEARTS, SPADES;
+    static Suit last() { return Suit.values()[1]; }
+}
+`)
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helpers[0]))
+	for _, want := range []string{"func enumSuitValues() []Suit", "return enumSuitValues()[1]"} {
+		if !strings.Contains(out, normalizeSpaces(want)) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestResolve_VariablesNamedAfterGoKeywords(t *testing.T) {
+	src := `
+package resolve.keywords;
+public class Sizes {
+    int len;
+    public int measure(int len, String type, int[] make) {
+        int cap = len + type.length();
+        for (int range : make) {
+            cap += range;
+        }
+        this.len = len;
+        return cap;
+    }
+}
+`
+	out := normalizeSpaces(renderGoFileFromJava(t, src))
+	for _, want := range []string{
+		"Measure(len_ int32, type_ string, make_ []int32) int32",
+		"cap_ := len_ +",
+		"for _, range_ := range make_ { cap_ += range_ }",
+		"ss.len = len_",
+		"return cap_",
+	} {
+		if !strings.Contains(out, normalizeSpaces(want)) {
+			t.Errorf("Expected %q in the output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestResolve_ProtectedMembersUsedFromAnotherPackage(t *testing.T) {
+	helpers := setupPackageHelpers(t, `
+package resolve.protectedbase;
+public class Widget {
+    protected int size;
+    int hidden;
+    protected void layout() { }
+}
+`, `
+package resolve.protectedsub;
+import resolve.protectedbase.Widget;
+public class Button extends Widget {
+    int area() { return this.size * this.size; }
+}
+`)
+
+	helpers[1].Ctx.diagnostics = NewDiagnostics("Button.java")
+	buttonOut := normalizeSpaces(renderGoFileFromJavaCtx(t, helpers[1]))
+	widgetOut := normalizeSpaces(renderGoFileFromJavaCtx(t, helpers[0]))
+
+	for _, expected := range []string{
+		"type Widget struct { Size int32 hidden int32 }",
+		") Layout() {",
+	} {
+		if !strings.Contains(widgetOut, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, widgetOut)
+		}
+	}
+	if !strings.Contains(buttonOut, "return bn.Size * bn.Size") {
+		t.Errorf("Expected the inherited field to be exported, got:\n%s", buttonOut)
+	}
+
+	diagnostics := helpers[1].Ctx.diagnostics.Items()
+	if len(diagnostics) != 1 || diagnostics[0].Kind != protectedExportDiagnostic || diagnostics[0].Line != 4 {
+		t.Errorf("Expected a diagnostic for the exported members, got %v", diagnostics)
+	}
+}
+
+func TestResolve_FieldsAccessedThroughCallChains(t *testing.T) {
+	src := `
+package resolve.chains;
+public class Tree {
+    public static class Node {
+        public Node next;
+        public int value;
+        public Node parent() { return next; }
+    }
+    public Node root;
+    public Node root() { return root; }
+    public int sum(Node other) {
+        return root().next.value + this.root.parent().value + (other).next.value;
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, "return root().Next.Value + te.Root.parent().Value + (other).Next.Value") {
+		t.Errorf("Expected the exported field names in the chains, got:\n%s", out)
+	}
+}
@@ -0,0 +1,96 @@
+package translate
+
+import (
+	"go/ast"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/NickyBoy89/java2go/parsing"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The methods of the classes that load resources, and their names in the
+// runtime's implementation of each class
+var resourceMethods = map[string]map[string]string{
+	"Properties": {
+		"getProperty":         "GetProperty",
+		"setProperty":         "SetProperty",
+		"load":                "Load",
+		"stringPropertyNames": "StringPropertyNames",
+	},
+	"ResourceBundle": {
+		"getString":   "GetString",
+		"containsKey": "ContainsKey",
+	},
+}
+
+// resourceMethod translates the methods that load properties and resource
+// bundles into the runtime's implementation of them, or returns nil if the
+// method isn't one of them
+//
+// Ex: `ResourceBundle.getBundle("messages")` turns into
+// `stdjava.GetBundle("messages", stdjava.DefaultLocale)`
+func resourceMethod(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	objectNode := node.ChildByFieldName("object")
+	name := node.ChildByFieldName("name").Content(source)
+
+	if objectNode.Content(source) == "ResourceBundle" && name == "getBundle" && (len(args) == 1 || len(args) == 2) {
+		return runtimeCall("GetBundle", args[0], runtimeLocale(args[1:]...))
+	}
+
+	javaType, ok := inferExprJavaType(objectNode, ctx, source)
+	if !ok {
+		return nil
+	}
+	if method, ok := resourceMethods[stripJavaQualifier(javaType)][name]; ok {
+		return &ast.CallExpr{Fun: &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: method}}, Args: args}
+	}
+	return nil
+}
+
+// CopyResourceFiles copies the resource files in the given directories into
+// the output directory, next to the code that is generated from the sources
+// beside them, so that the generated code can load them the same way
+func CopyResourceFiles(directories []string, outputDirectory string) error {
+	for _, directory := range directories {
+		resources, err := parsing.ResourcesInDir(directory)
+		if err != nil {
+			return err
+		}
+		for _, resource := range resources {
+			// The resources are already next to the generated code when it is
+			// written beside the sources
+			destination := filepath.Join(outputDirectory, resource)
+			if destination == filepath.Clean(resource) {
+				continue
+			}
+			if err := copyFile(resource, destination); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// copyFile copies a file, creating the directory that it is copied into
+func copyFile(from, to string) error {
+	source, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	destination, err := os.Create(to)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(destination, source); err != nil {
+		destination.Close()
+		return err
+	}
+	return destination.Close()
+}
@@ -0,0 +1,64 @@
+package translate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResourceLoading(t *testing.T) {
+	src := `
+package resources.loading;
+import java.util.*;
+import java.io.*;
+public class Loading {
+    public static String run(Reader in, Locale locale) throws IOException {
+        Properties config = new Properties();
+        config.load(in);
+        ResourceBundle messages = ResourceBundle.getBundle("i18n.messages", locale);
+        ResourceBundle defaults = ResourceBundle.getBundle("i18n.messages");
+        return config.getProperty("port", "8080") + messages.getString("hello") + defaults.getString("bye");
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	for _, expected := range []string{
+		"config := stdjava.NewProperties()",
+		"config.Load(in)",
+		`messages := stdjava.GetBundle("i18n.messages", locale)`,
+		`defaults := stdjava.GetBundle("i18n.messages", stdjava.DefaultLocale)`,
+		`return config.GetProperty("port", "8080") + messages.GetString("hello") + defaults.GetString("bye")`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestCopyResourceFiles(t *testing.T) {
+	sources, output := t.TempDir(), t.TempDir()
+	if err := os.MkdirAll(filepath.Join(sources, "i18n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range map[string]string{
+		"i18n/messages.properties": "hello=Hello\n",
+		"Main.java":                "class Main {}",
+	} {
+		if err := os.WriteFile(filepath.Join(sources, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := CopyResourceFiles([]string{sources}, output); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	copied, err := os.ReadFile(filepath.Join(output, sources, "i18n", "messages.properties"))
+	if err != nil || string(copied) != "hello=Hello\n" {
+		t.Errorf("Expected the resource to be copied, got %q and %v", copied, err)
+	}
+	if _, err := os.Stat(filepath.Join(output, sources, "Main.java")); err == nil {
+		t.Errorf("Expected only the resources to be copied")
+	}
+
+}
@@ -0,0 +1,103 @@
+package translate
+
+import (
+	"go/ast"
+
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// A sealed interface is a Go interface with an extra, unexported marker method,
+// that only the types that implement the interface in Java implement, so that
+// no other type satisfies the interface, and the types that can be in it are
+// the types that it permits, the same as in Java:
+//
+//	sealed interface Shape permits Circle, Square {
+//	    double area();
+//	}
+//
+//	type Shape interface {
+//	    Area() float64
+//	    isShape()
+//	}
+//
+//	func (*Circle) isShape() {}
+//	func (*Square) isShape() {}
+//
+// An interface that extends a sealed interface has its marker method as well,
+// so the types that implement it are in the sealed interface too. A sealed
+// class is a struct, like any other class, which Go can't restrict the
+// subtypes of
+
+// The kind of diagnostic reported for the sealed classes, whose subclasses
+// aren't restricted
+const sealedDiagnostic = "sealed"
+
+// sealedMarkerName returns the name of the marker method of a sealed interface
+func sealedMarkerName(sealed *symbol.ClassScope) string {
+	return "is" + symbol.Uppercase(sealed.Class.Name)
+}
+
+// sealedInterfaces returns the sealed interfaces that a class or interface
+// implements, either directly, or through the interfaces that it implements,
+// including the interface itself if it is sealed. Only the interfaces that are
+// declared in the package are known
+func sealedInterfaces(ctx Ctx, class *symbol.ClassScope) []*symbol.ClassScope {
+	var sealed []*symbol.ClassScope
+	visited := map[*symbol.ClassScope]bool{}
+	var visit func(class *symbol.ClassScope)
+	visit = func(class *symbol.ClassScope) {
+		if class == nil || visited[class] {
+			return
+		}
+		visited[class] = true
+		if class.IsInterface && class.IsSealed {
+			sealed = append(sealed, class)
+		}
+		for _, implemented := range class.Interfaces {
+			if iface := resolveClassScopeByName(ctx, implemented); iface != nil && iface.IsInterface {
+				visit(iface)
+			}
+		}
+	}
+	visit(class)
+	return sealed
+}
+
+// sealedMarkerMethods returns the marker methods of the sealed interfaces that
+// an interface is in, which are added to its methods
+func sealedMarkerMethods(ctx Ctx, class *symbol.ClassScope) []*ast.Field {
+	var methods []*ast.Field
+	for _, sealed := range sealedInterfaces(ctx, class) {
+		methods = append(methods, &ast.Field{
+			Names: []*ast.Ident{{Name: sealedMarkerName(sealed)}},
+			Type:  &ast.FuncType{Params: &ast.FieldList{}},
+		})
+	}
+	return methods
+}
+
+// sealedMarkerDecls implements the marker methods of the sealed interfaces that
+// the current class implements, and reports a sealed class, whose subclasses
+// aren't restricted
+func sealedMarkerDecls(node *sitter.Node, ctx Ctx) []ast.Decl {
+	class := ctx.currentClass
+	if class == nil {
+		return nil
+	}
+	if class.IsSealed && !class.IsInterface {
+		ctx.diagnostics.Report(node.ChildByFieldName("name"), sealedDiagnostic, "the subclasses of the sealed class `%s` aren't restricted, since Go's structs don't have subtypes", class.Class.OriginalName)
+	}
+
+	receiverType := classReceiverType(class, ctx)
+	var decls []ast.Decl
+	for _, sealed := range sealedInterfaces(ctx, class) {
+		decls = append(decls, &ast.FuncDecl{
+			Name: &ast.Ident{Name: sealedMarkerName(sealed)},
+			Recv: &ast.FieldList{List: []*ast.Field{{Type: receiverType}}},
+			Type: &ast.FuncType{Params: &ast.FieldList{}},
+			Body: &ast.BlockStmt{},
+		})
+	}
+	return decls
+}
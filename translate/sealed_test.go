@@ -0,0 +1,79 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSealedInterfaces(t *testing.T) {
+	helper := setupParseHelper(t, `
+package app.sealed;
+public sealed interface Expr permits Expr.Num, Expr.Neg, Expr.Unit, Expr.Binary {
+    int eval();
+
+    record Num(int value) implements Expr {
+        public int eval() {
+            return value;
+        }
+    }
+
+    final class Neg implements Expr {
+        private Expr operand;
+
+        public int eval() {
+            return -operand.eval();
+        }
+    }
+
+    enum Unit implements Expr {
+        ZERO;
+
+        public int eval() {
+            return 0;
+        }
+    }
+
+    non-sealed interface Binary extends Expr {
+        Expr left();
+    }
+}
+`)
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helper))
+	for _, expected := range []string{
+		"type Expr interface { Eval() int32 isExpr() }",
+		"func (*Exprnum) isExpr() { }",
+		"func (*Exprneg) isExpr() { }",
+		"func (Exprunit) isExpr() { }",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	// The interfaces that extend a sealed interface are in it as well
+	_, binary, _ := strings.Cut(out, "type Exprbinary interface {")
+	if binary, _, _ = strings.Cut(binary, "}"); !strings.Contains(binary, "isExpr()") {
+		t.Errorf("Expected the interface that extends the sealed interface to have its marker method, got:\n%s", out)
+	}
+}
+
+func TestSealedClasses(t *testing.T) {
+	helper := setupParseHelper(t, `
+package app.sealed.classes;
+public sealed abstract class Vehicle permits Vehicle.Car {
+    static final class Car extends Vehicle {
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Test.java")
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helper))
+	if strings.Contains(out, "isVehicle") {
+		t.Errorf("Expected a sealed class not to have a marker method, got:\n%s", out)
+	}
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != sealedDiagnostic || items[0].Line != 3 {
+		t.Errorf("Expected the sealed class to be reported on line 3, got %v", items)
+	}
+}
@@ -0,0 +1,218 @@
+package translate
+
+import (
+	"go/ast"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for the methods of a set that aren't
+// translated
+const setDiagnostic = "set"
+
+// A `Set` or a `HashSet` is the runtime's `HashSet`, which is a map of its
+// elements to empty values, so that it is ranged over as a map. The methods
+// that take another collection, such as `addAll`, take it as a set, so any
+// other collection is made into a set of its elements first
+//
+// Ex: `seen.addAll(Arrays.asList(a, b))` turns into
+// `seen.AddAll(stdjava.NewHashSet[string](a, b))`
+
+// The methods of a set that the runtime implements, and whether they take
+// another collection
+var setMethods = map[string]bool{
+	"add":         false,
+	"remove":      false,
+	"contains":    false,
+	"size":        false,
+	"isEmpty":     false,
+	"clear":       false,
+	"addAll":      true,
+	"removeAll":   true,
+	"retainAll":   true,
+	"containsAll": true,
+	"equals":      true,
+}
+
+// setTypeOf returns the Java type of the elements of a Java type, if it is
+// translated into the runtime's set, which it isn't if its elements are hashed
+func setTypeOf(javaType string) (string, bool) {
+	base, typeArgs := parseJavaTypeString(javaType)
+	switch stripJavaQualifier(base) {
+	case "Set", "HashSet":
+		if len(typeArgs) == 1 && hashedClassFor(base, typeArgs) == "" {
+			return typeArgs[0], true
+		}
+	}
+	return "", false
+}
+
+// setExprType returns the Java type of the elements of the set that an
+// expression evaluates to, if it evaluates to one
+func setExprType(node *sitter.Node, ctx Ctx, source []byte) (string, bool) {
+	switch node.Type() {
+	case "parenthesized_expression":
+		return setExprType(node.NamedChild(0), ctx, source)
+	case "object_creation_expression":
+		return setTypeOf(node.ChildByFieldName("type").Content(source))
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	if !ok {
+		return "", false
+	}
+	return setTypeOf(javaType)
+}
+
+// listedElements returns the arguments of a call that lists the elements of a
+// collection, such as `Arrays.asList(a, b)`, or nil if the expression isn't one
+func listedElements(node *sitter.Node, source []byte) []*sitter.Node {
+	if node.Type() != "method_invocation" || node.ChildByFieldName("object") == nil {
+		return nil
+	}
+	switch node.ChildByFieldName("object").Content(source) + "." + node.ChildByFieldName("name").Content(source) {
+	case "Arrays.asList", "List.of", "Set.of":
+		return nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+	}
+	return nil
+}
+
+// collectionElements returns the elements of a collection as the arguments of
+// a variadic call, and whether the last argument is spread over the call
+func collectionElements(node *sitter.Node, collection ast.Expr, element string, ctx Ctx, source []byte) ([]ast.Expr, bool) {
+	listed := listedElements(node, source)
+	// An array that is passed on its own is the list of its elements
+	if len(listed) == 1 {
+		if javaType, ok := inferExprJavaType(listed[0], ctx, source); ok && strings.HasSuffix(javaType, "[]") {
+			return []ast.Expr{ParseExpr(listed[0], source, ctx)}, true
+		}
+	}
+	if listed != nil {
+		elements := make([]ast.Expr, len(listed))
+		for ind, listedElement := range listed {
+			elements[ind] = elementValue(listedElement, ParseExpr(listedElement, source, ctx), element, true, ctx, source)
+		}
+		return elements, false
+	}
+	if _, ok := setExprType(node, ctx, source); ok || sortedClass(node, ctx, source) == "TreeSet" {
+		return []ast.Expr{&ast.CallExpr{Fun: &ast.SelectorExpr{X: collection, Sel: &ast.Ident{Name: "Elements"}}}}, true
+	}
+	if javaType, ok := inferExprJavaType(node, ctx, source); !ok || !strings.HasSuffix(javaType, "[]") {
+		ctx.diagnostics.Report(node, setDiagnostic, "the elements of `%s` are assumed to be a slice", node.Content(source))
+	}
+	return []ast.Expr{collection}, true
+}
+
+// newHashSet creates a set of the given elements
+func newHashSet(element string, elements []ast.Expr, spread bool, ctx Ctx) ast.Expr {
+	created := &ast.CallExpr{
+		Fun:  applyTypeArguments(&ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: "NewHashSet"}}, []ast.Expr{elementType(element, true, ctx)}),
+		Args: elements,
+	}
+	if spread {
+		created.Ellipsis = 1
+	}
+	return created
+}
+
+// asSet returns a collection that is passed to a method of a set as a set
+func asSet(node *sitter.Node, collection ast.Expr, element string, ctx Ctx, source []byte) ast.Expr {
+	if _, ok := setExprType(node, ctx, source); ok {
+		return collection
+	}
+	elements, spread := collectionElements(node, collection, element, ctx, source)
+	return newHashSet(element, elements, spread, ctx)
+}
+
+// setMethod translates a method of a set, or `Set.of`, or returns nil if the
+// method isn't one of them
+//
+// Ex: `seen.contains(name)` turns into `seen.Contains(name)`, and
+// `Set.of("a", "b")` turns into `stdjava.NewHashSet[string]("a", "b")`
+func setMethod(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	objectNode := node.ChildByFieldName("object")
+	name := node.ChildByFieldName("name").Content(source)
+
+	// The type of the elements comes from the variable that the set is
+	// assigned to, since the elements may be untyped constants
+	if objectNode.Content(source) == "Set" && name == "of" {
+		if element, ok := setTypeOf(ctx.expectedType); ok {
+			for ind, argument := range nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments")) {
+				args[ind] = elementValue(argument, args[ind], element, true, ctx, source)
+			}
+			return newHashSet(element, args, false, ctx)
+		}
+		return runtimeCall("NewHashSet", args...)
+	}
+
+	element, ok := setExprType(objectNode, ctx, source)
+	if !ok {
+		return nil
+	}
+	return setMethodOn(node, object, args, element, ctx, source)
+}
+
+// setMethodOn translates a method of a set, which is called on the given
+// object, with the given type of elements
+func setMethodOn(node *sitter.Node, object ast.Expr, args []ast.Expr, element string, ctx Ctx, source []byte) ast.Expr {
+	name := node.ChildByFieldName("name").Content(source)
+	takesCollection, ok := setMethods[name]
+	// A set is only equal to another set
+	if name == "equals" && len(args) == 1 {
+		_, ok = setExprType(node.ChildByFieldName("arguments").NamedChild(0), ctx, source)
+	}
+	if !ok || len(args) > 1 {
+		ctx.diagnostics.Report(node, setDiagnostic, "`%s` isn't implemented by the runtime's set", node.Content(source))
+		return nil
+	}
+	if len(args) == 1 {
+		argument := node.ChildByFieldName("arguments").NamedChild(0)
+		if takesCollection {
+			args[0] = asSet(argument, args[0], element, ctx, source)
+		} else {
+			args[0] = elementValue(argument, args[0], element, true, ctx, source)
+		}
+	}
+	return &ast.CallExpr{Fun: &ast.SelectorExpr{X: object, Sel: CapitalizeIdent(&ast.Ident{Name: name})}, Args: args}
+}
+
+// isCapacity determines if the argument of a collection's constructor is its
+// initial capacity, rather than a collection of its elements
+func isCapacity(node *sitter.Node, ctx Ctx, source []byte) bool {
+	if strings.HasSuffix(node.Type(), "integer_literal") {
+		return true
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	return ok && (javaType == "int" || javaType == "long")
+}
+
+// newSet translates the creation of a set, with the type arguments that it is
+// created with, or returns nil if the created object isn't one
+//
+// Ex: `new HashSet<>()` turns into `stdjava.NewHashSet[string]()`, and
+// `new HashSet<>(names)` turns into `stdjava.NewHashSet[string](names...)`
+func newSet(node *sitter.Node, className string, typeArgs []string, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	// A set with an anonymous class is created as the class, unless the class
+	// only initializes it, and a set of hashed objects isn't a map
+	if stripJavaQualifier(className) != "HashSet" || !isCreatedInPlace(node) || hashedClassFor(className, typeArgs) != "" {
+		return nil
+	}
+	// The set's type has to be known, since it can't be inferred from the
+	// arguments
+	if len(typeArgs) != 1 {
+		ctx.diagnostics.Report(node, setDiagnostic, "the type arguments of `%s` aren't known", node.Content(source))
+		return nil
+	}
+
+	arguments := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+	if len(args) == 0 || isCapacity(arguments[0], ctx, source) {
+		// The initial capacity of the set is ignored
+		return newHashSet(typeArgs[0], nil, false, ctx)
+	}
+	if _, ok := setExprType(arguments[0], ctx, source); ok {
+		return &ast.CallExpr{Fun: &ast.SelectorExpr{X: &ast.Ident{Name: "maps"}, Sel: &ast.Ident{Name: "Clone"}}, Args: args[:1]}
+	}
+	elements, spread := collectionElements(arguments[0], args[0], typeArgs[0], ctx, source)
+	return newHashSet(typeArgs[0], elements, spread, ctx)
+}
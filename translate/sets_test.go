@@ -0,0 +1,61 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSets(t *testing.T) {
+	helper := setupParseHelper(t, `
+package util.sets;
+import java.util.*;
+public class Words {
+    public static Set<String> unique(String[] words, Set<String> stop) {
+        Set<String> seen = new HashSet<>(16);
+        Set<String> copy = new HashSet<>(stop);
+        Set<Integer> lengths = Set.of(1, 2, 3);
+        for (String w : words) {
+            if (!seen.add(w)) {
+                seen.remove(w);
+            }
+        }
+        seen.removeAll(stop);
+        seen.retainAll(Arrays.asList("a", "b"));
+        seen.addAll(Arrays.asList(words));
+        for (String w : seen) {
+            if (lengths.contains(w.length()) && copy.containsAll(seen)) {
+                seen.clear();
+            }
+        }
+        seen.iterator();
+        return seen;
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Words.java")
+
+	out := renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		"func Unique(words []string, stop stdjava.HashSet[string]) stdjava.HashSet[string] {",
+		"seen := stdjava.NewHashSet[string]()",
+		"copy := maps.Clone(stop)",
+		"lengths := stdjava.NewHashSet[int32](1, 2, 3)",
+		"if !seen.Add(w) {",
+		"seen.Remove(w)",
+		"seen.RemoveAll(stop)",
+		"seen.RetainAll(stdjava.NewHashSet[string](\"a\", \"b\"))",
+		"seen.AddAll(stdjava.NewHashSet[string](words...))",
+		"for w := range seen {",
+		"copy.ContainsAll(seen)",
+		"seen.Clear()",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != setDiagnostic || items[0].Line != 22 {
+		t.Errorf("Expected the untranslated method on line 22 to be reported, got %v", items)
+	}
+}
@@ -0,0 +1,326 @@
+package translate
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// With `-simplify`, the generated file is type checked with `go/types` once it
+// has been generated, and the conversions and type assertions whose operands
+// already have the types that they convert to are removed, such as the casts
+// of Java that convert a value to its own type, or the conversions that are
+// added around the results of the helpers, which are often the same type:
+//
+//	total := int64(count) + int64(size)       total := count + int64(size)
+//
+// The generated code refers to classes that might not be in the same file, or
+// might not have been translated at all, so the errors of the type checker are
+// ignored. A conversion is only removed if the types of both of its sides are
+// fully known, and are identical
+
+// Simplify removes the conversions and the type assertions from a generated
+// file that don't change the types of their operands, as described above
+func Simplify(file *ast.File) {
+	rewriteExprs(file, func(_ ast.Node, _ string, expr ast.Expr) ast.Expr {
+		return parsedIdent(expr)
+	})
+	info := &types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+	if !checkTypes(file, info) {
+		return
+	}
+
+	rewriteExprs(file, func(parent ast.Node, field string, expr ast.Expr) ast.Expr {
+		operand := redundantConversion(expr, info)
+		if operand == nil {
+			return expr
+		}
+		if needsParentheses(parent, field, operand) {
+			return &ast.ParenExpr{X: operand}
+		}
+		return operand
+	})
+	importPackages(file)
+}
+
+// parsedIdent parses an identifier that holds a whole expression, such as a
+// number, or a pointer to a type, into the same expression, which is printed
+// the same, but which the type checker understands. Any other expression is
+// returned as it is
+//
+// Ex: the identifier `1` becomes the literal `1`
+func parsedIdent(expr ast.Expr) ast.Expr {
+	ident, ok := expr.(*ast.Ident)
+	if !ok || ident.Name == "" || token.IsIdentifier(ident.Name) {
+		return expr
+	}
+	parsed, err := parser.ParseExpr(ident.Name)
+	if err != nil {
+		return expr
+	}
+	// The positions of the parsed expression are in a file of its own
+	walkNodes(parsed, func(node ast.Node) {
+		structure := reflect.ValueOf(node).Elem()
+		for i := 0; i < structure.NumField(); i++ {
+			if field := structure.Field(i); field.Type() == reflect.TypeOf(token.NoPos) {
+				field.SetInt(int64(token.NoPos))
+			}
+		}
+	})
+	return parsed
+}
+
+// checkTypes type checks a generated file, filling in the types of its
+// expressions, and returns false if the type checker can't check it at all
+func checkTypes(file *ast.File, info *types.Info) (checked bool) {
+	// The type checker expects the code to have been parsed, and panics on some
+	// of the nodes that the parser never produces
+	defer func() {
+		if recover() != nil {
+			checked = false
+		}
+	}()
+
+	positions, restore := numberPositions(file)
+	defer restore()
+	config := &types.Config{
+		Importer: simplifyImporter(),
+		Error:    func(error) {},
+	}
+	config.Check(file.Name.Name, positions, []*ast.File{file}, info)
+	return true
+}
+
+// numberPositions gives every position in a node a position of its own, in
+// the order that the node's parts are printed in, in a file that only the type
+// checker sees, since it looks up the names that are declared before each
+// position, and expects each of them to be in a file. It returns the file,
+// and a function that puts back the positions that the node had before
+func numberPositions(node ast.Node) (*token.FileSet, func()) {
+	var fields []reflect.Value
+	var original []token.Pos
+	walkNodes(node, func(node ast.Node) {
+		structure := reflect.ValueOf(node).Elem()
+		for i := 0; i < structure.NumField(); i++ {
+			if field := structure.Field(i); field.Type() == reflect.TypeOf(token.NoPos) {
+				fields = append(fields, field)
+				original = append(original, token.Pos(field.Int()))
+			}
+		}
+	})
+
+	positions := token.NewFileSet()
+	file := positions.AddFile("", -1, len(fields)+1)
+	for i, field := range fields {
+		field.SetInt(int64(file.Pos(i + 1)))
+	}
+	return positions, func() {
+		for i, field := range fields {
+			field.SetInt(int64(original[i]))
+		}
+	}
+}
+
+// simplifyImporter is the importer of the packages that the generated files
+// import, which is shared between the files, so that each package is only
+// imported once
+var simplifyImporter = sync.OnceValue(func() types.Importer {
+	return lenientImporter{[]types.Importer{importer.Default(), importer.ForCompiler(token.NewFileSet(), "source", nil)}}
+})
+
+// A lenientImporter imports the packages that the compiler has built, or
+// failing that, the packages whose sources it can find, such as the ones of
+// the standard library, and imports any other package as an empty package,
+// whose members are all unknown to the type checker
+type lenientImporter struct {
+	importers []types.Importer
+}
+
+func (li lenientImporter) Import(path string) (*types.Package, error) {
+	for _, importer := range li.importers {
+		if imported, err := importer.Import(path); err == nil {
+			return imported, nil
+		}
+	}
+	name := path[strings.LastIndex(path, "/")+1:]
+	imported := types.NewPackage(path, name)
+	imported.MarkComplete()
+	return imported, nil
+}
+
+// redundantConversion returns the operand of a conversion or a type assertion
+// that has the same type as its operand, or nil if the expression is anything
+// else
+//
+// A constant that doesn't have a type of its own takes its type from the
+// conversion, so a conversion of one isn't redundant
+func redundantConversion(expr ast.Expr, info *types.Info) ast.Expr {
+	var operand, target ast.Expr
+	switch expr := expr.(type) {
+	case *ast.CallExpr:
+		if len(expr.Args) != 1 || expr.Ellipsis.IsValid() || !info.Types[expr.Fun].IsType() {
+			return nil
+		}
+		operand, target = expr.Args[0], expr.Fun
+	case *ast.TypeAssertExpr:
+		if expr.Type == nil {
+			return nil
+		}
+		operand, target = expr.X, expr.Type
+	default:
+		return nil
+	}
+
+	operandType, targetType := info.Types[operand].Type, info.Types[target].Type
+	if !isKnownType(operandType, map[types.Type]bool{}) || !isKnownType(targetType, map[types.Type]bool{}) {
+		return nil
+	}
+	if basic, ok := operandType.(*types.Basic); ok && basic.Info()&types.IsUntyped != 0 {
+		return nil
+	}
+	if !types.Identical(operandType, targetType) {
+		return nil
+	}
+	return operand
+}
+
+// isKnownType determines if the type checker knows every part of a type,
+// since the types that it doesn't know are all identical to each other
+func isKnownType(typ types.Type, visited map[types.Type]bool) bool {
+	if typ == nil {
+		return false
+	}
+	if visited[typ] {
+		return true
+	}
+	visited[typ] = true
+
+	switch typ := typ.(type) {
+	case *types.Basic:
+		return typ.Kind() != types.Invalid
+	case *types.Pointer:
+		return isKnownType(typ.Elem(), visited)
+	case *types.Slice:
+		return isKnownType(typ.Elem(), visited)
+	case *types.Array:
+		return isKnownType(typ.Elem(), visited)
+	case *types.Chan:
+		return isKnownType(typ.Elem(), visited)
+	case *types.Map:
+		return isKnownType(typ.Key(), visited) && isKnownType(typ.Elem(), visited)
+	case *types.Signature:
+		return isKnownType(typ.Params(), visited) && isKnownType(typ.Results(), visited)
+	case *types.Tuple:
+		for i := 0; i < typ.Len(); i++ {
+			if !isKnownType(typ.At(i).Type(), visited) {
+				return false
+			}
+		}
+		return true
+	case *types.Struct:
+		for i := 0; i < typ.NumFields(); i++ {
+			if !isKnownType(typ.Field(i).Type(), visited) {
+				return false
+			}
+		}
+		return true
+	case *types.Named:
+		for i := 0; i < typ.TypeArgs().Len(); i++ {
+			if !isKnownType(typ.TypeArgs().At(i), visited) {
+				return false
+			}
+		}
+		return isKnownType(typ.Underlying(), visited)
+	case *types.Alias:
+		return isKnownType(types.Unalias(typ), visited)
+	case *types.Interface, *types.TypeParam:
+		return true
+	}
+	return false
+}
+
+// needsParentheses determines if an operand that takes the place of the
+// conversion that was around it has to be put in parentheses, where it is an
+// operand of an operator that binds more tightly than its own
+func needsParentheses(parent ast.Node, field string, operand ast.Expr) bool {
+	switch operand.(type) {
+	case *ast.BinaryExpr:
+		switch parent.(type) {
+		case *ast.BinaryExpr, *ast.UnaryExpr, *ast.StarExpr:
+			return true
+		}
+	case *ast.UnaryExpr, *ast.StarExpr:
+	default:
+		return false
+	}
+	switch parent.(type) {
+	case *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr, *ast.SliceExpr, *ast.TypeAssertExpr:
+		return field == "X"
+	case *ast.CallExpr:
+		return field == "Fun"
+	}
+	return false
+}
+
+// rewriteExprs replaces every expression in a node with the expression that a
+// function returns for it, from the innermost expressions outwards, along with
+// the node that holds the expression, and the name of its field
+func rewriteExprs(node ast.Node, rewrite func(parent ast.Node, field string, expr ast.Expr) ast.Expr) {
+	exprType := reflect.TypeOf((*ast.Expr)(nil)).Elem()
+	eachChild(node, func(field string, value reflect.Value) {
+		child := value.Interface().(ast.Node)
+		rewriteExprs(child, rewrite)
+		if value.Type() == exprType {
+			value.Set(reflect.ValueOf(rewrite(node, field, child.(ast.Expr))))
+		}
+	})
+}
+
+// walkNodes calls a function for a node, and then for each of the nodes in
+// it, in the order that they are printed in
+func walkNodes(node ast.Node, visit func(ast.Node)) {
+	if value := reflect.ValueOf(node); value.Kind() != reflect.Pointer || value.IsNil() {
+		return
+	}
+	visit(node)
+	eachChild(node, func(_ string, value reflect.Value) {
+		walkNodes(value.Interface().(ast.Node), visit)
+	})
+}
+
+// eachChild calls a function for each of the fields of a node that hold a
+// node that isn't nil, along with the name of the field. The nodes of a list
+// are each passed on their own
+//
+// Unlike ast.Inspect, the function is given the field itself, which it can
+// replace the node in
+func eachChild(node ast.Node, visit func(field string, value reflect.Value)) {
+	value := reflect.ValueOf(node)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return
+	}
+	nodeType := reflect.TypeOf((*ast.Node)(nil)).Elem()
+
+	structure := value.Elem()
+	for i := 0; i < structure.NumField(); i++ {
+		field := structure.Field(i)
+		name := structure.Type().Field(i).Name
+		switch {
+		case (field.Kind() == reflect.Interface || field.Kind() == reflect.Pointer) && field.Type().Implements(nodeType):
+			if !field.IsNil() {
+				visit(name, field)
+			}
+		case field.Kind() == reflect.Slice && field.Type().Elem().Implements(nodeType):
+			for j := 0; j < field.Len(); j++ {
+				if !field.Index(j).IsNil() {
+					visit(name, field.Index(j))
+				}
+			}
+		}
+	}
+}
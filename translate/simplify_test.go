@@ -0,0 +1,101 @@
+package translate
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestSimplify(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			"IdentityConversions",
+			`func f(count int32, size int64) int64 { return int64(size) + int64(count) }`,
+			`func f(count int32, size int64) int64 { return size + int64(count) }`,
+		},
+		{
+			"ConvertedOperation",
+			`func f(size int64) int64 { return int64(size-1) * 3 }`,
+			`func f(size int64) int64 { return (size - 1) * 3 }`,
+		},
+		{
+			"UntypedConstants",
+			`func f() float64 { return float64(1) + float64(2.5) }`,
+			`func f() float64 { return float64(1) + float64(2.5) }`,
+		},
+		{
+			"IdentityAssertions",
+			`func f(err error, value any) (error, string) { return err.(error), value.(string) }`,
+			`func f(err error, value any) (error, string) { return err, value.(string) }`,
+		},
+		{
+			"UnknownTypes",
+			`import "github.com/NickyBoy89/java2go/stdjava"; func f(a *Shape, b []Node, c map[string]*stdjava.Entry) { g(*Shape(a), []Node(b), map[string]*stdjava.Entry(c)) }`,
+			`import "github.com/NickyBoy89/java2go/stdjava"; func f(a *Shape, b []Node, c map[string]*stdjava.Entry) { g(*Shape(a), []Node(b), map[string]*stdjava.Entry(c)) }`,
+		},
+		{
+			"StandardLibrary",
+			`import "strings"; func f(s string) string { return string(strings.TrimSpace(s)) }`,
+			`import "strings"; func f(s string) string { return strings.TrimSpace(s) }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := simplifySource(t, "package simplify\n"+tt.src)
+			if want := normalizeSpaces("package simplify\n" + strings.ReplaceAll(tt.want, ";", "")); got != want {
+				t.Errorf("Simplify() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestSimplifyGeneratedCode(t *testing.T) {
+	helper := setupParseHelper(t, `
+public class Sizes {
+    long total(int count, long size, double ratio) {
+        long all = (long) size + (long) count;
+        double scaled = (double) ratio * (double) 2;
+        return all + (long) (size - 1) * 3;
+    }
+}
+`)
+	file := ParseNode(helper.File.Ast, helper.File.Source, helper.Ctx).(*ast.File)
+	Simplify(file)
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), file); err != nil {
+		t.Fatalf("Failed to print the source: %v", err)
+	}
+	out := buf.String()
+	for _, expected := range []string{
+		"all := size + int64(count)",
+		"scaled := ratio * float64(2)",
+		"return all + (size-1)*3",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func simplifySource(t *testing.T, src string) string {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse the source: %v", err)
+	}
+	Simplify(file)
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), file); err != nil {
+		t.Fatalf("Failed to print the source: %v", err)
+	}
+	return normalizeSpaces(buf.String())
+}
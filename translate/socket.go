@@ -0,0 +1,167 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for the parts of Java's sockets and streams
+// that the runtime doesn't implement
+const streamDiagnostic = "stream"
+
+// Java's byte streams are translated into Go's `io.Reader` and `io.Writer`,
+// and the readers and writers that decode and encode their characters are the
+// same streams, so a socket's streams are used the same way as any other
+
+// The methods of the sockets and the streams that the runtime implements, and
+// their names in the runtime. The methods of the byte streams are functions of
+// the runtime, which take the stream as their first argument
+var streamMethods = map[string]map[string]string{
+	"ServerSocket": {
+		"accept":       "Accept",
+		"getLocalPort": "GetLocalPort",
+		"close":        "Close",
+		"isClosed":     "IsClosed",
+	},
+	"Socket": {
+		"getInputStream":  "GetInputStream",
+		"getOutputStream": "GetOutputStream",
+		"setSoTimeout":    "SetSoTimeout",
+		"getPort":         "GetPort",
+		"getLocalPort":    "GetLocalPort",
+		"close":           "Close",
+		"isClosed":        "IsClosed",
+	},
+	"BufferedReader": {"readLine": "ReadLine", "close": "Close"},
+	"PrintWriter": {
+		"print":   "Print",
+		"println": "Println",
+		"printf":  "Printf",
+		"flush":   "Flush",
+		"close":   "Close",
+	},
+	"InputStream":  {"read": "Read", "close": "Close"},
+	"OutputStream": {"write": "Write", "flush": "Flush", "close": "Close"},
+}
+
+// The classes that are translated into one of the classes above
+var streamClasses = map[string]string{
+	"ServerSocket":       "ServerSocket",
+	"Socket":             "Socket",
+	"BufferedReader":     "BufferedReader",
+	"PrintWriter":        "PrintWriter",
+	"InputStream":        "InputStream",
+	"InputStreamReader":  "InputStream",
+	"OutputStream":       "OutputStream",
+	"OutputStreamWriter": "OutputStream",
+}
+
+// streamClass returns which of the sockets or the streams an expression
+// evaluates to, or an empty string if it isn't one of them
+func streamClass(node *sitter.Node, ctx Ctx, source []byte) string {
+	switch node.Type() {
+	case "parenthesized_expression":
+		return streamClass(node.NamedChild(0), ctx, source)
+	case "object_creation_expression":
+		return streamClasses[stripJavaQualifier(node.ChildByFieldName("type").Content(source))]
+	case "method_invocation":
+		object := node.ChildByFieldName("object")
+		if object == nil {
+			break
+		}
+		switch node.ChildByFieldName("name").Content(source) {
+		case "accept":
+			if streamClass(object, ctx, source) == "ServerSocket" {
+				return "Socket"
+			}
+		case "getInputStream":
+			return "InputStream"
+		case "getOutputStream":
+			return "OutputStream"
+		}
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	if !ok {
+		return ""
+	}
+	base, _ := parseJavaTypeString(javaType)
+	return streamClasses[stripJavaQualifier(base)]
+}
+
+// streamMethod translates a method of a socket or a stream that the runtime
+// implements, or returns nil if the method isn't called on one of them
+//
+// Ex: `server.accept()` turns into `server.Accept()`, and `out.write(data)`
+// turns into `stdjava.Write(out, data)`
+func streamMethod(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	class := streamClass(node.ChildByFieldName("object"), ctx, source)
+	if class == "" {
+		return nil
+	}
+	name := node.ChildByFieldName("name").Content(source)
+	method, ok := streamMethods[class][name]
+	if !ok {
+		ctx.diagnostics.Report(node, streamDiagnostic, "`%s` isn't implemented by the runtime's `%s`", node.Content(source), class)
+		return nil
+	}
+	if class == "InputStream" || class == "OutputStream" {
+		return runtimeCall(method, append([]ast.Expr{object}, args...)...)
+	}
+	return &ast.CallExpr{Fun: &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: method}}, Args: args}
+}
+
+// newStreamObject translates the creation of a socket or a stream, or returns
+// nil if the created object isn't one of them
+//
+// Ex: `new BufferedReader(new InputStreamReader(in))` turns into
+// `stdjava.NewBufferedReader(in)`
+func newStreamObject(className string, args []ast.Expr) ast.Expr {
+	switch className {
+	case "ServerSocket", "Socket", "BufferedReader", "PrintWriter":
+		return runtimeCall("New"+className, args...)
+	// The character set of a reader or a writer is always UTF-8
+	case "InputStreamReader", "OutputStreamWriter":
+		if len(args) > 0 {
+			return args[0]
+		}
+	}
+	return nil
+}
+
+// readLineLoop translates a loop over the lines of a `BufferedReader`, which
+// Java ends when `readLine` returns null, into a loop that scans the lines, or
+// returns nil if the loop isn't one
+//
+// Ex: `while ((line = in.readLine()) != null) { ... }` becomes
+// `for in.Scan() { line = in.Text(); ... }`
+func readLineLoop(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	condition := unwrapParentheses(node.ChildByFieldName("condition"))
+	if condition.Type() != "binary_expression" || condition.ChildByFieldName("operator").Type() != "!=" ||
+		condition.ChildByFieldName("right").Type() != "null_literal" {
+		return nil
+	}
+	assignment := unwrapParentheses(condition.ChildByFieldName("left"))
+	if assignment.Type() != "assignment_expression" || assignment.ChildByFieldName("operator").Type() != "=" {
+		return nil
+	}
+	read := assignment.ChildByFieldName("right")
+	if read.Type() != "method_invocation" || read.ChildByFieldName("object") == nil ||
+		read.ChildByFieldName("name").Content(source) != "readLine" ||
+		streamClass(read.ChildByFieldName("object"), ctx, source) != "BufferedReader" {
+		return nil
+	}
+
+	reader := ParseExpr(read.ChildByFieldName("object"), source, ctx)
+	body := parseLoopBody(node.ChildByFieldName("body"), source, ctx)
+	body.List = append([]ast.Stmt{&ast.AssignStmt{
+		Lhs: []ast.Expr{ParseExpr(assignment.ChildByFieldName("left"), source, ctx)},
+		Tok: token.ASSIGN,
+		Rhs: []ast.Expr{&ast.CallExpr{Fun: &ast.SelectorExpr{X: reader, Sel: &ast.Ident{Name: "Text"}}}},
+	}}, body.List...)
+	return &ast.ForStmt{
+		Cond: &ast.CallExpr{Fun: &ast.SelectorExpr{X: reader, Sel: &ast.Ident{Name: "Scan"}}},
+		Body: body,
+	}
+}
@@ -0,0 +1,58 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSocketsAndStreams(t *testing.T) {
+	helper := setupParseHelper(t, `
+package net.echo;
+import java.io.*;
+import java.net.*;
+public class Echo {
+    public static void serve(int port) throws IOException {
+        ServerSocket server = new ServerSocket(port);
+        Socket client = server.accept();
+        BufferedReader in = new BufferedReader(new InputStreamReader(client.getInputStream()));
+        PrintWriter out = new PrintWriter(client.getOutputStream(), true);
+        String line;
+        while ((line = in.readLine()) != null) {
+            out.println("echo " + line);
+        }
+        client.shutdownInput();
+        client.close();
+    }
+    public static int copy(InputStream in, OutputStream out, byte[] buffer) {
+        out.write(buffer);
+        out.flush();
+        return in.read(buffer);
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Echo.java")
+
+	out := renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		"\"io\"",
+		"server := stdjava.NewServerSocket(port)",
+		"client := server.Accept()",
+		"in := stdjava.NewBufferedReader(client.GetInputStream())",
+		"out := stdjava.NewPrintWriter(client.GetOutputStream(), true)",
+		"for in.Scan() {\n\t\tline = in.Text()\n\t\tout.Println(\"echo \" + line)\n\t}",
+		"client.Close()",
+		"func Copy(in io.Reader, out io.Writer, buffer []byte) int32 {",
+		"stdjava.Write(out, buffer)",
+		"stdjava.Flush(out)",
+		"return stdjava.Read(in, buffer)",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != streamDiagnostic || items[0].Line != 15 {
+		t.Errorf("Expected the unimplemented method on line 15 to be reported, got %v", items)
+	}
+}
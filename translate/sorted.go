@@ -0,0 +1,296 @@
+package translate
+
+import (
+	"go/ast"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/astutil"
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for the parts of Java's sorted collections
+// that the runtime doesn't implement
+const sortedDiagnostic = "sorted-collection"
+
+// A `TreeMap` or a `TreeSet`, and the interfaces that they implement, such as
+// `NavigableMap`, are translated into the runtime's sorted collections, which
+// keep the same order as Java, and return nil for a key that doesn't exist,
+// such as the `ceilingKey` of a key that is larger than every other key
+
+// The methods of the sorted collections that the runtime implements, and their
+// names in the runtime
+var sortedMethods = map[string]map[string]string{
+	"TreeMap": {
+		"put":              "Put",
+		"get":              "Get",
+		"getOrDefault":     "GetOrDefault",
+		"containsKey":      "ContainsKey",
+		"remove":           "Remove",
+		"size":             "Size",
+		"isEmpty":          "IsEmpty",
+		"clear":            "Clear",
+		"firstKey":         "FirstKey",
+		"lastKey":          "LastKey",
+		"floorKey":         "FloorKey",
+		"ceilingKey":       "CeilingKey",
+		"lowerKey":         "LowerKey",
+		"higherKey":        "HigherKey",
+		"firstEntry":       "FirstEntry",
+		"lastEntry":        "LastEntry",
+		"floorEntry":       "FloorEntry",
+		"ceilingEntry":     "CeilingEntry",
+		"lowerEntry":       "LowerEntry",
+		"higherEntry":      "HigherEntry",
+		"pollFirstEntry":   "PollFirstEntry",
+		"pollLastEntry":    "PollLastEntry",
+		"headMap":          "HeadMap",
+		"tailMap":          "TailMap",
+		"subMap":           "SubMap",
+		"keySet":           "KeySet",
+		"navigableKeySet":  "KeySet",
+		"descendingKeySet": "DescendingKeySet",
+		"values":           "Values",
+		"entrySet":         "EntrySet",
+	},
+	"TreeSet": {
+		"add":           "Add",
+		"remove":        "Remove",
+		"contains":      "Contains",
+		"size":          "Size",
+		"isEmpty":       "IsEmpty",
+		"clear":         "Clear",
+		"first":         "First",
+		"last":          "Last",
+		"floor":         "Floor",
+		"ceiling":       "Ceiling",
+		"lower":         "Lower",
+		"higher":        "Higher",
+		"pollFirst":     "PollFirst",
+		"pollLast":      "PollLast",
+		"headSet":       "HeadSet",
+		"tailSet":       "TailSet",
+		"subSet":        "SubSet",
+		"descendingSet": "DescendingSet",
+	},
+}
+
+// The sorted collections that a method returns, for the methods that return a
+// part of the collection that they are called on
+var sortedViews = map[string]bool{
+	"headMap": true, "tailMap": true, "subMap": true,
+	"headSet": true, "tailSet": true, "subSet": true,
+}
+
+// The methods of the sorted collections that return one of their keys, or one
+// of the elements of a sorted set, which are its keys
+var sortedKeyMethods = map[string]bool{
+	"firstKey": true, "lastKey": true, "floorKey": true, "ceilingKey": true, "lowerKey": true, "higherKey": true,
+	"first": true, "last": true, "floor": true, "ceiling": true, "lower": true, "higher": true, "pollFirst": true, "pollLast": true,
+}
+
+// The methods of the sorted collections that return a pointer to a key, which
+// is nil if there isn't one, such as the `ceilingKey` of the largest key
+var sortedNullableMethods = map[string]bool{
+	"floorKey": true, "ceilingKey": true, "lowerKey": true, "higherKey": true,
+	"floor": true, "ceiling": true, "lower": true, "higher": true, "pollFirst": true, "pollLast": true,
+}
+
+// The methods of a sorted map that return one of its values
+var sortedValueMethods = map[string]bool{
+	"get": true, "getOrDefault": true, "put": true, "remove": true,
+}
+
+// sortedMethodJavaType returns the Java type of the key or the value that a
+// method of a sorted collection returns
+func sortedMethodJavaType(node *sitter.Node, ctx Ctx, source []byte) (string, bool) {
+	object := node.ChildByFieldName("object")
+	if object == nil {
+		return "", false
+	}
+	class := sortedClass(object, ctx, source)
+	typeArgs := sortedTypeArgs(object, ctx, source)
+	switch name := node.ChildByFieldName("name").Content(source); {
+	case class == "TreeMap" && sortedValueMethods[name] && len(typeArgs) == 2:
+		return typeArgs[1], true
+	case class != "" && sortedKeyMethods[name] && len(typeArgs) > 0:
+		return typeArgs[0], true
+	}
+	return "", false
+}
+
+// isSortedBox determines if a method of a sorted collection returns a box, for
+// the keys or the values that are wrapper classes. The keys are the primitives
+// that they box, other than the ones that can be nil, and the values are too,
+// unless they are kept boxed
+func isSortedBox(node *sitter.Node, javaType string, source []byte) bool {
+	if !isBoxedType(javaType) {
+		return false
+	}
+	name := node.ChildByFieldName("name").Content(source)
+	return sortedNullableMethods[name] || !sortedKeyMethods[name] && astutil.BoxedElements
+}
+
+// sortedClass returns which of the runtime's sorted collections an expression
+// evaluates to, or an empty string if it isn't one of them
+func sortedClass(node *sitter.Node, ctx Ctx, source []byte) string {
+	switch node.Type() {
+	case "parenthesized_expression":
+		return sortedClass(node.NamedChild(0), ctx, source)
+	case "object_creation_expression":
+		return sortedClassOf(node.ChildByFieldName("type").Content(source))
+	case "method_invocation":
+		if object := node.ChildByFieldName("object"); object != nil && sortedViews[node.ChildByFieldName("name").Content(source)] {
+			return sortedClass(object, ctx, source)
+		}
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	if !ok {
+		return ""
+	}
+	return sortedClassOf(javaType)
+}
+
+// sortedClassOf returns which of the runtime's sorted collections a Java type
+// is represented as, or an empty string if it isn't one of them
+func sortedClassOf(javaType string) string {
+	base, _ := parseJavaTypeString(javaType)
+	switch stripJavaQualifier(base) {
+	case "TreeMap", "SortedMap", "NavigableMap":
+		return "TreeMap"
+	case "TreeSet", "SortedSet", "NavigableSet":
+		return "TreeSet"
+	}
+	return ""
+}
+
+// sortedTypeArgs returns the Java type arguments of the sorted collection that
+// an expression evaluates to, or nil if they aren't known
+func sortedTypeArgs(node *sitter.Node, ctx Ctx, source []byte) []string {
+	switch node.Type() {
+	case "parenthesized_expression":
+		return sortedTypeArgs(node.NamedChild(0), ctx, source)
+	case "object_creation_expression":
+		_, typeArgs := parseJavaTypeString(node.ChildByFieldName("type").Content(source))
+		return typeArgs
+	case "method_invocation":
+		if object := node.ChildByFieldName("object"); object != nil && sortedViews[node.ChildByFieldName("name").Content(source)] {
+			return sortedTypeArgs(object, ctx, source)
+		}
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	if !ok {
+		return nil
+	}
+	_, typeArgs := parseJavaTypeString(javaType)
+	return typeArgs
+}
+
+// isSortedEntryExpr determines if the given expression evaluates to one of the
+// entries of a sorted map, such as its `firstEntry`
+func isSortedEntryExpr(node *sitter.Node, ctx Ctx, source []byte) bool {
+	switch node.Type() {
+	case "parenthesized_expression":
+		return isSortedEntryExpr(node.NamedChild(0), ctx, source)
+	case "method_invocation":
+		object := node.ChildByFieldName("object")
+		return object != nil && strings.HasSuffix(node.ChildByFieldName("name").Content(source), "Entry") &&
+			sortedClass(object, ctx, source) == "TreeMap"
+	}
+	return false
+}
+
+// sortedMethod translates a method of a sorted collection, or returns nil if
+// the method isn't called on one of them
+//
+// Ex: `scores.ceilingKey(50)` turns into `scores.CeilingKey(50)`, and
+// `scores.subMap(10, true, 20, true)` turns into
+// `scores.SubMapInclusive(10, true, 20, true)`
+func sortedMethod(node *sitter.Node, object ast.Expr, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	name := node.ChildByFieldName("name").Content(source)
+	class := sortedClass(node.ChildByFieldName("object"), ctx, source)
+	if class == "" {
+		return nil
+	}
+	method, ok := sortedMethods[class][name]
+	if !ok {
+		ctx.diagnostics.Report(node, sortedDiagnostic, "`%s` isn't implemented by the runtime's `%s`", node.Content(source), class)
+		return nil
+	}
+	if (name == "subMap" || name == "subSet") && len(args) == 4 {
+		method += "Inclusive"
+	}
+
+	// The keys and the values that are passed to the method are the
+	// collection's own, where the bounds of a view are keys as well
+	if typeArgs := sortedTypeArgs(node.ChildByFieldName("object"), ctx, source); len(typeArgs) > 0 {
+		arguments := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+		for ind, argument := range arguments {
+			switch {
+			case ind == 0, (name == "subMap" || name == "subSet") && ind == len(arguments)/2:
+				args[ind] = elementValue(argument, args[ind], typeArgs[0], true, ctx, source)
+			case ind == 1 && (name == "put" || name == "getOrDefault") && len(typeArgs) == 2:
+				args[ind] = elementValue(argument, args[ind], typeArgs[1], false, ctx, source)
+			}
+		}
+	}
+	return &ast.CallExpr{Fun: &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: method}}, Args: args}
+}
+
+// isReverseOrder determines if an expression is Java's comparator for the
+// reverse of the natural order
+func isReverseOrder(node *sitter.Node, source []byte) bool {
+	if node.Type() != "method_invocation" || node.ChildByFieldName("object") == nil || node.ChildByFieldName("arguments").NamedChildCount() > 0 {
+		return false
+	}
+	switch node.ChildByFieldName("object").Content(source) + "." + node.ChildByFieldName("name").Content(source) {
+	case "Comparator.reverseOrder", "Collections.reverseOrder":
+		return true
+	}
+	return false
+}
+
+// newSortedCollection translates the creation of a sorted collection, with
+// the type arguments that it is created with, or returns nil if the created
+// object isn't one of them
+//
+// Ex: `new TreeMap<String, Integer>()` turns into
+// `stdjava.NewTreeMap[string, *Integer]()`, and
+// `new TreeSet<>(Comparator.reverseOrder())` turns into
+// `stdjava.NewTreeSetFunc[int32](stdjava.ReverseOrder[int32])`
+func newSortedCollection(node *sitter.Node, className string, typeArgs []string, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	class := stripJavaQualifier(className)
+	if class != "TreeMap" && class != "TreeSet" {
+		return nil
+	}
+	// The collection's type has to be known, since it can't be inferred from
+	// the arguments
+	if class == "TreeMap" && len(typeArgs) != 2 || class == "TreeSet" && len(typeArgs) != 1 {
+		ctx.diagnostics.Report(node, sortedDiagnostic, "the type arguments of `%s` aren't known", node.Content(source))
+		return nil
+	}
+
+	typeArgExprs := make([]ast.Expr, len(typeArgs))
+	for ind, typeArg := range typeArgs {
+		typeArgExprs[ind] = elementType(typeArg, ind == 0, ctx)
+	}
+
+	switch len(args) {
+	case 0:
+		return &ast.CallExpr{Fun: applyTypeArguments(&ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: "New" + class}}, typeArgExprs)}
+	case 1:
+		comparator := args[0]
+		arguments := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+		if isReverseOrder(arguments[0], source) {
+			comparator = applyTypeArguments(&ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: "ReverseOrder"}}, typeArgExprs[:1])
+		} else if sortedClass(arguments[0], ctx, source) != "" || arguments[0].Type() == "object_creation_expression" {
+			break
+		}
+		return &ast.CallExpr{
+			Fun:  applyTypeArguments(&ast.SelectorExpr{X: &ast.Ident{Name: "stdjava"}, Sel: &ast.Ident{Name: "New" + class + "Func"}}, typeArgExprs),
+			Args: []ast.Expr{comparator},
+		}
+	}
+	ctx.diagnostics.Report(node, sortedDiagnostic, "`%s` isn't implemented by the runtime's `%s`", node.Content(source), class)
+	return nil
+}
@@ -0,0 +1,55 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSortedCollections(t *testing.T) {
+	helper := setupParseHelper(t, `
+package util.sorted;
+import java.util.*;
+public class Grades {
+    public static String grade(TreeMap<Integer, String> grades, int score) {
+        if (grades.floorKey(score) == null) {
+            return "F";
+        }
+        return grades.floorEntry(score).getValue();
+    }
+    public static void run(int score) {
+        TreeMap<Integer, String> grades = new TreeMap<>();
+        grades.put(90, "A");
+        SortedMap<Integer, String> passing = grades.tailMap(60, true);
+        NavigableSet<String> names = new TreeSet<>(Comparator.reverseOrder());
+        names.add("x");
+        for (String name : names.headSet("m")) {
+            names.remove(name);
+        }
+        grades.merge(score, "B", String::concat);
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Grades.java")
+
+	out := renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		"func Grade(grades *stdjava.TreeMap[int32, string], score int32) string {",
+		"if grades.FloorKey(score) == nil {",
+		"return grades.FloorEntry(score).GetValue()",
+		"grades := stdjava.NewTreeMap[int32, string]()",
+		"grades.Put(90, \"A\")",
+		"passing := grades.TailMap(60, true)",
+		"names := stdjava.NewTreeSetFunc[string](stdjava.ReverseOrder[string])",
+		"for _, name := range names.HeadSet(\"m\").Elements() {",
+		"names.Remove(name)",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != sortedDiagnostic || items[0].Line != 20 {
+		t.Errorf("Expected the unimplemented method on line 20 to be reported, got %v", items)
+	}
+}
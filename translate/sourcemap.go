@@ -0,0 +1,154 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The generated declarations and statements keep the position of the Java code
+// that they were generated from, as a `token.Pos` in a file set that has a file
+// for each of the Java sources, where the offsets are the byte offsets of the
+// Java code. The positions are cleared before the generated code is printed,
+// since the printer lays out the code by the lines of its positions, which are
+// the lines of the Java code
+//
+// With `-line-directives`, every generated declaration that has a position is
+// preceded by a `//line` directive, so that the Go compiler, and any panics,
+// refer to the Java code instead:
+//
+//	//line Shape.java:12
+//	func (se *Shape) Area() float64 {
+
+// A sourceMap maps the nodes of a Java file to the positions of the nodes that
+// are generated from them
+//
+// A nil `*sourceMap` doesn't map anything, and every position is `token.NoPos`
+type sourceMap struct {
+	fset *token.FileSet
+	file *token.File
+}
+
+// newSourceMap adds a Java file to a file set, for the positions of the nodes
+// that are generated from it
+func newSourceMap(fset *token.FileSet, name string, source []byte) *sourceMap {
+	file := fset.AddFile(name, -1, len(source))
+	file.SetLinesForContent(source)
+	return &sourceMap{fset: fset, file: file}
+}
+
+// pos returns the position of the start of a Java node
+func (sm *sourceMap) pos(node *sitter.Node) token.Pos {
+	if sm == nil || node == nil {
+		return token.NoPos
+	}
+	return sm.file.Pos(int(node.StartByte()))
+}
+
+// declPos returns the position of a Java declaration, which is the position of
+// its name, after any annotations and modifiers, if it has one
+func (sm *sourceMap) declPos(node *sitter.Node) token.Pos {
+	if name := node.ChildByFieldName("name"); name != nil {
+		return sm.pos(name)
+	}
+	return sm.pos(node)
+}
+
+// Position returns the line and column in the Java source that a position
+// refers to
+func (sm *sourceMap) Position(pos token.Pos) token.Position {
+	if sm == nil || !pos.IsValid() {
+		return token.Position{}
+	}
+	return sm.fset.Position(pos)
+}
+
+// positionDecls sets the position of the generated declarations that don't
+// have one already
+func positionDecls(decls []ast.Decl, pos token.Pos) {
+	for _, decl := range decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if !decl.Type.Func.IsValid() {
+				decl.Type.Func = pos
+			}
+		case *ast.GenDecl:
+			if !decl.TokPos.IsValid() {
+				decl.TokPos = pos
+			}
+		}
+	}
+}
+
+// positionStmt sets the position of a generated statement, for the statements
+// that start with a keyword of their own
+func positionStmt(stmt ast.Stmt, pos token.Pos) {
+	switch stmt := stmt.(type) {
+	case *ast.ReturnStmt:
+		stmt.Return = pos
+	case *ast.IfStmt:
+		stmt.If = pos
+	case *ast.ForStmt:
+		stmt.For = pos
+	case *ast.RangeStmt:
+		stmt.For = pos
+	case *ast.SwitchStmt:
+		stmt.Switch = pos
+	case *ast.TypeSwitchStmt:
+		stmt.Switch = pos
+	case *ast.BranchStmt:
+		stmt.TokPos = pos
+	case *ast.GoStmt:
+		stmt.Go = pos
+	case *ast.DeferStmt:
+		stmt.Defer = pos
+	case *ast.DeclStmt:
+		positionDecls([]ast.Decl{stmt.Decl}, pos)
+	}
+}
+
+// clearPositions clears the positions that were set on generated code, before
+// it is printed
+func clearPositions(node ast.Node) {
+	ast.Inspect(node, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncType:
+			node.Func = token.NoPos
+		case *ast.GenDecl:
+			node.TokPos = token.NoPos
+		case ast.Stmt:
+			positionStmt(node, token.NoPos)
+		}
+		return true
+	})
+}
+
+// addLineDirectives precedes each declaration that has a position with a
+// `//line` directive, which refers to the Java code that it was generated from
+func addLineDirectives(decls []ast.Decl, sources *sourceMap) {
+	for _, decl := range decls {
+		position := sources.Position(decl.Pos())
+		if !position.IsValid() {
+			continue
+		}
+		directive := &ast.Comment{Text: "//line " + position.Filename + ":" + strconv.Itoa(position.Line)}
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			decl.Doc = withDirective(decl.Doc, directive)
+		case *ast.GenDecl:
+			decl.Doc = withDirective(decl.Doc, directive)
+		}
+	}
+}
+
+// withDirective adds a directive to the end of a doc comment, right before the
+// declaration that it documents
+func withDirective(doc *ast.CommentGroup, directive *ast.Comment) *ast.CommentGroup {
+	if doc == nil {
+		return &ast.CommentGroup{List: []*ast.Comment{directive}}
+	}
+	doc.List = append(doc.List, directive)
+	return doc
+}
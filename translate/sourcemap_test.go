@@ -0,0 +1,95 @@
+package translate
+
+import (
+	"bytes"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+const sourceMapSource = `
+package app.shapes;
+public class Shape {
+    private double width;
+
+    @Deprecated
+    public double area() {
+        if (width < 0) {
+            return 0;
+        }
+        return width * width;
+    }
+}
+`
+
+func TestSourcePositions(t *testing.T) {
+	helper := setupParseHelper(t, sourceMapSource)
+	helper.Ctx.sources = newSourceMap(token.NewFileSet(), "Shape.java", helper.File.Source)
+	file := ParseNode(helper.File.Ast, helper.File.Source, helper.Ctx).(*ast.File)
+
+	var area *ast.FuncDecl
+	for _, decl := range file.Decls {
+		if function, ok := decl.(*ast.FuncDecl); ok && function.Name.Name == "Area" {
+			area = function
+		}
+	}
+	if area == nil {
+		t.Fatalf("Expected the method to be generated")
+	}
+
+	// The method is at the position of its name, after its annotation
+	if position := helper.Ctx.sources.Position(area.Pos()); position.Filename != "Shape.java" || position.Line != 7 || position.Column != 19 {
+		t.Errorf("Expected the method to be at Shape.java:7:19, got %v", position)
+	}
+	ifStmt := area.Body.List[0].(*ast.IfStmt)
+	if line := helper.Ctx.sources.Position(ifStmt.Pos()).Line; line != 8 {
+		t.Errorf("Expected the if statement to be on line 8, got %d", line)
+	}
+	if line := helper.Ctx.sources.Position(ifStmt.Body.List[0].Pos()).Line; line != 9 {
+		t.Errorf("Expected the return statement in the if statement to be on line 9, got %d", line)
+	}
+
+	// The positions aren't printed
+	clearPositions(file)
+	if area.Pos().IsValid() || ifStmt.Pos().IsValid() {
+		t.Errorf("Expected the positions to be cleared")
+	}
+}
+
+func TestLineDirectives(t *testing.T) {
+	LineDirectives = true
+	defer func() { LineDirectives = false }()
+
+	helper := setupParseHelper(t, sourceMapSource)
+	helper.Ctx.sources = newSourceMap(token.NewFileSet(), "Shape.java", helper.File.Source)
+	file := ParseNode(helper.File.Ast, helper.File.Source, helper.Ctx).(*ast.File)
+	clearPositions(file)
+
+	var out bytes.Buffer
+	if err := printer.Fprint(&out, token.NewFileSet(), file); err != nil {
+		t.Fatalf("Failed to print the file: %v", err)
+	}
+	for _, expected := range []string{
+		"//line Shape.java:3\ntype Shape struct {",
+		"//@Deprecated\n//line Shape.java:7\nfunc (se *Shape) Area() float64 {",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestDiagnosticPositions(t *testing.T) {
+	helper := setupParseHelper(t, sourceMapSource)
+	sources := newSourceMap(token.NewFileSet(), "Shape.java", helper.File.Source)
+	diagnostics := NewDiagnostics("Shape.java")
+	diagnostics.sources = sources
+
+	diagnostics.Report(findNode(helper.File.Ast, "if_statement"), "test", "a diagnostic")
+	item := diagnostics.Items()[0]
+	if position := sources.Position(item.Pos); position.Line != item.Line || position.Column != item.Column {
+		t.Errorf("Expected the position of the diagnostic to be %d:%d, got %v", item.Line, item.Column, position)
+	}
+}
@@ -0,0 +1,751 @@
+package translate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/NickyBoy89/java2go/astutil"
+	"github.com/NickyBoy89/java2go/codegen"
+	"github.com/NickyBoy89/java2go/nodeutil"
+	log "github.com/sirupsen/logrus"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+func ParseStmt(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	if stmt := TryParseStmt(node, source, ctx); stmt != nil {
+		return stmt
+	}
+	panic(fmt.Errorf("unhandled stmt type: %v", node.Type()))
+}
+
+func TryParseStmt(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	// Nothing can be hoisted out of a statement, unless it is one of these, or
+	// the condition of one of the statements below
+	ctx.hoisted = nil
+	var stmt ast.Stmt
+	if node.Type() == "local_variable_declaration" || node.Type() == "expression_statement" {
+		ctx.hoisted = &hoistedStatements{names: localVariableNames(ctx), spliced: true}
+		stmt = withHoisted(parseStmtNode(node, source, ctx), ctx.hoisted)
+	} else {
+		stmt = parseStmtNode(node, source, ctx)
+	}
+	if stmt != nil {
+		positionStmt(stmt, ctx.sources.pos(node))
+	}
+	return stmt
+}
+
+// parseStmtNode parses a statement, hoisting anything that has to be evaluated
+// before it into the statements that are hoisted out of the statement that it
+// is a part of, if there are any
+func parseStmtNode(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	switch node.Type() {
+	case "ERROR":
+		log.WithFields(log.Fields{
+			"parsed":    node.Content(source),
+			"className": ctx.className,
+		}).Warn("Statement parse error")
+		return &ast.BadStmt{}
+	case "comment", "line_comment", "block_comment":
+		return &ast.BadStmt{}
+	case "local_variable_declaration":
+		if declaration := checkedDeclaration(node, source, ctx); declaration != nil {
+			return declaration
+		}
+		variableType := astutil.ParseTypeWithTypeParams(node.ChildByFieldName("type"), source, inScopeTypeParameters(ctx))
+		declarators := nodeutil.ChildrenByFieldName(node, "declarator")
+
+		// Count how many of the declared variables are also set to a value
+		var initialized int
+		for _, declarator := range declarators {
+			if declarator.ChildByFieldName("value") != nil {
+				initialized++
+			}
+		}
+
+		// If a variable is being declared, but not set to a value
+		// Ex: `int value;` or `int first, second;`
+		if initialized == 0 {
+			names := make([]*ast.Ident, len(declarators))
+			for ind, declarator := range declarators {
+				names[ind] = ParseExpr(declarator.ChildByFieldName("name"), source, ctx).(*ast.Ident)
+			}
+			return &ast.DeclStmt{
+				Decl: &ast.GenDecl{
+					Tok: token.VAR,
+					Specs: []ast.Spec{
+						&ast.ValueSpec{
+							Names: names,
+							Type:  variableType,
+						},
+					},
+				},
+			}
+		}
+
+		ctx.lastType = variableType
+		// Set expected type for diamond operator inference
+		ctx.expectedType = node.ChildByFieldName("type").Content(source)
+
+		// Some of the variables are set to a value, and some of them aren't
+		// Ex: `int first = 1, second;`
+		// These can't be combined into one short variable declaration, so they
+		// are declared separately in a single `var` block
+		if initialized != len(declarators) {
+			declaration := &ast.GenDecl{Tok: token.VAR}
+			for _, declarator := range declarators {
+				spec := &ast.ValueSpec{
+					Names: []*ast.Ident{ParseExpr(declarator.ChildByFieldName("name"), source, ctx).(*ast.Ident)},
+					Type:  variableType,
+				}
+				if value := declarator.ChildByFieldName("value"); value != nil {
+					spec.Values = []ast.Expr{widenedValue(value, ParseExpr(value, source, ctx), ctx.expectedType, ctx, source)}
+				}
+				declaration.Specs = append(declaration.Specs, spec)
+			}
+			return &ast.DeclStmt{Decl: declaration}
+		}
+
+		// Combine every declarator into one declaration, such as `a, b := 1, 2`
+		declaration := &ast.AssignStmt{Tok: token.DEFINE}
+		for _, declarator := range declarators {
+			parsed := parseStmtNode(declarator, source, ctx).(*ast.AssignStmt)
+			declaration.Lhs = append(declaration.Lhs, parsed.Lhs...)
+			if value := declarator.ChildByFieldName("value"); len(parsed.Rhs) == 1 {
+				// A constant would be declared as an `int`, instead of a `long`
+				if ctx.expectedType == "long" && isUntypedConstant(value, source) {
+					parsed.Rhs[0] = convertTo("int64", parsed.Rhs[0])
+				}
+				parsed.Rhs[0] = widenedValue(value, parsed.Rhs[0], ctx.expectedType, ctx, source)
+			}
+			declaration.Rhs = append(declaration.Rhs, parsed.Rhs...)
+		}
+
+		// Now, if a variable is assigned to `null`, we can't infer its type, so
+		// don't throw out the type information associated with it
+		var containsNull bool
+
+		// Go through the values and see if there is a `null_literal`
+		for _, declarator := range declarators {
+			if declarator.ChildByFieldName("value").Type() == "null_literal" {
+				containsNull = true
+				break
+			}
+		}
+
+		names := make([]*ast.Ident, len(declaration.Lhs))
+		for ind, decl := range declaration.Lhs {
+			names[ind] = decl.(*ast.Ident)
+		}
+
+		// If the declaration contains null, declare it with the `var` keyword instead
+		// of implicitly
+		if containsNull {
+			return &ast.DeclStmt{
+				Decl: &ast.GenDecl{
+					Tok: token.VAR,
+					Specs: []ast.Spec{
+						&ast.ValueSpec{
+							Names:  names,
+							Type:   variableType,
+							Values: declaration.Rhs,
+						},
+					},
+				},
+			}
+		}
+
+		return declaration
+	case "variable_declarator":
+		var names, values []ast.Expr
+
+		// If there is only one node, then that node is just a name
+		if node.NamedChildCount() == 1 {
+			names = append(names, ParseExpr(node.NamedChild(0), source, ctx))
+		}
+
+		// Loop through every pair of name and value
+		for ind := 0; ind < int(node.NamedChildCount())-1; ind += 2 {
+			names = append(names, ParseExpr(node.NamedChild(ind), source, ctx))
+			values = append(values, ParseExpr(node.NamedChild(ind+1), source, ctx))
+		}
+
+		return &ast.AssignStmt{Lhs: names, Tok: token.DEFINE, Rhs: values}
+	case "assignment_expression":
+		if stmt := volatileAssignment(node, source, ctx); stmt != nil {
+			return stmt
+		}
+		if stmt := boxedAssignment(node, source, ctx); stmt != nil {
+			return stmt
+		}
+
+		assignVar := ParseExpr(node.Child(0), source, ctx)
+		assignVal := ParseExpr(node.Child(2), source, ctx)
+
+		// Ex: `x %= 2.5` becomes `x = stdjava.FloatRemainder(x, 2.5)`
+		if operator := node.Child(1).Content(source); operator == "%=" || operator == "/=" {
+			if converted := floatingArithmetic(operator[:1], node.Child(0), node.Child(2), source, ctx); converted != nil {
+				return &ast.AssignStmt{Lhs: []ast.Expr{assignVar}, Tok: token.ASSIGN, Rhs: []ast.Expr{converted}}
+			}
+		}
+
+		// Unsigned right shift, which Go has no operator for
+		if node.Child(1).Content(source) == ">>>=" {
+			return &ast.AssignStmt{
+				Lhs: []ast.Expr{assignVar},
+				Tok: token.ASSIGN,
+				Rhs: []ast.Expr{unsignedShiftAssignment(node.Child(0), node.Child(2), assignVar, assignVal, source, ctx)},
+			}
+		}
+
+		if converted := integralAssignment(node, assignVar, assignVal, source, ctx); converted != nil {
+			return converted
+		}
+
+		return &ast.AssignStmt{
+			Lhs: []ast.Expr{assignVar},
+			Tok: codegen.StrToToken(node.Child(1).Content(source)),
+			Rhs: []ast.Expr{assignVal},
+		}
+	case "update_expression":
+		if updated := volatileUpdate(node, false, source, ctx); updated != nil {
+			return &ast.ExprStmt{X: updated}
+		}
+		if updated := boxedUpdate(node, source, ctx); updated != nil {
+			return updated
+		}
+
+		if node.Child(0).IsNamed() {
+			return &ast.IncDecStmt{
+				X:   ParseExpr(node.Child(0), source, ctx),
+				Tok: codegen.StrToToken(node.Child(1).Content(source)),
+			}
+		}
+
+		return &ast.IncDecStmt{
+			X:   ParseExpr(node.Child(1), source, ctx),
+			Tok: codegen.StrToToken(node.Child(0).Content(source)),
+		}
+	case "resource":
+		var offset int
+		if node.NamedChild(0).Type() == "modifiers" {
+			offset = 1
+		}
+		return &ast.AssignStmt{
+			Lhs: []ast.Expr{ParseExpr(node.NamedChild(1+offset), source, ctx)},
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{ParseExpr(node.NamedChild(2+offset), source, ctx)},
+		}
+	case "method_invocation":
+		expr := ParseExpr(node, source, ctx)
+		// A stream that ends with `forEach` is the loop that it is lowered into
+		if loop := loweredForEach(node, expr, source); loop != nil {
+			return loop
+		}
+		return expressionStatement(expr)
+	case "constructor_body", "block":
+		return &ast.BlockStmt{List: parseStmtList(nodeutil.NamedChildrenOf(node), source, ctx)}
+	case "expression_statement":
+		return parseExpressionStatement(node.NamedChild(0), source, ctx)
+	case "explicit_constructor_invocation":
+		// This is when a constructor calls another constructor with the use of
+		// something such as `this(args...)`
+		if node.ChildByFieldName("constructor").Type() == "super" && isExceptionType(ctx.currentClass, ctx) {
+			arguments := node.ChildByFieldName("arguments")
+			return initEmbeddedException(nodeutil.NamedChildrenOf(arguments), ParseNode(arguments, source, ctx).([]ast.Expr), ctx, source)
+		}
+		return &ast.ExprStmt{
+			X: &ast.CallExpr{
+				Fun:  &ast.Ident{Name: "New" + ctx.className},
+				Args: ParseNode(node.NamedChild(1), source, ctx).([]ast.Expr),
+			},
+		}
+	case "return_statement":
+		returned := &ast.ReturnStmt{Results: []ast.Expr{}}
+		if node.NamedChildCount() > 0 {
+			if checked := checkedReturn(node.NamedChild(0), source, ctx); checked != nil {
+				return checked
+			}
+			// The returned value is expected to have the method's return type
+			ctx.expectedType = expectedReturnType(node, ctx)
+			// What is hoisted out of the value is declared in a block of its own
+			ctx.hoisted = &hoistedStatements{names: localVariableNames(ctx).Fork()}
+			returned.Results = append(returned.Results, widenedValue(node.NamedChild(0), ParseExpr(node.NamedChild(0), source, ctx), ctx.expectedType, ctx, source))
+		}
+		// In the errors mode, a method that returns what it throws returns a nil
+		// error along with its value
+		if returnsError(ctx) {
+			returned.Results = append(returned.Results, &ast.Ident{Name: "nil"})
+		}
+		if ctx.hoisted == nil {
+			return returned
+		}
+		return withHoisted(returned, ctx.hoisted)
+	case "labeled_statement":
+		return parseLabeledStatement(node, source, ctx)
+	case "break_statement":
+		return parseBranch(node, token.BREAK, source)
+	case "continue_statement":
+		return parseBranch(node, token.CONTINUE, source)
+	case "throw_statement":
+		// In the errors mode, the exception is returned as an error
+		if ctx.errorTarget != nil {
+			propagation := ctx.errorTarget.propagate(ParseExpr(node.NamedChild(0), source, ctx))
+			if len(propagation) > 1 {
+				return &ast.BlockStmt{List: propagation}
+			}
+			return propagation[0]
+		}
+		return &ast.ExprStmt{X: &ast.CallExpr{
+			Fun:  &ast.Ident{Name: "panic"},
+			Args: []ast.Expr{ParseExpr(node.NamedChild(0), source, ctx)},
+		}}
+	case "if_statement":
+		var other ast.Stmt
+		if node.ChildByFieldName("alternative") != nil {
+			other = ParseStmt(node.ChildByFieldName("alternative"), source, ctx)
+		}
+
+		// If the `if` statement is inline, replace the line with a full block
+		var body ast.Stmt = ParseStmt(node.ChildByFieldName("consequence"), source, ctx)
+		if _, ok := body.(*ast.BlockStmt); !ok {
+			body = &ast.BlockStmt{List: []ast.Stmt{
+				body,
+			}}
+		}
+
+		// Type assertions from `instanceof` are made before the condition, and
+		// are only declared in the scope of the statement, unless a pattern
+		// variable is used after it
+		condCtx := ctx.Clone()
+		condCtx.hoisted = &hoistedStatements{names: localVariableNames(ctx)}
+		if !bindsAfterIf(node) {
+			condCtx.hoisted.names = condCtx.hoisted.names.Fork()
+		}
+		ifStmt := &ast.IfStmt{
+			Cond: unboxedValue(node.ChildByFieldName("condition"), ParseExpr(node.ChildByFieldName("condition"), source, condCtx), ctx, source),
+			Body: body.(*ast.BlockStmt),
+			Else: other,
+		}
+		// A pattern variable that is used after the statement is declared in the
+		// block that the statement is in
+		if bindsAfterIf(node) {
+			return &ast.BlockStmt{List: append(condCtx.hoisted.list, ifStmt)}
+		}
+		return withHoisted(ifStmt, condCtx.hoisted)
+	case "enhanced_for_statement":
+		// An enhanced for statement has the following fields:
+		// variables for the variable being declared (ex: int n)
+		// then the expression that is being ranged over
+		// and finally, the block of the expression
+
+		if loop := mapRange(node, source, ctx); loop != nil {
+			return loop
+		}
+
+		total := int(node.NamedChildCount())
+
+		// A sorted set, or a set of hashed objects, is iterated over through its
+		// elements
+		iterated := ParseExpr(node.NamedChild(total-2), source, ctx)
+		if sortedClass(node.NamedChild(total-2), ctx, source) == "TreeSet" || hashedClass(node.NamedChild(total-2), ctx, source) == "ObjectSet" {
+			iterated = &ast.CallExpr{Fun: &ast.SelectorExpr{X: iterated, Sel: &ast.Ident{Name: "Elements"}}}
+		}
+
+		loop := &ast.RangeStmt{
+			// We don't need the type of the variable for the range expression
+			Key:   &ast.Ident{Name: "_"},
+			Value: ParseExpr(node.NamedChild(total-3), source, ctx),
+			Tok:   token.DEFINE,
+			X:     iterated,
+			Body:  parseLoopBody(node.NamedChild(total-1), source, ctx),
+		}
+		// The elements of a set are the keys of its map, and the elements of an
+		// `Iterable` are the only values of its iterator
+		if _, ok := setExprType(node.NamedChild(total-2), ctx, source); ok {
+			loop.Key, loop.Value = loop.Value, nil
+		} else if seq := iterableRange(node.NamedChild(total-2), iterated, ctx, source); seq != nil {
+			loop.Key, loop.Value, loop.X = loop.Value, nil, seq
+		}
+		return loop
+	case "for_statement":
+		var init, post ast.Stmt
+
+		// Java allows for multiple comma-separated init and update expressions,
+		// but Go only has room for a single simple statement in each position
+		initNodes := nodeutil.ChildrenByFieldName(node, "init")
+		if len(initNodes) > 0 {
+			inits := make([]ast.Stmt, len(initNodes))
+			for ind, initNode := range initNodes {
+				inits[ind] = ParseStmt(initNode, source, ctx)
+			}
+			init = combineSimpleStmts(inits)
+		}
+		updateNodes := nodeutil.ChildrenByFieldName(node, "update")
+		if len(updateNodes) > 0 {
+			updates := make([]ast.Stmt, len(updateNodes))
+			for ind, updateNode := range updateNodes {
+				updates[ind] = ParseStmt(updateNode, source, ctx)
+			}
+			post = combineSimpleStmts(updates)
+		}
+
+		return conditionalLoop(init, node.ChildByFieldName("condition"), post, node.ChildByFieldName("body"), source, ctx)
+	case "while_statement":
+		if loop := readLineLoop(node, source, ctx); loop != nil {
+			return loop
+		}
+		return conditionalLoop(nil, node.ChildByFieldName("condition"), nil, node.ChildByFieldName("body"), source, ctx)
+	case "do_statement":
+		// A do statement is handled as a blank for loop with the condition
+		// inserted as a break condition in the final part of the loop
+		body := parseLoopBody(node.ChildByFieldName("body"), source, ctx)
+
+		// A `do { } while (true)` never breaks out of the loop
+		condCtx := ctx.Clone()
+		condCtx.hoisted = &hoistedStatements{names: localVariableNames(ctx)}
+		if cond := loopCondition(node.ChildByFieldName("condition"), source, condCtx); cond != nil {
+			body.List = append(body.List, condCtx.hoisted.list...)
+			body.List = append(body.List, &ast.IfStmt{
+				Cond: &ast.UnaryExpr{
+					Op: token.NOT,
+					X:  &ast.ParenExpr{X: cond},
+				},
+				Body: &ast.BlockStmt{List: []ast.Stmt{&ast.BranchStmt{Tok: token.BREAK}}},
+			})
+		}
+
+		return &ast.ForStmt{
+			Body: body,
+		}
+	case "switch_expression":
+		return switchStatement(node, source, ctx)
+	case "yield_statement":
+		// The value of a switch expression is returned from the function
+		// literal that it is translated into
+		return &ast.ReturnStmt{Results: []ast.Expr{ParseExpr(node.NamedChild(0), source, ctx)}}
+	}
+	return nil
+}
+
+// Go's builtin functions and types that produce a value when they are called,
+// which can't be used as a statement
+var valueFunctions = map[string]bool{
+	"append": true, "cap": true, "complex": true, "imag": true, "len": true, "make": true, "max": true, "min": true,
+	"new": true, "real": true, "any": true, "bool": true, "byte": true, "float32": true, "float64": true, "int": true,
+	"int8": true, "int16": true, "int32": true, "int64": true, "rune": true, "string": true, "uint": true,
+	"uint16": true, "uint32": true, "uint64": true,
+}
+
+// expressionStatement uses an expression as a statement
+//
+// Java can use the result of any method as a statement, but a translation of
+// it might not be a function call, such as `len(s)` for `s.length()`, which Go
+// doesn't allow to be unused, so its result is explicitly discarded instead
+//
+// Ex: `_ = len(s)`
+func expressionStatement(expr ast.Expr) ast.Stmt {
+	if isStatementExpr(expr) {
+		return &ast.ExprStmt{X: expr}
+	}
+	return &ast.AssignStmt{
+		Lhs: []ast.Expr{&ast.Ident{Name: "_"}},
+		Tok: token.ASSIGN,
+		Rhs: []ast.Expr{expr},
+	}
+}
+
+// parseExpressionStatement parses an expression whose value isn't used, which
+// Go writes as a statement of its own when it can be one, such as `i++` or
+// `x = y`, instead of as an expression that evaluates to a value
+func parseExpressionStatement(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	if stmt := checkedCallStmt(node, source, ctx); stmt != nil {
+		return stmt
+	}
+	if stmt := requireNonNullStmt(node, source, ctx); stmt != nil {
+		return stmt
+	}
+	if stmt := initializedCollectionStmt(node, source, ctx); stmt != nil {
+		return stmt
+	}
+	if stmt := mapStmt(node, source, ctx); stmt != nil {
+		return stmt
+	}
+	if stmt := listStmt(node, source, ctx); stmt != nil {
+		return stmt
+	}
+	if stmt := parseStmtNode(node, source, ctx); stmt != nil {
+		return stmt
+	}
+	return expressionStatement(ParseExpr(node, source, ctx))
+}
+
+// spliceHoisted returns the statements that a statement was parsed into
+//
+// The statements that were hoisted out of a declaration, an expression
+// statement, or an if statement whose pattern variable is used after it, are
+// put in the enclosing block along with the statement, since the statement can
+// declare a variable. Any other statement keeps its hoisted statements in a
+// block of their own, except for a throw that is returned out of a lowered try
+// block, which is an assignment followed by a return. A labeled statement that
+// is followed by its label is put in the enclosing block along with the label
+func spliceHoisted(node *sitter.Node, stmt ast.Stmt, source []byte) []ast.Stmt {
+	block, ok := stmt.(*ast.BlockStmt)
+	if ok && (node.Type() == "local_variable_declaration" || node.Type() == "expression_statement" ||
+		node.Type() == "throw_statement" || bindsAfterIf(node) || endsWithLabel(node, source)) {
+		return block.List
+	}
+	return []ast.Stmt{stmt}
+}
+
+// isStatementExpr determines if Go allows an expression to be used as a
+// statement, which is only allowed for calls to functions, and receives
+func isStatementExpr(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return isStatementExpr(expr.X)
+	case *ast.UnaryExpr:
+		return expr.Op == token.ARROW
+	case *ast.CallExpr:
+		switch fun := expr.Fun.(type) {
+		case *ast.Ident:
+			return !valueFunctions[fun.Name]
+		case *ast.ArrayType, *ast.MapType, *ast.ChanType, *ast.FuncType, *ast.InterfaceType, *ast.ParenExpr:
+			// A conversion, such as `[]rune(s)`
+			return false
+		}
+		return true
+	case *ast.BadExpr:
+		return true
+	}
+	return false
+}
+
+// parseStmtList parses the statements of a block, or of any other list of
+// statements, such as a group of statements in a switch
+func parseStmtList(lines []*sitter.Node, source []byte, ctx Ctx) []ast.Stmt {
+	var stmts []ast.Stmt
+	for _, line := range lines {
+		if line.Type() == "comment" || line.Type() == "line_comment" || line.Type() == "block_comment" {
+			continue
+		}
+		if stmt := TryParseStmt(line, source, ctx); stmt != nil {
+			stmts = append(stmts, spliceHoisted(line, stmt, source)...)
+		} else {
+			// Try statements are ignored, so they return a list of statements
+			stmts = append(stmts, ParseNode(line, source, ctx).([]ast.Stmt)...)
+		}
+	}
+	return attachLabels(stmts)
+}
+
+// parseLoopBody parses the body of a loop, which in Java can be a single
+// statement, or an empty statement (`for (;;);`), and always returns a block
+func parseLoopBody(node *sitter.Node, source []byte, ctx Ctx) *ast.BlockStmt {
+	if node == nil || !node.IsNamed() {
+		return &ast.BlockStmt{}
+	}
+	body := ParseStmt(node, source, ctx)
+	if block, ok := body.(*ast.BlockStmt); ok {
+		return block
+	}
+	return &ast.BlockStmt{List: []ast.Stmt{body}}
+}
+
+// conditionalLoop creates a loop that runs for as long as its condition is true
+//
+// Anything that is hoisted out of the condition runs before the condition is
+// checked, every time. A single assignment is made in the init and the post
+// statements of the loop, and anything else runs at the start of the body,
+// before checking the condition
+//
+// Ex: `while ((line = reader.readLine()) != null)` becomes
+// `for line = reader.readLine(); line != nil; line = reader.readLine()`
+func conditionalLoop(init ast.Stmt, condition *sitter.Node, post ast.Stmt, body *sitter.Node, source []byte, ctx Ctx) *ast.ForStmt {
+	condCtx := ctx.Clone()
+	condCtx.hoisted = &hoistedStatements{names: localVariableNames(ctx)}
+	loop := &ast.ForStmt{
+		Init: init,
+		Cond: loopCondition(condition, source, condCtx),
+		Post: post,
+		Body: parseLoopBody(body, source, ctx),
+	}
+
+	hoisted := condCtx.hoisted.list
+	if len(hoisted) == 0 {
+		return loop
+	}
+	if assignment, ok := hoisted[0].(*ast.AssignStmt); ok && len(hoisted) == 1 && init == nil && post == nil && assignment.Tok != token.DEFINE {
+		loop.Init, loop.Post = assignment, assignment
+		return loop
+	}
+
+	check := &ast.IfStmt{
+		Cond: &ast.UnaryExpr{Op: token.NOT, X: &ast.ParenExpr{X: loop.Cond}},
+		Body: &ast.BlockStmt{List: []ast.Stmt{&ast.BranchStmt{Tok: token.BREAK}}},
+	}
+	loop.Body.List = append(append(hoisted, check), loop.Body.List...)
+	loop.Cond = nil
+	return loop
+}
+
+// loopCondition parses the condition of a loop, returning nil if the loop has
+// no condition, or if the condition is always true, so that the loop can be
+// emitted as Go's infinite `for { }` form
+func loopCondition(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	if node == nil {
+		return nil
+	}
+	// Go doesn't need the parentheses around the condition
+	for node.Type() == "parenthesized_expression" {
+		node = node.NamedChild(0)
+	}
+	if node.Type() == "true" {
+		return nil
+	}
+	return unboxedValue(node, ParseExpr(node, source, ctx), ctx, source)
+}
+
+// combineSimpleStmts merges several statements into the single simple statement
+// that is allowed in the init and post positions of a Go for loop
+//
+// Assignments and increments are combined into one parallel assignment, such as
+// `i, j = i+1, j+1`. Anything else is wrapped into an immediately-invoked function
+//
+// Java runs the statements from left to right, so they are only combined when
+// none of them uses a variable that an earlier one assigns, since a parallel
+// assignment would read the old value. Ex: `i++, j = i` is run in order
+func combineSimpleStmts(stmts []ast.Stmt) ast.Stmt {
+	if len(stmts) == 1 {
+		return stmts[0]
+	}
+
+	assigned := map[string]bool{}
+	combined := &ast.AssignStmt{Tok: token.ASSIGN}
+	for _, stmt := range stmts {
+		if usesAnyOf(stmt, assigned) {
+			return wrapInFuncLit(stmts)
+		}
+		switch stmt := stmt.(type) {
+		case *ast.IncDecStmt:
+			assigned[assignedRoot(stmt.X)] = true
+		case *ast.AssignStmt:
+			for _, lhs := range stmt.Lhs {
+				assigned[assignedRoot(lhs)] = true
+			}
+		}
+		switch stmt := stmt.(type) {
+		case *ast.IncDecStmt:
+			op := token.ADD
+			if stmt.Tok == token.DEC {
+				op = token.SUB
+			}
+			combined.Lhs = append(combined.Lhs, stmt.X)
+			combined.Rhs = append(combined.Rhs, &ast.BinaryExpr{X: stmt.X, Op: op, Y: &ast.BasicLit{Kind: token.INT, Value: "1"}})
+		case *ast.AssignStmt:
+			if len(stmt.Lhs) != len(stmt.Rhs) {
+				return wrapInFuncLit(stmts)
+			}
+			switch stmt.Tok {
+			case token.ASSIGN:
+				combined.Lhs = append(combined.Lhs, stmt.Lhs...)
+				combined.Rhs = append(combined.Rhs, stmt.Rhs...)
+			default:
+				// Compound assignments, such as `i += 2` are expanded into `i = i + 2`
+				op, ok := compoundAssignmentOperators[stmt.Tok]
+				if !ok || len(stmt.Lhs) != 1 {
+					return wrapInFuncLit(stmts)
+				}
+				combined.Lhs = append(combined.Lhs, stmt.Lhs[0])
+				combined.Rhs = append(combined.Rhs, &ast.BinaryExpr{X: stmt.Lhs[0], Op: op, Y: &ast.ParenExpr{X: stmt.Rhs[0]}})
+			}
+		default:
+			return wrapInFuncLit(stmts)
+		}
+	}
+
+	return combined
+}
+
+// assignedRoot returns the name of the variable that an assignment changes,
+// which is the variable that is indexed or selected from for anything but a
+// plain variable
+func assignedRoot(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return e.Name
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.SelectorExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		default:
+			return ""
+		}
+	}
+}
+
+// usesAnyOf determines if a statement mentions any of the given variables
+func usesAnyOf(stmt ast.Stmt, names map[string]bool) bool {
+	var uses bool
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && names[ident.Name] {
+			uses = true
+		}
+		return !uses
+	})
+	return uses
+}
+
+// compoundAssignmentOperators maps a compound assignment to its binary operator
+var compoundAssignmentOperators = map[token.Token]token.Token{
+	token.ADD_ASSIGN: token.ADD,
+	token.SUB_ASSIGN: token.SUB,
+	token.MUL_ASSIGN: token.MUL,
+	token.QUO_ASSIGN: token.QUO,
+	token.REM_ASSIGN: token.REM,
+	token.AND_ASSIGN: token.AND,
+	token.OR_ASSIGN:  token.OR,
+	token.XOR_ASSIGN: token.XOR,
+	token.SHL_ASSIGN: token.SHL,
+	token.SHR_ASSIGN: token.SHR,
+}
+
+// wrapInFuncLit runs a list of statements inside of an immediately-invoked
+// function, so that they can be used where only one statement is allowed
+func wrapInFuncLit(stmts []ast.Stmt) ast.Stmt {
+	return &ast.ExprStmt{
+		X: &ast.CallExpr{
+			Fun: &ast.FuncLit{
+				Type: &ast.FuncType{Params: &ast.FieldList{}},
+				Body: &ast.BlockStmt{List: stmts},
+			},
+		},
+	}
+}
+
+func ParseStmts(node *sitter.Node, source []byte, ctx Ctx) []ast.Stmt {
+	if stmts := TryParseStmts(node, source, ctx); stmts != nil {
+		return stmts
+	}
+	panic(fmt.Errorf("unhandled stmts type: %v", node.Type()))
+}
+
+func TryParseStmts(node *sitter.Node, source []byte, ctx Ctx) []ast.Stmt {
+	switch node.Type() {
+	case "assignment_expression":
+		if stmts, ok := ParseNode(node, source, ctx).([]ast.Stmt); ok {
+			return stmts
+		}
+	case "try_statement":
+		if stmts, ok := ParseNode(node, source, ctx).([]ast.Stmt); ok {
+			return stmts
+		}
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoops_InfiniteAndEmptyClauses(t *testing.T) {
+	src := `
+package stmt.loops;
+public class Loops {
+    public static void run(int n) {
+        for (;;) {
+            break;
+        }
+        while (true) {
+            break;
+        }
+        for (; n > 0;) {
+            n--;
+        }
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if strings.Count(out, "for {") != 2 {
+		t.Errorf("Expected `for(;;)` and `while(true)` to become `for {`, got:\n%s", out)
+	}
+	if !strings.Contains(out, "for n > 0 {") {
+		t.Errorf("Expected a condition-only loop to become `for cond {`, got:\n%s", out)
+	}
+	if strings.Contains(out, "BadExpr") || strings.Contains(out, "(true)") {
+		t.Errorf("Expected no leftover clauses in the loops, got:\n%s", out)
+	}
+}
+
+func TestLoops_MultipleInitAndUpdate(t *testing.T) {
+	src := `
+package stmt.loops;
+public class Loops {
+    public static void run(int n) {
+        for (int i = 0, j = 1; i < n; i++, j += 2) {
+            n--;
+        }
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, "for i, j := 0, 1; i < n; i, j = i+1, j+(2) {") {
+		t.Errorf("Expected the init and update clauses to be combined, got:\n%s", out)
+	}
+}
+
+func TestLoops_DependentUpdatesRunInOrder(t *testing.T) {
+	src := `
+package stmt.loops;
+public class Loops {
+    public static void run(int n) {
+        for (int i = 0, j = 0; i < n; i++, j = i) {
+            n--;
+        }
+        for (int i = 0; i < n; i++, i++) {
+            n--;
+        }
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	flat := normalizeSpaces(out)
+	if !strings.Contains(flat, "i < n; func() { i++ j = i }() {") {
+		t.Errorf("Expected an update that reads an earlier one to run after it, got:\n%s", out)
+	}
+	if !strings.Contains(flat, "i < n; func() { i++ i++ }() {") {
+		t.Errorf("Expected both increments of the same variable to run, got:\n%s", out)
+	}
+}
+
+func TestLoops_SingleStatementBodies(t *testing.T) {
+	src := `
+package stmt.loops;
+public class Loops {
+    public static void run(int n) {
+        while (n > 0) n--;
+        for (;;) n++;
+        do n++; while (n < 3);
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, "for n > 0 {\n\t\tn--\n\t}") {
+		t.Errorf("Expected the inline while body to be wrapped in a block, got:\n%s", out)
+	}
+	if !strings.Contains(out, "if !(n < 3) {") {
+		t.Errorf("Expected the do statement to break on the negated condition, got:\n%s", out)
+	}
+}
+
+func TestExpressionStatement_DiscardsValues(t *testing.T) {
+	src := `
+package stmt.discard;
+public class Discard {
+    public static void run(String s, char[] cs) {
+        s.toCharArray();
+        String.valueOf(cs);
+        System.out.println(s);
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	if !strings.Contains(out, "_ = []rune(s)") || !strings.Contains(out, "_ = string(cs)") {
+		t.Errorf("Expected conversions in statement position to be discarded, got:\n%s", out)
+	}
+	if strings.Contains(out, "_ = System.out.println(s)") {
+		t.Errorf("Expected method calls to stay as statements, got:\n%s", out)
+	}
+}
+
+func TestArrays_StrictIndexing(t *testing.T) {
+	StrictMode = true
+	defer func() { StrictMode = false }()
+
+	src := `
+package stmt.arrays;
+public class Arrays {
+    public static int run(int[] xs, int[][] grid) {
+        xs[0] = 1;
+        xs[1]++;
+        grid[1][2] += xs[0];
+        return xs[2];
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	for _, expected := range []string{
+		"*stdjava.ArrayElement(xs, 0) = 1",
+		"*stdjava.ArrayElement(xs, 1)++",
+		"*stdjava.ArrayElement(stdjava.ArrayGet(grid, 1), 2) += stdjava.ArrayGet(xs, 0)",
+		"return stdjava.ArrayGet(xs, 2)",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestArithmetic_StrictFloatingDivision(t *testing.T) {
+	StrictMode = true
+	defer func() { StrictMode = false }()
+
+	src := `
+package stmt.arithmetic;
+public class Arithmetic {
+    public static double run(double x, int a) {
+        double r = x % 2.5;
+        int i = a % 3;
+        x %= 2;
+        double inf = 1.0 / 0;
+        return x / 2 + a / 2;
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	for _, expected := range []string{
+		"r := stdjava.FloatRemainder(x, 2.5)",
+		"i := a % 3",
+		"x = stdjava.FloatRemainder(x, 2)",
+		"inf := stdjava.FloatDivide(1.0, 0)",
+		"return x/2 + a/2",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestClassLiterals(t *testing.T) {
+	src := `
+package stmt.classes;
+public class Classes {
+    interface Shape {}
+    public static Class<?> run(Class<? extends Shape> kind) {
+        Class<?> shape = Shape.class;
+        Class<?> number = int.class;
+        return Classes.class;
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	for _, expected := range []string{
+		"func Run(kind reflect.Type) reflect.Type",
+		"shape := reflect.TypeOf((*Classesshape)(nil)).Elem()",
+		"number := reflect.TypeOf((*int32)(nil)).Elem()",
+		"return reflect.TypeOf((*Classes)(nil))",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestUpdateExpressions(t *testing.T) {
+	src := `
+package stmt.updates;
+public class Updates {
+    public static int run(int n, int[] xs) {
+        int i = 0;
+        i++;
+        --n;
+        for (int k = 0; k < n; k++) {
+            xs[k]++;
+        }
+        Runnable r = () -> xs[0]--;
+        int a = i++ + --n;
+        return xs[++i];
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	for _, expected := range []string{
+		"i++\n",
+		"n--\n",
+		"for k := 0; k < n; k++ {",
+		"xs[k]++\n",
+		"r := func() {\n\t\txs[0]--\n\t}",
+		"a := stdjava.PostIncrement(&i) + stdjava.PreDecrement(&n)",
+		"return xs[stdjava.PreIncrement(&i)]",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestAssignmentExpressions(t *testing.T) {
+	src := `
+package stmt.assignments;
+public class Assignments {
+    public static int run(Reader reader, int z) {
+        int x, y;
+        x = y = z;
+        String line;
+        if ((line = reader.readLine()) != null) {
+            x = 1;
+        }
+        while ((line = reader.readLine()) != null) {
+            y++;
+        }
+        do {
+            x--;
+        } while ((x = reader.next()) > 0);
+        if (x > 0 && (y = z) > 0) {
+            x = 2;
+        }
+        return y = x;
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	for _, expected := range []string{
+		"y = z\n\tx = y\n",
+		"if line = reader.readLine(); line != nil {",
+		"for line = reader.readLine(); line != nil; line = reader.readLine() {",
+		"x = reader.next()\n\t\tif !(x > 0) {\n\t\t\tbreak\n\t\t}",
+		"if x > 0 && (stdjava.Assign(&y, z)) > 0 {",
+		"{\n\t\ty = x\n\t\treturn y\n\t}",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestAssignmentExpressions_ReadBefore(t *testing.T) {
+	helper := setupParseHelper(t, `
+package stmt.order;
+public class Order {
+    public static int run(int x) {
+        int z = x * (x = 2);
+        return z + twice(x) * (x = 3);
+    }
+    static int twice(int v) {
+        return v * 2;
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Order.java")
+
+	out := renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		"xOld := x\n\tx = 2\n\tz := xOld * x",
+		"return z + twice(x)*(stdjava.Assign(&x, 3))",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != evaluationOrderDiagnostic || items[0].Line != 6 {
+		t.Errorf("Expected the assignment after the call on line 6 to be reported, got %v", items)
+	}
+}
+
+func TestUpdateExpressions_ReadBefore(t *testing.T) {
+	out := renderGoFileFromJava(t, `
+package stmt.order;
+public class Order {
+    public static int run(int x) {
+        int y = x + x++;
+        int z = x * ++x;
+        return y + z;
+    }
+}
+`)
+	for _, expected := range []string{
+		"xOld := x\n\txOld2 := x\n\tx++\n\ty := xOld + xOld2",
+		"xOld3 := x\n\tx++\n\tz := xOld3 * x",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
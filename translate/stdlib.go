@@ -0,0 +1,440 @@
+package translate
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The static methods of Java's standard library that have an equivalent in
+// Go's are called through the Go function instead, with the packages that it
+// needs imported along with it:
+//
+//	System.out.println(total)      fmt.Println(total)
+//	Math.max(a, b)                 max(a, b)
+//	String.format("%d", a)         fmt.Sprintf("%d", a)
+//	Integer.parseInt(text)         stdjava.ParseInt(text, 10)
+//
+// Each method is mapped to a template, which is a Go expression where `_0`,
+// `_1`, and so on are the arguments of the call, and `_args` is all of them. A
+// method can have a template for each of its overloads, by the Java types of
+// its parameters, and the first one that the arguments can be passed to is
+// used. The arguments that a template converts to the Go type that they
+// already have aren't converted
+//
+// More mappings are read from the file that is given with `-stdlib-mappings`,
+// in the same format as the mappings below, and take precedence over them
+
+// A stdlibMapping is the template that a call of a method is translated with
+type stdlibMapping struct {
+	// The Java types of the parameters of the overload that the template is
+	// for, or nil if it is for all of them
+	params []string
+	// The number of arguments that the template takes, unless it takes all of
+	// them
+	arity   int
+	allArgs bool
+	// The template, which is copied for every call
+	template ast.Expr
+}
+
+// The mappings of the methods, by their class and name, such as `Math.max`
+var stdlibMappings = map[string][]stdlibMapping{}
+
+// The mappings that are built in, in the format of a mapping file
+const builtinStdlibMappings = `
+# Printing to the standard streams, where a char is printed as a character
+System.out.println(char) = fmt.Println(string(_0))
+System.out.println = fmt.Println(_args)
+System.out.print(char) = fmt.Print(string(_0))
+System.out.print = fmt.Print(_args)
+System.out.printf = fmt.Printf(_args)
+System.out.format = fmt.Printf(_args)
+System.err.println(char) = fmt.Fprintln(os.Stderr, string(_0))
+System.err.println = fmt.Fprintln(os.Stderr, _args)
+System.err.print(char) = fmt.Fprint(os.Stderr, string(_0))
+System.err.print = fmt.Fprint(os.Stderr, _args)
+System.err.printf = fmt.Fprintf(os.Stderr, _args)
+System.err.format = fmt.Fprintf(os.Stderr, _args)
+
+# Formatting and parsing, where a string that can't be parsed panics with the
+# same NumberFormatException that Java throws
+String.format = fmt.Sprintf(_args)
+Integer.parseInt(String) = stdjava.ParseInt(_0, 10)
+Integer.parseInt(String, int) = stdjava.ParseInt(_0, _1)
+Long.parseLong(String) = stdjava.ParseLong(_0, 10)
+Long.parseLong(String, int) = stdjava.ParseLong(_0, _1)
+Double.parseDouble(String) = stdjava.ParseDouble(_0)
+Float.parseFloat(String) = stdjava.ParseFloat(_0)
+Boolean.parseBoolean(String) = strings.EqualFold(_0, "true")
+Integer.toString(int) = strconv.Itoa(int(_0))
+Long.toString(long) = strconv.FormatInt(_0, 10)
+
+# Go's built-in max and min treat NaN and negative zero the same way as Java,
+# once both of their arguments are promoted to the same type
+Math.max(int, int) = max(_0, _1)
+Math.max(long, long) = max(int64(_0), int64(_1))
+Math.max(float, float) = max(float32(_0), float32(_1))
+Math.max(double, double) = max(float64(_0), float64(_1))
+Math.max = max(_0, _1)
+Math.min(int, int) = min(_0, _1)
+Math.min(long, long) = min(int64(_0), int64(_1))
+Math.min(float, float) = min(float32(_0), float32(_1))
+Math.min(double, double) = min(float64(_0), float64(_1))
+Math.min = min(_0, _1)
+Math.abs(int) = stdjava.Abs(_0)
+Math.abs(long) = stdjava.Abs(_0)
+Math.abs(float) = float32(math.Abs(float64(_0)))
+Math.abs(double) = math.Abs(_0)
+Math.sqrt(double) = math.Sqrt(float64(_0))
+Math.cbrt(double) = math.Cbrt(float64(_0))
+Math.pow(double, double) = math.Pow(float64(_0), float64(_1))
+Math.floor(double) = math.Floor(float64(_0))
+Math.ceil(double) = math.Ceil(float64(_0))
+Math.exp(double) = math.Exp(float64(_0))
+Math.log(double) = math.Log(float64(_0))
+Math.log10(double) = math.Log10(float64(_0))
+Math.sin(double) = math.Sin(float64(_0))
+Math.cos(double) = math.Cos(float64(_0))
+Math.tan(double) = math.Tan(float64(_0))
+Math.asin(double) = math.Asin(float64(_0))
+Math.acos(double) = math.Acos(float64(_0))
+Math.atan(double) = math.Atan(float64(_0))
+Math.atan2(double, double) = math.Atan2(float64(_0), float64(_1))
+Math.hypot(double, double) = math.Hypot(float64(_0), float64(_1))
+`
+
+func init() {
+	if err := LoadStdlibMappings(strings.NewReader(builtinStdlibMappings)); err != nil {
+		panic(err)
+	}
+}
+
+// LoadStdlibMappings reads the mappings of a mapping file, which each have a
+// line of the form `Class.method(Type, Type) = template`, where the types of
+// the parameters are optional, and adds them ahead of the ones that have
+// already been loaded
+//
+// A line of the form `import path` imports the package with that path,
+// whenever one of the templates refers to it by the last element of its path.
+// Empty lines and lines starting with `#` are skipped
+func LoadStdlibMappings(r io.Reader) error {
+	loaded := map[string][]stdlibMapping{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if importPath, ok := strings.CutPrefix(text, "import "); ok {
+			importPath = strings.TrimSpace(importPath)
+			generatedImports[path.Base(importPath)] = importPath
+			continue
+		}
+
+		method, template, ok := strings.Cut(text, "=")
+		if !ok {
+			return fmt.Errorf("line %d: expected a mapping of the form `Class.method = template`", line)
+		}
+		method = strings.TrimSpace(method)
+		var mapping stdlibMapping
+		if name, params, hasParams := strings.Cut(method, "("); hasParams {
+			method = strings.TrimSpace(name)
+			mapping.params = []string{}
+			for _, param := range strings.Split(strings.TrimSuffix(params, ")"), ",") {
+				if param = strings.TrimSpace(param); param != "" {
+					mapping.params = append(mapping.params, param)
+				}
+			}
+		}
+		if !strings.Contains(method, ".") {
+			return fmt.Errorf("line %d: expected the method %q to be qualified by its class", line, method)
+		}
+
+		expr, err := parser.ParseExprFrom(token.NewFileSet(), "", strings.TrimSpace(template), parser.SkipObjectResolution)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		mapping.template = expr
+		ast.Inspect(expr, func(node ast.Node) bool {
+			if ident, ok := node.(*ast.Ident); ok {
+				if ident.Name == "_args" {
+					mapping.allArgs = true
+				} else if index, ok := placeholderIndex(ident.Name); ok && index >= mapping.arity {
+					mapping.arity = index + 1
+				}
+			}
+			return true
+		})
+		if mapping.params != nil && !mapping.allArgs && len(mapping.params) != mapping.arity {
+			return fmt.Errorf("line %d: the template of %s takes %d arguments, but the method has %d parameters", line, method, mapping.arity, len(mapping.params))
+		}
+		loaded[method] = append(loaded[method], mapping)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	for method, mappings := range loaded {
+		stdlibMappings[method] = append(mappings, stdlibMappings[method]...)
+	}
+	return nil
+}
+
+// placeholderIndex returns the index of the argument that a name in a template
+// stands for, such as 1 for `_1`
+func placeholderIndex(name string) (int, bool) {
+	digits, ok := strings.CutPrefix(name, "_")
+	if !ok {
+		return 0, false
+	}
+	index, err := strconv.Atoi(digits)
+	return index, err == nil && index >= 0
+}
+
+// The primitives that each primitive is widened to when it is passed as an
+// argument
+var primitiveWidening = map[string][]string{
+	"byte":  {"short", "int", "long", "float", "double"},
+	"short": {"int", "long", "float", "double"},
+	"char":  {"int", "long", "float", "double"},
+	"int":   {"long", "float", "double"},
+	"long":  {"float", "double"},
+	"float": {"double"},
+}
+
+// The Go types of the Java types that the arguments of a template can have
+var argumentGoTypes = map[string]string{
+	"byte":    "byte",
+	"short":   "int16",
+	"char":    "rune",
+	"int":     "int32",
+	"long":    "int64",
+	"float":   "float32",
+	"double":  "float64",
+	"boolean": "bool",
+	"String":  "string",
+}
+
+// argumentJavaType returns the Java type of an argument, or an empty string if
+// it isn't known
+func argumentJavaType(node *sitter.Node, ctx Ctx, source []byte) string {
+	if javaType := integralType(node, ctx, source); javaType != "" {
+		return javaType
+	}
+	if javaType := floatingType(node, ctx, source); javaType != "" {
+		return javaType
+	}
+	if isStringExpr(node, ctx, source) {
+		return "String"
+	}
+	if javaType, ok := literalJavaType(node, source); ok {
+		return javaType
+	}
+	javaType, _ := inferExprJavaType(node, ctx, source)
+	return stripJavaQualifier(javaType)
+}
+
+// accepts determines if arguments of the given Java types can be passed to
+// the overload of a template. An argument whose type isn't known can only be
+// passed to a template for all of the overloads
+func (mapping stdlibMapping) accepts(argTypes []string) bool {
+	if mapping.allArgs {
+		return len(argTypes) >= mapping.arity && mapping.params == nil
+	}
+	if len(argTypes) != mapping.arity {
+		return false
+	}
+	for i, param := range mapping.params {
+		if argTypes[i] == param || param == "Object" {
+			continue
+		}
+		widened := false
+		for _, wider := range primitiveWidening[argTypes[i]] {
+			widened = widened || wider == param
+		}
+		if !widened {
+			return false
+		}
+	}
+	return true
+}
+
+// stdlibMethod translates a call of a static method of Java's standard library
+// through its mapping, with the arguments that are already translated, or
+// returns nil if the method doesn't have a mapping
+func stdlibMethod(node *sitter.Node, args []ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	objectNode := node.ChildByFieldName("object")
+	if objectNode == nil || resolveClassScopeByIdentifier(ctx, source, objectNode) != nil {
+		return nil
+	}
+	argNodes := nodeutil.NamedChildrenOf(node.ChildByFieldName("arguments"))
+	argTypes := make([]string, len(argNodes))
+	for i, argNode := range argNodes {
+		argTypes[i] = argumentJavaType(argNode, ctx, source)
+	}
+	name := node.ChildByFieldName("name").Content(source)
+	if (name == "printf" || name == "format") && len(args) > 0 {
+		args = append([]ast.Expr{formatVerbs(args[0], argNodes[1:], ctx, source)}, args[1:]...)
+	}
+	return stdlibCall(objectNode.Content(source), name, args, argNodes, argTypes, source)
+}
+
+// stdlibCall translates a call of a static method of a class of Java's
+// standard library through its mapping, with the Java types of its arguments,
+// or returns nil if the method doesn't have a mapping. The nodes of the
+// arguments are nil where they aren't written out in Java, such as for the
+// parameters of a method reference
+func stdlibCall(class, name string, args []ast.Expr, argNodes []*sitter.Node, argTypes []string, source []byte) ast.Expr {
+	mappings := stdlibMappings[strings.TrimPrefix(class, "java.lang.")+"."+name]
+	for _, mapping := range mappings {
+		if !mapping.accepts(argTypes) {
+			continue
+		}
+		// Java's line separator in a format string is a newline
+		if (name == "printf" || name == "format") && len(args) > 0 {
+			args = append([]ast.Expr{javaFormatString(args[0])}, args[1:]...)
+		}
+		instance := templateInstance{args: args, argNodes: argNodes, argTypes: argTypes, source: source}
+		return instance.copy(reflect.ValueOf(&mapping.template).Elem()).Interface().(ast.Expr)
+	}
+	return nil
+}
+
+// javaFormatString replaces the `%n` of a Java format string that is written
+// out with a newline, since Go's formatting doesn't have it
+func javaFormatString(format ast.Expr) ast.Expr {
+	literal, ok := format.(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return format
+	}
+	value, err := strconv.Unquote(literal.Value)
+	if err != nil || !strings.Contains(value, "%n") {
+		return format
+	}
+	var replaced strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '%' && i+1 < len(value) {
+			switch value[i+1] {
+			case 'n':
+				replaced.WriteByte('\n')
+				i++
+				continue
+			case '%':
+				replaced.WriteString("%%")
+				i++
+				continue
+			}
+		}
+		replaced.WriteByte(value[i])
+	}
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(replaced.String())}
+}
+
+// A templateInstance copies a template for a call, with the call's arguments
+type templateInstance struct {
+	args     []ast.Expr
+	argNodes []*sitter.Node
+	argTypes []string
+	source   []byte
+}
+
+var (
+	exprType = reflect.TypeOf((*ast.Expr)(nil)).Elem()
+	posType  = reflect.TypeOf(token.NoPos)
+)
+
+// copy makes a deep copy of a part of a template, without the positions of the
+// template, where the placeholders are replaced with the arguments
+func (instance templateInstance) copy(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type()).Elem()
+		if value.Type() == exprType {
+			if arg := instance.argument(value.Interface().(ast.Expr)); arg != nil {
+				copied.Set(reflect.ValueOf(arg))
+				return copied
+			}
+		}
+		copied.Set(instance.copy(value.Elem()))
+		return copied
+	case reflect.Pointer:
+		if value.IsNil() || value.Type() == reflect.TypeOf((*ast.Object)(nil)) {
+			return reflect.Zero(value.Type())
+		}
+		copied := reflect.New(value.Elem().Type())
+		copied.Elem().Set(instance.copy(value.Elem()))
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).Type != posType {
+				copied.Field(i).Set(instance.copy(value.Field(i)))
+			}
+		}
+		return copied
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeSlice(value.Type(), 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			if ident, ok := value.Index(i).Interface().(*ast.Ident); ok && ident.Name == "_args" {
+				for _, arg := range instance.args {
+					copied = reflect.Append(copied, reflect.ValueOf(arg))
+				}
+				continue
+			}
+			copied = reflect.Append(copied, instance.copy(value.Index(i)))
+		}
+		return copied
+	}
+	return value
+}
+
+// argument returns the argument that a placeholder stands for, or the argument
+// of a conversion of a placeholder to the Go type that the argument already
+// has, or nil if the expression is anything else
+func (instance templateInstance) argument(expr ast.Expr) ast.Expr {
+	if ident, ok := expr.(*ast.Ident); ok {
+		if index, ok := placeholderIndex(ident.Name); ok && index < len(instance.args) {
+			return instance.args[index]
+		}
+		return nil
+	}
+
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil
+	}
+	goType, isIdent := call.Fun.(*ast.Ident)
+	placeholder, isPlaceholder := call.Args[0].(*ast.Ident)
+	if !isIdent || !isPlaceholder {
+		return nil
+	}
+	index, ok := placeholderIndex(placeholder.Name)
+	if !ok || index >= len(instance.args) {
+		return nil
+	}
+	// A constant takes on the type that it is used as, other than a string,
+	// which a number is converted to as a character
+	argNode := instance.argNodes[index]
+	if argumentGoTypes[instance.argTypes[index]] == goType.Name || argNode != nil &&
+		goType.Name != "string" && (isUntypedConstant(argNode, instance.source) || isUntypedFloatingConstant(argNode, instance.source)) {
+		return instance.args[index]
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStdlibMappings(t *testing.T) {
+	out := normalizeSpaces(renderGoFileFromJava(t, `
+package app.stdlib;
+public class Report {
+    static void print(String name, String count, int a, long b, double d, char c) {
+        System.out.println("name: " + name);
+        System.out.println();
+        System.out.println(c);
+        System.err.println(name);
+        System.out.printf("%d items%n", a);
+        String line = String.format("%s=%d", name, a);
+        int parsed = Integer.parseInt(count);
+        int hex = Integer.parseInt(count, 16);
+        long total = Long.parseLong(count);
+        int larger = Math.max(a, 10);
+        long longer = Math.max(b, a);
+        double smaller = Math.min(a, d);
+        double root = Math.sqrt(d);
+        double intRoot = Math.sqrt(a);
+        long distance = Math.abs(b);
+        double magnitude = Math.abs(d);
+    }
+}
+`))
+	for _, expected := range []string{
+		`fmt.Println("name: " + name)`,
+		`fmt.Println()`,
+		`fmt.Println(string(c))`,
+		`fmt.Fprintln(os.Stderr, name)`,
+		`fmt.Printf("%d items\n", a)`,
+		`line := fmt.Sprintf("%s=%d", name, a)`,
+		`parsed := stdjava.ParseInt(count, 10)`,
+		`hex := stdjava.ParseInt(count, 16)`,
+		`total := stdjava.ParseLong(count, 10)`,
+		`larger := max(a, 10)`,
+		`longer := max(b, int64(a))`,
+		`smaller := min(float64(a), d)`,
+		`root := math.Sqrt(d)`,
+		`intRoot := math.Sqrt(float64(a))`,
+		`distance := stdjava.Abs(b)`,
+		`magnitude := math.Abs(d)`,
+		`"math"`,
+		`"os"`,
+	} {
+		if !strings.Contains(out, normalizeSpaces(expected)) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestStdlibMappingsOfOwnClasses(t *testing.T) {
+	out := normalizeSpaces(renderGoFileFromJava(t, `
+package app.stdlib.own;
+public class Integer {
+    static int parseInt(String text) {
+        return text.length();
+    }
+
+    static int twice(String text) {
+        return Integer.parseInt(text) * 2;
+    }
+}
+`))
+	if strings.Contains(out, "strconv") {
+		t.Errorf("Expected a class of the package to be called instead of the standard library, got:\n%s", out)
+	}
+}
+
+func TestLoadStdlibMappings(t *testing.T) {
+	defer func(mappings []stdlibMapping) {
+		stdlibMappings["StringUtils.isBlank"] = mappings
+		delete(generatedImports, "text")
+	}(stdlibMappings["StringUtils.isBlank"])
+
+	err := LoadStdlibMappings(strings.NewReader(`
+# The helpers of the application
+import github.com/acme/text
+StringUtils.isBlank(String) = text.IsBlank(_0)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := normalizeSpaces(renderGoFileFromJava(t, `
+package app.stdlib.custom;
+public class Form {
+    boolean empty(String name) {
+        return StringUtils.isBlank(name);
+    }
+}
+`))
+	for _, expected := range []string{`return text.IsBlank(name)`, `import "github.com/acme/text"`} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestInvalidStdlibMappings(t *testing.T) {
+	for _, mapping := range []string{
+		"Math.max max(_0, _1)",
+		"max = max(_0, _1)",
+		"Math.max = max(_0,",
+		"Math.max(int) = max(_0, _1)",
+	} {
+		if err := LoadStdlibMappings(strings.NewReader(mapping)); err == nil {
+			t.Errorf("Expected the mapping %q to be invalid", mapping)
+		}
+	}
+}
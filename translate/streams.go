@@ -0,0 +1,586 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/astutil"
+	"github.com/NickyBoy89/java2go/nodeutil"
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for the pipelines of streams that aren't
+// lowered into loops
+const streamLoweringDiagnostic = "stream-lowering"
+
+// The kind of diagnostic reported for the parallel streams that are lowered
+// into sequential loops
+const parallelStreamDiagnostic = "parallel-stream"
+
+// A pipeline of a stream, from the collection or the array that it streams to
+// the operation that ends it, is lowered into a loop over its source, in a
+// function that is called in place. Each of the stages of the pipeline runs
+// in the loop, with the parameters of their lambdas bound to the element that
+// reaches them:
+//
+//	Map<Boolean, List<Integer>> parts = numbers.stream()
+//		.filter(n -> n > 0)
+//		.collect(Collectors.partitioningBy(n -> n % 2 == 0));
+//
+// becomes
+//
+//	parts := func() map[bool][]int32 {
+//		result := map[bool][]int32{false: nil, true: nil}
+//		for _, n := range numbers {
+//			if !(n > 0) {
+//				continue
+//			}
+//			key := n%2 == 0
+//			result[key] = append(result[key], n)
+//		}
+//		return result
+//	}()
+//
+// Besides `collect`, a stream can end by counting, summing, or matching its
+// elements, which returns from the loop as soon as an element decides the
+// match, or by passing each of them to `forEach`, whose loop is a statement
+// of its own
+//
+// A parallel stream is lowered into the same loop, and reported, since its
+// stages no longer run in parallel. With `-parallel-streams`, its stages run
+// for each element in the runtime's goroutines instead, each of them returning
+// a function that collects the element, which the runtime calls in the order
+// of the elements once they have all finished:
+//
+//	stdjava.Parallel(numbers, func(n int32) func() {
+//		if !(n > 0) {
+//			return nil
+//		}
+//		return func() {
+//			key := n%2 == 0
+//			result[key] = append(result[key], n)
+//		}
+//	})
+
+// The stages of a stream that are lowered into the loop
+var streamStages = map[string]bool{
+	"filter":      true,
+	"boxed":       true,
+	"parallel":    true,
+	"sequential":  true,
+	"map":         true,
+	"mapToInt":    true,
+	"mapToLong":   true,
+	"mapToDouble": true,
+	"mapToObj":    true,
+}
+
+// The operations that end a stream that is lowered into a loop, and the
+// numbers of arguments that they can have
+var streamTerminals = map[string][]int{
+	"collect":   {1},
+	"toList":    {0},
+	"count":     {0},
+	"sum":       {0},
+	"anyMatch":  {1},
+	"allMatch":  {1},
+	"noneMatch": {1},
+	"forEach":   {1},
+}
+
+// The Java types of the elements of the streams of primitives
+var primitiveStreamTypes = map[string]string{
+	"mapToInt":    "int",
+	"mapToLong":   "long",
+	"mapToDouble": "double",
+}
+
+// streamSource returns the node that a stream is created from, and the stages
+// of the stream, in order, or a nil source if the expression isn't a stream
+//
+// Ex: `names.stream().filter(p).map(f)` is streamed from `names`, through
+// `filter(p)` and `map(f)`
+func streamSource(node *sitter.Node, source []byte) (*sitter.Node, []*sitter.Node) {
+	var stages []*sitter.Node
+	for node.Type() == "method_invocation" && node.ChildByFieldName("object") != nil {
+		object := node.ChildByFieldName("object")
+		arguments := node.ChildByFieldName("arguments").NamedChildCount()
+		switch name := node.ChildByFieldName("name").Content(source); {
+		case (name == "stream" || name == "parallelStream") && arguments == 0:
+			return node, stages
+		case name == "stream" && arguments == 1 && object.Content(source) == "Arrays":
+			return node, stages
+		}
+		stages = append([]*sitter.Node{node}, stages...)
+		node = object
+	}
+	return nil, nil
+}
+
+// A streamLoop is the body of the loop that a stream is lowered into, which
+// the stages of the stream are added to as they are lowered
+type streamLoop struct {
+	ctx    Ctx
+	source []byte
+	// The variables that the lambdas of the stream declare, which are found
+	// before the variables of the method that the stream is in
+	scope *symbol.Definition
+	names *symbol.NameAllocator
+	// The statements of the innermost block of the loop
+	stmts *[]ast.Stmt
+	// The statement that skips the elements that are filtered out
+	skip ast.Stmt
+	// Whether the loop ranges over the values of a map
+	mapValues bool
+	// The variables that are declared in the innermost block of the loop, and
+	// their Java types
+	declared map[string]string
+	// The element that reaches the current stage, and its Java type, if it is
+	// known
+	element     ast.Expr
+	elementType string
+	// Whether the element is a box, which is a pointer to the primitive that
+	// it boxes
+	boxed bool
+}
+
+// add adds statements to the loop
+func (l *streamLoop) add(stmts ...ast.Stmt) {
+	*l.stmts = append(*l.stmts, stmts...)
+}
+
+// declare declares a variable of the given Java type in the loop. A variable
+// that is already declared with the same type is assigned to, and otherwise,
+// the variable is declared again in a block of its own
+func (l *streamLoop) declare(name string, javaType string, value ast.Expr) {
+	declaredType, declared := l.declared[name]
+	if declared && javaType != "" && declaredType == javaType {
+		l.add(&ast.AssignStmt{Lhs: []ast.Expr{&ast.Ident{Name: name}}, Tok: token.ASSIGN, Rhs: []ast.Expr{value}})
+		return
+	}
+	if declared {
+		block := &ast.BlockStmt{}
+		l.add(block)
+		l.stmts = &block.List
+		l.declared = map[string]string{}
+	}
+	l.declared[name] = javaType
+	l.add(&ast.AssignStmt{Lhs: []ast.Expr{&ast.Ident{Name: name}}, Tok: token.DEFINE, Rhs: []ast.Expr{value}})
+}
+
+// value returns the current element as a variable, so that it can be used
+// more than once
+func (l *streamLoop) value() ast.Expr {
+	if _, ok := l.element.(*ast.Ident); !ok {
+		name := l.names.Allocate("value")
+		l.declare(name, l.elementType, l.element)
+		l.element = &ast.Ident{Name: name}
+	}
+	return l.element
+}
+
+// lambdaParameter returns the only parameter of a lambda, or nil if it has a
+// different number of parameters, or its body is a block
+func lambdaParameter(node *sitter.Node) *sitter.Node {
+	if node.Type() != "lambda_expression" || node.ChildByFieldName("body").Type() == "block" {
+		return nil
+	}
+	params := node.ChildByFieldName("parameters")
+	switch params.Type() {
+	case "identifier":
+		return params
+	case "inferred_parameters":
+		if params.NamedChildCount() == 1 {
+			return params.NamedChild(0)
+		}
+	case "formal_parameters":
+		if params.NamedChildCount() == 1 {
+			return params.NamedChild(0).ChildByFieldName("name")
+		}
+	}
+	return nil
+}
+
+// apply applies a function of the stream to the current element, inlining the
+// body of a lambda with its parameter bound to the element. Any other function
+// is called with the element
+func (l *streamLoop) apply(node *sitter.Node) ast.Expr {
+	switch node.Type() {
+	case "lambda_expression":
+		param := lambdaParameter(node)
+		name := ParseExpr(param, l.source, l.ctx).(*ast.Ident).Name
+		l.scope.Children = append([]*symbol.Definition{{
+			OriginalName: param.Content(l.source),
+			Name:         name,
+			OriginalType: l.elementType,
+		}}, l.scope.Children...)
+		if ident, ok := l.element.(*ast.Ident); !ok || ident.Name != name {
+			l.declare(name, l.elementType, l.element)
+			l.element = &ast.Ident{Name: name}
+		}
+		return ParseExpr(node.ChildByFieldName("body"), l.source, l.ctx)
+	case "method_invocation":
+		if node.Content(l.source) == "Function.identity()" {
+			return l.element
+		}
+	case "method_reference":
+		// A method of the elements' class is called on the element, the same
+		// as a method reference to a method of String, or of a wrapper class,
+		// is translated
+		// Ex: `Person::getName` is `element.getName()`, and `Integer::intValue`
+		// is `*element` for a box
+		if node.NamedChildCount() == 2 && l.elementType != "" {
+			base, _ := parseJavaTypeString(l.elementType)
+			if node.NamedChild(0).Content(l.source) == stripJavaQualifier(base) {
+				if call := unboundLibraryCall(base, node.NamedChild(1).Content(l.source), l.element, l.boxed, nil); call != nil {
+					return call
+				}
+				method := ParseExpr(node.NamedChild(1), l.source, l.ctx).(*ast.Ident)
+				return &ast.CallExpr{Fun: &ast.SelectorExpr{X: l.element, Sel: method}}
+			}
+		}
+	}
+	return &ast.CallExpr{Fun: ParseExpr(node, l.source, l.ctx), Args: []ast.Expr{l.element}}
+}
+
+// isInlinedFunction determines if a function of a stream can be applied to the
+// elements of the loop, which is anything but a lambda that can't be inlined
+func isInlinedFunction(node *sitter.Node) bool {
+	return node == nil || node.Type() != "lambda_expression" || lambdaParameter(node) != nil
+}
+
+// applyToValue applies a function of the stream to the current element, where
+// the element is used again afterwards
+func (l *streamLoop) applyToValue(node *sitter.Node) ast.Expr {
+	if node.Type() != "lambda_expression" {
+		l.value()
+	}
+	return l.apply(node)
+}
+
+// lowerStream lowers a pipeline of a stream into a loop, as described above,
+// or returns nil if the expression isn't the end of a stream. A stream that
+// can't be lowered is reported
+func lowerStream(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	name := node.ChildByFieldName("name").Content(source)
+	arguments := node.ChildByFieldName("arguments")
+	if counts, ok := streamTerminals[name]; !ok || !slices.Contains(counts, int(arguments.NamedChildCount())) || node.ChildByFieldName("object") == nil {
+		return nil
+	}
+	created, stages := streamSource(node.ChildByFieldName("object"), source)
+	if created == nil {
+		return nil
+	}
+	unlowered := func(format string, args ...any) ast.Expr {
+		ctx.diagnostics.Report(node, streamLoweringDiagnostic, format, args...)
+		return nil
+	}
+
+	for _, stage := range stages {
+		stageName := stage.ChildByFieldName("name").Content(source)
+		if !streamStages[stageName] {
+			return unlowered("`%s` isn't lowered into a loop", stageName)
+		}
+		if !isInlinedFunction(stage.ChildByFieldName("arguments").NamedChild(0)) {
+			return unlowered("the lambda of `%s` is only lowered into a loop if it has one parameter, and an expression as its body", stageName)
+		}
+	}
+	switch {
+	case name == "collect":
+		if problem := checkCollector(arguments.NamedChild(0), streamCollectors, source); problem != "" {
+			return unlowered("%s", problem)
+		}
+	case arguments.NamedChildCount() == 1 && !isInlinedFunction(arguments.NamedChild(0)):
+		return unlowered("the lambda of `%s` is only lowered into a loop if it has one parameter, and an expression as its body", name)
+	}
+
+	// The names of the variables of the loop can't be taken by the lambdas, and
+	// are only declared in the function literal that the loop is in
+	names := localVariableNames(ctx).Fork()
+	var reserve func(node *sitter.Node)
+	reserve = func(node *sitter.Node) {
+		if node.Type() == "identifier" {
+			names.Reserve(node.Content(source))
+		}
+		for _, child := range nodeutil.NamedChildrenOf(node) {
+			reserve(child)
+		}
+	}
+	reserve(node)
+
+	// The loop only has a body until it is returned from the function
+	scope := &symbol.Definition{}
+	if ctx.localScope != nil {
+		scope.Children = []*symbol.Definition{{Parameters: ctx.localScope.Parameters, Children: ctx.localScope.Children}}
+		scope.IsStatic = ctx.localScope.IsStatic
+	}
+	loopCtx := ctx.Clone()
+	loopCtx.localScope = scope
+	loopCtx.expectedType = ""
+	loopCtx.hoisted = nil
+	loopCtx.errorTarget = nil
+	loop := &ast.RangeStmt{Key: &ast.Ident{Name: "_"}, Tok: token.DEFINE, Body: &ast.BlockStmt{}}
+	l := &streamLoop{ctx: loopCtx, source: source, scope: scope, names: names, stmts: &loop.Body.List, skip: &ast.BranchStmt{Tok: token.CONTINUE}, declared: map[string]string{}}
+
+	// The loop's variable is named after the parameter of the first lambda
+	variable := names.Allocate("element")
+	for _, stage := range append(stages, node) {
+		if stage.ChildByFieldName("arguments").NamedChildCount() == 0 {
+			continue
+		}
+		if param := lambdaParameter(stage.ChildByFieldName("arguments").NamedChild(0)); param != nil {
+			variable = ParseExpr(param, source, ctx).(*ast.Ident).Name
+		}
+		break
+	}
+	l.element = &ast.Ident{Name: variable}
+	if !streamRange(created, loop, l, ctx, source) {
+		return unlowered("the elements of `%s` aren't known to be a collection or an array", created.Content(source))
+	}
+	l.declared[variable] = l.elementType
+
+	// The last of `parallel` and `sequential` decides how the whole stream runs
+	parallel := created.ChildByFieldName("name").Content(source) == "parallelStream"
+	for _, stage := range stages {
+		switch stage.ChildByFieldName("name").Content(source) {
+		case "parallel":
+			parallel = true
+		case "sequential":
+			parallel = false
+		}
+	}
+	var fanOut *ast.FuncLit
+	if parallel && ParallelStreams && strings.HasSuffix(name, "Match") {
+		return unlowered("`%s` of a parallel stream isn't lowered into goroutines, since it stops at the first element that decides it", name)
+	} else if parallel && ParallelStreams {
+		fanOut = l.fanOut(loop)
+	} else if parallel {
+		ctx.diagnostics.Report(node, parallelStreamDiagnostic, "the parallel stream runs sequentially, since it is lowered into a loop")
+	}
+
+	for _, stage := range stages {
+		stageName := stage.ChildByFieldName("name").Content(source)
+		switch stageName {
+		case "boxed", "parallel", "sequential":
+			continue
+		}
+		function := stage.ChildByFieldName("arguments").NamedChild(0)
+		applied := l.apply(function)
+		if stageName == "filter" {
+			l.add(&ast.IfStmt{
+				Cond: &ast.UnaryExpr{Op: token.NOT, X: &ast.ParenExpr{X: applied}},
+				Body: &ast.BlockStmt{List: []ast.Stmt{l.skip}},
+			})
+			continue
+		}
+		elementType, known := primitiveStreamTypes[stageName]
+		boxed := false
+		if !known && function.Type() == "lambda_expression" {
+			elementType, _ = inferExprJavaType(function.ChildByFieldName("body"), l.ctx, source)
+			boxed = isBoxValue(function.ChildByFieldName("body"), l.ctx, source)
+		}
+		l.element, l.elementType, l.boxed = applied, elementType, boxed
+	}
+
+	// The elements are collected after the stages of a parallel stream, by
+	// the function that the stages return
+	if fanOut != nil {
+		l.value()
+		collect := &ast.FuncLit{Type: &ast.FuncType{Params: &ast.FieldList{}}, Body: &ast.BlockStmt{}}
+		l.add(&ast.ReturnStmt{Results: []ast.Expr{collect}})
+		l.stmts, l.declared = &collect.Body.List, map[string]string{}
+	}
+	collected := terminateStream(node, l, ctx)
+	if collected == nil {
+		return nil
+	}
+	var run ast.Stmt = loop
+	if fanOut != nil {
+		run = &ast.ExprStmt{X: runtimeCall("Parallel", parallelElements(loop, l.mapValues), fanOut)}
+	}
+	lowered := &ast.FuncLit{Type: &ast.FuncType{Params: &ast.FieldList{}}, Body: &ast.BlockStmt{List: append(collected.init, run)}}
+	// `forEach` doesn't have a result
+	if collected.typ != nil {
+		lowered.Type.Results = &ast.FieldList{List: []*ast.Field{{Type: collected.typ}}}
+		lowered.Body.List = append(lowered.Body.List, &ast.ReturnStmt{Results: []ast.Expr{collected.result}})
+	}
+	return &ast.CallExpr{Fun: lowered}
+}
+
+// terminateStream ends the loop of a stream with the operation that ends the
+// stream, or returns nil, and reports it, if the result of the operation
+// isn't known. The matches return from the loop as soon as an element decides
+// them
+func terminateStream(node *sitter.Node, l *streamLoop, ctx Ctx) *collectedStream {
+	source := l.source
+	function := node.ChildByFieldName("arguments").NamedChild(0)
+	returned := func(value string) ast.Stmt {
+		return &ast.ReturnStmt{Results: []ast.Expr{&ast.Ident{Name: value}}}
+	}
+
+	switch name := node.ChildByFieldName("name").Content(source); name {
+	case "collect":
+		return collectStream(function, l, ctx)
+	case "toList":
+		return collectElements(name, node, l, ctx)
+	case "count", "sum":
+		resultType := &ast.Ident{Name: "int64"}
+		if name == "sum" {
+			if primitive, ok := javaTypeStringToGoTypeExpr(l.elementType, nil).(*ast.Ident); ok && primitiveTypes[l.elementType] {
+				resultType = primitive
+			} else {
+				ctx.diagnostics.Report(node, streamLoweringDiagnostic, "the type of the numbers that `sum` adds up isn't known")
+				return nil
+			}
+		}
+		total := &ast.Ident{Name: l.names.Allocate("result")}
+		if name == "count" {
+			l.add(&ast.IncDecStmt{X: total, Tok: token.INC})
+		} else {
+			l.add(&ast.AssignStmt{Lhs: []ast.Expr{total}, Tok: token.ADD_ASSIGN, Rhs: []ast.Expr{l.element}})
+		}
+		return &collectedStream{
+			typ:    resultType,
+			init:   []ast.Stmt{&ast.DeclStmt{Decl: &ast.GenDecl{Tok: token.VAR, Specs: []ast.Spec{&ast.ValueSpec{Names: []*ast.Ident{total}, Type: resultType}}}}},
+			result: total,
+		}
+	case "anyMatch", "allMatch", "noneMatch":
+		matched := l.apply(function)
+		decided, otherwise := "false", "true"
+		if name == "anyMatch" {
+			decided, otherwise = "true", "false"
+		}
+		if name == "allMatch" {
+			matched = &ast.UnaryExpr{Op: token.NOT, X: &ast.ParenExpr{X: matched}}
+		}
+		l.add(&ast.IfStmt{Cond: matched, Body: &ast.BlockStmt{List: []ast.Stmt{returned(decided)}}})
+		return &collectedStream{typ: &ast.Ident{Name: "bool"}, result: &ast.Ident{Name: otherwise}}
+	case "forEach":
+		l.add(&ast.ExprStmt{X: l.apply(function)})
+		return &collectedStream{}
+	}
+	return nil
+}
+
+// loweredForEach returns the loop that a stream that ends with `forEach` is
+// lowered into, as a statement on its own, or nil if the statement isn't one
+func loweredForEach(node *sitter.Node, expr ast.Expr, source []byte) ast.Stmt {
+	if node.Type() != "method_invocation" || node.ChildByFieldName("name").Content(source) != "forEach" {
+		return nil
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return nil
+	}
+	lowered, ok := call.Fun.(*ast.FuncLit)
+	if !ok || lowered.Type.Results != nil {
+		return nil
+	}
+	if len(lowered.Body.List) == 1 {
+		return lowered.Body.List[0]
+	}
+	return lowered.Body
+}
+
+// streamRange sets what a loop ranges over, from the node that the stream is
+// created by, and the Java type of the loop's elements, or returns false if
+// the elements of the stream aren't known
+func streamRange(created *sitter.Node, loop *ast.RangeStmt, l *streamLoop, ctx Ctx, source []byte) bool {
+	collection := created.ChildByFieldName("object")
+	if created.ChildByFieldName("arguments").NamedChildCount() == 1 {
+		collection = created.ChildByFieldName("arguments").NamedChild(0)
+	}
+	loop.X = ParseExpr(collection, source, ctx)
+	loop.Value = l.element
+
+	// The views of a map are ranged over as the map
+	if view := unwrapParentheses(collection); view.Type() == "method_invocation" && view.ChildByFieldName("object") != nil {
+		if keyType, valueType, ok := mapExprType(view.ChildByFieldName("object"), ctx, source); ok {
+			loop.X = ParseExpr(view.ChildByFieldName("object"), source, ctx)
+			switch view.ChildByFieldName("name").Content(source) {
+			case "keySet":
+				loop.Key, loop.Value, l.elementType = l.element, nil, keyType
+				return true
+			case "values":
+				l.elementType, l.mapValues = valueType, true
+				l.boxed = astutil.BoxedElements && isBoxedType(valueType)
+				return true
+			}
+			return false
+		}
+	}
+
+	if element, ok := setExprType(collection, ctx, source); ok {
+		loop.Key, loop.Value, l.elementType = l.element, nil, element
+		return true
+	}
+	javaType, ok := inferExprJavaType(collection, ctx, source)
+	if !ok {
+		return false
+	}
+	if strings.HasSuffix(javaType, "[]") {
+		l.elementType = strings.TrimSuffix(javaType, "[]")
+		l.boxed = isBoxedType(l.elementType)
+		return true
+	}
+	_, typeArgs := parseJavaTypeString(javaType)
+	if len(typeArgs) != 1 {
+		return false
+	}
+	if sortedClass(collection, ctx, source) == "TreeSet" {
+		loop.X = &ast.CallExpr{Fun: &ast.SelectorExpr{X: loop.X, Sel: &ast.Ident{Name: "Elements"}}}
+	}
+	l.elementType = typeArgs[0]
+	// The elements are boxes unless they would be unboxed by ranging over them
+	l.boxed = isBoxedType(l.elementType) && (astutil.BoxedElements || !hasUnboxedElements(collection, ctx, source))
+	return true
+}
+
+// fanOut returns the function that runs the stages of a parallel stream for
+// one of the elements of a loop, which the stages are added to instead of the
+// loop. The function returns nil for the elements that are filtered out
+func (l *streamLoop) fanOut(loop *ast.RangeStmt) *ast.FuncLit {
+	element, typ := loop.Value, javaTypeStringToGoTypeExpr(l.elementType, inScopeTypeParameters(l.ctx))
+	switch {
+	case element == nil:
+		element, typ = loop.Key, elementType(l.elementType, true, l.ctx)
+	case l.mapValues:
+		typ = elementType(l.elementType, false, l.ctx)
+	}
+	stages := &ast.FuncLit{
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{List: []*ast.Field{{Names: []*ast.Ident{element.(*ast.Ident)}, Type: typ}}},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: &ast.FuncType{Params: &ast.FieldList{}}}}},
+		},
+		Body: &ast.BlockStmt{},
+	}
+	l.stmts = &stages.Body.List
+	l.skip = &ast.ReturnStmt{Results: []ast.Expr{&ast.Ident{Name: "nil"}}}
+	return stages
+}
+
+// parallelElements returns the elements that a loop ranges over as a slice,
+// which is what the runtime runs a parallel stream over
+//
+// Ex: the keys of a set are `slices.Collect(maps.Keys(seen))`
+func parallelElements(loop *ast.RangeStmt, mapValues bool) ast.Expr {
+	view := ""
+	switch {
+	case loop.Value == nil:
+		view = "Keys"
+	case mapValues:
+		view = "Values"
+	default:
+		return loop.X
+	}
+	return &ast.CallExpr{
+		Fun: &ast.SelectorExpr{X: &ast.Ident{Name: "slices"}, Sel: &ast.Ident{Name: "Collect"}},
+		Args: []ast.Expr{&ast.CallExpr{
+			Fun:  &ast.SelectorExpr{X: &ast.Ident{Name: "maps"}, Sel: &ast.Ident{Name: view}},
+			Args: []ast.Expr{loop.X},
+		}},
+	}
+}
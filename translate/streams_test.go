@@ -0,0 +1,184 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStreamCollectors(t *testing.T) {
+	helper := setupParseHelper(t, `
+package util.words;
+import java.util.*;
+import java.util.stream.*;
+public class Words {
+    public Map<Boolean, List<Integer>> parts(List<Integer> numbers) {
+        Map<Boolean, List<Integer>> parts = numbers.stream()
+            .filter(n -> n > 0)
+            .collect(Collectors.partitioningBy(n -> n % 2 == 0));
+        return parts;
+    }
+    public Map<Character, Long> counts(Set<String> words) {
+        Map<Character, Long> counts = words.stream().collect(Collectors.groupingBy(w -> w.charAt(0), Collectors.counting()));
+        return counts;
+    }
+    public Map<String, Integer> lengths(String[] words) {
+        Map<String, Integer> merged = Arrays.stream(words).collect(Collectors.toMap(Function.identity(), String::length, (a, b) -> a));
+        Map<String, Integer> unique = Arrays.stream(words).map(w -> w.trim()).collect(Collectors.toMap(w -> w, w -> 1));
+        return merged;
+    }
+    public String join(List<String> words) {
+        var lengths = words.stream().collect(Collectors.toMap(w -> w, w -> 1));
+        return words.stream().filter(w -> w != null).collect(Collectors.joining(", ", "[", "]"));
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Words.java")
+
+	out := renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		"parts := func() map[bool][]int32 {",
+		"result := map[bool][]int32{false: nil, true: nil}",
+		"for _, n := range numbers {",
+		"if !(n > 0) {",
+		"key := n%2 == 0",
+		"result[key] = append(result[key], n)",
+		"counts := func() map[rune]int64 {",
+		"for element := range words {",
+		"result[key]++",
+		"merged := func() map[string]int32 {",
+		"stdjava.Merge(result, element, element.length(), func(a int32, b int32) int32 {",
+		"w := w.trim()",
+		"stdjava.PutUnique(result, w, 1)",
+		"parts = append(parts, w)",
+		`return "[" + strings.Join(parts, ", ") + "]"`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != streamLoweringDiagnostic || items[0].Line != 22 {
+		t.Errorf("Expected the map of unknown type on line 22 to be reported, got %v", items)
+	}
+}
+
+func TestParallelStreams(t *testing.T) {
+	src := `
+package util.words;
+import java.util.*;
+import java.util.stream.*;
+public class Words {
+    public Map<Character, Long> counts(Set<String> words) {
+        return words.parallelStream().map(w -> w.trim()).collect(Collectors.groupingBy(w -> w.charAt(0), Collectors.counting()));
+    }
+    public String join(String[] words) {
+        return Arrays.stream(words).parallel().sequential().filter(w -> w != null).collect(Collectors.joining());
+    }
+}
+`
+	helper := setupParseHelper(t, src)
+	helper.Ctx.diagnostics = NewDiagnostics("Words.java")
+	out := renderGoFileFromJavaCtx(t, helper)
+	if !strings.Contains(out, "for w := range words {") || strings.Contains(out, "stdjava.Parallel") {
+		t.Errorf("Expected the parallel stream to be lowered into a loop, got:\n%s", out)
+	}
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != parallelStreamDiagnostic || items[0].Line != 7 {
+		t.Errorf("Expected the sequential parallel stream on line 7 to be reported, got %v", items)
+	}
+
+	ParallelStreams = true
+	defer func() { ParallelStreams = false }()
+
+	helper = setupParseHelper(t, src)
+	helper.Ctx.diagnostics = NewDiagnostics("Words.java")
+	out = renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		"stdjava.Parallel(slices.Collect(maps.Keys(words)), func(w string) func() {",
+		"value := w.trim()",
+		"return func() {",
+		"w := value",
+		"result[key]++",
+		"for _, w := range words {",
+		"if !(w != nil) {",
+		"continue",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+	if items := helper.Ctx.diagnostics.Items(); len(items) != 0 {
+		t.Errorf("Expected nothing to be reported, got %v", items)
+	}
+}
+
+func TestStreamTerminals(t *testing.T) {
+	helper := setupParseHelper(t, `
+package util.words;
+import java.util.*;
+import java.util.stream.*;
+public class Words {
+    public List<String> trimmed(List<String> words) {
+        return words.stream().filter(w -> w != null).map(w -> w.trim()).collect(Collectors.toList());
+    }
+    public boolean check(List<String> words) {
+        long empty = words.stream().filter(w -> w.isEmpty()).count();
+        int total = words.stream().mapToInt(w -> w.length()).sum();
+        boolean all = words.stream().allMatch(w -> w != null);
+        words.stream().filter(w -> w != null).forEach(w -> print(w));
+        return words.parallelStream().anyMatch(w -> w.isEmpty());
+    }
+    void print(String word) {}
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Words.java")
+	ParallelStreams = true
+	defer func() { ParallelStreams = false }()
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helper))
+	for _, expected := range []string{
+		"return func() []string { var result []string for _, w := range words {",
+		"result = append(result, w.trim())",
+		"empty := func() int64 { var result int64",
+		"result++",
+		"total := func() int32 { var result int32",
+		"result += w.length()",
+		"all := func() bool { for _, w := range words { if !(w != nil) { return false } } return true }()",
+		"for _, w := range words { if !(w != nil) { continue } print(w) }",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != streamLoweringDiagnostic || items[0].Line != 14 {
+		t.Errorf("Expected the parallel match on line 14 to be reported, got %v", items)
+	}
+}
+
+func TestStreamUnboxingMethodReferences(t *testing.T) {
+	out := normalizeSpaces(renderGoFileFromJava(t, `
+package util.numbers;
+import java.util.*;
+public class Numbers {
+    public long total(Integer[] counts, Set<Integer> seen) {
+        int sum = Arrays.stream(counts).mapToInt(Integer::intValue).sum();
+        long wide = Arrays.stream(counts).mapToLong(Integer::longValue).sum();
+        int unique = seen.stream().mapToInt(Integer::intValue).sum();
+        return sum + wide + unique;
+    }
+}
+`))
+	for _, expected := range []string{
+		"sum := func() int32 { var result int32 for _, element := range counts { result += *element } return result }()",
+		"wide := func() int64 { var result int64 for _, element := range counts { result += int64(*element) } return result }()",
+		// The keys of a set are never boxes
+		"for element := range seen { result += element }",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// A class that declares `toString` has a `String` method as well, which calls
+// it, so that it implements `fmt.Stringer`, and Go describes its objects with
+// it wherever it formats them, the same as Java does when it prints them:
+//
+//	func (pt *Point) String() string {
+//	    return pt.ToString()
+//	}
+//
+// An object that is concatenated onto a string is described by the runtime's
+// `ObjectToString`, which is "null" for a missing object, the same as Java. A
+// `%s` in a format string is a `%v` for an argument that is neither a string
+// nor an object that describes itself, since Go's `%s` only formats those, and
+// a `%c` for a `char`
+
+// toStringMethod returns the `toString` method that a class declares, or nil
+// if it doesn't declare one
+func toStringMethod(class *symbol.ClassScope) *symbol.Definition {
+	for _, method := range class.Methods {
+		if method.OriginalName == "toString" && len(method.Parameters) == 0 && !method.IsStatic && !method.Constructor {
+			return method
+		}
+	}
+	return nil
+}
+
+// isStringerClass determines if the objects of a class describe themselves
+// with a `String` method, which a record always has, and any other class has
+// if it, or one of the classes that it extends, declares `toString`
+func isStringerClass(class *symbol.ClassScope, ctx Ctx) bool {
+	for visited := map[*symbol.ClassScope]bool{}; class != nil && !visited[class]; class = resolveClassScopeByName(ctx, class.Superclass) {
+		visited[class] = true
+		if class.IsRecord || toStringMethod(class) != nil {
+			return true
+		}
+		if class.Superclass == "" {
+			break
+		}
+	}
+	return false
+}
+
+// stringerBridge generates the `String` method of the current class, which
+// calls its `toString`, or returns nil if it doesn't declare one, or a record
+// generates its own. A class that already has a method named `String` keeps it
+func stringerBridge(ctx Ctx) []ast.Decl {
+	class := ctx.currentClass
+	if class == nil || class.IsInterface {
+		return nil
+	}
+	method := toStringMethod(class)
+	if method == nil || method.Name == "String" {
+		return nil
+	}
+	for _, declared := range class.Methods {
+		if declared.Name == "String" {
+			return nil
+		}
+	}
+
+	receiver := ReceiverName(ctx)
+	return []ast.Decl{&ast.FuncDecl{
+		Name: &ast.Ident{Name: "String"},
+		Recv: &ast.FieldList{List: []*ast.Field{{Names: []*ast.Ident{{Name: receiver}}, Type: classReceiverType(class, ctx)}}},
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: &ast.Ident{Name: "string"}}}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{
+			&ast.CallExpr{Fun: &ast.SelectorExpr{X: &ast.Ident{Name: receiver}, Sel: &ast.Ident{Name: method.Name}}},
+		}}}},
+	}}
+}
+
+// exprClass returns the class of the package that an expression evaluates to
+// an object of, or nil if it doesn't evaluate to one
+func exprClass(node *sitter.Node, ctx Ctx, source []byte) *symbol.ClassScope {
+	if node.Type() == "this" {
+		return ctx.currentClass
+	}
+	javaType, ok := inferExprJavaType(node, ctx, source)
+	if !ok {
+		return nil
+	}
+	base, _ := parseJavaTypeString(javaType)
+	return resolveClassScopeByName(ctx, base)
+}
+
+// concatenatedOperand converts an operand of a concatenation of strings into a
+// string, for the numbers and the objects that Go doesn't convert on its own
+//
+// Ex: `"at " + point` turns into `"at " + stdjava.ObjectToString(point)`
+func concatenatedOperand(node *sitter.Node, value ast.Expr, ctx Ctx, source []byte) ast.Expr {
+	if class := exprClass(unwrapParentheses(node), ctx, source); class != nil && !class.IsInterface && (!class.IsEnum || class.EnumHasState) {
+		return runtimeCall("ObjectToString", value)
+	}
+	if isBuilderExpr(node, ctx, source) {
+		return &ast.CallExpr{Fun: &ast.SelectorExpr{X: value, Sel: &ast.Ident{Name: "String"}}}
+	}
+	return formatFloating(node, value, ctx, source)
+}
+
+// formatSpecifierPattern matches the format specifiers of a Java format
+// string, with their explicit indexes, flags, widths, precisions, and
+// conversions
+var formatSpecifierPattern = regexp.MustCompile(`%(\d+\$)?([-#+ 0,(<]*)(\d+)?(\.\d+)?([a-zA-Z%])`)
+
+// formatVerbs replaces the `%s` specifiers of a format string that is written
+// out with the verbs that Go formats their arguments with the same as Java, as
+// described above. A format string that refers to its arguments by their
+// indexes is left as it is
+//
+// Ex: `"%s is %s"` with a string and an `int` turns into `"%s is %v"`
+func formatVerbs(format ast.Expr, argNodes []*sitter.Node, ctx Ctx, source []byte) ast.Expr {
+	literal, ok := format.(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return format
+	}
+	value, err := strconv.Unquote(literal.Value)
+	if err != nil {
+		return format
+	}
+
+	var replaced strings.Builder
+	var argIndex, last int
+	for _, match := range formatSpecifierPattern.FindAllStringSubmatchIndex(value, -1) {
+		// Explicit indexes and the previous argument's `<` aren't counted
+		if match[3] != -1 || strings.Contains(value[match[4]:match[5]], "<") {
+			return format
+		}
+		conversion := value[match[10]:match[11]]
+		if conversion == "%" || conversion == "n" {
+			continue
+		}
+		if conversion == "s" && argIndex < len(argNodes) {
+			if verb := stringVerb(argNodes[argIndex], ctx, source); verb != "s" {
+				replaced.WriteString(value[last:match[10]] + verb)
+				last = match[11]
+			}
+		}
+		argIndex++
+	}
+	if last == 0 {
+		return format
+	}
+	replaced.WriteString(value[last:])
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(replaced.String())}
+}
+
+// stringVerb returns the verb that Go formats an argument of a `%s` with the
+// same as Java
+func stringVerb(node *sitter.Node, ctx Ctx, source []byte) string {
+	switch javaType := argumentJavaType(node, ctx, source); {
+	case javaType == "char" || javaType == "Character":
+		return "c"
+	case javaType == "String" || javaType == "":
+		return "s"
+	case primitiveTypes[javaType] || isBoxedType(javaType):
+		return "v"
+	}
+	if class := exprClass(unwrapParentheses(node), ctx, source); class != nil && !isStringerClass(class, ctx) {
+		return "v"
+	}
+	return "s"
+}
@@ -0,0 +1,42 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStringers(t *testing.T) {
+	out := normalizeSpaces(renderGoFileFromJava(t, `
+package app.stringers;
+public class Point {
+    int x;
+    public String toString() { return "Point"; }
+    static class Plain { int y; }
+    void print(Plain plain, int n, char c, String s, Integer boxed) {
+        System.out.println(this);
+        System.out.println("at " + this + " and " + plain);
+        System.out.printf("%s %s %-4s %s %s %s%n", this, plain, n, c, s, boxed);
+        String both = String.format("%1$s %1$s", n);
+        String percent = String.format("100%% %s", n);
+    }
+}
+`))
+	for _, expected := range []string{
+		"func (pt *Point) String() string { return pt.ToString() }",
+		"fmt.Println(pt)",
+		`fmt.Println("at " + stdjava.ObjectToString(pt) + " and " + stdjava.ObjectToString(plain))`,
+		`fmt.Printf("%s %v %-4v %c %s %v\n", pt, plain, n, c, s, boxed)`,
+		// Arguments that are referred to by their indexes are left as they are
+		`both := fmt.Sprintf("%1$s %1$s", n)`,
+		`percent := fmt.Sprintf("100%% %v", n)`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	// A class without `toString` has no String method
+	if strings.Contains(out, "Pointplain) String()") {
+		t.Errorf("Expected a class without toString not to describe itself, got:\n%s", out)
+	}
+}
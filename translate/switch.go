@@ -0,0 +1,259 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for the parts of switches that aren't
+// translated, such as patterns, and for switch expressions whose type isn't
+// known
+const switchDiagnostic = "switch"
+
+// A switch is Go's switch, whether it is a statement or an expression. Go's
+// cases don't fall through to the next one, so a group of statements that
+// doesn't end with a `break`, or anything else that leaves it, ends with a
+// `fallthrough`, and the groups without any statements share the case of the
+// group after them:
+//
+//	case 1:                   case 1, 2:
+//	case 2:                       n++
+//	    n++;                  case 3:
+//	    break;                    n--
+//	case 3:                       fallthrough
+//	    n--;                  default:
+//	default:                      n = 0
+//	    n = 0;
+//
+// A switch expression is a function literal that is called in place, and
+// returns the value that the switch selects, which is what `yield` returns:
+//
+//	int x = switch (day) {    x := func() int32 {
+//	    case 1, 2 -> 1;           switch day {
+//	    default -> {              case 1, 2:
+//	        int y = 2;                return 1
+//	        yield y * 2;          default:
+//	    }                             y := 2
+//	};                                return y * 2
+//	                              }
+//	                          }()
+//
+// A switch expression without a default case covers every constant of an enum,
+// so it panics if none of them match, the same as Java does
+
+// switchTag parses the value that a switch selects a case with
+func switchTag(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	return ParseExpr(unwrapParentheses(node.ChildByFieldName("condition")), source, ctx)
+}
+
+// switchCase parses the label of a case of a switch. The constants of an enum
+// are named without their enum in the labels, and the numbers that a switch on
+// a `char` is compared with are the characters that they are the code of
+func switchCase(label *sitter.Node, switchNode *sitter.Node, source []byte, ctx Ctx) *ast.CaseClause {
+	clause := ParseNode(label, source, ctx).(*ast.CaseClause)
+	condition := unwrapParentheses(switchNode.ChildByFieldName("condition"))
+	if integralType(condition, ctx, source) == "char" {
+		for ind, value := range nodeutil.NamedChildrenOf(label) {
+			if char := charCase(value, source); char != nil {
+				clause.List[ind] = char
+			}
+		}
+		return clause
+	}
+	javaType, ok := inferExprJavaType(condition, ctx, source)
+	if !ok {
+		return clause
+	}
+	if class := resolveClassScopeByName(ctx, javaType); class != nil && class.IsEnum {
+		for ind, value := range nodeutil.NamedChildrenOf(label) {
+			if value.Type() == "identifier" {
+				clause.List[ind] = &ast.Ident{Name: enumConstantName(class, value.Content(source))}
+			}
+		}
+	}
+	return clause
+}
+
+// charCase returns the character that a number in the label of a switch on a
+// `char` is the code of, or nil if the value isn't a number that is written
+// out, or is half of a surrogate pair, which isn't a character of its own
+//
+// Ex: `case 65:` turns into `case 'A':`
+func charCase(value *sitter.Node, source []byte) ast.Expr {
+	code, ok := constantValue(value, source)
+	if !ok || value.Type() == "unary_expression" || code < 0 || code > 0xFFFF || utf16.IsSurrogate(rune(code)) {
+		return nil
+	}
+	return &ast.BasicLit{Kind: token.CHAR, Value: strconv.QuoteRune(rune(code))}
+}
+
+// switchLabelExprs parses the values of the label of a case of a switch, or
+// reports the patterns that it matches, which aren't translated
+func switchLabelExprs(label *sitter.Node, source []byte, ctx Ctx) []ast.Expr {
+	var values []ast.Expr
+	for _, value := range nodeutil.NamedChildrenOf(label) {
+		switch value.Type() {
+		case "pattern", "type_pattern", "record_pattern", "guard":
+			ctx.diagnostics.Report(value, switchDiagnostic, "the pattern `%s` of the switch isn't translated", value.Content(source))
+			values = append(values, &ast.BadExpr{})
+		default:
+			values = append(values, ParseExpr(value, source, ctx))
+		}
+	}
+	return values
+}
+
+// isDefaultCase determines if the label of a case of a switch is `default`
+func isDefaultCase(label *sitter.Node) bool {
+	return label.NamedChildCount() == 0
+}
+
+// leavesCase determines if the last statement of a group of statements in a
+// switch leaves it, so that it doesn't fall through to the next group
+func leavesCase(last *sitter.Node) bool {
+	switch last.Type() {
+	case "break_statement", "continue_statement", "return_statement", "throw_statement", "yield_statement":
+		return true
+	}
+	return false
+}
+
+// switchClauses parses the cases of a switch, with either groups of statements
+// or rules with arrows. The body of a rule is parsed with `rule`
+func switchClauses(node *sitter.Node, source []byte, ctx Ctx, rule func(body *sitter.Node) []ast.Stmt) []ast.Stmt {
+	var clauses []ast.Stmt
+	groups := nodeutil.NamedChildrenOf(node.ChildByFieldName("body"))
+	var shared []ast.Expr
+	for ind, group := range groups {
+		if group.Type() != "switch_block_statement_group" && group.Type() != "switch_rule" {
+			continue
+		}
+		children := nodeutil.NamedChildrenOf(group)
+		label := children[0]
+		clause := switchCase(label, node, source, ctx)
+
+		if group.Type() == "switch_rule" {
+			clause.Body = rule(children[1])
+			clauses = append(clauses, clause)
+			continue
+		}
+
+		var statements []*sitter.Node
+		for _, child := range children[1:] {
+			switch child.Type() {
+			case "switch_label", "comment", "line_comment", "block_comment":
+			default:
+				statements = append(statements, child)
+			}
+		}
+
+		// A group without statements shares the case of the group after it,
+		// unless one of them is the default case
+		last := ind == len(groups)-1
+		if len(statements) == 0 && !last && !isDefaultCase(label) && !isDefaultCase(nodeutil.NamedChildrenOf(groups[ind+1])[0]) {
+			shared = append(shared, clause.List...)
+			continue
+		}
+		clause.List = append(shared, clause.List...)
+		shared = nil
+
+		clause.Body = parseStmtList(statements, source, ctx)
+		switch {
+		case len(statements) > 0 && statements[len(statements)-1].Type() == "break_statement" && statements[len(statements)-1].NamedChildCount() == 0:
+			// Go's cases end without a break
+			clause.Body = clause.Body[:len(clause.Body)-1]
+		case !last && (len(statements) == 0 || !leavesCase(statements[len(statements)-1])):
+			clause.Body = append(clause.Body, &ast.BranchStmt{Tok: token.FALLTHROUGH})
+		}
+		clauses = append(clauses, clause)
+	}
+	return clauses
+}
+
+// switchStatement translates a switch that is used as a statement
+func switchStatement(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	rule := func(body *sitter.Node) []ast.Stmt {
+		if body.Type() == "block" {
+			return ParseStmt(body, source, ctx).(*ast.BlockStmt).List
+		}
+		return parseStmtList([]*sitter.Node{body}, source, ctx)
+	}
+	return &ast.SwitchStmt{
+		Tag:  switchTag(node, source, ctx),
+		Body: &ast.BlockStmt{List: switchClauses(node, source, ctx, rule)},
+	}
+}
+
+// switchExpression translates a switch that is used as an expression, into a
+// function literal that is called in place, or reports it if the type of its
+// value isn't known
+func switchExpression(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	resultType := switchType(node, ctx, source)
+	if resultType == nil {
+		ctx.diagnostics.Report(node, switchDiagnostic, "the type of the value of the switch expression isn't known")
+		ctx.fallbacks.Report(node, anyFallbackDiagnostic, "the type of the value of the switch expression isn't known")
+		resultType = &ast.Ident{Name: "any"}
+	}
+
+	// The cases are only evaluated if they are selected, and their exceptions
+	// can't be returned from the function literal
+	bodyCtx := ctx.Clone()
+	bodyCtx.hoisted = nil
+	bodyCtx.errorTarget = nil
+	rule := func(body *sitter.Node) []ast.Stmt {
+		switch body.Type() {
+		case "block":
+			return ParseStmt(body, source, bodyCtx).(*ast.BlockStmt).List
+		case "expression_statement":
+			return []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{ParseExpr(body.NamedChild(0), source, bodyCtx)}}}
+		}
+		return parseStmtList([]*sitter.Node{body}, source, bodyCtx)
+	}
+
+	clauses := switchClauses(node, source, bodyCtx, rule)
+	body := &ast.BlockStmt{List: []ast.Stmt{&ast.SwitchStmt{Tag: switchTag(node, source, ctx), Body: &ast.BlockStmt{List: clauses}}}}
+	hasDefault := false
+	for _, clause := range clauses {
+		if clause.(*ast.CaseClause).List == nil {
+			hasDefault = true
+		}
+	}
+	if !hasDefault {
+		body.List = append(body.List, &ast.ExprStmt{X: &ast.CallExpr{
+			Fun:  &ast.Ident{Name: "panic"},
+			Args: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: `"no case of the switch matches"`}},
+		}})
+	}
+
+	return &ast.CallExpr{Fun: &ast.FuncLit{
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: resultType}}},
+		},
+		Body: body,
+	}}
+}
+
+// switchType returns the Go type of the value of a switch expression, from
+// the type of one of the values of its rules, or from where its value goes, or
+// nil if the type isn't known
+func switchType(node *sitter.Node, ctx Ctx, source []byte) ast.Expr {
+	for _, group := range nodeutil.NamedChildrenOf(node.ChildByFieldName("body")) {
+		if group.Type() != "switch_rule" || group.NamedChild(1).Type() != "expression_statement" {
+			continue
+		}
+		value := group.NamedChild(1).NamedChild(0)
+		if javaType, ok := literalJavaType(value, source); ok {
+			return javaTypeStringToGoTypeExpr(javaType, inScopeTypeParameters(ctx))
+		}
+		if javaType, ok := inferExprJavaType(value, ctx, source); ok {
+			return javaTypeStringToGoTypeExpr(javaType, inScopeTypeParameters(ctx))
+		}
+	}
+	return destinationType(node, ctx)
+}
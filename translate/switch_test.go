@@ -0,0 +1,150 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSwitches(t *testing.T) {
+	helper := setupParseHelper(t, `
+package stmt.switches;
+public class Days {
+    enum Day { MON, TUE, SUN }
+
+    public int score(Day day, int n, String name) {
+        switch (n) {
+            case 1:
+            case 2:
+                n++;
+                break;
+            case 3:
+                n--;
+            default:
+                n = 0;
+        }
+        int x = switch (n) {
+            case 1, 2 -> 1;
+            case 3 -> {
+                int y = n * 2;
+                yield y;
+            }
+            default -> throw new IllegalStateException();
+        };
+        switch (name) {
+            case "a" -> n = 1;
+            default -> {
+                n = 2;
+            }
+        }
+        int z = switch (n) {
+            case 1:
+                yield 3;
+            default:
+                yield 4;
+        };
+        return switch (day) {
+            case MON, TUE -> x;
+            case SUN -> z;
+        };
+    }
+
+    public void print(int n) {
+        System.out.println(switch (n) { case 1 -> "one"; default -> "many"; });
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Days.java")
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helper))
+	for _, expected := range []string{
+		"switch n { case 1, 2: n++ case 3: n-- fallthrough default: n = 0 }",
+		"x := func() int32 { switch n { case 1, 2: return 1 case 3: y := n * 2 return y default: panic(",
+		`switch name { case "a": n = 1 default: n = 2 }`,
+		"z := func() int32 { switch n { case 1: return 3 default: return 4 } }()",
+		`return func() int32 { switch day { case MON, TUE: return x case SUN: return z } panic("no case of the switch matches") }()`,
+		`func() string { switch n { case 1: return "one" default: return "many" } }()`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	if items := helper.Ctx.diagnostics.Items(); len(items) != 0 {
+		t.Errorf("Expected no diagnostics, got %v", items)
+	}
+}
+
+func TestSwitchExpressionOfUnknownType(t *testing.T) {
+	helper := setupParseHelper(t, `
+package stmt.switches;
+public class Unknown {
+    public void print(int n, Object a, Object b) {
+        log(switch (n) { case 1 -> a.value(); default -> b.value(); });
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Unknown.java")
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helper))
+	if !strings.Contains(out, "func() any { switch n {") {
+		t.Errorf("Expected the switch to return `any`, got:\n%s", out)
+	}
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != switchDiagnostic || items[0].Line != 5 {
+		t.Errorf("Expected the switch expression on line 5 to be reported, got %v", items)
+	}
+}
+
+func TestCharSwitches(t *testing.T) {
+	out := normalizeSpaces(renderGoFileFromJava(t, `
+package stmt.switches.chars;
+public class Lexer {
+    int kind(char c) {
+        switch (c) {
+            case 'a':
+            case 'e':
+                return 1;
+            case '\n':
+            case '\'':
+                return 2;
+            case 65:
+                return 3;
+            case '0':
+                c++;
+            case '1':
+            case '2':
+                return 4;
+            case 'a' + 25:
+                return 5;
+            case '\uD800':
+                return 6;
+        }
+        return 0;
+    }
+
+    char next(char c) {
+        return switch (c) {
+            case 'z' -> (char) ('a' - 1);
+            case 'é' -> 'e';
+            default -> c;
+        };
+    }
+
+    int offset(char c) {
+        switch (c - 'a') {
+            case 0: return 1;
+        }
+        return 0;
+    }
+}
+`))
+	for _, expected := range []string{
+		`switch c { case 'a', 'e': return 1 case '\n', '\'': return 2 case 'A': return 3 case '0': c++ fallthrough case '1', '2': return 4 case 'a' + 25: return 5 case 0xD800: return 6 }`,
+		`return func() rune { switch c { case 'z': return rune('a' - 1) case 'é': return 'e' default: return c } }()`,
+		`switch c - 'a' { case 0: return 1 }`,
+	} {
+		if !strings.Contains(out, normalizeSpaces(expected)) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for the synchronization that behaves
+// differently with Go's mutexes
+const synchronizedDiagnostic = "synchronized"
+
+// A class whose objects are locked, by its synchronized methods or by blocks
+// that synchronize on `this`, has a `sync.Mutex` that stands in for the
+// monitor of its objects. A synchronized method locks it for as long as it
+// runs:
+//
+//	func (ct *Account) Deposit(amount int32) {
+//		ct.mu.Lock()
+//		defer ct.mu.Unlock()
+//		...
+//	}
+//
+// Static synchronized methods, and blocks that synchronize on any other object,
+// lock the mutexes that the runtime keeps for classes and objects. A block is
+// run the same way as the body of a try-with-resources statement, so that the
+// mutex is unlocked when the block completes
+//
+// Go's mutexes aren't reentrant, so a synchronized method that calls another
+// one on the same object deadlocks, and is reported
+
+// monitorOf returns the mutex that synchronizing on an expression locks
+func monitorOf(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	node = unwrapParentheses(node)
+	switch node.Type() {
+	case "this":
+		if ctx.currentClass.Monitor != "" && len(ctx.instances) == 0 {
+			return &ast.SelectorExpr{X: &ast.Ident{Name: ReceiverName(ctx)}, Sel: &ast.Ident{Name: ctx.currentClass.Monitor}}
+		}
+	case "class_literal":
+		return classMonitor(stripJavaQualifier(node.NamedChild(0).Content(source)))
+	}
+
+	object := ParseExpr(node, source, ctx)
+	if javaType, ok := inferExprJavaType(node, ctx, source); ok {
+		className, _ := parseJavaTypeString(javaType)
+		if class := resolveClassScopeByName(ctx, className); class != nil && class.Monitor != "" {
+			return &ast.SelectorExpr{X: object, Sel: &ast.Ident{Name: class.Monitor}}
+		}
+	}
+	return runtimeCall("Monitor", object)
+}
+
+// classMonitor returns the mutex that the runtime keeps for a class
+func classMonitor(className string) ast.Expr {
+	return runtimeCall("ClassMonitor", &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(className)})
+}
+
+// lockMonitor locks a mutex, and defers unlocking it. A mutex that the runtime
+// keeps is only looked up once
+//
+// Ex: `monitor := stdjava.Monitor(lock)`, followed by `monitor.Lock()` and
+// `defer monitor.Unlock()`
+func lockMonitor(monitor ast.Expr, ctx Ctx) []ast.Stmt {
+	var stmts []ast.Stmt
+	if _, lookedUp := monitor.(*ast.CallExpr); lookedUp {
+		name := &ast.Ident{Name: localVariableNames(ctx).Allocate("monitor")}
+		stmts = append(stmts, &ast.AssignStmt{Lhs: []ast.Expr{name}, Tok: token.DEFINE, Rhs: []ast.Expr{monitor}})
+		monitor = name
+	}
+	return append(stmts,
+		&ast.ExprStmt{X: &ast.CallExpr{Fun: &ast.SelectorExpr{X: monitor, Sel: &ast.Ident{Name: "Lock"}}}},
+		&ast.DeferStmt{Call: &ast.CallExpr{Fun: &ast.SelectorExpr{X: monitor, Sel: &ast.Ident{Name: "Unlock"}}}},
+	)
+}
+
+// synchronizedMethod locks the mutex of a synchronized method's object, or of
+// its class if the method is static, before the body of the method
+func synchronizedMethod(node *sitter.Node, body *ast.BlockStmt, source []byte, ctx Ctx) {
+	var monitor ast.Expr
+	if ctx.localScope.IsStatic {
+		monitor = classMonitor(ctx.currentClass.Class.OriginalName)
+	} else {
+		monitor = &ast.SelectorExpr{X: &ast.Ident{Name: ReceiverName(ctx)}, Sel: &ast.Ident{Name: ctx.currentClass.Monitor}}
+	}
+	reportReentrantLocks(node.ChildByFieldName("body"), ctx.localScope.IsStatic, source, ctx)
+	body.List = append(lockMonitor(monitor, ctx), body.List...)
+}
+
+// synchronizedStmt translates a block that synchronizes on an object, which
+// holds the object's mutex until the block completes
+//
+// Ex: `synchronized (this) { count++; }` becomes
+// `func() { ct.mu.Lock(); defer ct.mu.Unlock(); count++ }()`
+func synchronizedStmt(node *sitter.Node, source []byte, ctx Ctx) []ast.Stmt {
+	// The errors of the block can't be returned out of its function literal
+	errorResult := returnsError(ctx)
+	if !endsMethod(node) {
+		ctx.errorTarget = nil
+	}
+	if lock := unwrapParentheses(node.NamedChild(0)); lock.Type() == "this" && len(ctx.instances) == 0 {
+		reportReentrantLocks(node.NamedChild(1), false, source, ctx)
+	}
+
+	body := lockMonitor(monitorOf(node.NamedChild(0), source, ctx), ctx)
+	body = append(body, ParseStmt(node.NamedChild(1), source, ctx).(*ast.BlockStmt).List...)
+	return runDeferred(node, body, errorResult, ctx)
+}
+
+// reportReentrantLocks reports the calls in a block that hold the mutex of the
+// current object, or of the current class if the block is static, that lock it
+// again, which deadlocks in Go
+func reportReentrantLocks(block *sitter.Node, static bool, source []byte, ctx Ctx) {
+	locks := func(method *symbol.Definition) bool {
+		return method.IsSynchronized && method.IsStatic == static
+	}
+
+	var visit func(node *sitter.Node)
+	visit = func(node *sitter.Node) {
+		switch node.Type() {
+		// Lambdas and classes may run after the mutex is unlocked
+		case "lambda_expression", "class_body":
+			return
+		case "method_invocation":
+			object := node.ChildByFieldName("object")
+			if object == nil || object.Type() == "this" && !static {
+				name := node.ChildByFieldName("name").Content(source)
+				for _, method := range ctx.currentClass.Methods {
+					if method.OriginalName == name && locks(method) {
+						ctx.diagnostics.Report(node, synchronizedDiagnostic, "`%s` locks the mutex that is already held, which deadlocks, since Go's mutexes aren't reentrant", node.Content(source))
+						break
+					}
+				}
+			}
+		case "synchronized_statement":
+			if lock := unwrapParentheses(node.NamedChild(0)); lock.Type() == "this" && !static {
+				ctx.diagnostics.Report(node, synchronizedDiagnostic, "synchronizing on `this` again deadlocks, since Go's mutexes aren't reentrant")
+			}
+		}
+		for _, child := range nodeutil.NamedChildrenOf(node) {
+			visit(child)
+		}
+	}
+	visit(block)
+}
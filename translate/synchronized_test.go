@@ -0,0 +1,60 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSynchronized(t *testing.T) {
+	helper := setupParseHelper(t, `
+package concurrency.sync;
+public class Account {
+    private int balance;
+    public synchronized void deposit(int amount) {
+        this.balance += amount;
+    }
+    public synchronized void depositTwice(int amount) {
+        deposit(amount);
+    }
+    public static synchronized void audit() {
+    }
+    public int withdraw(int amount, Object lock) {
+        synchronized (this) {
+            if (this.balance < amount) {
+                return 0;
+            }
+            this.balance -= amount;
+        }
+        synchronized (lock) {
+            this.balance++;
+        }
+        return amount;
+    }
+    public void transfer(Account other) {
+        synchronized (other) {
+            other.balance++;
+        }
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Account.java")
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helper))
+	for _, expected := range []string{
+		"type Account struct { balance int32 mu sync.Mutex }",
+		"func (at *Account) Deposit(amount int32) { at.mu.Lock() defer at.mu.Unlock() at.balance += amount }",
+		`func Audit() { monitor := stdjava.ClassMonitor("Account") monitor.Lock() defer monitor.Unlock() }`,
+		"_tryCompletion, _tryResult := func() (_completion int, _result int32) { at.mu.Lock() defer at.mu.Unlock() if at.balance < amount { return 1, 0 }",
+		"func() { monitor := stdjava.Monitor(lock) monitor.Lock() defer monitor.Unlock() at.balance++ }()",
+		"func (at *Account) Transfer(other *Account) { other.mu.Lock() defer other.mu.Unlock() other.balance++ }",
+	} {
+		if !strings.Contains(out, normalizeSpaces(expected)) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != synchronizedDiagnostic || items[0].Line != 9 {
+		t.Errorf("Expected the reentrant call on line 9 to be reported, got %v", items)
+	}
+}
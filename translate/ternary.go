@@ -0,0 +1,153 @@
+package translate
+
+import (
+	"go/ast"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for a ternary expression that evaluates both
+// of its branches
+const eagerTernaryDiagnostic = "eager-ternary"
+
+// ternary translates a ternary expression, which only evaluates the branch
+// that is selected by its condition
+//
+// When evaluating both branches can't do anything, such as for literals and
+// variables, the runtime's `Ternary` selects one of them. Otherwise, they are
+// evaluated in a function literal that is called in place, which has to know
+// the type of the result
+//
+// Ex: `x == null ? fallback : x.get()` becomes
+// `func() *Value { if x == nil { return fallback }; return x.get() }()`
+func ternary(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	condition := unboxedValue(node.ChildByFieldName("condition"), ParseExpr(node.ChildByFieldName("condition"), source, ctx), ctx, source)
+
+	// Nothing can be hoisted out of a branch, since it might not be evaluated
+	branchCtx := ctx.Clone()
+	branchCtx.hoisted = nil
+	consequence := ParseExpr(node.ChildByFieldName("consequence"), source, branchCtx)
+	alternative := ParseExpr(node.ChildByFieldName("alternative"), source, branchCtx)
+
+	if isEagerlyEvaluable(node.ChildByFieldName("consequence"), source) && isEagerlyEvaluable(node.ChildByFieldName("alternative"), source) {
+		return runtimeCall("Ternary", condition, consequence, alternative)
+	}
+
+	resultType := ternaryType(node, ctx, source)
+	if resultType == nil {
+		ctx.diagnostics.Report(node, eagerTernaryDiagnostic,
+			"`%s` evaluates both of its branches, because the type of its value isn't known", node.Content(source))
+		return runtimeCall("Ternary", condition, consequence, alternative)
+	}
+
+	return &ast.CallExpr{
+		Fun: &ast.FuncLit{
+			Type: &ast.FuncType{
+				Params:  &ast.FieldList{},
+				Results: &ast.FieldList{List: []*ast.Field{{Type: resultType}}},
+			},
+			Body: &ast.BlockStmt{List: []ast.Stmt{
+				&ast.IfStmt{
+					Cond: condition,
+					Body: &ast.BlockStmt{List: []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{consequence}}}},
+				},
+				&ast.ReturnStmt{Results: []ast.Expr{alternative}},
+			}},
+		},
+	}
+}
+
+// isEagerlyEvaluable determines if an expression can be evaluated without it
+// being selected, because it can't change anything, or panic
+func isEagerlyEvaluable(node *sitter.Node, source []byte) bool {
+	switch node.Type() {
+	case "decimal_integer_literal", "hex_integer_literal", "octal_integer_literal", "binary_integer_literal",
+		"decimal_floating_point_literal", "hex_floating_point_literal", "string_literal", "character_literal",
+		"true", "false", "null_literal", "this", "identifier":
+		return true
+	case "field_access":
+		return node.ChildByFieldName("object").Type() == "this"
+	case "parenthesized_expression":
+		return isEagerlyEvaluable(node.NamedChild(0), source)
+	case "unary_expression":
+		return isEagerlyEvaluable(node.ChildByFieldName("operand"), source)
+	case "binary_expression":
+		// Dividing an integer by zero panics
+		switch node.ChildByFieldName("operator").Type() {
+		case "/", "%":
+			return false
+		}
+		return isEagerlyEvaluable(node.ChildByFieldName("left"), source) && isEagerlyEvaluable(node.ChildByFieldName("right"), source)
+	}
+	return false
+}
+
+// ternaryType returns the Go type of the value of a ternary expression, from
+// the type of one of its branches, or from where its value goes, or nil if the
+// type isn't known
+func ternaryType(node *sitter.Node, ctx Ctx, source []byte) ast.Expr {
+	for _, branch := range []*sitter.Node{node.ChildByFieldName("consequence"), node.ChildByFieldName("alternative")} {
+		if javaType, ok := literalJavaType(branch, source); ok {
+			return javaTypeStringToGoTypeExpr(javaType, inScopeTypeParameters(ctx))
+		}
+		if javaType, ok := inferExprJavaType(branch, ctx, source); ok {
+			return javaTypeStringToGoTypeExpr(javaType, inScopeTypeParameters(ctx))
+		}
+	}
+
+	return destinationType(node, ctx)
+}
+
+// destinationType returns the Go type of where the value of an expression
+// goes, if it is declared as a local variable, or returned from a method, or
+// nil if the type isn't known
+func destinationType(node *sitter.Node, ctx Ctx) ast.Expr {
+	switch parent := node.Parent(); parent.Type() {
+	// Ex: `String name = ...`
+	case "variable_declarator":
+		if parent.Parent().Type() == "local_variable_declaration" && ctx.lastType != nil {
+			return ctx.lastType
+		}
+	// A ternary that is returned from a method has the type that the method
+	// returns, unless it is returned from a lambda inside of the method
+	case "return_statement":
+		for enclosing := parent.Parent(); enclosing != nil; enclosing = enclosing.Parent() {
+			switch enclosing.Type() {
+			case "lambda_expression":
+				return nil
+			case "method_declaration":
+				if ctx.localScope != nil && ctx.localScope.OriginalType != "" {
+					return javaTypeStringToGoTypeExpr(ctx.localScope.OriginalType, inScopeTypeParameters(ctx))
+				}
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// literalJavaType returns the Java type of a literal
+func literalJavaType(node *sitter.Node, source []byte) (string, bool) {
+	switch node.Type() {
+	case "string_literal":
+		return "String", true
+	case "character_literal":
+		return "char", true
+	case "true", "false":
+		return "boolean", true
+	case "decimal_integer_literal", "hex_integer_literal", "octal_integer_literal", "binary_integer_literal":
+		if strings.HasSuffix(strings.ToLower(node.Content(source)), "l") {
+			return "long", true
+		}
+		return "int", true
+	case "decimal_floating_point_literal", "hex_floating_point_literal":
+		if strings.HasSuffix(strings.ToLower(node.Content(source)), "f") {
+			return "float", true
+		}
+		return "double", true
+	case "parenthesized_expression":
+		return literalJavaType(node.NamedChild(0), source)
+	}
+	return "", false
+}
@@ -0,0 +1,42 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTernaryEvaluatesOneBranch(t *testing.T) {
+	helper := setupParseHelper(t, `
+package ternary.lazy;
+public class Lazy {
+    public static String run(Value x, String fallback, int n, Object unknown) {
+        String label = n > 2 ? "many" : "few";
+        String s = x == null ? fallback : x.get();
+        int k = n > 0 ? 10 / n : 0;
+        consume(n > 0 ? unknown.toString() : unknown.hashCode());
+        return x != null ? x.get() : fallback;
+    }
+    public static void consume(Object o) {}
+    public static class Value { public String get() { return ""; } }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Lazy.java")
+
+	out := renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		`label := stdjava.Ternary(n > 2, "many", "few")`,
+		"s := func() string {\n\t\tif x == nil {\n\t\t\treturn fallback\n\t\t}\n\t\treturn x.get()\n\t}()",
+		"k := func() int32 {\n\t\tif n > 0 {\n\t\t\treturn 10 / n\n\t\t}\n\t\treturn 0\n\t}()",
+		"return func() string {\n\t\tif x != nil {",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	// Only the ternary whose type isn't known evaluates both branches
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != eagerTernaryDiagnostic || items[0].Line != 8 {
+		t.Errorf("Expected the ternary on line 8 to be reported, got %v", items)
+	}
+}
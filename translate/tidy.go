@@ -0,0 +1,411 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+)
+
+// Tidy removes the code that `go vet` and other linters commonly report in the
+// generated code, without changing what the code does
+//
+// This removes local variables that are never used, along with assignments of
+// a variable to itself, and renames any `err` variable that shadows another,
+// as well as the imports that the removed code was the only use of
+func Tidy(file *ast.File) {
+	for _, decl := range file.Decls {
+		if function, ok := decl.(*ast.FuncDecl); ok && function.Body != nil {
+			tidyFunction(function)
+		}
+	}
+	importPackages(file)
+}
+
+func tidyFunction(function *ast.FuncDecl) {
+	// Removing a variable can leave the variables that it was set to unused
+	for changed := true; changed; {
+		uses := variableUses(function.Body)
+		changed = false
+		ast.Inspect(function.Body, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.BlockStmt:
+				node.List, changed = tidyStatements(node.List, uses, changed)
+			case *ast.CaseClause:
+				node.Body, changed = tidyStatements(node.Body, uses, changed)
+			case *ast.CommClause:
+				node.Body, changed = tidyStatements(node.Body, uses, changed)
+			case *ast.RangeStmt:
+				changed = tidyRangeVariables(node, uses) || changed
+			}
+			return true
+		})
+	}
+
+	renameShadowedErrors(function)
+}
+
+// variableUses counts the number of times that every name is used in the body
+// of a function, not counting where a variable is declared
+//
+// This only looks at names, so a variable is used if any variable with the
+// same name is used
+func variableUses(body *ast.BlockStmt) map[string]int {
+	declarations := make(map[*ast.Ident]bool)
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.AssignStmt:
+			if node.Tok == token.DEFINE {
+				for _, name := range node.Lhs {
+					if ident, ok := name.(*ast.Ident); ok {
+						declarations[ident] = true
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for _, name := range node.Names {
+				declarations[name] = true
+			}
+		case *ast.RangeStmt:
+			if node.Tok == token.DEFINE {
+				if key, ok := node.Key.(*ast.Ident); ok {
+					declarations[key] = true
+				}
+				if value, ok := node.Value.(*ast.Ident); ok {
+					declarations[value] = true
+				}
+			}
+		case *ast.SelectorExpr:
+			declarations[node.Sel] = true
+		}
+		return true
+	})
+
+	uses := make(map[string]int)
+	ast.Inspect(body, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok && !declarations[ident] {
+			uses[ident.Name]++
+		}
+		return true
+	})
+	return uses
+}
+
+// tidyStatements removes the declarations of unused variables, and the
+// assignments of variables to themselves, from a list of statements
+//
+// Whatever a removed declaration was set to is still evaluated, if it could
+// have any side effects
+func tidyStatements(statements []ast.Stmt, uses map[string]int, changed bool) ([]ast.Stmt, bool) {
+	tidied := make([]ast.Stmt, 0, len(statements))
+	for _, statement := range statements {
+		replacement, removed := tidyStatement(statement, uses)
+		if !removed {
+			tidied = append(tidied, statement)
+			continue
+		}
+		changed = true
+		if replacement != nil {
+			tidied = append(tidied, replacement)
+		}
+	}
+	return tidied, changed
+}
+
+// tidyStatement determines if a statement should be removed, and what it
+// should be replaced with, if anything
+func tidyStatement(statement ast.Stmt, uses map[string]int) (ast.Stmt, bool) {
+	switch statement := statement.(type) {
+	case *ast.AssignStmt:
+		switch statement.Tok {
+		case token.DEFINE:
+			for _, name := range statement.Lhs {
+				if ident, ok := name.(*ast.Ident); !ok || (ident.Name != "_" && uses[ident.Name] > 0) {
+					return nil, false
+				}
+			}
+			return discardValues(statement.Rhs), true
+		case token.ASSIGN:
+			// Ex: `x = x`
+			if len(statement.Lhs) == 1 && len(statement.Rhs) == 1 && isReference(statement.Rhs[0]) &&
+				types.ExprString(statement.Lhs[0]) == types.ExprString(statement.Rhs[0]) {
+				return nil, true
+			}
+		}
+	case *ast.DeclStmt:
+		declaration, ok := statement.Decl.(*ast.GenDecl)
+		if !ok || declaration.Tok != token.VAR {
+			return nil, false
+		}
+		var values []ast.Expr
+		for _, spec := range declaration.Specs {
+			spec := spec.(*ast.ValueSpec)
+			for _, name := range spec.Names {
+				if name.Name != "_" && uses[name.Name] > 0 {
+					return nil, false
+				}
+			}
+			values = append(values, spec.Values...)
+		}
+		return discardValues(values), true
+	}
+	return nil, false
+}
+
+// discardValues evaluates the values that an unused variable was set to, for
+// their side effects
+func discardValues(values []ast.Expr) ast.Stmt {
+	var effects []ast.Expr
+	for _, value := range values {
+		if !isPureExpr(value) {
+			effects = append(effects, value)
+		}
+	}
+	if len(effects) == 0 {
+		return nil
+	}
+	if len(effects) == 1 {
+		return expressionStatement(effects[0])
+	}
+	blanks := make([]ast.Expr, len(effects))
+	for index := range blanks {
+		blanks[index] = &ast.Ident{Name: "_"}
+	}
+	return &ast.AssignStmt{Lhs: blanks, Tok: token.ASSIGN, Rhs: effects}
+}
+
+// tidyRangeVariables removes the variables of a range loop that are unused
+//
+// Ex: `for i, v := range values` becomes `for i := range values` if `v` isn't used
+func tidyRangeVariables(loop *ast.RangeStmt, uses map[string]int) bool {
+	if loop.Tok != token.DEFINE {
+		return false
+	}
+	var changed bool
+	if value, ok := loop.Value.(*ast.Ident); ok && value.Name != "_" && uses[value.Name] == 0 {
+		loop.Value = nil
+		changed = true
+	}
+	if key, ok := loop.Key.(*ast.Ident); ok && key.Name != "_" && uses[key.Name] == 0 {
+		loop.Key = &ast.Ident{Name: "_"}
+		changed = true
+	}
+	if key, ok := loop.Key.(*ast.Ident); ok && key.Name == "_" && loop.Value == nil {
+		loop.Key = nil
+		loop.Tok = token.ILLEGAL
+	}
+	return changed
+}
+
+// isReference determines if an expression refers to a variable or a field
+func isReference(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return true
+	case *ast.SelectorExpr:
+		return isReference(expr.X)
+	}
+	return false
+}
+
+// isPureExpr determines if evaluating an expression can't have any effect,
+// including panicking
+func isPureExpr(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.Ident, *ast.BasicLit, *ast.FuncLit:
+		return true
+	case *ast.ParenExpr:
+		return isPureExpr(expr.X)
+	case *ast.SelectorExpr:
+		// Selecting a field of a nil pointer panics, but the variables from the
+		// standard library, such as `os.Args`, are always there
+		ident, ok := expr.X.(*ast.Ident)
+		return ok && ident.Name == "os"
+	case *ast.CallExpr:
+		// A conversion between numbers, such as `int64(x)`, can't panic
+		ident, ok := expr.Fun.(*ast.Ident)
+		return ok && numericConversions[ident.Name] && len(expr.Args) == 1 && isPureExpr(expr.Args[0])
+	case *ast.UnaryExpr:
+		return expr.Op != token.ARROW && isPureExpr(expr.X)
+	case *ast.BinaryExpr:
+		// Dividing by zero panics
+		return expr.Op != token.QUO && expr.Op != token.REM && isPureExpr(expr.X) && isPureExpr(expr.Y)
+	case *ast.CompositeLit:
+		for _, element := range expr.Elts {
+			if keyValue, ok := element.(*ast.KeyValueExpr); ok {
+				element = keyValue.Value
+			}
+			if !isPureExpr(element) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// numericConversions are the types of numbers that a value can be converted to
+var numericConversions = map[string]bool{
+	"byte": true, "float32": true, "float64": true, "int": true, "int8": true, "int16": true, "int32": true,
+	"int64": true, "rune": true, "uint": true, "uint16": true, "uint32": true, "uint64": true,
+}
+
+// renameShadowedErrors renames every `err` variable that is declared in a
+// nested scope, while an `err` from an outer scope is visible, which is
+// commonly reported by linters
+//
+// Ex: `err := check()` in the body of an `if` becomes `err2 := check()`
+func renameShadowedErrors(function *ast.FuncDecl) {
+	// Every name in the function is taken, so the new names can't shadow anything
+	taken := make(map[string]bool)
+	ast.Inspect(function, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok {
+			taken[ident.Name] = true
+		}
+		return true
+	})
+
+	outer := make(map[string]bool)
+	for _, fields := range []*ast.FieldList{function.Recv, function.Type.Params, function.Type.Results} {
+		if fields == nil {
+			continue
+		}
+		for _, field := range fields.List {
+			for _, name := range field.Names {
+				outer[name.Name] = true
+			}
+		}
+	}
+	shadowedErrors{taken: taken}.block(function.Body.List, outer)
+}
+
+type shadowedErrors struct {
+	taken map[string]bool
+}
+
+// block renames the shadowing `err` variables in a list of statements, given
+// the names that are declared in the scopes around it
+func (se shadowedErrors) block(statements []ast.Stmt, outer map[string]bool) {
+	scope := make(map[string]bool)
+	for index, statement := range statements {
+		se.nested(statement, union(outer, scope))
+
+		assignment, ok := statement.(*ast.AssignStmt)
+		if !ok || assignment.Tok != token.DEFINE {
+			continue
+		}
+		for _, name := range assignment.Lhs {
+			ident, ok := name.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			if ident.Name == "err" && outer["err"] && !scope["err"] {
+				renamed := "err2"
+				for i := 3; se.taken[renamed]; i++ {
+					renamed = "err" + strconv.Itoa(i)
+				}
+				se.taken[renamed] = true
+
+				// Everything after the declaration refers to the new variable
+				ident.Name = renamed
+				for _, after := range statements[index+1:] {
+					renameIdent(after, "err", renamed)
+				}
+				scope[renamed] = true
+				continue
+			}
+			scope[ident.Name] = true
+		}
+	}
+}
+
+// nested checks the scopes that are nested in a statement
+func (se shadowedErrors) nested(statement ast.Stmt, outer map[string]bool) {
+	switch statement := statement.(type) {
+	case *ast.BlockStmt:
+		se.block(statement.List, outer)
+	case *ast.LabeledStmt:
+		se.nested(statement.Stmt, outer)
+	case *ast.IfStmt:
+		se.withInit(statement.Init, []ast.Stmt{statement.Body, statement.Else}, outer)
+	case *ast.ForStmt:
+		se.withInit(statement.Init, []ast.Stmt{statement.Body}, outer)
+	case *ast.RangeStmt:
+		scope := make(map[string]bool)
+		if statement.Tok == token.DEFINE {
+			for _, variable := range []ast.Expr{statement.Key, statement.Value} {
+				if ident, ok := variable.(*ast.Ident); ok {
+					scope[ident.Name] = true
+				}
+			}
+		}
+		se.nested(statement.Body, union(outer, scope))
+	case *ast.SwitchStmt:
+		se.withInit(statement.Init, []ast.Stmt{statement.Body}, outer)
+	case *ast.TypeSwitchStmt:
+		se.withInit(statement.Init, []ast.Stmt{statement.Body}, outer)
+	case *ast.CaseClause:
+		se.block(statement.Body, outer)
+	case *ast.CommClause:
+		se.block(statement.Body, outer)
+	default:
+		// Function literals see the variables of the scope that they are in
+		ast.Inspect(statement, func(node ast.Node) bool {
+			if literal, ok := node.(*ast.FuncLit); ok {
+				se.block(literal.Body.List, outer)
+				return false
+			}
+			return true
+		})
+	}
+}
+
+// withInit checks the statements that are in the scope of an initializer, such
+// as the body of an `if` statement
+func (se shadowedErrors) withInit(init ast.Stmt, statements []ast.Stmt, outer map[string]bool) {
+	if init != nil {
+		se.block([]ast.Stmt{init}, outer)
+		if assignment, ok := init.(*ast.AssignStmt); ok && assignment.Tok == token.DEFINE {
+			scope := make(map[string]bool)
+			for _, name := range assignment.Lhs {
+				if ident, ok := name.(*ast.Ident); ok {
+					scope[ident.Name] = true
+				}
+			}
+			outer = union(outer, scope)
+		}
+	}
+	for _, statement := range statements {
+		if statement != nil {
+			se.nested(statement, outer)
+		}
+	}
+}
+
+// renameIdent renames every identifier with the given name in a node, other
+// than the names of fields and methods
+func renameIdent(node ast.Node, from, to string) {
+	selected := make(map[*ast.Ident]bool)
+	ast.Inspect(node, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			selected[node.Sel] = true
+		case *ast.Ident:
+			if node.Name == from && !selected[node] {
+				node.Name = to
+			}
+		}
+		return true
+	})
+}
+
+func union(first, second map[string]bool) map[string]bool {
+	combined := make(map[string]bool, len(first)+len(second))
+	for name := range first {
+		combined[name] = true
+	}
+	for name := range second {
+		combined[name] = true
+	}
+	return combined
+}
@@ -0,0 +1,82 @@
+package translate
+
+import (
+	"bytes"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func tidySource(t *testing.T, src string) string {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse the source: %v", err)
+	}
+	Tidy(file)
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), file); err != nil {
+		t.Fatalf("Failed to print the source: %v", err)
+	}
+	return normalizeSpaces(buf.String())
+}
+
+func TestTidy(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			"UnusedVariables",
+			`func f() int32 { args := os.Args; unused := compute(); alsoUnused := 5; used := 2; chain := used; var zero int32; return 1 }`,
+			`func f() int32 { compute(); return 1 }`,
+		},
+		{
+			"UnusedVariableWithEffects",
+			`func f(xs []int32) { first, last := xs[0], xs[len(xs)-1] }`,
+			`func f(xs []int32) { _, _ = xs[0], xs[len(xs)-1] }`,
+		},
+		{
+			"UnusedVariableWithoutStatement",
+			`func f(x int32, xs []int32) { y := int64(x); z := len(xs); w := xs[0] }`,
+			`func f(x int32, xs []int32) { _ = len(xs); _ = xs[0] }`,
+		},
+		{
+			"SelfAssignments",
+			`func (b *Box) f(count int32) { count = count; b.size = b.size; b.size = count }`,
+			`func (b *Box) f(count int32) { b.size = count }`,
+		},
+		{
+			"UnusedRangeVariables",
+			`func f(xs []int32) int32 { total := 0; for i, x := range xs { total++ }; for j, y := range xs { total += j }; return total }`,
+			`func f(xs []int32) int32 { total := 0; for range xs { total++ }; for j := range xs { total += j }; return total }`,
+		},
+		{
+			"ShadowedErrors",
+			`func f(err error) error { if err != nil { err := wrap(err); return err }; return err }`,
+			`func f(err error) error { if err != nil { err2 := wrap(err); return err2 }; return err }`,
+		},
+		{
+			"RedeclaredErrorInSameScope",
+			`func f() error { a, err := g(); b, err := h(a); use(b); return err }`,
+			`func f() error { a, err := g(); b, err := h(a); use(b); return err }`,
+		},
+		{
+			"UnusedImports",
+			`import ("os"; "reflect"); func f() reflect.Type { args := os.Args; return reflect.TypeOf(1) }`,
+			`import "reflect"; func f() reflect.Type { return reflect.TypeOf(1) }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tidySource(t, "package tidy\n"+tt.src)
+			if want := normalizeSpaces("package tidy\n" + strings.ReplaceAll(tt.want, ";", "")); got != want {
+				t.Errorf("Tidy() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Package translate translates the tree-sitter AST of Java source files into
+// Go's AST, which is then printed as the generated Go code
+package translate
+
+import (
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"io"
+
+	"github.com/NickyBoy89/java2go/parsing"
+)
+
+// Result is a translated file, along with everything that was reported about
+// it while it was being translated
+type Result struct {
+	// File is the generated Go code
+	File *ast.File
+	// Diagnostics are the places that might behave differently in the
+	// generated code
+	Diagnostics *Diagnostics
+	// Fallbacks are the places where the types fell back to `any`, when
+	// ReportFallbacks is set
+	Fallbacks *Diagnostics
+	// Helpers and Methods are the number of helper types that were generated
+	// for generic methods, and the number of methods on them
+	Helpers, Methods int
+	// Invalid is the number of nodes that can't be printed as valid Go, which
+	// is only checked with StrictOutput
+	Invalid int
+
+	emitter *declEmitter
+}
+
+// File translates a parsed Java file into Go, keeping the positions of the
+// generated nodes in the given set of positions
+//
+// With symbols, the file must have been resolved with ResolveFile first
+func File(file parsing.SourceFile, positions *token.FileSet) (*Result, error) {
+	sources := newSourceMap(positions, file.Name, file.Source)
+	diagnostics := NewDiagnostics(file.Name)
+	diagnostics.sources = sources
+	if NullAnalysis {
+		CheckNullDereferences(file.Ast, file.Source, diagnostics)
+	}
+
+	initialContext := Ctx{diagnostics: diagnostics, helpers: newHelperRegistry(), sources: sources}
+	if ReportFallbacks {
+		initialContext.fallbacks = NewDiagnostics(file.Name)
+		reportWildcards(file.Ast, file.Source, initialContext.fallbacks)
+	}
+	if file.Symbols != nil {
+		initialContext.currentFile = file.Symbols
+		initialContext.currentClass = file.Symbols.BaseClass
+	}
+	if ChunkedOutput {
+		var strict *Diagnostics
+		if StrictOutput {
+			strict = diagnostics
+		}
+		emitter, err := newDeclEmitter(TidyOutput, strict)
+		if err != nil {
+			return nil, err
+		}
+		initialContext.emitter = emitter
+	}
+
+	parsed := ParseNode(file.Ast, file.Source, initialContext).(*ast.File)
+	if TidyOutput {
+		Tidy(parsed)
+	}
+	if SimplifyOutput {
+		Simplify(parsed)
+	}
+
+	result := &Result{
+		File:        parsed,
+		Diagnostics: diagnostics,
+		Fallbacks:   initialContext.fallbacks,
+		Helpers:     initialContext.helpers.Helpers(),
+		Methods:     initialContext.helpers.Methods(),
+		emitter:     initialContext.emitter,
+	}
+	if StrictOutput {
+		result.Invalid = validateOutput(parsed, diagnostics)
+		if result.emitter != nil {
+			result.Invalid += result.emitter.invalid
+		}
+	}
+	return result, nil
+}
+
+// Print prints the generated code, after the declarations that were written
+// out already in the chunked mode
+func (r *Result) Print(w io.Writer) error {
+	clearPositions(r.File)
+	if r.emitter != nil {
+		return r.emitter.finish(w, r.File.Name.Name)
+	}
+	return printer.Fprint(w, token.NewFileSet(), r.File)
+}
+
+// Discard throws away the declarations that were written out already in the
+// chunked mode, for a file that won't be printed
+func (r *Result) Discard() {
+	if r.emitter != nil {
+		r.emitter.discard()
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package translate
 
 import (
 	"fmt"
@@ -44,6 +44,12 @@ type Ctx struct {
 	// The symbols of the current
 	localScope *symbol.Definition
 
+	// The names of the variables of the function that is being parsed, which
+	// every variable that is generated in the function is named from, so that
+	// two generated variables never collide, or shadow one of the function's
+	// own variables
+	localNames *symbol.NameAllocator
+
 	// Used when generating arrays, because in Java, these are defined as
 	// arrType[] varName = {item, item, item}, and no class name data is defined
 	// Can either be of type `*ast.Ident` or `*ast.StarExpr`
@@ -51,6 +57,53 @@ type Ctx struct {
 
 	// Expected type from variable declaration, used for diamond operator inference
 	expectedType string
+
+	// Collects anything in the file that might behave differently once converted
+	diagnostics *Diagnostics
+
+	// Collects the places where the types fall back to `any`, or nil if they
+	// aren't reported
+	fallbacks *Diagnostics
+
+	// Package-level declarations that are generated while parsing the body of
+	// a method, such as the types of anonymous classes, which are added to the
+	// end of the file
+	synthesized *[]ast.Decl
+
+	// The variable that holds the object being created, inside of a double
+	// brace initializer, which unqualified method calls are made on
+	initializedObject string
+	// The Java type of the object being created inside of a double brace
+	// initializer
+	initializedType string
+
+	// The objects that `this` can refer to, from the innermost one outwards,
+	// inside of anonymous classes. When this is empty, `this` is the receiver
+	// of the current method
+	instances []enclosingInstance
+
+	// The statements that are hoisted out of the condition of the statement
+	// that is being parsed, or nil if nothing can be hoisted out of it
+	hoisted *hoistedStatements
+
+	// Where the errors of the methods that are called are returned to, in the
+	// errors mode, or nil if they panic instead
+	errorTarget *errorTarget
+
+	// The entries of the maps that are being ranged over, by the names of their
+	// variables, whose keys and values are the variables of the range statement
+	rangedEntries map[string]*rangedEntry
+
+	// The helper types of generic methods that are declared in the file
+	helpers *helperRegistry
+
+	// Writes out the declarations of the file as they are generated, in the
+	// chunked mode, or nil if the whole file is generated first
+	emitter *declEmitter
+
+	// Maps the Java nodes to the positions of the generated nodes, or nil if the
+	// generated nodes aren't given positions
+	sources *sourceMap
 }
 
 // Clone performs a shallow copy on a `Ctx`, returning a new Ctx with its pointers
@@ -61,8 +114,22 @@ func (c Ctx) Clone() Ctx {
 		currentFile:  c.currentFile,
 		currentClass: c.currentClass,
 		localScope:   c.localScope,
+		localNames:   c.localNames,
 		lastType:     c.lastType,
 		expectedType: c.expectedType,
+		diagnostics:  c.diagnostics,
+		fallbacks:    c.fallbacks,
+		synthesized:  c.synthesized,
+
+		initializedObject: c.initializedObject,
+		initializedType:   c.initializedType,
+		instances:         c.instances,
+		hoisted:           c.hoisted,
+		errorTarget:       c.errorTarget,
+		rangedEntries:     c.rangedEntries,
+		helpers:           c.helpers,
+		emitter:           c.emitter,
+		sources:           c.sources,
 	}
 }
 
@@ -84,17 +151,26 @@ func ParseNode(node *sitter.Node, source []byte, ctx Ctx) interface{} {
 		program := &ast.File{
 			Name: &ast.Ident{Name: "main"},
 		}
+		ctx.synthesized = &[]ast.Decl{}
+		if ctx.helpers == nil {
+			ctx.helpers = newHelperRegistry()
+		}
 
 		for _, c := range nodeutil.NamedChildrenOf(node) {
 			switch c.Type() {
 			case "package_declaration":
 				program.Name = &ast.Ident{Name: c.NamedChild(0).NamedChild(int(c.NamedChild(0).NamedChildCount()) - 1).Content(source)}
-			case "class_declaration", "interface_declaration", "enum_declaration":
-				program.Decls = ParseDecls(c, source, ctx)
+			case "class_declaration", "interface_declaration", "enum_declaration", "record_declaration":
+				program.Decls = emitDecls(ctx, ParseDecls(c, source, ctx))
 			case "import_declaration":
 				program.Imports = append(program.Imports, ParseNode(c, source, ctx).(*ast.ImportSpec))
 			}
 		}
+		program.Decls = append(program.Decls, emitDecls(ctx, *ctx.synthesized)...)
+		if LineDirectives {
+			addLineDirectives(program.Decls, ctx.sources)
+		}
+		importPackages(program)
 		return program
 	case "field_declaration":
 		var public bool
@@ -141,7 +217,7 @@ func ParseNode(node *sitter.Node, source []byte, ctx Ctx) interface{} {
 				switch modifier.Type() {
 				case "marker_annotation", "annotation":
 					comments = append(comments, &ast.Comment{Text: "//" + modifier.Content(source)})
-					if _, in := excludedAnnotations[modifier.Content(source)]; in {
+					if _, in := ExcludedAnnotations[modifier.Content(source)]; in {
 						// If this entire method is ignored, we return an empty field, which
 						// is handled by the logic that parses a class file
 						return &ast.Field{}
@@ -166,12 +242,7 @@ func ParseNode(node *sitter.Node, source []byte, ctx Ctx) interface{} {
 
 			// Go through the types and check to see if they differ
 			for index, param := range nodeutil.NamedChildrenOf(methodParameters) {
-				var paramType string
-				if param.Type() == "spread_parameter" {
-					paramType = param.NamedChild(0).Content(source)
-				} else {
-					paramType = param.ChildByFieldName("type").Content(source)
-				}
+				paramType := nodeutil.ParameterType(param).Content(source)
 				if paramType != d.Parameters[index].OriginalType {
 					return false
 				}
@@ -181,7 +252,7 @@ func ParseNode(node *sitter.Node, source []byte, ctx Ctx) interface{} {
 		}
 
 		def := ctx.currentClass.FindMethod().By(comparison)[0]
-		ctx.localScope = def
+		ctx.enterFunction(def)
 
 		parameters := &ast.FieldList{}
 		for _, param := range nodeutil.NamedChildrenOf(methodParameters) {
@@ -193,39 +264,48 @@ func ParseNode(node *sitter.Node, source []byte, ctx Ctx) interface{} {
 			Names: []*ast.Ident{&ast.Ident{Name: def.Name}},
 			Type: &ast.FuncType{
 				Params: parameters,
-				Results: &ast.FieldList{List: []*ast.Field{
+				Results: errorResults(&ast.FieldList{List: []*ast.Field{
 					&ast.Field{
 						Type: &ast.Ident{Name: def.Type},
 					},
 				},
-				},
+				}, def),
 			},
 		}
 	case "try_with_resources_statement":
-		// Ignore try with resources statements as well
-		// NOTE: This will also ignore the catch clause
-		stmts := []ast.Stmt{ParseStmt(node.NamedChild(0), source, ctx)}
-		return append(stmts, ParseStmt(node.NamedChild(1), source, ctx).(*ast.BlockStmt).List...)
+		return tryWithResources(node, source, ctx)
 	case "try_statement":
-		// We ignore try statements
-		return ParseStmt(node.NamedChild(0), source, ctx).(*ast.BlockStmt).List
+		// A finally block that returns, breaks, or continues has to be lowered
+		// into explicit control flow to keep the Java semantics
+		if finally := finallyClauseOf(node); finally != nil && completesAbruptly(finally, source) {
+			return []ast.Stmt{lowerAbruptFinally(node, finally, source, ctx)}
+		}
+
+		parseBody := func(ctx Ctx) []ast.Stmt {
+			return ParseStmt(node.ChildByFieldName("body"), source, ctx).(*ast.BlockStmt).List
+		}
+		parseRest := parseBody
+		if clauses := catchClausesOf(node); len(clauses) > 0 {
+			parseRest = func(ctx Ctx) []ast.Stmt {
+				return []ast.Stmt{tryCatch(node, parseBody, clauses, source, ctx)}
+			}
+		}
+		// Any other finally block is deferred
+		if finally := finallyClauseOf(node); finally != nil {
+			return deferFinally(node, finally, parseRest, source, ctx)
+		}
+		return parseRest(ctx)
 	case "synchronized_statement":
 		// A synchronized statement contains the variable to be synchronized, as
 		// well as the block
-
-		// Ignore the sychronized statement
-		return ParseStmt(node.NamedChild(1), source, ctx).(*ast.BlockStmt).List
+		return synchronizedStmt(node, source, ctx)
 	case "switch_label":
-		if node.NamedChildCount() > 0 {
-			return &ast.CaseClause{
-				List: []ast.Expr{ParseExpr(node.NamedChild(0), source, ctx)},
-			}
-		}
-		return &ast.CaseClause{}
+		// The default case has no values
+		return &ast.CaseClause{List: switchLabelExprs(node, source, ctx)}
 	case "argument_list":
 		args := []ast.Expr{}
 		for _, c := range nodeutil.NamedChildrenOf(node) {
-			args = append(args, ParseExpr(c, source, ctx))
+			args = append(args, adaptArgument(c, ParseExpr(c, source, ctx), source, ctx))
 		}
 		return args
 
@@ -261,8 +341,7 @@ func ParseNode(node *sitter.Node, source []byte, ctx Ctx) interface{} {
 		// The spread paramater takes a list and separates it into multiple elements
 		// Ex: addElements([]int elements...)
 
-		spreadType := node.NamedChild(0)
-		spreadDeclarator := node.NamedChild(1)
+		spreadType := nodeutil.ParameterType(node)
 
 		var typeParams []string
 		if ctx.currentClass != nil {
@@ -270,7 +349,7 @@ func ParseNode(node *sitter.Node, source []byte, ctx Ctx) interface{} {
 		}
 
 		return &ast.Field{
-			Names: []*ast.Ident{ParseExpr(spreadDeclarator.ChildByFieldName("name"), source, ctx).(*ast.Ident)},
+			Names: []*ast.Ident{ParseExpr(nodeutil.ParameterName(node), source, ctx).(*ast.Ident)},
 			Type: &ast.Ellipsis{
 				Elt: astutil.ParseTypeWithTypeParams(spreadType, source, typeParams),
 			},
@@ -1,4 +1,4 @@
-package main
+package translate
 
 import (
 	"bytes"
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/NickyBoy89/java2go/astutil"
 	"github.com/NickyBoy89/java2go/parsing"
 	"github.com/NickyBoy89/java2go/symbol"
 	sitter "github.com/smacker/go-tree-sitter"
@@ -36,6 +37,7 @@ func TestCtxClone(t *testing.T) {
 		className:    "TestClass",
 		expectedType: "int",
 		currentFile:  &symbol.FileScope{},
+		diagnostics:  NewDiagnostics("Test.java"),
 	}
 
 	clone := original.Clone()
@@ -49,6 +51,9 @@ func TestCtxClone(t *testing.T) {
 	if clone.currentFile != original.currentFile {
 		t.Error("Expected currentFile pointers to be identical")
 	}
+	if clone.diagnostics != original.diagnostics {
+		t.Error("Expected diagnostics pointers to be identical")
+	}
 
 	clone.className = "NewClass"
 	if original.className == "NewClass" {
@@ -73,6 +78,7 @@ func setupParseHelper(t *testing.T, source string) *ParseHelper {
 		t.Fatalf("Failed to parse AST: %v", err)
 	}
 
+	astutil.FindHashedClasses(file.Ast, file.Source)
 	symbols := file.ParseSymbols()
 	symbol.AddSymbolsToPackage(symbols)
 
@@ -152,9 +158,10 @@ public interface TestInterface {
 		t.Fatalf("Expected *ast.Field, got %T", res)
 	}
 
-	// Interface methods are not automatically capitalized in the current implementation
-	if len(field.Names) != 1 || field.Names[0].Name != "myMethod" {
-		t.Errorf("Expected method name 'myMethod', got %v", field.Names)
+	// Interface methods are implicitly public, so they are exported to match
+	// the public methods that implement them
+	if len(field.Names) != 1 || field.Names[0].Name != "MyMethod" {
+		t.Errorf("Expected method name 'MyMethod', got %v", field.Names)
 	}
 }
 
@@ -553,7 +560,7 @@ public class Box<T> {
 		output := buf.String()
 
 		// Explicit type args should be preserved
-		if !strings.Contains(output, "[Integer]") && !strings.Contains(output, "[*Integer]") && !strings.Contains(output, "[int]") {
+		if !strings.Contains(output, "[Integer]") && !strings.Contains(output, "[*int32]") && !strings.Contains(output, "[int]") {
 			t.Errorf("Explicit type arguments should be preserved, got:\n%s", output)
 		}
 	})
@@ -590,7 +597,7 @@ public class Pair<K, V> {
 		}
 		output := buf.String()
 
-		if !strings.Contains(output, "NewPair[String, Integer]") && !strings.Contains(output, "NewPair[string, *Integer]") && !strings.Contains(output, "NewPair[string,*Integer]") {
+		if !strings.Contains(output, "NewPair[String, Integer]") && !strings.Contains(output, "NewPair[string, *int32]") && !strings.Contains(output, "NewPair[string,*int32]") {
 			t.Errorf("Diamond operator should infer multiple type arguments, got:\n%s", output)
 		}
 	})
@@ -764,6 +771,34 @@ public class Box<T> {
 	}
 }
 
+func TestInstanceGenericMethodFinalIsFunction(t *testing.T) {
+	src := `
+package com.example.finals;
+public final class Crate<T> {
+    public <R> R identity(R value) {
+        return value;
+    }
+
+    public static <X> X callIdentity(Crate<X> crate, X value) {
+        return crate.identity(value);
+    }
+}
+`
+	out := normalizeSpaces(renderGoFileFromJava(t, src))
+
+	if strings.Contains(out, "Helper") {
+		t.Errorf("Expected no helper for a method that can't be overridden, got:\n%s", out)
+	}
+	for _, want := range []string{
+		"func CrateIdentity[T any, R any](ce *Crate[T], value R) R",
+		"return CrateIdentity[X, X](crate, value)",
+	} {
+		if !strings.Contains(out, normalizeSpaces(want)) {
+			t.Errorf("Expected %q, got:\n%s", want, out)
+		}
+	}
+}
+
 func TestParseExpr_InstanceGenericMethodInvocationUsesHelper(t *testing.T) {
 	src := `
 package com.example;
@@ -1012,3 +1047,39 @@ public class Utils<T> {
 		t.Errorf("Expected ellipsis element name 'T', got '%s'", elt.Name)
 	}
 }
+
+func TestParameters_FinalAndAnnotated(t *testing.T) {
+	src := `
+package com.example.modifiers;
+public class Summer {
+    public Summer(final int start, @Deprecated final String... labels) {
+    }
+
+    public int sum(final int a, @SuppressWarnings("unused") final int b, final @Deprecated int... more) {
+        return a + b;
+    }
+}
+`
+	helper := setupParseHelper(t, src)
+
+	sum := helper.Ctx.currentClass.FindMethod().ByName("Sum")
+	if len(sum) == 0 {
+		t.Fatal("Could not find Sum method")
+	}
+	if got := len(sum[0].Parameters); got != 3 {
+		t.Fatalf("Expected 3 parameters, got %d", got)
+	}
+	if more := sum[0].Parameters[2]; more.Name != "more" || more.OriginalType != "int" {
+		t.Errorf("Expected the annotated spread parameter to be `int more`, got `%s %s`", more.OriginalType, more.Name)
+	}
+
+	out := normalizeSpaces(renderGoFileFromJavaCtx(t, helper))
+	for _, want := range []string{
+		"func NewSummer(start int32, labels ...string) *Summer",
+		"func (sr *Summer) Sum(a int32, b int32, more ...int32) int32",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q, got:\n%s", want, out)
+		}
+	}
+}
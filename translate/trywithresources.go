@@ -0,0 +1,147 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/NickyBoy89/java2go/nodeutil"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// tryWithResources translates a try-with-resources statement, which acquires
+// each resource, and defers closing it, so that the resources are closed in
+// the reverse order that they were acquired in, however the body completes
+//
+// A deferred call only runs when the function that it is in returns, so the
+// body is run in a function literal that is called in place, unless nothing
+// runs after the statement in the method anyways. A body that returns, breaks,
+// or continues out of the statement is lowered the same way as a finally block
+// that does, by returning how it completed from the function literal. The
+// resources are closed before any of the catch clauses run
+//
+// Java suppresses an exception thrown while closing a resource, if the body
+// already threw one, and adds it to the suppressed exceptions of the original
+// one. Go has no suppressed panics: a panic in `Close` while the body is
+// panicking is printed after the original panic, and replaces it as the value
+// that `recover` returns
+//
+// Ex: `try (Reader r = open()) { ... }` becomes
+// `func() { r := open(); defer r.Close(); ... }()`
+func tryWithResources(node *sitter.Node, source []byte, ctx Ctx) []ast.Stmt {
+	parseRest := func(ctx Ctx) []ast.Stmt {
+		return closeResources(node, source, ctx)
+	}
+	if clauses := catchClausesOf(node); len(clauses) > 0 {
+		parseRest = func(ctx Ctx) []ast.Stmt {
+			return []ast.Stmt{tryCatch(node, func(ctx Ctx) []ast.Stmt {
+				return closeResources(node, source, ctx)
+			}, clauses, source, ctx)}
+		}
+	}
+	// The finally block runs after the resources are closed, and after the
+	// catch clauses
+	if finally := finallyClauseOf(node); finally != nil && !completesAbruptly(finally, source) {
+		return deferFinally(node, finally, parseRest, source, ctx)
+	}
+	return parseRest(ctx)
+}
+
+// closeResources translates a try-with-resources statement without its catch
+// clauses
+func closeResources(node *sitter.Node, source []byte, ctx Ctx) []ast.Stmt {
+	// The errors of the body can't be returned out of its function literal
+	errorResult := returnsError(ctx)
+	if !endsMethod(node) {
+		ctx.errorTarget = nil
+	}
+
+	var acquired []ast.Stmt
+	for _, resource := range nodeutil.NamedChildrenOf(node.ChildByFieldName("resources")) {
+		// Ex: `Reader r = open()`, or a variable that already holds the
+		// resource, such as `r`, or `this.reader`
+		resourceExpr := resource.NamedChild(0)
+		if name := resource.ChildByFieldName("name"); name != nil {
+			acquired = append(acquired, ParseStmt(resource, source, ctx))
+			resourceExpr = name
+		}
+		acquired = append(acquired, &ast.DeferStmt{
+			Call: &ast.CallExpr{Fun: &ast.SelectorExpr{X: ParseExpr(resourceExpr, source, ctx), Sel: &ast.Ident{Name: "Close"}}},
+		})
+	}
+	body := append(acquired, ParseStmt(node.ChildByFieldName("body"), source, ctx).(*ast.BlockStmt).List...)
+	return runDeferred(node, body, errorResult, ctx)
+}
+
+// runDeferred runs the statements that a try statement was translated into,
+// which defer the calls that have to run when the statement completes, in a
+// function literal that is called in place, unless nothing runs after the
+// statement in the method anyways
+//
+// Statements that return, break, or continue out of the try statement are
+// rewritten to return how they completed from the function literal, and the
+// statement then completes the same way after it is called
+func runDeferred(node *sitter.Node, body []ast.Stmt, errorResult bool, ctx Ctx) []ast.Stmt {
+	if endsMethod(node) {
+		return body
+	}
+
+	resultType := methodResultType(ctx)
+	rewriter := &completionRewriter{hasResult: resultType != nil, returnsError: errorResult}
+	rewriter.rewriteList(body, false, false, map[string]bool{})
+
+	if !rewriter.returns && len(rewriter.branches) == 0 {
+		return []ast.Stmt{&ast.ExprStmt{X: &ast.CallExpr{
+			Fun: &ast.FuncLit{Type: &ast.FuncType{Params: &ast.FieldList{}}, Body: &ast.BlockStmt{List: body}},
+		}}}
+	}
+	if len(body) == 0 || !isTerminating(body[len(body)-1]) {
+		body = append(body, &ast.ReturnStmt{})
+	}
+
+	results := &ast.FieldList{List: []*ast.Field{{Names: []*ast.Ident{{Name: "_completion"}}, Type: &ast.Ident{Name: "int"}}}}
+	captured := []ast.Expr{&ast.Ident{Name: "_tryCompletion"}}
+	if resultType != nil {
+		results.List = append(results.List, &ast.Field{Names: []*ast.Ident{{Name: "_result"}}, Type: resultType})
+		// The result is only used if the body returns
+		if rewriter.returns {
+			captured = append(captured, &ast.Ident{Name: "_tryResult"})
+		} else {
+			captured = append(captured, &ast.Ident{Name: "_"})
+		}
+	}
+
+	lowered := &ast.BlockStmt{List: []ast.Stmt{
+		&ast.AssignStmt{
+			Lhs: captured,
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{&ast.CallExpr{
+				Fun: &ast.FuncLit{
+					Type: &ast.FuncType{Params: &ast.FieldList{}, Results: results},
+					Body: &ast.BlockStmt{List: body},
+				},
+			}},
+		},
+	}}
+	lowered.List = append(lowered.List, rewriter.completionChecks()...)
+	return []ast.Stmt{lowered}
+}
+
+// endsMethod determines if a statement is the last statement in the body of a
+// method or a constructor, so that nothing in the method runs after it
+func endsMethod(node *sitter.Node) bool {
+	parent := node.Parent()
+	if parent == nil || parent.Type() != "block" && parent.Type() != "constructor_body" {
+		return false
+	}
+	if parent.Type() == "block" && (parent.Parent() == nil || parent.Parent().Type() != "method_declaration") {
+		return false
+	}
+	for next := node.NextNamedSibling(); next != nil; next = next.NextNamedSibling() {
+		switch next.Type() {
+		case "comment", "line_comment", "block_comment":
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTryWithResources(t *testing.T) {
+	src := `
+package resources.closing;
+public class Closing {
+    public static String copy(String from, String to) throws Exception {
+        try (Reader in = open(from); Writer out = create(to)) {
+            out.write(in.read());
+        }
+        try (Reader in = open(from)) {
+            if (in.ready()) {
+                return "ready";
+            }
+        }
+        try (Reader in = open(to)) {
+            return in.read();
+        }
+    }
+}
+`
+	out := renderGoFileFromJava(t, src)
+	for _, expected := range []string{
+		// Closed in the reverse order that they were opened, at the end of the statement
+		"func() {\n\t\tin := open(from)\n\t\tdefer in.Close()\n\t\tout := create(to)\n\t\tdefer out.Close()\n\t\tout.write(in.read())\n\t}()",
+		// Returning out of the statement returns from the method
+		"_tryCompletion, _tryResult := func() (_completion int, _result string) {\n\t\t\tin := open(from)\n\t\t\tdefer in.Close()",
+		"return 1, \"ready\"",
+		"if _tryCompletion == 1 {\n\t\t\treturn _tryResult\n\t\t}",
+		// Nothing runs after the last statement of the method
+		"in := open(to)\n\tdefer in.Close()\n\treturn in.read()\n}",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+}
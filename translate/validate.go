@@ -0,0 +1,200 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+)
+
+// With `-strict-output`, the generated code is checked for nodes that the
+// printer can't print as valid Go before it is printed, such as identifiers
+// without a name, nodes that are missing a part that Go requires, and the
+// `Bad` nodes that are left in place of the code that couldn't be translated.
+// The printer prints these without complaining, as code that doesn't compile,
+// or that refers to something else entirely, so every one of them is reported
+// at the Java code that it was generated from, and the file isn't printed
+
+// The kind of diagnostic reported for the generated code that can't be printed
+// as valid Go
+const invalidOutputDiagnostic = "invalid-output"
+
+// validateOutput reports every node in the generated code that can't be
+// printed as valid Go, and returns the number of nodes that were reported
+func validateOutput(node ast.Node, diagnostics *Diagnostics) int {
+	problems := 0
+	ast.Walk(&outputValidator{diagnostics: diagnostics, count: &problems}, node)
+	return problems
+}
+
+// isVoidResult determines if the results of a function are the result that
+// the functions returning `void` have, which is a single result without a name
+// or a type, that the printer prints as nothing
+func isVoidResult(results *ast.FieldList) bool {
+	if results == nil || len(results.List) != 1 || len(results.List[0].Names) > 0 {
+		return false
+	}
+	void, ok := results.List[0].Type.(*ast.Ident)
+	return ok && void != nil && void.Name == ""
+}
+
+// An outputValidator walks the generated code, keeping the position of the
+// closest declaration or statement that has one, which the problems with the
+// nodes inside of it are reported at
+type outputValidator struct {
+	diagnostics *Diagnostics
+	pos         token.Pos
+	// The number of problems that have been reported, shared by the validators
+	// of all the nodes
+	count *int
+}
+
+func (ov *outputValidator) report(format string, args ...interface{}) {
+	*ov.count++
+	ov.diagnostics.ReportAt(ov.pos, invalidOutputDiagnostic, format, args...)
+}
+
+// Visit checks a single node, and returns the validator for its children
+func (ov *outputValidator) Visit(node ast.Node) ast.Visitor {
+	if node == nil {
+		return nil
+	}
+	// A nil pointer to a node can't be walked into
+	if value := reflect.ValueOf(node); value.Kind() == reflect.Pointer && value.IsNil() {
+		ov.report("the generated code is missing a node, where a `%T` was expected", node)
+		return nil
+	}
+
+	child := &outputValidator{diagnostics: ov.diagnostics, pos: ov.pos, count: ov.count}
+	if pos := ownPos(node); pos.IsValid() {
+		child.pos = pos
+	}
+
+	switch node := node.(type) {
+	case *ast.BadExpr, *ast.BadStmt, *ast.BadDecl:
+		child.report("the generated code has a `%T`, in place of the code that couldn't be translated", node)
+		return nil
+	case *ast.FuncType:
+		if isVoidResult(node.Results) {
+			if node.TypeParams != nil {
+				ast.Walk(child, node.TypeParams)
+			}
+			if node.Params != nil {
+				ast.Walk(child, node.Params)
+			}
+			return nil
+		}
+	case *ast.Ident:
+		if node.Name == "" {
+			child.report("the generated code has an identifier without a name, such as the type of a `void` value")
+		}
+		return nil
+	}
+
+	for _, part := range missingParts(node) {
+		child.report("the generated `%T` is missing its %s", node, part)
+	}
+	return child
+}
+
+// ownPos returns the position of a declaration or statement that was generated
+// from the Java code, or `token.NoPos` for the other nodes
+func ownPos(node ast.Node) token.Pos {
+	switch node := node.(type) {
+	case *ast.FuncDecl:
+		if node.Type != nil {
+			return node.Type.Func
+		}
+	case *ast.GenDecl:
+		return node.TokPos
+	case *ast.ReturnStmt:
+		return node.Return
+	case *ast.IfStmt:
+		return node.If
+	case *ast.ForStmt:
+		return node.For
+	case *ast.RangeStmt:
+		return node.For
+	case *ast.SwitchStmt:
+		return node.Switch
+	case *ast.TypeSwitchStmt:
+		return node.Switch
+	case *ast.BranchStmt:
+		return node.TokPos
+	case *ast.GoStmt:
+		return node.Go
+	case *ast.DeferStmt:
+		return node.Defer
+	}
+	return token.NoPos
+}
+
+// missingParts returns the parts of a node that Go requires, but that are
+// missing from it. A missing part that is a nil pointer is reported when it is
+// walked into instead
+func missingParts(node ast.Node) []string {
+	var missing []string
+	require := func(part string, present bool) {
+		if !present {
+			missing = append(missing, part)
+		}
+	}
+	requireAll := func(part string, exprs []ast.Expr) {
+		for _, expr := range exprs {
+			if expr == nil {
+				missing = append(missing, part)
+				return
+			}
+		}
+	}
+
+	switch node := node.(type) {
+	case *ast.Field:
+		require("type", node.Type != nil)
+	case *ast.SelectorExpr:
+		require("operand", node.X != nil)
+	case *ast.CallExpr:
+		require("function", node.Fun != nil)
+		requireAll("arguments", node.Args)
+	case *ast.BinaryExpr:
+		require("left operand", node.X != nil)
+		require("right operand", node.Y != nil)
+	case *ast.UnaryExpr:
+		require("operand", node.X != nil)
+	case *ast.StarExpr:
+		require("operand", node.X != nil)
+	case *ast.ParenExpr:
+		require("expression", node.X != nil)
+	case *ast.IndexExpr:
+		require("operand", node.X != nil)
+		require("index", node.Index != nil)
+	case *ast.KeyValueExpr:
+		require("key", node.Key != nil)
+		require("value", node.Value != nil)
+	case *ast.CompositeLit:
+		requireAll("elements", node.Elts)
+	case *ast.ExprStmt:
+		require("expression", node.X != nil)
+	case *ast.AssignStmt:
+		require("left side", len(node.Lhs) > 0)
+		require("right side", len(node.Rhs) > 0)
+		requireAll("left side", node.Lhs)
+		requireAll("right side", node.Rhs)
+	case *ast.ReturnStmt:
+		requireAll("results", node.Results)
+	case *ast.IfStmt:
+		require("condition", node.Cond != nil)
+	case *ast.BlockStmt:
+		for _, stmt := range node.List {
+			if stmt == nil {
+				missing = append(missing, "statements")
+				break
+			}
+		}
+	case *ast.ValueSpec:
+		require("names", len(node.Names) > 0)
+		requireAll("values", node.Values)
+	case *ast.TypeSpec:
+		require("type", node.Type != nil)
+	}
+	return missing
+}
@@ -0,0 +1,68 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestValidateOutput(t *testing.T) {
+	source := `
+package app.counters;
+public class Counter extends Base {
+    private int count;
+
+    public void increment() {
+        count++;
+    }
+
+    public int size() {
+        return super.size() + count;
+    }
+}
+`
+	helper := setupParseHelper(t, source)
+	helper.Ctx.sources = newSourceMap(token.NewFileSet(), "Counter.java", helper.File.Source)
+	file := ParseNode(helper.File.Ast, helper.File.Source, helper.Ctx).(*ast.File)
+
+	diagnostics := NewDiagnostics("Counter.java")
+	diagnostics.sources = helper.Ctx.sources
+	if problems := validateOutput(file, diagnostics); problems != 1 {
+		t.Fatalf("Expected only the call of the superclass to be reported, got %v", diagnostics.Items())
+	}
+
+	// The problem is reported at the statement that it is in
+	item := diagnostics.Items()[0]
+	if item.Kind != invalidOutputDiagnostic || item.Line != 11 || !strings.Contains(item.Message, "BadExpr") {
+		t.Errorf("Expected the untranslated call to be reported on line 11, got %v", item)
+	}
+}
+
+func TestValidateMissingParts(t *testing.T) {
+	function := &ast.FuncDecl{
+		Name: &ast.Ident{Name: "f"},
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{List: []*ast.Field{{Names: []*ast.Ident{{Name: "x"}}, Type: &ast.Ident{Name: ""}}}},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: &ast.Ident{Name: ""}}}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{
+			&ast.ExprStmt{X: &ast.BinaryExpr{X: &ast.Ident{Name: "x"}, Op: token.ADD}},
+			&ast.IfStmt{Cond: &ast.Ident{Name: "true"}},
+		}},
+	}
+
+	diagnostics := NewDiagnostics("Test.java")
+	if problems := validateOutput(function, diagnostics); problems != 3 {
+		t.Errorf("Expected the nameless type, the missing operand, and the missing body to be reported, got %v", diagnostics.Items())
+	}
+	for _, expected := range []string{"without a name", "missing its right operand", "*ast.BlockStmt"} {
+		found := false
+		for _, item := range diagnostics.Items() {
+			found = found || strings.Contains(item.Message, expected)
+		}
+		if !found {
+			t.Errorf("Expected a diagnostic containing %q, got %v", expected, diagnostics.Items())
+		}
+	}
+}
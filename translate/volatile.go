@@ -0,0 +1,191 @@
+package translate
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/NickyBoy89/java2go/codegen"
+	"github.com/NickyBoy89/java2go/symbol"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// The kind of diagnostic reported for the volatile fields that can't be read
+// and written atomically
+const volatileDiagnostic = "volatile"
+
+// A field that is declared `volatile` has the type from `sync/atomic` that
+// holds its value, such as `atomic.Int32`, so that it is read and written
+// with the same guarantees as Java gives it. Reading the field loads its
+// value, and assigning to it stores one:
+//
+//	running = false;   ->   ct.running.Store(false)
+//	if (running) {     ->   if ct.running.Load() {
+//	count++;           ->   ct.count.Add(1)
+//
+// The fields whose types don't have an atomic type, such as a `double` or a
+// `String`, are left as they are, and reported
+
+// volatileField returns the field that an expression refers to, if it is a
+// volatile field that is read and written atomically
+func volatileField(node *sitter.Node, ctx Ctx, source []byte) *symbol.Definition {
+	var field *symbol.Definition
+	switch node.Type() {
+	case "parenthesized_expression":
+		return volatileField(node.NamedChild(0), ctx, source)
+	case "field_access":
+		if node.ChildByFieldName("field").Type() == "this" {
+			return nil
+		}
+		field = fieldOfExpr(node.ChildByFieldName("object"), node.ChildByFieldName("field").Content(source), ctx, source)
+	case "identifier":
+		if ctx.currentClass == nil || !isVariableReference(node) || isDeclaredName(node) {
+			return nil
+		}
+		// A local variable shadows the field
+		name := node.Content(source)
+		if ctx.localScope != nil && ctx.localScope.FindVariable(name, node.StartByte()) != nil {
+			return nil
+		}
+		field = ctx.currentClass.FindFieldByName(name)
+	}
+	if field != nil && field.IsVolatile {
+		return field
+	}
+	return nil
+}
+
+// isDeclaredName determines if an identifier is the name that a declaration
+// gives to a variable, rather than a reference to one
+func isDeclaredName(node *sitter.Node) bool {
+	parent := node.Parent()
+	if parent == nil {
+		return false
+	}
+	if name := parent.ChildByFieldName("name"); name != nil && name.Equal(node) {
+		return true
+	}
+	switch parent.Type() {
+	case "inferred_parameters", "lambda_expression":
+		return true
+	}
+	return false
+}
+
+// volatileFieldExpr parses an expression that refers to a volatile field, where
+// the name of a field on its own refers to the field of the current object, or
+// to the package's variable if the field is static
+func volatileFieldExpr(node *sitter.Node, field *symbol.Definition, source []byte, ctx Ctx) ast.Expr {
+	if node.Type() == "identifier" {
+		if field.IsStatic {
+			return &ast.Ident{Name: field.Name}
+		}
+		if len(ctx.instances) == 0 {
+			return &ast.SelectorExpr{X: &ast.Ident{Name: ReceiverName(ctx)}, Sel: &ast.Ident{Name: field.Name}}
+		}
+	}
+	return parseExprNode(node, source, ctx)
+}
+
+// atomicCall calls one of the methods of an atomic value
+func atomicCall(field ast.Expr, method string, args ...ast.Expr) *ast.CallExpr {
+	return &ast.CallExpr{Fun: &ast.SelectorExpr{X: field, Sel: &ast.Ident{Name: method}}, Args: args}
+}
+
+// volatileLoad reads a volatile field, or returns nil if the expression isn't
+// one, or is assigned to
+func volatileLoad(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	if node.Type() == "parenthesized_expression" || isAssignedTo(node) {
+		return nil
+	}
+	field := volatileField(node, ctx, source)
+	if field == nil {
+		return nil
+	}
+	return atomicCall(volatileFieldExpr(node, field, source, ctx), "Load")
+}
+
+// volatileUpdate translates an increment or a decrement of a volatile field
+// into adding to it, which evaluates to the value after the update, or returns
+// nil if the updated expression isn't one
+//
+// Ex: `count++` turns into `ct.count.Add(1)`, and when the value from before
+// the update is used, into `ct.count.Add(1) - 1`
+func volatileUpdate(node *sitter.Node, valueUsed bool, source []byte, ctx Ctx) ast.Expr {
+	operand, operator, post := node.Child(1), node.Child(0), false
+	if node.Child(0).IsNamed() {
+		operand, operator, post = node.Child(0), node.Child(1), true
+	}
+	field := volatileField(operand, ctx, source)
+	if field == nil {
+		return nil
+	}
+
+	delta := &ast.BasicLit{Kind: token.INT, Value: "1"}
+	var added ast.Expr = atomicCall(volatileFieldExpr(operand, field, source, ctx), "Add", delta)
+	if operator.Type() == "--" {
+		added.(*ast.CallExpr).Args[0] = &ast.UnaryExpr{Op: token.SUB, X: delta}
+	}
+	if post && valueUsed {
+		undo := token.SUB
+		if operator.Type() == "--" {
+			undo = token.ADD
+		}
+		added = &ast.BinaryExpr{X: added, Op: undo, Y: delta}
+	}
+	return added
+}
+
+// volatileAssignment translates an assignment to a volatile field into storing
+// its value, or adding to it, or returns nil if the assigned expression isn't
+// one. The value that a compound assignment assigns is evaluated from the value
+// that is loaded, so only adding to the field is a single atomic operation, the
+// same as Java, where none of them are
+//
+// Ex: `running = false` turns into `ct.running.Store(false)`, and
+// `total -= n` turns into `ct.total.Add(-n)`
+func volatileAssignment(node *sitter.Node, source []byte, ctx Ctx) ast.Stmt {
+	left := node.ChildByFieldName("left")
+	definition := volatileField(left, ctx, source)
+	if definition == nil {
+		return nil
+	}
+	field := volatileFieldExpr(left, definition, source, ctx)
+	value := ParseExpr(node.ChildByFieldName("right"), source, ctx)
+	switch operator := node.ChildByFieldName("operator").Content(source); operator {
+	case "=":
+	case "+=":
+		return &ast.ExprStmt{X: atomicCall(field, "Add", value)}
+	case "-=":
+		if _, binary := value.(*ast.BinaryExpr); binary {
+			value = &ast.ParenExpr{X: value}
+		}
+		return &ast.ExprStmt{X: atomicCall(field, "Add", &ast.UnaryExpr{Op: token.SUB, X: value})}
+	case ">>>=":
+		value = runtimeCall("UnsignedRightShift", atomicCall(field, "Load"), value)
+	default:
+		value = &ast.BinaryExpr{X: atomicCall(field, "Load"), Op: codegen.StrToToken(operator[:len(operator)-1]), Y: value}
+	}
+	return &ast.ExprStmt{X: atomicCall(field, "Store", value)}
+}
+
+// volatileAssignmentValue translates an assignment to a volatile field that is
+// used as a value, or returns nil if the assigned expression isn't one. Adding
+// to the field evaluates to its new value, and any other assignment is stored
+// before the statement, if nothing in the statement is evaluated before it
+func volatileAssignmentValue(node *sitter.Node, source []byte, ctx Ctx) ast.Expr {
+	left := node.ChildByFieldName("left")
+	if volatileField(left, ctx, source) == nil {
+		return nil
+	}
+	update := volatileAssignment(node, source, ctx).(*ast.ExprStmt).X.(*ast.CallExpr)
+	field := update.Fun.(*ast.SelectorExpr)
+	if field.Sel.Name == "Add" {
+		return update
+	}
+	if ctx.hoisted != nil && isReevaluable(left, source) && isEvaluatedFirst(node, assignedName(left, source), source) {
+		ctx.hoisted.hoist(&ast.ExprStmt{X: update})
+		return atomicCall(field.X, "Load")
+	}
+	ctx.diagnostics.Report(node, volatileDiagnostic, "`%s` can't be used as a value, since the volatile field is stored atomically", node.Content(source))
+	return &ast.BadExpr{}
+}
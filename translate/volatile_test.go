@@ -0,0 +1,61 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVolatileFields(t *testing.T) {
+	helper := setupParseHelper(t, `
+package concurrency.flags;
+public class Worker {
+    private volatile boolean running;
+    private volatile int count;
+    private volatile Worker next;
+    private volatile double ratio;
+    private static volatile long started;
+    public void stop() {
+        running = false;
+    }
+    public int run(Worker other) {
+        started++;
+        while (this.running) {
+            count++;
+            count -= 2;
+            count *= other.count;
+        }
+        this.next = other;
+        int before = count--;
+        return before + next.count;
+    }
+}
+`)
+	helper.Ctx.diagnostics = NewDiagnostics("Worker.java")
+
+	out := renderGoFileFromJavaCtx(t, helper)
+	for _, expected := range []string{
+		"var started atomic.Int64",
+		"running\tatomic.Bool",
+		"count\tatomic.Int32",
+		"next\tatomic.Pointer[Worker]",
+		"ratio\tfloat64",
+		"wr.running.Store(false)",
+		"started.Add(1)",
+		"for wr.running.Load() {",
+		"wr.count.Add(1)",
+		"wr.count.Add(-2)",
+		"wr.count.Store(wr.count.Load() * other.count.Load())",
+		"wr.next.Store(other)",
+		"before := wr.count.Add(-1) + 1",
+		"return before + wr.next.Load().count.Load()",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got:\n%s", expected, out)
+		}
+	}
+
+	items := helper.Ctx.diagnostics.Items()
+	if len(items) != 1 || items[0].Kind != volatileDiagnostic || items[0].Line != 7 {
+		t.Errorf("Expected the field without an atomic type on line 7 to be reported, got %v", items)
+	}
+}